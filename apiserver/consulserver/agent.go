@@ -0,0 +1,258 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package consulserver
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang/protobuf/ptypes/wrappers"
+
+	api "github.com/polarismesh/polaris/common/api/v1"
+	"github.com/polarismesh/polaris/common/model"
+)
+
+const (
+	probeProtocolTCP  = "tcp"
+	probeProtocolHTTP = "http"
+	probeProtocolGRPC = "grpc"
+)
+
+// parseCheckDuration 解析 consul 检查的时长，格式错误或者没有填写时使用默认值
+func parseCheckDuration(value string, defaultValue time.Duration) time.Duration {
+	if len(value) == 0 {
+		return defaultValue
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil || duration <= 0 {
+		return defaultValue
+	}
+	return duration
+}
+
+func durationSeconds(duration time.Duration) uint32 {
+	return uint32(math.Ceil(duration.Seconds()))
+}
+
+// buildHealthCheck consul 的 TTL 检查映射为北极星的心跳检查，http/tcp/grpc 检查映射为北极星的主动探测，
+// 探测的目标地址总是实例地址，只使用检查中的端口、路径等信息
+func buildHealthCheck(check *AgentServiceCheck, metadata map[string]string) (*api.HealthCheck, error) {
+	if len(check.TTL) > 0 {
+		ttl := parseCheckDuration(check.TTL, 0)
+		if ttl == 0 {
+			return nil, errors.New("invalid check ttl " + check.TTL)
+		}
+		return &api.HealthCheck{
+			Type:      api.HealthCheck_HEARTBEAT,
+			Heartbeat: &api.HeartbeatHealthCheck{Ttl: &wrappers.UInt32Value{Value: durationSeconds(ttl)}},
+		}, nil
+	}
+
+	var port string
+	switch {
+	case len(check.HTTP) > 0:
+		checkURL, err := url.Parse(check.HTTP)
+		if err != nil {
+			return nil, err
+		}
+		port = checkURL.Port()
+		if checkURL.Scheme == "https" {
+			// 主动探测不支持 https，退化为探测端口是否可以连接
+			metadata[model.MetaKeyProbeProtocol] = probeProtocolTCP
+			if len(port) == 0 {
+				port = "443"
+			}
+			break
+		}
+		metadata[model.MetaKeyProbeProtocol] = probeProtocolHTTP
+		metadata[model.MetaKeyProbeHTTPPath] = checkURL.RequestURI()
+	case len(check.TCP) > 0:
+		_, tcpPort, err := net.SplitHostPort(check.TCP)
+		if err != nil {
+			return nil, err
+		}
+		port = tcpPort
+		metadata[model.MetaKeyProbeProtocol] = probeProtocolTCP
+	case len(check.GRPC) > 0:
+		address := check.GRPC
+		if idx := strings.Index(address, "/"); idx >= 0 {
+			metadata[model.MetaKeyProbeGRPCService] = address[idx+1:]
+			address = address[:idx]
+		}
+		_, grpcPort, err := net.SplitHostPort(address)
+		if err != nil {
+			return nil, err
+		}
+		port = grpcPort
+		metadata[model.MetaKeyProbeProtocol] = probeProtocolGRPC
+	default:
+		return nil, nil
+	}
+	if len(port) > 0 {
+		metadata[model.MetaKeyProbePort] = port
+	}
+	if len(check.Timeout) > 0 {
+		metadata[model.MetaKeyProbeTimeout] = check.Timeout
+	}
+	interval := parseCheckDuration(check.Interval, DefaultCheckInterval)
+	return &api.HealthCheck{
+		Type:      model.HealthCheckProbe,
+		Heartbeat: &api.HeartbeatHealthCheck{Ttl: &wrappers.UInt32Value{Value: durationSeconds(interval)}},
+	}, nil
+}
+
+// buildPolarisInstance consul 的服务注册请求转换为北极星实例，没有指定地址时使用客户端的地址
+func buildPolarisInstance(namespace string, clientIP string,
+	registration *AgentServiceRegistration) (*api.Instance, []string, error) {
+	if len(registration.Name) == 0 {
+		return nil, nil, errors.New("missing service name")
+	}
+	if registration.Port <= 0 || registration.Port > math.MaxUint16 {
+		return nil, nil, errors.New("invalid service port " + strconv.Itoa(registration.Port))
+	}
+	id := registration.ID
+	if len(id) == 0 {
+		id = registration.Name
+	}
+	address := registration.Address
+	if len(address) == 0 {
+		address = clientIP
+	}
+
+	metadata := make(map[string]string, len(registration.Meta)+2)
+	for k, v := range registration.Meta {
+		metadata[k] = v
+	}
+	metadata[MetadataRegisterFrom] = ServerConsul
+	if len(registration.Tags) > 0 {
+		tags, _ := json.Marshal(registration.Tags)
+		metadata[MetadataConsulTags] = string(tags)
+	}
+	weight := uint32(100)
+	if registration.Weights != nil && registration.Weights.Passing > 0 {
+		weight = uint32(math.Min(float64(registration.Weights.Passing)*100, 10000))
+	}
+
+	targetInstance := &api.Instance{
+		Id:        &wrappers.StringValue{Value: id},
+		Namespace: &wrappers.StringValue{Value: namespace},
+		Service:   &wrappers.StringValue{Value: registration.Name},
+		Host:      &wrappers.StringValue{Value: address},
+		Port:      &wrappers.UInt32Value{Value: uint32(registration.Port)},
+		Weight:    &wrappers.UInt32Value{Value: weight},
+		Healthy:   &wrappers.BoolValue{Value: true},
+		Metadata:  metadata,
+	}
+
+	// 北极星的实例只支持一种健康检查，使用第一个可以转换的检查
+	checks := make([]*AgentServiceCheck, 0, len(registration.Checks)+1)
+	if registration.Check != nil {
+		checks = append(checks, registration.Check)
+	}
+	checks = append(checks, registration.Checks...)
+	var checkIDs []string
+	for _, check := range checks {
+		if len(check.CheckID) > 0 {
+			checkIDs = append(checkIDs, check.CheckID)
+		}
+		if targetInstance.HealthCheck != nil {
+			continue
+		}
+		healthCheck, err := buildHealthCheck(check, metadata)
+		if err != nil {
+			return nil, nil, err
+		}
+		if healthCheck == nil {
+			continue
+		}
+		targetInstance.EnableHealthCheck = &wrappers.BoolValue{Value: true}
+		targetInstance.HealthCheck = healthCheck
+		if check.Status == CheckStatusCritical {
+			targetInstance.Healthy = &wrappers.BoolValue{Value: false}
+		}
+	}
+	return targetInstance, checkIDs, nil
+}
+
+// registerInstance 注册实例，服务不存在时先创建服务，重复注册时北极星会替换实例的属性
+func (c *ConsulServer) registerInstance(ctx context.Context, instance *api.Instance) *api.Response {
+	resp := c.namingServer.RegisterInstance(ctx, instance)
+	switch resp.GetCode().GetValue() {
+	case api.ExistedResource:
+		return api.NewResponse(api.ExecuteSuccess)
+	case api.NotFoundResource:
+		svcResp := c.namingServer.CreateServices(ctx, []*api.Service{{
+			Namespace: instance.GetNamespace(),
+			Name:      instance.GetService(),
+		}})
+		svcCreateCode := svcResp.GetCode().GetValue()
+		if svcCreateCode != api.ExecuteSuccess && svcCreateCode != api.ExistedResource {
+			return api.NewResponse(svcCreateCode)
+		}
+		return c.namingServer.RegisterInstance(ctx, instance)
+	default:
+		return resp
+	}
+}
+
+// deregisterInstance 反注册实例，实例不存在也认为成功
+func (c *ConsulServer) deregisterInstance(ctx context.Context, id string) *api.Response {
+	resp := c.namingServer.DeregisterInstance(ctx, &api.Instance{Id: &wrappers.StringValue{Value: id}})
+	if resp.GetCode().GetValue() == api.NotFoundResource || resp.GetCode().GetValue() == api.NotFoundInstance {
+		return api.NewResponse(api.ExecuteSuccess)
+	}
+	return resp
+}
+
+// checkInstanceID 根据检查 id 找到对应的实例，默认的检查 id 为 service:{实例id}，
+// 自定义的检查 id 在本节点注册时记录
+func (c *ConsulServer) checkInstanceID(checkID string) string {
+	if id, ok := c.checkIDs.Load(checkID); ok {
+		return id.(string)
+	}
+	id := strings.TrimPrefix(checkID, ServiceCheckIDPrefix)
+	if id == checkID || c.namingServer.Cache().Instance().GetInstance(id) != nil {
+		return id
+	}
+	// 一个服务有多个检查时，consul 生成的检查 id 为 service:{服务id}:{序号}
+	if idx := strings.LastIndex(id, ":"); idx >= 0 {
+		if _, err := strconv.Atoi(id[idx+1:]); err == nil {
+			id = id[:idx]
+		}
+	}
+	return id
+}
+
+// passCheck consul 的 TTL 检查上报通过，对应北极星的心跳
+func (c *ConsulServer) passCheck(ctx context.Context, checkID string) uint32 {
+	resp := c.healthCheckServer.Report(ctx, &api.Instance{
+		Id: &wrappers.StringValue{Value: c.checkInstanceID(checkID)},
+	})
+	code := resp.GetCode().GetValue()
+	if code == api.HeartbeatOnDisabledIns {
+		return api.ExecuteSuccess
+	}
+	return code
+}
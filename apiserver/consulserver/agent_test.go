@@ -0,0 +1,91 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package consulserver
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	api "github.com/polarismesh/polaris/common/api/v1"
+	"github.com/polarismesh/polaris/common/model"
+)
+
+func TestBuildPolarisInstance(t *testing.T) {
+	registration := &AgentServiceRegistration{
+		Name:    "web",
+		Tags:    []string{"v1", "primary"},
+		Port:    8080,
+		Meta:    map[string]string{"env": "prod"},
+		Weights: &AgentWeights{Passing: 3, Warning: 1},
+		Check:   &AgentServiceCheck{CheckID: "web-ttl", TTL: "15s"},
+	}
+	instance, checkIDs, err := buildPolarisInstance("default", "10.0.0.1", registration)
+	assert.NoError(t, err)
+	assert.Equal(t, "web", instance.GetId().GetValue())
+	assert.Equal(t, "10.0.0.1", instance.GetHost().GetValue())
+	assert.Equal(t, uint32(300), instance.GetWeight().GetValue())
+	assert.Equal(t, []string{"web-ttl"}, checkIDs)
+	assert.Equal(t, api.HealthCheck_HEARTBEAT, instance.GetHealthCheck().GetType())
+	assert.Equal(t, uint32(15), instance.GetHealthCheck().GetHeartbeat().GetTtl().GetValue())
+	assert.Equal(t, []string{"v1", "primary"}, getTags(instance.GetMetadata()))
+	assert.Equal(t, map[string]string{"env": "prod"}, getServiceMeta(instance.GetMetadata()))
+
+	entry := (&ConsulServer{datacenter: DefaultDatacenter}).toServiceEntry("web", instance)
+	assert.Equal(t, 3, entry.Service.Weights.Passing)
+	assert.Equal(t, CheckStatusPassing, entry.Checks[1].Status)
+	data, err := json.Marshal(entry)
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), `"CheckID":"service:web"`)
+
+	_, _, err = buildPolarisInstance("default", "10.0.0.1", &AgentServiceRegistration{Name: "web"})
+	assert.Error(t, err)
+}
+
+func TestBuildHealthCheck(t *testing.T) {
+	metadata := map[string]string{}
+	check, err := buildHealthCheck(&AgentServiceCheck{HTTP: "http://127.0.0.1:8081/health?full=1",
+		Interval: "5s", Timeout: "1s"}, metadata)
+	assert.NoError(t, err)
+	assert.Equal(t, model.HealthCheckProbe, check.GetType())
+	assert.Equal(t, uint32(5), check.GetHeartbeat().GetTtl().GetValue())
+	assert.Equal(t, "http", metadata[model.MetaKeyProbeProtocol])
+	assert.Equal(t, "8081", metadata[model.MetaKeyProbePort])
+	assert.Equal(t, "/health?full=1", metadata[model.MetaKeyProbeHTTPPath])
+	assert.Equal(t, "1s", metadata[model.MetaKeyProbeTimeout])
+
+	metadata = map[string]string{}
+	check, err = buildHealthCheck(&AgentServiceCheck{GRPC: "127.0.0.1:9090/echo.Echo"}, metadata)
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(10), check.GetHeartbeat().GetTtl().GetValue())
+	assert.Equal(t, "grpc", metadata[model.MetaKeyProbeProtocol])
+	assert.Equal(t, "9090", metadata[model.MetaKeyProbePort])
+	assert.Equal(t, "echo.Echo", metadata[model.MetaKeyProbeGRPCService])
+
+	metadata = map[string]string{}
+	_, err = buildHealthCheck(&AgentServiceCheck{TCP: "127.0.0.1:3306"}, metadata)
+	assert.NoError(t, err)
+	assert.Equal(t, "tcp", metadata[model.MetaKeyProbeProtocol])
+
+	_, err = buildHealthCheck(&AgentServiceCheck{TCP: "3306"}, map[string]string{})
+	assert.Error(t, err)
+	check, err = buildHealthCheck(&AgentServiceCheck{Name: "script"}, map[string]string{})
+	assert.NoError(t, err)
+	assert.Nil(t, check)
+}
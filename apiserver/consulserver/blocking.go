@@ -0,0 +1,181 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package consulserver
+
+import (
+	"context"
+	"encoding/json"
+	"hash/fnv"
+	"strconv"
+	"sync"
+	"time"
+)
+
+type indexEntry struct {
+	hash  uint64
+	index uint64
+}
+
+// Indexer 为 consul 的阻塞查询生成索引。北极星没有全局的修改索引，这里记录每个查询上一次结果的摘要，
+// 结果发生变化时分配一个新的索引，删除等操作同样会让索引递增
+type Indexer struct {
+	lock    sync.Mutex
+	index   uint64
+	entries map[string]*indexEntry
+	changed chan struct{}
+}
+
+// NewIndexer 构造函数，索引以当前时间的秒数为起点，避免服务端重启后索引回退导致客户端一直阻塞
+func NewIndexer() *Indexer {
+	return &Indexer{
+		index:   uint64(time.Now().Unix()),
+		entries: make(map[string]*indexEntry),
+		changed: make(chan struct{}),
+	}
+}
+
+// Update 根据查询结果返回查询的索引，结果与上一次不同时分配新的索引
+func (i *Indexer) Update(key string, value interface{}) uint64 {
+	data, err := json.Marshal(value)
+	if err != nil {
+		log.Errorf("[CONSUL-SERVER]fail to marshal query result of %s, err: %v", key, err)
+	}
+	h := fnv.New64a()
+	_, _ = h.Write(data)
+	hash := h.Sum64()
+
+	i.lock.Lock()
+	defer i.lock.Unlock()
+	entry, ok := i.entries[key]
+	if !ok || entry.hash != hash {
+		i.index++
+		entry = &indexEntry{hash: hash, index: i.index}
+		i.entries[key] = entry
+	}
+	return entry.index
+}
+
+// Notify 数据发生变化，唤醒所有等待中的阻塞查询
+func (i *Indexer) Notify() {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+	close(i.changed)
+	i.changed = make(chan struct{})
+}
+
+func (i *Indexer) watch() <-chan struct{} {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+	return i.changed
+}
+
+// OnCreated callback when cache value created
+func (i *Indexer) OnCreated(value interface{}) {
+	i.Notify()
+}
+
+// OnUpdated callback when cache value updated
+func (i *Indexer) OnUpdated(value interface{}) {
+	i.Notify()
+}
+
+// OnDeleted callback when cache value deleted
+func (i *Indexer) OnDeleted(value interface{}) {
+	i.Notify()
+}
+
+// OnBatchCreated callback when cache value created
+func (i *Indexer) OnBatchCreated(value interface{}) {
+}
+
+// OnBatchUpdated 实例缓存每一轮更新结束后通知一次
+func (i *Indexer) OnBatchUpdated(value interface{}) {
+	i.Notify()
+}
+
+// OnBatchDeleted callback when cache value deleted
+func (i *Indexer) OnBatchDeleted(value interface{}) {
+}
+
+// QueryOptions 阻塞查询的参数
+type QueryOptions struct {
+	MinIndex uint64
+	Wait     time.Duration
+}
+
+// parseQueryOptions 解析 index 和 wait 参数，wait 的格式为 10s、5m 等，没有单位时按秒处理
+func parseQueryOptions(index string, wait string) (*QueryOptions, error) {
+	opts := &QueryOptions{Wait: DefaultBlockingWait}
+	if len(index) > 0 {
+		minIndex, err := strconv.ParseUint(index, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		opts.MinIndex = minIndex
+	}
+	if len(wait) > 0 {
+		duration, err := time.ParseDuration(wait)
+		if err != nil {
+			seconds, secErr := strconv.ParseUint(wait, 10, 64)
+			if secErr != nil {
+				return nil, err
+			}
+			duration = time.Duration(seconds) * time.Second
+		}
+		if duration > 0 {
+			opts.Wait = duration
+		}
+	}
+	if opts.Wait > MaxBlockingWait {
+		opts.Wait = MaxBlockingWait
+	}
+	return opts, nil
+}
+
+// queryFunc 执行一次查询，返回的结果用于计算索引
+type queryFunc func() (interface{}, error)
+
+// blockingQuery 执行 consul 的阻塞查询：没有指定 index，或者结果的索引与 index 不同时立即返回，
+// 否则等待数据变化或者超时，超时后返回当前的结果
+func (i *Indexer) blockingQuery(ctx context.Context, key string, opts *QueryOptions,
+	query queryFunc) (interface{}, uint64, error) {
+	timer := time.NewTimer(opts.Wait)
+	defer timer.Stop()
+	ticker := time.NewTicker(DefaultRecheckInterval)
+	defer ticker.Stop()
+	for {
+		// 先获取通知通道，避免查询期间发生的变化被遗漏
+		changed := i.watch()
+		result, err := query()
+		if err != nil {
+			return nil, 0, err
+		}
+		index := i.Update(key, result)
+		if opts.MinIndex == 0 || index != opts.MinIndex {
+			return result, index, nil
+		}
+		select {
+		case <-changed:
+		case <-ticker.C:
+		case <-timer.C:
+			return result, index, nil
+		case <-ctx.Done():
+			return result, index, nil
+		}
+	}
+}
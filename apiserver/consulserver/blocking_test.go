@@ -0,0 +1,100 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package consulserver
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseQueryOptions(t *testing.T) {
+	opts, err := parseQueryOptions("", "")
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(0), opts.MinIndex)
+	assert.Equal(t, DefaultBlockingWait, opts.Wait)
+
+	opts, err = parseQueryOptions("12", "30s")
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(12), opts.MinIndex)
+	assert.Equal(t, 30*time.Second, opts.Wait)
+
+	opts, err = parseQueryOptions("12", "20")
+	assert.NoError(t, err)
+	assert.Equal(t, 20*time.Second, opts.Wait)
+
+	opts, err = parseQueryOptions("12", "1h")
+	assert.NoError(t, err)
+	assert.Equal(t, MaxBlockingWait, opts.Wait)
+
+	_, err = parseQueryOptions("abc", "")
+	assert.Error(t, err)
+	_, err = parseQueryOptions("", "abc")
+	assert.Error(t, err)
+}
+
+func TestIndexer_Update(t *testing.T) {
+	indexer := NewIndexer()
+	first := indexer.Update("key", []string{"a"})
+	assert.Equal(t, first, indexer.Update("key", []string{"a"}))
+	// 结果减少时索引同样递增
+	second := indexer.Update("key", []string{})
+	assert.Greater(t, second, first)
+	assert.Greater(t, indexer.Update("other", []string{"a"}), second)
+}
+
+func TestIndexer_BlockingQuery(t *testing.T) {
+	indexer := NewIndexer()
+	query := func() (interface{}, error) {
+		return []string{"a"}, nil
+	}
+	ctx := context.Background()
+	// 没有指定 index 时立即返回
+	_, index, err := indexer.blockingQuery(ctx, "key", &QueryOptions{Wait: time.Minute}, query)
+	assert.NoError(t, err)
+
+	// 数据没有变化时一直等待到超时
+	start := time.Now()
+	_, timeoutIndex, err := indexer.blockingQuery(ctx, "key",
+		&QueryOptions{MinIndex: index, Wait: 100 * time.Millisecond}, query)
+	assert.NoError(t, err)
+	assert.Equal(t, index, timeoutIndex)
+	assert.GreaterOrEqual(t, time.Since(start), 100*time.Millisecond)
+
+	// 数据变化后被唤醒
+	changed := make(chan struct{})
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		close(changed)
+		indexer.Notify()
+	}()
+	result, newIndex, err := indexer.blockingQuery(ctx, "key", &QueryOptions{MinIndex: index, Wait: time.Minute},
+		func() (interface{}, error) {
+			select {
+			case <-changed:
+				return []string{"a", "b"}, nil
+			default:
+				return []string{"a"}, nil
+			}
+		})
+	assert.NoError(t, err)
+	assert.Greater(t, newIndex, index)
+	assert.Equal(t, []string{"a", "b"}, result)
+}
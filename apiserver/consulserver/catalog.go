@@ -0,0 +1,238 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package consulserver
+
+import (
+	"encoding/json"
+	"sort"
+
+	api "github.com/polarismesh/polaris/common/api/v1"
+	"github.com/polarismesh/polaris/common/model"
+)
+
+// getTags 从实例元数据中解析 consul 的 tags
+func getTags(metadata map[string]string) []string {
+	tags := make([]string, 0, 2)
+	if value, ok := metadata[MetadataConsulTags]; ok {
+		if err := json.Unmarshal([]byte(value), &tags); err != nil {
+			log.Warnf("[CONSUL-SERVER]invalid consul tags %s, err: %v", value, err)
+		}
+	}
+	return tags
+}
+
+// getServiceMeta 去掉北极星内部使用的元数据
+func getServiceMeta(metadata map[string]string) map[string]string {
+	meta := make(map[string]string, len(metadata))
+	for k, v := range metadata {
+		switch k {
+		case MetadataRegisterFrom, MetadataConsulTags, MetadataConsulNode:
+		default:
+			meta[k] = v
+		}
+	}
+	return meta
+}
+
+// toConsulWeights 北极星的权重默认为 100，consul 的权重默认为 1
+func toConsulWeights(weight uint32) AgentWeights {
+	passing := int(weight / 100)
+	if passing < 1 {
+		passing = 1
+	}
+	return AgentWeights{Passing: passing, Warning: 1}
+}
+
+// nodeName 北极星没有节点的概念，优先使用注册时指定的节点名，否则以实例的 IP 作为节点名
+func nodeName(instance *api.Instance) string {
+	if name, ok := instance.GetMetadata()[MetadataConsulNode]; ok && len(name) > 0 {
+		return name
+	}
+	return instance.GetHost().GetValue()
+}
+
+// checkStatus 隔离的实例对应 consul 的维护状态，与不健康的实例一样视为 critical
+func checkStatus(instance *api.Instance) string {
+	if instance.GetIsolate().GetValue() || !instance.GetHealthy().GetValue() {
+		return CheckStatusCritical
+	}
+	return CheckStatusPassing
+}
+
+func hasTags(tags []string, expects []string) bool {
+	for _, expect := range expects {
+		found := false
+		for _, tag := range tags {
+			if tag == expect {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// resolveService 查询缓存中的服务，别名服务返回其指向的服务
+func (c *ConsulServer) resolveService(namespace string, name string) *model.Service {
+	caches := c.namingServer.Cache()
+	svc := caches.Service().GetServiceByName(name, namespace)
+	if svc != nil && svc.IsAlias() {
+		svc = caches.Service().GetServiceByID(svc.Reference)
+	}
+	return svc
+}
+
+// listServices 返回命名空间下有实例的服务及其 tags
+func (c *ConsulServer) listServices(namespace string) (map[string][]string, error) {
+	caches := c.namingServer.Cache()
+	services := make(map[string][]string)
+	err := caches.Service().IteratorServices(func(_ string, svc *model.Service) (bool, error) {
+		if svc.Namespace != namespace || svc.IsAlias() {
+			return true, nil
+		}
+		instances := caches.Instance().GetInstancesByServiceID(svc.ID)
+		if len(instances) == 0 {
+			return true, nil
+		}
+		tagSet := make(map[string]struct{})
+		for _, instance := range instances {
+			for _, tag := range getTags(instance.Metadata()) {
+				tagSet[tag] = struct{}{}
+			}
+		}
+		tags := make([]string, 0, len(tagSet))
+		for tag := range tagSet {
+			tags = append(tags, tag)
+		}
+		sort.Strings(tags)
+		services[svc.Name] = tags
+		return true, nil
+	})
+	return services, err
+}
+
+// listInstances 按照 tag 过滤服务的实例，按照实例 id 排序保证结果稳定
+func (c *ConsulServer) listInstances(namespace string, name string, tags []string) []*api.Instance {
+	svc := c.resolveService(namespace, name)
+	if svc == nil {
+		return nil
+	}
+	instances := make([]*api.Instance, 0, 8)
+	for _, instance := range c.namingServer.Cache().Instance().GetInstancesByServiceID(svc.ID) {
+		if !hasTags(getTags(instance.Metadata()), tags) {
+			continue
+		}
+		instances = append(instances, instance.Proto)
+	}
+	sort.Slice(instances, func(i, j int) bool {
+		return instances[i].GetId().GetValue() < instances[j].GetId().GetValue()
+	})
+	return instances
+}
+
+// toCatalogService 北极星实例转换为 consul catalog 中的服务实例
+func (c *ConsulServer) toCatalogService(name string, instance *api.Instance) *CatalogService {
+	return &CatalogService{
+		ID:              instance.GetId().GetValue(),
+		Node:            nodeName(instance),
+		Address:         instance.GetHost().GetValue(),
+		Datacenter:      c.datacenter,
+		TaggedAddresses: map[string]string{},
+		NodeMeta:        map[string]string{},
+		ServiceID:       instance.GetId().GetValue(),
+		ServiceName:     name,
+		ServiceAddress:  instance.GetHost().GetValue(),
+		ServiceTags:     getTags(instance.GetMetadata()),
+		ServiceMeta:     getServiceMeta(instance.GetMetadata()),
+		ServicePort:     int(instance.GetPort().GetValue()),
+		ServiceWeights:  toConsulWeights(instance.GetWeight().GetValue()),
+	}
+}
+
+// toServiceEntry 北极星实例转换为 consul 健康查询的结果，节点检查总是 passing，服务检查对应实例的健康状态
+func (c *ConsulServer) toServiceEntry(name string, instance *api.Instance) *ServiceEntry {
+	id := instance.GetId().GetValue()
+	node := nodeName(instance)
+	tags := getTags(instance.GetMetadata())
+	serviceCheck := &HealthCheck{
+		Node:        node,
+		CheckID:     ServiceCheckIDPrefix + id,
+		Name:        "Service '" + name + "' check",
+		Status:      checkStatus(instance),
+		ServiceID:   id,
+		ServiceName: name,
+		ServiceTags: tags,
+	}
+	if instance.GetIsolate().GetValue() {
+		serviceCheck.CheckID = MaintenanceCheckIDPrefix + id
+		serviceCheck.Name = "Service Maintenance Mode"
+	}
+	return &ServiceEntry{
+		Node: &Node{
+			Node:            node,
+			Address:         instance.GetHost().GetValue(),
+			Datacenter:      c.datacenter,
+			TaggedAddresses: map[string]string{},
+			Meta:            map[string]string{},
+		},
+		Service: &AgentService{
+			ID:      id,
+			Service: name,
+			Tags:    tags,
+			Meta:    getServiceMeta(instance.GetMetadata()),
+			Port:    int(instance.GetPort().GetValue()),
+			Address: instance.GetHost().GetValue(),
+			Weights: toConsulWeights(instance.GetWeight().GetValue()),
+		},
+		Checks: []*HealthCheck{
+			{
+				Node:        node,
+				CheckID:     SerfCheckID,
+				Name:        "Serf Health Status",
+				Status:      CheckStatusPassing,
+				ServiceTags: []string{},
+			},
+			serviceCheck,
+		},
+	}
+}
+
+// catalogService 查询服务在 catalog 中的所有实例
+func (c *ConsulServer) catalogService(namespace string, name string, tags []string) []*CatalogService {
+	services := make([]*CatalogService, 0, 8)
+	for _, instance := range c.listInstances(namespace, name, tags) {
+		services = append(services, c.toCatalogService(name, instance))
+	}
+	return services
+}
+
+// healthService 查询服务实例及其健康状态，passingOnly 时只返回健康且没有隔离的实例
+func (c *ConsulServer) healthService(namespace string, name string, tags []string,
+	passingOnly bool) []*ServiceEntry {
+	entries := make([]*ServiceEntry, 0, 8)
+	for _, instance := range c.listInstances(namespace, name, tags) {
+		if passingOnly && checkStatus(instance) != CheckStatusPassing {
+			continue
+		}
+		entries = append(entries, c.toServiceEntry(name, instance))
+	}
+	return entries
+}
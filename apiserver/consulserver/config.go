@@ -0,0 +1,42 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package consulserver
+
+import "time"
+
+const (
+	optionListenIP         = "listenIP"
+	optionListenPort       = "listenPort"
+	optionDatacenter       = "datacenter"
+	optionDefaultNamespace = "defaultNamespace"
+)
+
+const (
+	// DefaultDatacenter consul 客户端看到的数据中心名称
+	DefaultDatacenter = "dc1"
+	// DefaultNamespace 没有通过 ns 参数指定命名空间时使用的北极星命名空间
+	DefaultNamespace = "default"
+	// DefaultBlockingWait 阻塞查询没有指定 wait 时的等待时长，与 consul 保持一致
+	DefaultBlockingWait = 5 * time.Minute
+	// MaxBlockingWait 阻塞查询最长的等待时长
+	MaxBlockingWait = 10 * time.Minute
+	// DefaultRecheckInterval 阻塞查询期间重新检查数据的间隔，配置中心的发布扫描间隔同样为 1s
+	DefaultRecheckInterval = time.Second
+	// DefaultCheckInterval consul 的 http/tcp/grpc 检查没有指定 interval 时的探测间隔
+	DefaultCheckInterval = 10 * time.Second
+)
@@ -0,0 +1,431 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package consulserver
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+
+	restful "github.com/emicklei/go-restful/v3"
+
+	api "github.com/polarismesh/polaris/common/api/v1"
+	"github.com/polarismesh/polaris/common/utils"
+)
+
+// MaxKVValueSize consul 单个 value 的大小限制
+const MaxKVValueSize = 512 * 1024
+
+// PolarisError 北极星的错误码及其信息
+type PolarisError struct {
+	Code    uint32
+	Message string
+}
+
+// Error 实现 error 接口
+func (e *PolarisError) Error() string {
+	return fmt.Sprintf("code: %d, message: %s", e.Code, e.Message)
+}
+
+// polarisError 北极星的应答转换为 error
+func polarisError(resp api.ResponseMessage) error {
+	message := resp.GetInfo().GetValue()
+	if len(message) == 0 {
+		message = api.Code2Info(resp.GetCode().GetValue())
+	}
+	return &PolarisError{Code: resp.GetCode().GetValue(), Message: message}
+}
+
+// GetConsulServer consul 注册发现以及 KV 接口
+func (c *ConsulServer) GetConsulServer() *restful.WebService {
+	ws := new(restful.WebService)
+	ws.Path("/v1").Consumes(restful.MIME_JSON, restful.MIME_OCTET, "text/plain").Produces(restful.MIME_JSON)
+
+	ws.Route(ws.GET("/catalog/services").To(c.CatalogServices))
+	ws.Route(ws.GET("/catalog/service/{service}").To(c.CatalogService))
+	ws.Route(ws.GET("/catalog/datacenters").To(c.CatalogDatacenters))
+	ws.Route(ws.GET("/health/service/{service}").To(c.HealthService))
+
+	ws.Route(ws.PUT("/agent/service/register").To(c.RegisterService))
+	ws.Route(ws.PUT("/agent/service/deregister/{service_id}").To(c.DeregisterService))
+	ws.Route(ws.PUT("/agent/check/pass/{check_id}").To(c.PassCheck))
+	ws.Route(ws.PUT("/agent/check/warn/{check_id}").To(c.IgnoreCheck))
+	ws.Route(ws.PUT("/agent/check/fail/{check_id}").To(c.IgnoreCheck))
+	ws.Route(ws.PUT("/agent/check/update/{check_id}").To(c.UpdateCheck))
+	ws.Route(ws.GET("/agent/self").To(c.AgentSelf))
+	ws.Route(ws.GET("/status/leader").To(c.StatusLeader))
+
+	if c.configServer != nil {
+		// 根路径的 key 为空，只能用于 recurse 以及 keys 查询
+		ws.Route(ws.GET("/kv/").To(c.GetKV))
+		ws.Route(ws.GET("/kv/{key:*}").To(c.GetKV))
+		ws.Route(ws.PUT("/kv/{key:*}").To(c.PutKV))
+		ws.Route(ws.DELETE("/kv/{key:*}").To(c.DeleteKV))
+	}
+	return ws
+}
+
+// CatalogServices 查询命名空间下所有的服务及其 tags
+func (c *ConsulServer) CatalogServices(req *restful.Request, rsp *restful.Response) {
+	namespace := c.namespace(req)
+	c.blockingQuery(req, rsp, "catalog-services/"+namespace, func() (interface{}, error) {
+		return c.listServices(namespace)
+	})
+}
+
+// CatalogService 查询服务的所有实例
+func (c *ConsulServer) CatalogService(req *restful.Request, rsp *restful.Response) {
+	namespace := c.namespace(req)
+	service := req.PathParameter("service")
+	tags := req.Request.URL.Query()[ParamTag]
+	key := fmt.Sprintf("catalog-service/%s/%s?tag=%s", namespace, service, strings.Join(tags, ","))
+	c.blockingQuery(req, rsp, key, func() (interface{}, error) {
+		return c.catalogService(namespace, service, tags), nil
+	})
+}
+
+// CatalogDatacenters 北极星只有一个数据中心
+func (c *ConsulServer) CatalogDatacenters(req *restful.Request, rsp *restful.Response) {
+	writeJson(rsp, []string{c.datacenter})
+}
+
+// HealthService 查询服务实例及其健康状态
+func (c *ConsulServer) HealthService(req *restful.Request, rsp *restful.Response) {
+	namespace := c.namespace(req)
+	service := req.PathParameter("service")
+	tags := req.Request.URL.Query()[ParamTag]
+	passingOnly := boolQueryParameter(req, ParamPassing)
+	key := fmt.Sprintf("health-service/%s/%s?tag=%s&passing=%v", namespace, service,
+		strings.Join(tags, ","), passingOnly)
+	c.blockingQuery(req, rsp, key, func() (interface{}, error) {
+		return c.healthService(namespace, service, tags, passingOnly), nil
+	})
+}
+
+// RegisterService 注册服务实例
+func (c *ConsulServer) RegisterService(req *restful.Request, rsp *restful.Response) {
+	registration := &AgentServiceRegistration{}
+	if err := req.ReadEntity(registration); err != nil {
+		writeError(rsp, http.StatusBadRequest, "Request decode failed: "+err.Error())
+		return
+	}
+	instance, checkIDs, err := buildPolarisInstance(c.namespace(req), clientIP(req), registration)
+	if err != nil {
+		writeError(rsp, http.StatusBadRequest, "Invalid service: "+err.Error())
+		return
+	}
+	resp := c.registerInstance(c.buildContext(req), instance)
+	if resp.GetCode().GetValue() != api.ExecuteSuccess {
+		writePolarisError(rsp, resp)
+		return
+	}
+	for _, checkID := range checkIDs {
+		c.checkIDs.Store(checkID, instance.GetId().GetValue())
+	}
+	rsp.WriteHeader(http.StatusOK)
+}
+
+// DeregisterService 反注册服务实例
+func (c *ConsulServer) DeregisterService(req *restful.Request, rsp *restful.Response) {
+	resp := c.deregisterInstance(c.buildContext(req), req.PathParameter("service_id"))
+	if resp.GetCode().GetValue() != api.ExecuteSuccess {
+		writePolarisError(rsp, resp)
+		return
+	}
+	rsp.WriteHeader(http.StatusOK)
+}
+
+// PassCheck TTL 检查上报通过
+func (c *ConsulServer) PassCheck(req *restful.Request, rsp *restful.Response) {
+	c.doPassCheck(req, rsp, req.PathParameter("check_id"))
+}
+
+// IgnoreCheck 北极星不支持主动将实例置为不健康，实例在心跳超时后变为不健康
+func (c *ConsulServer) IgnoreCheck(req *restful.Request, rsp *restful.Response) {
+	log.Infof("[CONSUL-SERVER]ignore check status report %s of %s", req.Request.URL.Path,
+		req.PathParameter("check_id"))
+	rsp.WriteHeader(http.StatusOK)
+}
+
+// UpdateCheck 按照请求体中的状态更新 TTL 检查
+func (c *ConsulServer) UpdateCheck(req *restful.Request, rsp *restful.Response) {
+	update := &struct {
+		Status string `json:"Status"`
+		Output string `json:"Output"`
+	}{}
+	if err := req.ReadEntity(update); err != nil {
+		writeError(rsp, http.StatusBadRequest, "Request decode failed: "+err.Error())
+		return
+	}
+	if update.Status != CheckStatusPassing {
+		c.IgnoreCheck(req, rsp)
+		return
+	}
+	c.doPassCheck(req, rsp, req.PathParameter("check_id"))
+}
+
+func (c *ConsulServer) doPassCheck(req *restful.Request, rsp *restful.Response, checkID string) {
+	code := c.passCheck(c.buildContext(req), checkID)
+	if code != api.ExecuteSuccess {
+		writePolarisError(rsp, api.NewResponse(code))
+		return
+	}
+	rsp.WriteHeader(http.StatusOK)
+}
+
+// AgentSelf 返回本节点的信息，prometheus 等客户端通过该接口获取数据中心
+func (c *ConsulServer) AgentSelf(req *restful.Request, rsp *restful.Response) {
+	host, _, _ := net.SplitHostPort(req.Request.Host)
+	if len(host) == 0 {
+		host = req.Request.Host
+	}
+	writeJson(rsp, map[string]interface{}{
+		"Config": map[string]interface{}{
+			"Datacenter": c.datacenter,
+			"NodeName":   host,
+			"Server":     true,
+		},
+		"Member": map[string]interface{}{
+			"Name":   host,
+			"Addr":   host,
+			"Port":   c.listenPort,
+			"Status": 1,
+		},
+	})
+}
+
+// StatusLeader 返回客户端访问的地址作为 leader 地址
+func (c *ConsulServer) StatusLeader(req *restful.Request, rsp *restful.Response) {
+	writeJson(rsp, req.Request.Host)
+}
+
+// GetKV 查询配置，支持 recurse、keys 以及 raw 参数
+func (c *ConsulServer) GetKV(req *restful.Request, rsp *restful.Response) {
+	ctx := c.buildContext(req)
+	namespace := c.namespace(req)
+	key := kvKey(req)
+	recurse := boolQueryParameter(req, ParamRecurse)
+	keysOnly := boolQueryParameter(req, ParamKeys)
+	separator := req.QueryParameter(ParamSeparator)
+
+	var query queryFunc
+	switch {
+	case keysOnly:
+		query = func() (interface{}, error) {
+			pairs, err := c.listKV(ctx, namespace, key)
+			if err != nil {
+				return nil, err
+			}
+			return listKeys(pairs, key, separator), nil
+		}
+	case recurse:
+		query = func() (interface{}, error) {
+			return c.listKV(ctx, namespace, key)
+		}
+	default:
+		group, fileName, err := parseKVKey(key)
+		if err != nil {
+			writeError(rsp, http.StatusBadRequest, err.Error())
+			return
+		}
+		query = func() (interface{}, error) {
+			pair, err := c.getKV(ctx, namespace, group, fileName)
+			if err != nil || pair == nil {
+				return nil, err
+			}
+			return []*KVPair{pair}, nil
+		}
+	}
+
+	indexKey := fmt.Sprintf("kv/%s/%s?recurse=%v&keys=%v&separator=%s", namespace, key, recurse, keysOnly,
+		separator)
+	result, ok := c.doBlockingQuery(req, rsp, indexKey, query)
+	if !ok {
+		return
+	}
+	switch value := result.(type) {
+	case []string:
+		if len(value) == 0 {
+			rsp.WriteHeader(http.StatusNotFound)
+			return
+		}
+	case []*KVPair:
+		if len(value) == 0 {
+			rsp.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if boolQueryParameter(req, ParamRaw) && !recurse {
+			rsp.AddHeader(restful.HEADER_ContentType, restful.MIME_OCTET)
+			rsp.WriteHeader(http.StatusOK)
+			_, _ = rsp.Write(value[0].Value)
+			return
+		}
+	default:
+		rsp.WriteHeader(http.StatusNotFound)
+		return
+	}
+	writeJson(rsp, result)
+}
+
+// PutKV 写入并发布配置，请求体为配置内容
+func (c *ConsulServer) PutKV(req *restful.Request, rsp *restful.Response) {
+	body, err := ioutil.ReadAll(http.MaxBytesReader(rsp, req.Request.Body, MaxKVValueSize))
+	if err != nil {
+		writeError(rsp, http.StatusRequestEntityTooLarge, "Value exceeds 524288 byte limit")
+		return
+	}
+	var cas *uint64
+	if value := req.QueryParameter(ParamCas); len(value) > 0 {
+		index, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			writeError(rsp, http.StatusBadRequest, "Invalid cas index: "+err.Error())
+			return
+		}
+		cas = &index
+	}
+	ok, err := c.putKV(c.buildContext(req), c.namespace(req), kvKey(req), string(body), cas)
+	if err != nil {
+		writeErrorResponse(rsp, err)
+		return
+	}
+	c.indexer.Notify()
+	writeJson(rsp, ok)
+}
+
+// DeleteKV 删除配置
+func (c *ConsulServer) DeleteKV(req *restful.Request, rsp *restful.Response) {
+	err := c.deleteKV(c.buildContext(req), c.namespace(req), kvKey(req),
+		boolQueryParameter(req, ParamRecurse))
+	if err != nil {
+		writeErrorResponse(rsp, err)
+		return
+	}
+	c.indexer.Notify()
+	writeJson(rsp, true)
+}
+
+// blockingQuery 执行阻塞查询并写回结果
+func (c *ConsulServer) blockingQuery(req *restful.Request, rsp *restful.Response, key string, query queryFunc) {
+	if result, ok := c.doBlockingQuery(req, rsp, key, query); ok {
+		writeJson(rsp, result)
+	}
+}
+
+func (c *ConsulServer) doBlockingQuery(req *restful.Request, rsp *restful.Response, key string,
+	query queryFunc) (interface{}, bool) {
+	opts, err := parseQueryOptions(req.QueryParameter(ParamIndex), req.QueryParameter(ParamWait))
+	if err != nil {
+		writeError(rsp, http.StatusBadRequest, "Invalid blocking query parameters: "+err.Error())
+		return nil, false
+	}
+	result, index, err := c.indexer.blockingQuery(req.Request.Context(), key, opts, query)
+	if err != nil {
+		writeErrorResponse(rsp, err)
+		return nil, false
+	}
+	rsp.AddHeader(HeaderConsulIndex, strconv.FormatUint(index, 10))
+	rsp.AddHeader(HeaderConsulKnownLeader, "true")
+	return result, true
+}
+
+// namespace 依次按照 ns 参数、请求头来确定命名空间，都没有指定时使用默认的命名空间
+func (c *ConsulServer) namespace(req *restful.Request) string {
+	if namespace := req.QueryParameter(ParamNamespace); len(namespace) > 0 {
+		return namespace
+	}
+	if namespace := req.HeaderParameter(HeaderConsulNamespace); len(namespace) > 0 {
+		return namespace
+	}
+	return c.defaultNamespace
+}
+
+// buildContext consul 的 ACL token 作为北极星的鉴权 token
+func (c *ConsulServer) buildContext(req *restful.Request) context.Context {
+	token := req.HeaderParameter(HeaderConsulToken)
+	if len(token) == 0 {
+		token = req.QueryParameter(ParamToken)
+	}
+	if len(token) == 0 {
+		token = strings.TrimPrefix(req.HeaderParameter("Authorization"), "Bearer ")
+	}
+	if len(token) == 0 {
+		token = req.HeaderParameter(utils.HeaderAuthTokenKey)
+	}
+	ctx := context.WithValue(context.Background(), utils.ContextAuthTokenKey, token)
+	return context.WithValue(ctx, utils.ContextClientAddress, req.Request.RemoteAddr)
+}
+
+// kvKey 路径参数会去掉末尾的 /，这里直接从路径中截取，保留前缀查询末尾的 /
+func kvKey(req *restful.Request) string {
+	return strings.TrimPrefix(req.Request.URL.Path, "/v1/kv/")
+}
+
+// boolQueryParameter consul 的布尔参数只需要出现即可，如 ?passing、?recurse
+func boolQueryParameter(req *restful.Request, name string) bool {
+	values, ok := req.Request.URL.Query()[name]
+	if !ok {
+		return false
+	}
+	if len(values) == 0 || len(values[0]) == 0 {
+		return true
+	}
+	value, _ := strconv.ParseBool(values[0])
+	return value
+}
+
+func clientIP(req *restful.Request) string {
+	host, _, err := net.SplitHostPort(req.Request.RemoteAddr)
+	if err != nil {
+		return req.Request.RemoteAddr
+	}
+	return host
+}
+
+func writeJson(rsp *restful.Response, value interface{}) {
+	if err := rsp.WriteHeaderAndJson(http.StatusOK, value, restful.MIME_JSON); err != nil {
+		log.Errorf("[CONSUL-SERVER]fail to write response, err: %v", err)
+	}
+}
+
+func writeError(rsp *restful.Response, httpStatus int, message string) {
+	rsp.AddHeader(restful.HEADER_ContentType, "text/plain; charset=utf-8")
+	_ = rsp.WriteErrorString(httpStatus, message)
+}
+
+func writePolarisError(rsp *restful.Response, resp api.ResponseMessage) {
+	writeErrorResponse(rsp, polarisError(resp))
+}
+
+// writeErrorResponse 北极星错误码的前三位与 http 状态码一致
+func writeErrorResponse(rsp *restful.Response, err error) {
+	if err == ErrInvalidKey {
+		writeError(rsp, http.StatusBadRequest, err.Error())
+		return
+	}
+	httpStatus := http.StatusInternalServerError
+	if polarisErr, ok := err.(*PolarisError); ok {
+		if code := int(polarisErr.Code / 1000); code >= http.StatusBadRequest {
+			httpStatus = code
+		}
+	}
+	writeError(rsp, httpStatus, err.Error())
+}
@@ -0,0 +1,230 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package consulserver
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+
+	api "github.com/polarismesh/polaris/common/api/v1"
+	"github.com/polarismesh/polaris/common/utils"
+	"github.com/polarismesh/polaris/config"
+)
+
+// ErrInvalidKey consul 的 key 需要包含配置分组和配置文件名
+var ErrInvalidKey = errors.New("key must be in the form of {group}/{file}")
+
+// parseKVKey consul 的 key 按照第一个 / 拆分为北极星的配置分组和配置文件名，如 app/db.yaml
+func parseKVKey(key string) (string, string, error) {
+	key = strings.TrimPrefix(key, "/")
+	idx := strings.Index(key, "/")
+	if idx <= 0 || idx == len(key)-1 {
+		return "", "", ErrInvalidKey
+	}
+	return key[:idx], key[idx+1:], nil
+}
+
+func toKVKey(group string, fileName string) string {
+	return group + "/" + fileName
+}
+
+// toKVPair 已发布的配置文件转换为 consul 的键值对，修改索引使用配置的发布版本
+func toKVPair(configFile *api.ClientConfigFileInfo) *KVPair {
+	version := configFile.GetVersion().GetValue()
+	return &KVPair{
+		Key:         toKVKey(configFile.GetGroup().GetValue(), configFile.GetFileName().GetValue()),
+		CreateIndex: version,
+		ModifyIndex: version,
+		Value:       []byte(configFile.GetContent().GetValue()),
+	}
+}
+
+// getKV 获取已经发布的配置，配置不存在时返回 nil
+func (c *ConsulServer) getKV(ctx context.Context, namespace string, group string, fileName string) (*KVPair, error) {
+	resp := c.configServer.GetConfigFileForClient(ctx, &api.ClientConfigFileInfo{
+		Namespace: utils.NewStringValue(namespace),
+		Group:     utils.NewStringValue(group),
+		FileName:  utils.NewStringValue(fileName),
+	})
+	switch resp.GetCode().GetValue() {
+	case api.ExecuteSuccess:
+		return toKVPair(resp.GetConfigFile()), nil
+	case api.NotFoundResource:
+		return nil, nil
+	default:
+		return nil, polarisError(resp)
+	}
+}
+
+// listGroups 列出命名空间下以 prefix 开头的配置分组
+func (c *ConsulServer) listGroups(ctx context.Context, namespace string, prefix string) ([]string, error) {
+	groups := make([]string, 0, 4)
+	for offset := uint32(0); ; offset += config.MaxPageSize {
+		resp := c.configServer.QueryConfigFileGroups(ctx, namespace, "", "", offset, config.MaxPageSize)
+		if resp.GetCode().GetValue() != api.ExecuteSuccess {
+			return nil, polarisError(resp)
+		}
+		for _, group := range resp.GetConfigFileGroups() {
+			if strings.HasPrefix(group.GetName().GetValue(), prefix) {
+				groups = append(groups, group.GetName().GetValue())
+			}
+		}
+		if len(resp.GetConfigFileGroups()) < config.MaxPageSize {
+			return groups, nil
+		}
+	}
+}
+
+// listKV 列出以 prefix 开头的所有已发布的配置，prefix 不包含 / 时按照配置分组名匹配
+func (c *ConsulServer) listKV(ctx context.Context, namespace string, prefix string) ([]*KVPair, error) {
+	prefix = strings.TrimPrefix(prefix, "/")
+	var groups []string
+	filePrefix := ""
+	if idx := strings.Index(prefix, "/"); idx >= 0 {
+		groups = []string{prefix[:idx]}
+		filePrefix = prefix[idx+1:]
+	} else {
+		var err error
+		if groups, err = c.listGroups(ctx, namespace, prefix); err != nil {
+			return nil, err
+		}
+	}
+
+	pairs := make([]*KVPair, 0, 8)
+	for _, group := range groups {
+		for offset := uint32(0); ; offset += config.MaxPageSize {
+			resp := c.configServer.QueryConfigFilesByGroup(ctx, namespace, group, offset, config.MaxPageSize)
+			if resp.GetCode().GetValue() != api.ExecuteSuccess {
+				return nil, polarisError(resp)
+			}
+			for _, file := range resp.GetConfigFiles() {
+				if !strings.HasPrefix(file.GetName().GetValue(), filePrefix) {
+					continue
+				}
+				pair, err := c.getKV(ctx, namespace, group, file.GetName().GetValue())
+				if err != nil {
+					return nil, err
+				}
+				if pair != nil {
+					pairs = append(pairs, pair)
+				}
+			}
+			if len(resp.GetConfigFiles()) < config.MaxPageSize {
+				break
+			}
+		}
+	}
+	sort.Slice(pairs, func(i, j int) bool {
+		return pairs[i].Key < pairs[j].Key
+	})
+	return pairs, nil
+}
+
+// listKeys 返回以 prefix 开头的 key，指定分隔符时与 consul 一样只返回到下一个分隔符为止的部分
+func listKeys(pairs []*KVPair, prefix string, separator string) []string {
+	prefix = strings.TrimPrefix(prefix, "/")
+	keys := make([]string, 0, len(pairs))
+	seen := make(map[string]struct{}, len(pairs))
+	for _, pair := range pairs {
+		key := pair.Key
+		if len(separator) > 0 {
+			if idx := strings.Index(key[len(prefix):], separator); idx >= 0 {
+				key = key[:len(prefix)+idx+len(separator)]
+			}
+		}
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// putKV 创建或者更新配置文件并发布。cas 不为空时，0 表示只有配置不存在时才写入，
+// 其他值表示配置当前的发布版本与之相等时才写入，检查和写入不是原子的，只能尽力保证
+func (c *ConsulServer) putKV(ctx context.Context, namespace string, key string, value string,
+	cas *uint64) (bool, error) {
+	group, fileName, err := parseKVKey(key)
+	if err != nil {
+		return false, err
+	}
+	if cas != nil {
+		current, err := c.getKV(ctx, namespace, group, fileName)
+		if err != nil {
+			return false, err
+		}
+		if (*cas == 0 && current != nil) || (*cas > 0 && (current == nil || current.ModifyIndex != *cas)) {
+			return false, nil
+		}
+	}
+
+	configFile := &api.ConfigFile{
+		Namespace: utils.NewStringValue(namespace),
+		Group:     utils.NewStringValue(group),
+		Name:      utils.NewStringValue(fileName),
+		Content:   utils.NewStringValue(value),
+		Format:    utils.NewStringValue(utils.FileFormatText),
+	}
+	resp := c.configServer.CreateConfigFile(ctx, configFile)
+	if resp.GetCode().GetValue() == api.ExistedResource {
+		resp = c.configServer.UpdateConfigFile(ctx, configFile)
+	}
+	if resp.GetCode().GetValue() != api.ExecuteSuccess {
+		return false, polarisError(resp)
+	}
+	resp = c.configServer.PublishConfigFile(ctx, &api.ConfigFileRelease{
+		Namespace: utils.NewStringValue(namespace),
+		Group:     utils.NewStringValue(group),
+		FileName:  utils.NewStringValue(fileName),
+	})
+	if resp.GetCode().GetValue() != api.ExecuteSuccess {
+		return false, polarisError(resp)
+	}
+	return true, nil
+}
+
+// deleteKV 删除配置文件及其发布内容，recurse 时删除以 key 开头的所有配置
+func (c *ConsulServer) deleteKV(ctx context.Context, namespace string, key string, recurse bool) error {
+	var keys []string
+	if recurse {
+		pairs, err := c.listKV(ctx, namespace, key)
+		if err != nil {
+			return err
+		}
+		for _, pair := range pairs {
+			keys = append(keys, pair.Key)
+		}
+	} else {
+		keys = []string{key}
+	}
+	for _, item := range keys {
+		group, fileName, err := parseKVKey(item)
+		if err != nil {
+			return err
+		}
+		resp := c.configServer.DeleteConfigFile(ctx, namespace, group, fileName, utils.ParseUserName(ctx))
+		code := resp.GetCode().GetValue()
+		if code != api.ExecuteSuccess && code != api.NotFoundResource {
+			return polarisError(resp)
+		}
+	}
+	return nil
+}
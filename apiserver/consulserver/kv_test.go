@@ -0,0 +1,144 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package consulserver
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	restful "github.com/emicklei/go-restful/v3"
+	"github.com/stretchr/testify/assert"
+
+	api "github.com/polarismesh/polaris/common/api/v1"
+	"github.com/polarismesh/polaris/common/utils"
+	"github.com/polarismesh/polaris/config"
+)
+
+// mockConfigServer 只实现 KV 查询用到的接口
+type mockConfigServer struct {
+	config.ConfigCenterServer
+	files map[string]string
+}
+
+func (m *mockConfigServer) GetConfigFileForClient(ctx context.Context,
+	req *api.ClientConfigFileInfo) *api.ConfigClientResponse {
+	content, ok := m.files[toKVKey(req.GetGroup().GetValue(), req.GetFileName().GetValue())]
+	if !ok {
+		return api.NewConfigClientResponse(api.NotFoundResource, nil)
+	}
+	file := &api.ClientConfigFileInfo{
+		Namespace: req.GetNamespace(),
+		Group:     req.GetGroup(),
+		FileName:  req.GetFileName(),
+		Content:   utils.NewStringValue(content),
+		Version:   utils.NewUInt64Value(3),
+	}
+	return api.NewConfigClientResponse(api.ExecuteSuccess, file)
+}
+
+func (m *mockConfigServer) QueryConfigFileGroups(ctx context.Context, namespace, groupName, fileName string,
+	offset, limit uint32) *api.ConfigBatchQueryResponse {
+	return api.NewConfigFileGroupBatchQueryResponse(api.ExecuteSuccess, 1,
+		[]*api.ConfigFileGroup{{Name: utils.NewStringValue("app")}})
+}
+
+func (m *mockConfigServer) QueryConfigFilesByGroup(ctx context.Context, namespace, group string,
+	offset, limit uint32) *api.ConfigBatchQueryResponse {
+	var files []*api.ConfigFile
+	for key := range m.files {
+		if fileGroup, fileName, _ := parseKVKey(key); fileGroup == group {
+			files = append(files, &api.ConfigFile{Name: utils.NewStringValue(fileName)})
+		}
+	}
+	return api.NewConfigFileBatchQueryResponse(api.ExecuteSuccess, uint32(len(files)), files)
+}
+
+func TestParseKVKey(t *testing.T) {
+	group, fileName, err := parseKVKey("/app/conf/db.yaml")
+	assert.NoError(t, err)
+	assert.Equal(t, "app", group)
+	assert.Equal(t, "conf/db.yaml", fileName)
+
+	for _, key := range []string{"", "app", "app/", "/app"} {
+		_, _, err = parseKVKey(key)
+		assert.Equal(t, ErrInvalidKey, err, key)
+	}
+}
+
+func TestListKeys(t *testing.T) {
+	pairs := []*KVPair{{Key: "app/a"}, {Key: "app/conf/b"}, {Key: "app/conf/c"}}
+	assert.Equal(t, []string{"app/a", "app/conf/b", "app/conf/c"}, listKeys(pairs, "app/", ""))
+	assert.Equal(t, []string{"app/a", "app/conf/"}, listKeys(pairs, "app/", "/"))
+}
+
+func TestConsulServer_GetKV(t *testing.T) {
+	c := &ConsulServer{
+		defaultNamespace: DefaultNamespace,
+		indexer:          NewIndexer(),
+		configServer: &mockConfigServer{files: map[string]string{
+			"app/db.yaml":      "port: 3306",
+			"app/conf/mq.yaml": "topic: test",
+		}},
+	}
+	container := restful.NewContainer()
+	container.Add(c.GetConsulServer())
+	serve := func(url string) *httptest.ResponseRecorder {
+		rsp := httptest.NewRecorder()
+		container.ServeHTTP(rsp, httptest.NewRequest(http.MethodGet, url, nil))
+		return rsp
+	}
+
+	rsp := serve("/v1/kv/app/db.yaml")
+	assert.Equal(t, http.StatusOK, rsp.Code)
+	assert.NotEmpty(t, rsp.Header().Get(HeaderConsulIndex))
+	var pairs []map[string]interface{}
+	assert.NoError(t, json.Unmarshal(rsp.Body.Bytes(), &pairs))
+	assert.Len(t, pairs, 1)
+	assert.Equal(t, "app/db.yaml", pairs[0]["Key"])
+	assert.Equal(t, base64.StdEncoding.EncodeToString([]byte("port: 3306")), pairs[0]["Value"])
+	assert.Equal(t, float64(3), pairs[0]["ModifyIndex"])
+
+	rsp = serve("/v1/kv/app/db.yaml?raw")
+	assert.Equal(t, http.StatusOK, rsp.Code)
+	assert.Equal(t, "port: 3306", rsp.Body.String())
+
+	rsp = serve("/v1/kv/app/missing.yaml")
+	assert.Equal(t, http.StatusNotFound, rsp.Code)
+	assert.NotEmpty(t, rsp.Header().Get(HeaderConsulIndex))
+
+	rsp = serve("/v1/kv/app")
+	assert.Equal(t, http.StatusBadRequest, rsp.Code)
+
+	rsp = serve("/v1/kv/?keys")
+	assert.Equal(t, http.StatusOK, rsp.Code)
+	assert.JSONEq(t, `["app/conf/mq.yaml","app/db.yaml"]`, rsp.Body.String())
+
+	rsp = serve("/v1/kv/app/?keys&separator=/")
+	assert.Equal(t, http.StatusOK, rsp.Code)
+	assert.JSONEq(t, `["app/conf/","app/db.yaml"]`, rsp.Body.String())
+
+	rsp = serve("/v1/kv/app/conf/?recurse")
+	assert.Equal(t, http.StatusOK, rsp.Code)
+	assert.NoError(t, json.Unmarshal(rsp.Body.Bytes(), &pairs))
+	assert.Len(t, pairs, 1)
+	assert.Equal(t, "app/conf/mq.yaml", pairs[0]["Key"])
+}
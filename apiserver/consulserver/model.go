@@ -0,0 +1,180 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package consulserver
+
+const (
+	// ServerConsul 协议名
+	ServerConsul = "consul"
+
+	// MetadataRegisterFrom 标识实例是通过 consul 协议注册的
+	MetadataRegisterFrom = "internal-register-from"
+	// MetadataConsulTags consul 服务的 tags，以 json 数组的形式存放在实例元数据中
+	MetadataConsulTags = "internal-consul-tags"
+	// MetadataConsulNode consul 注册请求中指定的节点名
+	MetadataConsulNode = "internal-consul-node"
+
+	// HeaderConsulIndex 阻塞查询返回的索引
+	HeaderConsulIndex = "X-Consul-Index"
+	// HeaderConsulKnownLeader consul 客户端用于判断集群状态的响应头
+	HeaderConsulKnownLeader = "X-Consul-KnownLeader"
+	// HeaderConsulToken consul 客户端携带 ACL token 的请求头，映射为北极星的鉴权 token
+	HeaderConsulToken = "X-Consul-Token"
+	// HeaderConsulNamespace consul 企业版指定命名空间的请求头
+	HeaderConsulNamespace = "X-Consul-Namespace"
+
+	ParamIndex     = "index"
+	ParamWait      = "wait"
+	ParamNamespace = "ns"
+	ParamToken     = "token"
+	ParamTag       = "tag"
+	ParamPassing   = "passing"
+	ParamRecurse   = "recurse"
+	ParamKeys      = "keys"
+	ParamRaw       = "raw"
+	ParamSeparator = "separator"
+	ParamCas       = "cas"
+
+	// CheckStatus consul 健康检查的状态
+	CheckStatusPassing  = "passing"
+	CheckStatusWarning  = "warning"
+	CheckStatusCritical = "critical"
+
+	// SerfCheckID consul 节点级别的健康检查
+	SerfCheckID = "serfHealth"
+	// ServiceCheckIDPrefix consul 为服务生成的默认检查 id 的前缀
+	ServiceCheckIDPrefix = "service:"
+	// MaintenanceCheckIDPrefix 服务处于维护状态时的检查 id 前缀，对应北极星的隔离
+	MaintenanceCheckIDPrefix = "_service_maintenance:"
+)
+
+// AgentServiceCheck 注册服务时携带的健康检查
+type AgentServiceCheck struct {
+	CheckID                        string              `json:"CheckID,omitempty"`
+	Name                           string              `json:"Name,omitempty"`
+	Interval                       string              `json:"Interval,omitempty"`
+	Timeout                        string              `json:"Timeout,omitempty"`
+	TTL                            string              `json:"TTL,omitempty"`
+	HTTP                           string              `json:"HTTP,omitempty"`
+	Header                         map[string][]string `json:"Header,omitempty"`
+	Method                         string              `json:"Method,omitempty"`
+	TCP                            string              `json:"TCP,omitempty"`
+	GRPC                           string              `json:"GRPC,omitempty"`
+	GRPCUseTLS                     bool                `json:"GRPCUseTLS,omitempty"`
+	Status                         string              `json:"Status,omitempty"`
+	DeregisterCriticalServiceAfter string              `json:"DeregisterCriticalServiceAfter,omitempty"`
+}
+
+// AgentWeights consul 服务的权重
+type AgentWeights struct {
+	Passing int `json:"Passing"`
+	Warning int `json:"Warning"`
+}
+
+// AgentServiceRegistration /v1/agent/service/register 的请求体
+type AgentServiceRegistration struct {
+	ID      string               `json:"ID,omitempty"`
+	Name    string               `json:"Name,omitempty"`
+	Tags    []string             `json:"Tags,omitempty"`
+	Port    int                  `json:"Port,omitempty"`
+	Address string               `json:"Address,omitempty"`
+	Meta    map[string]string    `json:"Meta,omitempty"`
+	Weights *AgentWeights        `json:"Weights,omitempty"`
+	Check   *AgentServiceCheck   `json:"Check,omitempty"`
+	Checks  []*AgentServiceCheck `json:"Checks,omitempty"`
+}
+
+// CatalogService /v1/catalog/service/:service 返回的服务实例
+type CatalogService struct {
+	ID              string            `json:"ID"`
+	Node            string            `json:"Node"`
+	Address         string            `json:"Address"`
+	Datacenter      string            `json:"Datacenter"`
+	TaggedAddresses map[string]string `json:"TaggedAddresses"`
+	NodeMeta        map[string]string `json:"NodeMeta"`
+	ServiceID       string            `json:"ServiceID"`
+	ServiceName     string            `json:"ServiceName"`
+	ServiceAddress  string            `json:"ServiceAddress"`
+	ServiceTags     []string          `json:"ServiceTags"`
+	ServiceMeta     map[string]string `json:"ServiceMeta"`
+	ServicePort     int               `json:"ServicePort"`
+	ServiceWeights  AgentWeights      `json:"ServiceWeights"`
+	Namespace       string            `json:"Namespace,omitempty"`
+	CreateIndex     uint64            `json:"CreateIndex"`
+	ModifyIndex     uint64            `json:"ModifyIndex"`
+}
+
+// Node consul 节点，北极星没有节点的概念，以实例的 IP 作为节点
+type Node struct {
+	ID              string            `json:"ID"`
+	Node            string            `json:"Node"`
+	Address         string            `json:"Address"`
+	Datacenter      string            `json:"Datacenter"`
+	TaggedAddresses map[string]string `json:"TaggedAddresses"`
+	Meta            map[string]string `json:"Meta"`
+	CreateIndex     uint64            `json:"CreateIndex"`
+	ModifyIndex     uint64            `json:"ModifyIndex"`
+}
+
+// AgentService 健康查询中返回的服务实例
+type AgentService struct {
+	ID          string            `json:"ID"`
+	Service     string            `json:"Service"`
+	Tags        []string          `json:"Tags"`
+	Meta        map[string]string `json:"Meta"`
+	Port        int               `json:"Port"`
+	Address     string            `json:"Address"`
+	Weights     AgentWeights      `json:"Weights"`
+	Namespace   string            `json:"Namespace,omitempty"`
+	Datacenter  string            `json:"Datacenter,omitempty"`
+	CreateIndex uint64            `json:"CreateIndex"`
+	ModifyIndex uint64            `json:"ModifyIndex"`
+}
+
+// HealthCheck 健康检查的结果
+type HealthCheck struct {
+	Node        string   `json:"Node"`
+	CheckID     string   `json:"CheckID"`
+	Name        string   `json:"Name"`
+	Status      string   `json:"Status"`
+	Notes       string   `json:"Notes"`
+	Output      string   `json:"Output"`
+	ServiceID   string   `json:"ServiceID"`
+	ServiceName string   `json:"ServiceName"`
+	ServiceTags []string `json:"ServiceTags"`
+	Namespace   string   `json:"Namespace,omitempty"`
+	CreateIndex uint64   `json:"CreateIndex"`
+	ModifyIndex uint64   `json:"ModifyIndex"`
+}
+
+// ServiceEntry /v1/health/service/:service 返回的服务实例
+type ServiceEntry struct {
+	Node    *Node          `json:"Node"`
+	Service *AgentService  `json:"Service"`
+	Checks  []*HealthCheck `json:"Checks"`
+}
+
+// KVPair consul 的键值对，Value 在 json 中以 base64 编码
+type KVPair struct {
+	Key         string `json:"Key"`
+	CreateIndex uint64 `json:"CreateIndex"`
+	ModifyIndex uint64 `json:"ModifyIndex"`
+	LockIndex   uint64 `json:"LockIndex"`
+	Flags       uint64 `json:"Flags"`
+	Value       []byte `json:"Value"`
+	Session     string `json:"Session,omitempty"`
+}
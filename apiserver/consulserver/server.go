@@ -0,0 +1,210 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package consulserver
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+
+	restful "github.com/emicklei/go-restful/v3"
+	"go.uber.org/zap"
+
+	"github.com/polarismesh/polaris/apiserver"
+	"github.com/polarismesh/polaris/bootstrap"
+	"github.com/polarismesh/polaris/cache"
+	"github.com/polarismesh/polaris/config"
+	"github.com/polarismesh/polaris/service"
+	"github.com/polarismesh/polaris/service/healthcheck"
+)
+
+// ConsulServer 兼容 consul catalog、health、agent 以及 KV 接口的服务器
+type ConsulServer struct {
+	server            *http.Server
+	namingServer      service.DiscoverServer
+	healthCheckServer *healthcheck.Server
+	configServer      config.ConfigCenterServer
+	option            map[string]interface{}
+	openAPI           map[string]apiserver.APIConfig
+	listenIP          string
+	listenPort        uint32
+	datacenter        string
+	defaultNamespace  string
+	indexer           *Indexer
+	// checkIDs 自定义的检查 id -> 实例 id
+	checkIDs sync.Map
+	exitCh   chan struct{}
+	start    bool
+	restart  bool
+}
+
+// GetPort 获取端口
+func (c *ConsulServer) GetPort() uint32 {
+	return c.listenPort
+}
+
+// GetProtocol 获取协议
+func (c *ConsulServer) GetProtocol() string {
+	return ServerConsul
+}
+
+// Initialize 初始化 consul 服务器
+func (c *ConsulServer) Initialize(ctx context.Context, option map[string]interface{},
+	api map[string]apiserver.APIConfig) error {
+	c.listenIP = option[optionListenIP].(string)
+	c.listenPort = uint32(option[optionListenPort].(int))
+	c.datacenter = DefaultDatacenter
+	if value, _ := option[optionDatacenter].(string); len(value) > 0 {
+		c.datacenter = value
+	}
+	c.defaultNamespace = DefaultNamespace
+	if value, _ := option[optionDefaultNamespace].(string); len(value) > 0 {
+		c.defaultNamespace = value
+	}
+	c.option = option
+	c.openAPI = api
+	return nil
+}
+
+// Run 启动 consul 服务器
+func (c *ConsulServer) Run(errCh chan error) {
+	log.Infof("start ConsulServer")
+	c.exitCh = make(chan struct{})
+	c.start = true
+	defer func() {
+		close(c.exitCh)
+		c.start = false
+	}()
+	if err := c.initServers(); err != nil {
+		log.Errorf("%v", err)
+		errCh <- err
+		return
+	}
+
+	address := fmt.Sprintf("%v:%v", c.listenIP, c.listenPort)
+	ln, err := net.Listen("tcp", address)
+	if err != nil {
+		log.Errorf("net listen(%s) err: %s", address, err.Error())
+		errCh <- err
+		return
+	}
+	bootstrap.ApiServerWaitGroup.Done()
+
+	// 阻塞查询最长会等待 MaxBlockingWait，这里不设置写超时
+	c.server = &http.Server{Addr: address, Handler: c.createRestfulContainer()}
+	if err := c.server.Serve(ln); err != nil {
+		log.Errorf("%+v", err)
+		if !c.restart {
+			log.Infof("not in restart progress, broadcast error")
+			errCh <- err
+		}
+		return
+	}
+	log.Infof("consulserver stop")
+}
+
+// initServers 引入功能模块，配置中心没有开启时不提供 KV 接口
+func (c *ConsulServer) initServers() error {
+	var err error
+	if c.namingServer, err = service.GetServer(); err != nil {
+		return err
+	}
+	if c.healthCheckServer, err = healthcheck.GetServer(); err != nil {
+		return err
+	}
+	if configServer, err := config.GetServer(); err == nil {
+		c.configServer = configServer
+	} else {
+		log.Warnf("config server is not initialized, consul kv api is disabled")
+	}
+	// 缓存监听只能注册一次，重启后复用
+	if c.indexer == nil {
+		c.indexer = NewIndexer()
+		listeners := []cache.Listener{c.indexer}
+		c.namingServer.Cache().AddListener(cache.CacheNameService, listeners)
+		c.namingServer.Cache().AddListener(cache.CacheNameInstance, listeners)
+	}
+	return nil
+}
+
+// 创建handler
+func (c *ConsulServer) createRestfulContainer() *restful.Container {
+	wsContainer := restful.NewContainer()
+	wsContainer.Filter(c.process)
+	wsContainer.Add(c.GetConsulServer())
+	return wsContainer
+}
+
+// process 打印写请求
+func (c *ConsulServer) process(req *restful.Request, rsp *restful.Response, chain *restful.FilterChain) {
+	if req.Request.Method != http.MethodGet && !strings.HasPrefix(req.Request.URL.Path, "/v1/agent/check/") {
+		log.Info("receive request",
+			zap.String("client-address", req.Request.RemoteAddr),
+			zap.String("user-agent", req.HeaderParameter("User-Agent")),
+			zap.String("method", req.Request.Method),
+			zap.String("url", req.Request.URL.String()),
+		)
+	}
+	chain.ProcessFilter(req, rsp)
+}
+
+// Stop 结束 consul 服务器
+func (c *ConsulServer) Stop() {
+	if c.server != nil {
+		_ = c.server.Close()
+	}
+}
+
+// Restart 重启 consul 服务器
+func (c *ConsulServer) Restart(
+	option map[string]interface{}, api map[string]apiserver.APIConfig, errCh chan error) error {
+	log.Infof("restart consulserver new config: %+v", option)
+	// 备份一下option
+	backupOption := c.option
+	// 备份一下api
+	backupAPI := c.openAPI
+
+	// 设置restart标记，防止stop的时候把错误抛出
+	c.restart = true
+	c.Stop()
+	if c.start {
+		<-c.exitCh
+	}
+
+	log.Infof("old consulserver has stopped, begin restart consulserver")
+
+	if err := c.Initialize(context.Background(), option, api); err != nil {
+		c.restart = false
+		if initErr := c.Initialize(context.Background(), backupOption, backupAPI); initErr != nil {
+			log.Errorf("start consulserver with backup cfg err: %s", initErr.Error())
+			return initErr
+		}
+		go c.Run(errCh)
+
+		log.Errorf("restart consulserver initialize err: %s", err.Error())
+		return err
+	}
+
+	log.Infof("init consulserver successfully, restart it")
+	c.restart = false
+	go c.Run(errCh)
+	return nil
+}
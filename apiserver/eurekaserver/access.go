@@ -113,6 +113,8 @@ func (h *EurekaServer) addDiscoverAccess(ws *restful.WebService) {
 	// Query for all instances under a particular secure vip address
 	ws.Route(ws.GET(fmt.Sprintf("/svips/{%s}", ParamSVip)).To(h.QueryBySVipAddress)).
 		Param(ws.PathParameter(ParamSVip, "svipAddress").DataType("string"))
+	// Receive batch replication from eureka peers
+	ws.Route(ws.POST("/peerreplication/batch").To(h.BatchReplication))
 }
 
 func parseAcceptValue(acceptValue string) map[string]bool {
@@ -129,7 +131,11 @@ func parseAcceptValue(acceptValue string) map[string]bool {
 
 // GetAllApplications 全量拉取服务实例信息
 func (h *EurekaServer) GetAllApplications(req *restful.Request, rsp *restful.Response) {
-	appsRespCache := h.worker.GetCachedAppsWithLoad()
+	worker := h.getWorker(req, rsp, "")
+	if worker == nil {
+		return
+	}
+	appsRespCache := worker.GetCachedAppsWithLoad()
 	remoteAddr := req.Request.RemoteAddr
 	acceptValue := getParamFromEurekaRequestHeader(req, restful.HEADER_Accept)
 	if err := writeResponse(parseAcceptValue(acceptValue), appsRespCache, req, rsp); nil != err {
@@ -146,7 +152,11 @@ func (h *EurekaServer) GetApplication(req *restful.Request, rsp *restful.Respons
 	appId := h.formatName(req.PathParameter(ParamAppId))
 
 	remoteAddr := req.Request.RemoteAddr
-	appsRespCache := h.worker.GetCachedAppsWithLoad()
+	worker := h.getWorker(req, rsp, appId)
+	if worker == nil {
+		return
+	}
+	appsRespCache := worker.GetCachedAppsWithLoad()
 	apps := appsRespCache.AppsResp.Applications
 	app := apps.GetApplication(appId)
 	if app == nil {
@@ -188,7 +198,11 @@ func (h *EurekaServer) GetAppInstance(req *restful.Request, rsp *restful.Respons
 		writeHeader(http.StatusBadRequest, rsp)
 		return
 	}
-	appsRespCache := h.worker.GetCachedAppsWithLoad()
+	worker := h.getWorker(req, rsp, appId)
+	if worker == nil {
+		return
+	}
+	appsRespCache := worker.GetCachedAppsWithLoad()
 	apps := appsRespCache.AppsResp.Applications
 	app := apps.GetApplication(appId)
 	if app == nil {
@@ -264,13 +278,17 @@ func writeResponse(acceptValues map[string]bool, appsRespCache *ApplicationsResp
 
 // GetDeltaApplications 增量拉取服务实例信息
 func (h *EurekaServer) GetDeltaApplications(req *restful.Request, rsp *restful.Response) {
-	appsRespCache := h.worker.GetDeltaApps()
+	worker := h.getWorker(req, rsp, "")
+	if worker == nil {
+		return
+	}
+	appsRespCache := worker.GetDeltaApps()
 	if nil == appsRespCache {
-		ctx := h.worker.StartWorker()
+		ctx := worker.StartWorker()
 		if nil != ctx {
 			<-ctx.Done()
 		}
-		appsRespCache = h.worker.GetDeltaApps()
+		appsRespCache = worker.GetDeltaApps()
 	}
 	remoteAddr := req.Request.RemoteAddr
 	acceptValue := getParamFromEurekaRequestHeader(req, restful.HEADER_Accept)
@@ -370,12 +388,19 @@ func (h *EurekaServer) RegisterApplication(req *restful.Request, rsp *restful.Re
 
 	log.Infof("[EUREKA-SERVER]received instance register request, client: %s, instId: %s, appId: %s, ipAddr: %s",
 		remoteAddr, registrationRequest.Instance.InstanceId, appId, registrationRequest.Instance.IpAddr)
-	code := h.registerInstances(ctx, appId, registrationRequest.Instance)
+	code := h.registerInstances(ctx, h.resolveNamespace(req, appId), appId, registrationRequest.Instance)
 	if code == api.ExecuteSuccess || code == api.ExistedResource || code == api.SameInstanceRequest {
 		log.Infof("[EUREKA-SERVER]instance (instId=%s, appId=%s) has been registered successfully, code is %d",
 			registrationRequest.Instance.InstanceId, appId, code)
 		writePolarisStatusCode(req, code)
 		writeHeader(http.StatusNoContent, rsp)
+		h.replicateToPeers(req, &ReplicationInstance{
+			AppName:      appId,
+			Id:           registrationRequest.Instance.InstanceId,
+			Status:       registrationRequest.Instance.Status,
+			InstanceInfo: registrationRequest.Instance,
+			Action:       ActionRegister,
+		})
 		return
 	}
 	log.Errorf("[EUREKA-SERVER]instance (instId=%s, appId=%s) has been registered failed, code is %d",
@@ -417,6 +442,8 @@ func (h *EurekaServer) UpdateStatus(req *restful.Request, rsp *restful.Response)
 	if code == api.ExecuteSuccess {
 		log.Infof("[EUREKA-SERVER]instance (instId=%s, appId=%s) has been updated successfully", instId, appId)
 		writeHeader(http.StatusOK, rsp)
+		h.replicateToPeers(req, &ReplicationInstance{AppName: appId, Id: instId, Status: status,
+			Action: ActionStatusUpdate})
 		return
 	}
 	log.Errorf("[EUREKA-SERVER]instance (instId=%s, appId=%s) has been updated failed, code is %d",
@@ -457,6 +484,7 @@ func (h *EurekaServer) DeleteStatus(req *restful.Request, rsp *restful.Response)
 		log.Infof("[EUREKA-SERVER]instance status (instId=%s, appId=%s) has been deleted successfully",
 			instId, appId)
 		writeHeader(http.StatusOK, rsp)
+		h.replicateToPeers(req, &ReplicationInstance{AppName: appId, Id: instId, Action: ActionDeleteStatusOverride})
 		return
 	}
 	log.Errorf("[EUREKA-SERVER]instance status (instId=%s, appId=%s) has been deleted failed, code is %d",
@@ -491,6 +519,8 @@ func (h *EurekaServer) RenewInstance(req *restful.Request, rsp *restful.Response
 	writePolarisStatusCode(req, code)
 	if code == api.ExecuteSuccess || code == api.HeartbeatExceedLimit {
 		writeHeader(http.StatusOK, rsp)
+		h.replicateToPeers(req, &ReplicationInstance{AppName: appId, Id: instId, Status: StatusUp,
+			Action: ActionHeartbeat})
 		return
 	}
 	log.Errorf("[EUREKA-SERVER]instance (instId=%s, appId=%s) heartbeat failed, code is %d",
@@ -529,6 +559,7 @@ func (h *EurekaServer) CancelInstance(req *restful.Request, rsp *restful.Respons
 		writeHeader(http.StatusOK, rsp)
 		log.Infof("[EUREKA-SERVER]instance (instId=%s, appId=%s) has been deregistered successfully, code is %d",
 			instId, appId, code)
+		h.replicateToPeers(req, &ReplicationInstance{AppName: appId, Id: instId, Action: ActionCancel})
 		return
 	}
 	log.Errorf("[EUREKA-SERVER]instance (instId=%s, appId=%s) has been deregistered failed, code is %d",
@@ -547,7 +578,11 @@ func (h *EurekaServer) GetInstance(req *restful.Request, rsp *restful.Response)
 		writeHeader(http.StatusBadRequest, rsp)
 		return
 	}
-	appsRespCache := h.worker.GetCachedAppsWithLoad()
+	worker := h.getWorker(req, rsp, "")
+	if worker == nil {
+		return
+	}
+	appsRespCache := worker.GetCachedAppsWithLoad()
 	apps := appsRespCache.AppsResp.Applications
 	instance := apps.GetInstance(instId)
 	if nil == instance {
@@ -623,7 +658,11 @@ func (h *EurekaServer) QueryByVipAddress(req *restful.Request, rsp *restful.Resp
 		writeHeader(http.StatusBadRequest, rsp)
 		return
 	}
-	appsRespCache := h.worker.GetVipApps(VipCacheKey{
+	worker := h.getWorker(req, rsp, "")
+	if worker == nil {
+		return
+	}
+	appsRespCache := worker.GetVipApps(VipCacheKey{
 		entityType:       entityTypeVip,
 		targetVipAddress: vipAddress,
 	})
@@ -644,7 +683,11 @@ func (h *EurekaServer) QueryBySVipAddress(req *restful.Request, rsp *restful.Res
 		writeHeader(http.StatusBadRequest, rsp)
 		return
 	}
-	appsRespCache := h.worker.GetVipApps(VipCacheKey{
+	worker := h.getWorker(req, rsp, "")
+	if worker == nil {
+		return
+	}
+	appsRespCache := worker.GetVipApps(VipCacheKey{
 		entityType:       entityTypeSVip,
 		targetVipAddress: vipAddress,
 	})
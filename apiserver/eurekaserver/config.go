@@ -29,6 +29,8 @@ const (
 	optionTLS                    = "tls"
 	optionEnableSelfPreservation = "enableSelfPreservation"
 	optionIgnoreUpLow            = "ignoreUpLow"
+	optionPeersToReplicate       = "peersToReplicate"
+	optionAppNamespaces          = "appNamespaces"
 )
 
 const (
@@ -43,4 +45,12 @@ const (
 	// DefaultSelfPreservationDuration instance unhealthy check point to preservation,
 	// instances over 15 min won't get preservation
 	DefaultSelfPreservationDuration = 15 * time.Minute
+	// DefaultReplicateBatchSize max tasks of one peer replication batch
+	DefaultReplicateBatchSize = 250
+	// DefaultReplicateInterval max waiting time before sending a peer replication batch
+	DefaultReplicateInterval = 500 * time.Millisecond
+	// DefaultReplicateQueueSize tasks will be dropped when the queue is full
+	DefaultReplicateQueueSize = 10000
+	// DefaultReplicateTimeout timeout of the peer replication request
+	DefaultReplicateTimeout = 5 * time.Second
 )
@@ -0,0 +1,86 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package eurekaserver
+
+import (
+	"fmt"
+	"net/http"
+
+	restful "github.com/emicklei/go-restful/v3"
+
+	api "github.com/polarismesh/polaris/common/api/v1"
+)
+
+const (
+	// ParamNamespace 路径中指定的命名空间，如 /eureka/ns/{namespace}/apps
+	ParamNamespace = "namespace"
+	// HeaderNamespace 请求头中指定的命名空间
+	HeaderNamespace = "x-polaris-namespace"
+)
+
+// GetEurekaNamespaceServer eureka web server, the polaris namespace is specified by the path
+func (h *EurekaServer) GetEurekaNamespaceServer() *restful.WebService {
+	ws := new(restful.WebService)
+
+	ws.Path(fmt.Sprintf("/eureka/ns/{%s}", ParamNamespace)).Consumes(restful.MIME_JSON, restful.MIME_OCTET,
+		restful.MIME_XML).Produces(restful.MIME_JSON, restful.MIME_XML)
+	h.addDiscoverAccess(ws)
+	return ws
+}
+
+// parseAppNamespaces 解析应用名到命名空间的映射
+func (h *EurekaServer) parseAppNamespaces(option map[string]interface{}) {
+	h.appNamespaces = make(map[string]string)
+	raw, _ := option[optionAppNamespaces].(map[interface{}]interface{})
+	for app, namespace := range raw {
+		appStr, _ := app.(string)
+		namespaceStr, _ := namespace.(string)
+		if len(appStr) == 0 || len(namespaceStr) == 0 {
+			continue
+		}
+		h.appNamespaces[h.formatName(appStr)] = namespaceStr
+	}
+}
+
+// resolveNamespace 依次按照路径、请求头、应用名映射来确定请求所在的命名空间，都没有指定时使用默认的命名空间
+func (h *EurekaServer) resolveNamespace(req *restful.Request, appId string) string {
+	if namespace := req.PathParameter(ParamNamespace); len(namespace) > 0 {
+		return namespace
+	}
+	if namespace := getParamFromEurekaRequestHeader(req, HeaderNamespace); len(namespace) > 0 {
+		return namespace
+	}
+	if len(appId) > 0 {
+		if namespace, ok := h.appNamespaces[h.formatName(appId)]; ok {
+			return namespace
+		}
+	}
+	return h.namespace
+}
+
+// getWorker 获取请求所在命名空间的缓存协程，命名空间不存在时返回 404
+func (h *EurekaServer) getWorker(req *restful.Request, rsp *restful.Response, appId string) *ApplicationsWorker {
+	namespace := h.resolveNamespace(req, appId)
+	worker := h.worker.Get(namespace)
+	if worker == nil {
+		log.Errorf("[EurekaServer]namespace %s not found, client: %s", namespace, req.Request.RemoteAddr)
+		writePolarisStatusCode(req, api.NotFoundNamespace)
+		writeHeader(http.StatusNotFound, rsp)
+	}
+	return worker
+}
@@ -0,0 +1,108 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package eurekaserver
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	restful "github.com/emicklei/go-restful/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEurekaServer_resolveNamespace(t *testing.T) {
+	h := &EurekaServer{namespace: DefaultNamespace}
+	h.parseAppNamespaces(map[string]interface{}{
+		optionAppNamespaces: map[interface{}]interface{}{
+			"order-service": "order",
+		},
+	})
+
+	var resolved string
+	ws := new(restful.WebService)
+	ws.Path("/eureka")
+	handler := func(req *restful.Request, rsp *restful.Response) {
+		resolved = h.resolveNamespace(req, req.PathParameter(ParamAppId))
+	}
+	ws.Route(ws.GET("/apps/{appId}").To(handler))
+	ws.Route(ws.GET("/ns/{namespace}/apps/{appId}").To(handler))
+	container := restful.NewContainer()
+	container.Add(ws)
+
+	testCases := []struct {
+		path      string
+		header    string
+		namespace string
+	}{
+		{path: "/eureka/apps/ECHO", namespace: DefaultNamespace},
+		{path: "/eureka/apps/ORDER-SERVICE", namespace: "order"},
+		{path: "/eureka/apps/ORDER-SERVICE", header: "test", namespace: "test"},
+		{path: "/eureka/ns/dev/apps/ORDER-SERVICE", header: "test", namespace: "dev"},
+	}
+	for _, testCase := range testCases {
+		req := httptest.NewRequest(http.MethodGet, testCase.path, nil)
+		if len(testCase.header) > 0 {
+			req.Header.Set(HeaderNamespace, testCase.header)
+		}
+		container.ServeHTTP(httptest.NewRecorder(), req)
+		assert.Equal(t, testCase.namespace, resolved, testCase.path)
+	}
+}
+
+func TestEurekaServer_NamespaceRoute(t *testing.T) {
+	h := &EurekaServer{}
+	container := restful.NewContainer()
+	container.Add(h.GetEurekaServer())
+	container.Add(h.GetEurekaNamespaceServer())
+
+	req := httptest.NewRequest(http.MethodPost, "/eureka/ns/dev/peerreplication/batch", strings.NewReader("{"))
+	req.Header.Set(restful.HEADER_ContentType, restful.MIME_JSON)
+	rsp := httptest.NewRecorder()
+	container.ServeHTTP(rsp, req)
+	assert.Equal(t, http.StatusBadRequest, rsp.Code)
+}
+
+func TestApplicationsWorkers_Get(t *testing.T) {
+	namespaces := map[string]bool{"order": true}
+	workers := &ApplicationsWorkers{
+		workers: make(map[string]*ApplicationsWorker),
+		creator: func(namespace string) *ApplicationsWorker {
+			return &ApplicationsWorker{mutex: &sync.Mutex{}}
+		},
+		exists: func(namespace string) bool {
+			return namespaces[namespace]
+		},
+		defaultNamespace: DefaultNamespace,
+	}
+
+	assert.NotNil(t, workers.Get(DefaultNamespace))
+	assert.NotNil(t, workers.Get("order"))
+	assert.Same(t, workers.Get("order"), workers.Get("order"))
+	// 不存在的命名空间不会创建缓存协程
+	assert.Nil(t, workers.Get("unknown"))
+	assert.Len(t, workers.workers, 2)
+
+	// 命名空间被删除后移除对应的缓存协程
+	delete(namespaces, "order")
+	workers.evictWorkers()
+	assert.Len(t, workers.workers, 1)
+	assert.Nil(t, workers.Get("order"))
+}
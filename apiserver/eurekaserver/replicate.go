@@ -0,0 +1,202 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package eurekaserver
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	restful "github.com/emicklei/go-restful/v3"
+
+	api "github.com/polarismesh/polaris/common/api/v1"
+	"github.com/polarismesh/polaris/common/utils"
+)
+
+const (
+	// HeaderReplication eureka 节点之间复制请求携带的请求头
+	HeaderReplication = "x-netflix-discovery-replication"
+
+	ActionRegister             = "Register"
+	ActionHeartbeat            = "Heartbeat"
+	ActionCancel               = "Cancel"
+	ActionStatusUpdate         = "StatusUpdate"
+	ActionDeleteStatusOverride = "DeleteStatusOverride"
+)
+
+// ReplicationInstance eureka 节点之间复制的单个实例操作
+type ReplicationInstance struct {
+	AppName            string        `json:"appName"`
+	Id                 string        `json:"id"`
+	LastDirtyTimestamp interface{}   `json:"lastDirtyTimestamp,omitempty"`
+	OverriddenStatus   string        `json:"overriddenStatus,omitempty"`
+	Status             string        `json:"status,omitempty"`
+	InstanceInfo       *InstanceInfo `json:"instanceInfo,omitempty"`
+	Action             string        `json:"action"`
+	// namespace 实例所在的北极星命名空间，不参与序列化，复制时通过请求头 x-polaris-namespace 传递给对端
+	namespace string
+}
+
+// ReplicationList 批量复制请求
+type ReplicationList struct {
+	ReplicationList []*ReplicationInstance `json:"replicationList"`
+}
+
+// ReplicationInstanceResponse 单个实例操作的处理结果
+type ReplicationInstanceResponse struct {
+	StatusCode     int           `json:"statusCode"`
+	ResponseEntity *InstanceInfo `json:"responseEntity,omitempty"`
+}
+
+// ReplicationListResponse 批量复制请求的处理结果
+type ReplicationListResponse struct {
+	ResponseList []*ReplicationInstanceResponse `json:"responseList"`
+}
+
+// BatchReplication 接收其他 eureka 节点批量复制过来的实例操作
+func (h *EurekaServer) BatchReplication(req *restful.Request, rsp *restful.Response) {
+	remoteAddr := req.Request.RemoteAddr
+	replicationList := &ReplicationList{}
+	if err := req.ReadEntity(replicationList); err != nil {
+		log.Errorf("[EUREKA-SERVER] fail to parse peer replication request, uri: %s, client: %s, err: %v",
+			req.Request.RequestURI, remoteAddr, err)
+		writePolarisStatusCode(req, api.ParseException)
+		writeHeader(http.StatusBadRequest, rsp)
+		return
+	}
+	token, err := getAuthFromEurekaRequestHeader(req)
+	if err != nil {
+		log.Infof("[EUREKA-SERVER]peer replication get basic auth info fail, client: %s", remoteAddr)
+		writePolarisStatusCode(req, api.ExecuteException)
+		writeHeader(http.StatusUnauthorized, rsp)
+		return
+	}
+
+	ctx := context.WithValue(context.Background(), utils.ContextAuthTokenKey, token)
+	batchResponse := &ReplicationListResponse{
+		ResponseList: make([]*ReplicationInstanceResponse, 0, len(replicationList.ReplicationList)),
+	}
+	for _, task := range replicationList.ReplicationList {
+		batchResponse.ResponseList = append(batchResponse.ResponseList, h.dispatchReplication(ctx, req, task))
+	}
+	writePolarisStatusCode(req, api.ExecuteSuccess)
+	if err := rsp.WriteHeaderAndJson(http.StatusOK, batchResponse, restful.MIME_JSON); err != nil {
+		log.Errorf("[EUREKA-SERVER]fail to write peer replication response, client: %s, err: %v", remoteAddr, err)
+	}
+}
+
+func (h *EurekaServer) dispatchReplication(ctx context.Context, req *restful.Request,
+	task *ReplicationInstance) *ReplicationInstanceResponse {
+	appId := h.formatName(task.AppName)
+	if len(appId) == 0 || len(task.Id) == 0 {
+		return &ReplicationInstanceResponse{StatusCode: http.StatusBadRequest}
+	}
+	log.Infof("[EUREKA-SERVER]received peer replication, action: %s, instId: %s, appId: %s",
+		task.Action, task.Id, appId)
+
+	var code uint32
+	switch task.Action {
+	case ActionRegister:
+		if task.InstanceInfo == nil {
+			return &ReplicationInstanceResponse{StatusCode: http.StatusBadRequest}
+		}
+		if err := convertInstancePorts(task.InstanceInfo); err != nil {
+			log.Errorf("[EUREKA-SERVER]invalid port of replicated instance %s, err: %v", task.Id, err)
+			return &ReplicationInstanceResponse{StatusCode: http.StatusBadRequest}
+		}
+		code = h.registerInstances(ctx, h.resolveNamespace(req, appId), appId, task.InstanceInfo)
+		if code == api.ExecuteSuccess || code == api.ExistedResource || code == api.SameInstanceRequest {
+			return &ReplicationInstanceResponse{StatusCode: http.StatusNoContent}
+		}
+	case ActionHeartbeat:
+		code = h.renew(ctx, appId, task.Id)
+		if code == api.HeartbeatExceedLimit {
+			code = api.ExecuteSuccess
+		}
+	case ActionCancel:
+		code = h.deregisterInstance(ctx, appId, task.Id)
+		if code == api.NotFoundResource || code == api.SameInstanceRequest {
+			code = api.ExecuteSuccess
+		}
+	case ActionStatusUpdate:
+		if task.Status == StatusUnknown {
+			return &ReplicationInstanceResponse{StatusCode: http.StatusOK}
+		}
+		code = h.updateStatus(ctx, appId, task.Id, task.Status)
+	case ActionDeleteStatusOverride:
+		code = h.updateStatus(ctx, appId, task.Id, StatusUp)
+	default:
+		log.Errorf("[EUREKA-SERVER]unknown peer replication action %s, instId: %s", task.Action, task.Id)
+		return &ReplicationInstanceResponse{StatusCode: http.StatusBadRequest}
+	}
+	return &ReplicationInstanceResponse{StatusCode: replicationStatusCode(code)}
+}
+
+func replicationStatusCode(code uint32) int {
+	switch code {
+	case api.ExecuteSuccess:
+		return http.StatusOK
+	case api.NotFoundResource, api.NotFoundInstance:
+		// 对端收到 404 后会重新复制注册请求
+		return http.StatusNotFound
+	default:
+		return int(code / 1000)
+	}
+}
+
+func convertInstancePorts(instance *InstanceInfo) error {
+	for _, port := range []*PortWrapper{instance.Port, instance.SecurePort} {
+		if port == nil {
+			continue
+		}
+		if err := port.convertPortValue(); err != nil {
+			return err
+		}
+		if err := port.convertEnableValue(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// isReplicationRequest 其他 eureka 节点复制过来的请求，不再向外复制，避免循环
+func isReplicationRequest(req *restful.Request) bool {
+	return strings.EqualFold(getParamFromEurekaRequestHeader(req, HeaderReplication), "true")
+}
+
+// replicateToPeers 把本节点处理成功的写请求复制到其他 eureka 节点，对端按照本节点解析出的命名空间处理
+func (h *EurekaServer) replicateToPeers(req *restful.Request, task *ReplicationInstance) {
+	if h.replicateWorker == nil || isReplicationRequest(req) {
+		return
+	}
+	task.namespace = h.resolveNamespace(req, task.AppName)
+	h.replicateWorker.AddReplicateTask(task)
+}
+
+func (h *EurekaServer) getCachedInstance(namespace string, appId string, instId string) *InstanceInfo {
+	worker := h.worker.Get(namespace)
+	if worker == nil {
+		return nil
+	}
+	apps := worker.GetCachedAppsWithLoad().AppsResp.Applications
+	app := apps.GetApplication(h.formatName(appId))
+	if app == nil {
+		return nil
+	}
+	return app.GetInstance(instId)
+}
@@ -0,0 +1,178 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package eurekaserver
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	restful "github.com/emicklei/go-restful/v3"
+	"github.com/stretchr/testify/assert"
+
+	api "github.com/polarismesh/polaris/common/api/v1"
+	"github.com/polarismesh/polaris/service"
+)
+
+const replicationBody = `{"replicationList":[{"appName":"ECHO","id":"127.0.0.1:echo:8080","lastDirtyTimestamp":1666000000000,
+"overriddenStatus":"UNKNOWN","status":"UP","action":"Register","instanceInfo":{"instanceId":"127.0.0.1:echo:8080",
+"app":"ECHO","ipAddr":"127.0.0.1","status":"UP","port":{"$":8080,"@enabled":"true"},
+"securePort":{"$":443,"@enabled":"false"},"dataCenterInfo":{"@class":
+"com.netflix.appinfo.InstanceInfo$DefaultDataCenterInfo","name":"MyOwn"}}},
+{"appName":"ECHO","id":"127.0.0.1:echo:8080","status":"UP","action":"Heartbeat"}]}`
+
+func TestReplicationList_Unmarshal(t *testing.T) {
+	replicationList := &ReplicationList{}
+	decoder := json.NewDecoder(strings.NewReader(replicationBody))
+	decoder.UseNumber()
+	assert.NoError(t, decoder.Decode(replicationList))
+	assert.Len(t, replicationList.ReplicationList, 2)
+
+	register := replicationList.ReplicationList[0]
+	assert.Equal(t, ActionRegister, register.Action)
+	assert.NoError(t, convertInstancePorts(register.InstanceInfo))
+	assert.Equal(t, 8080, register.InstanceInfo.Port.RealPort)
+	assert.True(t, register.InstanceInfo.Port.RealEnable)
+	assert.False(t, register.InstanceInfo.SecurePort.RealEnable)
+	assert.Equal(t, ActionHeartbeat, replicationList.ReplicationList[1].Action)
+}
+
+func TestEurekaServer_BatchReplicationRoute(t *testing.T) {
+	h := &EurekaServer{}
+	container := restful.NewContainer()
+	container.Add(h.GetEurekaServer())
+
+	req := httptest.NewRequest(http.MethodPost, "/eureka/peerreplication/batch/", strings.NewReader("{"))
+	req.Header.Set(restful.HEADER_ContentType, restful.MIME_JSON)
+	rsp := httptest.NewRecorder()
+	container.ServeHTTP(rsp, req)
+	// 路由存在，请求体非法
+	assert.Equal(t, http.StatusBadRequest, rsp.Code)
+}
+
+func TestReplicateWorker(t *testing.T) {
+	received := make(chan *ReplicationList, 2)
+	peer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/eureka/peerreplication/batch/", r.URL.Path)
+		assert.Equal(t, "true", r.Header.Get(HeaderReplication))
+		assert.Equal(t, DefaultNamespace, r.Header.Get(HeaderNamespace))
+		replicationList := &ReplicationList{}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(replicationList))
+		received <- replicationList
+
+		batchResponse := &ReplicationListResponse{}
+		for _, task := range replicationList.ReplicationList {
+			statusCode := http.StatusOK
+			if task.Action == ActionHeartbeat {
+				// 对端不存在该实例
+				statusCode = http.StatusNotFound
+			}
+			batchResponse.ResponseList = append(batchResponse.ResponseList,
+				&ReplicationInstanceResponse{StatusCode: statusCode})
+		}
+		_ = json.NewEncoder(w).Encode(batchResponse)
+	}))
+	defer peer.Close()
+
+	instance := &InstanceInfo{InstanceId: "ins-1", AppName: "ECHO", IpAddr: "127.0.0.1", Status: StatusUp}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	getter := func(namespace string, appId string, instId string) *InstanceInfo {
+		if namespace == DefaultNamespace && appId == "ECHO" && instId == "ins-1" {
+			return instance
+		}
+		return nil
+	}
+	worker := NewReplicateWorker(ctx, []string{peer.URL + "/eureka/"}, getter)
+	worker.AddReplicateTask(&ReplicationInstance{AppName: "ECHO", Id: "ins-1", Status: StatusUp,
+		Action: ActionHeartbeat, namespace: DefaultNamespace})
+	worker.AddReplicateTask(&ReplicationInstance{AppName: "ECHO", Id: "ins-2", Action: ActionCancel,
+		namespace: DefaultNamespace})
+
+	select {
+	case batch := <-received:
+		assert.Len(t, batch.ReplicationList, 2)
+		assert.Equal(t, ActionHeartbeat, batch.ReplicationList[0].Action)
+		assert.Equal(t, ActionCancel, batch.ReplicationList[1].Action)
+	case <-time.After(5 * time.Second):
+		t.Fatal("replication batch not received")
+	}
+
+	// 心跳返回 404 后补充复制注册
+	select {
+	case batch := <-received:
+		assert.Len(t, batch.ReplicationList, 1)
+		assert.Equal(t, ActionRegister, batch.ReplicationList[0].Action)
+		assert.Equal(t, "127.0.0.1", batch.ReplicationList[0].InstanceInfo.IpAddr)
+	case <-time.After(5 * time.Second):
+		t.Fatal("register replication not received")
+	}
+}
+
+// recordNamingServer 记录注册实例所在的命名空间
+type recordNamingServer struct {
+	service.DiscoverServer
+	registered chan *api.Instance
+}
+
+func (s *recordNamingServer) RegisterInstance(_ context.Context, req *api.Instance) *api.Response {
+	s.registered <- req
+	return api.NewInstanceResponse(api.ExecuteSuccess, req)
+}
+
+func TestEurekaServer_ReplicateNamespace(t *testing.T) {
+	peerNaming := &recordNamingServer{registered: make(chan *api.Instance, 1)}
+	peerServer := &EurekaServer{namespace: DefaultNamespace, namingServer: peerNaming}
+	peerContainer := restful.NewContainer()
+	peerContainer.Add(peerServer.GetEurekaServer())
+	peer := httptest.NewServer(peerContainer)
+	defer peer.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	localNaming := &recordNamingServer{registered: make(chan *api.Instance, 1)}
+	localServer := &EurekaServer{namespace: DefaultNamespace, namingServer: localNaming}
+	localServer.replicateWorker = NewReplicateWorker(ctx, []string{peer.URL + "/eureka"},
+		localServer.getCachedInstance)
+	localContainer := restful.NewContainer()
+	localContainer.Add(localServer.GetEurekaServer())
+
+	body := `{"instance":{"instanceId":"127.0.0.1:echo:8080","app":"ECHO","ipAddr":"127.0.0.1","status":"UP",
+"port":{"$":8080,"@enabled":"true"},"dataCenterInfo":{"@class":
+"com.netflix.appinfo.InstanceInfo$DefaultDataCenterInfo","name":"MyOwn"}}}`
+	req := httptest.NewRequest(http.MethodPost, "/eureka/apps/ECHO", strings.NewReader(body))
+	req.Header.Set(restful.HEADER_ContentType, restful.MIME_JSON)
+	req.Header.Set(HeaderNamespace, "order")
+	rsp := httptest.NewRecorder()
+	localContainer.ServeHTTP(rsp, req)
+	assert.Equal(t, http.StatusNoContent, rsp.Code)
+	assert.Equal(t, "order", (<-localNaming.registered).GetNamespace().GetValue())
+
+	// 对端按照本节点解析出的命名空间注册，而不是对端的默认命名空间
+	select {
+	case instance := <-peerNaming.registered:
+		assert.Equal(t, "order", instance.GetNamespace().GetValue())
+		assert.Equal(t, "ECHO", instance.GetService().GetValue())
+	case <-time.After(5 * time.Second):
+		t.Fatal("register replication not received")
+	}
+}
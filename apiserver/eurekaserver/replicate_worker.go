@@ -0,0 +1,177 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package eurekaserver
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	restful "github.com/emicklei/go-restful/v3"
+)
+
+// InstanceGetter 根据命名空间、应用名和实例 id 获取本节点的 eureka 实例
+type InstanceGetter func(namespace string, appId string, instId string) *InstanceInfo
+
+// ReplicateWorker 将本节点处理的写请求批量复制到其他 eureka 节点
+type ReplicateWorker struct {
+	peers         []string
+	client        *http.Client
+	taskCh        chan *ReplicationInstance
+	batchSize     int
+	batchInterval time.Duration
+	getInstance   InstanceGetter
+}
+
+// NewReplicateWorker 构造函数，peers 为对端的 eureka 地址，如 http://127.0.0.1:8761/eureka
+func NewReplicateWorker(ctx context.Context, peers []string, getInstance InstanceGetter) *ReplicateWorker {
+	worker := &ReplicateWorker{
+		client:        &http.Client{Timeout: DefaultReplicateTimeout},
+		taskCh:        make(chan *ReplicationInstance, DefaultReplicateQueueSize),
+		batchSize:     DefaultReplicateBatchSize,
+		batchInterval: DefaultReplicateInterval,
+		getInstance:   getInstance,
+	}
+	for _, peer := range peers {
+		worker.peers = append(worker.peers, strings.TrimSuffix(peer, "/"))
+	}
+	go worker.doBatchReplicate(ctx)
+	return worker
+}
+
+// AddReplicateTask 添加复制任务，队列满时直接丢弃，由 eureka 客户端的心跳自行修复
+func (r *ReplicateWorker) AddReplicateTask(task *ReplicationInstance) {
+	select {
+	case r.taskCh <- task:
+	default:
+		log.Warnf("[EUREKA-SERVER]replicate queue is full, drop task, action: %s, instId: %s",
+			task.Action, task.Id)
+	}
+}
+
+func (r *ReplicateWorker) doBatchReplicate(ctx context.Context) {
+	ticker := time.NewTicker(r.batchInterval)
+	defer ticker.Stop()
+	tasks := make([]*ReplicationInstance, 0, r.batchSize)
+	for {
+		select {
+		case task := <-r.taskCh:
+			tasks = append(tasks, task)
+			if len(tasks) < r.batchSize {
+				continue
+			}
+		case <-ticker.C:
+			if len(tasks) == 0 {
+				continue
+			}
+		case <-ctx.Done():
+			return
+		}
+		namespaces, groups := groupTasksByNamespace(tasks)
+		for _, peer := range r.peers {
+			for _, namespace := range namespaces {
+				r.replicateToPeer(peer, namespace, groups[namespace])
+			}
+		}
+		tasks = make([]*ReplicationInstance, 0, r.batchSize)
+	}
+}
+
+// groupTasksByNamespace 按照命名空间对复制任务分组，各命名空间内的任务保持原有的先后顺序
+func groupTasksByNamespace(tasks []*ReplicationInstance) ([]string, map[string][]*ReplicationInstance) {
+	var namespaces []string
+	groups := make(map[string][]*ReplicationInstance)
+	for _, task := range tasks {
+		if _, ok := groups[task.namespace]; !ok {
+			namespaces = append(namespaces, task.namespace)
+		}
+		groups[task.namespace] = append(groups[task.namespace], task)
+	}
+	return namespaces, groups
+}
+
+// replicateToPeer 将同一个命名空间的复制任务批量发送到对端
+func (r *ReplicateWorker) replicateToPeer(peer string, namespace string, tasks []*ReplicationInstance) {
+	batchResponse, err := r.sendBatch(peer, namespace, tasks)
+	if err != nil {
+		log.Errorf("[EUREKA-SERVER]fail to replicate %d tasks to peer %s, err: %v", len(tasks), peer, err)
+		return
+	}
+
+	// 对端不存在该实例时，心跳会返回 404，需要补充复制一次注册
+	var registers []*ReplicationInstance
+	for i, response := range batchResponse.ResponseList {
+		if i >= len(tasks) || response.StatusCode != http.StatusNotFound || tasks[i].Action != ActionHeartbeat {
+			continue
+		}
+		instance := r.getInstance(namespace, tasks[i].AppName, tasks[i].Id)
+		if instance == nil {
+			continue
+		}
+		registers = append(registers, &ReplicationInstance{
+			AppName:      tasks[i].AppName,
+			Id:           tasks[i].Id,
+			Status:       instance.Status,
+			InstanceInfo: instance,
+			Action:       ActionRegister,
+			namespace:    namespace,
+		})
+	}
+	if len(registers) == 0 {
+		return
+	}
+	if _, err := r.sendBatch(peer, namespace, registers); err != nil {
+		log.Errorf("[EUREKA-SERVER]fail to replicate %d registers to peer %s, err: %v", len(registers), peer, err)
+	}
+}
+
+// sendBatch 发送一批复制任务，命名空间通过请求头传递，为空时由对端使用默认的命名空间
+func (r *ReplicateWorker) sendBatch(peer string, namespace string,
+	tasks []*ReplicationInstance) (*ReplicationListResponse, error) {
+	body, err := json.Marshal(&ReplicationList{ReplicationList: tasks})
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequest(http.MethodPost, peer+"/peerreplication/batch/", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set(restful.HEADER_ContentType, restful.MIME_JSON)
+	req.Header.Set(restful.HEADER_Accept, restful.MIME_JSON)
+	req.Header.Set(HeaderReplication, "true")
+	if len(namespace) > 0 {
+		req.Header.Set(HeaderNamespace, namespace)
+	}
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+	batchResponse := &ReplicationListResponse{}
+	if err := json.NewDecoder(resp.Body).Decode(batchResponse); err != nil {
+		return nil, err
+	}
+	return batchResponse, nil
+}
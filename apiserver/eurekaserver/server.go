@@ -122,7 +122,7 @@ type EurekaServer struct {
 	tlsInfo                *secure.TLSInfo
 	option                 map[string]interface{}
 	openAPI                map[string]apiserver.APIConfig
-	worker                 *ApplicationsWorker
+	worker                 *ApplicationsWorkers
 	listenPort             uint32
 	listenIP               string
 	exitCh                 chan struct{}
@@ -131,9 +131,13 @@ type EurekaServer struct {
 	rateLimit              plugin.Ratelimit
 	statis                 plugin.Statis
 	namespace              string
+	appNamespaces          map[string]string
 	refreshInterval        time.Duration
 	deltaExpireInterval    time.Duration
 	enableSelfPreservation bool
+	replicatePeers         []string
+	replicateWorker        *ReplicateWorker
+	replicateCancel        context.CancelFunc
 }
 
 // GetPort 获取端口
@@ -163,6 +167,7 @@ func (h *EurekaServer) Initialize(ctx context.Context, option map[string]interfa
 		}
 	}
 	h.namespace = namespace
+	h.parseAppNamespaces(option)
 
 	var refreshInterval int
 	if value, ok := option[optionRefreshInterval]; ok {
@@ -210,6 +215,15 @@ func (h *EurekaServer) Initialize(ctx context.Context, option map[string]interfa
 		enableSelfPreservation = DefaultEnableSelfPreservation
 	}
 	h.enableSelfPreservation = enableSelfPreservation
+
+	h.replicatePeers = nil
+	if value, ok := option[optionPeersToReplicate].([]interface{}); ok {
+		for _, peer := range value {
+			if peerStr, ok := peer.(string); ok && len(peerStr) > 0 {
+				h.replicatePeers = append(h.replicatePeers, peerStr)
+			}
+		}
+	}
 	return nil
 }
 
@@ -236,9 +250,15 @@ func (h *EurekaServer) Run(errCh chan error) {
 		errCh <- err
 		return
 	}
-	h.worker = NewApplicationsWorker(h.refreshInterval, h.deltaExpireInterval, h.enableSelfPreservation,
+	h.worker = NewApplicationsWorkers(h.refreshInterval, h.deltaExpireInterval, h.enableSelfPreservation,
 		h.namingServer, h.healthCheckServer, h.namespace)
 	h.statis = plugin.GetStatis()
+	if len(h.replicatePeers) > 0 {
+		log.Infof("eureka server replicate to peers %v", h.replicatePeers)
+		var ctx context.Context
+		ctx, h.replicateCancel = context.WithCancel(context.Background())
+		h.replicateWorker = NewReplicateWorker(ctx, h.replicatePeers, h.getCachedInstance)
+	}
 	// 初始化http server
 	address := fmt.Sprintf("%v:%v", h.listenIP, h.listenPort)
 
@@ -296,6 +316,7 @@ func (h *EurekaServer) createRestfulContainer() (*restful.Container, error) {
 	wsContainer.Add(h.GetEurekaV2Server())
 	wsContainer.Add(h.GetEurekaV1Server())
 	wsContainer.Add(h.GetEurekaServer())
+	wsContainer.Add(h.GetEurekaNamespaceServer())
 	return wsContainer, nil
 }
 
@@ -480,6 +501,11 @@ func (h *EurekaServer) Stop() {
 		_ = h.server.Close()
 	}
 	h.worker.Stop()
+	if h.replicateCancel != nil {
+		h.replicateCancel()
+		h.replicateCancel = nil
+		h.replicateWorker = nil
+	}
 }
 
 // Restart 重启eurekaServer
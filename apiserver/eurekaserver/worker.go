@@ -265,3 +265,94 @@ func (a *ApplicationsWorker) Stop() {
 		a.workerCancel()
 	}
 }
+
+// ApplicationsWorkers 按命名空间维护应用缓存协程，每个命名空间的全量和增量缓存相互独立
+// 只为已经存在的命名空间创建缓存协程，命名空间被删除后停止并移除对应的协程
+type ApplicationsWorkers struct {
+	mutex            sync.Mutex
+	workers          map[string]*ApplicationsWorker
+	creator          func(namespace string) *ApplicationsWorker
+	exists           func(namespace string) bool
+	defaultNamespace string
+	cancel           context.CancelFunc
+}
+
+// NewApplicationsWorkers 构造函数
+func NewApplicationsWorkers(interval time.Duration,
+	deltaExpireInterval time.Duration, enableSelfPreservation bool,
+	namingServer service.DiscoverServer, healthCheckServer *healthcheck.Server,
+	defaultNamespace string) *ApplicationsWorkers {
+	ctx, cancel := context.WithCancel(context.Background())
+	workers := &ApplicationsWorkers{
+		workers: make(map[string]*ApplicationsWorker),
+		creator: func(namespace string) *ApplicationsWorker {
+			return NewApplicationsWorker(interval, deltaExpireInterval, enableSelfPreservation,
+				namingServer, healthCheckServer, namespace)
+		},
+		exists: func(namespace string) bool {
+			return namingServer.Cache().Namespace().GetNamespace(namespace) != nil
+		},
+		defaultNamespace: defaultNamespace,
+		cancel:           cancel,
+	}
+	go workers.timingEvictWorkers(ctx, interval)
+	return workers
+}
+
+// Get 获取命名空间对应的缓存协程，不存在则创建，命名空间不存在时返回 nil
+func (a *ApplicationsWorkers) Get(namespace string) *ApplicationsWorker {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	worker, ok := a.workers[namespace]
+	if ok {
+		return worker
+	}
+	if !a.isValid(namespace) {
+		return nil
+	}
+	worker = a.creator(namespace)
+	a.workers[namespace] = worker
+	return worker
+}
+
+// isValid 默认命名空间总是有效，其余命名空间需要存在于命名空间缓存中
+func (a *ApplicationsWorkers) isValid(namespace string) bool {
+	return namespace == a.defaultNamespace || a.exists(namespace)
+}
+
+func (a *ApplicationsWorkers) timingEvictWorkers(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			a.evictWorkers()
+		}
+	}
+}
+
+// evictWorkers 停止并移除已经被删除的命名空间的缓存协程
+func (a *ApplicationsWorkers) evictWorkers() {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	for namespace, worker := range a.workers {
+		if a.isValid(namespace) {
+			continue
+		}
+		worker.Stop()
+		delete(a.workers, namespace)
+		log.Infof("[EurekaServer]namespace %s deleted, stop applications worker", namespace)
+	}
+}
+
+// Stop 结束所有命名空间的任务
+func (a *ApplicationsWorkers) Stop() {
+	a.cancel()
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	for _, worker := range a.workers {
+		worker.Stop()
+	}
+}
@@ -168,9 +168,10 @@ func convertEurekaInstance(instance *InstanceInfo, namespace string, appId strin
 	return targetInstance
 }
 
-func (h *EurekaServer) registerInstances(ctx context.Context, appId string, instance *InstanceInfo) uint32 {
+func (h *EurekaServer) registerInstances(ctx context.Context, namespace string, appId string,
+	instance *InstanceInfo) uint32 {
 	// 1. 先转换数据结构
-	totalInstance := convertEurekaInstance(instance, h.namespace, appId)
+	totalInstance := convertEurekaInstance(instance, namespace, appId)
 	// 3. 注册实例
 	resp := h.namingServer.RegisterInstance(ctx, totalInstance)
 	// 4. 注册成功，则返回
@@ -180,7 +181,7 @@ func (h *EurekaServer) registerInstances(ctx context.Context, appId string, inst
 	// 5. 如果报服务不存在，对服务进行注册
 	if resp.Code.Value == api.NotFoundResource {
 		svc := &api.Service{}
-		svc.Namespace = &wrappers.StringValue{Value: h.namespace}
+		svc.Namespace = &wrappers.StringValue{Value: namespace}
 		svc.Name = &wrappers.StringValue{Value: appId}
 		svcResp := h.namingServer.CreateServices(ctx, []*api.Service{svc})
 		svcCreateCode := svcResp.GetCode().GetValue()
@@ -397,8 +397,10 @@ func (b *BaseGrpcServer) AllowAccess(method string) bool {
 // ConvertContext 将GRPC上下文转换成内部上下文
 func ConvertContext(ctx context.Context) context.Context {
 	var (
-		requestID = ""
-		userAgent = ""
+		requestID    = ""
+		userAgent    = ""
+		clientLabels = ""
+		publicKey    = ""
 	)
 	meta, exist := metadata.FromIncomingContext(ctx)
 	if exist {
@@ -410,6 +412,14 @@ func ConvertContext(ctx context.Context) context.Context {
 		if len(agents) > 0 {
 			userAgent = agents[0]
 		}
+		labels := meta.Get(utils.HeaderClientLabelsKey)
+		if len(labels) > 0 {
+			clientLabels = labels[0]
+		}
+		publicKeys := meta.Get(utils.HeaderConfigPublicKey)
+		if len(publicKeys) > 0 {
+			publicKey = publicKeys[0]
+		}
 	} else {
 		meta = metadata.MD{}
 	}
@@ -432,6 +442,12 @@ func ConvertContext(ctx context.Context) context.Context {
 	ctx = context.WithValue(ctx, utils.StringContext("client-ip"), clientIP)
 	ctx = context.WithValue(ctx, utils.ContextClientAddress, address)
 	ctx = context.WithValue(ctx, utils.StringContext("user-agent"), userAgent)
+	if clientLabels != "" {
+		ctx = context.WithValue(ctx, utils.ContextClientLabels, clientLabels)
+	}
+	if publicKey != "" {
+		ctx = context.WithValue(ctx, utils.ContextConfigPublicKey, publicKey)
+	}
 
 	return ctx
 }
@@ -85,6 +85,8 @@ func (g *DiscoverServer) Discover(server apiv2.PolarisGRPC_DiscoverServer) error
 			out = g.namingServer.GetRoutingConfigV2WithCache(ctx, in.GetSerivce())
 		case apiv2.DiscoverRequest_CIRCUIT_BREAKER:
 			out = g.namingServer.GetCircuitBreakerV2WithCache(ctx, in.GetSerivce())
+		case apiv2.DiscoverRequest_FAULT_MIRROR:
+			out = g.namingServer.GetFaultMirrorWithCache(ctx, in.GetSerivce())
 		default:
 			out = apiv2.NewDiscoverRoutingResponse(apiv1.InvalidDiscoverResource, in.GetSerivce())
 		}
@@ -18,6 +18,7 @@
 package httpserver
 
 import (
+	"encoding/json"
 	"strconv"
 
 	"github.com/emicklei/go-restful/v3"
@@ -26,6 +27,7 @@ import (
 
 	httpcommon "github.com/polarismesh/polaris/apiserver/httpserver/http"
 	api "github.com/polarismesh/polaris/common/api/v1"
+	"github.com/polarismesh/polaris/common/model"
 	"github.com/polarismesh/polaris/common/utils"
 )
 
@@ -106,6 +108,38 @@ func (h *HTTPServer) UpdateConfigFileGroup(req *restful.Request, rsp *restful.Re
 	handler.WriteHeaderAndProto(h.configServer.UpdateConfigFileGroup(ctx, configFileGroup))
 }
 
+// configFileGroupSchemaRequest 更新配置文件组 JSON Schema 请求
+type configFileGroupSchemaRequest struct {
+	Namespace string          `json:"namespace"`
+	Name      string          `json:"name"`
+	Schema    json.RawMessage `json:"schema"`
+}
+
+// UpdateConfigFileGroupSchema 更新配置文件组的 JSON Schema
+func (h *HTTPServer) UpdateConfigFileGroupSchema(req *restful.Request, rsp *restful.Response) {
+	handler := &httpcommon.Handler{
+		Request:  req,
+		Response: rsp,
+	}
+
+	ctx := handler.ParseHeaderContext()
+	schemaReq := &configFileGroupSchemaRequest{}
+	if err := json.NewDecoder(req.Request.Body).Decode(schemaReq); err != nil {
+		configLog.Error("[Config][HttpServer] parse config file group schema from request error.",
+			zap.String("requestId", utils.ParseRequestID(ctx)),
+			zap.String("error", err.Error()))
+		handler.WriteHeaderAndProto(api.NewConfigFileGroupResponseWithMessage(api.ParseException, err.Error()))
+		return
+	}
+
+	schema := string(schemaReq.Schema)
+	if schema == "null" {
+		schema = ""
+	}
+	handler.WriteHeaderAndProto(h.configServer.UpdateConfigFileGroupSchema(ctx, schemaReq.Namespace,
+		schemaReq.Name, schema))
+}
+
 // CreateConfigFile 创建配置文件
 func (h *HTTPServer) CreateConfigFile(req *restful.Request, rsp *restful.Response) {
 	handler := &httpcommon.Handler{
@@ -262,6 +296,96 @@ func (h *HTTPServer) PublishConfigFile(req *restful.Request, rsp *restful.Respon
 	handler.WriteHeaderAndProto(h.configServer.PublishConfigFile(ctx, configFile))
 }
 
+// configFileGrayReleaseRequest 灰度发布请求
+type configFileGrayReleaseRequest struct {
+	Namespace string                    `json:"namespace"`
+	Group     string                    `json:"group"`
+	FileName  string                    `json:"fileName"`
+	Name      string                    `json:"name"`
+	Comment   string                    `json:"comment"`
+	Rule      *model.ConfigFileGrayRule `json:"rule"`
+}
+
+// PublishConfigFileGray 灰度发布配置文件
+func (h *HTTPServer) PublishConfigFileGray(req *restful.Request, rsp *restful.Response) {
+	handler := &httpcommon.Handler{
+		Request:  req,
+		Response: rsp,
+	}
+
+	ctx := handler.ParseHeaderContext()
+	grayReq := &configFileGrayReleaseRequest{}
+	if err := json.NewDecoder(req.Request.Body).Decode(grayReq); err != nil {
+		configLog.Error("[Config][HttpServer] parse config file gray release from request error.",
+			zap.String("requestId", utils.ParseRequestID(ctx)),
+			zap.String("error", err.Error()))
+		handler.WriteHeaderAndProto(api.NewConfigFileReleaseResponseWithMessage(api.ParseException, err.Error()))
+		return
+	}
+
+	configFileRelease := &api.ConfigFileRelease{
+		Name:      utils.NewStringValue(grayReq.Name),
+		Namespace: utils.NewStringValue(grayReq.Namespace),
+		Group:     utils.NewStringValue(grayReq.Group),
+		FileName:  utils.NewStringValue(grayReq.FileName),
+		Comment:   utils.NewStringValue(grayReq.Comment),
+	}
+	handler.WriteHeaderAndProto(h.configServer.PublishConfigFileGray(ctx, configFileRelease, grayReq.Rule))
+}
+
+// PromoteConfigFileGrayRelease 配置文件灰度转全量
+func (h *HTTPServer) PromoteConfigFileGrayRelease(req *restful.Request, rsp *restful.Response) {
+	handler := &httpcommon.Handler{
+		Request:  req,
+		Response: rsp,
+	}
+
+	namespace := handler.Request.QueryParameter("namespace")
+	group := handler.Request.QueryParameter("group")
+	name := handler.Request.QueryParameter("name")
+
+	response := h.configServer.PromoteConfigFileGrayRelease(handler.ParseHeaderContext(), namespace, group, name)
+
+	handler.WriteHeaderAndProto(response)
+}
+
+// AbortConfigFileGrayRelease 终止配置文件灰度发布
+func (h *HTTPServer) AbortConfigFileGrayRelease(req *restful.Request, rsp *restful.Response) {
+	handler := &httpcommon.Handler{
+		Request:  req,
+		Response: rsp,
+	}
+
+	namespace := handler.Request.QueryParameter("namespace")
+	group := handler.Request.QueryParameter("group")
+	name := handler.Request.QueryParameter("name")
+
+	response := h.configServer.AbortConfigFileGrayRelease(handler.ParseHeaderContext(), namespace, group, name)
+
+	handler.WriteHeaderAndProto(response)
+}
+
+// RollbackConfigFile 回滚配置文件到指定的历史发布版本
+func (h *HTTPServer) RollbackConfigFile(req *restful.Request, rsp *restful.Response) {
+	handler := &httpcommon.Handler{
+		Request:  req,
+		Response: rsp,
+	}
+
+	namespace := handler.Request.QueryParameter("namespace")
+	group := handler.Request.QueryParameter("group")
+	name := handler.Request.QueryParameter("name")
+	historyId, err := strconv.ParseUint(handler.Request.QueryParameter("historyId"), 10, 64)
+	if err != nil {
+		handler.WriteHeaderAndProto(api.NewConfigFileResponseWithMessage(api.InvalidParameter, "invalid historyId"))
+		return
+	}
+
+	response := h.configServer.RollbackConfigFile(handler.ParseHeaderContext(), namespace, group, name, historyId)
+
+	handler.WriteHeaderAndProto(response)
+}
+
 // GetConfigFileRelease 获取配置文件最后一次发布内容
 func (h *HTTPServer) GetConfigFileRelease(req *restful.Request, rsp *restful.Response) {
 	handler := &httpcommon.Handler{
@@ -64,6 +64,8 @@ func (h *HTTPServer) bindConfigConsoleEndpoint(ws *restful.WebService) {
 	ws.Route(enrichQueryConfigFileGroupsApiDocs(ws.GET("/configfilegroups").To(h.QueryConfigFileGroups)))
 	ws.Route(enrichDeleteConfigFileGroupApiDocs(ws.DELETE("/configfilegroups").To(h.DeleteConfigFileGroup)))
 	ws.Route(enrichUpdateConfigFileGroupApiDocs(ws.PUT("/configfilegroups").To(h.UpdateConfigFileGroup)))
+	ws.Route(enrichUpdateConfigFileGroupSchemaApiDocs(ws.PUT("/configfilegroups/schema").
+		To(h.UpdateConfigFileGroupSchema)))
 
 	// 配置文件
 	ws.Route(enrichCreateConfigFileApiDocs(ws.POST("/configfiles").To(h.CreateConfigFile)))
@@ -77,6 +79,12 @@ func (h *HTTPServer) bindConfigConsoleEndpoint(ws *restful.WebService) {
 	// 配置文件发布
 	ws.Route(enrichPublishConfigFileApiDocs(ws.POST("/configfiles/release").To(h.PublishConfigFile)))
 	ws.Route(enrichGetConfigFileReleaseApiDocs(ws.GET("/configfiles/release").To(h.GetConfigFileRelease)))
+	ws.Route(enrichPublishConfigFileGrayApiDocs(ws.POST("/configfiles/release/gray").To(h.PublishConfigFileGray)))
+	ws.Route(enrichPromoteConfigFileGrayApiDocs(ws.POST("/configfiles/release/gray/promote").
+		To(h.PromoteConfigFileGrayRelease)))
+	ws.Route(enrichAbortConfigFileGrayApiDocs(ws.POST("/configfiles/release/gray/abort").
+		To(h.AbortConfigFileGrayRelease)))
+	ws.Route(enrichRollbackConfigFileApiDocs(ws.POST("/configfiles/release/rollback").To(h.RollbackConfigFile)))
 
 	// 配置文件发布历史
 	ws.Route(enrichGetConfigFileReleaseHistoryApiDocs(ws.GET("/configfiles/releasehistory").To(h.GetConfigFileReleaseHistory)))
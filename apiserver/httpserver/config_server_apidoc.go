@@ -62,6 +62,13 @@ func enrichUpdateConfigFileGroupApiDocs(r *restful.RouteBuilder) *restful.RouteB
 		Reads(api.ConfigFileGroup{}, "开启北极星服务端针对控制台接口鉴权开关后，需要添加下面的 header\nHeader X-Polaris-Token: {访问凭据}\n ```\n{\n    \"name\":\"someGroup\",\n    \"namespace\":\"someNamespace\",\n    \"comment\":\"some comment\",\n    \"createBy\":\"ledou\"\n}\n```")
 }
 
+func enrichUpdateConfigFileGroupSchemaApiDocs(r *restful.RouteBuilder) *restful.RouteBuilder {
+	return r.
+		Doc("更新配置文件组的 JSON Schema").
+		Metadata(restfulspec.KeyOpenAPITags, configConsoleApiTags).
+		Reads(configFileGroupSchemaRequest{}, "创建、更新以及发布配置文件时按照 Schema 校验配置内容，schema 为 null 时取消校验\n```{\n    \"name\":\"someGroup\",\n    \"namespace\":\"someNamespace\",\n    \"schema\":{\n        \"type\":\"object\",\n        \"required\":[\"port\"],\n        \"properties\":{\"port\":{\"type\":\"integer\"}}\n    }\n}\n```")
+}
+
 func enrichCreateConfigFileApiDocs(r *restful.RouteBuilder) *restful.RouteBuilder {
 	return r.
 		Doc("创建配置文件").
@@ -141,6 +148,41 @@ func enrichGetConfigFileReleaseApiDocs(r *restful.RouteBuilder) *restful.RouteBu
 		Param(restful.QueryParameter("name", "配置文件").DataType("string").Required(true))
 }
 
+func enrichPublishConfigFileGrayApiDocs(r *restful.RouteBuilder) *restful.RouteBuilder {
+	return r.
+		Doc("灰度发布配置文件").
+		Metadata(restfulspec.KeyOpenAPITags, configConsoleApiTags).
+		Reads(configFileGrayReleaseRequest{}, "灰度规则中的条件为或的关系，percentage 按客户端 IP 散列\n```{\n    \"name\":\"release-gray\",\n    \"fileName\":\"application.properties\",\n    \"namespace\":\"someNamespace\",\n    \"group\":\"someGroup\",\n    \"comment\":\"灰度发布\",\n    \"rule\":{\n        \"clientIps\":[\"127.0.0.1\"],\n        \"labels\":{\"env\":\"gray\"},\n        \"percentage\":10\n    }\n}\n```")
+}
+
+func enrichPromoteConfigFileGrayApiDocs(r *restful.RouteBuilder) *restful.RouteBuilder {
+	return r.
+		Doc("配置文件灰度转全量").
+		Metadata(restfulspec.KeyOpenAPITags, configConsoleApiTags).
+		Param(restful.QueryParameter("namespace", "命名空间").DataType("string").Required(true)).
+		Param(restful.QueryParameter("group", "配置文件分组").DataType("string").Required(true)).
+		Param(restful.QueryParameter("name", "配置文件").DataType("string").Required(true))
+}
+
+func enrichAbortConfigFileGrayApiDocs(r *restful.RouteBuilder) *restful.RouteBuilder {
+	return r.
+		Doc("终止配置文件灰度发布").
+		Metadata(restfulspec.KeyOpenAPITags, configConsoleApiTags).
+		Param(restful.QueryParameter("namespace", "命名空间").DataType("string").Required(true)).
+		Param(restful.QueryParameter("group", "配置文件分组").DataType("string").Required(true)).
+		Param(restful.QueryParameter("name", "配置文件").DataType("string").Required(true))
+}
+
+func enrichRollbackConfigFileApiDocs(r *restful.RouteBuilder) *restful.RouteBuilder {
+	return r.
+		Doc("回滚配置文件到指定的历史发布版本").
+		Metadata(restfulspec.KeyOpenAPITags, configConsoleApiTags).
+		Param(restful.QueryParameter("namespace", "命名空间").DataType("string").Required(true)).
+		Param(restful.QueryParameter("group", "配置文件分组").DataType("string").Required(true)).
+		Param(restful.QueryParameter("name", "配置文件").DataType("string").Required(true)).
+		Param(restful.QueryParameter("historyId", "发布历史记录 ID").DataType("integer").Required(true))
+}
+
 func enrichGetConfigFileReleaseHistoryApiDocs(r *restful.RouteBuilder) *restful.RouteBuilder {
 	return r.
 		Doc("获取配置文件发布历史记录").
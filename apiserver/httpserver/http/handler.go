@@ -80,6 +80,12 @@ func (h *Handler) postParseMessage(requestID string) (context.Context, error) {
 	if authToken != "" {
 		ctx = context.WithValue(ctx, utils.ContextAuthTokenKey, authToken)
 	}
+	if labels := h.Request.HeaderParameter(utils.HeaderClientLabelsKey); labels != "" {
+		ctx = context.WithValue(ctx, utils.ContextClientLabels, labels)
+	}
+	if publicKey := h.Request.HeaderParameter(utils.HeaderConfigPublicKey); publicKey != "" {
+		ctx = context.WithValue(ctx, utils.ContextConfigPublicKey, publicKey)
+	}
 
 	var operator string
 	addrSlice := strings.Split(h.Request.Request.RemoteAddr, ":")
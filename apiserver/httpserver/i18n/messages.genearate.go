@@ -83,6 +83,7 @@ func init() {
 		api.InvalidCircuitBreakerBusiness:          {ID: fmt.Sprint(api.InvalidCircuitBreakerBusiness)},
 		api.InvalidCircuitBreakerDepartment:        {ID: fmt.Sprint(api.InvalidCircuitBreakerDepartment)},
 		api.InvalidCircuitBreakerComment:           {ID: fmt.Sprint(api.InvalidCircuitBreakerComment)},
+		api.InvalidCircuitBreakerRule:              {ID: fmt.Sprint(api.InvalidCircuitBreakerRule)},
 		api.ServicesExistedMesh:                    {ID: fmt.Sprint(api.ServicesExistedMesh)},
 		api.ResourcesExistedMesh:                   {ID: fmt.Sprint(api.ResourcesExistedMesh)},
 		api.InvalidMeshParameter:                   {ID: fmt.Sprint(api.InvalidMeshParameter)},
@@ -158,6 +159,9 @@ func init() {
 		api.InvalidRoutingPolicy:                   {ID: fmt.Sprint(api.InvalidRoutingPolicy)},
 		api.InvalidRoutingName:                     {ID: fmt.Sprint(api.InvalidRoutingName)},
 		api.InvalidRoutingPriority:                 {ID: fmt.Sprint(api.InvalidRoutingPriority)},
+		api.InvalidFaultMirrorID:                   {ID: fmt.Sprint(api.InvalidFaultMirrorID)},
+		api.InvalidFaultMirrorName:                 {ID: fmt.Sprint(api.InvalidFaultMirrorName)},
+		api.InvalidFaultMirrorRule:                 {ID: fmt.Sprint(api.InvalidFaultMirrorRule)},
 		api.InvalidConfigFileGroupName:             {ID: fmt.Sprint(api.InvalidConfigFileGroupName)},
 		api.InvalidConfigFileName:                  {ID: fmt.Sprint(api.InvalidConfigFileName)},
 		api.InvalidConfigFileContentLength:         {ID: fmt.Sprint(api.InvalidConfigFileContentLength)},
@@ -166,6 +170,8 @@ func init() {
 		api.InvalidWatchConfigFileFormat:           {ID: fmt.Sprint(api.InvalidWatchConfigFileFormat)},
 		api.NotFoundResourceConfigFile:             {ID: fmt.Sprint(api.NotFoundResourceConfigFile)},
 		api.InvalidConfigFileTemplateName:          {ID: fmt.Sprint(api.InvalidConfigFileTemplateName)},
+		api.InvalidConfigFileContent:               {ID: fmt.Sprint(api.InvalidConfigFileContent)},
+		api.InvalidConfigFileSchema:                {ID: fmt.Sprint(api.InvalidConfigFileSchema)},
 		api.Unauthorized:                           {ID: fmt.Sprint(api.Unauthorized)},
 		api.NotAllowedAccess:                       {ID: fmt.Sprint(api.NotAllowedAccess)},
 		api.EmptyAutToken:                          {ID: fmt.Sprint(api.EmptyAutToken)},
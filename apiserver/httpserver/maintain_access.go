@@ -20,8 +20,11 @@ package httpserver
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/emicklei/go-restful/v3"
 
@@ -29,6 +32,8 @@ import (
 	api "github.com/polarismesh/polaris/common/api/v1"
 	"github.com/polarismesh/polaris/common/utils"
 	"github.com/polarismesh/polaris/maintain"
+	"github.com/polarismesh/polaris/service/healthcheck"
+	"github.com/polarismesh/polaris/store/transfer"
 )
 
 // GetMaintainAccessServer 运维接口
@@ -43,8 +48,13 @@ func (h *HTTPServer) GetMaintainAccessServer() *restful.WebService {
 	ws.Route(enrichCleanInstanceApiDocs(ws.POST("/instance/clean").To(h.CleanInstance)))
 	ws.Route(enrichBatchCleanInstancesApiDocs(ws.POST("/instance/batchclean").To(h.BatchCleanInstances)))
 	ws.Route(enrichGetLastHeartbeatApiDocs(ws.GET("/instance/heartbeat").To(h.GetLastHeartbeat)))
+	ws.Route(enrichGetInstanceHealthHistoryApiDocs(
+		ws.GET("/instance/health/history").To(h.GetInstanceHealthHistory)))
 	ws.Route(enrichGetLogOutputLevelApiDocs(ws.GET("/log/outputlevel").To(h.GetLogOutputLevel)))
 	ws.Route(enrichSetLogOutputLevelApiDocs(ws.PUT("/log/outputlevel").To(h.SetLogOutputLevel)))
+	ws.Route(enrichExportBackupApiDocs(ws.GET("/backup").To(h.ExportBackup)))
+	ws.Route(enrichRestoreBackupApiDocs(ws.POST("/backup/restore").
+		Consumes(mimeGzip, restful.MIME_OCTET).To(h.RestoreBackup)))
 	return ws
 }
 
@@ -197,6 +207,30 @@ func (h *HTTPServer) GetLastHeartbeat(req *restful.Request, rsp *restful.Respons
 	handler.WriteHeaderAndProto(ret)
 }
 
+// GetInstanceHealthHistory 获取所有节点记录的实例健康状态变化
+// query参数：id，可选，查看指定实例
+//
+//	namespace、service，id为空时必须指定service
+//	limit，可选，最多返回的记录数
+func (h *HTTPServer) GetInstanceHealthHistory(req *restful.Request, rsp *restful.Response) {
+	ctx := initContext(req)
+	params := httpcommon.ParseQueryParams(req)
+	limit, _ := strconv.Atoi(params["limit"])
+	query := &healthcheck.HealthHistoryQuery{
+		InstanceId: params["id"],
+		Namespace:  params["namespace"],
+		Service:    params["service"],
+		Limit:      limit,
+	}
+
+	records, err := h.maintainServer.GetInstanceHealthHistory(ctx, query)
+	if err != nil {
+		_ = rsp.WriteError(http.StatusBadRequest, err)
+	} else {
+		_ = rsp.WriteAsJson(records)
+	}
+}
+
 // GetLogOutputLevel 获取日志输出级别
 func (h *HTTPServer) GetLogOutputLevel(req *restful.Request, rsp *restful.Response) {
 	ctx := initContext(req)
@@ -231,6 +265,77 @@ func (h *HTTPServer) SetLogOutputLevel(req *restful.Request, rsp *restful.Respon
 	_ = rsp.WriteEntity("ok")
 }
 
+const (
+	mimeGzip = "application/gzip"
+)
+
+// backupWriter 第一次写入数据时才设置附件的响应头，导出失败且尚未写入数据时仍然可以返回错误信息
+type backupWriter struct {
+	rsp     *restful.Response
+	written bool
+}
+
+func (w *backupWriter) Write(p []byte) (int, error) {
+	if !w.written {
+		w.written = true
+		w.rsp.Header().Set("Content-Type", mimeGzip)
+		w.rsp.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=polaris-backup-%s.tar.gz",
+			time.Now().Format("20060102150405")))
+	}
+	return w.rsp.Write(p)
+}
+
+// ExportBackup 导出全量数据的备份文件，备份文件为 tar.gz 归档，边编码边写出
+// query参数：format，可选，归档中数据文件的格式 json 或者 yaml，默认为 json
+func (h *HTTPServer) ExportBackup(req *restful.Request, rsp *restful.Response) {
+	ctx := initContext(req)
+	format, err := maintain.ParseBackupFormat(req.QueryParameter("format"))
+	if err != nil {
+		_ = rsp.WriteError(http.StatusBadRequest, err)
+		return
+	}
+
+	w := &backupWriter{rsp: rsp}
+	if err := h.maintainServer.ExportBackup(ctx, format, w); err != nil {
+		if !w.written {
+			_ = rsp.WriteError(http.StatusBadRequest, err)
+			return
+		}
+		// 响应已经开始写出，gzip 流没有正常结束，客户端解压时会发现备份文件不完整
+		log.Errorf("[MAINTAIN] export backup archive err: %s", err.Error())
+	}
+}
+
+// RestoreBackup 还原备份文件中的数据，请求体为导出的 tar.gz 归档，大小不能超过 maintain.MaxBackupArchiveSize
+// query参数：conflict，可选，数据已经存在且不一致时的处理策略 skip、overwrite、fail，默认为 skip
+//
+//	dry_run，可选，为 true 时只统计将要写入的记录
+func (h *HTTPServer) RestoreBackup(req *restful.Request, rsp *restful.Response) {
+	ctx := initContext(req)
+	params := httpcommon.ParseQueryParams(req)
+	conflict, err := transfer.ParseConflictPolicy(params["conflict"])
+	if err != nil {
+		_ = rsp.WriteError(http.StatusBadRequest, err)
+		return
+	}
+	dryRun, _ := strconv.ParseBool(params["dry_run"])
+
+	report, err := h.maintainServer.RestoreBackup(ctx, &maintain.RestoreBackupReq{
+		Conflict: conflict,
+		DryRun:   dryRun,
+		Archive:  http.MaxBytesReader(rsp, req.Request.Body, maintain.MaxBackupArchiveSize),
+	})
+	if errors.Is(err, transfer.ErrConflict) {
+		_ = rsp.WriteHeaderAndJson(http.StatusConflict, report, restful.MIME_JSON)
+		return
+	}
+	if err != nil {
+		_ = rsp.WriteError(http.StatusBadRequest, err)
+		return
+	}
+	_ = rsp.WriteAsJson(report)
+}
+
 func initContext(req *restful.Request) context.Context {
 	ctx := context.Background()
 
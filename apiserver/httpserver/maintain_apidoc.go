@@ -91,6 +91,17 @@ func enrichGetLastHeartbeatApiDocs(r *restful.RouteBuilder) *restful.RouteBuilde
 		Notes(enrichGetLastHeartbeatApiNotes)
 }
 
+func enrichGetInstanceHealthHistoryApiDocs(r *restful.RouteBuilder) *restful.RouteBuilder {
+	return r.
+		Doc("获取实例健康状态变化记录").
+		Metadata(restfulspec.KeyOpenAPITags, maintainApiTags).
+		Param(restful.QueryParameter("id", "实例ID 如果存在则其它参数可不填").DataType("string").Required(false)).
+		Param(restful.QueryParameter("service", "服务名").DataType("string").Required(false)).
+		Param(restful.QueryParameter("namespace", "命名空间").DataType("string").Required(false)).
+		Param(restful.QueryParameter("limit", "最多返回的记录数").DataType("integer").Required(false)).
+		Notes(enrichGetInstanceHealthHistoryApiNotes)
+}
+
 func enrichGetLogOutputLevelApiDocs(r *restful.RouteBuilder) *restful.RouteBuilder {
 	return r.
 		Doc("获取日志输出级别").
@@ -104,3 +115,21 @@ func enrichSetLogOutputLevelApiDocs(r *restful.RouteBuilder) *restful.RouteBuild
 		Metadata(restfulspec.KeyOpenAPITags, maintainApiTags).
 		Notes(enrichSetLogOutputLevelApiNotes)
 }
+
+func enrichExportBackupApiDocs(r *restful.RouteBuilder) *restful.RouteBuilder {
+	return r.
+		Doc("导出全量数据的备份文件").
+		Metadata(restfulspec.KeyOpenAPITags, maintainApiTags).
+		Param(restful.QueryParameter("format", "归档中数据文件的格式 json 或者 yaml").DataType("string").Required(false)).
+		Notes(enrichExportBackupApiNotes)
+}
+
+func enrichRestoreBackupApiDocs(r *restful.RouteBuilder) *restful.RouteBuilder {
+	return r.
+		Doc("还原备份文件中的数据").
+		Metadata(restfulspec.KeyOpenAPITags, maintainApiTags).
+		Param(restful.QueryParameter("conflict", "数据冲突时的处理策略 skip、overwrite、fail").
+			DataType("string").Required(false)).
+		Param(restful.QueryParameter("dry_run", "只统计将要写入的记录").DataType("boolean").Required(false)).
+		Notes(enrichRestoreBackupApiNotes)
+}
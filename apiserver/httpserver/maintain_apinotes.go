@@ -140,6 +140,44 @@ Header X-Polaris-Token: {访问凭据}
 | host                | string             | 实例的IP                                                          | 否       |
 | port                | string             | 实例的端口                                                        | 否       |
 | vpc_id              | string             | VPC ID                                                            | 否       |
+`
+	enrichGetInstanceHealthHistoryApiNotes = `
+请求示例：
+
+~~~
+GET /maintain/v1/instance/health/history?namespace=default&service=svc&limit=10
+Header X-Polaris-Token: {访问凭据}
+~~~
+
+请求参数：
+
+| 参数名              | 类型               | 描述                                                              | 是否必填 |
+| ------------------- | ------------------ | ----------------------------------------------------------------- | -------- |
+| id                  | string             | 实例id 如果存在id，后面参数可以不填                                 | 否       |
+| service             | string             | 服务名，id为空时必填                                              | 否       |
+| namespace           | string             | 命名空间                                                          | 否       |
+| limit               | int                | 最多返回的记录数，默认以及上限为 healthHistory.maxSize            | 否       |
+
+只返回当前节点在保留期内记录的健康状态变化，按照时间倒序排列，reason 取值为 HeartbeatTimeout、HeartbeatRecover、
+ProbeFailed、ProbeRecover、Isolate、UnIsolate。
+
+返回示例：
+~~~
+[
+ {
+  "instance_id": "ed2c4d0c7d3a4c4fb5ba6fdb1b2b6b5f",
+  "namespace": "default",
+  "service": "svc",
+  "host": "127.0.0.1",
+  "port": 8080,
+  "healthy": false,
+  "isolate": false,
+  "reason": "HeartbeatTimeout",
+  "checker": "10.0.0.1",
+  "create_time": "2022-08-01T12:00:00+08:00"
+ }
+]
+~~~
 `
 	enrichGetLogOutputLevelApiNotes = `
 请求示例：
@@ -175,5 +213,85 @@ Header X-Polaris-Token: {访问凭据}
     "scope": "apiserver",
     "level": "info"
 }
+`
+	enrichExportBackupApiNotes = `
+请求示例：
+
+~~~
+GET /maintain/v1/backup?format=yaml
+Header X-Polaris-Token: {访问凭据}
+~~~
+
+请求参数：
+
+| 参数名              | 类型               | 描述                                                              | 是否必填 |
+| ------------------- | ------------------ | ----------------------------------------------------------------- | -------- |
+| format              | string             | 归档中数据文件的格式，json 或者 yaml，默认为 json                  | 否       |
+
+备份文件包含用户密码以及各类资源的 token，只允许管理员导出。导出时在存储的一致视图上读取全部资源，数据库存储在读取期间
+会锁定业务数据表，写入请求需要等待读取完成，建议在业务低峰期导出。
+
+备份文件为 tar.gz 归档，边编码边返回，第一个文件为描述版本的 manifest，之后每类资源按照固定的记录数分块存放：
+
+~~~
+manifest.yaml
+namespaces/000000.yaml
+services/000000.yaml
+services/000001.yaml
+instances/000000.yaml
+config_files/000000.yaml
+users/000000.yaml
+~~~
+
+返回示例：
+~~~
+Header Content-Type: application/gzip
+Header Content-Disposition: attachment; filename=polaris-backup-20220801120000.tar.gz
+
+{tar.gz 格式的备份文件}
+~~~
+`
+	enrichRestoreBackupApiNotes = `
+请求示例：
+
+~~~
+POST /maintain/v1/backup/restore?conflict=overwrite&dry_run=true
+Header X-Polaris-Token: {访问凭据}
+Header Content-Type: application/gzip
+
+{导出的 tar.gz 备份文件}
+~~~
+
+请求参数：
+
+| 参数名              | 类型               | 描述                                                              | 是否必填 |
+| ------------------- | ------------------ | ----------------------------------------------------------------- | -------- |
+| conflict            | string             | 数据已经存在且不一致时的处理策略，skip 跳过，overwrite 覆盖，fail 存在冲突时不写入任何数据，默认为 skip | 否       |
+| dry_run             | bool               | 为 true 时只统计将要写入的记录，不实际写入                        | 否       |
+
+只允许管理员还原。请求体大小不能超过 512MB，解压后的数据不能超过 2GB，数据文件的格式根据文件扩展名判断。conflict 为 fail 且存在冲突时返回 409 以及冲突的统计结果，冲突的记录见 errors。
+
+返回示例：
+~~~
+{
+ "dry_run": false,
+ "kinds": [
+  {
+   "kind": "service",
+   "total": 2,
+   "created": 1,
+   "skipped": 1,
+   "conflicted": 1,
+   "overwritten": 0,
+   "failed": 0,
+   "missing": 0,
+   "mismatched": 0
+  }
+ ],
+ "warnings": [
+  "service(svc-1) conflicts with target, skipped: revision expect a but b"
+ ]
+}
+~~~
 `
 )
@@ -90,6 +90,10 @@ func (h *HTTPServerV2) Discover(req *restful.Request, rsp *restful.Response) {
 	switch discoverRequest.Type {
 	case apiv2.DiscoverRequest_ROUTING:
 		ret = h.namingServer.GetRoutingConfigV2WithCache(ctx, discoverRequest.GetSerivce())
+	case apiv2.DiscoverRequest_CIRCUIT_BREAKER:
+		ret = h.namingServer.GetCircuitBreakerV2WithCache(ctx, discoverRequest.GetSerivce())
+	case apiv2.DiscoverRequest_FAULT_MIRROR:
+		ret = h.namingServer.GetFaultMirrorWithCache(ctx, discoverRequest.GetSerivce())
 	default:
 		ret = apiv2.NewDiscoverRoutingResponse(api.InvalidDiscoverResource, discoverRequest.GetSerivce())
 	}
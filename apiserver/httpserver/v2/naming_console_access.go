@@ -73,6 +73,8 @@ func (h *HTTPServerV2) GetNamingConsoleAccessServer(include []string) (*restful.
 func (h *HTTPServerV2) addDefaultReadAccess(ws *restful.WebService) {
 	ws.Route(enrichCreateRoutingsApiDocs(ws.POST("/routings").To(h.CreateRoutings)))
 	ws.Route(enrichGetRoutingsApiDocs(ws.GET("/routings").To(h.GetRoutings)))
+	ws.Route(enrichGetCircuitBreakersApiDocs(ws.GET("/circuitbreakers").To(h.GetCircuitBreakers)))
+	ws.Route(enrichGetFaultMirrorsApiDocs(ws.GET("/faultmirrors").To(h.GetFaultMirrors)))
 }
 
 // addDefaultAccess 增加默认接口
@@ -82,6 +84,18 @@ func (h *HTTPServerV2) addDefaultAccess(ws *restful.WebService) {
 	ws.Route(enrichUpdateRoutingsApiDocs(ws.PUT("/routings").To(h.UpdateRoutings)))
 	ws.Route(enrichGetRoutingsApiDocs(ws.GET("/routings").To(h.GetRoutings)))
 	ws.Route(enrichEnableRoutingsApiDocs(ws.PUT("/routings/enable").To(h.EnableRoutings)))
+
+	ws.Route(enrichCreateCircuitBreakersApiDocs(ws.POST("/circuitbreakers").To(h.CreateCircuitBreakers)))
+	ws.Route(enrichDeleteCircuitBreakersApiDocs(ws.POST("/circuitbreakers/delete").To(h.DeleteCircuitBreakers)))
+	ws.Route(enrichUpdateCircuitBreakersApiDocs(ws.PUT("/circuitbreakers").To(h.UpdateCircuitBreakers)))
+	ws.Route(enrichGetCircuitBreakersApiDocs(ws.GET("/circuitbreakers").To(h.GetCircuitBreakers)))
+	ws.Route(enrichEnableCircuitBreakersApiDocs(ws.PUT("/circuitbreakers/enable").To(h.EnableCircuitBreakers)))
+
+	ws.Route(enrichCreateFaultMirrorsApiDocs(ws.POST("/faultmirrors").To(h.CreateFaultMirrors)))
+	ws.Route(enrichDeleteFaultMirrorsApiDocs(ws.POST("/faultmirrors/delete").To(h.DeleteFaultMirrors)))
+	ws.Route(enrichUpdateFaultMirrorsApiDocs(ws.PUT("/faultmirrors").To(h.UpdateFaultMirrors)))
+	ws.Route(enrichGetFaultMirrorsApiDocs(ws.GET("/faultmirrors").To(h.GetFaultMirrors)))
+	ws.Route(enrichEnableFaultMirrorsApiDocs(ws.PUT("/faultmirrors/enable").To(h.EnableFaultMirrors)))
 }
 
 // CreateRoutings 创建规则路由
@@ -183,3 +197,203 @@ func (h *HTTPServerV2) EnableRoutings(req *restful.Request, rsp *restful.Respons
 	ret := h.namingServer.EnableRoutings(ctx, routings)
 	handler.WriteHeaderAndProtoV2(ret)
 }
+
+// CreateCircuitBreakers 创建熔断规则
+func (h *HTTPServerV2) CreateCircuitBreakers(req *restful.Request, rsp *restful.Response) {
+	handler := &httpcommon.Handler{
+		Request:  req,
+		Response: rsp,
+	}
+
+	var rules CircuitBreakerArr
+	ctx, err := handler.ParseArray(func() proto.Message {
+		msg := &apiv2.CircuitBreaker{}
+		rules = append(rules, msg)
+		return msg
+	})
+	if err != nil {
+		handler.WriteHeaderAndProtoV2(apiv2.NewBatchWriteResponseWithMsg(apiv1.ParseException, err.Error()))
+		return
+	}
+
+	ret := h.namingServer.CreateCircuitBreakersV2(ctx, rules)
+	handler.WriteHeaderAndProtoV2(ret)
+}
+
+// DeleteCircuitBreakers 删除熔断规则
+func (h *HTTPServerV2) DeleteCircuitBreakers(req *restful.Request, rsp *restful.Response) {
+	handler := &httpcommon.Handler{
+		Request:  req,
+		Response: rsp,
+	}
+
+	var rules CircuitBreakerArr
+	ctx, err := handler.ParseArray(func() proto.Message {
+		msg := &apiv2.CircuitBreaker{}
+		rules = append(rules, msg)
+		return msg
+	})
+	if err != nil {
+		handler.WriteHeaderAndProtoV2(apiv2.NewBatchWriteResponseWithMsg(apiv1.ParseException, err.Error()))
+		return
+	}
+
+	ret := h.namingServer.DeleteCircuitBreakersV2(ctx, rules)
+	handler.WriteHeaderAndProtoV2(ret)
+}
+
+// UpdateCircuitBreakers 修改熔断规则
+func (h *HTTPServerV2) UpdateCircuitBreakers(req *restful.Request, rsp *restful.Response) {
+	handler := &httpcommon.Handler{
+		Request:  req,
+		Response: rsp,
+	}
+
+	var rules CircuitBreakerArr
+	ctx, err := handler.ParseArray(func() proto.Message {
+		msg := &apiv2.CircuitBreaker{}
+		rules = append(rules, msg)
+		return msg
+	})
+	if err != nil {
+		handler.WriteHeaderAndProtoV2(apiv2.NewBatchWriteResponseWithMsg(apiv1.ParseException, err.Error()))
+		return
+	}
+
+	ret := h.namingServer.UpdateCircuitBreakersV2(ctx, rules)
+	handler.WriteHeaderAndProtoV2(ret)
+}
+
+// GetCircuitBreakers 查询熔断规则
+func (h *HTTPServerV2) GetCircuitBreakers(req *restful.Request, rsp *restful.Response) {
+	handler := &httpcommon.Handler{
+		Request:  req,
+		Response: rsp,
+	}
+
+	queryParams := httpcommon.ParseQueryParams(req)
+	ret := h.namingServer.GetCircuitBreakersV2(handler.ParseHeaderContext(), queryParams)
+	handler.WriteHeaderAndProtoV2(ret)
+}
+
+// EnableCircuitBreakers 启用或禁用熔断规则
+func (h *HTTPServerV2) EnableCircuitBreakers(req *restful.Request, rsp *restful.Response) {
+	handler := &httpcommon.Handler{
+		Request:  req,
+		Response: rsp,
+	}
+
+	var rules CircuitBreakerArr
+	ctx, err := handler.ParseArray(func() proto.Message {
+		msg := &apiv2.CircuitBreaker{}
+		rules = append(rules, msg)
+		return msg
+	})
+	if err != nil {
+		handler.WriteHeaderAndProtoV2(apiv2.NewBatchWriteResponseWithMsg(apiv1.ParseException, err.Error()))
+		return
+	}
+
+	ret := h.namingServer.EnableCircuitBreakersV2(ctx, rules)
+	handler.WriteHeaderAndProtoV2(ret)
+}
+
+// CreateFaultMirrors 创建故障注入与流量镜像规则
+func (h *HTTPServerV2) CreateFaultMirrors(req *restful.Request, rsp *restful.Response) {
+	handler := &httpcommon.Handler{
+		Request:  req,
+		Response: rsp,
+	}
+
+	var rules FaultMirrorRuleArr
+	ctx, err := handler.ParseArray(func() proto.Message {
+		msg := &apiv2.FaultMirrorRule{}
+		rules = append(rules, msg)
+		return msg
+	})
+	if err != nil {
+		handler.WriteHeaderAndProtoV2(apiv2.NewBatchWriteResponseWithMsg(apiv1.ParseException, err.Error()))
+		return
+	}
+
+	ret := h.namingServer.CreateFaultMirrorRules(ctx, rules)
+	handler.WriteHeaderAndProtoV2(ret)
+}
+
+// DeleteFaultMirrors 删除故障注入与流量镜像规则
+func (h *HTTPServerV2) DeleteFaultMirrors(req *restful.Request, rsp *restful.Response) {
+	handler := &httpcommon.Handler{
+		Request:  req,
+		Response: rsp,
+	}
+
+	var rules FaultMirrorRuleArr
+	ctx, err := handler.ParseArray(func() proto.Message {
+		msg := &apiv2.FaultMirrorRule{}
+		rules = append(rules, msg)
+		return msg
+	})
+	if err != nil {
+		handler.WriteHeaderAndProtoV2(apiv2.NewBatchWriteResponseWithMsg(apiv1.ParseException, err.Error()))
+		return
+	}
+
+	ret := h.namingServer.DeleteFaultMirrorRules(ctx, rules)
+	handler.WriteHeaderAndProtoV2(ret)
+}
+
+// UpdateFaultMirrors 修改故障注入与流量镜像规则
+func (h *HTTPServerV2) UpdateFaultMirrors(req *restful.Request, rsp *restful.Response) {
+	handler := &httpcommon.Handler{
+		Request:  req,
+		Response: rsp,
+	}
+
+	var rules FaultMirrorRuleArr
+	ctx, err := handler.ParseArray(func() proto.Message {
+		msg := &apiv2.FaultMirrorRule{}
+		rules = append(rules, msg)
+		return msg
+	})
+	if err != nil {
+		handler.WriteHeaderAndProtoV2(apiv2.NewBatchWriteResponseWithMsg(apiv1.ParseException, err.Error()))
+		return
+	}
+
+	ret := h.namingServer.UpdateFaultMirrorRules(ctx, rules)
+	handler.WriteHeaderAndProtoV2(ret)
+}
+
+// GetFaultMirrors 查询故障注入与流量镜像规则
+func (h *HTTPServerV2) GetFaultMirrors(req *restful.Request, rsp *restful.Response) {
+	handler := &httpcommon.Handler{
+		Request:  req,
+		Response: rsp,
+	}
+
+	queryParams := httpcommon.ParseQueryParams(req)
+	ret := h.namingServer.GetFaultMirrorRules(handler.ParseHeaderContext(), queryParams)
+	handler.WriteHeaderAndProtoV2(ret)
+}
+
+// EnableFaultMirrors 启用或禁用故障注入与流量镜像规则
+func (h *HTTPServerV2) EnableFaultMirrors(req *restful.Request, rsp *restful.Response) {
+	handler := &httpcommon.Handler{
+		Request:  req,
+		Response: rsp,
+	}
+
+	var rules FaultMirrorRuleArr
+	ctx, err := handler.ParseArray(func() proto.Message {
+		msg := &apiv2.FaultMirrorRule{}
+		rules = append(rules, msg)
+		return msg
+	})
+	if err != nil {
+		handler.WriteHeaderAndProtoV2(apiv2.NewBatchWriteResponseWithMsg(apiv1.ParseException, err.Error()))
+		return
+	}
+
+	ret := h.namingServer.EnableFaultMirrorRules(ctx, rules)
+	handler.WriteHeaderAndProtoV2(ret)
+}
@@ -25,7 +25,9 @@ import (
 )
 
 var (
-	routingRulesApiTags = []string{"RoutingRules"}
+	routingRulesApiTags        = []string{"RoutingRules"}
+	circuitBreakerRulesApiTags = []string{"CircuitBreakerRules"}
+	faultMirrorRulesApiTags    = []string{"FaultMirrorRules"}
 )
 
 func enrichCreateRoutingsApiDocs(r *restful.RouteBuilder) *restful.RouteBuilder {
@@ -64,3 +66,77 @@ func enrichEnableRoutingsApiDocs(r *restful.RouteBuilder) *restful.RouteBuilder
 		Operation("v2EnableRoutings").
 		Notes(enrichEnableRoutingsApiNotes)
 }
+
+func enrichCreateCircuitBreakersApiDocs(r *restful.RouteBuilder) *restful.RouteBuilder {
+	return r.Doc("创建熔断规则").
+		Metadata(restfulspec.KeyOpenAPITags, circuitBreakerRulesApiTags).
+		Operation("v2CreateCircuitBreakers").
+		Reads([]apiv2.CircuitBreaker{}).
+		Notes(enrichCreateCircuitBreakersApiNotes)
+}
+
+func enrichDeleteCircuitBreakersApiDocs(r *restful.RouteBuilder) *restful.RouteBuilder {
+	return r.Doc("删除熔断规则").
+		Metadata(restfulspec.KeyOpenAPITags, circuitBreakerRulesApiTags).
+		Operation("v2DeleteCircuitBreakers").
+		Notes(enrichDeleteCircuitBreakersApiNotes)
+}
+
+func enrichUpdateCircuitBreakersApiDocs(r *restful.RouteBuilder) *restful.RouteBuilder {
+	return r.Doc("更新熔断规则").
+		Metadata(restfulspec.KeyOpenAPITags, circuitBreakerRulesApiTags).
+		Operation("v2UpdateCircuitBreakers").
+		Reads([]apiv2.CircuitBreaker{}).
+		Notes(enrichUpdateCircuitBreakersApiNotes)
+}
+
+func enrichGetCircuitBreakersApiDocs(r *restful.RouteBuilder) *restful.RouteBuilder {
+	return r.Doc("获取熔断规则").
+		Metadata(restfulspec.KeyOpenAPITags, circuitBreakerRulesApiTags).
+		Operation("v2GetCircuitBreakers").
+		Notes(enrichGetCircuitBreakersApiNotes)
+}
+
+func enrichEnableCircuitBreakersApiDocs(r *restful.RouteBuilder) *restful.RouteBuilder {
+	return r.Doc("启用或禁用熔断规则").
+		Metadata(restfulspec.KeyOpenAPITags, circuitBreakerRulesApiTags).
+		Operation("v2EnableCircuitBreakers").
+		Notes(enrichEnableCircuitBreakersApiNotes)
+}
+
+func enrichCreateFaultMirrorsApiDocs(r *restful.RouteBuilder) *restful.RouteBuilder {
+	return r.Doc("创建故障注入与流量镜像规则").
+		Metadata(restfulspec.KeyOpenAPITags, faultMirrorRulesApiTags).
+		Operation("v2CreateFaultMirrors").
+		Reads([]apiv2.FaultMirrorRule{}).
+		Notes(enrichCreateFaultMirrorsApiNotes)
+}
+
+func enrichDeleteFaultMirrorsApiDocs(r *restful.RouteBuilder) *restful.RouteBuilder {
+	return r.Doc("删除故障注入与流量镜像规则").
+		Metadata(restfulspec.KeyOpenAPITags, faultMirrorRulesApiTags).
+		Operation("v2DeleteFaultMirrors").
+		Notes(enrichDeleteFaultMirrorsApiNotes)
+}
+
+func enrichUpdateFaultMirrorsApiDocs(r *restful.RouteBuilder) *restful.RouteBuilder {
+	return r.Doc("更新故障注入与流量镜像规则").
+		Metadata(restfulspec.KeyOpenAPITags, faultMirrorRulesApiTags).
+		Operation("v2UpdateFaultMirrors").
+		Reads([]apiv2.FaultMirrorRule{}).
+		Notes(enrichUpdateFaultMirrorsApiNotes)
+}
+
+func enrichGetFaultMirrorsApiDocs(r *restful.RouteBuilder) *restful.RouteBuilder {
+	return r.Doc("获取故障注入与流量镜像规则").
+		Metadata(restfulspec.KeyOpenAPITags, faultMirrorRulesApiTags).
+		Operation("v2GetFaultMirrors").
+		Notes(enrichGetFaultMirrorsApiNotes)
+}
+
+func enrichEnableFaultMirrorsApiDocs(r *restful.RouteBuilder) *restful.RouteBuilder {
+	return r.Doc("启用或禁用故障注入与流量镜像规则").
+		Metadata(restfulspec.KeyOpenAPITags, faultMirrorRulesApiTags).
+		Operation("v2EnableFaultMirrors").
+		Notes(enrichEnableFaultMirrorsApiNotes)
+}
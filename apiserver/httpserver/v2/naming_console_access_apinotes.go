@@ -71,6 +71,116 @@ PUT /naming/v2/routings
 # 开启北极星服务端针对控制台接口鉴权开关后，需要添加下面的 header
 Header X-Polaris-Token: {访问凭据}
 
+~~~
+`
+	enrichCreateCircuitBreakersApiNotes = `
+创建熔断规则
+
+~~~
+POST /naming/v2/circuitbreakers
+
+# 开启北极星服务端针对控制台接口鉴权开关后，需要添加下面的 header
+Header X-Polaris-Token: {访问凭据}
+
+~~~
+`
+	enrichDeleteCircuitBreakersApiNotes = `
+删除熔断规则
+
+~~~
+POST /naming/v2/circuitbreakers/delete
+
+# 开启北极星服务端针对控制台接口鉴权开关后，需要添加下面的 header
+Header X-Polaris-Token: {访问凭据}
+
+~~~
+`
+	enrichUpdateCircuitBreakersApiNotes = `
+更新熔断规则
+
+~~~
+PUT /naming/v2/circuitbreakers
+
+# 开启北极星服务端针对控制台接口鉴权开关后，需要添加下面的 header
+Header X-Polaris-Token: {访问凭据}
+
+~~~
+`
+	enrichGetCircuitBreakersApiNotes = `
+获取熔断规则
+
+~~~
+GET /naming/v2/circuitbreakers
+
+# 开启北极星服务端针对控制台接口鉴权开关后，需要添加下面的 header
+Header X-Polaris-Token: {访问凭据}
+
+~~~
+`
+	enrichEnableCircuitBreakersApiNotes = `
+启用或禁用熔断规则
+
+~~~
+PUT /naming/v2/circuitbreakers/enable
+
+# 开启北极星服务端针对控制台接口鉴权开关后，需要添加下面的 header
+Header X-Polaris-Token: {访问凭据}
+
+~~~
+`
+	enrichCreateFaultMirrorsApiNotes = `
+创建故障注入与流量镜像规则
+
+~~~
+POST /naming/v2/faultmirrors
+
+# 开启北极星服务端针对控制台接口鉴权开关后，需要添加下面的 header
+Header X-Polaris-Token: {访问凭据}
+
+~~~
+`
+	enrichDeleteFaultMirrorsApiNotes = `
+删除故障注入与流量镜像规则
+
+~~~
+POST /naming/v2/faultmirrors/delete
+
+# 开启北极星服务端针对控制台接口鉴权开关后，需要添加下面的 header
+Header X-Polaris-Token: {访问凭据}
+
+~~~
+`
+	enrichUpdateFaultMirrorsApiNotes = `
+更新故障注入与流量镜像规则
+
+~~~
+PUT /naming/v2/faultmirrors
+
+# 开启北极星服务端针对控制台接口鉴权开关后，需要添加下面的 header
+Header X-Polaris-Token: {访问凭据}
+
+~~~
+`
+	enrichGetFaultMirrorsApiNotes = `
+获取故障注入与流量镜像规则
+
+~~~
+GET /naming/v2/faultmirrors
+
+# 开启北极星服务端针对控制台接口鉴权开关后，需要添加下面的 header
+Header X-Polaris-Token: {访问凭据}
+
+~~~
+`
+	enrichEnableFaultMirrorsApiNotes = `
+启用或禁用故障注入与流量镜像规则
+
+~~~
+PUT /naming/v2/faultmirrors/enable
+
+# 开启北极星服务端针对控制台接口鉴权开关后，需要添加下面的 header
+Header X-Polaris-Token: {访问凭据}
+
 ~~~
 `
 )
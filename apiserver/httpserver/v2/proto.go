@@ -34,3 +34,27 @@ func (m *RoutingArr) String() string { return proto.CompactTextString(m) }
 
 // ProtoMessage return proto message
 func (*RoutingArr) ProtoMessage() {}
+
+// CircuitBreakerArr 熔断规则数组定义
+type CircuitBreakerArr []*api.CircuitBreaker
+
+// Reset reset initialization
+func (m *CircuitBreakerArr) Reset() { *m = CircuitBreakerArr{} }
+
+// String return string
+func (m *CircuitBreakerArr) String() string { return proto.CompactTextString(m) }
+
+// ProtoMessage return proto message
+func (*CircuitBreakerArr) ProtoMessage() {}
+
+// FaultMirrorRuleArr 故障注入与流量镜像规则数组定义
+type FaultMirrorRuleArr []*api.FaultMirrorRule
+
+// Reset reset initialization
+func (m *FaultMirrorRuleArr) Reset() { *m = FaultMirrorRuleArr{} }
+
+// String return string
+func (m *FaultMirrorRuleArr) String() string { return proto.CompactTextString(m) }
+
+// ProtoMessage return proto message
+func (*FaultMirrorRuleArr) ProtoMessage() {}
@@ -0,0 +1,40 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package nacosserver
+
+import "time"
+
+const (
+	optionListenIP         = "listenIP"
+	optionListenPort       = "listenPort"
+	optionGrpcPort         = "grpcPort"
+	optionDefaultNamespace = "defaultNamespace"
+)
+
+const (
+	// DefaultGrpcPortOffset nacos 2.x 客户端默认使用 http 端口 + 1000 作为 grpc 端口
+	DefaultGrpcPortOffset = 1000
+	// DefaultHeartbeatInterval nacos 客户端默认的心跳间隔
+	DefaultHeartbeatInterval = 5 * time.Second
+	// DefaultLongPollingTimeout nacos 配置长轮询默认的超时时间
+	DefaultLongPollingTimeout = 30 * time.Second
+	// DefaultCacheMillis nacos 客户端缓存服务信息的时长，单位毫秒
+	DefaultCacheMillis = 3000
+	// DefaultPushQueueSize 每个长连接待推送消息的队列长度，队列满时丢弃推送
+	DefaultPushQueueSize = 256
+)
@@ -0,0 +1,149 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package nacosserver
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strings"
+
+	api "github.com/polarismesh/polaris/common/api/v1"
+	"github.com/polarismesh/polaris/common/utils"
+)
+
+const (
+	// nacos 1.x 客户端监听配置时使用的分隔符
+	wordSeparator = "\x02"
+	lineSeparator = "\x01"
+)
+
+// toPolarisConfigFile nacos 的 tenant、group、dataId 分别映射为北极星的命名空间、配置分组和配置文件名
+func (n *NacosServer) toPolarisConfigFile(key *ConfigKey) (string, string, string) {
+	group := key.Group
+	if len(group) == 0 {
+		group = DefaultGroup
+	}
+	return n.toPolarisNamespace(key.Tenant), group, key.DataId
+}
+
+// getConfig 获取已经发布的配置，配置不存在时返回 nil
+func (n *NacosServer) getConfig(ctx context.Context, key *ConfigKey) (*api.ClientConfigFileInfo, uint32) {
+	namespace, group, fileName := n.toPolarisConfigFile(key)
+	resp := n.configServer.GetConfigFileForClient(ctx, &api.ClientConfigFileInfo{
+		Namespace: utils.NewStringValue(namespace),
+		Group:     utils.NewStringValue(group),
+		FileName:  utils.NewStringValue(fileName),
+	})
+	code := resp.GetCode().GetValue()
+	if code != api.ExecuteSuccess {
+		return nil, code
+	}
+	return resp.GetConfigFile(), code
+}
+
+// publishConfig 创建或者更新配置文件，然后发布
+func (n *NacosServer) publishConfig(ctx context.Context, key *ConfigKey, content string,
+	format string) *api.ConfigResponse {
+	if len(format) > 0 && !utils.IsValidFileFormat(format) {
+		format = utils.FileFormatText
+	}
+	namespace, group, fileName := n.toPolarisConfigFile(key)
+	configFile := &api.ConfigFile{
+		Namespace: utils.NewStringValue(namespace),
+		Group:     utils.NewStringValue(group),
+		Name:      utils.NewStringValue(fileName),
+		Content:   utils.NewStringValue(content),
+		Format:    utils.NewStringValue(format),
+	}
+	resp := n.configServer.CreateConfigFile(ctx, configFile)
+	if resp.GetCode().GetValue() == api.ExistedResource {
+		resp = n.configServer.UpdateConfigFile(ctx, configFile)
+	}
+	if resp.GetCode().GetValue() != api.ExecuteSuccess {
+		return resp
+	}
+	return n.configServer.PublishConfigFile(ctx, &api.ConfigFileRelease{
+		Namespace: utils.NewStringValue(namespace),
+		Group:     utils.NewStringValue(group),
+		FileName:  utils.NewStringValue(fileName),
+	})
+}
+
+// removeConfig 删除配置文件及其发布内容
+func (n *NacosServer) removeConfig(ctx context.Context, key *ConfigKey) *api.ConfigResponse {
+	namespace, group, fileName := n.toPolarisConfigFile(key)
+	resp := n.configServer.DeleteConfigFile(ctx, namespace, group, fileName, utils.ParseUserName(ctx))
+	if resp.GetCode().GetValue() == api.ExecuteSuccess {
+		// 删除配置不会产生发布事件，直接通知本节点的监听者
+		n.configWatcher.notifyLocal(namespace, group, fileName)
+	}
+	return resp
+}
+
+// changedConfigs 返回服务端 md5 和客户端不一致的配置
+func (n *NacosServer) changedConfigs(ctx context.Context, listens []*ConfigListenContext) []*ConfigKey {
+	changed := make([]*ConfigKey, 0, len(listens))
+	for _, listen := range listens {
+		configFile, _ := n.getConfig(ctx, &listen.ConfigKey)
+		if configFile.GetMd5().GetValue() != listen.Md5 {
+			key := listen.ConfigKey
+			changed = append(changed, &key)
+		}
+	}
+	return changed
+}
+
+// parseListeningConfigs 解析 nacos 1.x 客户端监听的配置，格式为 dataId^2group^2md5[^2tenant]^1
+func parseListeningConfigs(probe string) ([]*ConfigListenContext, error) {
+	listens := make([]*ConfigListenContext, 0, 4)
+	for _, line := range strings.Split(probe, lineSeparator) {
+		if len(line) == 0 {
+			continue
+		}
+		words := strings.Split(line, wordSeparator)
+		if len(words) < 3 || len(words) > 4 {
+			return nil, errors.New("invalid listening configs")
+		}
+		listen := &ConfigListenContext{
+			ConfigKey: ConfigKey{DataId: words[0], Group: words[1]},
+			Md5:       words[2],
+		}
+		if len(words) == 4 {
+			listen.Tenant = words[3]
+		}
+		listens = append(listens, listen)
+	}
+	return listens, nil
+}
+
+// encodeChangedConfigs 按照 nacos 1.x 的格式编码发生变化的配置，格式为 dataId^2group[^2tenant]^1
+func encodeChangedConfigs(keys []*ConfigKey) string {
+	builder := strings.Builder{}
+	for _, key := range keys {
+		builder.WriteString(key.DataId)
+		builder.WriteString(wordSeparator)
+		builder.WriteString(key.Group)
+		if len(key.Tenant) > 0 {
+			builder.WriteString(wordSeparator)
+			builder.WriteString(key.Tenant)
+		}
+		builder.WriteString(lineSeparator)
+	}
+	return url.QueryEscape(builder.String())
+}
@@ -0,0 +1,74 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package nacosserver
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseListeningConfigs(t *testing.T) {
+	probe := "app.yaml\x02DEFAULT_GROUP\x02d41d8cd98f00b204e9800998ecf8427e\x01" +
+		"db.properties\x02infra\x02\x02dev\x01"
+	listens, err := parseListeningConfigs(probe)
+	assert.NoError(t, err)
+	assert.Len(t, listens, 2)
+	assert.Equal(t, "app.yaml", listens[0].DataId)
+	assert.Equal(t, "DEFAULT_GROUP", listens[0].Group)
+	assert.Equal(t, "d41d8cd98f00b204e9800998ecf8427e", listens[0].Md5)
+	assert.Empty(t, listens[0].Tenant)
+	assert.Equal(t, "dev", listens[1].Tenant)
+	assert.Empty(t, listens[1].Md5)
+
+	_, err = parseListeningConfigs("app.yaml\x01")
+	assert.Error(t, err)
+}
+
+func TestEncodeChangedConfigs(t *testing.T) {
+	encoded := encodeChangedConfigs([]*ConfigKey{
+		{DataId: "app.yaml", Group: "DEFAULT_GROUP"},
+		{DataId: "db.properties", Group: "infra", Tenant: "dev"},
+	})
+	decoded, err := url.QueryUnescape(encoded)
+	assert.NoError(t, err)
+	assert.Equal(t, "app.yaml\x02DEFAULT_GROUP\x01db.properties\x02infra\x02dev\x01", decoded)
+	assert.Empty(t, encodeChangedConfigs(nil))
+}
+
+func TestConfigWatcher(t *testing.T) {
+	watcher := NewConfigWatcher(nil)
+	notified := make(map[string]int)
+	watcher.Watch("c1", "default", "DEFAULT_GROUP", "app.yaml", func(string, string, string) {
+		notified["c1"]++
+	})
+	watcher.Watch("c2", "default", "DEFAULT_GROUP", "app.yaml", func(string, string, string) {
+		notified["c2"]++
+	})
+	watcher.notifyLocal("default", "DEFAULT_GROUP", "app.yaml")
+	assert.Equal(t, map[string]int{"c1": 1, "c2": 1}, notified)
+
+	watcher.Unwatch("c1", "default", "DEFAULT_GROUP", "app.yaml")
+	watcher.notifyLocal("default", "DEFAULT_GROUP", "app.yaml")
+	watcher.notifyLocal("default", "DEFAULT_GROUP", "other.yaml")
+	assert.Equal(t, map[string]int{"c1": 1, "c2": 2}, notified)
+
+	watcher.Unwatch("c2", "default", "DEFAULT_GROUP", "app.yaml")
+	assert.Empty(t, watcher.watchers)
+}
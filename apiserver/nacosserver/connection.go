@@ -0,0 +1,262 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package nacosserver
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/stats"
+	"google.golang.org/protobuf/types/known/anypb"
+
+	api "github.com/polarismesh/polaris/common/api/v1"
+	"github.com/polarismesh/polaris/common/model"
+)
+
+type connectionIDKey struct{}
+
+// connectionID 获取请求所在的长连接 id
+func connectionID(ctx context.Context) string {
+	id, _ := ctx.Value(connectionIDKey{}).(string)
+	return id
+}
+
+// connInstance 通过长连接注册的临时实例
+type connInstance struct {
+	instance *api.Instance
+	token    string
+}
+
+// connListen 长连接监听的配置
+type connListen struct {
+	namespace string
+	group     string
+	fileName  string
+	key       ConfigKey
+}
+
+// Connection nacos 2.x 客户端的长连接，连接断开时清理通过它注册的实例、订阅的服务以及监听的配置
+type Connection struct {
+	ID            string
+	RemoteAddress string
+	sendCh        chan *Payload
+	lock          sync.Mutex
+	clientVersion string
+	instances     map[string]*connInstance
+	subscribes    map[model.ServiceKey]struct{}
+	listens       map[string]*connListen
+}
+
+func newConnection(id string, remoteAddress string) *Connection {
+	return &Connection{
+		ID:            id,
+		RemoteAddress: remoteAddress,
+		sendCh:        make(chan *Payload, DefaultPushQueueSize),
+		instances:     make(map[string]*connInstance),
+		subscribes:    make(map[model.ServiceKey]struct{}),
+		listens:       make(map[string]*connListen),
+	}
+}
+
+// Push 通过双向流推送请求给客户端，队列满时丢弃
+func (c *Connection) Push(requestType string, request interface{}) {
+	payload, err := newPayload(requestType, request)
+	if err != nil {
+		log.Errorf("[NACOS-SERVER]fail to marshal %s, conn: %s, err: %v", requestType, c.ID, err)
+		return
+	}
+	select {
+	case c.sendCh <- payload:
+	default:
+		log.Warnf("[NACOS-SERVER]push queue is full, drop %s, conn: %s", requestType, c.ID)
+	}
+}
+
+func (c *Connection) setClientVersion(clientVersion string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.clientVersion = clientVersion
+}
+
+func (c *Connection) getClientVersion() string {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.clientVersion
+}
+
+func (c *Connection) addInstance(id string, instance *api.Instance, token string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.instances[id] = &connInstance{instance: instance, token: token}
+}
+
+func (c *Connection) removeInstance(id string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	delete(c.instances, id)
+}
+
+func (c *Connection) listInstances() []*connInstance {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	instances := make([]*connInstance, 0, len(c.instances))
+	for _, instance := range c.instances {
+		instances = append(instances, instance)
+	}
+	return instances
+}
+
+func (c *Connection) addSubscribe(key model.ServiceKey) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.subscribes[key] = struct{}{}
+}
+
+func (c *Connection) removeSubscribe(key model.ServiceKey) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	delete(c.subscribes, key)
+}
+
+func (c *Connection) listSubscribes() []model.ServiceKey {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	keys := make([]model.ServiceKey, 0, len(c.subscribes))
+	for key := range c.subscribes {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+func (c *Connection) addListen(fileId string, listen *connListen) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.listens[fileId] = listen
+}
+
+func (c *Connection) removeListen(fileId string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	delete(c.listens, fileId)
+}
+
+func (c *Connection) getListen(fileId string) (*connListen, bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	listen, ok := c.listens[fileId]
+	return listen, ok
+}
+
+func (c *Connection) listListens() []*connListen {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	listens := make([]*connListen, 0, len(c.listens))
+	for _, listen := range c.listens {
+		listens = append(listens, listen)
+	}
+	return listens
+}
+
+// ConnectionManager 通过 grpc 的 stats.Handler 感知连接的建立和断开，管理 nacos 2.x 客户端的长连接
+type ConnectionManager struct {
+	lock        sync.RWMutex
+	connections map[string]*Connection
+	onClose     func(conn *Connection)
+}
+
+// NewConnectionManager 构造函数，onClose 在连接断开后调用
+func NewConnectionManager(onClose func(conn *Connection)) *ConnectionManager {
+	return &ConnectionManager{
+		connections: make(map[string]*Connection),
+		onClose:     onClose,
+	}
+}
+
+// Get 获取长连接
+func (m *ConnectionManager) Get(id string) *Connection {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	return m.connections[id]
+}
+
+// Range 遍历所有的长连接
+func (m *ConnectionManager) Range(proc func(conn *Connection)) {
+	m.lock.RLock()
+	connections := make([]*Connection, 0, len(m.connections))
+	for _, conn := range m.connections {
+		connections = append(connections, conn)
+	}
+	m.lock.RUnlock()
+
+	for _, conn := range connections {
+		proc(conn)
+	}
+}
+
+// TagConn 连接建立时生成连接 id，格式与 nacos 一致：时间戳_ip_端口
+func (m *ConnectionManager) TagConn(ctx context.Context, info *stats.ConnTagInfo) context.Context {
+	remoteAddress := info.RemoteAddr.String()
+	id := fmt.Sprintf("%d_%s", time.Now().UnixNano()/int64(time.Millisecond), remoteAddress)
+	if host, port, err := net.SplitHostPort(remoteAddress); err == nil {
+		id = fmt.Sprintf("%d_%s_%s", time.Now().UnixNano()/int64(time.Millisecond), host, port)
+	}
+
+	m.lock.Lock()
+	m.connections[id] = newConnection(id, remoteAddress)
+	m.lock.Unlock()
+	return context.WithValue(ctx, connectionIDKey{}, id)
+}
+
+// HandleConn 连接断开时清理连接上的资源
+func (m *ConnectionManager) HandleConn(ctx context.Context, s stats.ConnStats) {
+	if _, ok := s.(*stats.ConnEnd); !ok {
+		return
+	}
+	id := connectionID(ctx)
+	m.lock.Lock()
+	conn, ok := m.connections[id]
+	delete(m.connections, id)
+	m.lock.Unlock()
+	if ok && m.onClose != nil {
+		go m.onClose(conn)
+	}
+}
+
+// TagRPC implements stats.Handler
+func (m *ConnectionManager) TagRPC(ctx context.Context, info *stats.RPCTagInfo) context.Context {
+	return ctx
+}
+
+// HandleRPC implements stats.Handler
+func (m *ConnectionManager) HandleRPC(ctx context.Context, s stats.RPCStats) {
+}
+
+func newPayload(payloadType string, body interface{}) (*Payload, error) {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	return &Payload{
+		Metadata: &Metadata{Type: payloadType},
+		Body:     &anypb.Any{Value: data},
+	}, nil
+}
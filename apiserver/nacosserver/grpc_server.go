@@ -0,0 +1,454 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package nacosserver
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	api "github.com/polarismesh/polaris/common/api/v1"
+	"github.com/polarismesh/polaris/common/model"
+	"github.com/polarismesh/polaris/common/utils"
+)
+
+// NacosError 处理 nacos 2.x 请求的错误，转换为 ErrorResponse 返回给客户端
+type NacosError struct {
+	Code    int
+	Message string
+}
+
+// Error implements error
+func (e *NacosError) Error() string {
+	return fmt.Sprintf("code: %d, message: %s", e.Code, e.Message)
+}
+
+func newNacosError(code int, message string) error {
+	return &NacosError{Code: code, Message: message}
+}
+
+// polarisError 北极星接口的错误转换为 nacos 的错误
+func polarisError(resp api.ResponseMessage) error {
+	return &NacosError{
+		Code:    CodeServerError,
+		Message: fmt.Sprintf("polaris code: %d, info: %s", resp.GetCode().GetValue(), resp.GetInfo().GetValue()),
+	}
+}
+
+// grpcRequestHandler 处理一种 nacos 2.x 的请求
+type grpcRequestHandler struct {
+	responseType string
+	handle       func(ctx context.Context, conn *Connection, body []byte) (ResponseMessage, error)
+}
+
+func (n *NacosServer) initGrpcHandlers() {
+	n.grpcHandlers = map[string]*grpcRequestHandler{
+		TypeServerCheckRequest:       {TypeServerCheckResponse, n.handleServerCheck},
+		TypeHealthCheckRequest:       {TypeHealthCheckResponse, n.handleHealthCheck},
+		TypeInstanceRequest:          {TypeInstanceResponse, n.handleInstanceRequest},
+		TypeBatchInstanceRequest:     {TypeBatchInstanceResponse, n.handleBatchInstanceRequest},
+		TypeServiceQueryRequest:      {TypeQueryServiceResponse, n.handleServiceQuery},
+		TypeSubscribeServiceRequest:  {TypeSubscribeServiceResponse, n.handleSubscribeService},
+		TypeConfigQueryRequest:       {TypeConfigQueryResponse, n.handleConfigQuery},
+		TypeConfigPublishRequest:     {TypeConfigPublishResponse, n.handleConfigPublish},
+		TypeConfigRemoveRequest:      {TypeConfigRemoveResponse, n.handleConfigRemove},
+		TypeConfigBatchListenRequest: {TypeConfigChangeBatchListenResponse, n.handleConfigBatchListen},
+	}
+}
+
+// Request 处理 nacos 2.x 客户端的一元请求
+func (n *NacosServer) Request(ctx context.Context, payload *Payload) (*Payload, error) {
+	requestType := payload.GetMetadata().GetType()
+	body := payload.GetBody().GetValue()
+	request := &Request{}
+	if err := json.Unmarshal(body, request); err != nil {
+		return newPayload(TypeErrorResponse, newErrorResponse(CodeBadRequest, err.Error()))
+	}
+
+	handler, ok := n.grpcHandlers[requestType]
+	if !ok {
+		log.Warnf("[NACOS-SERVER]unsupported request type %s, client: %s", requestType,
+			payload.GetMetadata().GetClientIp())
+		return newPayload(TypeErrorResponse, newErrorResponse(CodeNoHandler, "unsupported request "+requestType))
+	}
+
+	conn := n.connections.Get(connectionID(ctx))
+	reqCtx := newRequestContext(payload.GetMetadata().GetHeaders()[ParamAccessToken],
+		payload.GetMetadata().GetClientIp())
+	resp, err := handler.handle(reqCtx, conn, body)
+	responseType := handler.responseType
+	if err != nil {
+		log.Errorf("[NACOS-SERVER]fail to handle %s, client: %s, err: %v", requestType,
+			payload.GetMetadata().GetClientIp(), err)
+		nacosErr := &NacosError{Code: CodeServerError, Message: err.Error()}
+		errors.As(err, &nacosErr)
+		resp, responseType = newErrorResponse(nacosErr.Code, nacosErr.Message), TypeErrorResponse
+	}
+	resp.GetResponse().RequestId = request.RequestId
+	return newPayload(responseType, resp)
+}
+
+// RequestBiStream 处理 nacos 2.x 客户端的双向流，服务端通过它推送服务和配置的变更
+func (n *NacosServer) RequestBiStream(stream BiRequestStream_RequestBiStreamServer) error {
+	conn := n.connections.Get(connectionID(stream.Context()))
+	if conn == nil {
+		return status.Error(codes.Unavailable, "connection not found")
+	}
+
+	recvDone := make(chan struct{})
+	go func() {
+		defer close(recvDone)
+		for {
+			payload, err := stream.Recv()
+			if err != nil {
+				return
+			}
+			n.handleStreamPayload(conn, payload)
+		}
+	}()
+
+	for {
+		select {
+		case payload := <-conn.sendCh:
+			if err := stream.Send(payload); err != nil {
+				log.Errorf("[NACOS-SERVER]fail to push %s, conn: %s, err: %v",
+					payload.GetMetadata().GetType(), conn.ID, err)
+				return err
+			}
+		case <-recvDone:
+			return nil
+		case <-stream.Context().Done():
+			return nil
+		}
+	}
+}
+
+// handleStreamPayload 处理客户端通过双向流发送的连接建立请求，以及对推送的应答
+func (n *NacosServer) handleStreamPayload(conn *Connection, payload *Payload) {
+	payloadType := payload.GetMetadata().GetType()
+	if payloadType == TypeConnectionSetupRequest {
+		setup := &ConnectionSetupRequest{}
+		if err := json.Unmarshal(payload.GetBody().GetValue(), setup); err != nil {
+			log.Errorf("[NACOS-SERVER]invalid connection setup request, conn: %s, err: %v", conn.ID, err)
+			return
+		}
+		conn.setClientVersion(setup.ClientVersion)
+		log.Infof("[NACOS-SERVER]connection setup, conn: %s, client version: %s, tenant: %s",
+			conn.ID, setup.ClientVersion, setup.Tenant)
+		return
+	}
+	resp := &Response{}
+	if err := json.Unmarshal(payload.GetBody().GetValue(), resp); err == nil && resp.ResultCode != CodeSuccess {
+		log.Warnf("[NACOS-SERVER]client fail to handle push, conn: %s, type: %s, message: %s",
+			conn.ID, payloadType, resp.Message)
+	}
+}
+
+// closeConnection 连接断开后反注册通过该连接注册的实例，并取消订阅和监听
+func (n *NacosServer) closeConnection(conn *Connection) {
+	log.Infof("[NACOS-SERVER]connection closed, conn: %s, client version: %s", conn.ID, conn.getClientVersion())
+	for _, item := range conn.listInstances() {
+		resp := n.deregisterInstance(newRequestContext(item.token, conn.RemoteAddress), item.instance)
+		if resp.GetCode().GetValue() != api.ExecuteSuccess {
+			log.Errorf("[NACOS-SERVER]fail to deregister instance %s of closed conn %s, code: %d",
+				item.instance.GetId().GetValue(), conn.ID, resp.GetCode().GetValue())
+		}
+	}
+	for _, key := range conn.listSubscribes() {
+		n.pushCenter.Unsubscribe(key, conn.ID)
+	}
+	for _, listen := range conn.listListens() {
+		n.configWatcher.Unwatch(conn.ID, listen.namespace, listen.group, listen.fileName)
+	}
+}
+
+// runConnectionHeartbeat 长连接存活期间，定期为通过它注册的临时实例上报心跳
+func (n *NacosServer) runConnectionHeartbeat(ctx context.Context) {
+	ticker := time.NewTicker(DefaultHeartbeatInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			n.connections.Range(func(conn *Connection) {
+				for _, item := range conn.listInstances() {
+					reqCtx := newRequestContext(item.token, conn.RemoteAddress)
+					code := n.heartbeat(reqCtx, &api.Instance{Id: item.instance.GetId()})
+					if code != api.NotFoundResource {
+						continue
+					}
+					// 实例被删除，但是连接仍然存活，重新注册
+					if resp := n.registerInstance(reqCtx, item.instance); resp.GetCode().GetValue() !=
+						api.ExecuteSuccess {
+						log.Errorf("[NACOS-SERVER]fail to re-register instance %s of conn %s, code: %d",
+							item.instance.GetId().GetValue(), conn.ID, resp.GetCode().GetValue())
+					}
+				}
+			})
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (n *NacosServer) handleServerCheck(ctx context.Context, conn *Connection, body []byte) (ResponseMessage, error) {
+	resp := &ServerCheckResponse{Response: newSuccessResponse()}
+	if conn != nil {
+		resp.ConnectionId = conn.ID
+	}
+	return resp, nil
+}
+
+func (n *NacosServer) handleHealthCheck(ctx context.Context, conn *Connection, body []byte) (ResponseMessage, error) {
+	return &HealthCheckResponse{Response: newSuccessResponse()}, nil
+}
+
+func (n *NacosServer) handleInstanceRequest(ctx context.Context, conn *Connection,
+	body []byte) (ResponseMessage, error) {
+	req := &InstanceRequest{}
+	if err := json.Unmarshal(body, req); err != nil {
+		return nil, newNacosError(CodeBadRequest, err.Error())
+	}
+	if req.Instance == nil {
+		return nil, newNacosError(CodeBadRequest, "instance can not be empty")
+	}
+	namespace := n.toPolarisNamespace(req.Namespace)
+	group, service := splitGroupedName(req.GroupName, req.ServiceName)
+	var err error
+	switch req.Type {
+	case RegisterInstance:
+		err = n.registerConnInstance(ctx, conn, namespace, group, service, req.Instance)
+	case DeregisterInstance:
+		err = n.deregisterConnInstance(ctx, conn, namespace, group, service, req.Instance)
+	default:
+		err = newNacosError(CodeBadRequest, "unsupported instance request type "+req.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &InstanceResponse{Response: newSuccessResponse(), Type: req.Type}, nil
+}
+
+func (n *NacosServer) handleBatchInstanceRequest(ctx context.Context, conn *Connection,
+	body []byte) (ResponseMessage, error) {
+	req := &BatchInstanceRequest{}
+	if err := json.Unmarshal(body, req); err != nil {
+		return nil, newNacosError(CodeBadRequest, err.Error())
+	}
+	if req.Type != BatchRegisterInstance {
+		return nil, newNacosError(CodeBadRequest, "unsupported batch instance request type "+req.Type)
+	}
+	namespace := n.toPolarisNamespace(req.Namespace)
+	group, service := splitGroupedName(req.GroupName, req.ServiceName)
+	for _, instance := range req.Instances {
+		if err := n.registerConnInstance(ctx, conn, namespace, group, service, instance); err != nil {
+			return nil, err
+		}
+	}
+	return &BatchInstanceResponse{Response: newSuccessResponse(), Type: req.Type}, nil
+}
+
+// registerConnInstance 注册实例，临时实例和连接绑定，由服务端代为上报心跳
+func (n *NacosServer) registerConnInstance(ctx context.Context, conn *Connection, namespace string,
+	group string, service string, instance *Instance) error {
+	polarisInstance := buildPolarisInstance(namespace, group, service, instance)
+	bindConn := instance.Ephemeral && conn != nil
+	if bindConn {
+		polarisInstance.HealthCheck.Heartbeat.Ttl.Value = uint32(DefaultHeartbeatInterval / time.Second)
+	}
+	resp := n.registerInstance(ctx, polarisInstance)
+	if code := resp.GetCode().GetValue(); code != api.ExecuteSuccess && code != api.ExistedResource {
+		return polarisError(resp)
+	}
+	if bindConn {
+		id, errRsp := utils.CheckInstanceTetrad(polarisInstance)
+		if errRsp != nil {
+			return polarisError(errRsp)
+		}
+		polarisInstance.Id = utils.NewStringValue(id)
+		conn.addInstance(id, polarisInstance, utils.ParseAuthToken(ctx))
+	}
+	return nil
+}
+
+func (n *NacosServer) deregisterConnInstance(ctx context.Context, conn *Connection, namespace string,
+	group string, service string, instance *Instance) error {
+	polarisInstance := buildPolarisInstance(namespace, group, service, instance)
+	if resp := n.deregisterInstance(ctx, polarisInstance); resp.GetCode().GetValue() != api.ExecuteSuccess {
+		return polarisError(resp)
+	}
+	if conn != nil {
+		if id, errRsp := utils.CheckInstanceTetrad(polarisInstance); errRsp == nil {
+			conn.removeInstance(id)
+		}
+	}
+	return nil
+}
+
+func (n *NacosServer) handleServiceQuery(ctx context.Context, conn *Connection, body []byte) (ResponseMessage, error) {
+	req := &ServiceQueryRequest{}
+	if err := json.Unmarshal(body, req); err != nil {
+		return nil, newNacosError(CodeBadRequest, err.Error())
+	}
+	group, service := splitGroupedName(req.GroupName, req.ServiceName)
+	info := n.buildServiceInfo(n.toPolarisNamespace(req.Namespace), group, service, service, req.Cluster,
+		req.HealthyOnly)
+	return &QueryServiceResponse{Response: newSuccessResponse(), ServiceInfo: info}, nil
+}
+
+func (n *NacosServer) handleSubscribeService(ctx context.Context, conn *Connection,
+	body []byte) (ResponseMessage, error) {
+	req := &SubscribeServiceRequest{}
+	if err := json.Unmarshal(body, req); err != nil {
+		return nil, newNacosError(CodeBadRequest, err.Error())
+	}
+	if conn == nil {
+		return nil, newNacosError(CodeServerError, "connection not found")
+	}
+	namespace := n.toPolarisNamespace(req.Namespace)
+	group, service := splitGroupedName(req.GroupName, req.ServiceName)
+	key := model.ServiceKey{Namespace: namespace, Name: toPolarisService(group, service)}
+	if req.Subscribe {
+		n.pushCenter.Subscribe(key, &subscriber{
+			conn:        conn,
+			namespace:   req.Namespace,
+			groupName:   group,
+			serviceName: service,
+			clusters:    req.Clusters,
+		})
+		conn.addSubscribe(key)
+	} else {
+		n.pushCenter.Unsubscribe(key, conn.ID)
+		conn.removeSubscribe(key)
+	}
+	info := n.buildServiceInfo(namespace, group, service, service, req.Clusters, false)
+	return &SubscribeServiceResponse{Response: newSuccessResponse(), ServiceInfo: info}, nil
+}
+
+func (n *NacosServer) handleConfigQuery(ctx context.Context, conn *Connection, body []byte) (ResponseMessage, error) {
+	if n.configServer == nil {
+		return nil, newNacosError(CodeServerError, "config center is not open")
+	}
+	req := &ConfigQueryRequest{}
+	if err := json.Unmarshal(body, req); err != nil {
+		return nil, newNacosError(CodeBadRequest, err.Error())
+	}
+	configFile, code := n.getConfig(ctx, &req.ConfigKey)
+	if code == api.NotFoundResource {
+		return &ConfigQueryResponse{Response: Response{
+			ResultCode: CodeFail,
+			ErrorCode:  CodeConfigNotFound,
+			Message:    "config data not exist",
+		}}, nil
+	}
+	if code != api.ExecuteSuccess {
+		return nil, polarisError(api.NewResponse(code))
+	}
+	return &ConfigQueryResponse{
+		Response:     newSuccessResponse(),
+		Content:      configFile.GetContent().GetValue(),
+		Md5:          configFile.GetMd5().GetValue(),
+		LastModified: time.Now().UnixNano() / int64(time.Millisecond),
+		Tag:          req.Tag,
+	}, nil
+}
+
+func (n *NacosServer) handleConfigPublish(ctx context.Context, conn *Connection,
+	body []byte) (ResponseMessage, error) {
+	if n.configServer == nil {
+		return nil, newNacosError(CodeServerError, "config center is not open")
+	}
+	req := &ConfigPublishRequest{}
+	if err := json.Unmarshal(body, req); err != nil {
+		return nil, newNacosError(CodeBadRequest, err.Error())
+	}
+	if resp := n.publishConfig(ctx, &req.ConfigKey, req.Content, req.AdditionMap["type"]); resp.GetCode().
+		GetValue() != api.ExecuteSuccess {
+		return nil, polarisError(resp)
+	}
+	return &ConfigPublishResponse{Response: newSuccessResponse()}, nil
+}
+
+func (n *NacosServer) handleConfigRemove(ctx context.Context, conn *Connection,
+	body []byte) (ResponseMessage, error) {
+	if n.configServer == nil {
+		return nil, newNacosError(CodeServerError, "config center is not open")
+	}
+	req := &ConfigRemoveRequest{}
+	if err := json.Unmarshal(body, req); err != nil {
+		return nil, newNacosError(CodeBadRequest, err.Error())
+	}
+	if resp := n.removeConfig(ctx, &req.ConfigKey); resp.GetCode().GetValue() != api.ExecuteSuccess {
+		return nil, polarisError(resp)
+	}
+	return &ConfigRemoveResponse{Response: newSuccessResponse()}, nil
+}
+
+func (n *NacosServer) handleConfigBatchListen(ctx context.Context, conn *Connection,
+	body []byte) (ResponseMessage, error) {
+	if n.configServer == nil {
+		return nil, newNacosError(CodeServerError, "config center is not open")
+	}
+	req := &ConfigBatchListenRequest{}
+	if err := json.Unmarshal(body, req); err != nil {
+		return nil, newNacosError(CodeBadRequest, err.Error())
+	}
+	if conn == nil {
+		return nil, newNacosError(CodeServerError, "connection not found")
+	}
+	for _, listen := range req.ConfigListenContexts {
+		namespace, group, fileName := n.toPolarisConfigFile(&listen.ConfigKey)
+		fileId := utils.GenFileId(namespace, group, fileName)
+		if !req.Listen {
+			conn.removeListen(fileId)
+			n.configWatcher.Unwatch(conn.ID, namespace, group, fileName)
+			continue
+		}
+		conn.addListen(fileId, &connListen{
+			namespace: namespace,
+			group:     group,
+			fileName:  fileName,
+			key:       listen.ConfigKey,
+		})
+		n.configWatcher.Watch(conn.ID, namespace, group, fileName, func(namespace, group, fileName string) {
+			n.notifyConfigChange(conn, utils.GenFileId(namespace, group, fileName))
+		})
+	}
+
+	resp := &ConfigChangeBatchListenResponse{Response: newSuccessResponse(), ChangedConfigs: []*ConfigKey{}}
+	if req.Listen {
+		resp.ChangedConfigs = n.changedConfigs(ctx, req.ConfigListenContexts)
+	}
+	return resp, nil
+}
+
+// notifyConfigChange 推送配置变更，客户端收到后再查询最新的配置
+func (n *NacosServer) notifyConfigChange(conn *Connection, fileId string) {
+	listen, ok := conn.getListen(fileId)
+	if !ok {
+		return
+	}
+	conn.Push(TypeConfigChangeNotifyRequest, &ConfigChangeNotifyRequest{
+		Request:   Request{RequestId: utils.NewUUID(), Module: ModuleConfig},
+		ConfigKey: listen.key,
+	})
+}
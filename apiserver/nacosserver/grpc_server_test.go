@@ -0,0 +1,112 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package nacosserver
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+func newTestGrpcServer(t *testing.T, onClose func(conn *Connection)) (*NacosServer, *grpc.ClientConn) {
+	n := &NacosServer{}
+	n.connections = NewConnectionManager(onClose)
+	n.initGrpcHandlers()
+	n.grpcServer = grpc.NewServer(grpc.StatsHandler(n.connections))
+	RegisterRequestServer(n.grpcServer, n)
+	RegisterBiRequestStreamServer(n.grpcServer, n)
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	go func() {
+		_ = n.grpcServer.Serve(ln)
+	}()
+	t.Cleanup(n.grpcServer.Stop)
+
+	clientConn, err := grpc.Dial(ln.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	assert.NoError(t, err)
+	return n, clientConn
+}
+
+func request(t *testing.T, clientConn *grpc.ClientConn, requestType string, body string) *Payload {
+	in := &Payload{Metadata: &Metadata{Type: requestType}, Body: &anypb.Any{Value: []byte(body)}}
+	out, err := NewRequestClient(clientConn).Request(context.Background(), in)
+	assert.NoError(t, err)
+	return out
+}
+
+func TestNacosServer_Request(t *testing.T) {
+	closed := make(chan *Connection, 1)
+	n, clientConn := newTestGrpcServer(t, func(conn *Connection) {
+		closed <- conn
+	})
+
+	out := request(t, clientConn, TypeServerCheckRequest, `{"requestId":"1","module":"internal"}`)
+	assert.Equal(t, TypeServerCheckResponse, out.GetMetadata().GetType())
+	checkResp := &ServerCheckResponse{}
+	assert.NoError(t, json.Unmarshal(out.GetBody().GetValue(), checkResp))
+	assert.Equal(t, CodeSuccess, checkResp.ResultCode)
+	assert.Equal(t, "1", checkResp.RequestId)
+	assert.NotEmpty(t, checkResp.ConnectionId)
+	assert.NotNil(t, n.connections.Get(checkResp.ConnectionId))
+
+	out = request(t, clientConn, "UnknownRequest", `{"requestId":"2"}`)
+	assert.Equal(t, TypeErrorResponse, out.GetMetadata().GetType())
+	errResp := &ErrorResponse{}
+	assert.NoError(t, json.Unmarshal(out.GetBody().GetValue(), errResp))
+	assert.Equal(t, CodeFail, errResp.ResultCode)
+	assert.Equal(t, CodeNoHandler, errResp.ErrorCode)
+
+	// 通过双向流建立连接，并接收服务端的推送
+	stream, err := NewBiRequestStreamClient(clientConn).RequestBiStream(context.Background())
+	assert.NoError(t, err)
+	setup, _ := newPayload(TypeConnectionSetupRequest, &ConnectionSetupRequest{ClientVersion: "Nacos-Java-Client:v2.1.0"})
+	assert.NoError(t, stream.Send(setup))
+
+	conn := n.connections.Get(checkResp.ConnectionId)
+	conn.Push(TypeConfigChangeNotifyRequest, &ConfigChangeNotifyRequest{
+		Request:   Request{RequestId: "3", Module: ModuleConfig},
+		ConfigKey: ConfigKey{DataId: "app.yaml", Group: DefaultGroup},
+	})
+	pushed, err := stream.Recv()
+	assert.NoError(t, err)
+	assert.Equal(t, TypeConfigChangeNotifyRequest, pushed.GetMetadata().GetType())
+	notify := &ConfigChangeNotifyRequest{}
+	assert.NoError(t, json.Unmarshal(pushed.GetBody().GetValue(), notify))
+	assert.Equal(t, "app.yaml", notify.DataId)
+	assert.Eventually(t, func() bool {
+		return conn.getClientVersion() == "Nacos-Java-Client:v2.1.0"
+	}, time.Second, 10*time.Millisecond)
+
+	// 连接断开后清理连接
+	assert.NoError(t, clientConn.Close())
+	select {
+	case closedConn := <-closed:
+		assert.Equal(t, checkResp.ConnectionId, closedConn.ID)
+	case <-time.After(5 * time.Second):
+		t.Fatal("connection close not handled")
+	}
+	assert.Nil(t, n.connections.Get(checkResp.ConnectionId))
+}
@@ -0,0 +1,429 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package nacosserver
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	restful "github.com/emicklei/go-restful/v3"
+
+	api "github.com/polarismesh/polaris/common/api/v1"
+	"github.com/polarismesh/polaris/common/utils"
+)
+
+const (
+	ParamNamespaceId      = "namespaceId"
+	ParamServiceName      = "serviceName"
+	ParamGroupName        = "groupName"
+	ParamClusterName      = "clusterName"
+	ParamClusters         = "clusters"
+	ParamIp               = "ip"
+	ParamPort             = "port"
+	ParamWeight           = "weight"
+	ParamEnabled          = "enabled"
+	ParamHealthy          = "healthy"
+	ParamEphemeral        = "ephemeral"
+	ParamMetadata         = "metadata"
+	ParamBeat             = "beat"
+	ParamHealthyOnly      = "healthyOnly"
+	ParamDataId           = "dataId"
+	ParamGroup            = "group"
+	ParamTenant           = "tenant"
+	ParamContent          = "content"
+	ParamType             = "type"
+	ParamListeningConfigs = "Listening-Configs"
+
+	HeaderLongPullingTimeout       = "Long-Pulling-Timeout"
+	HeaderLongPullingTimeoutNoHang = "Long-Pulling-Timeout-No-Hangup"
+	HeaderContentMD5               = "Content-MD5"
+
+	// longPullingDelay nacos 服务端提前于客户端超时时间返回长轮询请求
+	longPullingDelay = 500 * time.Millisecond
+)
+
+// GetNamingServer nacos 1.x 注册发现的 open api
+func (n *NacosServer) GetNamingServer() *restful.WebService {
+	ws := new(restful.WebService)
+	ws.Path("/nacos/v1/ns")
+	ws.Route(ws.POST("/instance").To(n.RegisterInstance))
+	ws.Route(ws.DELETE("/instance").To(n.DeregisterInstance))
+	ws.Route(ws.PUT("/instance/beat").To(n.BeatInstance))
+	ws.Route(ws.GET("/instance/list").To(n.ListInstances))
+	ws.Route(ws.GET("/operator/metrics").To(n.ServerStatus))
+	return ws
+}
+
+// GetConfigServer nacos 1.x 配置中心的 open api
+func (n *NacosServer) GetConfigServer() *restful.WebService {
+	ws := new(restful.WebService)
+	ws.Path("/nacos/v1/cs")
+	ws.Route(ws.GET("/configs").To(n.GetConfig))
+	ws.Route(ws.POST("/configs").To(n.PublishConfig))
+	ws.Route(ws.DELETE("/configs").To(n.RemoveConfig))
+	ws.Route(ws.POST("/configs/listener").To(n.ListenConfigs))
+	return ws
+}
+
+// RegisterInstance 注册实例
+func (n *NacosServer) RegisterInstance(req *restful.Request, rsp *restful.Response) {
+	namespace, group, service, err := n.parseService(req)
+	if err != nil {
+		writeError(rsp, http.StatusBadRequest, err.Error())
+		return
+	}
+	instance, err := parseInstance(req)
+	if err != nil {
+		writeError(rsp, http.StatusBadRequest, err.Error())
+		return
+	}
+	resp := n.registerInstance(buildContext(req), buildPolarisInstance(namespace, group, service, instance))
+	if code := resp.GetCode().GetValue(); code != api.ExecuteSuccess && code != api.ExistedResource {
+		writePolarisError(rsp, resp)
+		return
+	}
+	writeText(rsp, "ok")
+}
+
+// DeregisterInstance 反注册实例
+func (n *NacosServer) DeregisterInstance(req *restful.Request, rsp *restful.Response) {
+	namespace, group, service, err := n.parseService(req)
+	if err != nil {
+		writeError(rsp, http.StatusBadRequest, err.Error())
+		return
+	}
+	instance, err := parseInstance(req)
+	if err != nil {
+		writeError(rsp, http.StatusBadRequest, err.Error())
+		return
+	}
+	resp := n.deregisterInstance(buildContext(req), buildPolarisInstance(namespace, group, service, instance))
+	if resp.GetCode().GetValue() != api.ExecuteSuccess {
+		writePolarisError(rsp, resp)
+		return
+	}
+	writeText(rsp, "ok")
+}
+
+// BeatInstance nacos 1.x 客户端的心跳，实例不存在时返回 20404，客户端收到后会重新注册
+func (n *NacosServer) BeatInstance(req *restful.Request, rsp *restful.Response) {
+	namespace, group, service, err := n.parseService(req)
+	if err != nil {
+		writeError(rsp, http.StatusBadRequest, err.Error())
+		return
+	}
+	beat := &ClientBeat{}
+	if value := req.Request.FormValue(ParamBeat); len(value) > 0 {
+		if err := json.Unmarshal([]byte(value), beat); err != nil {
+			writeError(rsp, http.StatusBadRequest, "invalid beat: "+err.Error())
+			return
+		}
+	}
+	// 开启轻量心跳后，客户端不再携带 beat，只通过参数携带 ip 和端口
+	if len(beat.Ip) == 0 {
+		beat.Ip = req.Request.FormValue(ParamIp)
+		beat.Port, _ = strconv.Atoi(req.Request.FormValue(ParamPort))
+	}
+	if len(beat.Ip) == 0 || beat.Port <= 0 {
+		writeError(rsp, http.StatusBadRequest, "ip and port can not be empty")
+		return
+	}
+
+	code := n.heartbeat(buildContext(req), &api.Instance{
+		Namespace: utils.NewStringValue(namespace),
+		Service:   utils.NewStringValue(toPolarisService(group, service)),
+		Host:      utils.NewStringValue(beat.Ip),
+		Port:      utils.NewUInt32Value(uint32(beat.Port)),
+	})
+	beatResp := &BeatResponse{
+		ClientBeatInterval: int64(DefaultHeartbeatInterval / time.Millisecond),
+		Code:               CodeOK,
+		LightBeatEnabled:   true,
+	}
+	switch code {
+	case api.ExecuteSuccess, api.HeartbeatExceedLimit:
+	case api.NotFoundResource, api.NotFoundInstance:
+		beatResp.Code = CodeResourceNotFound
+	default:
+		writePolarisError(rsp, api.NewResponse(code))
+		return
+	}
+	writeJson(rsp, beatResp)
+}
+
+// ListInstances 查询服务实例，返回的服务名带有分组
+func (n *NacosServer) ListInstances(req *restful.Request, rsp *restful.Response) {
+	namespace, group, service, err := n.parseService(req)
+	if err != nil {
+		writeError(rsp, http.StatusBadRequest, err.Error())
+		return
+	}
+	healthyOnly, _ := strconv.ParseBool(req.Request.FormValue(ParamHealthyOnly))
+	info := n.buildServiceInfo(namespace, group, service, getGroupedName(group, service),
+		req.Request.FormValue(ParamClusters), healthyOnly)
+	writeJson(rsp, info)
+}
+
+// ServerStatus nacos 客户端检查服务端是否可用
+func (n *NacosServer) ServerStatus(req *restful.Request, rsp *restful.Response) {
+	writeJson(rsp, map[string]string{"status": "UP"})
+}
+
+// GetConfig 获取已经发布的配置内容
+func (n *NacosServer) GetConfig(req *restful.Request, rsp *restful.Response) {
+	key, ok := n.parseConfigKey(req, rsp)
+	if !ok {
+		return
+	}
+	configFile, code := n.getConfig(buildContext(req), key)
+	if code == api.NotFoundResource {
+		writeError(rsp, http.StatusNotFound, "config data not exist")
+		return
+	}
+	if code != api.ExecuteSuccess {
+		writePolarisError(rsp, api.NewResponse(code))
+		return
+	}
+	rsp.AddHeader(HeaderContentMD5, configFile.GetMd5().GetValue())
+	writeText(rsp, configFile.GetContent().GetValue())
+}
+
+// PublishConfig 创建或者更新配置，并立即发布
+func (n *NacosServer) PublishConfig(req *restful.Request, rsp *restful.Response) {
+	key, ok := n.parseConfigKey(req, rsp)
+	if !ok {
+		return
+	}
+	content := req.Request.FormValue(ParamContent)
+	if len(strings.TrimSpace(content)) == 0 {
+		writeError(rsp, http.StatusBadRequest, "content can not be empty")
+		return
+	}
+	resp := n.publishConfig(buildContext(req), key, content, req.Request.FormValue(ParamType))
+	if resp.GetCode().GetValue() != api.ExecuteSuccess {
+		writePolarisError(rsp, resp)
+		return
+	}
+	writeText(rsp, "true")
+}
+
+// RemoveConfig 删除配置
+func (n *NacosServer) RemoveConfig(req *restful.Request, rsp *restful.Response) {
+	key, ok := n.parseConfigKey(req, rsp)
+	if !ok {
+		return
+	}
+	if resp := n.removeConfig(buildContext(req), key); resp.GetCode().GetValue() != api.ExecuteSuccess {
+		writePolarisError(rsp, resp)
+		return
+	}
+	writeText(rsp, "true")
+}
+
+// ListenConfigs nacos 1.x 客户端的长轮询，返回 md5 发生变化的配置
+func (n *NacosServer) ListenConfigs(req *restful.Request, rsp *restful.Response) {
+	if n.configServer == nil {
+		writeError(rsp, http.StatusNotImplemented, "config center is not open")
+		return
+	}
+	listens, err := parseListeningConfigs(req.Request.FormValue(ParamListeningConfigs))
+	if err != nil || len(listens) == 0 {
+		writeError(rsp, http.StatusBadRequest, "invalid probeModify")
+		return
+	}
+
+	timeout := DefaultLongPollingTimeout
+	if value, err := strconv.ParseInt(req.HeaderParameter(HeaderLongPullingTimeout), 10, 64); err == nil {
+		timeout = time.Duration(value) * time.Millisecond
+	}
+	timeout -= longPullingDelay
+	if strings.EqualFold(req.HeaderParameter(HeaderLongPullingTimeoutNoHang), "true") {
+		timeout = 0
+	}
+	changed := n.waitChangedConfigs(req.Request.Context(), buildContext(req), listens, timeout)
+	writeText(rsp, encodeChangedConfigs(changed))
+}
+
+// waitChangedConfigs 先注册监听再比较 md5，避免丢失两者之间发布的变更；没有变更时等待发布事件或者超时
+func (n *NacosServer) waitChangedConfigs(waitCtx context.Context, ctx context.Context,
+	listens []*ConfigListenContext, timeout time.Duration) []*ConfigKey {
+	if timeout <= 0 {
+		return n.changedConfigs(ctx, listens)
+	}
+
+	clientId := "nacos-http@" + utils.NewUUID()
+	notifyCh := make(chan struct{}, 1)
+	for _, listen := range listens {
+		namespace, group, fileName := n.toPolarisConfigFile(&listen.ConfigKey)
+		n.configWatcher.Watch(clientId, namespace, group, fileName, func(string, string, string) {
+			select {
+			case notifyCh <- struct{}{}:
+			default:
+			}
+		})
+	}
+	defer func() {
+		for _, listen := range listens {
+			namespace, group, fileName := n.toPolarisConfigFile(&listen.ConfigKey)
+			n.configWatcher.Unwatch(clientId, namespace, group, fileName)
+		}
+	}()
+
+	if changed := n.changedConfigs(ctx, listens); len(changed) > 0 {
+		return changed
+	}
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case <-notifyCh:
+		return n.changedConfigs(ctx, listens)
+	case <-timer.C:
+	case <-waitCtx.Done():
+	}
+	return nil
+}
+
+// parseService 解析请求的命名空间、分组和服务名
+func (n *NacosServer) parseService(req *restful.Request) (string, string, string, error) {
+	group, service := splitGroupedName(req.Request.FormValue(ParamGroupName), req.Request.FormValue(ParamServiceName))
+	if len(service) == 0 {
+		return "", "", "", errors.New("serviceName can not be empty")
+	}
+	return n.toPolarisNamespace(req.Request.FormValue(ParamNamespaceId)), group, service, nil
+}
+
+// parseConfigKey 解析请求的配置标识，配置中心没有开启或者参数非法时直接应答
+func (n *NacosServer) parseConfigKey(req *restful.Request, rsp *restful.Response) (*ConfigKey, bool) {
+	if n.configServer == nil {
+		writeError(rsp, http.StatusNotImplemented, "config center is not open")
+		return nil, false
+	}
+	key := &ConfigKey{
+		DataId: req.Request.FormValue(ParamDataId),
+		Group:  req.Request.FormValue(ParamGroup),
+		Tenant: req.Request.FormValue(ParamTenant),
+	}
+	if len(key.DataId) == 0 {
+		writeError(rsp, http.StatusBadRequest, "dataId can not be empty")
+		return nil, false
+	}
+	return key, true
+}
+
+// parseInstance 解析实例参数，未携带的参数使用 nacos 的默认值
+func parseInstance(req *restful.Request) (*Instance, error) {
+	instance := &Instance{
+		Ip:          req.Request.FormValue(ParamIp),
+		Weight:      1,
+		Healthy:     true,
+		Enabled:     true,
+		Ephemeral:   true,
+		ClusterName: DefaultCluster,
+	}
+	port, err := strconv.Atoi(req.Request.FormValue(ParamPort))
+	if len(instance.Ip) == 0 || err != nil || port <= 0 {
+		return nil, errors.New("ip and port can not be empty")
+	}
+	instance.Port = port
+	if value := req.Request.FormValue(ParamWeight); len(value) > 0 {
+		if instance.Weight, err = strconv.ParseFloat(value, 64); err != nil {
+			return nil, errors.New("invalid weight")
+		}
+	}
+	for name, field := range map[string]*bool{
+		ParamHealthy:   &instance.Healthy,
+		ParamEnabled:   &instance.Enabled,
+		ParamEphemeral: &instance.Ephemeral,
+	} {
+		if value := req.Request.FormValue(name); len(value) > 0 {
+			if *field, err = strconv.ParseBool(value); err != nil {
+				return nil, errors.New("invalid " + name)
+			}
+		}
+	}
+	if value := req.Request.FormValue(ParamClusterName); len(value) > 0 {
+		instance.ClusterName = value
+	}
+	instance.Metadata = parseMetadata(req.Request.FormValue(ParamMetadata))
+	return instance, nil
+}
+
+// parseMetadata 元数据为 json 格式，兼容老版本 k1=v1,k2=v2 的格式
+func parseMetadata(value string) map[string]string {
+	metadata := make(map[string]string)
+	if len(value) == 0 {
+		return metadata
+	}
+	if err := json.Unmarshal([]byte(value), &metadata); err == nil {
+		return metadata
+	}
+	for _, item := range strings.Split(value, ",") {
+		kv := strings.SplitN(item, "=", 2)
+		if len(kv) == 2 {
+			metadata[kv[0]] = kv[1]
+		}
+	}
+	return metadata
+}
+
+// buildContext nacos 客户端通过 accessToken 参数携带鉴权 token，同时兼容北极星的 token 请求头
+func buildContext(req *restful.Request) context.Context {
+	token := req.Request.FormValue(ParamAccessToken)
+	if len(token) == 0 {
+		token = req.HeaderParameter(ParamAccessToken)
+	}
+	if len(token) == 0 {
+		token = req.HeaderParameter(utils.HeaderAuthTokenKey)
+	}
+	return newRequestContext(token, req.Request.RemoteAddr)
+}
+
+func writeText(rsp *restful.Response, text string) {
+	rsp.AddHeader(restful.HEADER_ContentType, "text/plain;charset=UTF-8")
+	rsp.WriteHeader(http.StatusOK)
+	_, _ = rsp.Write([]byte(text))
+}
+
+func writeJson(rsp *restful.Response, value interface{}) {
+	if err := rsp.WriteHeaderAndJson(http.StatusOK, value, restful.MIME_JSON); err != nil {
+		log.Errorf("[NACOS-SERVER]fail to write response, err: %v", err)
+	}
+}
+
+func writeError(rsp *restful.Response, httpStatus int, message string) {
+	rsp.AddHeader(restful.HEADER_ContentType, "text/plain;charset=UTF-8")
+	_ = rsp.WriteErrorString(httpStatus, "caused: "+message)
+}
+
+// writePolarisError 北极星的错误码转换为 http 状态码
+func writePolarisError(rsp *restful.Response, resp api.ResponseMessage) {
+	httpStatus := api.CalcCode(resp)
+	if httpStatus < http.StatusBadRequest {
+		httpStatus = http.StatusInternalServerError
+	}
+	message := resp.GetInfo().GetValue()
+	if len(message) == 0 {
+		message = api.Code2Info(resp.GetCode().GetValue())
+	}
+	writeError(rsp, httpStatus, message)
+}
@@ -0,0 +1,114 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package nacosserver
+
+const (
+	ServerNacos = "nacos"
+
+	// DefaultNacosNamespace nacos 的默认命名空间，映射为北极星的默认命名空间
+	DefaultNacosNamespace = "public"
+	// DefaultNamespace 北极星的默认命名空间
+	DefaultNamespace = "default"
+	// DefaultGroup nacos 的默认分组
+	DefaultGroup = "DEFAULT_GROUP"
+	// DefaultCluster nacos 的默认集群
+	DefaultCluster = "DEFAULT"
+	// GroupServiceSeparator nacos 中分组和服务名的连接符，如 DEFAULT_GROUP@@echo
+	GroupServiceSeparator = "@@"
+	// PolarisGroupSeparator 非默认分组的服务在北极星中的服务名为 分组__服务名
+	PolarisGroupSeparator = "__"
+
+	MetadataRegisterFrom = "internal-register-from"
+	MetadataNacosCluster = "internal-nacos-cluster"
+	// MetadataHeartBeatInterval nacos 实例元数据中的心跳间隔，单位毫秒
+	MetadataHeartBeatInterval = "preserved.heart.beat.interval"
+
+	// ParamAccessToken nacos 客户端携带鉴权 token 的参数
+	ParamAccessToken = "accessToken"
+)
+
+// nacos 接口返回的错误码
+const (
+	CodeSuccess          = 200
+	CodeBadRequest       = 400
+	CodeFail             = 500
+	CodeOK               = 10200
+	CodeResourceNotFound = 20404
+	CodeConfigNotFound   = 300
+	CodeNoHandler        = 302
+	CodeServerError      = 500
+)
+
+// Instance nacos 的服务实例
+type Instance struct {
+	InstanceId  string            `json:"instanceId,omitempty"`
+	Ip          string            `json:"ip"`
+	Port        int               `json:"port"`
+	Weight      float64           `json:"weight"`
+	Healthy     bool              `json:"healthy"`
+	Enabled     bool              `json:"enabled"`
+	Ephemeral   bool              `json:"ephemeral"`
+	ClusterName string            `json:"clusterName"`
+	ServiceName string            `json:"serviceName"`
+	Metadata    map[string]string `json:"metadata"`
+}
+
+// ServiceInfo nacos 客户端查询和订阅得到的服务信息
+type ServiceInfo struct {
+	Name                     string      `json:"name"`
+	GroupName                string      `json:"groupName"`
+	Clusters                 string      `json:"clusters"`
+	CacheMillis              int64       `json:"cacheMillis"`
+	Hosts                    []*Instance `json:"hosts"`
+	LastRefTime              int64       `json:"lastRefTime"`
+	Checksum                 string      `json:"checksum"`
+	AllIPs                   bool        `json:"allIPs"`
+	ReachProtectionThreshold bool        `json:"reachProtectionThreshold"`
+	Valid                    bool        `json:"valid"`
+}
+
+// ClientBeat nacos 1.x 客户端上报的心跳
+type ClientBeat struct {
+	ServiceName string            `json:"serviceName"`
+	Cluster     string            `json:"cluster"`
+	Ip          string            `json:"ip"`
+	Port        int               `json:"port"`
+	Weight      float64           `json:"weight"`
+	Metadata    map[string]string `json:"metadata"`
+	Period      int64             `json:"period"`
+}
+
+// BeatResponse nacos 1.x 心跳的应答
+type BeatResponse struct {
+	ClientBeatInterval int64 `json:"clientBeatInterval"`
+	Code               int   `json:"code"`
+	LightBeatEnabled   bool  `json:"lightBeatEnabled"`
+}
+
+// ConfigKey nacos 的配置标识，tenant 即命名空间
+type ConfigKey struct {
+	DataId string `json:"dataId"`
+	Group  string `json:"group"`
+	Tenant string `json:"tenant"`
+}
+
+// ConfigListenContext nacos 客户端监听的配置及其本地的 md5
+type ConfigListenContext struct {
+	ConfigKey
+	Md5 string `json:"md5"`
+}
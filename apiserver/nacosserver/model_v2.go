@@ -0,0 +1,246 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package nacosserver
+
+// nacos 2.x grpc 请求和应答的类型，即 Payload.Metadata.Type
+const (
+	TypeServerCheckRequest              = "ServerCheckRequest"
+	TypeServerCheckResponse             = "ServerCheckResponse"
+	TypeConnectionSetupRequest          = "ConnectionSetupRequest"
+	TypeHealthCheckRequest              = "HealthCheckRequest"
+	TypeHealthCheckResponse             = "HealthCheckResponse"
+	TypeErrorResponse                   = "ErrorResponse"
+	TypeInstanceRequest                 = "InstanceRequest"
+	TypeInstanceResponse                = "InstanceResponse"
+	TypeBatchInstanceRequest            = "BatchInstanceRequest"
+	TypeBatchInstanceResponse           = "BatchInstanceResponse"
+	TypeServiceQueryRequest             = "ServiceQueryRequest"
+	TypeQueryServiceResponse            = "QueryServiceResponse"
+	TypeSubscribeServiceRequest         = "SubscribeServiceRequest"
+	TypeSubscribeServiceResponse        = "SubscribeServiceResponse"
+	TypeNotifySubscriberRequest         = "NotifySubscriberRequest"
+	TypeConfigQueryRequest              = "ConfigQueryRequest"
+	TypeConfigQueryResponse             = "ConfigQueryResponse"
+	TypeConfigPublishRequest            = "ConfigPublishRequest"
+	TypeConfigPublishResponse           = "ConfigPublishResponse"
+	TypeConfigRemoveRequest             = "ConfigRemoveRequest"
+	TypeConfigRemoveResponse            = "ConfigRemoveResponse"
+	TypeConfigBatchListenRequest        = "ConfigBatchListenRequest"
+	TypeConfigChangeBatchListenResponse = "ConfigChangeBatchListenResponse"
+	TypeConfigChangeNotifyRequest       = "ConfigChangeNotifyRequest"
+
+	// InstanceRequest 和 BatchInstanceRequest 的操作类型
+	RegisterInstance      = "registerInstance"
+	DeregisterInstance    = "deRegisterInstance"
+	BatchRegisterInstance = "batchRegisterInstance"
+
+	ModuleNaming = "naming"
+	ModuleConfig = "config"
+)
+
+// Request nacos 2.x 请求的公共字段
+type Request struct {
+	Headers   map[string]string `json:"headers,omitempty"`
+	RequestId string            `json:"requestId,omitempty"`
+	Module    string            `json:"module,omitempty"`
+}
+
+// Response nacos 2.x 应答的公共字段
+type Response struct {
+	ResultCode int    `json:"resultCode"`
+	ErrorCode  int    `json:"errorCode"`
+	Success    bool   `json:"success"`
+	Message    string `json:"message,omitempty"`
+	RequestId  string `json:"requestId,omitempty"`
+}
+
+// ResponseMessage 所有的应答都包含公共字段
+type ResponseMessage interface {
+	GetResponse() *Response
+}
+
+// GetResponse 获取应答的公共字段
+func (r *Response) GetResponse() *Response {
+	return r
+}
+
+func newSuccessResponse() Response {
+	return Response{ResultCode: CodeSuccess, Success: true}
+}
+
+// ErrorResponse 通用的错误应答
+type ErrorResponse struct {
+	Response
+}
+
+func newErrorResponse(errorCode int, message string) *ErrorResponse {
+	return &ErrorResponse{Response: Response{ResultCode: CodeFail, ErrorCode: errorCode, Message: message}}
+}
+
+// ServerCheckResponse 客户端建立连接前检查服务端是否可用，并获取连接 id
+type ServerCheckResponse struct {
+	Response
+	ConnectionId              string `json:"connectionId"`
+	SupportAbilityNegotiation bool   `json:"supportAbilityNegotiation"`
+}
+
+// ConnectionSetupRequest 双向流建立后客户端发送的第一个请求
+type ConnectionSetupRequest struct {
+	Request
+	ClientVersion string            `json:"clientVersion"`
+	Tenant        string            `json:"tenant"`
+	Labels        map[string]string `json:"labels"`
+}
+
+// HealthCheckResponse 连接保活的应答
+type HealthCheckResponse struct {
+	Response
+}
+
+// InstanceRequest 注册或者反注册单个实例
+type InstanceRequest struct {
+	Request
+	Namespace   string    `json:"namespace"`
+	ServiceName string    `json:"serviceName"`
+	GroupName   string    `json:"groupName"`
+	Type        string    `json:"type"`
+	Instance    *Instance `json:"instance"`
+}
+
+// InstanceResponse 注册或者反注册实例的应答
+type InstanceResponse struct {
+	Response
+	Type string `json:"type"`
+}
+
+// BatchInstanceRequest 批量注册实例
+type BatchInstanceRequest struct {
+	Request
+	Namespace   string      `json:"namespace"`
+	ServiceName string      `json:"serviceName"`
+	GroupName   string      `json:"groupName"`
+	Type        string      `json:"type"`
+	Instances   []*Instance `json:"instances"`
+}
+
+// BatchInstanceResponse 批量注册实例的应答
+type BatchInstanceResponse struct {
+	Response
+	Type string `json:"type"`
+}
+
+// ServiceQueryRequest 查询服务实例
+type ServiceQueryRequest struct {
+	Request
+	Namespace   string `json:"namespace"`
+	ServiceName string `json:"serviceName"`
+	GroupName   string `json:"groupName"`
+	Cluster     string `json:"cluster"`
+	HealthyOnly bool   `json:"healthyOnly"`
+}
+
+// QueryServiceResponse 查询服务实例的应答
+type QueryServiceResponse struct {
+	Response
+	ServiceInfo *ServiceInfo `json:"serviceInfo"`
+}
+
+// SubscribeServiceRequest 订阅或者取消订阅服务
+type SubscribeServiceRequest struct {
+	Request
+	Namespace   string `json:"namespace"`
+	ServiceName string `json:"serviceName"`
+	GroupName   string `json:"groupName"`
+	Subscribe   bool   `json:"subscribe"`
+	Clusters    string `json:"clusters"`
+}
+
+// SubscribeServiceResponse 订阅服务的应答
+type SubscribeServiceResponse struct {
+	Response
+	ServiceInfo *ServiceInfo `json:"serviceInfo"`
+}
+
+// NotifySubscriberRequest 服务实例变更后推送给订阅者
+type NotifySubscriberRequest struct {
+	Request
+	ServiceInfo *ServiceInfo `json:"serviceInfo"`
+}
+
+// ConfigQueryRequest 查询配置
+type ConfigQueryRequest struct {
+	Request
+	ConfigKey
+	Tag string `json:"tag"`
+}
+
+// ConfigQueryResponse 查询配置的应答
+type ConfigQueryResponse struct {
+	Response
+	Content      string `json:"content"`
+	ContentType  string `json:"contentType"`
+	Md5          string `json:"md5"`
+	LastModified int64  `json:"lastModified"`
+	Beta         bool   `json:"beta"`
+	Tag          string `json:"tag,omitempty"`
+}
+
+// ConfigPublishRequest 发布配置
+type ConfigPublishRequest struct {
+	Request
+	ConfigKey
+	Content     string            `json:"content"`
+	CasMd5      string            `json:"casMd5"`
+	AdditionMap map[string]string `json:"additionMap"`
+}
+
+// ConfigPublishResponse 发布配置的应答
+type ConfigPublishResponse struct {
+	Response
+}
+
+// ConfigRemoveRequest 删除配置
+type ConfigRemoveRequest struct {
+	Request
+	ConfigKey
+	Tag string `json:"tag"`
+}
+
+// ConfigRemoveResponse 删除配置的应答
+type ConfigRemoveResponse struct {
+	Response
+}
+
+// ConfigBatchListenRequest 批量监听或者取消监听配置
+type ConfigBatchListenRequest struct {
+	Request
+	Listen               bool                   `json:"listen"`
+	ConfigListenContexts []*ConfigListenContext `json:"configListenContexts"`
+}
+
+// ConfigChangeBatchListenResponse 批量监听配置的应答，返回 md5 已经发生变化的配置
+type ConfigChangeBatchListenResponse struct {
+	Response
+	ChangedConfigs []*ConfigKey `json:"changedConfigs"`
+}
+
+// ConfigChangeNotifyRequest 配置发布后推送给监听者
+type ConfigChangeNotifyRequest struct {
+	Request
+	ConfigKey
+}
@@ -0,0 +1,216 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package nacosserver
+
+import (
+	"context"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang/protobuf/ptypes/wrappers"
+
+	api "github.com/polarismesh/polaris/common/api/v1"
+)
+
+// toPolarisNamespace nacos 的默认命名空间 public 映射为北极星的默认命名空间
+func (n *NacosServer) toPolarisNamespace(namespace string) string {
+	if len(namespace) == 0 || namespace == DefaultNacosNamespace {
+		return n.defaultNamespace
+	}
+	return namespace
+}
+
+// splitGroupedName 服务名中带有分组时，以服务名中的分组为准
+func splitGroupedName(groupName string, serviceName string) (string, string) {
+	if idx := strings.Index(serviceName, GroupServiceSeparator); idx >= 0 {
+		return serviceName[:idx], serviceName[idx+len(GroupServiceSeparator):]
+	}
+	if len(groupName) == 0 {
+		groupName = DefaultGroup
+	}
+	return groupName, serviceName
+}
+
+func getGroupedName(groupName string, serviceName string) string {
+	return groupName + GroupServiceSeparator + serviceName
+}
+
+// toPolarisService 默认分组的服务直接使用服务名，其他分组的服务名为 分组__服务名
+func toPolarisService(groupName string, serviceName string) string {
+	if len(groupName) == 0 || groupName == DefaultGroup {
+		return serviceName
+	}
+	return groupName + PolarisGroupSeparator + serviceName
+}
+
+// heartbeatTTL 根据实例元数据中的心跳间隔计算北极星的心跳 TTL
+func heartbeatTTL(metadata map[string]string) uint32 {
+	ttl := uint32(DefaultHeartbeatInterval / time.Second)
+	if value, ok := metadata[MetadataHeartBeatInterval]; ok {
+		if interval, err := strconv.ParseInt(value, 10, 64); err == nil && interval > 0 {
+			ttl = uint32(math.Ceil(float64(interval) / 1000))
+		}
+	}
+	return ttl
+}
+
+// buildPolarisInstance nacos 实例转换为北极星实例，临时实例通过心跳保持健康
+func buildPolarisInstance(namespace string, groupName string, serviceName string, instance *Instance) *api.Instance {
+	metadata := make(map[string]string, len(instance.Metadata)+2)
+	for k, v := range instance.Metadata {
+		metadata[k] = v
+	}
+	metadata[MetadataRegisterFrom] = ServerNacos
+	if len(instance.ClusterName) > 0 && instance.ClusterName != DefaultCluster {
+		metadata[MetadataNacosCluster] = instance.ClusterName
+	}
+	// nacos 的权重默认为 1，北极星的权重默认为 100
+	weight := math.Round(instance.Weight * 100)
+	weight = math.Max(0, math.Min(weight, 10000))
+
+	targetInstance := &api.Instance{
+		Namespace: &wrappers.StringValue{Value: namespace},
+		Service:   &wrappers.StringValue{Value: toPolarisService(groupName, serviceName)},
+		Host:      &wrappers.StringValue{Value: instance.Ip},
+		Port:      &wrappers.UInt32Value{Value: uint32(instance.Port)},
+		Weight:    &wrappers.UInt32Value{Value: uint32(weight)},
+		Healthy:   &wrappers.BoolValue{Value: instance.Healthy},
+		Isolate:   &wrappers.BoolValue{Value: !instance.Enabled},
+		Metadata:  metadata,
+	}
+	if instance.Ephemeral {
+		targetInstance.EnableHealthCheck = &wrappers.BoolValue{Value: true}
+		targetInstance.HealthCheck = &api.HealthCheck{
+			Type:      api.HealthCheck_HEARTBEAT,
+			Heartbeat: &api.HeartbeatHealthCheck{Ttl: &wrappers.UInt32Value{Value: heartbeatTTL(metadata)}},
+		}
+	}
+	return targetInstance
+}
+
+// toNacosInstance 北极星实例转换为 nacos 实例，groupedName 为带分组的服务名
+func toNacosInstance(instance *api.Instance, groupedName string) *Instance {
+	metadata := make(map[string]string, len(instance.GetMetadata()))
+	clusterName := DefaultCluster
+	for k, v := range instance.GetMetadata() {
+		switch k {
+		case MetadataRegisterFrom:
+		case MetadataNacosCluster:
+			clusterName = v
+		default:
+			metadata[k] = v
+		}
+	}
+	return &Instance{
+		InstanceId:  instance.GetId().GetValue(),
+		Ip:          instance.GetHost().GetValue(),
+		Port:        int(instance.GetPort().GetValue()),
+		Weight:      float64(instance.GetWeight().GetValue()) / 100,
+		Healthy:     instance.GetHealthy().GetValue(),
+		Enabled:     !instance.GetIsolate().GetValue(),
+		Ephemeral:   instance.GetEnableHealthCheck().GetValue(),
+		ClusterName: clusterName,
+		ServiceName: groupedName,
+		Metadata:    metadata,
+	}
+}
+
+// registerInstance 注册实例，服务不存在时先创建服务
+func (n *NacosServer) registerInstance(ctx context.Context, instance *api.Instance) *api.Response {
+	resp := n.namingServer.RegisterInstance(ctx, instance)
+	if resp.GetCode().GetValue() != api.NotFoundResource {
+		return resp
+	}
+	svc := &api.Service{
+		Namespace: instance.GetNamespace(),
+		Name:      instance.GetService(),
+	}
+	svcResp := n.namingServer.CreateServices(ctx, []*api.Service{svc})
+	svcCreateCode := svcResp.GetCode().GetValue()
+	if svcCreateCode != api.ExecuteSuccess && svcCreateCode != api.ExistedResource {
+		return api.NewResponse(svcCreateCode)
+	}
+	return n.namingServer.RegisterInstance(ctx, instance)
+}
+
+// deregisterInstance 按照命名空间、服务、IP 和端口反注册实例，实例不存在也认为成功
+func (n *NacosServer) deregisterInstance(ctx context.Context, instance *api.Instance) *api.Response {
+	resp := n.namingServer.DeregisterInstance(ctx, &api.Instance{
+		Namespace: instance.GetNamespace(),
+		Service:   instance.GetService(),
+		Host:      instance.GetHost(),
+		Port:      instance.GetPort(),
+	})
+	if resp.GetCode().GetValue() == api.NotFoundResource || resp.GetCode().GetValue() == api.NotFoundInstance {
+		return api.NewResponse(api.ExecuteSuccess)
+	}
+	return resp
+}
+
+// heartbeat 上报实例心跳，没有开启心跳的实例也认为成功
+func (n *NacosServer) heartbeat(ctx context.Context, instance *api.Instance) uint32 {
+	resp := n.healthCheckServer.Report(ctx, instance)
+	code := resp.GetCode().GetValue()
+	if code == api.HeartbeatOnDisabledIns {
+		return api.ExecuteSuccess
+	}
+	return code
+}
+
+// buildServiceInfo 从缓存中构建 nacos 的服务信息，name 为返回给客户端的服务名
+func (n *NacosServer) buildServiceInfo(namespace string, groupName string, serviceName string, name string,
+	clusters string, healthyOnly bool) *ServiceInfo {
+	info := &ServiceInfo{
+		Name:        name,
+		GroupName:   groupName,
+		Clusters:    clusters,
+		CacheMillis: DefaultCacheMillis,
+		Hosts:       []*Instance{},
+		LastRefTime: time.Now().UnixNano() / int64(time.Millisecond),
+		Valid:       true,
+	}
+	caches := n.namingServer.Cache()
+	svc := caches.Service().GetServiceByName(toPolarisService(groupName, serviceName), namespace)
+	if svc != nil && svc.IsAlias() {
+		svc = caches.Service().GetServiceByID(svc.Reference)
+	}
+	if svc == nil {
+		return info
+	}
+
+	clusterSet := make(map[string]struct{})
+	for _, cluster := range strings.Split(clusters, ",") {
+		if cluster = strings.TrimSpace(cluster); len(cluster) > 0 {
+			clusterSet[cluster] = struct{}{}
+		}
+	}
+	groupedName := getGroupedName(groupName, serviceName)
+	for _, instance := range caches.Instance().GetInstancesByServiceID(svc.ID) {
+		nacosInstance := toNacosInstance(instance.Proto, groupedName)
+		if _, ok := clusterSet[nacosInstance.ClusterName]; len(clusterSet) > 0 && !ok {
+			continue
+		}
+		if healthyOnly && (!nacosInstance.Healthy || !nacosInstance.Enabled) {
+			continue
+		}
+		info.Hosts = append(info.Hosts, nacosInstance)
+	}
+	return info
+}
@@ -0,0 +1,88 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package nacosserver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitGroupedName(t *testing.T) {
+	group, service := splitGroupedName("", "echo")
+	assert.Equal(t, DefaultGroup, group)
+	assert.Equal(t, "echo", service)
+
+	group, service = splitGroupedName("DEFAULT_GROUP", "order@@echo")
+	assert.Equal(t, "order", group)
+	assert.Equal(t, "echo", service)
+
+	assert.Equal(t, "echo", toPolarisService(DefaultGroup, "echo"))
+	assert.Equal(t, "order__echo", toPolarisService("order", "echo"))
+	assert.Equal(t, "order@@echo", getGroupedName("order", "echo"))
+}
+
+func TestToPolarisNamespace(t *testing.T) {
+	n := &NacosServer{defaultNamespace: DefaultNamespace}
+	assert.Equal(t, DefaultNamespace, n.toPolarisNamespace(""))
+	assert.Equal(t, DefaultNamespace, n.toPolarisNamespace(DefaultNacosNamespace))
+	assert.Equal(t, "dev", n.toPolarisNamespace("dev"))
+}
+
+func TestConvertInstance(t *testing.T) {
+	instance := &Instance{
+		Ip:          "127.0.0.1",
+		Port:        8080,
+		Weight:      1.5,
+		Healthy:     true,
+		Enabled:     false,
+		Ephemeral:   true,
+		ClusterName: "hz",
+		Metadata:    map[string]string{"version": "v1", MetadataHeartBeatInterval: "3500"},
+	}
+	polarisInstance := buildPolarisInstance(DefaultNamespace, "order", "echo", instance)
+	assert.Equal(t, "order__echo", polarisInstance.GetService().GetValue())
+	assert.Equal(t, uint32(150), polarisInstance.GetWeight().GetValue())
+	assert.True(t, polarisInstance.GetIsolate().GetValue())
+	assert.True(t, polarisInstance.GetEnableHealthCheck().GetValue())
+	assert.Equal(t, uint32(4), polarisInstance.GetHealthCheck().GetHeartbeat().GetTtl().GetValue())
+	assert.Equal(t, ServerNacos, polarisInstance.GetMetadata()[MetadataRegisterFrom])
+
+	nacosInstance := toNacosInstance(polarisInstance, "order@@echo")
+	assert.Equal(t, "127.0.0.1", nacosInstance.Ip)
+	assert.Equal(t, 8080, nacosInstance.Port)
+	assert.Equal(t, 1.5, nacosInstance.Weight)
+	assert.False(t, nacosInstance.Enabled)
+	assert.True(t, nacosInstance.Ephemeral)
+	assert.Equal(t, "hz", nacosInstance.ClusterName)
+	assert.Equal(t, "order@@echo", nacosInstance.ServiceName)
+	assert.Equal(t, map[string]string{"version": "v1", MetadataHeartBeatInterval: "3500"}, nacosInstance.Metadata)
+
+	// 持久化实例不开启心跳
+	instance.Ephemeral = false
+	instance.ClusterName = DefaultCluster
+	polarisInstance = buildPolarisInstance(DefaultNamespace, DefaultGroup, "echo", instance)
+	assert.Nil(t, polarisInstance.GetEnableHealthCheck())
+	assert.Equal(t, DefaultCluster, toNacosInstance(polarisInstance, "DEFAULT_GROUP@@echo").ClusterName)
+}
+
+func TestParseMetadata(t *testing.T) {
+	assert.Equal(t, map[string]string{"a": "1"}, parseMetadata(`{"a":"1"}`))
+	assert.Equal(t, map[string]string{"a": "1", "b": "2"}, parseMetadata("a=1,b=2"))
+	assert.Empty(t, parseMetadata(""))
+}
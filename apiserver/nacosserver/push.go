@@ -0,0 +1,279 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package nacosserver
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/polarismesh/polaris/cache"
+	api "github.com/polarismesh/polaris/common/api/v1"
+	"github.com/polarismesh/polaris/common/model"
+	"github.com/polarismesh/polaris/common/utils"
+	"github.com/polarismesh/polaris/config"
+)
+
+// watcherClientId 本节点在配置中心发布监听中的客户端 id，所有的 nacos 监听者共用
+const watcherClientId = "nacos-server"
+
+// ConfigChangeCallback 配置发布后的回调，不能阻塞
+type ConfigChangeCallback func(namespace string, group string, fileName string)
+
+// releaseWatcher 配置中心的发布监听
+type releaseWatcher interface {
+	AddWatcher(clientId string, files []*api.ClientConfigFileInfo, cb config.FileReleaseCallback)
+	RemoveWatcher(clientId string, files []*api.ClientConfigFileInfo)
+}
+
+// ConfigWatcher 统一监听配置中心的发布事件，再分发给 nacos 的长轮询请求和长连接
+type ConfigWatcher struct {
+	releaseWatcher releaseWatcher
+	lock           sync.RWMutex
+	// watchers fileId -> clientId -> callback
+	watchers map[string]map[string]ConfigChangeCallback
+}
+
+// NewConfigWatcher 构造函数，releaseWatcher 为空时只能收到本节点的变更通知
+func NewConfigWatcher(releaseWatcher releaseWatcher) *ConfigWatcher {
+	return &ConfigWatcher{
+		releaseWatcher: releaseWatcher,
+		watchers:       make(map[string]map[string]ConfigChangeCallback),
+	}
+}
+
+// Watch 监听配置文件的发布
+func (w *ConfigWatcher) Watch(clientId string, namespace string, group string, fileName string,
+	cb ConfigChangeCallback) {
+	fileId := utils.GenFileId(namespace, group, fileName)
+
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	callbacks, ok := w.watchers[fileId]
+	if !ok {
+		callbacks = make(map[string]ConfigChangeCallback)
+		w.watchers[fileId] = callbacks
+		if w.releaseWatcher != nil {
+			w.releaseWatcher.AddWatcher(watcherClientId, []*api.ClientConfigFileInfo{
+				toClientConfigFile(namespace, group, fileName)}, w.onRelease)
+		}
+	}
+	callbacks[clientId] = cb
+}
+
+// Unwatch 取消监听配置文件的发布
+func (w *ConfigWatcher) Unwatch(clientId string, namespace string, group string, fileName string) {
+	fileId := utils.GenFileId(namespace, group, fileName)
+
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	callbacks, ok := w.watchers[fileId]
+	if !ok {
+		return
+	}
+	delete(callbacks, clientId)
+	if len(callbacks) > 0 {
+		return
+	}
+	delete(w.watchers, fileId)
+	if w.releaseWatcher != nil {
+		w.releaseWatcher.RemoveWatcher(watcherClientId, []*api.ClientConfigFileInfo{
+			toClientConfigFile(namespace, group, fileName)})
+	}
+}
+
+func (w *ConfigWatcher) onRelease(clientId string, rsp *api.ConfigClientResponse) bool {
+	configFile := rsp.GetConfigFile()
+	w.notifyLocal(configFile.GetNamespace().GetValue(), configFile.GetGroup().GetValue(),
+		configFile.GetFileName().GetValue())
+	return true
+}
+
+// notifyLocal 通知本节点上监听该配置文件的客户端
+func (w *ConfigWatcher) notifyLocal(namespace string, group string, fileName string) {
+	fileId := utils.GenFileId(namespace, group, fileName)
+
+	w.lock.RLock()
+	callbacks := make([]ConfigChangeCallback, 0, len(w.watchers[fileId]))
+	for _, cb := range w.watchers[fileId] {
+		callbacks = append(callbacks, cb)
+	}
+	w.lock.RUnlock()
+
+	for _, cb := range callbacks {
+		cb(namespace, group, fileName)
+	}
+}
+
+func toClientConfigFile(namespace string, group string, fileName string) *api.ClientConfigFileInfo {
+	return &api.ClientConfigFileInfo{
+		Namespace: utils.NewStringValue(namespace),
+		Group:     utils.NewStringValue(group),
+		FileName:  utils.NewStringValue(fileName),
+	}
+}
+
+// subscriber 订阅了服务的 nacos 长连接
+type subscriber struct {
+	conn        *Connection
+	namespace   string
+	groupName   string
+	serviceName string
+	clusters    string
+}
+
+// PushCenter 监听服务和实例缓存的变更，把变更后的服务信息推送给 nacos 2.x 的订阅者
+type PushCenter struct {
+	server *NacosServer
+	lock   sync.Mutex
+	// subscribers 北极星服务 -> 连接 id -> 订阅者
+	subscribers map[model.ServiceKey]map[string]*subscriber
+	// pendingIDs 实例发生变化的服务 id
+	pendingIDs map[string]struct{}
+	// pendingKeys 发生变化的服务
+	pendingKeys map[model.ServiceKey]struct{}
+}
+
+// NewPushCenter 构造函数
+func NewPushCenter(server *NacosServer) *PushCenter {
+	return &PushCenter{
+		server:      server,
+		subscribers: make(map[model.ServiceKey]map[string]*subscriber),
+		pendingIDs:  make(map[string]struct{}),
+		pendingKeys: make(map[model.ServiceKey]struct{}),
+	}
+}
+
+// Subscribe 添加订阅者
+func (p *PushCenter) Subscribe(key model.ServiceKey, sub *subscriber) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	subscribers, ok := p.subscribers[key]
+	if !ok {
+		subscribers = make(map[string]*subscriber)
+		p.subscribers[key] = subscribers
+	}
+	subscribers[sub.conn.ID] = sub
+}
+
+// Unsubscribe 删除订阅者
+func (p *PushCenter) Unsubscribe(key model.ServiceKey, connID string) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	subscribers, ok := p.subscribers[key]
+	if !ok {
+		return
+	}
+	delete(subscribers, connID)
+	if len(subscribers) == 0 {
+		delete(p.subscribers, key)
+	}
+}
+
+// OnCreated callback when cache value created
+func (p *PushCenter) OnCreated(value interface{}) {
+	p.onServiceChanged(value)
+}
+
+// OnUpdated callback when cache value updated
+func (p *PushCenter) OnUpdated(value interface{}) {
+	p.onServiceChanged(value)
+}
+
+// OnDeleted callback when cache value deleted
+func (p *PushCenter) OnDeleted(value interface{}) {
+	p.onServiceChanged(value)
+}
+
+// OnBatchCreated callback when cache value created
+func (p *PushCenter) OnBatchCreated(value interface{}) {
+}
+
+// OnBatchUpdated 实例缓存每一轮更新结束后，会以服务 id 集合的形式通知本轮受影响的服务
+func (p *PushCenter) OnBatchUpdated(value interface{}) {
+	affect, ok := value.(map[string]bool)
+	if !ok {
+		return
+	}
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	for id := range affect {
+		p.pendingIDs[id] = struct{}{}
+	}
+}
+
+// OnBatchDeleted callback when cache value deleted
+func (p *PushCenter) OnBatchDeleted(value interface{}) {
+}
+
+func (p *PushCenter) onServiceChanged(value interface{}) {
+	svc, ok := value.(*model.Service)
+	if !ok {
+		return
+	}
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.pendingKeys[model.ServiceKey{Namespace: svc.Namespace, Name: svc.Name}] = struct{}{}
+}
+
+// takeChanged 取出有订阅者并且发生了变化的服务的订阅者
+func (p *PushCenter) takeChanged() []*subscriber {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	serviceCache := p.server.namingServer.Cache().Service()
+	for id := range p.pendingIDs {
+		if svc := serviceCache.GetServiceByID(id); svc != nil {
+			p.pendingKeys[model.ServiceKey{Namespace: svc.Namespace, Name: svc.Name}] = struct{}{}
+		}
+	}
+	var changed []*subscriber
+	for key := range p.pendingKeys {
+		for _, sub := range p.subscribers[key] {
+			changed = append(changed, sub)
+		}
+	}
+	p.pendingIDs = make(map[string]struct{})
+	p.pendingKeys = make(map[model.ServiceKey]struct{})
+	return changed
+}
+
+// Run 在每一轮缓存更新后合并推送
+func (p *PushCenter) Run(ctx context.Context) {
+	ticker := time.NewTicker(cache.UpdateCacheInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			for _, sub := range p.takeChanged() {
+				p.push(sub)
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (p *PushCenter) push(sub *subscriber) {
+	info := p.server.buildServiceInfo(p.server.toPolarisNamespace(sub.namespace), sub.groupName,
+		sub.serviceName, sub.serviceName, sub.clusters, false)
+	sub.conn.Push(TypeNotifySubscriberRequest, &NotifySubscriberRequest{
+		Request:     Request{RequestId: utils.NewUUID(), Module: ModuleNaming},
+		ServiceInfo: info,
+	})
+}
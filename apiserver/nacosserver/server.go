@@ -0,0 +1,261 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package nacosserver
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	restful "github.com/emicklei/go-restful/v3"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+
+	"github.com/polarismesh/polaris/apiserver"
+	"github.com/polarismesh/polaris/bootstrap"
+	"github.com/polarismesh/polaris/cache"
+	"github.com/polarismesh/polaris/common/utils"
+	"github.com/polarismesh/polaris/config"
+	"github.com/polarismesh/polaris/service"
+	"github.com/polarismesh/polaris/service/healthcheck"
+)
+
+// NacosServer 兼容 nacos 1.x open api 以及 nacos 2.x grpc 协议的注册发现和配置中心服务
+type NacosServer struct {
+	server            *http.Server
+	grpcServer        *grpc.Server
+	namingServer      service.DiscoverServer
+	healthCheckServer *healthcheck.Server
+	configServer      config.ConfigCenterServer
+	option            map[string]interface{}
+	openAPI           map[string]apiserver.APIConfig
+	listenIP          string
+	listenPort        uint32
+	grpcPort          uint32
+	defaultNamespace  string
+	connections       *ConnectionManager
+	pushCenter        *PushCenter
+	configWatcher     *ConfigWatcher
+	grpcHandlers      map[string]*grpcRequestHandler
+	cancel            context.CancelFunc
+	exitCh            chan struct{}
+	start             bool
+	restart           bool
+}
+
+// GetPort 获取端口
+func (n *NacosServer) GetPort() uint32 {
+	return n.listenPort
+}
+
+// GetProtocol 获取协议
+func (n *NacosServer) GetProtocol() string {
+	return ServerNacos
+}
+
+// Initialize 初始化 nacos 服务器
+func (n *NacosServer) Initialize(ctx context.Context, option map[string]interface{},
+	api map[string]apiserver.APIConfig) error {
+	n.listenIP = option[optionListenIP].(string)
+	n.listenPort = uint32(option[optionListenPort].(int))
+	n.grpcPort = n.listenPort + DefaultGrpcPortOffset
+	if value, ok := option[optionGrpcPort].(int); ok && value > 0 {
+		n.grpcPort = uint32(value)
+	}
+	n.defaultNamespace = DefaultNamespace
+	if value, _ := option[optionDefaultNamespace].(string); len(value) > 0 {
+		n.defaultNamespace = value
+	}
+	n.option = option
+	n.openAPI = api
+	return nil
+}
+
+// Run 启动 nacos 服务器，同时监听 http 端口和 grpc 端口
+func (n *NacosServer) Run(errCh chan error) {
+	log.Infof("start NacosServer")
+	n.exitCh = make(chan struct{})
+	n.start = true
+	defer func() {
+		close(n.exitCh)
+		n.start = false
+	}()
+	if err := n.initServers(); err != nil {
+		log.Errorf("%v", err)
+		errCh <- err
+		return
+	}
+
+	address := fmt.Sprintf("%v:%v", n.listenIP, n.listenPort)
+	ln, err := net.Listen("tcp", address)
+	if err != nil {
+		log.Errorf("net listen(%s) err: %s", address, err.Error())
+		errCh <- err
+		return
+	}
+	grpcAddress := fmt.Sprintf("%v:%v", n.listenIP, n.grpcPort)
+	grpcLn, err := net.Listen("tcp", grpcAddress)
+	if err != nil {
+		_ = ln.Close()
+		log.Errorf("net listen(%s) err: %s", grpcAddress, err.Error())
+		errCh <- err
+		return
+	}
+	bootstrap.ApiServerWaitGroup.Done()
+
+	n.connections = NewConnectionManager(n.closeConnection)
+	var ctx context.Context
+	ctx, n.cancel = context.WithCancel(context.Background())
+	go n.pushCenter.Run(ctx)
+	go n.runConnectionHeartbeat(ctx)
+
+	n.grpcServer = grpc.NewServer(grpc.StatsHandler(n.connections))
+	RegisterRequestServer(n.grpcServer, n)
+	RegisterBiRequestStreamServer(n.grpcServer, n)
+	go func() {
+		if err := n.grpcServer.Serve(grpcLn); err != nil {
+			log.Errorf("%+v", err)
+			if !n.restart {
+				errCh <- err
+			}
+		}
+	}()
+
+	n.server = &http.Server{Addr: address, Handler: n.createRestfulContainer(), WriteTimeout: 2 * time.Minute}
+	if err := n.server.Serve(ln); err != nil {
+		log.Errorf("%+v", err)
+		if !n.restart {
+			log.Infof("not in restart progress, broadcast error")
+			errCh <- err
+		}
+		return
+	}
+	log.Infof("nacosserver stop")
+}
+
+// initServers 引入功能模块，配置中心没有开启时只提供注册发现的能力
+func (n *NacosServer) initServers() error {
+	var err error
+	if n.namingServer, err = service.GetServer(); err != nil {
+		return err
+	}
+	if n.healthCheckServer, err = healthcheck.GetServer(); err != nil {
+		return err
+	}
+	// 配置发布监听和缓存监听只能注册一次，重启后复用
+	if n.configWatcher == nil {
+		var watcher releaseWatcher
+		if configServer, err := config.GetServer(); err == nil {
+			n.configServer = configServer
+			originServer, _ := config.GetOriginServer()
+			watcher = originServer.WatchCenter()
+		} else {
+			log.Warnf("config server is not initialized, nacos config api is disabled")
+		}
+		n.configWatcher = NewConfigWatcher(watcher)
+	}
+	if n.pushCenter == nil {
+		n.pushCenter = NewPushCenter(n)
+		listeners := []cache.Listener{n.pushCenter}
+		n.namingServer.Cache().AddListener(cache.CacheNameService, listeners)
+		n.namingServer.Cache().AddListener(cache.CacheNameInstance, listeners)
+	}
+	n.initGrpcHandlers()
+	return nil
+}
+
+// 创建handler
+func (n *NacosServer) createRestfulContainer() *restful.Container {
+	wsContainer := restful.NewContainer()
+	wsContainer.Filter(n.process)
+	wsContainer.Add(n.GetNamingServer())
+	wsContainer.Add(n.GetConfigServer())
+	return wsContainer
+}
+
+// process 打印写请求
+func (n *NacosServer) process(req *restful.Request, rsp *restful.Response, chain *restful.FilterChain) {
+	if req.Request.Method != http.MethodGet && req.Request.URL.Path != "/nacos/v1/ns/instance/beat" &&
+		req.Request.URL.Path != "/nacos/v1/cs/configs/listener" {
+		log.Info("receive request",
+			zap.String("client-address", req.Request.RemoteAddr),
+			zap.String("user-agent", req.HeaderParameter("User-Agent")),
+			zap.String("method", req.Request.Method),
+			zap.String("url", req.Request.URL.String()),
+		)
+	}
+	chain.ProcessFilter(req, rsp)
+}
+
+// newRequestContext 构建请求的上下文，携带鉴权 token 以及客户端地址
+func newRequestContext(token string, clientAddress string) context.Context {
+	ctx := context.WithValue(context.Background(), utils.ContextAuthTokenKey, token)
+	return context.WithValue(ctx, utils.ContextClientAddress, clientAddress)
+}
+
+// Stop 结束 nacos 服务器
+func (n *NacosServer) Stop() {
+	if n.server != nil {
+		_ = n.server.Close()
+	}
+	if n.grpcServer != nil {
+		n.grpcServer.Stop()
+	}
+	if n.cancel != nil {
+		n.cancel()
+		n.cancel = nil
+	}
+}
+
+// Restart 重启 nacos 服务器
+func (n *NacosServer) Restart(
+	option map[string]interface{}, api map[string]apiserver.APIConfig, errCh chan error) error {
+	log.Infof("restart nacosserver new config: %+v", option)
+	// 备份一下option
+	backupOption := n.option
+	// 备份一下api
+	backupAPI := n.openAPI
+
+	// 设置restart标记，防止stop的时候把错误抛出
+	n.restart = true
+	n.Stop()
+	if n.start {
+		<-n.exitCh
+	}
+
+	log.Infof("old nacosserver has stopped, begin restart nacosserver")
+
+	if err := n.Initialize(context.Background(), option, api); err != nil {
+		n.restart = false
+		if initErr := n.Initialize(context.Background(), backupOption, backupAPI); initErr != nil {
+			log.Errorf("start nacosserver with backup cfg err: %s", initErr.Error())
+			return initErr
+		}
+		go n.Run(errCh)
+
+		log.Errorf("restart nacosserver initialize err: %s", err.Error())
+		return err
+	}
+
+	log.Infof("init nacosserver successfully, restart it")
+	n.restart = false
+	go n.Run(errCh)
+	return nil
+}
@@ -0,0 +1,316 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package xdsserverv3
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/url"
+	"sync"
+	"time"
+
+	core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	tlstrans "github.com/envoyproxy/go-control-plane/envoy/extensions/transport_sockets/tls/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/cache/types"
+
+	"github.com/polarismesh/polaris/plugin"
+)
+
+const (
+	// spiffeTrustDomain 工作负载证书的信任域，和入口流量校验的 SAN 前缀保持一致
+	spiffeTrustDomain = "cluster.local"
+
+	rootCASecretName   = "ROOTCA"
+	workloadSecretName = "default"
+
+	defaultCertTTL = 24 * time.Hour
+	rootCertTTL    = 10 * 365 * 24 * time.Hour
+	rootCommonName = "Polaris Mesh Root CA"
+)
+
+// sdsConfig 内置证书签发的配置
+type sdsConfig struct {
+	Enable  bool
+	CertTTL time.Duration
+}
+
+func parseSdsConfig(raw map[interface{}]interface{}) (*sdsConfig, error) {
+	conf := &sdsConfig{CertTTL: defaultCertTTL}
+	conf.Enable, _ = raw["enable"].(bool)
+	if ttl, _ := raw["certTTL"].(string); ttl != "" {
+		d, err := time.ParseDuration(ttl)
+		if err != nil {
+			return nil, fmt.Errorf("invalid sds certTTL %s: %w", ttl, err)
+		}
+		conf.CertTTL = d
+	}
+	if conf.CertTTL <= 0 {
+		return nil, errors.New("sds certTTL must be positive")
+	}
+	return conf, nil
+}
+
+// workloadCert 签发给工作负载的证书
+type workloadCert struct {
+	certPEM []byte
+	keyPEM  []byte
+	// rotateAt 证书有效期过去 2/3 之后重新签发
+	rotateAt time.Time
+}
+
+// certificateAuthority 为网格 mTLS 签发 SPIFFE 格式的工作负载证书，并通过 SDS 下发
+type certificateAuthority struct {
+	certTTL     time.Duration
+	rootCert    *x509.Certificate
+	rootKey     crypto.Signer
+	rootCertPEM []byte
+
+	lock sync.Mutex
+	// certs spiffe id => 已签发的证书
+	certs map[string]*workloadCert
+	now   func() time.Time
+}
+
+// newCertificateAuthority 从 provider 加载根证书，不存在时生成新的根证书并保存；
+// provider 为空时根证书只保存在内存中，重启后会重新生成
+func newCertificateAuthority(certTTL time.Duration, provider plugin.KeyProvider) (*certificateAuthority, error) {
+	ca := &certificateAuthority{
+		certTTL: certTTL,
+		certs:   make(map[string]*workloadCert),
+		now:     time.Now,
+	}
+
+	var certPEM, keyPEM []byte
+	if provider != nil {
+		var err error
+		if certPEM, keyPEM, err = provider.LoadRootCA(); err != nil {
+			return nil, err
+		}
+	}
+	if len(certPEM) != 0 {
+		if err := ca.loadRoot(certPEM, keyPEM); err != nil {
+			return nil, err
+		}
+		return ca, nil
+	}
+
+	certPEM, keyPEM, err := ca.generateRoot()
+	if err != nil {
+		return nil, err
+	}
+	if provider == nil {
+		log.Warnf("[XDSV3] no key provider configured, root ca only kept in memory")
+		return ca, nil
+	}
+	if err := provider.SaveRootCA(certPEM, keyPEM); err != nil {
+		return nil, err
+	}
+	return ca, nil
+}
+
+func (ca *certificateAuthority) loadRoot(certPEM, keyPEM []byte) error {
+	certBlock, _ := pem.Decode(certPEM)
+	if certBlock == nil {
+		return errors.New("invalid root ca certificate")
+	}
+	rootCert, err := x509.ParseCertificate(certBlock.Bytes)
+	if err != nil {
+		return err
+	}
+	keyBlock, _ := pem.Decode(keyPEM)
+	if keyBlock == nil {
+		return errors.New("invalid root ca private key")
+	}
+	rootKey, err := x509.ParsePKCS8PrivateKey(keyBlock.Bytes)
+	if err != nil {
+		return err
+	}
+	signer, ok := rootKey.(crypto.Signer)
+	if !ok {
+		return errors.New("root ca private key can not sign")
+	}
+	ca.rootCert = rootCert
+	ca.rootKey = signer
+	ca.rootCertPEM = certPEM
+	return nil
+}
+
+func (ca *certificateAuthority) generateRoot() ([]byte, []byte, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	serial, err := newSerialNumber()
+	if err != nil {
+		return nil, nil, err
+	}
+	now := ca.now()
+	template := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{CommonName: rootCommonName, Organization: []string{spiffeTrustDomain}},
+		NotBefore:             now.Add(-time.Minute),
+		NotAfter:              now.Add(rootCertTTL),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	if err != nil {
+		return nil, nil, err
+	}
+	keyPEM, err := encodePrivateKey(key)
+	if err != nil {
+		return nil, nil, err
+	}
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	if err := ca.loadRoot(certPEM, keyPEM); err != nil {
+		return nil, nil, err
+	}
+	return certPEM, keyPEM, nil
+}
+
+// spiffeID 工作负载的身份标识
+func spiffeID(namespace, serviceAccount string) string {
+	return fmt.Sprintf("spiffe://%s/ns/%s/sa/%s", spiffeTrustDomain, namespace, serviceAccount)
+}
+
+// issue 签发工作负载证书，已签发的证书在需要轮转之前会被复用，保证快照内容稳定
+func (ca *certificateAuthority) issue(namespace, serviceAccount string) (*workloadCert, error) {
+	id := spiffeID(namespace, serviceAccount)
+
+	ca.lock.Lock()
+	defer ca.lock.Unlock()
+
+	now := ca.now()
+	if cert, ok := ca.certs[id]; ok && now.Before(cert.rotateAt) {
+		return cert, nil
+	}
+
+	uri, err := url.Parse(id)
+	if err != nil {
+		return nil, err
+	}
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	serial, err := newSerialNumber()
+	if err != nil {
+		return nil, err
+	}
+	notAfter := now.Add(ca.certTTL)
+	if notAfter.After(ca.rootCert.NotAfter) {
+		notAfter = ca.rootCert.NotAfter
+	}
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{Organization: []string{spiffeTrustDomain}},
+		NotBefore:    now.Add(-time.Minute),
+		NotAfter:     notAfter,
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		URIs:         []*url.URL{uri},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.rootCert, key.Public(), ca.rootKey)
+	if err != nil {
+		return nil, err
+	}
+	keyPEM, err := encodePrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+	cert := &workloadCert{
+		certPEM:  pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		keyPEM:   keyPEM,
+		rotateAt: now.Add(notAfter.Sub(now) * 2 / 3),
+	}
+	ca.certs[id] = cert
+	log.Infof("[XDSV3] issue workload certificate for %s, expire at %s", id, notAfter.Format(time.RFC3339))
+	return cert, nil
+}
+
+// needRotate 是否有已签发的证书需要轮转
+func (ca *certificateAuthority) needRotate() bool {
+	ca.lock.Lock()
+	defer ca.lock.Unlock()
+
+	now := ca.now()
+	for _, cert := range ca.certs {
+		if !now.Before(cert.rotateAt) {
+			return true
+		}
+	}
+	return false
+}
+
+// makeSecrets 生成服务的 SDS 资源，包括根证书以及以服务名作为身份的工作负载证书，
+// service 为空时只下发根证书
+func (ca *certificateAuthority) makeSecrets(namespace, service string) []types.Resource {
+	rootSecret := &tlstrans.Secret{
+		Name: rootCASecretName,
+		Type: &tlstrans.Secret_ValidationContext{
+			ValidationContext: &tlstrans.CertificateValidationContext{
+				TrustedCa: inlineBytes(ca.rootCertPEM),
+			},
+		},
+	}
+	if service == "" {
+		return []types.Resource{rootSecret}
+	}
+	cert, err := ca.issue(namespace, service)
+	if err != nil {
+		log.Errorf("[XDSV3] issue workload certificate for service %s/%s error %v", namespace, service, err)
+		return []types.Resource{rootSecret}
+	}
+	return []types.Resource{
+		&tlstrans.Secret{
+			Name: workloadSecretName,
+			Type: &tlstrans.Secret_TlsCertificate{
+				TlsCertificate: &tlstrans.TlsCertificate{
+					CertificateChain: inlineBytes(cert.certPEM),
+					PrivateKey:       inlineBytes(cert.keyPEM),
+				},
+			},
+		},
+		rootSecret,
+	}
+}
+
+func inlineBytes(data []byte) *core.DataSource {
+	return &core.DataSource{Specifier: &core.DataSource_InlineBytes{InlineBytes: data}}
+}
+
+func encodePrivateKey(key crypto.PrivateKey) ([]byte, error) {
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), nil
+}
+
+func newSerialNumber() (*big.Int, error) {
+	return rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+}
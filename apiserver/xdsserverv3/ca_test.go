@@ -0,0 +1,165 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package xdsserverv3
+
+import (
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"testing"
+	"time"
+
+	tlstrans "github.com/envoyproxy/go-control-plane/envoy/extensions/transport_sockets/tls/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/cache/v3"
+	resource "github.com/envoyproxy/go-control-plane/pkg/resource/v3"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/atomic"
+
+	"github.com/polarismesh/polaris/common/model"
+	"github.com/polarismesh/polaris/plugin"
+)
+
+// memoryKeyProvider 保存在内存中的根证书
+type memoryKeyProvider struct {
+	certPEM []byte
+	keyPEM  []byte
+	saved   int
+}
+
+func (p *memoryKeyProvider) Name() string                         { return "memory" }
+func (p *memoryKeyProvider) Initialize(*plugin.ConfigEntry) error { return nil }
+func (p *memoryKeyProvider) Destroy() error                       { return nil }
+
+func (p *memoryKeyProvider) LoadRootCA() ([]byte, []byte, error) {
+	return p.certPEM, p.keyPEM, nil
+}
+
+func (p *memoryKeyProvider) SaveRootCA(certPEM []byte, keyPEM []byte) error {
+	p.certPEM, p.keyPEM = certPEM, keyPEM
+	p.saved++
+	return nil
+}
+
+func parseTestCert(t *testing.T, data []byte) *x509.Certificate {
+	block, _ := pem.Decode(data)
+	assert.NotNil(t, block)
+	cert, err := x509.ParseCertificate(block.Bytes)
+	assert.NoError(t, err)
+	return cert
+}
+
+func TestCertificateAuthorityIssue(t *testing.T) {
+	provider := &memoryKeyProvider{}
+	ca, err := newCertificateAuthority(time.Hour, provider)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, provider.saved)
+
+	// 再次启动时复用保存的根证书
+	reloaded, err := newCertificateAuthority(time.Hour, provider)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, provider.saved)
+	assert.Equal(t, ca.rootCertPEM, reloaded.rootCertPEM)
+
+	cert, err := ca.issue("default", "echo")
+	assert.NoError(t, err)
+	leaf := parseTestCert(t, cert.certPEM)
+	assert.Equal(t, 1, len(leaf.URIs))
+	assert.Equal(t, "spiffe://cluster.local/ns/default/sa/echo", leaf.URIs[0].String())
+
+	roots := x509.NewCertPool()
+	roots.AddCert(reloaded.rootCert)
+	_, err = leaf.Verify(x509.VerifyOptions{
+		Roots:     roots,
+		KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	})
+	assert.NoError(t, err)
+
+	// 轮转之前复用已签发的证书
+	again, err := ca.issue("default", "echo")
+	assert.NoError(t, err)
+	assert.Equal(t, cert, again)
+	assert.False(t, ca.needRotate())
+
+	now := time.Now()
+	ca.now = func() time.Time { return now.Add(41 * time.Minute) }
+	assert.True(t, ca.needRotate())
+	rotated, err := ca.issue("default", "echo")
+	assert.NoError(t, err)
+	assert.NotEqual(t, cert.certPEM, rotated.certPEM)
+	assert.False(t, ca.needRotate())
+}
+
+func TestParseSdsConfig(t *testing.T) {
+	conf, err := parseSdsConfig(map[interface{}]interface{}{"enable": true})
+	assert.NoError(t, err)
+	assert.True(t, conf.Enable)
+	assert.Equal(t, defaultCertTTL, conf.CertTTL)
+
+	conf, err = parseSdsConfig(map[interface{}]interface{}{"enable": true, "certTTL": "2h"})
+	assert.NoError(t, err)
+	assert.Equal(t, 2*time.Hour, conf.CertTTL)
+
+	_, err = parseSdsConfig(map[interface{}]interface{}{"certTTL": "abc"})
+	assert.Error(t, err)
+}
+
+func TestSnapshotWithSecrets(t *testing.T) {
+	sis := map[string][]*ServiceInfo{}
+	_ = json.Unmarshal(testServicesData, &sis)
+
+	ca, err := newCertificateAuthority(time.Hour, nil)
+	assert.NoError(t, err)
+	x := XDSServer{
+		CircuitBreakerConfigGetter: func(id string) *model.ServiceWithCircuitBreaker {
+			return nil
+		},
+		RatelimitConfigGetter: func(serviceID string) []*model.RateLimit { return nil },
+		versionNum:            atomic.NewUint64(1),
+		cache:                 cache.NewSnapshotCache(true, cache.IDHash{}, nil),
+		ca:                    ca,
+	}
+	_ = x.pushRegistryInfoToXDSCache(sis)
+
+	// 非 mTLS 模式不下发证书
+	snapshot, _ := x.cache.GetSnapshot("default")
+	assert.Equal(t, 0, len(snapshot.GetResources(resource.SecretType)))
+
+	for _, key := range []string{"default/permissive", "default/strict"} {
+		// 没有声明服务的节点只下发根证书
+		snapshot, _ = x.cache.GetSnapshot(key)
+		secrets := snapshot.GetResources(resource.SecretType)
+		assert.Equal(t, 1, len(secrets))
+		root := secrets[rootCASecretName].(*tlstrans.Secret)
+		assert.Equal(t, ca.rootCertPEM, root.GetValidationContext().GetTrustedCa().GetInlineBytes())
+
+		// 每个服务只下发以自身服务名作为身份的证书
+		snapshot, _ = x.cache.GetSnapshot(key + "/service-a")
+		secrets = snapshot.GetResources(resource.SecretType)
+		assert.Equal(t, 2, len(secrets))
+		workload := secrets[workloadSecretName].(*tlstrans.Secret)
+		leaf := parseTestCert(t, workload.GetTlsCertificate().GetCertificateChain().GetInlineBytes())
+		assert.Equal(t, "spiffe://cluster.local/ns/default/sa/service-a", leaf.URIs[0].String())
+	}
+
+	// 服务删除之后清理对应的快照
+	_ = x.pushRegistryInfoToXDSCache(map[string][]*ServiceInfo{"default": {}})
+	_, err = x.cache.GetSnapshot("default/strict/service-a")
+	assert.Error(t, err)
+	_, err = x.cache.GetSnapshot("default/strict")
+	assert.NoError(t, err)
+}
@@ -27,7 +27,8 @@ import (
 )
 
 type Callbacks struct {
-	log *commonlog.Scope
+	log   *commonlog.Scope
+	nodes *nodeTracker
 }
 
 func (cb *Callbacks) Report() {
@@ -42,6 +43,9 @@ func (cb *Callbacks) OnStreamOpen(_ context.Context, id int64, typ string) error
 }
 
 func (cb *Callbacks) OnStreamClosed(id int64) {
+	if cb.nodes != nil {
+		cb.nodes.onStreamClosed(id)
+	}
 	if cb.log.DebugEnabled() {
 		cb.log.Debugf("stream %d closed", id)
 	}
@@ -61,6 +65,9 @@ func (cb *Callbacks) OnDeltaStreamClosed(id int64) {
 }
 
 func (cb *Callbacks) OnStreamRequest(id int64, req *discovery.DiscoveryRequest) error {
+	if cb.nodes != nil {
+		cb.nodes.onRequest(id, req)
+	}
 	if cb.log.DebugEnabled() {
 		marshaler := jsonpb.Marshaler{}
 		str, _ := marshaler.MarshalToString(req)
@@ -70,6 +77,9 @@ func (cb *Callbacks) OnStreamRequest(id int64, req *discovery.DiscoveryRequest)
 }
 
 func (cb *Callbacks) OnStreamResponse(_ context.Context, id int64, req *discovery.DiscoveryRequest, resp *discovery.DiscoveryResponse) {
+	if cb.nodes != nil {
+		cb.nodes.onResponse(id, resp)
+	}
 	if cb.log.DebugEnabled() {
 		marshaler := jsonpb.Marshaler{}
 		reqstr, _ := marshaler.MarshalToString(req)
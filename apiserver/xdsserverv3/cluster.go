@@ -66,6 +66,9 @@ func (x *XDSServer) makeClusters(services []*ServiceInfo) []types.Resource {
 	// 默认 passthrough cluster
 
 	clusters = append(clusters, passthroughCluster)
+	if x.rls != nil {
+		clusters = append(clusters, x.rls.makeCluster())
+	}
 
 	// 每一个 polaris service 对应一个 envoy cluster
 	for _, service := range services {
@@ -80,6 +83,9 @@ func (x *XDSServer) makePermissiveClusters(services []*ServiceInfo) []types.Reso
 	// 默认 passthrough cluster & inbound cluster
 
 	clusters = append(clusters, passthroughCluster, inboundCluster)
+	if x.rls != nil {
+		clusters = append(clusters, x.rls.makeCluster())
+	}
 
 	// 每一个 polaris service 对应一个 envoy cluster
 	for _, service := range services {
@@ -109,6 +115,9 @@ func (x *XDSServer) makeStrictClusters(services []*ServiceInfo) []types.Resource
 	// 默认 passthrough cluster & inbound cluster
 
 	clusters = append(clusters, passthroughCluster, inboundCluster)
+	if x.rls != nil {
+		clusters = append(clusters, x.rls.makeCluster())
+	}
 
 	// 每一个 polaris service 对应一个 envoy cluster
 	for _, service := range services {
@@ -146,7 +146,7 @@ var inboundCommonTLSContext = &tlstrans.CommonTlsContext{
 				MatchSubjectAltNames: []*matcherv3.StringMatcher{
 					{
 						MatchPattern: &matcherv3.StringMatcher_Prefix{
-							Prefix: "spiffe://cluster.local/",
+							Prefix: "spiffe://" + spiffeTrustDomain + "/",
 						},
 					},
 				},
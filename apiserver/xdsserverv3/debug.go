@@ -0,0 +1,118 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package xdsserverv3
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+)
+
+const (
+	debugNodesPath    = "/debug/xds/nodes"
+	debugSnapshotPath = "/debug/xds/snapshot"
+)
+
+// snapshotDebugInfo 调试接口返回的节点快照
+type snapshotDebugInfo struct {
+	Node        *NodeStatus            `json:"node,omitempty"`
+	SnapshotKey string                 `json:"snapshotKey"`
+	Versions    map[string]string      `json:"versions"`
+	Resources   map[string]interface{} `json:"resources"`
+}
+
+// runDebugServer 启动 xds 调试接口，用于查看连接的 envoy 节点以及下发给节点的快照
+func (x *XDSServer) runDebugServer(errCh chan error) {
+	address := fmt.Sprintf("%v:%v", x.listenIP, x.debugPort)
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		log.Errorf("[XDSV3] debug server listen %s err: %v", address, err)
+		errCh <- err
+		return
+	}
+	x.debugServer = &http.Server{Addr: address, Handler: x.debugHandler(), WriteTimeout: time.Minute}
+
+	log.Infof("xds debug server listening on %d", x.debugPort)
+	if err := x.debugServer.Serve(listener); err != nil && err != http.ErrServerClosed {
+		log.Errorf("[XDSV3] debug server err: %v", err)
+		errCh <- err
+	}
+}
+
+func (x *XDSServer) debugHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(debugNodesPath, x.listNodes)
+	mux.HandleFunc(debugSnapshotPath, x.getNodeSnapshot)
+	return mux
+}
+
+// listNodes 列出所有连接的 envoy 节点以及资源的确认情况
+func (x *XDSServer) listNodes(rsp http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		rsp.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	writeDebugResponse(rsp, req, x.nodes.list())
+}
+
+// getNodeSnapshot 返回正在下发给某个节点的快照，节点未连接时可以通过 key 直接指定快照
+func (x *XDSServer) getNodeSnapshot(rsp http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		rsp.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	info := &snapshotDebugInfo{SnapshotKey: req.URL.Query().Get("key")}
+	if nodeID := req.URL.Query().Get("node"); nodeID != "" {
+		info.Node = x.nodes.get(nodeID)
+		if info.Node == nil {
+			http.Error(rsp, fmt.Sprintf("node %s not connected", nodeID), http.StatusNotFound)
+			return
+		}
+		info.SnapshotKey = info.Node.SnapshotKey
+	}
+	if info.SnapshotKey == "" {
+		http.Error(rsp, "node or key is required", http.StatusBadRequest)
+		return
+	}
+	snapshot, err := x.cache.GetSnapshot(info.SnapshotKey)
+	if err != nil {
+		http.Error(rsp, err.Error(), http.StatusNotFound)
+		return
+	}
+	info.Versions = snapshotVersions(snapshot)
+	info.Resources = snapshotResources(snapshot)
+	writeDebugResponse(rsp, req, info)
+}
+
+// writeDebugResponse 默认返回 json，format=yaml 时返回 yaml
+func writeDebugResponse(rsp http.ResponseWriter, req *http.Request, data interface{}) {
+	if req.URL.Query().Get("format") == "yaml" {
+		rsp.Header().Set("Content-Type", "application/x-yaml")
+		_, _ = rsp.Write(yamlEncode(data))
+		return
+	}
+	body, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		http.Error(rsp, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	rsp.Header().Set("Content-Type", "application/json")
+	_, _ = rsp.Write(body)
+}
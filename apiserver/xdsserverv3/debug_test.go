@@ -0,0 +1,131 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package xdsserverv3
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	discovery "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/cache/v3"
+	resource "github.com/envoyproxy/go-control-plane/pkg/resource/v3"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/atomic"
+	"google.golang.org/genproto/googleapis/rpc/status"
+
+	"github.com/polarismesh/polaris/common/model"
+)
+
+func TestNodeTracker(t *testing.T) {
+	tracker := newNodeTracker()
+	nodeID := "default/9b9f5630-81a1-47cd-a558-036eb616dc71~172.17.1.1"
+
+	// 首个请求携带节点信息
+	tracker.onRequest(1, &discovery.DiscoveryRequest{
+		Node:    &core.Node{Id: nodeID},
+		TypeUrl: resource.ClusterType,
+	})
+	tracker.onResponse(1, &discovery.DiscoveryResponse{TypeUrl: resource.ClusterType, VersionInfo: "v1"})
+	tracker.onRequest(1, &discovery.DiscoveryRequest{
+		TypeUrl:       resource.ClusterType,
+		VersionInfo:   "v1",
+		ResponseNonce: "1",
+	})
+
+	node := tracker.get(nodeID)
+	assert.NotNil(t, node)
+	assert.Equal(t, "default", node.Namespace)
+	assert.Equal(t, "default", node.SnapshotKey)
+	assert.Equal(t, "v1", node.Resources[resource.ClusterType].AckVersion)
+
+	// 拒绝新的版本
+	tracker.onResponse(1, &discovery.DiscoveryResponse{TypeUrl: resource.ClusterType, VersionInfo: "v2"})
+	tracker.onRequest(1, &discovery.DiscoveryRequest{
+		TypeUrl:       resource.ClusterType,
+		VersionInfo:   "v1",
+		ResponseNonce: "2",
+		ErrorDetail:   &status.Status{Message: "invalid cluster"},
+	})
+	node = tracker.get(nodeID)
+	assert.Equal(t, "v1", node.Resources[resource.ClusterType].AckVersion)
+	assert.Equal(t, "v2", node.Resources[resource.ClusterType].NackVersion)
+	assert.Equal(t, "invalid cluster", node.Resources[resource.ClusterType].ErrorDetail)
+
+	// 同一个节点的多个流全部关闭后才移除节点
+	tracker.onRequest(2, &discovery.DiscoveryRequest{Node: &core.Node{Id: nodeID}, TypeUrl: resource.RouteType})
+	assert.Equal(t, 2, tracker.get(nodeID).Streams)
+	tracker.onStreamClosed(1)
+	assert.Equal(t, 1, len(tracker.list()))
+	tracker.onStreamClosed(2)
+	assert.Equal(t, 0, len(tracker.list()))
+}
+
+func TestDebugHandler(t *testing.T) {
+	sis := map[string][]*ServiceInfo{}
+	_ = json.Unmarshal(testServicesData, &sis)
+
+	x := XDSServer{
+		CircuitBreakerConfigGetter: func(id string) *model.ServiceWithCircuitBreaker {
+			return nil
+		},
+		RatelimitConfigGetter: func(serviceID string) []*model.RateLimit { return nil },
+		versionNum:            atomic.NewUint64(1),
+		cache:                 cache.NewSnapshotCache(true, cache.IDHash{}, nil),
+		nodes:                 newNodeTracker(),
+	}
+	_ = x.pushRegistryInfoToXDSCache(sis)
+
+	nodeID := "default/9b9f5630-81a1-47cd-a558-036eb616dc71~172.17.1.1"
+	x.nodes.onRequest(1, &discovery.DiscoveryRequest{Node: &core.Node{Id: nodeID}, TypeUrl: resource.ClusterType})
+
+	handler := x.debugHandler()
+
+	rsp := httptest.NewRecorder()
+	handler.ServeHTTP(rsp, httptest.NewRequest(http.MethodGet, debugNodesPath, nil))
+	assert.Equal(t, http.StatusOK, rsp.Code)
+	var nodes []*NodeStatus
+	assert.NoError(t, json.Unmarshal(rsp.Body.Bytes(), &nodes))
+	assert.Equal(t, 1, len(nodes))
+	assert.Equal(t, nodeID, nodes[0].ID)
+
+	rsp = httptest.NewRecorder()
+	handler.ServeHTTP(rsp, httptest.NewRequest(http.MethodGet, debugSnapshotPath+"?node="+nodeID, nil))
+	assert.Equal(t, http.StatusOK, rsp.Code)
+	info := map[string]interface{}{}
+	assert.NoError(t, json.Unmarshal(rsp.Body.Bytes(), &info))
+	assert.Equal(t, "default", info["snapshotKey"])
+	snapshot, _ := x.cache.GetSnapshot("default")
+	versions := info["versions"].(map[string]interface{})
+	assert.Equal(t, snapshot.GetVersion(resource.ClusterType), versions["clusters"])
+
+	rsp = httptest.NewRecorder()
+	handler.ServeHTTP(rsp, httptest.NewRequest(http.MethodGet, debugSnapshotPath+"?key=default/strict&format=yaml", nil))
+	assert.Equal(t, http.StatusOK, rsp.Code)
+	assert.Equal(t, "application/x-yaml", rsp.Header().Get("Content-Type"))
+
+	rsp = httptest.NewRecorder()
+	handler.ServeHTTP(rsp, httptest.NewRequest(http.MethodGet, debugSnapshotPath+"?node=unknown", nil))
+	assert.Equal(t, http.StatusNotFound, rsp.Code)
+
+	rsp = httptest.NewRecorder()
+	handler.ServeHTTP(rsp, httptest.NewRequest(http.MethodGet, debugSnapshotPath, nil))
+	assert.Equal(t, http.StatusBadRequest, rsp.Code)
+}
@@ -29,25 +29,34 @@ import (
 )
 
 func dumpSnapShot(snapshot cache.ResourceSnapshot) []byte {
-	return yamlEncode(map[string]interface{}{
-		"endpoints": toJSONArray(snapshot.GetResources(res.EndpointType)),
-		"clusters":  toJSONArray(snapshot.GetResources(res.ClusterType)),
-		"routers":   toJSONArray(snapshot.GetResources(res.RouteType)),
-		"listeners": toJSONArray(snapshot.GetResources(res.ListenerType)),
-	})
+	return yamlEncode(snapshotResources(snapshot))
 }
 
 func dumpSnapShotJSON(snapshot cache.ResourceSnapshot) []byte {
-	data, err := json.Marshal(map[string]interface{}{
+	data, err := json.Marshal(snapshotResources(snapshot))
+	if err != nil {
+		return nil
+	}
+	return data
+}
+
+func snapshotResources(snapshot cache.ResourceSnapshot) map[string]interface{} {
+	return map[string]interface{}{
 		"endpoints": toJSONArray(snapshot.GetResources(res.EndpointType)),
 		"clusters":  toJSONArray(snapshot.GetResources(res.ClusterType)),
 		"routers":   toJSONArray(snapshot.GetResources(res.RouteType)),
 		"listeners": toJSONArray(snapshot.GetResources(res.ListenerType)),
-	})
-	if err != nil {
-		return nil
 	}
-	return data
+}
+
+// snapshotVersions 快照中各类资源的版本号
+func snapshotVersions(snapshot cache.ResourceSnapshot) map[string]string {
+	return map[string]string{
+		"endpoints": snapshot.GetVersion(res.EndpointType),
+		"clusters":  snapshot.GetVersion(res.ClusterType),
+		"routers":   snapshot.GetVersion(res.RouteType),
+		"listeners": snapshot.GetVersion(res.ListenerType),
+	}
 }
 
 func yamlEncode(any interface{}) []byte {
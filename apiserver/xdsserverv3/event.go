@@ -0,0 +1,171 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package xdsserverv3
+
+import (
+	"sync"
+
+	apiv2 "github.com/polarismesh/polaris/common/api/v2"
+	"github.com/polarismesh/polaris/common/model"
+	v2 "github.com/polarismesh/polaris/common/model/v2"
+	routingcommon "github.com/polarismesh/polaris/common/routing"
+)
+
+// pendingChanges 两次推送之间累积的变更
+type pendingChanges struct {
+	// all 需要重建所有的命名空间
+	all bool
+	// namespaces 需要整体重建的命名空间
+	namespaces map[string]struct{}
+	// services 发生变化的服务，service id -> namespace，namespace 未知时为空
+	services map[string]string
+	// names 通过服务名关联到的服务，由同步任务解析为服务 id
+	names map[model.ServiceKey]struct{}
+}
+
+func newPendingChanges() *pendingChanges {
+	return &pendingChanges{
+		namespaces: map[string]struct{}{},
+		services:   map[string]string{},
+		names:      map[model.ServiceKey]struct{}{},
+	}
+}
+
+func (p *pendingChanges) isEmpty() bool {
+	return !p.all && len(p.namespaces) == 0 && len(p.services) == 0 && len(p.names) == 0
+}
+
+// changeCollector 监听服务、实例、路由规则以及限流规则缓存的变更事件，只记录受影响的服务，
+// 由同步任务合并后只重建并推送这些服务所在的命名空间
+type changeCollector struct {
+	lock    sync.Mutex
+	pending *pendingChanges
+}
+
+func newChangeCollector() *changeCollector {
+	return &changeCollector{
+		pending: newPendingChanges(),
+	}
+}
+
+// take 取出当前累积的变更，并重新开始记录
+func (c *changeCollector) take() *pendingChanges {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	ret := c.pending
+	c.pending = newPendingChanges()
+	return ret
+}
+
+// OnCreated callback when cache value created
+func (c *changeCollector) OnCreated(value interface{}) {
+	c.onChanged(value, false)
+}
+
+// OnUpdated callback when cache value updated
+func (c *changeCollector) OnUpdated(value interface{}) {
+	c.onChanged(value, true)
+}
+
+// OnDeleted callback when cache value deleted
+func (c *changeCollector) OnDeleted(value interface{}) {
+	c.onChanged(value, false)
+}
+
+// OnBatchCreated callback when cache value created
+func (c *changeCollector) OnBatchCreated(value interface{}) {
+}
+
+// OnBatchUpdated 实例缓存每一轮更新结束后，会以服务 id 集合的形式通知本轮受影响的服务
+func (c *changeCollector) OnBatchUpdated(value interface{}) {
+	affect, ok := value.(map[string]bool)
+	if !ok {
+		return
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	for id := range affect {
+		c.markService(id, "")
+	}
+}
+
+// OnBatchDeleted callback when cache value deleted
+func (c *changeCollector) OnBatchDeleted(value interface{}) {
+}
+
+func (c *changeCollector) onChanged(value interface{}, update bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	switch item := value.(type) {
+	case *model.Service:
+		c.markService(item.ID, item.Namespace)
+	case *model.RateLimit:
+		c.markService(item.ServiceID, "")
+	case *model.RoutingConfig:
+		// v1 的路由规则 id 就是服务 id
+		c.markService(item.ID, "")
+	case *v2.ExtendRoutingConfig:
+		if item.GetRoutingPolicy() != apiv2.RoutingPolicy_RulePolicy {
+			return
+		}
+		if update {
+			// 无法得知规则更新前关联的服务，只能重建所有的命名空间
+			c.pending.all = true
+			return
+		}
+		for _, source := range item.RuleRouting.GetSources() {
+			c.markServiceName(source.GetNamespace(), source.GetService())
+		}
+		for _, destination := range item.RuleRouting.GetDestinations() {
+			c.markServiceName(destination.GetNamespace(), destination.GetService())
+		}
+	case *v2.ExtendFaultMirrorRule:
+		if update {
+			// 规则更新时不允许修改命名空间，但无法得知更新前关联的服务
+			c.pending.namespaces[item.Namespace] = struct{}{}
+			return
+		}
+		c.markServiceName(item.Namespace, item.Service)
+	default:
+		// 单个实例的变更，由 OnBatchUpdated 统一处理
+	}
+}
+
+func (c *changeCollector) markService(id, namespace string) {
+	if id == "" {
+		return
+	}
+	if ns, ok := c.pending.services[id]; ok && ns != "" {
+		return
+	}
+	c.pending.services[id] = namespace
+}
+
+func (c *changeCollector) markServiceName(namespace, service string) {
+	switch {
+	case namespace == routingcommon.MatchAll:
+		c.pending.all = true
+	case service == routingcommon.MatchAll:
+		c.pending.namespaces[namespace] = struct{}{}
+	default:
+		c.pending.names[model.ServiceKey{Namespace: namespace, Name: service}] = struct{}{}
+	}
+}
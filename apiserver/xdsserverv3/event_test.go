@@ -0,0 +1,72 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package xdsserverv3
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	apiv2 "github.com/polarismesh/polaris/common/api/v2"
+	"github.com/polarismesh/polaris/common/model"
+	v2 "github.com/polarismesh/polaris/common/model/v2"
+)
+
+func TestChangeCollector(t *testing.T) {
+	c := newChangeCollector()
+	assert.True(t, c.take().isEmpty())
+
+	c.OnCreated(&model.Instance{ServiceID: "ignored"})
+	c.OnBatchUpdated(map[string]bool{"svc-1": true})
+	c.OnDeleted(&model.Service{ID: "svc-2", Namespace: "ns-1"})
+	c.OnUpdated(&model.RateLimit{ServiceID: "svc-3"})
+	c.OnCreated(&model.RoutingConfig{ID: "svc-4"})
+	c.OnCreated(&v2.ExtendRoutingConfig{
+		RoutingConfig: &v2.RoutingConfig{Policy: apiv2.RoutingPolicy_RulePolicy.String()},
+		RuleRouting: &apiv2.RuleRoutingConfig{
+			Sources:      []*apiv2.Source{{Namespace: "ns-2", Service: "*"}},
+			Destinations: []*apiv2.Destination{{Namespace: "ns-3", Service: "svc-5"}},
+		},
+	})
+
+	changes := c.take()
+	assert.False(t, changes.all)
+	assert.Equal(t, map[string]string{"svc-1": "", "svc-2": "ns-1", "svc-3": "", "svc-4": ""}, changes.services)
+	assert.Equal(t, map[string]struct{}{"ns-2": {}}, changes.namespaces)
+	assert.Equal(t, map[model.ServiceKey]struct{}{{Namespace: "ns-3", Name: "svc-5"}: {}}, changes.names)
+	assert.True(t, c.take().isEmpty())
+
+	// 规则更新时无法得知更新前关联的服务
+	c.OnUpdated(&v2.ExtendRoutingConfig{
+		RoutingConfig: &v2.RoutingConfig{Policy: apiv2.RoutingPolicy_RulePolicy.String()},
+		RuleRouting:   &apiv2.RuleRoutingConfig{},
+	})
+	assert.True(t, c.take().all)
+
+	// 故障注入与流量镜像规则更新时重建规则所在的命名空间
+	c.OnCreated(&v2.ExtendFaultMirrorRule{
+		FaultMirrorRule: &v2.FaultMirrorRule{Namespace: "ns-4", Service: "svc-6"},
+	})
+	c.OnUpdated(&v2.ExtendFaultMirrorRule{
+		FaultMirrorRule: &v2.FaultMirrorRule{Namespace: "ns-5", Service: "svc-7"},
+	})
+	changes = c.take()
+	assert.False(t, changes.all)
+	assert.Equal(t, map[string]struct{}{"ns-5": {}}, changes.namespaces)
+	assert.Equal(t, map[model.ServiceKey]struct{}{{Namespace: "ns-4", Name: "svc-6"}: {}}, changes.names)
+}
@@ -0,0 +1,163 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package xdsserverv3
+
+import (
+	"time"
+
+	core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	route "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	faultcommon "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/common/fault/v3"
+	faultfilter "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/fault/v3"
+	hcm "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
+	envoy_type_v3 "github.com/envoyproxy/go-control-plane/envoy/type/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/wellknown"
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"google.golang.org/protobuf/types/known/anypb"
+
+	apiv2 "github.com/polarismesh/polaris/common/api/v2"
+)
+
+// hasFaultInjection 判断服务列表中是否存在需要故障注入的规则，存在时才需要挂载 fault filter
+func hasFaultInjection(services []*ServiceInfo) bool {
+	for _, serviceInfo := range services {
+		for _, rule := range serviceInfo.FaultMirrors {
+			if makeHTTPFault(rule.GetFault()) != nil {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// makeFaultHTTPFilter 故障注入的 http filter，本身不注入故障，具体的故障由路由上的配置决定
+func makeFaultHTTPFilter() *hcm.HttpFilter {
+	return &hcm.HttpFilter{
+		Name:       wellknown.Fault,
+		ConfigType: &hcm.HttpFilter_TypedConfig{TypedConfig: mustNewAny(&faultfilter.HTTPFault{})},
+	}
+}
+
+// makeFaultMirrorRoutes 将故障注入与流量镜像规则挂载到服务的路由上。
+// 带有匹配条件的规则生成独立的路由并放在最前面，目标沿用兜底路由的目标；
+// 不带匹配条件的规则只取第一条，作用于其余所有的路由
+func makeFaultMirrorRoutes(serviceInfo *ServiceInfo, routes []*route.Route) []*route.Route {
+	if len(serviceInfo.FaultMirrors) == 0 || len(routes) == 0 {
+		return routes
+	}
+
+	fallback := routes[len(routes)-1]
+	var (
+		matched  []*route.Route
+		matchAll *apiv2.FaultMirrorRule
+	)
+	for _, rule := range serviceInfo.FaultMirrors {
+		if len(rule.GetSources()) == 0 && matchAll == nil {
+			matchAll = rule
+		}
+		for _, source := range rule.GetSources() {
+			if len(source.GetArguments()) == 0 {
+				if matchAll == nil {
+					matchAll = rule
+				}
+				continue
+			}
+			routeMatch, ok := makeRouteMatchV2(source.GetArguments())
+			if !ok {
+				continue
+			}
+			item := proto.Clone(fallback).(*route.Route)
+			item.Match = routeMatch
+			applyFaultMirrorRule(serviceInfo, item, rule)
+			matched = append(matched, item)
+		}
+	}
+
+	if matchAll != nil {
+		for _, item := range routes {
+			applyFaultMirrorRule(serviceInfo, item, matchAll)
+		}
+	}
+	return append(matched, routes...)
+}
+
+// applyFaultMirrorRule 在路由上设置故障注入配置以及流量镜像策略
+func applyFaultMirrorRule(serviceInfo *ServiceInfo, item *route.Route, rule *apiv2.FaultMirrorRule) {
+	if fault := makeHTTPFault(rule.GetFault()); fault != nil {
+		if item.TypedPerFilterConfig == nil {
+			item.TypedPerFilterConfig = map[string]*anypb.Any{}
+		}
+		item.TypedPerFilterConfig[wellknown.Fault] = mustNewAny(fault)
+	}
+
+	action := item.GetRoute()
+	if action == nil {
+		return
+	}
+	for _, mirror := range rule.GetMirrors() {
+		// 快照按命名空间生成，只能镜像到同一个命名空间下的服务
+		if mirror.GetNamespace() != "" && mirror.GetNamespace() != serviceInfo.Namespace {
+			log.Warnf("[XDSV3] fault mirror rule %s mirror to %s/%s in other namespace is not supported",
+				rule.GetId(), mirror.GetNamespace(), mirror.GetService())
+			continue
+		}
+		policy := &route.RouteAction_RequestMirrorPolicy{
+			Cluster: mirror.GetService(),
+		}
+		// 未设置比例时镜像全部的请求
+		if mirror.GetPercentage() > 0 {
+			policy.RuntimeFraction = &core.RuntimeFractionalPercent{
+				DefaultValue: makeHundredPercent(mirror.GetPercentage()),
+			}
+		}
+		action.RequestMirrorPolicies = append(action.RequestMirrorPolicies, policy)
+	}
+}
+
+// makeHTTPFault 转换为 envoy 的故障注入配置，没有需要注入的故障时返回 nil
+func makeHTTPFault(fault *apiv2.FaultInjection) *faultfilter.HTTPFault {
+	delay, abort := fault.GetDelay(), fault.GetAbort()
+	if delay.GetPercentage() == 0 && abort.GetPercentage() == 0 {
+		return nil
+	}
+
+	out := &faultfilter.HTTPFault{}
+	if delay.GetPercentage() > 0 {
+		out.Delay = &faultcommon.FaultDelay{
+			FaultDelaySecifier: &faultcommon.FaultDelay_FixedDelay{
+				FixedDelay: ptypes.DurationProto(time.Duration(delay.GetFixedDelay()) * time.Millisecond),
+			},
+			Percentage: makeHundredPercent(delay.GetPercentage()),
+		}
+	}
+	if abort.GetPercentage() > 0 {
+		out.Abort = &faultfilter.FaultAbort{
+			ErrorType:  &faultfilter.FaultAbort_HttpStatus{HttpStatus: abort.GetHttpStatus()},
+			Percentage: makeHundredPercent(abort.GetPercentage()),
+		}
+	}
+	return out
+}
+
+func makeHundredPercent(percentage uint32) *envoy_type_v3.FractionalPercent {
+	return &envoy_type_v3.FractionalPercent{
+		Numerator:   percentage,
+		Denominator: envoy_type_v3.FractionalPercent_HUNDRED,
+	}
+}
@@ -0,0 +1,113 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package xdsserverv3
+
+import (
+	"testing"
+	"time"
+
+	route "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	faultfilter "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/fault/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/wellknown"
+	"github.com/stretchr/testify/assert"
+
+	apiv2 "github.com/polarismesh/polaris/common/api/v2"
+	"github.com/polarismesh/polaris/common/utils"
+)
+
+func TestMakeFaultMirrorRoutes(t *testing.T) {
+	headerRule := &apiv2.FaultMirrorRule{
+		Id: "header",
+		Sources: []*apiv2.Source{
+			{
+				Arguments: []*apiv2.SourceMatch{
+					{
+						Type:  apiv2.SourceMatch_HEADER,
+						Key:   "x-test",
+						Value: &apiv2.MatchString{Value: utils.NewStringValue("true")},
+					},
+				},
+			},
+		},
+		Fault: &apiv2.FaultInjection{
+			Abort: &apiv2.FaultInjection_Abort{Percentage: 100, HttpStatus: 503},
+		},
+	}
+	matchAllRule := &apiv2.FaultMirrorRule{
+		Id: "match-all",
+		Fault: &apiv2.FaultInjection{
+			Delay: &apiv2.FaultInjection_Delay{Percentage: 10, FixedDelay: 200},
+		},
+		Mirrors: []*apiv2.MirrorDestination{
+			{Service: "svc-shadow", Namespace: "default", Percentage: 50},
+			{Service: "svc-other", Namespace: "other"},
+		},
+	}
+	serviceInfo := &ServiceInfo{
+		Name:         "svc",
+		Namespace:    "default",
+		FaultMirrors: []*apiv2.FaultMirrorRule{headerRule, matchAllRule},
+	}
+	assert.True(t, hasFaultInjection([]*ServiceInfo{serviceInfo}))
+
+	routes := makeFaultMirrorRoutes(serviceInfo, makeRoutes(serviceInfo))
+	assert.Equal(t, 2, len(routes))
+
+	// 带匹配条件的规则生成独立的路由，目标沿用兜底路由
+	assert.Equal(t, "x-test", routes[0].Match.Headers[0].Name)
+	assert.Equal(t, "svc", routes[0].GetRoute().GetCluster())
+	assert.Equal(t, 0, len(routes[0].GetRoute().RequestMirrorPolicies))
+	fault := &faultfilter.HTTPFault{}
+	assert.NoError(t, routes[0].TypedPerFilterConfig[wellknown.Fault].UnmarshalTo(fault))
+	assert.Nil(t, fault.Delay)
+	assert.Equal(t, uint32(503), fault.Abort.GetHttpStatus())
+	assert.Equal(t, uint32(100), fault.Abort.Percentage.Numerator)
+
+	// 不带匹配条件的规则作用于原有的路由，跨命名空间的镜像目标被忽略
+	_, ok := routes[1].Match.PathSpecifier.(*route.RouteMatch_Prefix)
+	assert.True(t, ok)
+	fault = &faultfilter.HTTPFault{}
+	assert.NoError(t, routes[1].TypedPerFilterConfig[wellknown.Fault].UnmarshalTo(fault))
+	assert.Nil(t, fault.Abort)
+	assert.Equal(t, 200*time.Millisecond, fault.Delay.GetFixedDelay().AsDuration())
+	assert.Equal(t, uint32(10), fault.Delay.Percentage.Numerator)
+	mirrors := routes[1].GetRoute().RequestMirrorPolicies
+	assert.Equal(t, 1, len(mirrors))
+	assert.Equal(t, "svc-shadow", mirrors[0].Cluster)
+	assert.Equal(t, uint32(50), mirrors[0].RuntimeFraction.DefaultValue.Numerator)
+}
+
+func TestMakeFaultMirrorRoutesOnlyMirror(t *testing.T) {
+	serviceInfo := &ServiceInfo{
+		Name:      "svc",
+		Namespace: "default",
+		FaultMirrors: []*apiv2.FaultMirrorRule{
+			{Id: "mirror", Mirrors: []*apiv2.MirrorDestination{{Service: "svc-shadow"}}},
+		},
+	}
+	// 只有流量镜像时不需要挂载 fault filter
+	assert.False(t, hasFaultInjection([]*ServiceInfo{serviceInfo}))
+
+	routes := makeFaultMirrorRoutes(serviceInfo, makeRoutes(serviceInfo))
+	assert.Equal(t, 1, len(routes))
+	assert.Nil(t, routes[0].TypedPerFilterConfig)
+	mirrors := routes[0].GetRoute().RequestMirrorPolicies
+	assert.Equal(t, 1, len(mirrors))
+	// 未设置比例时镜像全部的请求
+	assert.Nil(t, mirrors[0].RuntimeFraction)
+}
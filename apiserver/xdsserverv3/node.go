@@ -0,0 +1,195 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package xdsserverv3
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	discovery "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
+)
+
+// ResourceStatus envoy 节点对某一类资源的确认情况
+type ResourceStatus struct {
+	// SentVersion 最近一次下发的版本
+	SentVersion string `json:"sentVersion"`
+	// AckVersion 最近一次确认接收的版本
+	AckVersion string `json:"ackVersion"`
+	// NackVersion 最近一次拒绝的版本，为空时表示没有被拒绝
+	NackVersion string `json:"nackVersion,omitempty"`
+	// ErrorDetail 拒绝的原因
+	ErrorDetail string    `json:"errorDetail,omitempty"`
+	UpdateTime  time.Time `json:"updateTime"`
+}
+
+// NodeStatus 连接到 xds server 的 envoy 节点
+type NodeStatus struct {
+	ID        string `json:"id"`
+	Namespace string `json:"namespace"`
+	// SnapshotKey 节点实际使用的快照
+	SnapshotKey string `json:"snapshotKey"`
+	Cluster     string `json:"cluster,omitempty"`
+	// Streams 节点当前打开的 xds 流数量
+	Streams     int       `json:"streams"`
+	ConnectTime time.Time `json:"connectTime"`
+	// Resources 资源类型 => 确认情况
+	Resources map[string]*ResourceStatus `json:"resources"`
+}
+
+// nodeTracker 根据 xds 流上的请求记录 envoy 节点的状态
+type nodeTracker struct {
+	lock sync.RWMutex
+	// streams 流 id => 节点 id
+	streams map[int64]string
+	nodes   map[string]*NodeStatus
+}
+
+func newNodeTracker() *nodeTracker {
+	return &nodeTracker{
+		streams: make(map[int64]string),
+		nodes:   make(map[string]*NodeStatus),
+	}
+}
+
+// onRequest envoy 只在流上的第一个请求中携带节点信息，之后通过流 id 关联
+func (t *nodeTracker) onRequest(streamID int64, req *discovery.DiscoveryRequest) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	nodeID, ok := t.streams[streamID]
+	if !ok {
+		if req.GetNode().GetId() == "" {
+			return
+		}
+		nodeID = req.GetNode().GetId()
+		t.streams[streamID] = nodeID
+		t.attachNode(req.GetNode())
+	}
+	status := t.nodes[nodeID]
+	if status == nil || req.GetResponseNonce() == "" {
+		// 首次订阅，不是对下发内容的应答
+		return
+	}
+
+	resource := status.resource(req.GetTypeUrl())
+	resource.UpdateTime = time.Now()
+	if req.GetErrorDetail() != nil {
+		resource.NackVersion = resource.SentVersion
+		resource.ErrorDetail = req.GetErrorDetail().GetMessage()
+		return
+	}
+	resource.AckVersion = req.GetVersionInfo()
+	resource.NackVersion = ""
+	resource.ErrorDetail = ""
+}
+
+func (t *nodeTracker) attachNode(node *core.Node) {
+	status, ok := t.nodes[node.GetId()]
+	if !ok {
+		ns, _, _ := parseNodeID(node.GetId())
+		status = &NodeStatus{
+			ID:          node.GetId(),
+			Namespace:   ns,
+			SnapshotKey: PolarisNodeHash{}.ID(node),
+			Cluster:     node.GetCluster(),
+			ConnectTime: time.Now(),
+			Resources:   make(map[string]*ResourceStatus),
+		}
+		t.nodes[node.GetId()] = status
+	}
+	status.Streams++
+}
+
+func (t *nodeTracker) onResponse(streamID int64, resp *discovery.DiscoveryResponse) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	status := t.nodes[t.streams[streamID]]
+	if status == nil {
+		return
+	}
+	resource := status.resource(resp.GetTypeUrl())
+	resource.SentVersion = resp.GetVersionInfo()
+	resource.UpdateTime = time.Now()
+}
+
+func (t *nodeTracker) onStreamClosed(streamID int64) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	nodeID, ok := t.streams[streamID]
+	if !ok {
+		return
+	}
+	delete(t.streams, streamID)
+	status := t.nodes[nodeID]
+	if status == nil {
+		return
+	}
+	status.Streams--
+	if status.Streams <= 0 {
+		delete(t.nodes, nodeID)
+	}
+}
+
+// list 返回所有节点状态的拷贝，按照节点 id 排序
+func (t *nodeTracker) list() []*NodeStatus {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	ret := make([]*NodeStatus, 0, len(t.nodes))
+	for _, status := range t.nodes {
+		ret = append(ret, status.clone())
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].ID < ret[j].ID
+	})
+	return ret
+}
+
+func (t *nodeTracker) get(nodeID string) *NodeStatus {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	status, ok := t.nodes[nodeID]
+	if !ok {
+		return nil
+	}
+	return status.clone()
+}
+
+func (s *NodeStatus) resource(typeURL string) *ResourceStatus {
+	resource, ok := s.Resources[typeURL]
+	if !ok {
+		resource = &ResourceStatus{}
+		s.Resources[typeURL] = resource
+	}
+	return resource
+}
+
+func (s *NodeStatus) clone() *NodeStatus {
+	ret := *s
+	ret.Resources = make(map[string]*ResourceStatus, len(s.Resources))
+	for typeURL, resource := range s.Resources {
+		copied := *resource
+		ret.Resources[typeURL] = &copied
+	}
+	return &ret
+}
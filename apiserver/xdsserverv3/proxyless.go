@@ -0,0 +1,188 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package xdsserverv3
+
+import (
+	"sort"
+	"strings"
+
+	cluster "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	endpoint "github.com/envoyproxy/go-control-plane/envoy/config/endpoint/v3"
+	listener "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
+	route "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	routerv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/router/v3"
+	hcm "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/cache/types"
+	"github.com/envoyproxy/go-control-plane/pkg/resource/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/wellknown"
+	"github.com/golang/protobuf/ptypes/wrappers"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// ProxylessTag 节点元数据中标识 proxyless gRPC 客户端的标签，取值为 true 时下发 API listener
+	ProxylessTag = "polarismesh.cn/proxyless"
+
+	proxylessKeySuffix = "/proxyless"
+)
+
+// makeProxylessSnapshot 生成 proxyless gRPC 客户端使用的快照。gRPC 通过 xds:///{domain} 订阅同名的
+// API listener，并且不支持 subset 负载均衡，路由中的每个 subset 会被转换为独立的 cluster。
+// 快照一致性校验不会解析 API listener 中的 RDS 引用，因此路由配置直接内联在 listener 中
+func (x *XDSServer) makeProxylessSnapshot(ns, version string, services []*ServiceInfo) (err error) {
+	var (
+		listeners []types.Resource
+		clusters  []types.Resource
+		endpoints []types.Resource
+	)
+	for _, serviceInfo := range services {
+		routeConfig, subsets := makeProxylessRouteConfig(serviceInfo)
+		listeners = append(listeners, makeProxylessListeners(serviceInfo, routeConfig)...)
+
+		c := x.makeCluster(serviceInfo)
+		c.LbSubsetConfig = nil
+		clusters = append(clusters, c)
+		endpoints = append(endpoints, makeProxylessEndpoints(serviceInfo.Name, serviceInfo, nil))
+
+		names := make([]string, 0, len(subsets))
+		for name := range subsets {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			subsetCluster := proto.Clone(c).(*cluster.Cluster)
+			subsetCluster.Name = name
+			subsetCluster.EdsClusterConfig.ServiceName = name
+			clusters = append(clusters, subsetCluster)
+			endpoints = append(endpoints, makeProxylessEndpoints(name, serviceInfo, subsets[name]))
+		}
+	}
+
+	resources := make(map[resource.Type][]types.Resource)
+	resources[resource.EndpointType] = endpoints
+	resources[resource.ClusterType] = clusters
+	resources[resource.ListenerType] = listeners
+	return x.setSnapshot(ns+proxylessKeySuffix, version, resources)
+}
+
+// makeProxylessListeners 服务的每一个域名对应一个 API listener
+func makeProxylessListeners(serviceInfo *ServiceInfo, routeConfig *route.RouteConfiguration) []types.Resource {
+	manager := &hcm.HttpConnectionManager{
+		RouteSpecifier: &hcm.HttpConnectionManager_RouteConfig{
+			RouteConfig: routeConfig,
+		},
+		// gRPC 要求 http filter 必须携带 typed config
+		HttpFilters: []*hcm.HttpFilter{{
+			Name: wellknown.Router,
+			ConfigType: &hcm.HttpFilter_TypedConfig{
+				TypedConfig: mustNewAny(&routerv3.Router{}),
+			},
+		}},
+	}
+
+	domains := generateServiceDomains(serviceInfo)
+	listeners := make([]types.Resource, 0, len(domains))
+	for _, domain := range domains {
+		listeners = append(listeners, &listener.Listener{
+			Name: domain,
+			ApiListener: &listener.ApiListener{
+				ApiListener: mustNewAny(manager),
+			},
+		})
+	}
+	return listeners
+}
+
+// makeProxylessRouteConfig 生成服务的路由配置，返回路由中用到的 subset cluster 及其实例标签
+func makeProxylessRouteConfig(serviceInfo *ServiceInfo) (*route.RouteConfiguration, map[string]map[string]string) {
+	subsets := make(map[string]map[string]string)
+	routes := makeRoutes(serviceInfo)
+	for _, r := range routes {
+		weightedClusters := r.GetRoute().GetWeightedClusters()
+		if weightedClusters == nil {
+			continue
+		}
+		for _, clusterWeight := range weightedClusters.Clusters {
+			fields := clusterWeight.GetMetadataMatch().GetFilterMetadata()["envoy.lb"].GetFields()
+			if len(fields) == 0 {
+				continue
+			}
+			labels := make(map[string]string, len(fields))
+			for key, value := range fields {
+				labels[key] = value.GetStringValue()
+			}
+			name := subsetClusterName(serviceInfo.Name, labels)
+			subsets[name] = labels
+			clusterWeight.Name = name
+			clusterWeight.MetadataMatch = nil
+		}
+	}
+
+	return &route.RouteConfiguration{
+		Name:             serviceInfo.Name,
+		ValidateClusters: &wrappers.BoolValue{Value: false},
+		VirtualHosts: []*route.VirtualHost{
+			{
+				Name:    serviceInfo.Name,
+				Domains: generateServiceDomains(serviceInfo),
+				Routes:  routes,
+			},
+		},
+	}, subsets
+}
+
+// subsetClusterName subset cluster 的名称，格式为 {service}|{key}={value},...
+func subsetClusterName(service string, labels map[string]string) string {
+	pairs := make([]string, 0, len(labels))
+	for key, value := range labels {
+		pairs = append(pairs, key+"="+value)
+	}
+	sort.Strings(pairs)
+	return service + "|" + strings.Join(pairs, ",")
+}
+
+// makeProxylessEndpoints 只包含健康并且匹配 subset 标签的实例，gRPC 会忽略没有权重的 locality
+func makeProxylessEndpoints(clusterName string, serviceInfo *ServiceInfo,
+	labels map[string]string) *endpoint.ClusterLoadAssignment {
+	var lbEndpoints []*endpoint.LbEndpoint
+	for _, instance := range serviceInfo.Instances {
+		if !instance.GetHealthy().GetValue() || !matchSubsetLabels(instance.GetMetadata(), labels) {
+			continue
+		}
+		lbEndpoints = append(lbEndpoints, makeLbEndpoint(instance))
+	}
+
+	return &endpoint.ClusterLoadAssignment{
+		ClusterName: clusterName,
+		Endpoints: []*endpoint.LocalityLbEndpoints{
+			{
+				LoadBalancingWeight: &wrappers.UInt32Value{Value: 1},
+				LbEndpoints:         lbEndpoints,
+			},
+		},
+	}
+}
+
+func matchSubsetLabels(metadata map[string]string, labels map[string]string) bool {
+	for key, value := range labels {
+		if metadata[key] != value {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,113 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package xdsserverv3
+
+import (
+	"encoding/json"
+	"testing"
+
+	cluster "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	endpoint "github.com/envoyproxy/go-control-plane/envoy/config/endpoint/v3"
+	listener "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
+	hcm "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/cache/v3"
+	resource "github.com/envoyproxy/go-control-plane/pkg/resource/v3"
+	_struct "github.com/golang/protobuf/ptypes/struct"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/atomic"
+
+	apiv2 "github.com/polarismesh/polaris/common/api/v2"
+	"github.com/polarismesh/polaris/common/model"
+	"github.com/polarismesh/polaris/common/utils"
+)
+
+func TestProxylessNodeHash(t *testing.T) {
+	node := &core.Node{
+		Id: "default/9b9f5630-81a1-47cd-a558-036eb616dc71~172.17.1.1",
+		Metadata: &_struct.Struct{
+			Fields: map[string]*_struct.Value{
+				ProxylessTag: {Kind: &_struct.Value_StringValue{StringValue: "true"}},
+			},
+		},
+	}
+	assert.Equal(t, "default/proxyless", PolarisNodeHash{}.ID(node))
+}
+
+func TestProxylessSnapshot(t *testing.T) {
+	sis := map[string][]*ServiceInfo{}
+	_ = json.Unmarshal(testServicesData, &sis)
+
+	// 按照实例标签划分流量的规则
+	rule := &apiv2.RuleRoutingConfig{
+		Sources: []*apiv2.Source{{Service: "*", Namespace: "*"}},
+		Destinations: []*apiv2.Destination{
+			{
+				Service:   "service-a",
+				Namespace: "default",
+				Labels: map[string]*apiv2.MatchString{
+					TLSModeTag: {Value: utils.NewStringValue("strict")},
+				},
+			},
+		},
+	}
+	serviceInfo := sis["default"][0]
+	serviceInfo.RoutingV2 = makeInboundRoutingV2(serviceInfo.Name, serviceInfo.Namespace,
+		[]*apiv2.Routing{buildRoutingV2(t, "rule", 0, rule)})
+
+	x := XDSServer{
+		CircuitBreakerConfigGetter: func(id string) *model.ServiceWithCircuitBreaker {
+			return nil
+		},
+		RatelimitConfigGetter: func(serviceID string) []*model.RateLimit { return nil },
+		versionNum:            atomic.NewUint64(1),
+		cache:                 cache.NewSnapshotCache(true, cache.IDHash{}, nil),
+	}
+	_ = x.pushRegistryInfoToXDSCache(sis)
+
+	snapshot, err := x.cache.GetSnapshot("default/proxyless")
+	assert.NoError(t, err)
+	assert.NoError(t, snapshot.(*cache.Snapshot).Consistent())
+
+	listeners := snapshot.GetResources(resource.ListenerType)
+	assert.Equal(t, len(generateServiceDomains(serviceInfo)), len(listeners))
+	apiListener := listeners["service-a"].(*listener.Listener).GetApiListener()
+	assert.NotNil(t, apiListener)
+	manager := &hcm.HttpConnectionManager{}
+	assert.NoError(t, apiListener.GetApiListener().UnmarshalTo(manager))
+	assert.NotNil(t, manager.HttpFilters[0].GetTypedConfig())
+
+	subsetName := subsetClusterName("service-a", map[string]string{TLSModeTag: "strict"})
+	routeConfig := manager.GetRouteConfig()
+	assert.Equal(t, "service-a", routeConfig.Name)
+	clusterWeight := routeConfig.VirtualHosts[0].Routes[0].GetRoute().GetWeightedClusters().Clusters[0]
+	assert.Equal(t, subsetName, clusterWeight.Name)
+	assert.Nil(t, clusterWeight.MetadataMatch)
+
+	clusters := snapshot.GetResources(resource.ClusterType)
+	assert.Equal(t, 2, len(clusters))
+	assert.Nil(t, clusters["service-a"].(*cluster.Cluster).LbSubsetConfig)
+	assert.Equal(t, subsetName, clusters[subsetName].(*cluster.Cluster).EdsClusterConfig.ServiceName)
+
+	endpoints := snapshot.GetResources(resource.EndpointType)
+	all := endpoints["service-a"].(*endpoint.ClusterLoadAssignment)
+	assert.Equal(t, 2, len(all.Endpoints[0].LbEndpoints))
+	assert.Equal(t, uint32(1), all.Endpoints[0].LoadBalancingWeight.GetValue())
+	subset := endpoints[subsetName].(*endpoint.ClusterLoadAssignment)
+	assert.Equal(t, 1, len(subset.Endpoints[0].LbEndpoints))
+}
@@ -0,0 +1,567 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package xdsserverv3
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	cluster "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	endpoint "github.com/envoyproxy/go-control-plane/envoy/config/endpoint/v3"
+	rlconf "github.com/envoyproxy/go-control-plane/envoy/config/ratelimit/v3"
+	route "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	rlfilter "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/ratelimit/v3"
+	hcm "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
+	upstreamhttp "github.com/envoyproxy/go-control-plane/envoy/extensions/upstreams/http/v3"
+	rlsv3 "github.com/envoyproxy/go-control-plane/envoy/service/ratelimit/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/resource/v3"
+	"github.com/go-redis/redis/v8"
+	"github.com/golang/protobuf/ptypes"
+	"google.golang.org/protobuf/types/known/anypb"
+
+	api "github.com/polarismesh/polaris/common/api/v1"
+	"github.com/polarismesh/polaris/common/model"
+	"github.com/polarismesh/polaris/common/redispool"
+)
+
+const (
+	// rlsDomain envoy 请求全局限流服务时携带的 domain
+	rlsDomain = "polaris"
+	// rlsClusterName envoy 访问 polaris 全局限流服务的 cluster
+	rlsClusterName = "polaris-ratelimit"
+	// rlsServiceKey 描述符中标识服务 ID 的条目
+	rlsServiceKey = "polaris.service"
+	// rlsRuleKey 描述符中标识限流规则 ID 的条目
+	rlsRuleKey = "polaris.rule"
+	// rlsRedisKeyPrefix 全局计数在 redis 中的 key 前缀
+	rlsRedisKeyPrefix = "polaris:rls:"
+)
+
+// rateLimitServiceConfig 全局限流服务配置
+type rateLimitServiceConfig struct {
+	Enable bool
+	// Address envoy 访问 polaris 全局限流服务的地址，格式为 host:port
+	Address string
+	// Redis 配置后计数通过 redis 在 polaris 节点之间共享，否则只在本节点内计数
+	Redis *redispool.Config
+}
+
+func parseRateLimitServiceConfig(raw map[interface{}]interface{}) (*rateLimitServiceConfig, error) {
+	conf := &rateLimitServiceConfig{}
+	conf.Enable, _ = raw["enable"].(bool)
+	conf.Address, _ = raw["address"].(string)
+	if !conf.Enable {
+		return conf, nil
+	}
+	if _, _, err := splitHostPort(conf.Address); err != nil {
+		return nil, fmt.Errorf("invalid ratelimit address %q: %w", conf.Address, err)
+	}
+	if redisRaw, _ := raw["redis"].(map[interface{}]interface{}); redisRaw != nil {
+		data, err := json.Marshal(toStringKeyMap(redisRaw))
+		if err != nil {
+			return nil, fmt.Errorf("fail to marshal ratelimit redis config: %w", err)
+		}
+		conf.Redis = &redispool.Config{}
+		if err = json.Unmarshal(data, conf.Redis); err != nil {
+			return nil, fmt.Errorf("fail to unmarshal ratelimit redis config: %w", err)
+		}
+	}
+	return conf, nil
+}
+
+// toStringKeyMap yaml 解析出的嵌套配置转换为可以 json 序列化的结构
+func toStringKeyMap(raw map[interface{}]interface{}) map[string]interface{} {
+	ret := make(map[string]interface{}, len(raw))
+	for k, v := range raw {
+		if sub, ok := v.(map[interface{}]interface{}); ok {
+			v = toStringKeyMap(sub)
+		}
+		ret[fmt.Sprint(k)] = v
+	}
+	return ret
+}
+
+func splitHostPort(address string) (string, uint32, error) {
+	host, portStr, err := net.SplitHostPort(address)
+	if err != nil {
+		return "", 0, err
+	}
+	port, err := strconv.ParseUint(portStr, 10, 16)
+	if err != nil {
+		return "", 0, err
+	}
+	return host, uint32(port), nil
+}
+
+// rateLimitCounter 固定时间窗口计数器
+type rateLimitCounter interface {
+	// Incr 在 key 当前所处的时间窗口内累加 hits，返回累加后的计数
+	Incr(ctx context.Context, key string, hits uint32, window time.Duration) (uint64, error)
+	// Close 释放计数器资源
+	Close() error
+}
+
+// windowIndex 计算当前时间所处的窗口序号以及距离窗口结束的时间，
+// 各个 polaris 节点按照相同的规则切分窗口，保证共享计数时窗口一致
+func windowIndex(now time.Time, window time.Duration) (int64, time.Duration) {
+	nanos := now.UnixNano()
+	return nanos / int64(window), window - time.Duration(nanos%int64(window))
+}
+
+type localWindow struct {
+	index    int64
+	count    uint64
+	expireAt time.Time
+}
+
+// localCounter 进程内计数，仅在单个 polaris 节点内生效
+type localCounter struct {
+	mutex     sync.Mutex
+	windows   map[string]*localWindow
+	lastSweep time.Time
+}
+
+func newLocalCounter() *localCounter {
+	return &localCounter{windows: make(map[string]*localWindow), lastSweep: time.Now()}
+}
+
+// Incr 实现 rateLimitCounter
+func (c *localCounter) Incr(_ context.Context, key string, hits uint32, window time.Duration) (uint64, error) {
+	now := time.Now()
+	index, remain := windowIndex(now, window)
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.sweep(now)
+	w, ok := c.windows[key]
+	if !ok || w.index != index {
+		w = &localWindow{index: index, expireAt: now.Add(remain)}
+		c.windows[key] = w
+	}
+	w.count += uint64(hits)
+	return w.count, nil
+}
+
+// sweep 定期清理已经过期的窗口，避免 key 只增不减
+func (c *localCounter) sweep(now time.Time) {
+	if now.Sub(c.lastSweep) < time.Minute {
+		return
+	}
+	c.lastSweep = now
+	for key, w := range c.windows {
+		if now.After(w.expireAt) {
+			delete(c.windows, key)
+		}
+	}
+}
+
+// Close 实现 rateLimitCounter
+func (c *localCounter) Close() error {
+	return nil
+}
+
+// redisCounter 基于 redis 的计数，多个 polaris 节点共享同一份配额
+type redisCounter struct {
+	client redis.UniversalClient
+}
+
+// Incr 实现 rateLimitCounter
+func (c *redisCounter) Incr(ctx context.Context, key string, hits uint32, window time.Duration) (uint64, error) {
+	index, remain := windowIndex(time.Now(), window)
+	redisKey := rlsRedisKeyPrefix + key + ":" + strconv.FormatInt(index, 10)
+	var incr *redis.IntCmd
+	_, err := c.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		incr = pipe.IncrBy(ctx, redisKey, int64(hits))
+		// 多保留一秒，兼容节点之间的时钟偏差
+		pipe.PExpire(ctx, redisKey, remain+time.Second)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return uint64(incr.Val()), nil
+}
+
+// Close 实现 rateLimitCounter
+func (c *redisCounter) Close() error {
+	return c.client.Close()
+}
+
+// rateLimitService 实现 envoy 的全局限流服务，按照 polaris 的全局限流规则对描述符计数
+type rateLimitService struct {
+	host    string
+	port    uint32
+	getter  RatelimitConfigGetter
+	counter rateLimitCounter
+	regexes sync.Map
+}
+
+func newRateLimitService(conf *rateLimitServiceConfig, getter RatelimitConfigGetter) (*rateLimitService, error) {
+	host, port, err := splitHostPort(conf.Address)
+	if err != nil {
+		return nil, err
+	}
+	svc := &rateLimitService{host: host, port: port, getter: getter}
+	if conf.Redis != nil {
+		svc.counter = &redisCounter{client: redispool.NewRedisClient(conf.Redis)}
+	} else {
+		svc.counter = newLocalCounter()
+	}
+	return svc, nil
+}
+
+// ShouldRateLimit 实现 ratelimit.v3.RateLimitService
+func (s *rateLimitService) ShouldRateLimit(ctx context.Context,
+	req *rlsv3.RateLimitRequest) (*rlsv3.RateLimitResponse, error) {
+	if req.GetDomain() != rlsDomain {
+		return nil, fmt.Errorf("unknown rate limit domain %s", req.GetDomain())
+	}
+	hits := req.GetHitsAddend()
+	if hits == 0 {
+		hits = 1
+	}
+	resp := &rlsv3.RateLimitResponse{OverallCode: rlsv3.RateLimitResponse_OK}
+	for _, descriptor := range req.GetDescriptors() {
+		entries := make(map[string]string, len(descriptor.GetEntries()))
+		for _, entry := range descriptor.GetEntries() {
+			entries[entry.GetKey()] = entry.GetValue()
+		}
+		status := s.checkDescriptor(ctx, entries, hits)
+		if status.Code == rlsv3.RateLimitResponse_OVER_LIMIT {
+			resp.OverallCode = rlsv3.RateLimitResponse_OVER_LIMIT
+		}
+		resp.Statuses = append(resp.Statuses, status)
+	}
+	return resp, nil
+}
+
+// checkDescriptor 对单个描述符计数，规则不存在或者计数失败时放通
+func (s *rateLimitService) checkDescriptor(ctx context.Context, entries map[string]string,
+	hits uint32) *rlsv3.RateLimitResponse_DescriptorStatus {
+	ok := &rlsv3.RateLimitResponse_DescriptorStatus{Code: rlsv3.RateLimitResponse_OK}
+	rule := s.findRule(entries[rlsServiceKey], entries[rlsRuleKey])
+	if rule == nil {
+		return ok
+	}
+	matchers := globalRateLimitMatchers(rule)
+	keys := make([]string, 0, len(matchers))
+	for key, matcher := range matchers {
+		if !s.matchValue(matcher, entries[key]) {
+			return ok
+		}
+		keys = append(keys, key)
+	}
+	// 正则合并时所有匹配的请求共用一份配额，否则按照实际的标签值分别计数
+	bucket := entries[rlsRuleKey]
+	if !rule.GetRegexCombine().GetValue() {
+		sort.Strings(keys)
+		for _, key := range keys {
+			bucket += "|" + key + "=" + entries[key]
+		}
+	}
+
+	for _, amount := range rule.GetAmounts() {
+		window := amount.GetValidDuration().AsDuration()
+		maxAmount := amount.GetMaxAmount().GetValue()
+		if window <= 0 {
+			continue
+		}
+		count, err := s.counter.Incr(ctx, bucket+"|"+window.String(), hits, window)
+		if err != nil {
+			log.Errorf("[XDSV3] rate limit count %s error %v", bucket, err)
+			return ok
+		}
+		_, remain := windowIndex(time.Now(), window)
+		status := &rlsv3.RateLimitResponse_DescriptorStatus{
+			Code:               rlsv3.RateLimitResponse_OK,
+			CurrentLimit:       makeCurrentLimit(maxAmount, window),
+			DurationUntilReset: ptypes.DurationProto(remain),
+		}
+		if count > uint64(maxAmount) {
+			status.Code = rlsv3.RateLimitResponse_OVER_LIMIT
+			return status
+		}
+		status.LimitRemaining = maxAmount - uint32(count)
+		ok = status
+	}
+	return ok
+}
+
+func (s *rateLimitService) findRule(serviceID, ruleID string) *api.Rule {
+	if serviceID == "" || ruleID == "" {
+		return nil
+	}
+	for _, conf := range s.getter(serviceID) {
+		if conf.ID == ruleID {
+			return parseGlobalRateLimitRule(conf)
+		}
+	}
+	return nil
+}
+
+func (s *rateLimitService) matchValue(matcher *api.MatchString, value string) bool {
+	expect := matcher.GetValue().GetValue()
+	switch matcher.GetType() {
+	case api.MatchString_EXACT:
+		return value == expect
+	case api.MatchString_NOT_EQUALS:
+		return value != expect
+	case api.MatchString_REGEX:
+		regex, err := s.compile(expect)
+		return err == nil && regex.MatchString(value)
+	case api.MatchString_IN, api.MatchString_NOT_IN:
+		in := false
+		for _, item := range strings.Split(expect, ",") {
+			if strings.TrimSpace(item) == value {
+				in = true
+				break
+			}
+		}
+		return in == (matcher.GetType() == api.MatchString_IN)
+	}
+	return false
+}
+
+func (s *rateLimitService) compile(expr string) (*regexp.Regexp, error) {
+	if val, ok := s.regexes.Load(expr); ok {
+		return val.(*regexp.Regexp), nil
+	}
+	regex, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, err
+	}
+	s.regexes.Store(expr, regex)
+	return regex, nil
+}
+
+// Close 释放计数器
+func (s *rateLimitService) Close() error {
+	return s.counter.Close()
+}
+
+// makeCluster envoy 访问 polaris 全局限流服务使用的 cluster
+func (s *rateLimitService) makeCluster() *cluster.Cluster {
+	protocolOptions, err := ptypes.MarshalAny(&upstreamhttp.HttpProtocolOptions{
+		UpstreamProtocolOptions: &upstreamhttp.HttpProtocolOptions_ExplicitHttpConfig_{
+			ExplicitHttpConfig: &upstreamhttp.HttpProtocolOptions_ExplicitHttpConfig{
+				ProtocolConfig: &upstreamhttp.HttpProtocolOptions_ExplicitHttpConfig_Http2ProtocolOptions{
+					Http2ProtocolOptions: &core.Http2ProtocolOptions{},
+				},
+			},
+		},
+	})
+	if err != nil {
+		panic(err)
+	}
+	return &cluster.Cluster{
+		Name:                 rlsClusterName,
+		ConnectTimeout:       ptypes.DurationProto(5 * time.Second),
+		ClusterDiscoveryType: &cluster.Cluster_Type{Type: cluster.Cluster_STRICT_DNS},
+		TypedExtensionProtocolOptions: map[string]*anypb.Any{
+			"envoy.extensions.upstreams.http.v3.HttpProtocolOptions": protocolOptions,
+		},
+		LoadAssignment: &endpoint.ClusterLoadAssignment{
+			ClusterName: rlsClusterName,
+			Endpoints: []*endpoint.LocalityLbEndpoints{{
+				LbEndpoints: []*endpoint.LbEndpoint{{
+					HostIdentifier: &endpoint.LbEndpoint_Endpoint{
+						Endpoint: &endpoint.Endpoint{
+							Address: &core.Address{
+								Address: &core.Address_SocketAddress{
+									SocketAddress: &core.SocketAddress{
+										Protocol: core.SocketAddress_TCP,
+										Address:  s.host,
+										PortSpecifier: &core.SocketAddress_PortValue{
+											PortValue: s.port,
+										},
+									},
+								},
+							},
+						},
+					},
+				}},
+			}},
+		},
+	}
+}
+
+// makeHTTPFilter 挂载在 router 之前的全局限流 filter，限流服务不可用时放通
+func (s *rateLimitService) makeHTTPFilter() *hcm.HttpFilter {
+	conf, err := ptypes.MarshalAny(&rlfilter.RateLimit{
+		Domain:  rlsDomain,
+		Timeout: ptypes.DurationProto(100 * time.Millisecond),
+		RateLimitService: &rlconf.RateLimitServiceConfig{
+			GrpcService: &core.GrpcService{
+				TargetSpecifier: &core.GrpcService_EnvoyGrpc_{
+					EnvoyGrpc: &core.GrpcService_EnvoyGrpc{ClusterName: rlsClusterName},
+				},
+			},
+			TransportApiVersion: resource.DefaultAPIVersion,
+		},
+	})
+	if err != nil {
+		panic(err)
+	}
+	return &hcm.HttpFilter{
+		Name:       "envoy.filters.http.ratelimit",
+		ConfigType: &hcm.HttpFilter_TypedConfig{TypedConfig: conf},
+	}
+}
+
+func makeCurrentLimit(maxAmount uint32, window time.Duration) *rlsv3.RateLimitResponse_RateLimit {
+	units := []struct {
+		duration time.Duration
+		unit     rlsv3.RateLimitResponse_RateLimit_Unit
+	}{
+		{time.Second, rlsv3.RateLimitResponse_RateLimit_SECOND},
+		{time.Minute, rlsv3.RateLimitResponse_RateLimit_MINUTE},
+		{time.Hour, rlsv3.RateLimitResponse_RateLimit_HOUR},
+		{24 * time.Hour, rlsv3.RateLimitResponse_RateLimit_DAY},
+	}
+	for _, u := range units {
+		if window == u.duration {
+			return &rlsv3.RateLimitResponse_RateLimit{RequestsPerUnit: maxAmount, Unit: u.unit}
+		}
+	}
+	return &rlsv3.RateLimitResponse_RateLimit{
+		Name:            window.String(),
+		RequestsPerUnit: maxAmount,
+		Unit:            rlsv3.RateLimitResponse_RateLimit_UNKNOWN,
+	}
+}
+
+// parseGlobalRateLimitRule 解析出生效的全局限流规则，其余规则返回 nil
+func parseGlobalRateLimitRule(conf *model.RateLimit) *api.Rule {
+	rule := conf.Proto
+	if rule == nil {
+		if conf.Rule == "" {
+			return nil
+		}
+		rule = new(api.Rule)
+		if err := json.Unmarshal([]byte(conf.Rule), rule); err != nil {
+			log.Errorf("[XDSV3] unmarshal rate limit rule %s error %v", conf.ID, err)
+			return nil
+		}
+		if len(rule.Labels) == 0 && conf.Labels != "" {
+			if err := json.Unmarshal([]byte(conf.Labels), &rule.Labels); err != nil {
+				log.Errorf("[XDSV3] unmarshal rate limit labels %s error %v", conf.ID, err)
+				return nil
+			}
+		}
+	}
+	if rule.GetType() != api.Rule_GLOBAL || rule.GetDisable().GetValue() {
+		return nil
+	}
+	return rule
+}
+
+// globalRateLimitMatchers 规则需要匹配的请求属性，key 与描述符条目的 key 一致
+func globalRateLimitMatchers(rule *api.Rule) map[string]*api.MatchString {
+	matchers := make(map[string]*api.MatchString, len(rule.GetLabels())+1)
+	for key, matcher := range rule.GetLabels() {
+		matchers[key] = matcher
+	}
+	if rule.GetMethod().GetValue().GetValue() != "" {
+		if _, ok := matchers[model.LabelKeyPath]; !ok {
+			matchers[model.LabelKeyPath] = rule.GetMethod()
+		}
+	}
+	return matchers
+}
+
+// rateLimitHeaderName 将限流标签转换为 envoy 可以提取的请求头
+func rateLimitHeaderName(key string) (string, error) {
+	switch {
+	case key == model.LabelKeyMethod:
+		return ":method", nil
+	case key == model.LabelKeyPath:
+		return ":path", nil
+	case strings.HasPrefix(key, model.LabelKeyHeader+"."):
+		return strings.TrimPrefix(key, model.LabelKeyHeader+"."), nil
+	case strings.HasPrefix(key, "$"):
+		return "", errors.New("unsupported rate limit label " + key)
+	}
+	// 自定义标签按照同名请求头处理
+	return key, nil
+}
+
+// makeGlobalRateLimits 为服务的全局限流规则生成路由上的 rate_limit 动作，
+// 描述符依次为服务 ID、规则 ID 以及规则标签对应的请求头
+func makeGlobalRateLimits(serviceID string, conf []*model.RateLimit) []*route.RateLimit {
+	var rateLimits []*route.RateLimit
+	for _, c := range conf {
+		rule := parseGlobalRateLimitRule(c)
+		if rule == nil {
+			continue
+		}
+		actions := []*route.RateLimit_Action{
+			makeGenericKeyAction(rlsServiceKey, serviceID),
+			makeGenericKeyAction(rlsRuleKey, c.ID),
+		}
+		matchers := globalRateLimitMatchers(rule)
+		keys := make([]string, 0, len(matchers))
+		for key := range matchers {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		supported := true
+		for _, key := range keys {
+			header, err := rateLimitHeaderName(key)
+			if err != nil {
+				log.Warnf("[XDSV3] skip global rate limit rule %s: %v", c.ID, err)
+				supported = false
+				break
+			}
+			actions = append(actions, &route.RateLimit_Action{
+				ActionSpecifier: &route.RateLimit_Action_RequestHeaders_{
+					RequestHeaders: &route.RateLimit_Action_RequestHeaders{
+						HeaderName:    header,
+						DescriptorKey: key,
+						// 请求头不存在时交由限流服务按照空值匹配
+						SkipIfAbsent: true,
+					},
+				},
+			})
+		}
+		if supported {
+			rateLimits = append(rateLimits, &route.RateLimit{Actions: actions})
+		}
+	}
+	return rateLimits
+}
+
+func makeGenericKeyAction(key, value string) *route.RateLimit_Action {
+	return &route.RateLimit_Action{
+		ActionSpecifier: &route.RateLimit_Action_GenericKey_{
+			GenericKey: &route.RateLimit_Action_GenericKey{
+				DescriptorKey:   key,
+				DescriptorValue: value,
+			},
+		},
+	}
+}
@@ -0,0 +1,166 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package xdsserverv3
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	ratelimitv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/common/ratelimit/v3"
+	rlsv3 "github.com/envoyproxy/go-control-plane/envoy/service/ratelimit/v3"
+	"github.com/golang/protobuf/ptypes/duration"
+	"github.com/golang/protobuf/ptypes/wrappers"
+	"github.com/stretchr/testify/assert"
+
+	api "github.com/polarismesh/polaris/common/api/v1"
+	"github.com/polarismesh/polaris/common/model"
+)
+
+func buildGlobalRateLimit(t *testing.T, id string, maxAmount uint32, labels map[string]*api.MatchString) *model.RateLimit {
+	rule := &api.Rule{
+		Type:   api.Rule_GLOBAL,
+		Labels: labels,
+		Amounts: []*api.Amount{{
+			MaxAmount:     &wrappers.UInt32Value{Value: maxAmount},
+			ValidDuration: &duration.Duration{Seconds: 3600},
+		}},
+	}
+	data, err := json.Marshal(rule)
+	assert.NoError(t, err)
+	return &model.RateLimit{ID: id, ServiceID: "service-1", Rule: string(data), Valid: true}
+}
+
+func makeTestDescriptor(entries ...string) *ratelimitv3.RateLimitDescriptor {
+	descriptor := &ratelimitv3.RateLimitDescriptor{}
+	for i := 0; i+1 < len(entries); i += 2 {
+		descriptor.Entries = append(descriptor.Entries,
+			&ratelimitv3.RateLimitDescriptor_Entry{Key: entries[i], Value: entries[i+1]})
+	}
+	return descriptor
+}
+
+func TestMakeGlobalRateLimits(t *testing.T) {
+	global, _ := generateGlobalRateLimitRule()
+	local, _ := generateLocalRateLimitRule()
+	rateLimits := makeGlobalRateLimits("service-1", append(global, local...))
+	assert.Len(t, rateLimits, 1)
+
+	actions := rateLimits[0].GetActions()
+	assert.Len(t, actions, 4)
+	assert.Equal(t, rlsServiceKey, actions[0].GetGenericKey().GetDescriptorKey())
+	assert.Equal(t, "service-1", actions[0].GetGenericKey().GetDescriptorValue())
+	assert.Equal(t, rlsRuleKey, actions[1].GetGenericKey().GetDescriptorKey())
+	assert.Equal(t, "ratelimit-1", actions[1].GetGenericKey().GetDescriptorValue())
+	assert.Equal(t, ":path", actions[2].GetRequestHeaders().GetHeaderName())
+	assert.Equal(t, model.LabelKeyPath, actions[2].GetRequestHeaders().GetDescriptorKey())
+	assert.Equal(t, "uin", actions[3].GetRequestHeaders().GetHeaderName())
+
+	// 无法从请求头提取的标签跳过整条规则
+	unsupported := buildGlobalRateLimit(t, "ratelimit-3", 1, map[string]*api.MatchString{
+		model.LabelKeyCallerIP: {Value: &wrappers.StringValue{Value: "127.0.0.1"}},
+	})
+	assert.Empty(t, makeGlobalRateLimits("service-1", []*model.RateLimit{unsupported}))
+}
+
+func TestParseRateLimitServiceConfig(t *testing.T) {
+	conf, err := parseRateLimitServiceConfig(map[interface{}]interface{}{
+		"enable":  true,
+		"address": "polaris.polaris-system:15010",
+		"redis": map[interface{}]interface{}{
+			"kvAddr":     "127.0.0.1:6379",
+			"deployMode": "standalone",
+		},
+	})
+	assert.NoError(t, err)
+	assert.True(t, conf.Enable)
+	assert.Equal(t, "127.0.0.1:6379", conf.Redis.KvAddr)
+
+	_, err = parseRateLimitServiceConfig(map[interface{}]interface{}{"enable": true, "address": "polaris"})
+	assert.Error(t, err)
+}
+
+func TestRateLimitServiceShouldRateLimit(t *testing.T) {
+	rules := []*model.RateLimit{
+		buildGlobalRateLimit(t, "rule-exact", 2, map[string]*api.MatchString{
+			"$header.uid": {Type: api.MatchString_EXACT, Value: &wrappers.StringValue{Value: "u1"}},
+		}),
+		buildGlobalRateLimit(t, "rule-regex", 1, map[string]*api.MatchString{
+			"$header.uid": {Type: api.MatchString_REGEX, Value: &wrappers.StringValue{Value: "^v.*"}},
+		}),
+	}
+	svc, err := newRateLimitService(&rateLimitServiceConfig{Enable: true, Address: "127.0.0.1:15010"},
+		func(serviceID string) []*model.RateLimit {
+			if serviceID == "service-1" {
+				return rules
+			}
+			return nil
+		})
+	assert.NoError(t, err)
+	defer svc.Close()
+
+	check := func(descriptor *ratelimitv3.RateLimitDescriptor) *rlsv3.RateLimitResponse {
+		resp, err := svc.ShouldRateLimit(context.Background(), &rlsv3.RateLimitRequest{
+			Domain:      rlsDomain,
+			Descriptors: []*ratelimitv3.RateLimitDescriptor{descriptor},
+		})
+		assert.NoError(t, err)
+		assert.Len(t, resp.GetStatuses(), 1)
+		return resp
+	}
+
+	exact := makeTestDescriptor(rlsServiceKey, "service-1", rlsRuleKey, "rule-exact", "$header.uid", "u1")
+	resp := check(exact)
+	assert.Equal(t, rlsv3.RateLimitResponse_OK, resp.GetOverallCode())
+	assert.Equal(t, uint32(1), resp.GetStatuses()[0].GetLimitRemaining())
+	assert.Equal(t, rlsv3.RateLimitResponse_RateLimit_HOUR, resp.GetStatuses()[0].GetCurrentLimit().GetUnit())
+	assert.Equal(t, rlsv3.RateLimitResponse_OK, check(exact).GetOverallCode())
+	assert.Equal(t, rlsv3.RateLimitResponse_OVER_LIMIT, check(exact).GetOverallCode())
+
+	// 标签不匹配、规则或服务不存在时放通
+	assert.Equal(t, rlsv3.RateLimitResponse_OK, check(makeTestDescriptor(rlsServiceKey, "service-1",
+		rlsRuleKey, "rule-exact", "$header.uid", "u2")).GetOverallCode())
+	assert.Equal(t, rlsv3.RateLimitResponse_OK, check(makeTestDescriptor(rlsServiceKey, "service-1",
+		rlsRuleKey, "rule-unknown")).GetOverallCode())
+	assert.Equal(t, rlsv3.RateLimitResponse_OK, check(makeTestDescriptor(rlsServiceKey, "service-2",
+		rlsRuleKey, "rule-exact", "$header.uid", "u1")).GetOverallCode())
+
+	// 正则规则默认按照实际的标签值分别计数
+	assert.Equal(t, rlsv3.RateLimitResponse_OK, check(makeTestDescriptor(rlsServiceKey, "service-1",
+		rlsRuleKey, "rule-regex", "$header.uid", "v1")).GetOverallCode())
+	assert.Equal(t, rlsv3.RateLimitResponse_OK, check(makeTestDescriptor(rlsServiceKey, "service-1",
+		rlsRuleKey, "rule-regex", "$header.uid", "v2")).GetOverallCode())
+	assert.Equal(t, rlsv3.RateLimitResponse_OVER_LIMIT, check(makeTestDescriptor(rlsServiceKey, "service-1",
+		rlsRuleKey, "rule-regex", "$header.uid", "v1")).GetOverallCode())
+
+	_, err = svc.ShouldRateLimit(context.Background(), &rlsv3.RateLimitRequest{Domain: "other"})
+	assert.Error(t, err)
+}
+
+func TestLocalCounterWindow(t *testing.T) {
+	counter := newLocalCounter()
+	window := 50 * time.Millisecond
+	count, err := counter.Incr(context.Background(), "key", 3, window)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(3), count)
+	time.Sleep(window)
+	count, err = counter.Incr(context.Background(), "key", 1, window)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1), count)
+}
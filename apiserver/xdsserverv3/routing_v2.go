@@ -0,0 +1,325 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package xdsserverv3
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	route "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	v32 "github.com/envoyproxy/go-control-plane/envoy/type/matcher/v3"
+	"github.com/golang/protobuf/ptypes"
+	_struct "github.com/golang/protobuf/ptypes/struct"
+	"github.com/golang/protobuf/ptypes/wrappers"
+
+	apiv2 "github.com/polarismesh/polaris/common/api/v2"
+	routingcommon "github.com/polarismesh/polaris/common/routing"
+)
+
+// methodHeaderName envoy 中请求方法对应的伪头部
+const methodHeaderName = ":method"
+
+// makeInboundRoutingV2 从 v2 路由规则中筛选出以该服务为目标的规则路由，按照优先级排序，
+// 并且只保留与该服务匹配的 destination
+func makeInboundRoutingV2(service, namespace string, rules []*apiv2.Routing) []*apiv2.RuleRoutingConfig {
+	sorted := make([]*apiv2.Routing, 0, len(rules))
+	for _, rule := range rules {
+		if rule.GetRoutingPolicy() == apiv2.RoutingPolicy_RulePolicy && rule.GetRoutingConfig() != nil {
+			sorted = append(sorted, rule)
+		}
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].GetPriority() != sorted[j].GetPriority() {
+			return sorted[i].GetPriority() < sorted[j].GetPriority()
+		}
+		if sorted[i].GetCtime() != sorted[j].GetCtime() {
+			return sorted[i].GetCtime() < sorted[j].GetCtime()
+		}
+		return sorted[i].GetId() < sorted[j].GetId()
+	})
+
+	var ret []*apiv2.RuleRoutingConfig
+	for _, rule := range sorted {
+		ruleRouting := &apiv2.RuleRoutingConfig{}
+		if err := ptypes.UnmarshalAny(rule.GetRoutingConfig(), ruleRouting); err != nil {
+			log.Errorf("[XDSV3] unmarshal routing v2 %s error %v", rule.GetId(), err)
+			continue
+		}
+		var destinations []*apiv2.Destination
+		for _, destination := range ruleRouting.GetDestinations() {
+			if matchRoutingV2Service(destination.GetService(), destination.GetNamespace(), service, namespace) {
+				destinations = append(destinations, destination)
+			}
+		}
+		if len(destinations) == 0 {
+			continue
+		}
+		ret = append(ret, &apiv2.RuleRoutingConfig{
+			Sources:      ruleRouting.GetSources(),
+			Destinations: destinations,
+		})
+	}
+	return ret
+}
+
+func matchRoutingV2Service(ruleService, ruleNamespace, service, namespace string) bool {
+	if ruleNamespace != routingcommon.MatchAll && ruleNamespace != namespace {
+		return false
+	}
+	return ruleService == routingcommon.MatchAll || ruleService == service
+}
+
+// makeRoutesV2 将 v2 的规则路由转换为 envoy 的路由，每一个 source 生成一条路由，
+// 包含 envoy 无法处理的匹配条件的 source 会被忽略。路由按照被调服务下发给所有主调，
+// 因此限定了主调服务的 source 也会被忽略
+func makeRoutesV2(serviceInfo *ServiceInfo) []*route.Route {
+	var routes []*route.Route
+	for _, rule := range serviceInfo.RoutingV2 {
+		for _, source := range rule.GetSources() {
+			if !isMatchAllSourceV2(source) {
+				log.Infof("[XDSV3] routing v2 source %s/%s of service %s/%s is not supported, only wildcard caller is supported",
+					source.GetNamespace(), source.GetService(), serviceInfo.Namespace, serviceInfo.Name)
+				continue
+			}
+			routeMatch, ok := makeRouteMatchV2(source.GetArguments())
+			if !ok {
+				continue
+			}
+			clusters := makeWeightedClustersV2(serviceInfo.Name, rule.GetDestinations())
+			if clusters == nil {
+				continue
+			}
+			routes = append(routes, &route.Route{
+				Match: routeMatch,
+				Action: &route.Route_Route{
+					Route: &route.RouteAction{
+						ClusterSpecifier: &route.RouteAction_WeightedClusters{
+							WeightedClusters: clusters,
+						},
+					},
+				},
+			})
+			// 匹配所有请求的路由之后的路由都不会生效
+			if len(source.GetArguments()) == 0 {
+				return routes
+			}
+		}
+	}
+	return append(routes, getDefaultRoute(serviceInfo.Name))
+}
+
+// isMatchAllSourceV2 source 是否对所有的主调服务生效
+func isMatchAllSourceV2(source *apiv2.Source) bool {
+	namespace, service := source.GetNamespace(), source.GetService()
+	return (namespace == "" || namespace == routingcommon.MatchAll) &&
+		(service == "" || service == routingcommon.MatchAll)
+}
+
+// makeRouteMatchV2 将 source 的参数转换为 envoy 的匹配条件，没有参数时匹配所有请求
+func makeRouteMatchV2(arguments []*apiv2.SourceMatch) (*route.RouteMatch, bool) {
+	routeMatch := &route.RouteMatch{
+		PathSpecifier: &route.RouteMatch_Prefix{Prefix: "/"},
+	}
+	for _, argument := range arguments {
+		matcher, invert, ok := makeStringMatcherV2(argument.GetValue())
+		if !ok {
+			return nil, false
+		}
+		switch argument.GetType() {
+		case apiv2.SourceMatch_PATH:
+			if invert {
+				return nil, false
+			}
+			switch pattern := matcher.MatchPattern.(type) {
+			case *v32.StringMatcher_Exact:
+				routeMatch.PathSpecifier = &route.RouteMatch_Path{Path: pattern.Exact}
+			case *v32.StringMatcher_SafeRegex:
+				routeMatch.PathSpecifier = &route.RouteMatch_SafeRegex{SafeRegex: pattern.SafeRegex}
+			}
+		case apiv2.SourceMatch_HEADER, apiv2.SourceMatch_METHOD:
+			name := argument.GetKey()
+			if argument.GetType() == apiv2.SourceMatch_METHOD {
+				name = methodHeaderName
+			}
+			if name == "" {
+				return nil, false
+			}
+			routeMatch.Headers = append(routeMatch.Headers, &route.HeaderMatcher{
+				Name:                 name,
+				HeaderMatchSpecifier: &route.HeaderMatcher_StringMatch{StringMatch: matcher},
+				InvertMatch:          invert,
+			})
+		case apiv2.SourceMatch_QUERY:
+			// query 参数的匹配不支持取反
+			if invert || argument.GetKey() == "" {
+				return nil, false
+			}
+			routeMatch.QueryParameters = append(routeMatch.QueryParameters, &route.QueryParameterMatcher{
+				Name:                         argument.GetKey(),
+				QueryParameterMatchSpecifier: &route.QueryParameterMatcher_StringMatch{StringMatch: matcher},
+			})
+		default:
+			// 自定义参数、主调 IP 以及 cookie 无法在 envoy 中匹配
+			return nil, false
+		}
+	}
+	return routeMatch, true
+}
+
+// makeStringMatcherV2 将 v2 的 MatchString 转为 envoy 的 StringMatcher，第二个返回值表示是否需要取反
+func makeStringMatcherV2(matchString *apiv2.MatchString) (*v32.StringMatcher, bool, bool) {
+	if matchString.GetValueType() != apiv2.MatchString_TEXT {
+		return nil, false, false
+	}
+	value := matchString.GetValue().GetValue()
+	switch matchString.GetType() {
+	case apiv2.MatchString_EXACT:
+		return &v32.StringMatcher{MatchPattern: &v32.StringMatcher_Exact{Exact: value}}, false, true
+	case apiv2.MatchString_NOT_EQUALS:
+		return &v32.StringMatcher{MatchPattern: &v32.StringMatcher_Exact{Exact: value}}, true, true
+	case apiv2.MatchString_REGEX:
+		return makeRegexStringMatcher(value), false, true
+	case apiv2.MatchString_IN, apiv2.MatchString_NOT_IN:
+		// 多个取值使用 , 分隔，转换为正则表达式进行匹配
+		values := strings.Split(value, ",")
+		for i := range values {
+			values[i] = regexp.QuoteMeta(strings.TrimSpace(values[i]))
+		}
+		regex := "(" + strings.Join(values, "|") + ")"
+		return makeRegexStringMatcher(regex), matchString.GetType() == apiv2.MatchString_NOT_IN, true
+	default:
+		return nil, false, false
+	}
+}
+
+func makeRegexStringMatcher(regex string) *v32.StringMatcher {
+	return &v32.StringMatcher{MatchPattern: &v32.StringMatcher_SafeRegex{
+		SafeRegex: &v32.RegexMatcher{
+			EngineType: &v32.RegexMatcher_GoogleRe2{GoogleRe2: &v32.RegexMatcher_GoogleRE2{}},
+			Regex:      regex,
+		}}}
+}
+
+// makeWeightedClustersV2 使用优先级最高且未被隔离的 destination 生成带权重的 subset，
+// 没有可用的 destination 时返回 nil
+func makeWeightedClustersV2(serviceName string, destinations []*apiv2.Destination) *route.WeightedCluster {
+	var selected []*apiv2.Destination
+	for _, destination := range destinations {
+		if destination.GetIsolate() {
+			continue
+		}
+		if len(selected) > 0 && destination.GetPriority() > selected[0].GetPriority() {
+			continue
+		}
+		if len(selected) > 0 && destination.GetPriority() < selected[0].GetPriority() {
+			selected = selected[:0]
+		}
+		selected = append(selected, destination)
+	}
+	if len(selected) == 0 {
+		return nil
+	}
+
+	var totalWeight uint32
+	for _, destination := range selected {
+		totalWeight += destination.GetWeight()
+	}
+	// 权重都没有设置时，认为各个 subset 的权重相同
+	sameWeight := totalWeight == 0
+
+	weightedClusters := make([]*route.WeightedCluster_ClusterWeight, 0, len(selected))
+	totalWeight = 0
+	for _, destination := range selected {
+		weight := destination.GetWeight()
+		if sameWeight {
+			weight = 1
+		}
+		if weight == 0 {
+			continue
+		}
+		totalWeight += weight
+		weightedClusters = append(weightedClusters, &route.WeightedCluster_ClusterWeight{
+			Name:          serviceName,
+			Weight:        &wrappers.UInt32Value{Value: weight},
+			MetadataMatch: makeSubsetMetadataMatchV2(destination.GetLabels()),
+		})
+	}
+	return &route.WeightedCluster{
+		TotalWeight: &wrappers.UInt32Value{Value: totalWeight},
+		Clusters:    weightedClusters,
+	}
+}
+
+// makeSubsetMetadataMatchV2 subset 只能精确匹配实例标签，其余匹配方式的标签会被忽略
+func makeSubsetMetadataMatchV2(labels map[string]*apiv2.MatchString) *core.Metadata {
+	fields := make(map[string]*_struct.Value)
+	for key, matchString := range labels {
+		if !isSubsetLabelV2(matchString) {
+			continue
+		}
+		fields[key] = &_struct.Value{
+			Kind: &_struct.Value_StringValue{
+				StringValue: matchString.GetValue().GetValue(),
+			},
+		}
+	}
+	if len(fields) == 0 {
+		return nil
+	}
+	return &core.Metadata{
+		FilterMetadata: map[string]*_struct.Struct{
+			"envoy.lb": {
+				Fields: fields,
+			},
+		},
+	}
+}
+
+func isSubsetLabelV2(matchString *apiv2.MatchString) bool {
+	return matchString.GetType() == apiv2.MatchString_EXACT &&
+		matchString.GetValueType() == apiv2.MatchString_TEXT
+}
+
+// subsetKeysV2 v2 路由规则中 destination 用到的实例标签集合，每组标签对应一个 subset selector
+func subsetKeysV2(rules []*apiv2.RuleRoutingConfig) [][]string {
+	var ret [][]string
+	exists := make(map[string]struct{})
+	for _, rule := range rules {
+		for _, destination := range rule.GetDestinations() {
+			var keys []string
+			for key, matchString := range destination.GetLabels() {
+				if isSubsetLabelV2(matchString) {
+					keys = append(keys, key)
+				}
+			}
+			if len(keys) == 0 {
+				continue
+			}
+			sort.Strings(keys)
+			id := strings.Join(keys, ",")
+			if _, ok := exists[id]; ok {
+				continue
+			}
+			exists[id] = struct{}{}
+			ret = append(ret, keys)
+		}
+	}
+	return ret
+}
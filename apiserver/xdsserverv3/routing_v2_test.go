@@ -0,0 +1,264 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package xdsserverv3
+
+import (
+	"testing"
+
+	route "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/stretchr/testify/assert"
+
+	apiv2 "github.com/polarismesh/polaris/common/api/v2"
+	"github.com/polarismesh/polaris/common/utils"
+)
+
+func buildRoutingV2(t *testing.T, id string, priority uint32, rule *apiv2.RuleRoutingConfig) *apiv2.Routing {
+	any, err := ptypes.MarshalAny(rule)
+	assert.NoError(t, err)
+	return &apiv2.Routing{
+		Id:            id,
+		Enable:        true,
+		Priority:      priority,
+		RoutingPolicy: apiv2.RoutingPolicy_RulePolicy,
+		RoutingConfig: any,
+	}
+}
+
+func TestMakeRoutesV2(t *testing.T) {
+	headerRule := &apiv2.RuleRoutingConfig{
+		Sources: []*apiv2.Source{
+			{
+				Service:   "*",
+				Namespace: "*",
+				Arguments: []*apiv2.SourceMatch{
+					{
+						Type: apiv2.SourceMatch_HEADER,
+						Key:  "user",
+						Value: &apiv2.MatchString{
+							Type:  apiv2.MatchString_IN,
+							Value: utils.NewStringValue("a, b"),
+						},
+					},
+					{
+						Type:  apiv2.SourceMatch_PATH,
+						Value: &apiv2.MatchString{Value: utils.NewStringValue("/echo")},
+					},
+				},
+			},
+			{
+				// 自定义参数 envoy 无法处理，忽略
+				Arguments: []*apiv2.SourceMatch{
+					{
+						Type:  apiv2.SourceMatch_CUSTOM,
+						Key:   "env",
+						Value: &apiv2.MatchString{Value: utils.NewStringValue("test")},
+					},
+				},
+			},
+		},
+		Destinations: []*apiv2.Destination{
+			{
+				Service:   "svc",
+				Namespace: "default",
+				Labels: map[string]*apiv2.MatchString{
+					"version": {Value: utils.NewStringValue("v2")},
+				},
+				Weight: 20,
+			},
+			{
+				Service:   "svc",
+				Namespace: "default",
+				Labels: map[string]*apiv2.MatchString{
+					"version": {Value: utils.NewStringValue("v1")},
+				},
+				Weight: 80,
+			},
+			{
+				// 低优先级的 destination 不生效
+				Service:   "svc",
+				Namespace: "default",
+				Labels: map[string]*apiv2.MatchString{
+					"version": {Value: utils.NewStringValue("v0")},
+				},
+				Priority: 1,
+				Weight:   100,
+			},
+			{
+				// 其他服务的 destination 忽略
+				Service:   "other",
+				Namespace: "default",
+			},
+		},
+	}
+	queryRule := &apiv2.RuleRoutingConfig{
+		Sources: []*apiv2.Source{
+			{
+				Arguments: []*apiv2.SourceMatch{
+					{
+						Type: apiv2.SourceMatch_QUERY,
+						Key:  "uid",
+						Value: &apiv2.MatchString{
+							Type:  apiv2.MatchString_REGEX,
+							Value: utils.NewStringValue("^1.*"),
+						},
+					},
+				},
+			},
+		},
+		Destinations: []*apiv2.Destination{
+			{
+				Service:   "*",
+				Namespace: "default",
+				Labels: map[string]*apiv2.MatchString{
+					"env": {Value: utils.NewStringValue("gray")},
+				},
+			},
+		},
+	}
+	matchAllRule := &apiv2.RuleRoutingConfig{
+		Sources: []*apiv2.Source{{Service: "*", Namespace: "*"}},
+		Destinations: []*apiv2.Destination{
+			{
+				Service:   "svc",
+				Namespace: "default",
+				Labels: map[string]*apiv2.MatchString{
+					"version": {Value: utils.NewStringValue("v1")},
+				},
+			},
+		},
+	}
+	otherRule := &apiv2.RuleRoutingConfig{
+		Sources:      []*apiv2.Source{{Service: "svc", Namespace: "default"}},
+		Destinations: []*apiv2.Destination{{Service: "other", Namespace: "default"}},
+	}
+
+	rules := makeInboundRoutingV2("svc", "default", []*apiv2.Routing{
+		buildRoutingV2(t, "match-all", 2, matchAllRule),
+		buildRoutingV2(t, "query", 1, queryRule),
+		buildRoutingV2(t, "other", 0, otherRule),
+		buildRoutingV2(t, "header", 0, headerRule),
+	})
+	assert.Equal(t, 3, len(rules))
+	assert.Equal(t, 3, len(rules[0].Destinations))
+
+	serviceInfo := &ServiceInfo{Name: "svc", Namespace: "default", RoutingV2: rules}
+	routes := makeRoutes(serviceInfo)
+	assert.Equal(t, 3, len(routes))
+
+	// header + path
+	assert.Equal(t, "/echo", routes[0].Match.GetPath())
+	assert.Equal(t, 1, len(routes[0].Match.Headers))
+	assert.Equal(t, "user", routes[0].Match.Headers[0].Name)
+	assert.Equal(t, "(a|b)", routes[0].Match.Headers[0].GetStringMatch().GetSafeRegex().GetRegex())
+	clusters := routes[0].GetRoute().GetWeightedClusters()
+	assert.Equal(t, uint32(100), clusters.TotalWeight.GetValue())
+	assert.Equal(t, 2, len(clusters.Clusters))
+	assert.Equal(t, uint32(20), clusters.Clusters[0].Weight.GetValue())
+	assert.Equal(t, "v2",
+		clusters.Clusters[0].MetadataMatch.FilterMetadata["envoy.lb"].Fields["version"].GetStringValue())
+
+	// query，未设置权重时各个 subset 权重相同
+	assert.Equal(t, "uid", routes[1].Match.QueryParameters[0].Name)
+	clusters = routes[1].GetRoute().GetWeightedClusters()
+	assert.Equal(t, uint32(1), clusters.TotalWeight.GetValue())
+
+	// 匹配所有请求的路由之后不再追加默认路由
+	_, ok := routes[2].Match.PathSpecifier.(*route.RouteMatch_Prefix)
+	assert.True(t, ok)
+	assert.NotNil(t, routes[2].GetRoute().GetWeightedClusters())
+
+	lbSubsetConfig := makeLbSubsetConfig(serviceInfo)
+	assert.Equal(t, 2, len(lbSubsetConfig.SubsetSelectors))
+	assert.Equal(t, []string{"version"}, lbSubsetConfig.SubsetSelectors[0].Keys)
+	assert.Equal(t, []string{"env"}, lbSubsetConfig.SubsetSelectors[1].Keys)
+}
+
+func TestMakeRoutesV2WithoutMatchAll(t *testing.T) {
+	rule := &apiv2.RuleRoutingConfig{
+		Sources: []*apiv2.Source{
+			{
+				Arguments: []*apiv2.SourceMatch{
+					{
+						Type: apiv2.SourceMatch_METHOD,
+						Value: &apiv2.MatchString{
+							Type:  apiv2.MatchString_NOT_EQUALS,
+							Value: utils.NewStringValue("GET"),
+						},
+					},
+				},
+			},
+		},
+		Destinations: []*apiv2.Destination{
+			{Service: "svc", Namespace: "default", Isolate: true},
+			{Service: "svc", Namespace: "default", Priority: 1},
+		},
+	}
+	serviceInfo := &ServiceInfo{
+		Name:      "svc",
+		Namespace: "default",
+		RoutingV2: makeInboundRoutingV2("svc", "default", []*apiv2.Routing{buildRoutingV2(t, "method", 0, rule)}),
+	}
+	routes := makeRoutes(serviceInfo)
+	assert.Equal(t, 2, len(routes))
+	assert.Equal(t, methodHeaderName, routes[0].Match.Headers[0].Name)
+	assert.True(t, routes[0].Match.Headers[0].InvertMatch)
+	// 被隔离的 destination 不参与路由
+	assert.Equal(t, 1, len(routes[0].GetRoute().GetWeightedClusters().Clusters))
+	assert.Nil(t, routes[0].GetRoute().GetWeightedClusters().Clusters[0].MetadataMatch)
+	assert.Equal(t, getDefaultRoute("svc"), routes[1])
+}
+
+func TestMakeRoutesV2WithCallerScoped(t *testing.T) {
+	rule := &apiv2.RuleRoutingConfig{
+		Sources: []*apiv2.Source{
+			{
+				// 限定了主调服务的 source 无法下发给 envoy，需要忽略
+				Service:   "caller",
+				Namespace: "default",
+				Arguments: []*apiv2.SourceMatch{
+					{
+						Type:  apiv2.SourceMatch_PATH,
+						Value: &apiv2.MatchString{Value: utils.NewStringValue("/caller")},
+					},
+				},
+			},
+			{
+				Service:   "*",
+				Namespace: "default",
+			},
+		},
+		Destinations: []*apiv2.Destination{
+			{
+				Service:   "svc",
+				Namespace: "default",
+				Labels: map[string]*apiv2.MatchString{
+					"version": {Value: utils.NewStringValue("v1")},
+				},
+			},
+		},
+	}
+	serviceInfo := &ServiceInfo{
+		Name:      "svc",
+		Namespace: "default",
+		RoutingV2: makeInboundRoutingV2("svc", "default", []*apiv2.Routing{buildRoutingV2(t, "caller", 0, rule)}),
+	}
+	routes := makeRoutes(serviceInfo)
+	assert.Equal(t, 1, len(routes))
+	assert.Equal(t, getDefaultRoute("svc"), routes[0])
+}
@@ -20,8 +20,10 @@ package xdsserverv3
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net"
+	"net/http"
 	"regexp"
 	"strconv"
 	"strings"
@@ -33,10 +35,12 @@ import (
 	route "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
 	envoy_extensions_common_ratelimit_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/common/ratelimit/v3"
 	lrl "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/local_ratelimit/v3"
+	hcm "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
 	clusterservice "github.com/envoyproxy/go-control-plane/envoy/service/cluster/v3"
 	discoverygrpc "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
 	endpointservice "github.com/envoyproxy/go-control-plane/envoy/service/endpoint/v3"
 	listenerservice "github.com/envoyproxy/go-control-plane/envoy/service/listener/v3"
+	rlsv3 "github.com/envoyproxy/go-control-plane/envoy/service/ratelimit/v3"
 	routeservice "github.com/envoyproxy/go-control-plane/envoy/service/route/v3"
 	runtimeservice "github.com/envoyproxy/go-control-plane/envoy/service/runtime/v3"
 	secretservice "github.com/envoyproxy/go-control-plane/envoy/service/secret/v3"
@@ -51,17 +55,22 @@ import (
 	"github.com/golang/protobuf/ptypes/wrappers"
 	"go.uber.org/atomic"
 	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/anypb"
 
 	"github.com/polarismesh/polaris/apiserver"
 	"github.com/polarismesh/polaris/bootstrap"
 	"github.com/polarismesh/polaris/cache"
 	api "github.com/polarismesh/polaris/common/api/v1"
+	apiv2 "github.com/polarismesh/polaris/common/api/v2"
 	"github.com/polarismesh/polaris/common/connlimit"
 	commonlog "github.com/polarismesh/polaris/common/log"
 	"github.com/polarismesh/polaris/common/model"
 	"github.com/polarismesh/polaris/namespace"
+	"github.com/polarismesh/polaris/plugin"
 	"github.com/polarismesh/polaris/service"
+	"github.com/polarismesh/polaris/store"
+	"github.com/polarismesh/polaris/store/boltdb"
 )
 
 const (
@@ -70,11 +79,16 @@ const (
 	K8sDnsResolveSuffixSvcClusterLocal = ".svc.cluster.local"
 )
 
+// fullSyncInterval 全量对账的间隔，正常情况下变更都通过缓存事件推送
+const fullSyncInterval = time.Minute
+
 const (
 	TLSModeTag        = "polarismesh.cn/tls-mode"
 	TLSModeNone       = "none"
 	TLSModeStrict     = "strict"
 	TLSModePermissive = "permissive"
+	// ServiceTag 节点所属的服务，mTLS 模式下只给节点下发该服务的工作负载证书
+	ServiceTag = "polarismesh.cn/service"
 )
 
 // XDSServer is the xDS server
@@ -91,8 +105,21 @@ type XDSServer struct {
 	connLimitConfig *connlimit.Config
 
 	registryInfo               map[string][]*ServiceInfo
+	changes                    *changeCollector
+	cancelSync                 context.CancelFunc
 	CircuitBreakerConfigGetter CircuitBreakerConfigGetter
 	RatelimitConfigGetter      RatelimitConfigGetter
+
+	// nodes 连接的 envoy 节点，供调试接口查询
+	nodes       *nodeTracker
+	debugPort   uint32
+	debugServer *http.Server
+	// ca 内置证书签发，开启后通过 SDS 下发网格 mTLS 使用的证书
+	ca *certificateAuthority
+	// tlsSnapshots mTLS 快照 key => 按照服务生成的快照 key，用于清理已删除服务的快照
+	tlsSnapshots map[string]map[string]struct{}
+	// rls 全局限流服务，开启后 envoy 通过它按照 polaris 的全局限流规则限流
+	rls *rateLimitService
 }
 
 // Initialize 初始化
@@ -103,6 +130,12 @@ func (x *XDSServer) Initialize(ctx context.Context, option map[string]interface{
 	x.registryInfo = make(map[string][]*ServiceInfo)
 	x.listenPort = uint32(option["listenPort"].(int))
 	x.listenIP = option["listenIP"].(string)
+	if debugPort, _ := option["debugPort"].(int); debugPort > 0 {
+		x.debugPort = uint32(debugPort)
+	}
+	if x.nodes == nil {
+		x.nodes = newNodeTracker()
+	}
 
 	x.versionNum = atomic.NewUint64(0)
 	var err error
@@ -121,6 +154,43 @@ func (x *XDSServer) Initialize(ctx context.Context, option map[string]interface{
 		x.connLimitConfig = connConfig
 	}
 
+	if raw, _ := option["sds"].(map[interface{}]interface{}); raw != nil {
+		sdsConf, err := parseSdsConfig(raw)
+		if err != nil {
+			return err
+		}
+		if sdsConf.Enable {
+			provider := plugin.GetKeyProvider()
+			// 集群部署时各个节点需要使用同一个根证书，否则不同节点下发的证书之间无法互相校验
+			if provider == nil && !isStandaloneStore() {
+				return errors.New("sds requires a shared keyProvider when polaris is deployed as a cluster")
+			}
+			if x.ca, err = newCertificateAuthority(sdsConf.CertTTL, provider); err != nil {
+				log.Errorf("init certificate authority err: %v", err)
+				return err
+			}
+		}
+	}
+
+	if raw, _ := option["ratelimit"].(map[interface{}]interface{}); raw != nil {
+		rlsConf, err := parseRateLimitServiceConfig(raw)
+		if err != nil {
+			return err
+		}
+		if rlsConf.Enable {
+			if x.rls, err = newRateLimitService(rlsConf, x.getRateLimitConf); err != nil {
+				log.Errorf("init rate limit service err: %v", err)
+				return err
+			}
+		}
+	}
+
+	// 先注册缓存监听，避免首次加载注册信息之后、同步任务启动之前的变更丢失
+	if x.changes == nil {
+		x.changes = newChangeCollector()
+		x.registerCacheListener()
+	}
+
 	err = x.initRegistryInfo()
 	if err != nil {
 		log.Errorf("%v", err)
@@ -148,7 +218,7 @@ func (x *XDSServer) Initialize(ctx context.Context, option map[string]interface{
 func (x *XDSServer) Run(errCh chan error) {
 	// 启动 grpc server
 	ctx := context.Background()
-	cb := &Callbacks{log: commonlog.GetScopeOrDefaultByName(commonlog.XDSLoggerName)}
+	cb := &Callbacks{log: commonlog.GetScopeOrDefaultByName(commonlog.XDSLoggerName), nodes: x.nodes}
 	srv := serverv3.NewServer(ctx, x.cache, cb)
 	var grpcOptions []grpc.ServerOption
 	grpcOptions = append(grpcOptions, grpc.MaxConcurrentStreams(1000))
@@ -175,6 +245,12 @@ func (x *XDSServer) Run(errCh chan error) {
 	}
 
 	registerServer(grpcServer, srv)
+	if x.rls != nil {
+		rlsv3.RegisterRateLimitServiceServer(grpcServer, x.rls)
+	}
+	if x.debugPort > 0 {
+		go x.runDebugServer(errCh)
+	}
 
 	log.Infof("management server listening on %d\n", x.listenPort)
 
@@ -200,10 +276,19 @@ func registerServer(grpcServer *grpc.Server, server serverv3.Server) {
 
 // Stop 停止服务
 func (x *XDSServer) Stop() {
+	if x.cancelSync != nil {
+		x.cancelSync()
+	}
 	connlimit.RemoveLimitListener(x.GetProtocol())
 	if x.server != nil {
 		x.server.Stop()
 	}
+	if x.debugServer != nil {
+		_ = x.debugServer.Close()
+	}
+	if x.rls != nil {
+		_ = x.rls.Close()
+	}
 }
 
 // Restart 重启服务
@@ -257,8 +342,15 @@ func (PolarisNodeHash) ID(node *core.Node) string {
 	}
 	ns, _, _ := parseNodeID(node.Id)
 	if node.Metadata != nil && node.Metadata.Fields != nil {
+		if node.Metadata.Fields[ProxylessTag].GetStringValue() == "true" {
+			return ns + proxylessKeySuffix
+		}
 		tlsMode := node.Metadata.Fields[TLSModeTag].GetStringValue()
 		if tlsMode == TLSModePermissive || tlsMode == TLSModeStrict {
+			// mTLS 模式下按照服务区分快照，节点只能拿到自身服务的证书
+			if svc := node.Metadata.Fields[ServiceTag].GetStringValue(); svc != "" {
+				return ns + "/" + tlsMode + "/" + svc
+			}
 			return ns + "/" + tlsMode
 		}
 	}
@@ -285,12 +377,29 @@ type ServiceInfo struct {
 	SvcInsRevision       string
 	Routing              *api.Routing
 	SvcRoutingRevision   string
+	RoutingV2            []*apiv2.RuleRoutingConfig
 	Ports                string
 	RateLimit            *api.RateLimit
 	SvcRateLimitRevision string
+	// FaultMirrors 故障注入与流量镜像规则
+	FaultMirrors           []*apiv2.FaultMirrorRule
+	SvcFaultMirrorRevision string
 }
 
 func makeLbSubsetConfig(serviceInfo *ServiceInfo) *cluster.Cluster_LbSubsetConfig {
+	if len(serviceInfo.RoutingV2) > 0 {
+		lbSubsetConfig := &cluster.Cluster_LbSubsetConfig{
+			FallbackPolicy: cluster.Cluster_LbSubsetConfig_ANY_ENDPOINT,
+		}
+		for _, keys := range subsetKeysV2(serviceInfo.RoutingV2) {
+			lbSubsetConfig.SubsetSelectors = append(lbSubsetConfig.SubsetSelectors,
+				&cluster.Cluster_LbSubsetConfig_LbSubsetSelector{
+					Keys:           keys,
+					FallbackPolicy: cluster.Cluster_LbSubsetConfig_LbSubsetSelector_NO_FALLBACK,
+				})
+		}
+		return lbSubsetConfig
+	}
 	if serviceInfo.Routing != nil && serviceInfo.Routing.Inbounds != nil &&
 		len(serviceInfo.Routing.Inbounds) > 0 {
 		lbSubsetConfig := &cluster.Cluster_LbSubsetConfig{}
@@ -391,26 +500,7 @@ func makeEndpoints(services []*ServiceInfo) []types.Resource {
 		for _, instance := range serviceInfo.Instances {
 			// 只加入健康的实例
 			if instance.Healthy.Value {
-				ep := &endpoint.LbEndpoint{
-					HostIdentifier: &endpoint.LbEndpoint_Endpoint{
-						Endpoint: &endpoint.Endpoint{
-							Address: &core.Address{
-								Address: &core.Address_SocketAddress{
-									SocketAddress: &core.SocketAddress{
-										Protocol: core.SocketAddress_TCP,
-										Address:  instance.Host.Value,
-										PortSpecifier: &core.SocketAddress_PortValue{
-											PortValue: instance.Port.Value,
-										},
-									},
-								},
-							},
-						},
-					},
-					Metadata: getEndpointMetaFromPolarisIns(instance),
-				}
-
-				lbEndpoints = append(lbEndpoints, ep)
+				lbEndpoints = append(lbEndpoints, makeLbEndpoint(instance))
 			}
 		}
 
@@ -429,7 +519,32 @@ func makeEndpoints(services []*ServiceInfo) []types.Resource {
 	return clusterLoads
 }
 
+func makeLbEndpoint(instance *api.Instance) *endpoint.LbEndpoint {
+	return &endpoint.LbEndpoint{
+		HostIdentifier: &endpoint.LbEndpoint_Endpoint{
+			Endpoint: &endpoint.Endpoint{
+				Address: &core.Address{
+					Address: &core.Address_SocketAddress{
+						SocketAddress: &core.SocketAddress{
+							Protocol: core.SocketAddress_TCP,
+							Address:  instance.Host.Value,
+							PortSpecifier: &core.SocketAddress_PortValue{
+								PortValue: instance.Port.Value,
+							},
+						},
+					},
+				},
+			},
+		},
+		Metadata: getEndpointMetaFromPolarisIns(instance),
+	}
+}
+
 func makeRoutes(serviceInfo *ServiceInfo) []*route.Route {
+	// 存在 v2 的规则路由时优先使用，v1 的路由规则只作为兼容
+	if len(serviceInfo.RoutingV2) > 0 {
+		return makeRoutesV2(serviceInfo)
+	}
 	var routes []*route.Route
 	var matchAllRoute *route.Route
 	// 路由目前只处理 inbounds
@@ -719,23 +834,42 @@ func makeLocalRateLimit(conf []*model.RateLimit) map[string]*anypb.Any {
 	return nil
 }
 
+func (x *XDSServer) getRateLimitConf(serviceID string) []*model.RateLimit {
+	if x.RatelimitConfigGetter != nil {
+		return x.RatelimitConfigGetter(serviceID)
+	}
+	return x.namingServer.Cache().RateLimit().GetRateLimitByServiceID(serviceID)
+}
+
+// httpFilters 需要额外挂载在 router 之前的 http filter
+func (x *XDSServer) httpFilters(services []*ServiceInfo) []*hcm.HttpFilter {
+	var filters []*hcm.HttpFilter
+	if hasFaultInjection(services) {
+		filters = append(filters, makeFaultHTTPFilter())
+	}
+	if x.rls != nil {
+		filters = append(filters, x.rls.makeHTTPFilter())
+	}
+	return filters
+}
+
 func (x *XDSServer) makeVirtualHosts(services []*ServiceInfo) []types.Resource {
 	// 每个 polaris serviceInfo 对应一个 virtualHost
 	var routeConfs []types.Resource
 	var hosts []*route.VirtualHost
 
 	for _, serviceInfo := range services {
-		ratelimitGetter := x.RatelimitConfigGetter
-		if ratelimitGetter == nil {
-			ratelimitGetter = x.namingServer.Cache().RateLimit().GetRateLimitByServiceID
-		}
-		rateLimitConf := ratelimitGetter(serviceInfo.ID)
-		hosts = append(hosts, &route.VirtualHost{
+		rateLimitConf := x.getRateLimitConf(serviceInfo.ID)
+		host := &route.VirtualHost{
 			Name:                 serviceInfo.Name,
 			Domains:              generateServiceDomains(serviceInfo),
-			Routes:               makeRoutes(serviceInfo),
+			Routes:               makeFaultMirrorRoutes(serviceInfo, makeRoutes(serviceInfo)),
 			TypedPerFilterConfig: makeLocalRateLimit(rateLimitConf),
-		})
+		}
+		if x.rls != nil {
+			host.RateLimits = makeGlobalRateLimits(serviceInfo.ID, rateLimitConf)
+		}
+		hosts = append(hosts, host)
 	}
 
 	// 最后是 allow_any
@@ -778,6 +912,7 @@ func (x *XDSServer) pushRegistryInfoToXDSCache(registryInfo map[string][]*Servic
 		_ = x.makeSnapshot(ns, versionLocal, services)
 		_ = x.makePermissiveSnapshot(ns, versionLocal, services)
 		_ = x.makeStrictSnapshot(ns, versionLocal, services)
+		_ = x.makeProxylessSnapshot(ns, versionLocal, services)
 	}
 	return nil
 }
@@ -787,23 +922,8 @@ func (x *XDSServer) makeSnapshot(ns, version string, services []*ServiceInfo) (e
 	resources[resource.EndpointType] = makeEndpoints(services)
 	resources[resource.ClusterType] = x.makeClusters(services)
 	resources[resource.RouteType] = x.makeVirtualHosts(services)
-	resources[resource.ListenerType] = makeListeners()
-	snapshot, err := cachev3.NewSnapshot(version, resources)
-	if err != nil {
-		log.Errorf("fail to create snapshot for %s, err is %v", ns, err)
-		return err
-	}
-	err = snapshot.Consistent()
-	if err != nil {
-		return err
-	}
-	log.Infof("will serve ns: %s ,snapshot: %+v", ns, string(dumpSnapShotJSON(snapshot)))
-	// 为每个 ns 刷写 cache ，推送 xds 更新
-	if err := x.cache.SetSnapshot(context.Background(), ns, snapshot); err != nil {
-		log.Errorf("snapshot error %q for %+v", err, snapshot)
-		return err
-	}
-	return
+	resources[resource.ListenerType] = makeListeners(x.httpFilters(services)...)
+	return x.setSnapshot(ns, version, resources)
 }
 
 func (x *XDSServer) makePermissiveSnapshot(ns, version string, services []*ServiceInfo) (err error) {
@@ -811,22 +931,8 @@ func (x *XDSServer) makePermissiveSnapshot(ns, version string, services []*Servi
 	resources[resource.EndpointType] = makeEndpoints(services)
 	resources[resource.ClusterType] = x.makePermissiveClusters(services)
 	resources[resource.RouteType] = x.makeVirtualHosts(services)
-	resources[resource.ListenerType] = makePermissiveListeners()
-	snapshot, err := cachev3.NewSnapshot(version, resources)
-	if err != nil {
-		return err
-	}
-	err = snapshot.Consistent()
-	if err != nil {
-		return err
-	}
-	log.Infof("will serve ns: %s ,mode permissive,snapshot: %+v", ns, string(dumpSnapShotJSON(snapshot)))
-	// 为每个 ns 刷写 cache ，推送 xds 更新
-	if err := x.cache.SetSnapshot(context.Background(), ns+"/permissive", snapshot); err != nil {
-		log.Errorf("snapshot error %q for %+v", err, snapshot)
-		return err
-	}
-	return
+	resources[resource.ListenerType] = makePermissiveListeners(x.httpFilters(services)...)
+	return x.setTLSSnapshots(ns, ns+"/"+TLSModePermissive, version, resources, services)
 }
 
 func (x *XDSServer) makeStrictSnapshot(ns, version string, services []*ServiceInfo) (err error) {
@@ -834,49 +940,117 @@ func (x *XDSServer) makeStrictSnapshot(ns, version string, services []*ServiceIn
 	resources[resource.EndpointType] = makeEndpoints(services)
 	resources[resource.ClusterType] = x.makeStrictClusters(services)
 	resources[resource.RouteType] = x.makeVirtualHosts(services)
-	resources[resource.ListenerType] = makeStrictListeners()
+	resources[resource.ListenerType] = makeStrictListeners(x.httpFilters(services)...)
+	return x.setTLSSnapshots(ns, ns+"/"+TLSModeStrict, version, resources, services)
+}
+
+// setTLSSnapshots 开启证书签发时，为命名空间下的每个服务生成单独的 mTLS 快照，
+// 快照中只包含该服务自身的工作负载证书；没有声明服务的节点使用 key 对应的快照，只下发根证书
+func (x *XDSServer) setTLSSnapshots(ns, key, version string, resources map[resource.Type][]types.Resource,
+	services []*ServiceInfo) error {
+	if x.ca == nil {
+		return x.setSnapshot(key, version, resources)
+	}
+	resources[resource.SecretType] = x.ca.makeSecrets(ns, "")
+	if err := x.setSnapshot(key, version, resources); err != nil {
+		return err
+	}
+
+	keys := make(map[string]struct{}, len(services))
+	for _, svc := range services {
+		svcKey := key + "/" + svc.Name
+		keys[svcKey] = struct{}{}
+		resources[resource.SecretType] = x.ca.makeSecrets(ns, svc.Name)
+		if err := x.setSnapshot(svcKey, version, resources); err != nil {
+			return err
+		}
+	}
+	if x.tlsSnapshots == nil {
+		x.tlsSnapshots = make(map[string]map[string]struct{})
+	}
+	for svcKey := range x.tlsSnapshots[key] {
+		if _, ok := keys[svcKey]; !ok {
+			x.cache.ClearSnapshot(svcKey)
+		}
+	}
+	x.tlsSnapshots[key] = keys
+	return nil
+}
+
+// isStandaloneStore 使用单机的 boltdb 存储时认为是单机部署
+func isStandaloneStore() bool {
+	s, err := store.GetStore()
+	if err != nil {
+		return false
+	}
+	return s.Name() == boltdb.STORENAME
+}
+
+// setSnapshot 刷写 key 对应的快照。内容没有变化的资源类型沿用上一次的版本号，
+// 这样 envoy 只会收到真正发生变化的资源类型；所有资源都没有变化时不刷写
+func (x *XDSServer) setSnapshot(key, version string, resources map[resource.Type][]types.Resource) error {
 	snapshot, err := cachev3.NewSnapshot(version, resources)
 	if err != nil {
+		log.Errorf("fail to create snapshot for %s, err is %v", key, err)
 		return err
 	}
-	err = snapshot.Consistent()
-	if err != nil {
+	if err = snapshot.Consistent(); err != nil {
 		return err
 	}
-	log.Infof("will serve ns: %s ,mode strict,snapshot: %+v", ns, string(dumpSnapShotJSON(snapshot)))
+
+	if previous, err := x.cache.GetSnapshot(key); err == nil {
+		changed := false
+		for typ := range resources {
+			index := cachev3.GetResponseType(typ)
+			if equalResources(previous.GetResources(typ), snapshot.Resources[index].Items) {
+				snapshot.Resources[index].Version = previous.GetVersion(typ)
+				continue
+			}
+			changed = true
+		}
+		if !changed {
+			log.Debugf("snapshot of %s not changed, skip it", key)
+			return nil
+		}
+	}
+
+	log.Infof("will serve %s, snapshot: %+v", key, string(dumpSnapShotJSON(snapshot)))
 	// 为每个 ns 刷写 cache ，推送 xds 更新
-	if err := x.cache.SetSnapshot(context.Background(), ns+"/strict", snapshot); err != nil {
+	if err := x.cache.SetSnapshot(context.Background(), key, snapshot); err != nil {
 		log.Errorf("snapshot error %q for %+v", err, snapshot)
 		return err
 	}
-	return
+	return nil
 }
 
-// syncPolarisServiceInfo 初始化本地 cache，初始化 xds cache
-func (x *XDSServer) getRegistryInfoWithCache(ctx context.Context, registryInfo map[string][]*ServiceInfo) error {
-	// 从 cache 中获取全量的服务信息
-	serviceIterProc := func(key string, value *model.Service) (bool, error) {
-		if _, ok := registryInfo[value.Namespace]; !ok {
-			registryInfo[value.Namespace] = []*ServiceInfo{}
+func equalResources(previous map[string]types.Resource, current map[string]types.ResourceWithTTL) bool {
+	if len(previous) != len(current) {
+		return false
+	}
+	for name, item := range current {
+		old, ok := previous[name]
+		if !ok || !proto.Equal(old, item.Resource) {
+			return false
 		}
+	}
+	return true
+}
 
-		info := &ServiceInfo{
-			ID:        value.ID,
-			Name:      value.Name,
-			Namespace: value.Namespace,
-			Instances: []*api.Instance{},
-			Ports:     value.Ports,
-		}
+// syncPolarisServiceInfo 初始化本地 cache，初始化 xds cache
+func (x *XDSServer) getRegistryInfoWithCache(ctx context.Context, registryInfo map[string][]*ServiceInfo) error {
+	return x.loadRegistryInfo(ctx, registryInfo, func(*model.Service) bool {
+		return true
+	})
+}
 
-		if info.Ports == "" {
-			ports := x.namingServer.Cache().Instance().GetServicePorts(value.ID)
-			if len(ports) != 0 {
-				info.Ports = strings.Join(ports, ",")
-			}
+// loadRegistryInfo 从 cache 中获取满足条件的服务信息
+func (x *XDSServer) loadRegistryInfo(ctx context.Context, registryInfo map[string][]*ServiceInfo,
+	filter func(*model.Service) bool) error {
+	var services []*model.Service
+	serviceIterProc := func(key string, value *model.Service) (bool, error) {
+		if filter(value) {
+			services = append(services, value)
 		}
-
-		registryInfo[value.Namespace] = append(registryInfo[value.Namespace], info)
-
 		return true, nil
 	}
 
@@ -886,56 +1060,96 @@ func (x *XDSServer) getRegistryInfoWithCache(ctx context.Context, registryInfo m
 	}
 
 	// 遍历每一个服务，获取路由、熔断策略和全量的服务实例信息
-	for _, v := range registryInfo {
-		for _, svc := range v {
-			s := &api.Service{
-				Name: &wrappers.StringValue{
-					Value: svc.Name,
-				},
-				Namespace: &wrappers.StringValue{
-					Value: svc.Namespace,
-				},
-				Revision: &wrappers.StringValue{
-					Value: "-1",
-				},
-			}
+	for _, svc := range services {
+		info, err := x.makeServiceInfo(ctx, svc)
+		if err != nil {
+			return err
+		}
+		registryInfo[svc.Namespace] = append(registryInfo[svc.Namespace], info)
+	}
 
-			// 获取routing配置
-			routeResp := x.namingServer.GetRoutingConfigWithCache(ctx, s)
-			if routeResp.GetCode().Value != api.ExecuteSuccess {
-				log.Errorf("error sync routing for %s, info : %s", svc.Name, routeResp.Info.GetValue())
-				return fmt.Errorf("[XDSV3] error sync routing for %s", svc.Name)
-			}
+	return nil
+}
 
-			if routeResp.Routing != nil {
-				svc.SvcRoutingRevision = routeResp.Routing.Revision.Value
-				svc.Routing = routeResp.Routing
-			}
+// makeServiceInfo 获取单个服务的路由、实例以及限流信息
+func (x *XDSServer) makeServiceInfo(ctx context.Context, value *model.Service) (*ServiceInfo, error) {
+	svc := &ServiceInfo{
+		ID:        value.ID,
+		Name:      value.Name,
+		Namespace: value.Namespace,
+		Instances: []*api.Instance{},
+		Ports:     value.Ports,
+	}
 
-			// 获取instance配置
-			resp := x.namingServer.ServiceInstancesCache(context.TODO(), s)
-			if resp.GetCode().Value != api.ExecuteSuccess {
-				log.Errorf("[XDSV3] error sync instances for %s, info : %s", svc.Name, resp.Info.GetValue())
-				return fmt.Errorf("error sync instances for %s", svc.Name)
-			}
+	if svc.Ports == "" {
+		ports := x.namingServer.Cache().Instance().GetServicePorts(value.ID)
+		if len(ports) != 0 {
+			svc.Ports = strings.Join(ports, ",")
+		}
+	}
 
-			svc.SvcInsRevision = resp.Service.Revision.Value
-			svc.Instances = resp.Instances
+	s := &api.Service{
+		Name: &wrappers.StringValue{
+			Value: svc.Name,
+		},
+		Namespace: &wrappers.StringValue{
+			Value: svc.Namespace,
+		},
+		Revision: &wrappers.StringValue{
+			Value: "-1",
+		},
+	}
 
-			// 获取ratelimit配置
-			ratelimitResp := x.namingServer.GetRateLimitWithCache(ctx, s)
-			if ratelimitResp.GetCode().Value != api.ExecuteSuccess {
-				log.Errorf("[XDSV3] error sync ratelimit for %s, info : %s", svc.Name, ratelimitResp.Info.GetValue())
-				return fmt.Errorf("error sync ratelimit for %s", svc.Name)
-			}
-			if ratelimitResp.RateLimit != nil {
-				svc.SvcRateLimitRevision = ratelimitResp.RateLimit.Revision.Value
-				svc.RateLimit = ratelimitResp.RateLimit
-			}
-		}
+	// 获取routing配置
+	routeResp := x.namingServer.GetRoutingConfigWithCache(ctx, s)
+	if routeResp.GetCode().Value != api.ExecuteSuccess {
+		log.Errorf("error sync routing for %s, info : %s", svc.Name, routeResp.Info.GetValue())
+		return nil, fmt.Errorf("[XDSV3] error sync routing for %s", svc.Name)
 	}
 
-	return nil
+	if routeResp.Routing != nil {
+		svc.SvcRoutingRevision = routeResp.Routing.Revision.Value
+		svc.Routing = routeResp.Routing
+	}
+
+	// 获取 v2 版本的规则路由配置
+	routingsV2, err := x.namingServer.Cache().RoutingConfig().GetRoutingConfigV2(svc.ID, svc.Name, svc.Namespace)
+	if err != nil {
+		log.Errorf("[XDSV3] error sync routing v2 for %s, err : %v", svc.Name, err)
+		return nil, fmt.Errorf("error sync routing v2 for %s", svc.Name)
+	}
+	svc.RoutingV2 = makeInboundRoutingV2(svc.Name, svc.Namespace, routingsV2)
+
+	// 获取instance配置
+	resp := x.namingServer.ServiceInstancesCache(context.TODO(), s)
+	if resp.GetCode().Value != api.ExecuteSuccess {
+		log.Errorf("[XDSV3] error sync instances for %s, info : %s", svc.Name, resp.Info.GetValue())
+		return nil, fmt.Errorf("error sync instances for %s", svc.Name)
+	}
+
+	svc.SvcInsRevision = resp.Service.Revision.Value
+	svc.Instances = resp.Instances
+
+	// 获取ratelimit配置
+	ratelimitResp := x.namingServer.GetRateLimitWithCache(ctx, s)
+	if ratelimitResp.GetCode().Value != api.ExecuteSuccess {
+		log.Errorf("[XDSV3] error sync ratelimit for %s, info : %s", svc.Name, ratelimitResp.Info.GetValue())
+		return nil, fmt.Errorf("error sync ratelimit for %s", svc.Name)
+	}
+	if ratelimitResp.RateLimit != nil {
+		svc.SvcRateLimitRevision = ratelimitResp.RateLimit.Revision.Value
+		svc.RateLimit = ratelimitResp.RateLimit
+	}
+
+	// 获取故障注入与流量镜像规则
+	faultMirrors, revision, err := x.namingServer.Cache().FaultMirror().GetFaultMirrorRules(svc.Name, svc.Namespace)
+	if err != nil {
+		log.Errorf("[XDSV3] error sync fault mirror rules for %s, err : %v", svc.Name, err)
+		return nil, fmt.Errorf("error sync fault mirror rules for %s", svc.Name)
+	}
+	svc.FaultMirrors = faultMirrors
+	svc.SvcFaultMirrorRevision = revision
+	return svc, nil
 }
 
 func (x *XDSServer) initRegistryInfo() error {
@@ -957,66 +1171,199 @@ func (x *XDSServer) initRegistryInfo() error {
 	return nil
 }
 
+// registerCacheListener 监听服务、实例、路由、限流以及故障注入与流量镜像规则的缓存变更
+func (x *XDSServer) registerCacheListener() {
+	cacheMgr := x.namingServer.Cache()
+	listeners := []cache.Listener{x.changes}
+	cacheMgr.AddListener(cache.CacheNameService, listeners)
+	cacheMgr.AddListener(cache.CacheNameInstance, listeners)
+	cacheMgr.AddListener(cache.CacheNameRoutingConfig, listeners)
+	cacheMgr.AddListener(cache.CacheNameRateLimit, listeners)
+	cacheMgr.AddListener(cache.CacheNameFaultMirror, listeners)
+}
+
 func (x *XDSServer) startSynTask(ctx context.Context) error {
-	// 读取 polaris 缓存数据
-	synXdsConfFunc := func() {
-		registryInfo := make(map[string][]*ServiceInfo)
+	ctx, x.cancelSync = context.WithCancel(ctx)
 
-		err := x.getRegistryInfoWithCache(ctx, registryInfo)
-		if err != nil {
+	go func() {
+		// 变更事件在每一轮缓存更新后合并推送，全量对账只作为兜底
+		eventTicker := time.NewTicker(cache.UpdateCacheInterval)
+		defer eventTicker.Stop()
+		fullTicker := time.NewTicker(fullSyncInterval)
+		defer fullTicker.Stop()
+		for {
+			select {
+			case <-eventTicker.C:
+				x.syncChanges(ctx, x.changes.take())
+			case <-fullTicker.C:
+				x.syncRegistryInfo(ctx)
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return nil
+}
+
+// syncChanges 只重建发生变更的服务，并推送这些服务所在的命名空间
+func (x *XDSServer) syncChanges(ctx context.Context, changes *pendingChanges) {
+	if changes.isEmpty() {
+		return
+	}
+	if changes.all {
+		registryInfo := make(map[string][]*ServiceInfo)
+		if err := x.getRegistryInfoWithCache(ctx, registryInfo); err != nil {
 			log.Errorf("get registry info from cache error %v", err)
 			return
 		}
+		for ns := range x.registryInfo {
+			if _, ok := registryInfo[ns]; !ok {
+				registryInfo[ns] = []*ServiceInfo{}
+			}
+		}
+		x.updateRegistryInfo(registryInfo)
+		return
+	}
 
-		needPush := make(map[string][]*ServiceInfo)
+	serviceCache := x.namingServer.Cache().Service()
+	for key := range changes.names {
+		if svc := serviceCache.GetServiceByName(key.Name, key.Namespace); svc != nil {
+			changes.services[svc.ID] = svc.Namespace
+		}
+	}
+	dirty := make(map[string]map[string]struct{})
+	for id, ns := range changes.services {
+		if svc := serviceCache.GetServiceByID(id); svc != nil {
+			ns = svc.Namespace
+		}
+		if _, ok := changes.namespaces[ns]; ns == "" || ok {
+			continue
+		}
+		if _, ok := dirty[ns]; !ok {
+			dirty[ns] = make(map[string]struct{})
+		}
+		dirty[ns][id] = struct{}{}
+	}
 
-		// 处理删除 ns 中最后一个 service
-		for ns, infos := range x.registryInfo {
-			_, ok := registryInfo[ns]
-			if !ok && len(infos) > 0 {
-				// 这一次轮询时，该命名空间下的最后一个服务已经被删除了，此时，当前的命名空间需要处理
-				needPush[ns] = []*ServiceInfo{}
-				x.registryInfo[ns] = []*ServiceInfo{}
-			}
+	needPush := make(map[string][]*ServiceInfo)
+	for ns := range changes.namespaces {
+		registryInfo := map[string][]*ServiceInfo{ns: {}}
+		err := x.loadRegistryInfo(ctx, registryInfo, func(svc *model.Service) bool {
+			return svc.Namespace == ns
+		})
+		if err != nil {
+			log.Errorf("get registry info of namespace %s from cache error %v", ns, err)
+			continue
 		}
+		needPush[ns] = registryInfo[ns]
+	}
+	for ns, ids := range dirty {
+		infos, err := x.refreshServiceInfos(ctx, x.registryInfo[ns], ids)
+		if err != nil {
+			log.Errorf("refresh registry info of namespace %s error %v", ns, err)
+			continue
+		}
+		needPush[ns] = infos
+	}
+	x.updateRegistryInfo(needPush)
+}
 
-		// 与本地缓存对比，是否发生了变化，对发生变化的命名空间，推送配置
-		for ns, infos := range registryInfo {
-			cacheServiceInfos, ok := x.registryInfo[ns]
-			if !ok {
-				// 新命名空间，需要处理
-				needPush[ns] = infos
-				x.registryInfo[ns] = infos
-				continue
-			}
+// refreshServiceInfos 重建命名空间中发生变化的服务，其余服务沿用原来的信息
+func (x *XDSServer) refreshServiceInfos(ctx context.Context, infos []*ServiceInfo,
+	ids map[string]struct{}) ([]*ServiceInfo, error) {
+	serviceCache := x.namingServer.Cache().Service()
+	refreshed := make(map[string]struct{}, len(ids))
+	ret := make([]*ServiceInfo, 0, len(infos)+len(ids))
+	refresh := func(id string) error {
+		refreshed[id] = struct{}{}
+		svc := serviceCache.GetServiceByID(id)
+		if svc == nil {
+			// 服务已经被删除
+			return nil
+		}
+		info, err := x.makeServiceInfo(ctx, svc)
+		if err != nil {
+			return err
+		}
+		ret = append(ret, info)
+		return nil
+	}
 
-			// todo 不考虑命名空间删除的情况
-			// 判断当前这个空间，是否需要更新配置
-			if x.checkUpdate(infos, cacheServiceInfos) {
-				needPush[ns] = infos
-				x.registryInfo[ns] = infos
-			}
+	for _, info := range infos {
+		if _, ok := ids[info.ID]; !ok {
+			ret = append(ret, info)
+			continue
+		}
+		if err := refresh(info.ID); err != nil {
+			return nil, err
 		}
+	}
+	// 新增的服务
+	for id := range ids {
+		if _, ok := refreshed[id]; ok {
+			continue
+		}
+		if err := refresh(id); err != nil {
+			return nil, err
+		}
+	}
+	return ret, nil
+}
+
+// syncRegistryInfo 全量对账，作为事件推送的兜底
+func (x *XDSServer) syncRegistryInfo(ctx context.Context) {
+	registryInfo := make(map[string][]*ServiceInfo)
+
+	err := x.getRegistryInfoWithCache(ctx, registryInfo)
+	if err != nil {
+		log.Errorf("get registry info from cache error %v", err)
+		return
+	}
 
-		if len(needPush) > 0 {
-			_ = x.pushRegistryInfoToXDSCache(needPush)
+	needPush := make(map[string][]*ServiceInfo)
+
+	// 处理删除 ns 中最后一个 service
+	for ns, infos := range x.registryInfo {
+		_, ok := registryInfo[ns]
+		if !ok && len(infos) > 0 {
+			// 这一次轮询时，该命名空间下的最后一个服务已经被删除了，此时，当前的命名空间需要处理
+			needPush[ns] = []*ServiceInfo{}
 		}
 	}
 
-	go func() {
-		ticker := time.NewTicker(5 * cache.UpdateCacheInterval)
-		defer ticker.Stop()
-		for {
-			select {
-			case <-ticker.C:
-				synXdsConfFunc()
-			case <-ctx.Done():
-				return
+	// 证书需要轮转时，所有命名空间都需要重新推送
+	rotate := x.ca != nil && x.ca.needRotate()
+
+	// 与本地缓存对比，是否发生了变化，对发生变化的命名空间，推送配置
+	for ns, infos := range registryInfo {
+		cacheServiceInfos, ok := x.registryInfo[ns]
+		// todo 不考虑命名空间删除的情况
+		// 新命名空间，或者当前这个空间的配置发生了变化
+		if !ok || rotate || x.checkUpdate(infos, cacheServiceInfos) {
+			needPush[ns] = infos
+		}
+	}
+	if rotate {
+		for ns, infos := range x.registryInfo {
+			if _, ok := needPush[ns]; !ok {
+				needPush[ns] = infos
 			}
 		}
-	}()
+	}
 
-	return nil
+	x.updateRegistryInfo(needPush)
+}
+
+// updateRegistryInfo 更新本地的注册信息，并推送到 xds cache
+func (x *XDSServer) updateRegistryInfo(needPush map[string][]*ServiceInfo) {
+	if len(needPush) == 0 {
+		return
+	}
+	for ns, infos := range needPush {
+		x.registryInfo[ns] = infos
+	}
+	_ = x.pushRegistryInfoToXDSCache(needPush)
 }
 
 func (x *XDSServer) checkUpdate(curServiceInfo, cacheServiceInfo []*ServiceInfo) bool {
@@ -1038,6 +1385,9 @@ func (x *XDSServer) checkUpdate(curServiceInfo, cacheServiceInfo []*ServiceInfo)
 				if info.SvcRateLimitRevision != serviceInfo.SvcRateLimitRevision {
 					return true
 				}
+				if info.SvcFaultMirrorRevision != serviceInfo.SvcFaultMirrorRevision {
+					return true
+				}
 
 				find = true
 			}
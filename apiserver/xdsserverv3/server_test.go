@@ -30,6 +30,7 @@ import (
 	lrl "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/local_ratelimit/v3"
 	envoy_type_v3 "github.com/envoyproxy/go-control-plane/envoy/type/v3"
 	"github.com/envoyproxy/go-control-plane/pkg/cache/v3"
+	resource "github.com/envoyproxy/go-control-plane/pkg/resource/v3"
 	"github.com/golang/protobuf/ptypes"
 	"github.com/golang/protobuf/ptypes/duration"
 	_struct "github.com/golang/protobuf/ptypes/struct"
@@ -286,6 +287,26 @@ func TestNodeHashID(t *testing.T) {
 			},
 			TargetID: "default",
 		},
+		{
+			Node: &core.Node{
+				Id: "default/9b9f5630-81a1-47cd-a558-036eb616dc71~172.17.1.1",
+				Metadata: &_struct.Struct{
+					Fields: map[string]*structpb.Value{
+						TLSModeTag: &_struct.Value{
+							Kind: &_struct.Value_StringValue{
+								StringValue: TLSModeStrict,
+							},
+						},
+						ServiceTag: &_struct.Value{
+							Kind: &_struct.Value_StringValue{
+								StringValue: "echo",
+							},
+						},
+					},
+				},
+			},
+			TargetID: "default/" + TLSModeStrict + "/echo",
+		},
 		// bad case: wrong tls mode
 		{
 			Node: &core.Node{
@@ -379,3 +400,43 @@ func TestSnapshot(t *testing.T) {
 		t.Fatal(string(dumpYaml))
 	}
 }
+
+func TestSnapshotKeepVersionOfUnchangedResources(t *testing.T) {
+	sis := map[string][]*ServiceInfo{}
+	json.Unmarshal(testServicesData, &sis)
+
+	x := XDSServer{
+		CircuitBreakerConfigGetter: func(id string) *model.ServiceWithCircuitBreaker {
+			return nil
+		},
+		RatelimitConfigGetter: func(serviceID string) []*model.RateLimit { return nil },
+		versionNum:            atomic.NewUint64(1),
+		cache:                 cache.NewSnapshotCache(true, cache.IDHash{}, nil),
+	}
+	x.pushRegistryInfoToXDSCache(sis)
+	first, _ := x.cache.GetSnapshot("default")
+
+	// 内容没有变化，不刷写快照
+	x.pushRegistryInfoToXDSCache(sis)
+	second, _ := x.cache.GetSnapshot("default")
+	if first != second {
+		t.Fatal("snapshot should not be replaced when nothing changed")
+	}
+
+	// 只修改实例，只有 endpoint 的版本号发生变化
+	for _, info := range sis["default"] {
+		for _, ins := range info.Instances {
+			ins.Port = &wrappers.UInt32Value{Value: ins.GetPort().GetValue() + 1}
+		}
+	}
+	x.pushRegistryInfoToXDSCache(sis)
+	third, _ := x.cache.GetSnapshot("default")
+	if first.GetVersion(resource.EndpointType) == third.GetVersion(resource.EndpointType) {
+		t.Fatal("endpoint version should be changed")
+	}
+	for _, typ := range []string{resource.ClusterType, resource.RouteType, resource.ListenerType} {
+		if first.GetVersion(typ) != third.GetVersion(typ) {
+			t.Fatalf("%s version should not be changed", typ)
+		}
+	}
+}
@@ -93,9 +93,9 @@ func (d *AuthTestSuit) loadConfig() error {
 	d.cfg = new(TestConfig)
 
 	confFileName := testdata.Path("auth_test.yaml")
-	if os.Getenv("STORE_MODE") == "sqldb" {
-		fmt.Printf("run store mode : sqldb\n")
-		confFileName = testdata.Path("auth_test_sqldb.yaml")
+	if storeMode := os.Getenv("STORE_MODE"); storeMode == "sqldb" || storeMode == "pgsql" {
+		fmt.Printf("run store mode : %s\n", storeMode)
+		confFileName = testdata.Path("auth_test_" + storeMode + ".yaml")
 		d.defaultCtx = context.WithValue(d.defaultCtx, utils.ContextAuthTokenKey,
 			"nu/0WRA4EqSR1FagrjRj0fZwPXuGlMpX+zCuWu4uMqy8xr1vRjisSbA25aAC3mtU8MeeRsKhQiDAynUR09I=")
 	}
@@ -207,7 +207,7 @@ func (d *AuthTestSuit) Destroy() {
 }
 
 func (d *AuthTestSuit) cleanAllUser() {
-	if d.storage.Name() == sqldb.STORENAME {
+	if sqldb.IsSqlStore(d.storage.Name()) {
 		func() {
 			tx, err := d.storage.StartTx()
 			if err != nil {
@@ -247,7 +247,7 @@ func (d *AuthTestSuit) cleanAllUser() {
 }
 
 func (d *AuthTestSuit) cleanAllUserGroup() {
-	if d.storage.Name() == sqldb.STORENAME {
+	if sqldb.IsSqlStore(d.storage.Name()) {
 		func() {
 			tx, err := d.storage.StartTx()
 			if err != nil {
@@ -291,7 +291,7 @@ func (d *AuthTestSuit) cleanAllUserGroup() {
 }
 
 func (d *AuthTestSuit) cleanAllAuthStrategy() {
-	if d.storage.Name() == sqldb.STORENAME {
+	if sqldb.IsSqlStore(d.storage.Name()) {
 		func() {
 			tx, err := d.storage.StartTx()
 			if err != nil {
@@ -0,0 +1,63 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package bootstrap
+
+import (
+	"fmt"
+
+	boot_config "github.com/polarismesh/polaris/bootstrap/config"
+	"github.com/polarismesh/polaris/common/log"
+	"github.com/polarismesh/polaris/plugin"
+	"github.com/polarismesh/polaris/store/sqldb"
+)
+
+// Migrate 执行数据库存储的 schema 迁移，dryRun 为 true 时只打印待执行的迁移
+func Migrate(configFilePath string, dryRun bool) error {
+	cfg, err := boot_config.Load(configFilePath)
+	if err != nil {
+		return fmt.Errorf("load config fail: %w", err)
+	}
+	if err := log.Configure(cfg.Bootstrap.Logger); err != nil {
+		return fmt.Errorf("configure logger fail: %w", err)
+	}
+	if !sqldb.IsSqlStore(cfg.Store.Name) {
+		fmt.Printf("store %s has no schema to migrate\n", cfg.Store.Name)
+		return nil
+	}
+
+	// 密码解析插件依赖插件配置
+	plugin.SetPluginConfig(&cfg.Plugin)
+	ret, err := sqldb.Migrate(&cfg.Store, dryRun)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("current schema version: %d, latest schema version: %d\n", ret.Current, ret.Latest)
+	if len(ret.Pending) == 0 {
+		fmt.Println("schema is up to date")
+		return nil
+	}
+	for _, m := range ret.Pending {
+		if dryRun {
+			fmt.Printf("pending migration: %s\n", m)
+		} else {
+			fmt.Printf("applied migration: %s\n", m)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package bootstrap
+
+import (
+	"errors"
+	"fmt"
+
+	boot_config "github.com/polarismesh/polaris/bootstrap/config"
+	"github.com/polarismesh/polaris/common/log"
+	"github.com/polarismesh/polaris/plugin"
+	"github.com/polarismesh/polaris/store"
+	"github.com/polarismesh/polaris/store/transfer"
+)
+
+// Transfer 将源配置文件中的存储数据迁移到目标配置文件中的存储，迁移时两端的服务需要停止
+func Transfer(srcConfigPath, dstConfigPath string, dryRun, verify bool) error {
+	srcCfg, err := boot_config.Load(srcConfigPath)
+	if err != nil {
+		return fmt.Errorf("load source config fail: %w", err)
+	}
+	dstCfg, err := boot_config.Load(dstConfigPath)
+	if err != nil {
+		return fmt.Errorf("load target config fail: %w", err)
+	}
+	if err := log.Configure(srcCfg.Bootstrap.Logger); err != nil {
+		return fmt.Errorf("configure logger fail: %w", err)
+	}
+
+	// 密码解析插件依赖插件配置
+	plugin.SetPluginConfig(&srcCfg.Plugin)
+	src, err := store.NewStore(&srcCfg.Store)
+	if err != nil {
+		return fmt.Errorf("open source store %s fail: %w", srcCfg.Store.Name, err)
+	}
+	defer func() { _ = src.Destroy() }()
+
+	plugin.SetPluginConfig(&dstCfg.Plugin)
+	dst, err := store.NewStore(&dstCfg.Store)
+	if err != nil {
+		return fmt.Errorf("open target store %s fail: %w", dstCfg.Store.Name, err)
+	}
+	defer func() { _ = dst.Destroy() }()
+
+	fmt.Printf("transfer data from store %s to store %s\n", src.Name(), dst.Name())
+	report, verifyReport, err := transfer.Copy(src, dst, dryRun, verify)
+	if report != nil {
+		printTransferReport("import", report)
+	}
+	if verifyReport != nil {
+		printTransferReport("verify", verifyReport)
+	}
+	if err != nil {
+		return err
+	}
+	if report.HasError() || (verifyReport != nil && verifyReport.HasError()) {
+		return errors.New("some records transfer fail, see the report above")
+	}
+	return nil
+}
+
+func printTransferReport(stage string, report *transfer.Report) {
+	if report.DryRun {
+		fmt.Printf("%s report (dry run):\n", stage)
+	} else {
+		fmt.Printf("%s report:\n", stage)
+	}
+	fmt.Printf("  %-30s %8s %8s %8s %10s %8s %8s %10s\n",
+		"kind", "total", "created", "skipped", "conflicted", "failed", "missing", "mismatched")
+	for _, kr := range report.Kinds {
+		fmt.Printf("  %-30s %8d %8d %8d %10d %8d %8d %10d\n",
+			kr.Kind, kr.Total, kr.Created, kr.Skipped, kr.Conflicted, kr.Failed, kr.Missing, kr.Mismatched)
+	}
+	for _, msg := range report.Warnings {
+		fmt.Printf("[WARN] %s\n", msg)
+	}
+	for _, msg := range report.Errors {
+		fmt.Printf("[ERROR] %s\n", msg)
+	}
+}
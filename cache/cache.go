@@ -45,6 +45,7 @@ var (
 	_ StrategyCache       = (*strategyCache)(nil)
 	_ L5Cache             = (*l5Cache)(nil)
 	_ FileCache           = (*fileCache)(nil)
+	_ FaultMirrorCache    = (*faultMirrorCache)(nil)
 )
 
 const (
@@ -61,6 +62,7 @@ const (
 	CacheNamespace
 	CacheClient
 	CacheConfigFile
+	CacheFaultMirror
 
 	CacheLast
 )
@@ -80,6 +82,7 @@ const (
 	CacheNameNamespace      CacheName = "Namespace"
 	CacheNameClient         CacheName = "Client"
 	CacheNameConfigFile     CacheName = "ConfigFile"
+	CacheNameFaultMirror    CacheName = "FaultMirror"
 )
 
 var (
@@ -95,6 +98,7 @@ var (
 		CacheNameNamespace:      CacheNamespace,
 		CacheNameClient:         CacheClient,
 		CacheNameConfigFile:     CacheConfigFile,
+		CacheNameFaultMirror:    CacheFaultMirror,
 	}
 )
 
@@ -438,6 +442,11 @@ func (nc *CacheManager) ConfigFile() FileCache {
 	return nc.caches[CacheConfigFile].(FileCache)
 }
 
+// FaultMirror get fault mirror rule cache information
+func (nc *CacheManager) FaultMirror() FaultMirrorCache {
+	return nc.caches[CacheFaultMirror].(FaultMirrorCache)
+}
+
 // GetStore get store
 func (nc *CacheManager) GetStore() store.Store {
 	return nc.storage
@@ -18,10 +18,16 @@
 package cache
 
 import (
+	"sort"
+	"strings"
 	"sync"
 	"time"
 
+	"go.uber.org/zap"
+
+	apiv2 "github.com/polarismesh/polaris/common/api/v2"
 	"github.com/polarismesh/polaris/common/model"
+	v2 "github.com/polarismesh/polaris/common/model/v2"
 	"github.com/polarismesh/polaris/store"
 )
 
@@ -38,6 +44,36 @@ type CircuitBreakerCache interface {
 
 	// GetCircuitBreakerConfig 根据ServiceID获取熔断配置
 	GetCircuitBreakerConfig(id string) *model.ServiceWithCircuitBreaker
+
+	// GetCircuitBreakerRulesV2 获取被调服务匹配的已启用 v2 熔断规则，以及这些规则的聚合 revision
+	GetCircuitBreakerRulesV2(service, namespace string) ([]*apiv2.CircuitBreaker, string, error)
+
+	// QueryCircuitBreakerRulesV2 查询 v2 熔断规则列表
+	QueryCircuitBreakerRulesV2(args *CircuitBreakerV2Args) (uint32, []*v2.ExtendCircuitBreakerRule)
+}
+
+// CircuitBreakerV2Args v2 熔断规则查询参数
+type CircuitBreakerV2Args struct {
+	// ID 规则 ID
+	ID string
+	// Name 规则名称，支持前缀模糊匹配
+	Name string
+	// Namespace 规则所在命名空间
+	Namespace string
+	// SrcService 主调服务
+	SrcService string
+	// SrcNamespace 主调服务所在命名空间
+	SrcNamespace string
+	// DstService 被调服务
+	DstService string
+	// DstNamespace 被调服务所在命名空间
+	DstNamespace string
+	// Enable 是否启用
+	Enable *bool
+	// Offset
+	Offset uint32
+	// Limit
+	Limit uint32
 }
 
 // circuitBreaker的实现
@@ -49,6 +85,10 @@ type circuitBreakerCache struct {
 	lock            sync.RWMutex
 	lastTime        time.Time
 	firstUpdate     bool
+
+	rulesV2     map[string]*v2.ExtendCircuitBreakerRule
+	lockV2      sync.RWMutex
+	lastMtimeV2 time.Time
 }
 
 // init 自注册到缓存列表
@@ -62,12 +102,14 @@ func newCircuitBreakerCache(s store.Store) *circuitBreakerCache {
 		baseCache:       newBaseCache(),
 		storage:         s,
 		circuitBreakers: map[string]*model.ServiceWithCircuitBreaker{},
+		rulesV2:         map[string]*v2.ExtendCircuitBreakerRule{},
 	}
 }
 
 // initialize 实现Cache接口的函数
 func (c *circuitBreakerCache) initialize(_ map[string]interface{}) error {
 	c.lastTime = time.Unix(0, 0)
+	c.lastMtimeV2 = time.Unix(0, 0)
 	c.firstUpdate = true
 
 	return nil
@@ -81,8 +123,14 @@ func (c *circuitBreakerCache) update(storeRollbackSec time.Duration) error {
 		log.Errorf("[Cache] circuit breaker config cache update err:%s", err.Error())
 		return err
 	}
+	outV2, err := c.storage.GetCircuitBreakerRulesV2ForCache(c.lastMtimeV2.Add(storeRollbackSec), c.firstUpdate)
+	if err != nil {
+		log.Errorf("[Cache] circuit breaker rules v2 cache update err:%s", err.Error())
+		return err
+	}
 
 	c.firstUpdate = false
+	c.setCircuitBreakerRulesV2(outV2)
 	return c.setCircuitBreaker(out)
 }
 
@@ -92,7 +140,12 @@ func (c *circuitBreakerCache) clear() error {
 	c.circuitBreakers = map[string]*model.ServiceWithCircuitBreaker{}
 	c.lock.Unlock()
 
+	c.lockV2.Lock()
+	c.rulesV2 = map[string]*v2.ExtendCircuitBreakerRule{}
+	c.lockV2.Unlock()
+
 	c.lastTime = time.Unix(0, 0)
+	c.lastMtimeV2 = time.Unix(0, 0)
 	return nil
 }
 
@@ -174,3 +227,127 @@ func (c *circuitBreakerCache) GetCircuitBreakerCount(f func(k, v interface{}) bo
 		}
 	}
 }
+
+// setCircuitBreakerRulesV2 更新 v2 熔断规则到缓存中
+func (c *circuitBreakerCache) setCircuitBreakerRulesV2(rules []*v2.CircuitBreakerRule) {
+	if len(rules) == 0 {
+		return
+	}
+
+	lastMtime := c.lastMtimeV2.Unix()
+
+	c.lockV2.Lock()
+	defer c.lockV2.Unlock()
+	for _, entry := range rules {
+		if entry.ID == "" {
+			continue
+		}
+		if entry.ModifyTime.Unix() > lastMtime {
+			lastMtime = entry.ModifyTime.Unix()
+		}
+		if !entry.Valid {
+			delete(c.rulesV2, entry.ID)
+			continue
+		}
+		extendEntry, err := entry.ToExtendCircuitBreakerRule()
+		if err != nil {
+			log.Error("[Cache] circuit breaker rule v2 convert to extend", zap.String("id", entry.ID),
+				zap.Error(err))
+			continue
+		}
+		c.rulesV2[entry.ID] = extendEntry
+	}
+
+	if c.lastMtimeV2.Unix() < lastMtime {
+		c.lastMtimeV2 = time.Unix(lastMtime, 0)
+	}
+}
+
+// GetCircuitBreakerRulesV2 获取被调服务匹配的已启用 v2 熔断规则，以及这些规则的聚合 revision
+func (c *circuitBreakerCache) GetCircuitBreakerRulesV2(service, namespace string) (
+	[]*apiv2.CircuitBreaker, string, error) {
+	c.lockV2.RLock()
+	matched := make([]*v2.ExtendCircuitBreakerRule, 0, 4)
+	for _, rule := range c.rulesV2 {
+		if rule.Enable && rule.MatchService(service, namespace) {
+			matched = append(matched, rule)
+		}
+	}
+	c.lockV2.RUnlock()
+
+	sort.Slice(matched, func(i, j int) bool {
+		return matched[i].ID < matched[j].ID
+	})
+
+	ret := make([]*apiv2.CircuitBreaker, 0, len(matched))
+	revisions := make([]string, 0, len(matched))
+	for i := range matched {
+		ret = append(ret, matched[i].ToApi())
+		revisions = append(revisions, matched[i].Revision)
+	}
+
+	revision, err := CompositeComputeRevision(revisions)
+	if err != nil {
+		return nil, "", err
+	}
+	return ret, revision, nil
+}
+
+// QueryCircuitBreakerRulesV2 查询 v2 熔断规则列表
+func (c *circuitBreakerCache) QueryCircuitBreakerRulesV2(
+	args *CircuitBreakerV2Args) (uint32, []*v2.ExtendCircuitBreakerRule) {
+	c.lockV2.RLock()
+	ret := make([]*v2.ExtendCircuitBreakerRule, 0, len(c.rulesV2))
+	for _, rule := range c.rulesV2 {
+		if matchCircuitBreakerV2Args(rule, args) {
+			ret = append(ret, rule)
+		}
+	}
+	c.lockV2.RUnlock()
+
+	// 按照修改时间倒序返回
+	sort.Slice(ret, func(i, j int) bool {
+		if ret[i].ModifyTime.Equal(ret[j].ModifyTime) {
+			return ret[i].ID < ret[j].ID
+		}
+		return ret[i].ModifyTime.After(ret[j].ModifyTime)
+	})
+
+	total := uint32(len(ret))
+	if args.Offset >= total || args.Limit == 0 {
+		return total, nil
+	}
+	end := args.Offset + args.Limit
+	if end > total {
+		end = total
+	}
+	return total, ret[args.Offset:end]
+}
+
+func matchCircuitBreakerV2Args(rule *v2.ExtendCircuitBreakerRule, args *CircuitBreakerV2Args) bool {
+	if args.ID != "" && args.ID != rule.ID {
+		return false
+	}
+	if args.Name != "" && !strings.HasPrefix(rule.Name, args.Name) {
+		return false
+	}
+	if args.Namespace != "" && args.Namespace != rule.Namespace {
+		return false
+	}
+	if args.SrcService != "" && args.SrcService != rule.SrcService {
+		return false
+	}
+	if args.SrcNamespace != "" && args.SrcNamespace != rule.SrcNamespace {
+		return false
+	}
+	if args.DstService != "" && args.DstService != rule.DstService {
+		return false
+	}
+	if args.DstNamespace != "" && args.DstNamespace != rule.DstNamespace {
+		return false
+	}
+	if args.Enable != nil && *args.Enable != rule.Enable {
+		return false
+	}
+	return true
+}
@@ -24,7 +24,9 @@ import (
 
 	"github.com/golang/mock/gomock"
 
+	apiv2 "github.com/polarismesh/polaris/common/api/v2"
 	"github.com/polarismesh/polaris/common/model"
+	v2 "github.com/polarismesh/polaris/common/model/v2"
 	"github.com/polarismesh/polaris/store/mock"
 )
 
@@ -37,6 +39,7 @@ func newTestCircuitBreakerCache(t *testing.T) (*gomock.Controller, *mock.MockSto
 	storage := mock.NewMockStore(ctl)
 	rlc := newCircuitBreakerCache(storage)
 	storage.EXPECT().GetUnixSecond().AnyTimes().Return(time.Now().Unix(), nil)
+	storage.EXPECT().GetCircuitBreakerRulesV2ForCache(gomock.Any(), gomock.Any()).AnyTimes().Return(nil, nil)
 	var opt map[string]interface{}
 	_ = rlc.initialize(opt)
 	return ctl, storage, rlc
@@ -236,3 +239,94 @@ func TestGetCircuitBreakerByServiceID(t *testing.T) {
 		}
 	})
 }
+
+// genModelCircuitBreakerRulesV2 生成 v2 熔断规则测试数据
+func genModelCircuitBreakerRulesV2(t *testing.T, total int, dstService string) []*v2.CircuitBreakerRule {
+	out := make([]*v2.CircuitBreakerRule, 0, total)
+	for i := 0; i < total; i++ {
+		rule := &v2.CircuitBreakerRule{}
+		err := rule.ParseFromAPI(&apiv2.CircuitBreaker{
+			Id:        fmt.Sprintf("cb-%s-%d", dstService, i),
+			Name:      fmt.Sprintf("cb-%s-%d", dstService, i),
+			Namespace: "default",
+			Enable:    true,
+			Revision:  fmt.Sprintf("revision-%d", i),
+			Level:     apiv2.Level_SERVICE,
+			RuleMatcher: &apiv2.RuleMatcher{
+				Source:      &apiv2.RuleMatcher_SourceService{Service: v2.MatchAll, Namespace: v2.MatchAll},
+				Destination: &apiv2.RuleMatcher_DestinationService{Service: dstService, Namespace: "default"},
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		rule.Valid = true
+		rule.ModifyTime = time.Unix(int64(i), 0)
+		out = append(out, rule)
+	}
+	return out
+}
+
+// TestCircuitBreakerRulesV2Update 测试 v2 熔断规则的缓存更新以及 revision 计算
+func TestCircuitBreakerRulesV2Update(t *testing.T) {
+	ctl := gomock.NewController(t)
+	defer ctl.Finish()
+
+	storage := mock.NewMockStore(ctl)
+	cbc := newCircuitBreakerCache(storage)
+	_ = cbc.initialize(nil)
+
+	total := 5
+	rules := genModelCircuitBreakerRulesV2(t, total, "svc-1")
+	rules = append(rules, genModelCircuitBreakerRulesV2(t, 1, v2.MatchAll)...)
+
+	storage.EXPECT().GetCircuitBreakerForCache(gomock.Any(), gomock.Any()).AnyTimes().Return(nil, nil)
+	storage.EXPECT().GetCircuitBreakerRulesV2ForCache(gomock.Any(), true).Return(rules, nil)
+	if err := cbc.update(0); err != nil {
+		t.Fatal(err)
+	}
+
+	ret, revision, err := cbc.GetCircuitBreakerRulesV2("svc-1", "default")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ret) != total+1 {
+		t.Fatalf("expect %d rules, actual %d", total+1, len(ret))
+	}
+	if ret[0].GetRuleMatcher().GetDestination().GetService() == "" {
+		t.Fatal("rule content is empty")
+	}
+
+	// 其他服务只能匹配到通配的规则
+	ret, _, err = cbc.GetCircuitBreakerRulesV2("svc-2", "default")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ret) != 1 {
+		t.Fatalf("expect 1 rule, actual %d", len(ret))
+	}
+
+	// 禁用以及删除规则后，revision 发生变化
+	rules[0].Enable = false
+	rules[1].Valid = false
+	storage.EXPECT().GetCircuitBreakerRulesV2ForCache(gomock.Any(), false).
+		Return([]*v2.CircuitBreakerRule{rules[0], rules[1]}, nil)
+	if err := cbc.update(0); err != nil {
+		t.Fatal(err)
+	}
+	ret, newRevision, err := cbc.GetCircuitBreakerRulesV2("svc-1", "default")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ret) != total-1 {
+		t.Fatalf("expect %d rules, actual %d", total-1, len(ret))
+	}
+	if newRevision == revision {
+		t.Fatal("revision should be changed")
+	}
+
+	amount, items := cbc.QueryCircuitBreakerRulesV2(&CircuitBreakerV2Args{DstService: "svc-1", Limit: 2})
+	if amount != uint32(total-1) || len(items) != 2 {
+		t.Fatalf("query rules amount %d, size %d", amount, len(items))
+	}
+}
@@ -88,6 +88,7 @@ func newCacheManager(ctx context.Context, cacheOpt *Config, storage store.Store)
 	mgr.caches[CacheNamespace] = newNamespaceCache(storage)
 	mgr.caches[CacheClient] = newClientCache(storage)
 	mgr.caches[CacheConfigFile] = newFileCache(ctx, storage)
+	mgr.caches[CacheFaultMirror] = newFaultMirrorCache(storage)
 
 	if len(mgr.caches) != CacheLast {
 		return nil, errors.New("some Cache implement not loaded into CacheManager")
@@ -0,0 +1,257 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package cache
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	apiv2 "github.com/polarismesh/polaris/common/api/v2"
+	v2 "github.com/polarismesh/polaris/common/model/v2"
+	"github.com/polarismesh/polaris/store"
+)
+
+const (
+	// FaultMirrorName fault mirror config name
+	FaultMirrorName = "faultMirrorConfig"
+)
+
+// FaultMirrorCache 故障注入与流量镜像规则的缓存接口
+type FaultMirrorCache interface {
+	Cache
+
+	// GetFaultMirrorRules 获取目标服务匹配的已启用规则，以及这些规则的聚合 revision
+	GetFaultMirrorRules(service, namespace string) ([]*apiv2.FaultMirrorRule, string, error)
+
+	// QueryFaultMirrorRules 查询故障注入与流量镜像规则列表
+	QueryFaultMirrorRules(args *FaultMirrorArgs) (uint32, []*v2.ExtendFaultMirrorRule)
+
+	// GetFaultMirrorRulesCount 获取规则总数
+	GetFaultMirrorRulesCount() int
+}
+
+// FaultMirrorArgs 故障注入与流量镜像规则查询参数
+type FaultMirrorArgs struct {
+	// ID 规则 ID
+	ID string
+	// Name 规则名称，支持前缀模糊匹配
+	Name string
+	// Namespace 规则所在命名空间
+	Namespace string
+	// Service 目标服务
+	Service string
+	// Enable 是否启用
+	Enable *bool
+	// Offset
+	Offset uint32
+	// Limit
+	Limit uint32
+}
+
+// faultMirrorCache 的实现
+type faultMirrorCache struct {
+	*baseCache
+
+	storage     store.Store
+	rules       map[string]*v2.ExtendFaultMirrorRule
+	lock        sync.RWMutex
+	lastMtime   time.Time
+	firstUpdate bool
+}
+
+// init 自注册到缓存列表
+func init() {
+	RegisterCache(FaultMirrorName, CacheFaultMirror)
+}
+
+// newFaultMirrorCache 返回一个操作 FaultMirrorCache 的对象
+func newFaultMirrorCache(s store.Store) *faultMirrorCache {
+	return &faultMirrorCache{
+		baseCache: newBaseCache(),
+		storage:   s,
+		rules:     map[string]*v2.ExtendFaultMirrorRule{},
+	}
+}
+
+// initialize 实现Cache接口的函数
+func (f *faultMirrorCache) initialize(_ map[string]interface{}) error {
+	f.lastMtime = time.Unix(0, 0)
+	f.firstUpdate = true
+	return nil
+}
+
+// update 实现Cache接口的函数
+func (f *faultMirrorCache) update(storeRollbackSec time.Duration) error {
+	out, err := f.storage.GetFaultMirrorRulesForCache(f.lastMtime.Add(storeRollbackSec), f.firstUpdate)
+	if err != nil {
+		log.Errorf("[Cache] fault mirror rules cache update err:%s", err.Error())
+		return err
+	}
+
+	f.firstUpdate = false
+	f.setFaultMirrorRules(out)
+	return nil
+}
+
+// clear 实现Cache接口的函数
+func (f *faultMirrorCache) clear() error {
+	f.lock.Lock()
+	f.rules = map[string]*v2.ExtendFaultMirrorRule{}
+	f.lock.Unlock()
+
+	f.lastMtime = time.Unix(0, 0)
+	return nil
+}
+
+// name 实现资源名称
+func (f *faultMirrorCache) name() string {
+	return FaultMirrorName
+}
+
+// setFaultMirrorRules 更新规则到缓存中
+func (f *faultMirrorCache) setFaultMirrorRules(rules []*v2.FaultMirrorRule) {
+	if len(rules) == 0 {
+		return
+	}
+
+	lastMtime := f.lastMtime.Unix()
+
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	for _, entry := range rules {
+		if entry.ID == "" {
+			continue
+		}
+		if entry.ModifyTime.Unix() > lastMtime {
+			lastMtime = entry.ModifyTime.Unix()
+		}
+		old, exist := f.rules[entry.ID]
+		if !entry.Valid {
+			if exist {
+				delete(f.rules, entry.ID)
+				f.manager.onEvent(old, EventDeleted)
+			}
+			continue
+		}
+		extendEntry, err := entry.ToExtendFaultMirrorRule()
+		if err != nil {
+			log.Error("[Cache] fault mirror rule convert to extend", zap.String("id", entry.ID), zap.Error(err))
+			continue
+		}
+		f.rules[entry.ID] = extendEntry
+		if exist {
+			f.manager.onEvent(extendEntry, EventUpdated)
+		} else {
+			f.manager.onEvent(extendEntry, EventCreated)
+		}
+	}
+
+	if f.lastMtime.Unix() < lastMtime {
+		f.lastMtime = time.Unix(lastMtime, 0)
+	}
+}
+
+// GetFaultMirrorRules 获取目标服务匹配的已启用规则，以及这些规则的聚合 revision
+func (f *faultMirrorCache) GetFaultMirrorRules(service, namespace string) (
+	[]*apiv2.FaultMirrorRule, string, error) {
+	f.lock.RLock()
+	matched := make([]*v2.ExtendFaultMirrorRule, 0, 4)
+	for _, rule := range f.rules {
+		if rule.Enable && rule.MatchService(service, namespace) {
+			matched = append(matched, rule)
+		}
+	}
+	f.lock.RUnlock()
+
+	sort.Slice(matched, func(i, j int) bool {
+		return matched[i].ID < matched[j].ID
+	})
+
+	ret := make([]*apiv2.FaultMirrorRule, 0, len(matched))
+	revisions := make([]string, 0, len(matched))
+	for i := range matched {
+		ret = append(ret, matched[i].ToApi())
+		revisions = append(revisions, matched[i].Revision)
+	}
+
+	revision, err := CompositeComputeRevision(revisions)
+	if err != nil {
+		return nil, "", err
+	}
+	return ret, revision, nil
+}
+
+// QueryFaultMirrorRules 查询故障注入与流量镜像规则列表
+func (f *faultMirrorCache) QueryFaultMirrorRules(args *FaultMirrorArgs) (uint32, []*v2.ExtendFaultMirrorRule) {
+	f.lock.RLock()
+	ret := make([]*v2.ExtendFaultMirrorRule, 0, len(f.rules))
+	for _, rule := range f.rules {
+		if matchFaultMirrorArgs(rule, args) {
+			ret = append(ret, rule)
+		}
+	}
+	f.lock.RUnlock()
+
+	// 按照修改时间倒序返回
+	sort.Slice(ret, func(i, j int) bool {
+		if ret[i].ModifyTime.Equal(ret[j].ModifyTime) {
+			return ret[i].ID < ret[j].ID
+		}
+		return ret[i].ModifyTime.After(ret[j].ModifyTime)
+	})
+
+	total := uint32(len(ret))
+	if args.Offset >= total || args.Limit == 0 {
+		return total, nil
+	}
+	end := args.Offset + args.Limit
+	if end > total {
+		end = total
+	}
+	return total, ret[args.Offset:end]
+}
+
+// GetFaultMirrorRulesCount 获取规则总数
+func (f *faultMirrorCache) GetFaultMirrorRulesCount() int {
+	f.lock.RLock()
+	defer f.lock.RUnlock()
+	return len(f.rules)
+}
+
+func matchFaultMirrorArgs(rule *v2.ExtendFaultMirrorRule, args *FaultMirrorArgs) bool {
+	if args.ID != "" && args.ID != rule.ID {
+		return false
+	}
+	if args.Name != "" && !strings.HasPrefix(rule.Name, args.Name) {
+		return false
+	}
+	if args.Namespace != "" && args.Namespace != rule.Namespace {
+		return false
+	}
+	if args.Service != "" && args.Service != rule.Service {
+		return false
+	}
+	if args.Enable != nil && *args.Enable != rule.Enable {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,129 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package cache
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+
+	apiv2 "github.com/polarismesh/polaris/common/api/v2"
+	v2 "github.com/polarismesh/polaris/common/model/v2"
+	"github.com/polarismesh/polaris/store/mock"
+)
+
+// genModelFaultMirrorRules 生成故障注入与流量镜像规则测试数据
+func genModelFaultMirrorRules(t *testing.T, total int, service string) []*v2.FaultMirrorRule {
+	out := make([]*v2.FaultMirrorRule, 0, total)
+	for i := 0; i < total; i++ {
+		rule := &v2.FaultMirrorRule{}
+		err := rule.ParseFromAPI(&apiv2.FaultMirrorRule{
+			Id:        fmt.Sprintf("fm-%s-%d", service, i),
+			Name:      fmt.Sprintf("fm-%s-%d", service, i),
+			Namespace: "default",
+			Enable:    true,
+			Revision:  fmt.Sprintf("revision-%d", i),
+			Service:   service,
+			Fault: &apiv2.FaultInjection{
+				Abort: &apiv2.FaultInjection_Abort{Percentage: 10, HttpStatus: 503},
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		rule.Valid = true
+		rule.ModifyTime = time.Unix(int64(i), 0)
+		out = append(out, rule)
+	}
+	return out
+}
+
+// TestFaultMirrorRulesUpdate 测试故障注入与流量镜像规则的缓存更新以及 revision 计算
+func TestFaultMirrorRulesUpdate(t *testing.T) {
+	ctl := gomock.NewController(t)
+	defer ctl.Finish()
+
+	storage := mock.NewMockStore(ctl)
+	fmc := newFaultMirrorCache(storage)
+	_ = fmc.initialize(nil)
+
+	total := 5
+	rules := genModelFaultMirrorRules(t, total, "svc-1")
+	rules = append(rules, genModelFaultMirrorRules(t, 1, v2.MatchAll)...)
+
+	storage.EXPECT().GetFaultMirrorRulesForCache(gomock.Any(), true).Return(rules, nil)
+	if err := fmc.update(0); err != nil {
+		t.Fatal(err)
+	}
+
+	ret, revision, err := fmc.GetFaultMirrorRules("svc-1", "default")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ret) != total+1 {
+		t.Fatalf("expect %d rules, actual %d", total+1, len(ret))
+	}
+	if ret[0].GetFault().GetAbort().GetHttpStatus() != 503 {
+		t.Fatal("rule content is empty")
+	}
+
+	// 其他服务只能匹配到通配的规则，其他命名空间匹配不到规则
+	ret, _, err = fmc.GetFaultMirrorRules("svc-2", "default")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ret) != 1 {
+		t.Fatalf("expect 1 rule, actual %d", len(ret))
+	}
+	ret, _, err = fmc.GetFaultMirrorRules("svc-1", "other")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ret) != 0 {
+		t.Fatalf("expect 0 rule, actual %d", len(ret))
+	}
+
+	// 禁用以及删除规则后，revision 发生变化
+	rules[0].Enable = false
+	rules[1].Valid = false
+	storage.EXPECT().GetFaultMirrorRulesForCache(gomock.Any(), false).
+		Return([]*v2.FaultMirrorRule{rules[0], rules[1]}, nil)
+	if err := fmc.update(0); err != nil {
+		t.Fatal(err)
+	}
+	ret, newRevision, err := fmc.GetFaultMirrorRules("svc-1", "default")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ret) != total-1 {
+		t.Fatalf("expect %d rules, actual %d", total-1, len(ret))
+	}
+	if newRevision == revision {
+		t.Fatal("revision should be changed")
+	}
+	if fmc.GetFaultMirrorRulesCount() != total {
+		t.Fatalf("expect %d rules in cache, actual %d", total, fmc.GetFaultMirrorRulesCount())
+	}
+
+	amount, items := fmc.QueryFaultMirrorRules(&FaultMirrorArgs{Service: "svc-1", Limit: 2})
+	if amount != uint32(total-1) || len(items) != 2 {
+		t.Fatalf("query rules amount %d, size %d", amount, len(items))
+	}
+}
@@ -149,7 +149,9 @@ func (rlc *rateLimitCache) setRateLimit(rateLimits []*model.RateLimit,
 			if !ok {
 				continue
 			}
-			value.(*sync.Map).Delete(item.ID)
+			if _, exist := value.(*sync.Map).LoadAndDelete(item.ID); exist {
+				rlc.manager.onEvent(item, EventDeleted)
+			}
 			continue
 		}
 
@@ -158,7 +160,13 @@ func (rlc *rateLimitCache) setRateLimit(rateLimits []*model.RateLimit,
 			value = new(sync.Map)
 			rlc.ids.Store(item.ServiceID, value)
 		}
+		_, exist := value.(*sync.Map).Load(item.ID)
 		value.(*sync.Map).Store(item.ID, item)
+		if exist {
+			rlc.manager.onEvent(item, EventUpdated)
+		} else {
+			rlc.manager.onEvent(item, EventCreated)
+		}
 	}
 
 	// 更新last revision
@@ -268,6 +268,7 @@ func (rc *routingConfigCache) setRoutingConfigV1(cs []*model.RoutingConfig) erro
 		if entry.ModifyTime.Unix() > lastMtimeV1 {
 			lastMtimeV1 = entry.ModifyTime.Unix()
 		}
+		oldEntry := rc.bucketV1.get(entry.ID)
 		if !entry.Valid {
 			// 删除老的 v1 缓存
 			rc.bucketV1.delete(entry.ID)
@@ -275,12 +276,20 @@ func (rc *routingConfigCache) setRoutingConfigV1(cs []*model.RoutingConfig) erro
 			rc.bucketV2.deleteV1(entry.ID)
 			// 删除 v1 转换到 v2 的任务id
 			delete(rc.pendingV1RuleIds, entry.ID)
+			if oldEntry != nil {
+				rc.manager.onEvent(entry, EventDeleted)
+			}
 			continue
 		}
 
 		// 保存到老的 v1 缓存
 		rc.bucketV1.save(entry)
 		rc.pendingV1RuleIds[entry.ID] = struct{}{}
+		if oldEntry == nil {
+			rc.manager.onEvent(entry, EventCreated)
+		} else {
+			rc.manager.onEvent(entry, EventUpdated)
+		}
 	}
 
 	if rc.lastMtimeV1.Unix() < lastMtimeV1 {
@@ -303,8 +312,13 @@ func (rc *routingConfigCache) setRoutingConfigV2(cs []*v2.RoutingConfig) error {
 		if entry.ModifyTime.Unix() > lastMtimeV2 {
 			lastMtimeV2 = entry.ModifyTime.Unix()
 		}
+		oldEntry := rc.bucketV2.getV2(entry.ID)
 		if !entry.Valid {
 			rc.bucketV2.deleteV2(entry.ID)
+			if oldEntry != nil {
+				// 删除事件携带删除前的规则，便于监听者得知受影响的服务
+				rc.manager.onEvent(oldEntry, EventDeleted)
+			}
 			continue
 		}
 		extendEntry, err := entry.ToExpendRoutingConfig()
@@ -313,6 +327,11 @@ func (rc *routingConfigCache) setRoutingConfigV2(cs []*v2.RoutingConfig) error {
 			continue
 		}
 		rc.bucketV2.saveV2(extendEntry)
+		if oldEntry == nil {
+			rc.manager.onEvent(extendEntry, EventCreated)
+		} else {
+			rc.manager.onEvent(extendEntry, EventUpdated)
+		}
 	}
 	if rc.lastMtimeV2.Unix() < lastMtimeV2 {
 		rc.lastMtimeV2 = time.Unix(lastMtimeV2, 0)
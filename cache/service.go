@@ -368,13 +368,20 @@ func (sc *serviceCache) setServices(services map[string]*model.Service) (int, in
 		if !service.Valid {
 			sc.removeServices(service)
 			sc.revisionCh <- newRevisionNotify(service.ID, false)
+			sc.manager.onEvent(service, EventDeleted)
 			del++
 			continue
 		}
 
 		update++
+		_, itemExist := sc.ids.Load(service.ID)
 		sc.ids.Store(service.ID, service)
 		sc.revisionCh <- newRevisionNotify(service.ID, true)
+		if !itemExist {
+			sc.manager.onEvent(service, EventCreated)
+		} else {
+			sc.manager.onEvent(service, EventUpdated)
+		}
 
 		spaces, ok := sc.names.Load(spaceName)
 		if !ok {
@@ -0,0 +1,49 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/polarismesh/polaris/bootstrap"
+)
+
+var (
+	migrateDryRun = false
+
+	migrateCmd = &cobra.Command{
+		Use:   "migrate",
+		Short: "migrate database schema",
+		Long:  "apply pending schema migrations of the database store",
+		Run: func(c *cobra.Command, args []string) {
+			if err := bootstrap.Migrate(configFilePath, migrateDryRun); err != nil {
+				fmt.Printf("[ERROR] migrate fail: %v\n", err)
+				os.Exit(1)
+			}
+		},
+	}
+)
+
+// init 解析命令参数
+func init() {
+	migrateCmd.PersistentFlags().StringVarP(&configFilePath, "config", "c", "polaris-server.yaml", "config file path")
+	migrateCmd.PersistentFlags().BoolVar(&migrateDryRun, "dry-run", false, "only print pending migrations")
+}
@@ -33,6 +33,8 @@ var (
 // init 初始化命令行工具
 func init() {
 	rootCmd.AddCommand(startCmd)
+	rootCmd.AddCommand(migrateCmd)
+	rootCmd.AddCommand(transferCmd)
 	rootCmd.AddCommand(versionCmd)
 	rootCmd.AddCommand(revisionCmd)
 }
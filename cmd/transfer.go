@@ -0,0 +1,57 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/polarismesh/polaris/bootstrap"
+)
+
+var (
+	transferSource = ""
+	transferTarget = ""
+	transferDryRun = false
+	transferVerify = false
+
+	transferCmd = &cobra.Command{
+		Use:   "transfer",
+		Short: "transfer data between stores",
+		Long:  "copy all data from the store of source config to the store of target config, servers must be stopped",
+		Run: func(c *cobra.Command, args []string) {
+			err := bootstrap.Transfer(transferSource, transferTarget, transferDryRun, transferVerify)
+			if err != nil {
+				fmt.Printf("[ERROR] transfer fail: %v\n", err)
+				os.Exit(1)
+			}
+		},
+	}
+)
+
+// init 解析命令参数
+func init() {
+	transferCmd.PersistentFlags().StringVarP(&transferSource, "source", "s", "", "config file path of source store")
+	transferCmd.PersistentFlags().StringVarP(&transferTarget, "target", "t", "", "config file path of target store")
+	transferCmd.PersistentFlags().BoolVar(&transferDryRun, "dry-run", false, "only count the records to transfer")
+	transferCmd.PersistentFlags().BoolVar(&transferVerify, "verify", false, "verify the target store after transfer")
+	_ = transferCmd.MarkPersistentFlagRequired("source")
+	_ = transferCmd.MarkPersistentFlagRequired("target")
+}
@@ -76,10 +76,14 @@ const (
 	InvalidCircuitBreakerBusiness   uint32 = 400166
 	InvalidCircuitBreakerDepartment uint32 = 400167
 	InvalidCircuitBreakerComment    uint32 = 400168
+	InvalidCircuitBreakerRule       uint32 = 400169
 	InvalidRoutingID                uint32 = 400700
 	InvalidRoutingPolicy            uint32 = 400701
 	InvalidRoutingName              uint32 = 400702
 	InvalidRoutingPriority          uint32 = 400703
+	InvalidFaultMirrorID            uint32 = 400710
+	InvalidFaultMirrorName          uint32 = 400711
+	InvalidFaultMirrorRule          uint32 = 400712
 
 	// 网格相关错误码
 	ServicesExistedMesh  uint32 = 400170
@@ -164,6 +168,8 @@ const (
 	InvalidWatchConfigFileFormat   uint32 = 400806
 	NotFoundResourceConfigFile     uint32 = 400807
 	InvalidConfigFileTemplateName  uint32 = 400808
+	InvalidConfigFileContent       uint32 = 400809
+	InvalidConfigFileSchema        uint32 = 400810
 
 	// 鉴权相关错误码
 	InvalidUserOwners         uint32 = 400410
@@ -253,6 +259,7 @@ var code2info = map[uint32]string{
 	InvalidCircuitBreakerBusiness:      "invalid circuit breaker business",
 	InvalidCircuitBreakerDepartment:    "invalid circuit breaker department",
 	InvalidCircuitBreakerComment:       "invalid circuit breaker comment",
+	InvalidCircuitBreakerRule:          "invalid circuit breaker rule content",
 	ExistedResource:                    "existed resource",
 	SameInstanceRequest:                "the same instance request",
 	NotFoundResource:                   "not found resource",
@@ -321,11 +328,13 @@ var code2info = map[uint32]string{
 	InvalidConfigFileGroupName:     "invalid config file group name",
 	InvalidConfigFileName:          "invalid config file name",
 	InvalidConfigFileContentLength: "config file content too long",
-	InvalidConfigFileFormat:        "invalid config file format, support json,xml,html,properties,text,yaml",
+	InvalidConfigFileFormat:        "invalid config file format, support json,xml,html,properties,text,yaml,toml",
 	InvalidConfigFileTags:          "invalid config file tags, tags should be pair, like key1,value1,key2,value2. and key,value should not blank",
 	InvalidWatchConfigFileFormat:   "invalid watch config file format",
 	NotFoundResourceConfigFile:     "config file not existed",
 	InvalidConfigFileTemplateName:  "invalid config file template name",
+	InvalidConfigFileContent:       "config file content does not match the file format",
+	InvalidConfigFileSchema:        "config file content does not match the json schema of config file group",
 
 	// 鉴权错误
 	NotFoundUser:             "not found user",
@@ -356,6 +365,10 @@ var code2info = map[uint32]string{
 	InvalidRoutingPolicy: "invalid routing policy, only support (RulePolicy,MetadataPolicy)",
 	InvalidRoutingName:   "invalid routing name",
 
+	InvalidFaultMirrorID:   "invalid fault mirror rule id",
+	InvalidFaultMirrorName: "invalid fault mirror rule name",
+	InvalidFaultMirrorRule: "invalid fault mirror rule content",
+
 	NamespaceExistedConfigGroups: "some config group existed in namespace",
 }
 
@@ -30,7 +30,7 @@
 CURRENT_OS=$(uname -s)
 CURRENT_ARCH=$(uname -m)
 PROTOC=../protoc
-PROTO_FILES="model_v2.proto routing_v2.proto request_v2.proto response_v2.proto grpcapi_v2.proto"
+PROTO_FILES="model_v2.proto routing_v2.proto circuitbreaker_v2.proto faultmirror_v2.proto request_v2.proto response_v2.proto grpcapi_v2.proto"
 
 if [ "$CURRENT_ARCH" != "x86_64" ]; then
     echo "Current only support x86_64"
@@ -0,0 +1,666 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: circuitbreaker_v2.proto
+
+package v2
+
+import proto "github.com/golang/protobuf/proto"
+import fmt "fmt"
+import math "math"
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
+
+// Level 熔断粒度
+type Level int32
+
+const (
+	Level_UNKNOWN Level = 0
+	// service level circuit breaker
+	Level_SERVICE Level = 1
+	// method level circuit breaker
+	Level_METHOD Level = 2
+	// subset level circuit breaker
+	Level_GROUP Level = 3
+	// instance level circuit breaker
+	Level_INSTANCE Level = 4
+)
+
+var Level_name = map[int32]string{
+	0: "UNKNOWN",
+	1: "SERVICE",
+	2: "METHOD",
+	3: "GROUP",
+	4: "INSTANCE",
+}
+var Level_value = map[string]int32{
+	"UNKNOWN":  0,
+	"SERVICE":  1,
+	"METHOD":   2,
+	"GROUP":    3,
+	"INSTANCE": 4,
+}
+
+func (x Level) String() string {
+	return proto.EnumName(Level_name, int32(x))
+}
+func (Level) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_circuitbreaker_v2_c9555703e696d1ed, []int{0}
+}
+
+type ErrorCondition_InputType int32
+
+const (
+	ErrorCondition_UNKNOWN ErrorCondition_InputType = 0
+	// return code of the invocation
+	ErrorCondition_RET_CODE ErrorCondition_InputType = 1
+	// delay of the invocation, unit is millisecond
+	ErrorCondition_DELAY ErrorCondition_InputType = 2
+)
+
+var ErrorCondition_InputType_name = map[int32]string{
+	0: "UNKNOWN",
+	1: "RET_CODE",
+	2: "DELAY",
+}
+var ErrorCondition_InputType_value = map[string]int32{
+	"UNKNOWN":  0,
+	"RET_CODE": 1,
+	"DELAY":    2,
+}
+
+func (x ErrorCondition_InputType) String() string {
+	return proto.EnumName(ErrorCondition_InputType_name, int32(x))
+}
+func (ErrorCondition_InputType) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_circuitbreaker_v2_c9555703e696d1ed, []int{2, 0}
+}
+
+type TriggerCondition_TriggerType int32
+
+const (
+	TriggerCondition_UNKNOWN TriggerCondition_TriggerType = 0
+	// error rate within the statistic interval
+	TriggerCondition_ERROR_RATE TriggerCondition_TriggerType = 1
+	// consecutive errors
+	TriggerCondition_CONSECUTIVE_ERROR TriggerCondition_TriggerType = 2
+)
+
+var TriggerCondition_TriggerType_name = map[int32]string{
+	0: "UNKNOWN",
+	1: "ERROR_RATE",
+	2: "CONSECUTIVE_ERROR",
+}
+var TriggerCondition_TriggerType_value = map[string]int32{
+	"UNKNOWN":           0,
+	"ERROR_RATE":        1,
+	"CONSECUTIVE_ERROR": 2,
+}
+
+func (x TriggerCondition_TriggerType) String() string {
+	return proto.EnumName(TriggerCondition_TriggerType_name, int32(x))
+}
+func (TriggerCondition_TriggerType) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_circuitbreaker_v2_c9555703e696d1ed, []int{3, 0}
+}
+
+// CircuitBreaker 熔断规则
+type CircuitBreaker struct {
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// circuit breaker rule name
+	Name string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	// namespace namingspace of circuit breaker rules
+	Namespace string `protobuf:"bytes,3,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	// Enable this circuit breaker rule
+	Enable bool `protobuf:"varint,4,opt,name=enable,proto3" json:"enable,omitempty"`
+	// revision circuit breaker rule version
+	Revision string `protobuf:"bytes,5,opt,name=revision,proto3" json:"revision,omitempty"`
+	// ctime create time of the rules
+	Ctime string `protobuf:"bytes,6,opt,name=ctime,proto3" json:"ctime,omitempty"`
+	// mtime modify time of the rules
+	Mtime string `protobuf:"bytes,7,opt,name=mtime,proto3" json:"mtime,omitempty"`
+	// etime enable time of the rules
+	Etime string `protobuf:"bytes,8,opt,name=etime,proto3" json:"etime,omitempty"`
+	// description simple description rules
+	Description string `protobuf:"bytes,9,opt,name=description,proto3" json:"description,omitempty"`
+	// the circuit breaking level
+	Level Level `protobuf:"varint,21,opt,name=level,proto3,enum=v2.Level" json:"level,omitempty"`
+	// match condition for this rule
+	RuleMatcher *RuleMatcher `protobuf:"bytes,22,opt,name=rule_matcher,proto3" json:"rule_matcher,omitempty"`
+	// error conditions to judge an invocation as an error
+	ErrorConditions []*ErrorCondition `protobuf:"bytes,23,rep,name=error_conditions,proto3" json:"error_conditions,omitempty"`
+	// trigger condition to trigger circuit breaker
+	TriggerCondition []*TriggerCondition `protobuf:"bytes,24,rep,name=trigger_condition,proto3" json:"trigger_condition,omitempty"`
+	// the maximum % of an upstream cluster that can be ejected
+	MaxEjectionPercent uint32 `protobuf:"varint,25,opt,name=max_ejection_percent,proto3" json:"max_ejection_percent,omitempty"`
+	// recover condition to make resource open to close
+	RecoverCondition     *RecoverCondition `protobuf:"bytes,26,opt,name=recover_condition,proto3" json:"recover_condition,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *CircuitBreaker) Reset()         { *m = CircuitBreaker{} }
+func (m *CircuitBreaker) String() string { return proto.CompactTextString(m) }
+func (*CircuitBreaker) ProtoMessage()    {}
+func (*CircuitBreaker) Descriptor() ([]byte, []int) {
+	return fileDescriptor_circuitbreaker_v2_c9555703e696d1ed, []int{0}
+}
+func (m *CircuitBreaker) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CircuitBreaker.Unmarshal(m, b)
+}
+func (m *CircuitBreaker) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CircuitBreaker.Marshal(b, m, deterministic)
+}
+func (dst *CircuitBreaker) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CircuitBreaker.Merge(dst, src)
+}
+func (m *CircuitBreaker) XXX_Size() int {
+	return xxx_messageInfo_CircuitBreaker.Size(m)
+}
+func (m *CircuitBreaker) XXX_DiscardUnknown() {
+	xxx_messageInfo_CircuitBreaker.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CircuitBreaker proto.InternalMessageInfo
+
+func (m *CircuitBreaker) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *CircuitBreaker) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *CircuitBreaker) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
+func (m *CircuitBreaker) GetEnable() bool {
+	if m != nil {
+		return m.Enable
+	}
+	return false
+}
+
+func (m *CircuitBreaker) GetRevision() string {
+	if m != nil {
+		return m.Revision
+	}
+	return ""
+}
+
+func (m *CircuitBreaker) GetCtime() string {
+	if m != nil {
+		return m.Ctime
+	}
+	return ""
+}
+
+func (m *CircuitBreaker) GetMtime() string {
+	if m != nil {
+		return m.Mtime
+	}
+	return ""
+}
+
+func (m *CircuitBreaker) GetEtime() string {
+	if m != nil {
+		return m.Etime
+	}
+	return ""
+}
+
+func (m *CircuitBreaker) GetDescription() string {
+	if m != nil {
+		return m.Description
+	}
+	return ""
+}
+
+func (m *CircuitBreaker) GetLevel() Level {
+	if m != nil {
+		return m.Level
+	}
+	return Level_UNKNOWN
+}
+
+func (m *CircuitBreaker) GetRuleMatcher() *RuleMatcher {
+	if m != nil {
+		return m.RuleMatcher
+	}
+	return nil
+}
+
+func (m *CircuitBreaker) GetErrorConditions() []*ErrorCondition {
+	if m != nil {
+		return m.ErrorConditions
+	}
+	return nil
+}
+
+func (m *CircuitBreaker) GetTriggerCondition() []*TriggerCondition {
+	if m != nil {
+		return m.TriggerCondition
+	}
+	return nil
+}
+
+func (m *CircuitBreaker) GetMaxEjectionPercent() uint32 {
+	if m != nil {
+		return m.MaxEjectionPercent
+	}
+	return 0
+}
+
+func (m *CircuitBreaker) GetRecoverCondition() *RecoverCondition {
+	if m != nil {
+		return m.RecoverCondition
+	}
+	return nil
+}
+
+// RuleMatcher 熔断规则的主调以及被调匹配条件
+type RuleMatcher struct {
+	Source               *RuleMatcher_SourceService      `protobuf:"bytes,1,opt,name=source,proto3" json:"source,omitempty"`
+	Destination          *RuleMatcher_DestinationService `protobuf:"bytes,2,opt,name=destination,proto3" json:"destination,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                        `json:"-"`
+	XXX_unrecognized     []byte                          `json:"-"`
+	XXX_sizecache        int32                           `json:"-"`
+}
+
+func (m *RuleMatcher) Reset()         { *m = RuleMatcher{} }
+func (m *RuleMatcher) String() string { return proto.CompactTextString(m) }
+func (*RuleMatcher) ProtoMessage()    {}
+func (*RuleMatcher) Descriptor() ([]byte, []int) {
+	return fileDescriptor_circuitbreaker_v2_c9555703e696d1ed, []int{1}
+}
+func (m *RuleMatcher) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RuleMatcher.Unmarshal(m, b)
+}
+func (m *RuleMatcher) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RuleMatcher.Marshal(b, m, deterministic)
+}
+func (dst *RuleMatcher) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RuleMatcher.Merge(dst, src)
+}
+func (m *RuleMatcher) XXX_Size() int {
+	return xxx_messageInfo_RuleMatcher.Size(m)
+}
+func (m *RuleMatcher) XXX_DiscardUnknown() {
+	xxx_messageInfo_RuleMatcher.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RuleMatcher proto.InternalMessageInfo
+
+func (m *RuleMatcher) GetSource() *RuleMatcher_SourceService {
+	if m != nil {
+		return m.Source
+	}
+	return nil
+}
+
+func (m *RuleMatcher) GetDestination() *RuleMatcher_DestinationService {
+	if m != nil {
+		return m.Destination
+	}
+	return nil
+}
+
+type RuleMatcher_SourceService struct {
+	// * means all services
+	Service string `protobuf:"bytes,1,opt,name=service,proto3" json:"service,omitempty"`
+	// * means all namespaces
+	Namespace            string   `protobuf:"bytes,2,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RuleMatcher_SourceService) Reset()         { *m = RuleMatcher_SourceService{} }
+func (m *RuleMatcher_SourceService) String() string { return proto.CompactTextString(m) }
+func (*RuleMatcher_SourceService) ProtoMessage()    {}
+func (*RuleMatcher_SourceService) Descriptor() ([]byte, []int) {
+	return fileDescriptor_circuitbreaker_v2_c9555703e696d1ed, []int{1, 0}
+}
+func (m *RuleMatcher_SourceService) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RuleMatcher_SourceService.Unmarshal(m, b)
+}
+func (m *RuleMatcher_SourceService) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RuleMatcher_SourceService.Marshal(b, m, deterministic)
+}
+func (dst *RuleMatcher_SourceService) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RuleMatcher_SourceService.Merge(dst, src)
+}
+func (m *RuleMatcher_SourceService) XXX_Size() int {
+	return xxx_messageInfo_RuleMatcher_SourceService.Size(m)
+}
+func (m *RuleMatcher_SourceService) XXX_DiscardUnknown() {
+	xxx_messageInfo_RuleMatcher_SourceService.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RuleMatcher_SourceService proto.InternalMessageInfo
+
+func (m *RuleMatcher_SourceService) GetService() string {
+	if m != nil {
+		return m.Service
+	}
+	return ""
+}
+
+func (m *RuleMatcher_SourceService) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
+type RuleMatcher_DestinationService struct {
+	// * means all services
+	Service string `protobuf:"bytes,1,opt,name=service,proto3" json:"service,omitempty"`
+	// * means all namespaces
+	Namespace string `protobuf:"bytes,2,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	// only used when level is METHOD
+	Method               *MatchString `protobuf:"bytes,3,opt,name=method,proto3" json:"method,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *RuleMatcher_DestinationService) Reset()         { *m = RuleMatcher_DestinationService{} }
+func (m *RuleMatcher_DestinationService) String() string { return proto.CompactTextString(m) }
+func (*RuleMatcher_DestinationService) ProtoMessage()    {}
+func (*RuleMatcher_DestinationService) Descriptor() ([]byte, []int) {
+	return fileDescriptor_circuitbreaker_v2_c9555703e696d1ed, []int{1, 1}
+}
+func (m *RuleMatcher_DestinationService) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RuleMatcher_DestinationService.Unmarshal(m, b)
+}
+func (m *RuleMatcher_DestinationService) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RuleMatcher_DestinationService.Marshal(b, m, deterministic)
+}
+func (dst *RuleMatcher_DestinationService) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RuleMatcher_DestinationService.Merge(dst, src)
+}
+func (m *RuleMatcher_DestinationService) XXX_Size() int {
+	return xxx_messageInfo_RuleMatcher_DestinationService.Size(m)
+}
+func (m *RuleMatcher_DestinationService) XXX_DiscardUnknown() {
+	xxx_messageInfo_RuleMatcher_DestinationService.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RuleMatcher_DestinationService proto.InternalMessageInfo
+
+func (m *RuleMatcher_DestinationService) GetService() string {
+	if m != nil {
+		return m.Service
+	}
+	return ""
+}
+
+func (m *RuleMatcher_DestinationService) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
+func (m *RuleMatcher_DestinationService) GetMethod() *MatchString {
+	if m != nil {
+		return m.Method
+	}
+	return nil
+}
+
+// ErrorCondition 判断一次调用是否为错误的条件
+type ErrorCondition struct {
+	InputType            ErrorCondition_InputType `protobuf:"varint,1,opt,name=input_type,proto3,enum=v2.ErrorCondition_InputType" json:"input_type,omitempty"`
+	Condition            *MatchString             `protobuf:"bytes,2,opt,name=condition,proto3" json:"condition,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                 `json:"-"`
+	XXX_unrecognized     []byte                   `json:"-"`
+	XXX_sizecache        int32                    `json:"-"`
+}
+
+func (m *ErrorCondition) Reset()         { *m = ErrorCondition{} }
+func (m *ErrorCondition) String() string { return proto.CompactTextString(m) }
+func (*ErrorCondition) ProtoMessage()    {}
+func (*ErrorCondition) Descriptor() ([]byte, []int) {
+	return fileDescriptor_circuitbreaker_v2_c9555703e696d1ed, []int{2}
+}
+func (m *ErrorCondition) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ErrorCondition.Unmarshal(m, b)
+}
+func (m *ErrorCondition) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ErrorCondition.Marshal(b, m, deterministic)
+}
+func (dst *ErrorCondition) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ErrorCondition.Merge(dst, src)
+}
+func (m *ErrorCondition) XXX_Size() int {
+	return xxx_messageInfo_ErrorCondition.Size(m)
+}
+func (m *ErrorCondition) XXX_DiscardUnknown() {
+	xxx_messageInfo_ErrorCondition.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ErrorCondition proto.InternalMessageInfo
+
+func (m *ErrorCondition) GetInputType() ErrorCondition_InputType {
+	if m != nil {
+		return m.InputType
+	}
+	return ErrorCondition_UNKNOWN
+}
+
+func (m *ErrorCondition) GetCondition() *MatchString {
+	if m != nil {
+		return m.Condition
+	}
+	return nil
+}
+
+// TriggerCondition 触发熔断的条件
+type TriggerCondition struct {
+	TriggerType  TriggerCondition_TriggerType `protobuf:"varint,1,opt,name=trigger_type,proto3,enum=v2.TriggerCondition_TriggerType" json:"trigger_type,omitempty"`
+	ErrorCount   uint32                       `protobuf:"varint,2,opt,name=error_count,proto3" json:"error_count,omitempty"`
+	ErrorPercent uint32                       `protobuf:"varint,3,opt,name=error_percent,proto3" json:"error_percent,omitempty"`
+	// statistic interval, unit is second
+	Interval             uint32   `protobuf:"varint,4,opt,name=interval,proto3" json:"interval,omitempty"`
+	MinimumRequest       uint32   `protobuf:"varint,5,opt,name=minimum_request,proto3" json:"minimum_request,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TriggerCondition) Reset()         { *m = TriggerCondition{} }
+func (m *TriggerCondition) String() string { return proto.CompactTextString(m) }
+func (*TriggerCondition) ProtoMessage()    {}
+func (*TriggerCondition) Descriptor() ([]byte, []int) {
+	return fileDescriptor_circuitbreaker_v2_c9555703e696d1ed, []int{3}
+}
+func (m *TriggerCondition) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TriggerCondition.Unmarshal(m, b)
+}
+func (m *TriggerCondition) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TriggerCondition.Marshal(b, m, deterministic)
+}
+func (dst *TriggerCondition) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TriggerCondition.Merge(dst, src)
+}
+func (m *TriggerCondition) XXX_Size() int {
+	return xxx_messageInfo_TriggerCondition.Size(m)
+}
+func (m *TriggerCondition) XXX_DiscardUnknown() {
+	xxx_messageInfo_TriggerCondition.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TriggerCondition proto.InternalMessageInfo
+
+func (m *TriggerCondition) GetTriggerType() TriggerCondition_TriggerType {
+	if m != nil {
+		return m.TriggerType
+	}
+	return TriggerCondition_UNKNOWN
+}
+
+func (m *TriggerCondition) GetErrorCount() uint32 {
+	if m != nil {
+		return m.ErrorCount
+	}
+	return 0
+}
+
+func (m *TriggerCondition) GetErrorPercent() uint32 {
+	if m != nil {
+		return m.ErrorPercent
+	}
+	return 0
+}
+
+func (m *TriggerCondition) GetInterval() uint32 {
+	if m != nil {
+		return m.Interval
+	}
+	return 0
+}
+
+func (m *TriggerCondition) GetMinimumRequest() uint32 {
+	if m != nil {
+		return m.MinimumRequest
+	}
+	return 0
+}
+
+// RecoverCondition 熔断恢复的条件
+type RecoverCondition struct {
+	// seconds from open to half-open
+	SleepWindow uint32 `protobuf:"varint,1,opt,name=sleep_window,proto3" json:"sleep_window,omitempty"`
+	// consecutive success request to make half-open to close
+	ConsecutiveSuccess   uint32   `protobuf:"varint,2,opt,name=consecutive_success,proto3" json:"consecutive_success,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RecoverCondition) Reset()         { *m = RecoverCondition{} }
+func (m *RecoverCondition) String() string { return proto.CompactTextString(m) }
+func (*RecoverCondition) ProtoMessage()    {}
+func (*RecoverCondition) Descriptor() ([]byte, []int) {
+	return fileDescriptor_circuitbreaker_v2_c9555703e696d1ed, []int{4}
+}
+func (m *RecoverCondition) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RecoverCondition.Unmarshal(m, b)
+}
+func (m *RecoverCondition) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RecoverCondition.Marshal(b, m, deterministic)
+}
+func (dst *RecoverCondition) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RecoverCondition.Merge(dst, src)
+}
+func (m *RecoverCondition) XXX_Size() int {
+	return xxx_messageInfo_RecoverCondition.Size(m)
+}
+func (m *RecoverCondition) XXX_DiscardUnknown() {
+	xxx_messageInfo_RecoverCondition.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RecoverCondition proto.InternalMessageInfo
+
+func (m *RecoverCondition) GetSleepWindow() uint32 {
+	if m != nil {
+		return m.SleepWindow
+	}
+	return 0
+}
+
+func (m *RecoverCondition) GetConsecutiveSuccess() uint32 {
+	if m != nil {
+		return m.ConsecutiveSuccess
+	}
+	return 0
+}
+
+func init() {
+	proto.RegisterType((*CircuitBreaker)(nil), "v2.CircuitBreaker")
+	proto.RegisterType((*RuleMatcher)(nil), "v2.RuleMatcher")
+	proto.RegisterType((*RuleMatcher_SourceService)(nil), "v2.RuleMatcher.SourceService")
+	proto.RegisterType((*RuleMatcher_DestinationService)(nil), "v2.RuleMatcher.DestinationService")
+	proto.RegisterType((*ErrorCondition)(nil), "v2.ErrorCondition")
+	proto.RegisterType((*TriggerCondition)(nil), "v2.TriggerCondition")
+	proto.RegisterType((*RecoverCondition)(nil), "v2.RecoverCondition")
+	proto.RegisterEnum("v2.Level", Level_name, Level_value)
+	proto.RegisterEnum("v2.ErrorCondition_InputType", ErrorCondition_InputType_name, ErrorCondition_InputType_value)
+	proto.RegisterEnum("v2.TriggerCondition_TriggerType", TriggerCondition_TriggerType_name, TriggerCondition_TriggerType_value)
+}
+
+func init() {
+	proto.RegisterFile("circuitbreaker_v2.proto", fileDescriptor_circuitbreaker_v2_c9555703e696d1ed)
+}
+
+var fileDescriptor_circuitbreaker_v2_c9555703e696d1ed = []byte{
+	// 760 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x55, 0xdd, 0x6e, 0xe3, 0x44,
+	0x14, 0xc6, 0x4e, 0x93, 0x36, 0xc7, 0x4d, 0xd6, 0x3b, 0x74, 0x77, 0x4d, 0xb4, 0x08, 0xcb, 0x42,
+	0x22, 0x42, 0x22, 0x02, 0xaf, 0xb8, 0x43, 0x48, 0xdd, 0xc4, 0x2c, 0x15, 0xbb, 0x09, 0x9a, 0xa4,
+	0x8b, 0xb8, 0xb2, 0xdc, 0xc9, 0x51, 0x3b, 0xe0, 0x3f, 0xc6, 0x63, 0x2f, 0x7d, 0x24, 0xee, 0x78,
+	0x00, 0x9e, 0x80, 0xa7, 0x42, 0x33, 0x8e, 0x1b, 0x3b, 0xe9, 0x15, 0x57, 0xf5, 0xf9, 0xbe, 0xef,
+	0xfc, 0xf4, 0xcc, 0x37, 0x13, 0x78, 0xc1, 0xb8, 0x60, 0x25, 0x97, 0x37, 0x02, 0xa3, 0xdf, 0x51,
+	0x84, 0x95, 0x3f, 0xcb, 0x45, 0x26, 0x33, 0x62, 0x56, 0xfe, 0x64, 0x9c, 0x64, 0x5b, 0x8c, 0x1f,
+	0x30, 0xef, 0x9f, 0x13, 0x18, 0xcf, 0x6b, 0xfd, 0xeb, 0x5a, 0x4f, 0xc6, 0x60, 0xf2, 0xad, 0x63,
+	0xb8, 0xc6, 0x74, 0x48, 0x4d, 0xbe, 0x25, 0x04, 0x4e, 0xd2, 0x28, 0x41, 0xc7, 0xd4, 0x88, 0xfe,
+	0x26, 0x2f, 0x61, 0xa8, 0xfe, 0x16, 0x79, 0xc4, 0xd0, 0xe9, 0x69, 0x62, 0x0f, 0x90, 0xe7, 0x30,
+	0xc0, 0x34, 0xba, 0x89, 0xd1, 0x39, 0x71, 0x8d, 0xe9, 0x19, 0xdd, 0x45, 0x64, 0x02, 0x67, 0x02,
+	0x2b, 0x5e, 0xf0, 0x2c, 0x75, 0xfa, 0x3a, 0xe9, 0x21, 0x26, 0x17, 0xd0, 0x67, 0x92, 0x27, 0xe8,
+	0x0c, 0x34, 0x51, 0x07, 0x0a, 0x4d, 0x34, 0x7a, 0x5a, 0xa3, 0x49, 0x83, 0xa2, 0x46, 0xcf, 0x6a,
+	0x54, 0x07, 0xc4, 0x05, 0x6b, 0x8b, 0x05, 0x13, 0x3c, 0x97, 0xaa, 0xc1, 0x50, 0x73, 0x6d, 0x88,
+	0x7c, 0x06, 0xfd, 0x18, 0x2b, 0x8c, 0x9d, 0x67, 0xae, 0x31, 0x1d, 0xfb, 0xc3, 0x59, 0xe5, 0xcf,
+	0xde, 0x2a, 0x80, 0xd6, 0x38, 0x79, 0x05, 0xe7, 0xa2, 0x8c, 0x31, 0x4c, 0x22, 0xc9, 0xee, 0x50,
+	0x38, 0xcf, 0x5d, 0x63, 0x6a, 0xf9, 0x4f, 0x94, 0x8e, 0x96, 0x31, 0xbe, 0xab, 0x61, 0xda, 0x11,
+	0x91, 0xef, 0xc1, 0x46, 0x21, 0x32, 0x11, 0xb2, 0x2c, 0xdd, 0x72, 0xd5, 0xa8, 0x70, 0x5e, 0xb8,
+	0xbd, 0xa9, 0xe5, 0x13, 0x95, 0x18, 0x28, 0x6e, 0xde, 0x50, 0xf4, 0x48, 0x4b, 0x5e, 0xc3, 0x53,
+	0x29, 0xf8, 0xed, 0x2d, 0xb6, 0x50, 0xc7, 0xd1, 0x05, 0x2e, 0x54, 0x81, 0x4d, 0x4d, 0xee, 0x4b,
+	0x1c, 0xcb, 0x89, 0x0f, 0x17, 0x49, 0xf4, 0x67, 0x88, 0xbf, 0x21, 0x53, 0x71, 0x98, 0xa3, 0x60,
+	0x98, 0x4a, 0xe7, 0x13, 0xd7, 0x98, 0x8e, 0xe8, 0xa3, 0x9c, 0xea, 0x2b, 0x90, 0x65, 0x55, 0xa7,
+	0xef, 0xc4, 0x35, 0x9a, 0xbe, 0xb4, 0x26, 0x5b, 0x7d, 0x8f, 0xe4, 0xde, 0xbf, 0x26, 0x58, 0xad,
+	0xcd, 0x90, 0x6f, 0x61, 0x50, 0x64, 0xa5, 0x60, 0xa8, 0xfd, 0x63, 0xf9, 0x9f, 0x1e, 0xac, 0x6e,
+	0xb6, 0xd6, 0xec, 0x1a, 0x45, 0xc5, 0x19, 0xd2, 0x9d, 0x98, 0x2c, 0xf4, 0xd1, 0x49, 0x9e, 0x46,
+	0x7a, 0x08, 0x53, 0xe7, 0x7a, 0x87, 0xb9, 0x8b, 0xbd, 0xa4, 0x29, 0xd0, 0x4e, 0x9b, 0xbc, 0x81,
+	0x51, 0xa7, 0x3c, 0x71, 0xe0, 0xb4, 0xa8, 0x3f, 0x77, 0x76, 0x6e, 0xc2, 0xae, 0x7f, 0xcd, 0x03,
+	0xff, 0x4e, 0x4a, 0x20, 0xc7, 0xbd, 0xfe, 0x6f, 0x35, 0xf2, 0x05, 0x0c, 0x12, 0x94, 0x77, 0xd9,
+	0xd6, 0xe9, 0xed, 0xed, 0xa4, 0xff, 0xa7, 0xb5, 0x14, 0x3c, 0xbd, 0xa5, 0x3b, 0xda, 0xfb, 0xdb,
+	0x80, 0x71, 0xd7, 0x2d, 0xe4, 0x3b, 0x00, 0x9e, 0xe6, 0xa5, 0x0c, 0xe5, 0x7d, 0x5e, 0xb7, 0x1d,
+	0xfb, 0x2f, 0x8f, 0x5d, 0x35, 0xbb, 0x52, 0xa2, 0xcd, 0x7d, 0x8e, 0xb4, 0xa5, 0x27, 0x5f, 0xc1,
+	0x70, 0x7f, 0xb2, 0xe6, 0xe3, 0xcd, 0xf7, 0x0a, 0xef, 0x1b, 0x18, 0x3e, 0xd4, 0x21, 0x16, 0x9c,
+	0x5e, 0x2f, 0x7f, 0x5a, 0xae, 0x7e, 0x59, 0xda, 0x1f, 0x91, 0x73, 0x38, 0xa3, 0xc1, 0x26, 0x9c,
+	0xaf, 0x16, 0x81, 0x6d, 0x90, 0x21, 0xf4, 0x17, 0xc1, 0xdb, 0xcb, 0x5f, 0x6d, 0xd3, 0xfb, 0xcb,
+	0x04, 0xfb, 0xd0, 0x9f, 0x64, 0x01, 0xe7, 0x8d, 0x43, 0x5b, 0x63, 0xbb, 0x8f, 0x79, 0xb9, 0x01,
+	0xf4, 0xe8, 0x9d, 0x2c, 0x75, 0x9d, 0x9b, 0xab, 0x52, 0xa6, 0x52, 0x8f, 0x3f, 0xa2, 0x6d, 0x88,
+	0x7c, 0x0e, 0xa3, 0x3a, 0x6c, 0xdc, 0xde, 0xd3, 0x9a, 0x2e, 0xa8, 0x1e, 0x1d, 0x9e, 0x4a, 0x14,
+	0x55, 0x14, 0xeb, 0xe7, 0x68, 0x44, 0x1f, 0x62, 0x32, 0x85, 0x27, 0x09, 0x4f, 0x79, 0x52, 0x26,
+	0xa1, 0xc0, 0x3f, 0x4a, 0x2c, 0xa4, 0x7e, 0x97, 0x46, 0xf4, 0x10, 0xf6, 0x2e, 0xc1, 0x6a, 0x8d,
+	0xda, 0xdd, 0xce, 0x18, 0x20, 0xa0, 0x74, 0x45, 0x43, 0x7a, 0xb9, 0x51, 0xfb, 0x79, 0x06, 0x4f,
+	0xe7, 0xab, 0xe5, 0x3a, 0x98, 0x5f, 0x6f, 0xae, 0xde, 0x07, 0xa1, 0xe6, 0x6c, 0xd3, 0xbb, 0x03,
+	0xfb, 0xf0, 0x4a, 0x11, 0x0f, 0xce, 0x8b, 0x18, 0x31, 0x0f, 0x3f, 0xf0, 0x74, 0x9b, 0x7d, 0xd0,
+	0xab, 0x1a, 0xd1, 0x0e, 0x46, 0xbe, 0x86, 0x8f, 0x59, 0x96, 0x16, 0xc8, 0x4a, 0xc9, 0x2b, 0x0c,
+	0x8b, 0x92, 0x31, 0x2c, 0x8a, 0xdd, 0x42, 0x1e, 0xa3, 0xbe, 0xfc, 0x01, 0xfa, 0xfa, 0x59, 0xeb,
+	0x8e, 0x69, 0xc1, 0xe9, 0x3a, 0xa0, 0xef, 0xaf, 0xe6, 0x6a, 0x46, 0x80, 0xc1, 0xbb, 0x60, 0xf3,
+	0xe3, 0x6a, 0x61, 0x9b, 0xea, 0x3c, 0xdf, 0xd0, 0xd5, 0xf5, 0xcf, 0x76, 0x4f, 0x1d, 0xf4, 0xd5,
+	0x72, 0xbd, 0xb9, 0x5c, 0xce, 0x03, 0xfb, 0xe4, 0x66, 0xa0, 0x7f, 0x23, 0x5e, 0xfd, 0x37, 0x00,
+	0x6e, 0x9b, 0x73, 0x8b, 0x52, 0x06, 0x00, 0x00,
+}
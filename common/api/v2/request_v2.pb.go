@@ -24,24 +24,27 @@ const (
 	DiscoverRequest_UNKNOWN         DiscoverRequest_DiscoverRequestType = 0
 	DiscoverRequest_ROUTING         DiscoverRequest_DiscoverRequestType = 1
 	DiscoverRequest_CIRCUIT_BREAKER DiscoverRequest_DiscoverRequestType = 2
+	DiscoverRequest_FAULT_MIRROR    DiscoverRequest_DiscoverRequestType = 3
 )
 
 var DiscoverRequest_DiscoverRequestType_name = map[int32]string{
 	0: "UNKNOWN",
 	1: "ROUTING",
 	2: "CIRCUIT_BREAKER",
+	3: "FAULT_MIRROR",
 }
 var DiscoverRequest_DiscoverRequestType_value = map[string]int32{
 	"UNKNOWN":         0,
 	"ROUTING":         1,
 	"CIRCUIT_BREAKER": 2,
+	"FAULT_MIRROR":    3,
 }
 
 func (x DiscoverRequest_DiscoverRequestType) String() string {
 	return proto.EnumName(DiscoverRequest_DiscoverRequestType_name, int32(x))
 }
 func (DiscoverRequest_DiscoverRequestType) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_request_v2_da411c9ba009335d, []int{1, 0}
+	return fileDescriptor_request_v2_474482275f0441a3, []int{1, 0}
 }
 
 type Service struct {
@@ -57,7 +60,7 @@ func (m *Service) Reset()         { *m = Service{} }
 func (m *Service) String() string { return proto.CompactTextString(m) }
 func (*Service) ProtoMessage()    {}
 func (*Service) Descriptor() ([]byte, []int) {
-	return fileDescriptor_request_v2_da411c9ba009335d, []int{0}
+	return fileDescriptor_request_v2_474482275f0441a3, []int{0}
 }
 func (m *Service) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Service.Unmarshal(m, b)
@@ -110,7 +113,7 @@ func (m *DiscoverRequest) Reset()         { *m = DiscoverRequest{} }
 func (m *DiscoverRequest) String() string { return proto.CompactTextString(m) }
 func (*DiscoverRequest) ProtoMessage()    {}
 func (*DiscoverRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_request_v2_da411c9ba009335d, []int{1}
+	return fileDescriptor_request_v2_474482275f0441a3, []int{1}
 }
 func (m *DiscoverRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_DiscoverRequest.Unmarshal(m, b)
@@ -150,24 +153,24 @@ func init() {
 	proto.RegisterEnum("v2.DiscoverRequest_DiscoverRequestType", DiscoverRequest_DiscoverRequestType_name, DiscoverRequest_DiscoverRequestType_value)
 }
 
-func init() { proto.RegisterFile("request_v2.proto", fileDescriptor_request_v2_da411c9ba009335d) }
-
-var fileDescriptor_request_v2_da411c9ba009335d = []byte{
-	// 241 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0x12, 0x28, 0x4a, 0x2d, 0x2c,
-	0x4d, 0x2d, 0x2e, 0x89, 0x2f, 0x33, 0xd2, 0x2b, 0x28, 0xca, 0x2f, 0xc9, 0x17, 0x62, 0x2a, 0x33,
-	0x52, 0x0a, 0xe7, 0x62, 0x0f, 0x4e, 0x2d, 0x2a, 0xcb, 0x4c, 0x4e, 0x15, 0x12, 0xe2, 0x62, 0xc9,
-	0x4b, 0xcc, 0x4d, 0x95, 0x60, 0x54, 0x60, 0xd4, 0xe0, 0x0c, 0x02, 0xb3, 0x85, 0x64, 0xb8, 0x38,
-	0x41, 0x74, 0x71, 0x41, 0x62, 0x72, 0xaa, 0x04, 0x13, 0x58, 0x02, 0x21, 0x20, 0x24, 0xc5, 0xc5,
-	0x51, 0x94, 0x5a, 0x96, 0x59, 0x9c, 0x99, 0x9f, 0x27, 0xc1, 0x0c, 0x96, 0x84, 0xf3, 0x95, 0x8e,
-	0x33, 0x72, 0xf1, 0xbb, 0x64, 0x16, 0x27, 0xe7, 0x97, 0xa5, 0x16, 0x05, 0x41, 0x6c, 0x16, 0xb2,
-	0xe6, 0x62, 0x29, 0xa9, 0x2c, 0x80, 0xd8, 0xc0, 0x67, 0xa4, 0xae, 0x57, 0x66, 0xa4, 0x87, 0xa6,
-	0x04, 0x9d, 0x1f, 0x52, 0x59, 0x90, 0x1a, 0x04, 0xd6, 0x24, 0xa4, 0xca, 0xc5, 0x5e, 0x9c, 0x5a,
-	0x94, 0x59, 0x06, 0x75, 0x08, 0xb7, 0x11, 0x37, 0x48, 0x3f, 0xd4, 0xf1, 0x41, 0x30, 0x39, 0x25,
-	0x2f, 0x2e, 0x61, 0x2c, 0x66, 0x08, 0x71, 0x73, 0xb1, 0x87, 0xfa, 0x79, 0xfb, 0xf9, 0x87, 0xfb,
-	0x09, 0x30, 0x80, 0x38, 0x41, 0xfe, 0xa1, 0x21, 0x9e, 0x7e, 0xee, 0x02, 0x8c, 0x42, 0xc2, 0x5c,
-	0xfc, 0xce, 0x9e, 0x41, 0xce, 0xa1, 0x9e, 0x21, 0xf1, 0x4e, 0x41, 0xae, 0x8e, 0xde, 0xae, 0x41,
-	0x02, 0x4c, 0x4a, 0x2c, 0x1c, 0xec, 0x02, 0xdc, 0x5e, 0x2c, 0x1c, 0xcc, 0x02, 0xac, 0x49, 0x6c,
-	0xe0, 0xd0, 0x32, 0x06, 0x04, 0x00, 0x00, 0xff, 0xff, 0x23, 0xae, 0x3a, 0x13, 0x41, 0x01, 0x00,
-	0x00,
+func init() { proto.RegisterFile("request_v2.proto", fileDescriptor_request_v2_474482275f0441a3) }
+
+var fileDescriptor_request_v2_474482275f0441a3 = []byte{
+	// 254 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x6c, 0x50, 0x4d, 0x4b, 0xc3, 0x40,
+	0x10, 0x35, 0x1f, 0x9a, 0x74, 0x22, 0x76, 0x99, 0x5e, 0x82, 0x78, 0x90, 0x80, 0xe8, 0x29, 0x87,
+	0x78, 0xf4, 0x54, 0x6b, 0x95, 0x58, 0x4d, 0x60, 0x4c, 0xe8, 0x45, 0x08, 0x35, 0xcc, 0x21, 0x07,
+	0x9b, 0xb8, 0x1b, 0x17, 0xfa, 0x53, 0xfd, 0x37, 0x92, 0x6d, 0x55, 0x28, 0x9e, 0x66, 0xde, 0xbc,
+	0x79, 0xf3, 0x1e, 0x03, 0x42, 0xf2, 0xc7, 0x27, 0xab, 0xbe, 0xd2, 0x49, 0xdc, 0xc9, 0xb6, 0x6f,
+	0xd1, 0xd6, 0x49, 0xb4, 0x04, 0xef, 0x85, 0xa5, 0x6e, 0x6a, 0x46, 0x04, 0x77, 0xbd, 0x7a, 0xe7,
+	0xd0, 0x3a, 0xb7, 0xae, 0x46, 0x64, 0x7a, 0x3c, 0x83, 0xd1, 0x50, 0x55, 0xb7, 0xaa, 0x39, 0xb4,
+	0x0d, 0xf1, 0x37, 0xc0, 0x53, 0xf0, 0x25, 0xeb, 0x46, 0x35, 0xed, 0x3a, 0x74, 0x0c, 0xf9, 0x8b,
+	0xa3, 0x2f, 0x0b, 0xc6, 0x77, 0x8d, 0xaa, 0x5b, 0xcd, 0x92, 0xb6, 0xce, 0x78, 0x03, 0x6e, 0xbf,
+	0xe9, 0xb6, 0x0e, 0x27, 0xc9, 0x65, 0xac, 0x93, 0x78, 0x6f, 0x65, 0x1f, 0x17, 0x9b, 0x8e, 0xc9,
+	0x88, 0xf0, 0x02, 0x3c, 0xc5, 0xb2, 0xd1, 0xbb, 0x20, 0x41, 0x12, 0x0c, 0xfa, 0x5d, 0x78, 0xfa,
+	0xe1, 0xa2, 0x57, 0x98, 0xfc, 0x73, 0x03, 0x03, 0xf0, 0xca, 0x6c, 0x91, 0xe5, 0xcb, 0x4c, 0x1c,
+	0x0c, 0x80, 0xf2, 0xb2, 0x48, 0xb3, 0x07, 0x61, 0xe1, 0x04, 0xc6, 0xb3, 0x94, 0x66, 0x65, 0x5a,
+	0x54, 0xb7, 0x34, 0x9f, 0x2e, 0xe6, 0x24, 0x6c, 0x14, 0x70, 0x7c, 0x3f, 0x2d, 0x9f, 0x8a, 0xea,
+	0x39, 0x25, 0xca, 0x49, 0x38, 0x91, 0xeb, 0x7b, 0x22, 0x78, 0x74, 0x7d, 0x47, 0x1c, 0xbe, 0x1d,
+	0x99, 0xff, 0x5d, 0x7f, 0x0f, 0x00, 0x4c, 0x59, 0xe0, 0x58, 0x53, 0x01, 0x00, 0x00,
 }
@@ -112,6 +112,44 @@ func NewRoutingResponse(code uint32, routing *Routing) *Response {
 	}
 }
 
+/**
+ * @brief 创建回复带熔断规则信息
+ */
+func NewCircuitBreakerResponse(code uint32, rule *CircuitBreaker) *Response {
+	ret, err := ptypes.MarshalAny(rule)
+	if err != nil {
+		return &Response{
+			Code: code,
+			Info: v1.Code2Info(code),
+		}
+	}
+
+	return &Response{
+		Code: code,
+		Info: v1.Code2Info(code),
+		Data: ret,
+	}
+}
+
+/**
+ * @brief 创建回复带故障注入与流量镜像规则信息
+ */
+func NewFaultMirrorResponse(code uint32, rule *FaultMirrorRule) *Response {
+	ret, err := ptypes.MarshalAny(rule)
+	if err != nil {
+		return &Response{
+			Code: code,
+			Info: v1.Code2Info(code),
+		}
+	}
+
+	return &Response{
+		Code: code,
+		Info: v1.Code2Info(code),
+		Data: ret,
+	}
+}
+
 /**
  * @brief 创建批量回复
  */
@@ -213,6 +251,30 @@ func NewDiscoverRoutingResponse(code uint32, service *Service) *DiscoverResponse
 	}
 }
 
+/**
+ * @brief 创建查询熔断规则回复
+ */
+func NewDiscoverCircuitBreakerResponse(code uint32, service *Service) *DiscoverResponse {
+	return &DiscoverResponse{
+		Code:    code,
+		Info:    v1.Code2Info(code),
+		Type:    DiscoverResponse_CIRCUIT_BREAKER,
+		Service: service,
+	}
+}
+
+/**
+ * @brief 创建查询故障注入与流量镜像规则回复
+ */
+func NewDiscoverFaultMirrorResponse(code uint32, service *Service) *DiscoverResponse {
+	return &DiscoverResponse{
+		Code:    code,
+		Info:    v1.Code2Info(code),
+		Type:    DiscoverResponse_FAULT_MIRROR,
+		Service: service,
+	}
+}
+
 // 创建一个空白的discoverResponse
 func NewDiscoverResponse(code uint32) *DiscoverResponse {
 	return &DiscoverResponse{
@@ -30,6 +30,7 @@ const (
 	DiscoverResponse_CIRCUIT_BREAKER DiscoverResponse_DiscoverResponseType = 5
 	DiscoverResponse_SERVICES        DiscoverResponse_DiscoverResponseType = 6
 	DiscoverResponse_NAMESPACES      DiscoverResponse_DiscoverResponseType = 12
+	DiscoverResponse_FAULT_MIRROR    DiscoverResponse_DiscoverResponseType = 13
 )
 
 var DiscoverResponse_DiscoverResponseType_name = map[int32]string{
@@ -41,6 +42,7 @@ var DiscoverResponse_DiscoverResponseType_name = map[int32]string{
 	5:  "CIRCUIT_BREAKER",
 	6:  "SERVICES",
 	12: "NAMESPACES",
+	13: "FAULT_MIRROR",
 }
 var DiscoverResponse_DiscoverResponseType_value = map[string]int32{
 	"UNKNOWN":         0,
@@ -51,13 +53,14 @@ var DiscoverResponse_DiscoverResponseType_value = map[string]int32{
 	"CIRCUIT_BREAKER": 5,
 	"SERVICES":        6,
 	"NAMESPACES":      12,
+	"FAULT_MIRROR":    13,
 }
 
 func (x DiscoverResponse_DiscoverResponseType) String() string {
 	return proto.EnumName(DiscoverResponse_DiscoverResponseType_name, int32(x))
 }
 func (DiscoverResponse_DiscoverResponseType) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_response_v2_32a9e06b08c12914, []int{4, 0}
+	return fileDescriptor_response_v2_8e44f0620386a113, []int{4, 0}
 }
 
 type SimpleResponse struct {
@@ -72,7 +75,7 @@ func (m *SimpleResponse) Reset()         { *m = SimpleResponse{} }
 func (m *SimpleResponse) String() string { return proto.CompactTextString(m) }
 func (*SimpleResponse) ProtoMessage()    {}
 func (*SimpleResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_response_v2_32a9e06b08c12914, []int{0}
+	return fileDescriptor_response_v2_8e44f0620386a113, []int{0}
 }
 func (m *SimpleResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_SimpleResponse.Unmarshal(m, b)
@@ -119,7 +122,7 @@ func (m *Response) Reset()         { *m = Response{} }
 func (m *Response) String() string { return proto.CompactTextString(m) }
 func (*Response) ProtoMessage()    {}
 func (*Response) Descriptor() ([]byte, []int) {
-	return fileDescriptor_response_v2_32a9e06b08c12914, []int{1}
+	return fileDescriptor_response_v2_8e44f0620386a113, []int{1}
 }
 func (m *Response) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Response.Unmarshal(m, b)
@@ -174,7 +177,7 @@ func (m *BatchWriteResponse) Reset()         { *m = BatchWriteResponse{} }
 func (m *BatchWriteResponse) String() string { return proto.CompactTextString(m) }
 func (*BatchWriteResponse) ProtoMessage()    {}
 func (*BatchWriteResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_response_v2_32a9e06b08c12914, []int{2}
+	return fileDescriptor_response_v2_8e44f0620386a113, []int{2}
 }
 func (m *BatchWriteResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_BatchWriteResponse.Unmarshal(m, b)
@@ -237,7 +240,7 @@ func (m *BatchQueryResponse) Reset()         { *m = BatchQueryResponse{} }
 func (m *BatchQueryResponse) String() string { return proto.CompactTextString(m) }
 func (*BatchQueryResponse) ProtoMessage()    {}
 func (*BatchQueryResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_response_v2_32a9e06b08c12914, []int{3}
+	return fileDescriptor_response_v2_8e44f0620386a113, []int{3}
 }
 func (m *BatchQueryResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_BatchQueryResponse.Unmarshal(m, b)
@@ -298,6 +301,8 @@ type DiscoverResponse struct {
 	Type                 DiscoverResponse_DiscoverResponseType `protobuf:"varint,3,opt,name=type,proto3,enum=v2.DiscoverResponse_DiscoverResponseType" json:"type,omitempty"`
 	Service              *Service                              `protobuf:"bytes,4,opt,name=service,proto3" json:"service,omitempty"`
 	Routings             []*Routing                            `protobuf:"bytes,6,rep,name=routings,proto3" json:"routings,omitempty"`
+	CircuitBreakers      []*CircuitBreaker                     `protobuf:"bytes,7,rep,name=circuitBreakers,proto3" json:"circuitBreakers,omitempty"`
+	FaultMirrorRules     []*FaultMirrorRule                    `protobuf:"bytes,8,rep,name=faultMirrorRules,proto3" json:"faultMirrorRules,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}                              `json:"-"`
 	XXX_unrecognized     []byte                                `json:"-"`
 	XXX_sizecache        int32                                 `json:"-"`
@@ -307,7 +312,7 @@ func (m *DiscoverResponse) Reset()         { *m = DiscoverResponse{} }
 func (m *DiscoverResponse) String() string { return proto.CompactTextString(m) }
 func (*DiscoverResponse) ProtoMessage()    {}
 func (*DiscoverResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_response_v2_32a9e06b08c12914, []int{4}
+	return fileDescriptor_response_v2_8e44f0620386a113, []int{4}
 }
 func (m *DiscoverResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_DiscoverResponse.Unmarshal(m, b)
@@ -362,6 +367,20 @@ func (m *DiscoverResponse) GetRoutings() []*Routing {
 	return nil
 }
 
+func (m *DiscoverResponse) GetCircuitBreakers() []*CircuitBreaker {
+	if m != nil {
+		return m.CircuitBreakers
+	}
+	return nil
+}
+
+func (m *DiscoverResponse) GetFaultMirrorRules() []*FaultMirrorRule {
+	if m != nil {
+		return m.FaultMirrorRules
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*SimpleResponse)(nil), "v2.SimpleResponse")
 	proto.RegisterType((*Response)(nil), "v2.Response")
@@ -371,40 +390,45 @@ func init() {
 	proto.RegisterEnum("v2.DiscoverResponse_DiscoverResponseType", DiscoverResponse_DiscoverResponseType_name, DiscoverResponse_DiscoverResponseType_value)
 }
 
-func init() { proto.RegisterFile("response_v2.proto", fileDescriptor_response_v2_32a9e06b08c12914) }
-
-var fileDescriptor_response_v2_32a9e06b08c12914 = []byte{
-	// 504 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x52, 0x5d, 0x6f, 0xd3, 0x30,
-	0x14, 0x25, 0xab, 0xd7, 0x66, 0x4e, 0xd7, 0x19, 0x6f, 0x42, 0x65, 0x4f, 0x55, 0x24, 0x44, 0xe9,
-	0x43, 0x26, 0x85, 0x17, 0x5e, 0x78, 0x48, 0x53, 0x77, 0xa4, 0x1f, 0x29, 0xdc, 0xa4, 0x1b, 0x6f,
-	0x55, 0xd7, 0x79, 0x5d, 0xa4, 0x2d, 0x2e, 0x49, 0x5a, 0xa9, 0x88, 0x5f, 0xc1, 0x2f, 0xe0, 0x17,
-	0xf1, 0x9b, 0x90, 0x9d, 0xa4, 0x43, 0x80, 0x90, 0xfa, 0x12, 0xdd, 0x73, 0x8e, 0xef, 0xb9, 0xc7,
-	0x37, 0xc6, 0xcf, 0x13, 0x9e, 0xae, 0x44, 0x9c, 0xf2, 0xd9, 0xc6, 0xb6, 0x56, 0x89, 0xc8, 0x04,
-	0x3d, 0xd8, 0xd8, 0xe7, 0x24, 0xe1, 0x5f, 0xd6, 0x3c, 0xcd, 0x76, 0xec, 0x39, 0x49, 0xc4, 0x3a,
-	0x8b, 0xe2, 0xe5, 0x13, 0xf3, 0x72, 0x29, 0xc4, 0xf2, 0x81, 0x5f, 0x28, 0x74, 0xb3, 0xbe, 0xbb,
-	0x98, 0xc7, 0xdb, 0x5c, 0x32, 0xdf, 0xe1, 0x46, 0x10, 0x3d, 0xae, 0x1e, 0x38, 0x14, 0xee, 0x94,
-	0x62, 0xb4, 0x10, 0xb7, 0xbc, 0xa9, 0xb5, 0xb4, 0xf6, 0x31, 0xa8, 0x5a, 0x72, 0x51, 0x7c, 0x27,
-	0x9a, 0x07, 0x2d, 0xad, 0x7d, 0x04, 0xaa, 0x36, 0xef, 0xb1, 0xbe, 0x6f, 0x0f, 0x6d, 0x63, 0x74,
-	0x3b, 0xcf, 0xe6, 0xcd, 0x4a, 0x4b, 0x6b, 0x1b, 0xf6, 0x99, 0x95, 0xe7, 0xb2, 0xca, 0x5c, 0x96,
-	0x13, 0x6f, 0x41, 0x9d, 0x18, 0x20, 0xbd, 0x4e, 0x4e, 0x06, 0x48, 0x27, 0xe4, 0xd4, 0xfc, 0x86,
-	0x69, 0x77, 0x9e, 0x2d, 0xee, 0xaf, 0x93, 0x28, 0xdb, 0x3b, 0xa7, 0xe4, 0xd2, 0xe8, 0x2b, 0x57,
-	0x33, 0x8f, 0x41, 0xd5, 0xb4, 0x83, 0x8f, 0xca, 0x6d, 0xa6, 0x4d, 0xd4, 0xaa, 0xb4, 0x0d, 0xbb,
-	0x6e, 0x6d, 0x6c, 0xab, 0x34, 0x87, 0x27, 0xd9, 0xfc, 0xae, 0x15, 0xe3, 0x3f, 0xad, 0x79, 0xb2,
-	0xdd, 0x7b, 0xfc, 0x0b, 0x5c, 0x9d, 0x3f, 0x8a, 0x75, 0x9c, 0x15, 0x01, 0x0a, 0xb4, 0x8b, 0x85,
-	0x7e, 0x8b, 0x55, 0xae, 0xe7, 0x50, 0x25, 0xfa, 0xcf, 0x7a, 0xcc, 0x1f, 0x15, 0x4c, 0x7a, 0x51,
-	0xba, 0x10, 0x1b, 0x9e, 0xec, 0x1d, 0xe9, 0x3d, 0x46, 0xd9, 0x76, 0x95, 0x6f, 0xa4, 0x61, 0xbf,
-	0x91, 0x17, 0xff, 0xd3, 0xeb, 0x2f, 0x22, 0xdc, 0xae, 0x38, 0xa8, 0x36, 0xfa, 0x0a, 0xd7, 0x52,
-	0x9e, 0x6c, 0xa2, 0x45, 0x1e, 0xde, 0xb0, 0x0d, 0xe9, 0x10, 0xe4, 0x14, 0x94, 0x1a, 0x7d, 0x8d,
-	0xf5, 0xe2, 0x21, 0xa6, 0xcd, 0xaa, 0xba, 0x90, 0x3a, 0x07, 0x39, 0x07, 0x3b, 0xd1, 0xfc, 0xa9,
-	0xe1, 0xb3, 0x7f, 0x8d, 0xa3, 0x06, 0xae, 0x4d, 0xfd, 0xa1, 0x3f, 0xb9, 0xf6, 0xc9, 0x33, 0x5a,
-	0xc7, 0xba, 0xe7, 0x07, 0xa1, 0xe3, 0xbb, 0x8c, 0x68, 0x52, 0x72, 0x47, 0xd3, 0x20, 0x64, 0x40,
-	0x0e, 0x24, 0x80, 0xc9, 0x34, 0xf4, 0xfc, 0x4b, 0x52, 0xa1, 0x0d, 0x8c, 0xc1, 0x09, 0xd9, 0x6c,
-	0xe4, 0x8d, 0xbd, 0x90, 0x20, 0x7a, 0x8a, 0x4f, 0x5c, 0x0f, 0xdc, 0xa9, 0x17, 0xce, 0xba, 0xc0,
-	0x9c, 0x21, 0x03, 0x72, 0x28, 0xcd, 0x02, 0x06, 0x57, 0x9e, 0xcb, 0x02, 0x52, 0x95, 0x2d, 0xbe,
-	0x33, 0x66, 0xc1, 0x47, 0x47, 0xe2, 0xba, 0x89, 0xf4, 0x1a, 0x31, 0x3a, 0x68, 0xcc, 0x82, 0x0f,
-	0x1d, 0x43, 0x7e, 0x67, 0xee, 0xc4, 0xef, 0x7b, 0x97, 0x9d, 0x46, 0x7f, 0x34, 0xfd, 0x3c, 0xeb,
-	0x75, 0x81, 0xf5, 0x41, 0x8a, 0xba, 0xc2, 0x41, 0x6f, 0xd8, 0x31, 0xf2, 0x8a, 0xc1, 0x15, 0x83,
-	0x01, 0xd2, 0x0d, 0xd2, 0xb8, 0xa9, 0xaa, 0xdf, 0xf6, 0xf6, 0x57, 0x00, 0x00, 0x00, 0xff, 0xff,
-	0x47, 0x5a, 0x69, 0xa4, 0xb8, 0x03, 0x00, 0x00,
+func init() { proto.RegisterFile("response_v2.proto", fileDescriptor_response_v2_8e44f0620386a113) }
+
+var fileDescriptor_response_v2_8e44f0620386a113 = []byte{
+	// 584 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x52, 0xcd, 0x6e, 0xd3, 0x4c,
+	0x14, 0xfd, 0xdc, 0x4c, 0x13, 0x77, 0x9c, 0xa6, 0xf3, 0x4d, 0x2b, 0x30, 0x5d, 0x45, 0x96, 0x10,
+	0x21, 0x8b, 0x54, 0x32, 0x1b, 0x16, 0x20, 0xe4, 0xb8, 0x93, 0xe2, 0x36, 0x71, 0xe0, 0xda, 0x69,
+	0xd9, 0x45, 0x6e, 0x3a, 0x6d, 0x2d, 0xd2, 0x38, 0x8c, 0xed, 0x48, 0x41, 0x3c, 0x05, 0x6f, 0xc2,
+	0x1b, 0xf1, 0x28, 0x68, 0xc6, 0x71, 0x7f, 0x11, 0x52, 0x36, 0xd6, 0xbd, 0xe7, 0x9c, 0x7b, 0xe7,
+	0xf8, 0xea, 0xe0, 0xff, 0x05, 0x4f, 0xe7, 0xc9, 0x2c, 0xe5, 0xe3, 0x85, 0xdd, 0x99, 0x8b, 0x24,
+	0x4b, 0xe8, 0xc6, 0xc2, 0xde, 0x27, 0x82, 0x7f, 0xcb, 0x79, 0x9a, 0xdd, 0xa2, 0xfb, 0x44, 0x24,
+	0x79, 0x16, 0xcf, 0xae, 0xee, 0x90, 0xe7, 0x93, 0x58, 0x4c, 0xf2, 0x38, 0x3b, 0x17, 0x3c, 0xfa,
+	0xca, 0xc5, 0x1d, 0xb1, 0x77, 0x19, 0xe5, 0xd3, 0xec, 0x26, 0x16, 0x22, 0xb9, 0x87, 0xbe, 0xb8,
+	0x4a, 0x92, 0xab, 0x29, 0x3f, 0x50, 0xdd, 0x79, 0x7e, 0x79, 0x10, 0xcd, 0x96, 0x05, 0x65, 0xbd,
+	0xc5, 0x8d, 0x20, 0xbe, 0x99, 0x4f, 0x39, 0xac, 0xcc, 0x50, 0x8a, 0xd1, 0x24, 0xb9, 0xe0, 0xa6,
+	0xd6, 0xd4, 0x5a, 0xdb, 0xa0, 0x6a, 0x89, 0xc5, 0xb3, 0xcb, 0xc4, 0xdc, 0x68, 0x6a, 0xad, 0x2d,
+	0x50, 0xb5, 0x75, 0x8d, 0xf5, 0x75, 0x67, 0x68, 0x0b, 0xa3, 0x8b, 0x28, 0x8b, 0xcc, 0x4a, 0x53,
+	0x6b, 0x19, 0xf6, 0x5e, 0xa7, 0xf0, 0xd5, 0x29, 0x7d, 0x75, 0x9c, 0xd9, 0x12, 0x94, 0xe2, 0x18,
+	0xe9, 0x75, 0xb2, 0x73, 0x8c, 0x74, 0x42, 0x76, 0xad, 0x1f, 0x98, 0x76, 0xa3, 0x6c, 0x72, 0x7d,
+	0x26, 0xe2, 0x6c, 0x6d, 0x9f, 0x12, 0x4b, 0xe3, 0xef, 0x5c, 0xbd, 0xb9, 0x0d, 0xaa, 0xa6, 0x6d,
+	0xbc, 0x55, 0x1e, 0x3f, 0x35, 0x51, 0xb3, 0xd2, 0x32, 0xec, 0x7a, 0x67, 0x61, 0x77, 0xca, 0xe5,
+	0x70, 0x47, 0x5b, 0x3f, 0xb5, 0xd5, 0xf3, 0x9f, 0x73, 0x2e, 0x96, 0x6b, 0x3f, 0xff, 0x0c, 0x57,
+	0xa3, 0x9b, 0x24, 0x9f, 0x65, 0x2b, 0x03, 0xab, 0xee, 0xd6, 0x16, 0xba, 0x67, 0xab, 0x3c, 0xcf,
+	0x66, 0xb3, 0xf2, 0xef, 0xf3, 0x58, 0xbf, 0x10, 0x26, 0x87, 0x71, 0x3a, 0x49, 0x16, 0x5c, 0xac,
+	0x6d, 0xe9, 0x3d, 0x46, 0xd9, 0x72, 0x5e, 0x5c, 0xa4, 0x61, 0xbf, 0x96, 0x3f, 0xfe, 0x78, 0xd7,
+	0x13, 0x20, 0x5c, 0xce, 0x39, 0xa8, 0x31, 0xfa, 0x12, 0xd7, 0x52, 0x2e, 0x16, 0xf1, 0xa4, 0x30,
+	0x6f, 0xd8, 0x86, 0xdc, 0x10, 0x14, 0x10, 0x94, 0x1c, 0x7d, 0x85, 0xf5, 0x55, 0x6e, 0x53, 0xb3,
+	0xda, 0xac, 0x94, 0x3a, 0x28, 0x30, 0xb8, 0x25, 0xe9, 0x3b, 0xbc, 0xb3, 0x8a, 0x73, 0xb7, 0x88,
+	0x73, 0x6a, 0xd6, 0x94, 0x9e, 0x4a, 0xbd, 0xfb, 0x80, 0x82, 0xc7, 0x52, 0xfa, 0x01, 0x13, 0x95,
+	0xf9, 0x81, 0xca, 0x3c, 0xe4, 0x53, 0x9e, 0x9a, 0xba, 0x1a, 0xdf, 0x95, 0xe3, 0xbd, 0x87, 0x1c,
+	0x3c, 0x11, 0x5b, 0xbf, 0x35, 0xbc, 0xf7, 0xb7, 0xbf, 0xa5, 0x06, 0xae, 0x8d, 0xfc, 0x13, 0x7f,
+	0x78, 0xe6, 0x93, 0xff, 0x68, 0x1d, 0xeb, 0x9e, 0x1f, 0x84, 0x8e, 0xef, 0x32, 0xa2, 0x49, 0xca,
+	0xed, 0x8f, 0x82, 0x90, 0x01, 0xd9, 0x90, 0x0d, 0x0c, 0x47, 0xa1, 0xe7, 0x1f, 0x91, 0x0a, 0x6d,
+	0x60, 0x0c, 0x4e, 0xc8, 0xc6, 0x7d, 0x6f, 0xe0, 0x85, 0x04, 0xd1, 0x5d, 0xbc, 0xe3, 0x7a, 0xe0,
+	0x8e, 0xbc, 0x70, 0xdc, 0x05, 0xe6, 0x9c, 0x30, 0x20, 0x9b, 0x72, 0x59, 0xc0, 0xe0, 0xd4, 0x73,
+	0x59, 0x40, 0xaa, 0x72, 0xc4, 0x77, 0x06, 0x2c, 0xf8, 0xe4, 0xc8, 0xbe, 0x4e, 0x09, 0xae, 0xf7,
+	0x9c, 0x51, 0x3f, 0x1c, 0x0f, 0x3c, 0x80, 0x21, 0x90, 0x6d, 0x0b, 0xe9, 0x35, 0x62, 0xb4, 0xd1,
+	0x80, 0x05, 0x1f, 0xdb, 0x86, 0xfc, 0x8e, 0xdd, 0xa1, 0xdf, 0xf3, 0x8e, 0xda, 0x8d, 0x5e, 0x7f,
+	0xf4, 0x65, 0x7c, 0xd8, 0x05, 0xd6, 0x03, 0x49, 0xea, 0xaa, 0x0f, 0x0e, 0x4f, 0xda, 0x46, 0x51,
+	0x31, 0x38, 0x65, 0x70, 0x8c, 0x74, 0x83, 0x34, 0xce, 0xab, 0x2a, 0x47, 0x6f, 0xfe, 0x0c, 0x00,
+	0xa7, 0xea, 0x2a, 0xb7, 0x78, 0x04, 0x00, 0x00,
 }
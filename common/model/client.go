@@ -18,6 +18,7 @@
 package model
 
 import (
+	"encoding/json"
 	"time"
 
 	"github.com/golang/protobuf/ptypes/wrappers"
@@ -57,6 +58,30 @@ func (c *Client) ModifyTime() time.Time {
 	return c.modifyTime
 }
 
+// clientJSON Client 的序列化格式，用于在存储节点之间复制客户端数据
+type clientJSON struct {
+	Proto      *api.Client `json:"proto"`
+	Valid      bool        `json:"valid"`
+	ModifyTime time.Time   `json:"modify_time"`
+}
+
+// MarshalJSON 序列化客户端信息
+func (c *Client) MarshalJSON() ([]byte, error) {
+	return json.Marshal(&clientJSON{Proto: c.proto, Valid: c.valid, ModifyTime: c.modifyTime})
+}
+
+// UnmarshalJSON 反序列化客户端信息
+func (c *Client) UnmarshalJSON(data []byte) error {
+	value := &clientJSON{}
+	if err := json.Unmarshal(data, value); err != nil {
+		return err
+	}
+	c.proto = value.Proto
+	c.valid = value.Valid
+	c.modifyTime = value.ModifyTime
+	return nil
+}
+
 // ClientStore 对应store层（database）的对象
 type ClientStore struct {
 	ID         string
@@ -17,7 +17,10 @@
 
 package model
 
-import "time"
+import (
+	"hash/fnv"
+	"time"
+)
 
 /** ----------- DataObject ------------- */
 
@@ -29,6 +32,8 @@ type ConfigFileGroup struct {
 	Comment    string
 	CreateTime time.Time
 	Owner      string
+	// Schema 校验分组下配置内容的 JSON Schema，为空时不校验
+	Schema     string
 	CreateBy   string
 	ModifyTime time.Time
 	ModifyBy   string
@@ -71,6 +76,26 @@ type ConfigFileRelease struct {
 	Valid      bool
 }
 
+// ConfigFileGrayRelease 配置文件灰度发布数据持久化对象，同一个配置文件同时只存在一个生效的灰度发布
+type ConfigFileGrayRelease struct {
+	Id         uint64
+	Name       string
+	Namespace  string
+	Group      string
+	FileName   string
+	Content    string
+	Comment    string
+	Md5        string
+	Version    uint64
+	Rule       string
+	Flag       int
+	CreateTime time.Time
+	CreateBy   string
+	ModifyTime time.Time
+	ModifyBy   string
+	Valid      bool
+}
+
 // ConfigFileReleaseHistory 配置文件发布历史记录数据持久化对象
 type ConfigFileReleaseHistory struct {
 	Id         uint64
@@ -119,3 +144,57 @@ type ConfigFileTemplate struct {
 	ModifyTime time.Time
 	ModifyBy   string
 }
+
+// ConfigFileGrayRule 配置文件灰度规则，各条件之间为或的关系
+type ConfigFileGrayRule struct {
+	// ClientIPs 命中灰度的客户端 IP 列表
+	ClientIPs []string `json:"clientIps,omitempty"`
+	// Labels 客户端需要携带的全部标签
+	Labels map[string]string `json:"labels,omitempty"`
+	// Percentage 按客户端 IP 散列后命中灰度的百分比，取值 [0, 100]
+	Percentage uint32 `json:"percentage,omitempty"`
+}
+
+// ConfigClient 配置中心客户端信息
+type ConfigClient struct {
+	IP     string
+	Labels map[string]string
+}
+
+// IsEmpty 灰度规则没有设置任何条件
+func (r *ConfigFileGrayRule) IsEmpty() bool {
+	return len(r.ClientIPs) == 0 && len(r.Labels) == 0 && r.Percentage == 0
+}
+
+// Match 判断客户端是否命中灰度规则
+func (r *ConfigFileGrayRule) Match(client *ConfigClient) bool {
+	if r == nil || client == nil {
+		return false
+	}
+	for _, ip := range r.ClientIPs {
+		if ip != "" && ip == client.IP {
+			return true
+		}
+	}
+	if len(r.Labels) > 0 {
+		matched := true
+		for k, v := range r.Labels {
+			if client.Labels[k] != v {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+	if r.Percentage > 0 && client.IP != "" {
+		if r.Percentage >= 100 {
+			return true
+		}
+		h := fnv.New32a()
+		_, _ = h.Write([]byte(client.IP))
+		return h.Sum32()%100 < r.Percentage
+	}
+	return false
+}
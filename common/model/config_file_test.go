@@ -0,0 +1,54 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package model
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestConfigFileGrayRule_Match 测试灰度规则匹配
+func TestConfigFileGrayRule_Match(t *testing.T) {
+	rule := &ConfigFileGrayRule{
+		ClientIPs: []string{"10.0.0.1"},
+		Labels:    map[string]string{"env": "gray", "zone": "a"},
+	}
+	assert.True(t, rule.Match(&ConfigClient{IP: "10.0.0.1"}))
+	assert.True(t, rule.Match(&ConfigClient{IP: "10.0.0.2", Labels: map[string]string{"env": "gray", "zone": "a"}}))
+	assert.False(t, rule.Match(&ConfigClient{IP: "10.0.0.2", Labels: map[string]string{"env": "gray"}}))
+	assert.False(t, rule.Match(nil))
+
+	full := &ConfigFileGrayRule{Percentage: 100}
+	assert.True(t, full.Match(&ConfigClient{IP: "10.0.0.2"}))
+	assert.False(t, full.Match(&ConfigClient{}))
+
+	// 按比例灰度时，同一个客户端的结果是稳定的，并且扩大比例后仍然命中
+	part := &ConfigFileGrayRule{Percentage: 30}
+	hit := 0
+	for i := 0; i < 1000; i++ {
+		client := &ConfigClient{IP: fmt.Sprintf("192.168.%d.%d", i/250, i%250)}
+		if part.Match(client) {
+			hit++
+			assert.True(t, part.Match(client))
+			assert.True(t, (&ConfigFileGrayRule{Percentage: 60}).Match(client))
+		}
+	}
+	assert.True(t, hit > 0 && hit < 1000)
+}
@@ -0,0 +1,47 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package model
+
+import "time"
+
+// HealthChangeRecord 实例健康状态的变化记录
+type HealthChangeRecord struct {
+	Id         uint64 `json:"id"`
+	InstanceId string `json:"instance_id"`
+	Namespace  string `json:"namespace"`
+	Service    string `json:"service"`
+	Host       string `json:"host"`
+	Port       uint32 `json:"port"`
+	Healthy    bool   `json:"healthy"`
+	Isolate    bool   `json:"isolate"`
+	Reason     string `json:"reason"`
+	// Checker 做出变更的健康检查节点
+	Checker    string    `json:"checker"`
+	CreateTime time.Time `json:"create_time"`
+}
+
+// HealthHistoryFilter 健康状态变化记录的查询条件，实例ID不为空时忽略命名空间以及服务
+type HealthHistoryFilter struct {
+	InstanceId string
+	Namespace  string
+	Service    string
+	// Since 只查询创建时间晚于该时间的记录
+	Since time.Time
+	// Limit 最多返回的记录数，优先返回最新的记录
+	Limit int
+}
@@ -214,6 +214,17 @@ func (i *Instance) MallocProto() {
 	}
 }
 
+// HealthCheckProbe active probing health check type, the server dials the instance instead of waiting for heartbeats
+const HealthCheckProbe api.HealthCheck_HealthCheckType = 2
+
+// NormalizeHealthCheckType returns the supported health check type, heartbeat is used by default
+func NormalizeHealthCheckType(checkType api.HealthCheck_HealthCheckType) api.HealthCheck_HealthCheckType {
+	if checkType == HealthCheckProbe {
+		return HealthCheckProbe
+	}
+	return api.HealthCheck_HEARTBEAT
+}
+
 // InstanceStore 对应store层（database）的对象
 type InstanceStore struct {
 	ID                string
@@ -23,4 +23,19 @@ const (
 
 	// MetaKeyBuildRevision build revision for server
 	MetaKeyBuildRevision = "build-revision"
+
+	// MetaKeyProbeProtocol protocol used by active probing, one of tcp/http/grpc, default tcp
+	MetaKeyProbeProtocol = "internal-probe-protocol"
+	// MetaKeyProbePort port to probe, default is the instance port
+	MetaKeyProbePort = "internal-probe-port"
+	// MetaKeyProbeTimeout timeout of a single probe, like 2s or 500ms
+	MetaKeyProbeTimeout = "internal-probe-timeout"
+	// MetaKeyProbeHTTPPath request path of http probing, default /
+	MetaKeyProbeHTTPPath = "internal-probe-http-path"
+	// MetaKeyProbeHTTPExpectStatus expected status codes of http probing, like 200,204 or 2xx, default 2xx
+	MetaKeyProbeHTTPExpectStatus = "internal-probe-http-expect-status"
+	// MetaKeyProbeHTTPExpectBody substring which the http response body must contain
+	MetaKeyProbeHTTPExpectBody = "internal-probe-http-expect-body"
+	// MetaKeyProbeGRPCService service name sent in grpc health check request, default empty means the whole server
+	MetaKeyProbeGRPCService = "internal-probe-grpc-service"
 )
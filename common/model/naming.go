@@ -412,6 +412,8 @@ const (
 	RRoutingV2         Resource = "RoutingV2"
 	RInstance          Resource = "Instance"
 	RRateLimit         Resource = "RateLimit"
+	RCircuitBreakerV2  Resource = "CircuitBreakerV2"
+	RFaultMirror       Resource = "FaultMirror"
 	RMeshResource      Resource = "MeshResource"
 	RMesh              Resource = "Mesh"
 	RMeshService       Resource = "MeshService"
@@ -502,6 +504,8 @@ const (
 	EventInstanceCloseIsolate DiscoverEventType = "InstanceCloseIsolate"
 	// EventInstanceOffline Instance offline
 	EventInstanceOffline DiscoverEventType = "InstanceOffline"
+	// EventInstanceExpired Instance is deregistered after being unhealthy for too long
+	EventInstanceExpired DiscoverEventType = "InstanceExpired"
 )
 
 // DiscoverEvent 服务发现事件
@@ -0,0 +1,146 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package v2
+
+import (
+	"time"
+
+	"github.com/golang/protobuf/jsonpb"
+
+	apiv2 "github.com/polarismesh/polaris/common/api/v2"
+	commontime "github.com/polarismesh/polaris/common/time"
+)
+
+// MatchAll 熔断规则中主调、被调服务以及命名空间的通配符
+const MatchAll = "*"
+
+// ExtendCircuitBreakerRule 熔断规则的扩展，提前反序列化出规则内容
+type ExtendCircuitBreakerRule struct {
+	*CircuitBreakerRule
+	// Proto 熔断规则的具体内容
+	Proto *apiv2.CircuitBreaker
+}
+
+// ToApi 转为 api 对象
+func (r *ExtendCircuitBreakerRule) ToApi() *apiv2.CircuitBreaker {
+	return &apiv2.CircuitBreaker{
+		Id:                 r.ID,
+		Name:               r.Name,
+		Namespace:          r.Namespace,
+		Enable:             r.Enable,
+		Revision:           r.Revision,
+		Ctime:              commontime.Time2String(r.CreateTime),
+		Mtime:              commontime.Time2String(r.ModifyTime),
+		Etime:              commontime.Time2String(r.EnableTime),
+		Description:        r.Description,
+		Level:              r.Proto.GetLevel(),
+		RuleMatcher:        r.Proto.GetRuleMatcher(),
+		ErrorConditions:    r.Proto.GetErrorConditions(),
+		TriggerCondition:   r.Proto.GetTriggerCondition(),
+		MaxEjectionPercent: r.Proto.GetMaxEjectionPercent(),
+		RecoverCondition:   r.Proto.GetRecoverCondition(),
+	}
+}
+
+// MatchService 判断熔断规则的被调服务是否匹配该服务
+func (r *ExtendCircuitBreakerRule) MatchService(service, namespace string) bool {
+	if r.DstNamespace != MatchAll && r.DstNamespace != namespace {
+		return false
+	}
+	return r.DstService == MatchAll || r.DstService == service
+}
+
+// CircuitBreakerRule 熔断规则
+type CircuitBreakerRule struct {
+	// ID 规则唯一标识
+	ID string `json:"id"`
+	// Namespace 所属的命名空间
+	Namespace string `json:"namespace"`
+	// Name 规则名称
+	Name string `json:"name"`
+	// Enable 熔断规则是否启用
+	Enable bool `json:"enable"`
+	// Level 熔断粒度
+	Level string `json:"level"`
+	// SrcService 主调服务
+	SrcService string `json:"src_service"`
+	// SrcNamespace 主调服务所在命名空间
+	SrcNamespace string `json:"src_namespace"`
+	// DstService 被调服务
+	DstService string `json:"dst_service"`
+	// DstNamespace 被调服务所在命名空间
+	DstNamespace string `json:"dst_namespace"`
+	// Rule 熔断规则的具体内容，json 格式
+	Rule string `json:"rule"`
+	// Revision 熔断规则的版本信息
+	Revision string `json:"revision"`
+	// Description 规则简单描述
+	Description string `json:"description"`
+	// Valid 熔断规则是否有效，没有被逻辑删除
+	Valid bool `json:"flag"`
+	// CreateTime 规则创建时间
+	CreateTime time.Time `json:"ctime"`
+	// ModifyTime 规则修改时间
+	ModifyTime time.Time `json:"mtime"`
+	// EnableTime 规则最近一次启用时间
+	EnableTime time.Time `json:"etime"`
+}
+
+// ToExtendCircuitBreakerRule 转为扩展对象，提前反序列化出相应的 pb struct
+func (r *CircuitBreakerRule) ToExtendCircuitBreakerRule() (*ExtendCircuitBreakerRule, error) {
+	rule := &apiv2.CircuitBreaker{}
+	if err := jsonpb.UnmarshalString(r.Rule, rule); err != nil {
+		return nil, err
+	}
+	return &ExtendCircuitBreakerRule{
+		CircuitBreakerRule: r,
+		Proto:              rule,
+	}, nil
+}
+
+// ParseFromAPI 从 API 对象中转换出内部对象
+func (r *CircuitBreakerRule) ParseFromAPI(rule *apiv2.CircuitBreaker) error {
+	// 只需要保存规则的具体内容，基础属性单独存储
+	content := &apiv2.CircuitBreaker{
+		Level:              rule.GetLevel(),
+		RuleMatcher:        rule.GetRuleMatcher(),
+		ErrorConditions:    rule.GetErrorConditions(),
+		TriggerCondition:   rule.GetTriggerCondition(),
+		MaxEjectionPercent: rule.GetMaxEjectionPercent(),
+		RecoverCondition:   rule.GetRecoverCondition(),
+	}
+	marshaler := &jsonpb.Marshaler{}
+	data, err := marshaler.MarshalToString(content)
+	if err != nil {
+		return err
+	}
+
+	r.ID = rule.GetId()
+	r.Revision = rule.GetRevision()
+	r.Name = rule.GetName()
+	r.Namespace = rule.GetNamespace()
+	r.Enable = rule.GetEnable()
+	r.Description = rule.GetDescription()
+	r.Level = rule.GetLevel().String()
+	r.SrcService = rule.GetRuleMatcher().GetSource().GetService()
+	r.SrcNamespace = rule.GetRuleMatcher().GetSource().GetNamespace()
+	r.DstService = rule.GetRuleMatcher().GetDestination().GetService()
+	r.DstNamespace = rule.GetRuleMatcher().GetDestination().GetNamespace()
+	r.Rule = data
+	return nil
+}
@@ -0,0 +1,126 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package v2
+
+import (
+	"time"
+
+	"github.com/golang/protobuf/jsonpb"
+
+	apiv2 "github.com/polarismesh/polaris/common/api/v2"
+	commontime "github.com/polarismesh/polaris/common/time"
+)
+
+// ExtendFaultMirrorRule 故障注入与流量镜像规则的扩展，提前反序列化出规则内容
+type ExtendFaultMirrorRule struct {
+	*FaultMirrorRule
+	// Proto 规则的具体内容
+	Proto *apiv2.FaultMirrorRule
+}
+
+// ToApi 转为 api 对象
+func (r *ExtendFaultMirrorRule) ToApi() *apiv2.FaultMirrorRule {
+	return &apiv2.FaultMirrorRule{
+		Id:          r.ID,
+		Name:        r.Name,
+		Namespace:   r.Namespace,
+		Enable:      r.Enable,
+		Revision:    r.Revision,
+		Ctime:       commontime.Time2String(r.CreateTime),
+		Mtime:       commontime.Time2String(r.ModifyTime),
+		Etime:       commontime.Time2String(r.EnableTime),
+		Description: r.Description,
+		Service:     r.Service,
+		Sources:     r.Proto.GetSources(),
+		Fault:       r.Proto.GetFault(),
+		Mirrors:     r.Proto.GetMirrors(),
+	}
+}
+
+// MatchService 判断规则的目标服务是否匹配该服务
+func (r *ExtendFaultMirrorRule) MatchService(service, namespace string) bool {
+	if r.Namespace != namespace {
+		return false
+	}
+	return r.Service == MatchAll || r.Service == service
+}
+
+// FaultMirrorRule 故障注入与流量镜像规则
+type FaultMirrorRule struct {
+	// ID 规则唯一标识
+	ID string `json:"id"`
+	// Namespace 目标服务所在的命名空间
+	Namespace string `json:"namespace"`
+	// Name 规则名称
+	Name string `json:"name"`
+	// Enable 规则是否启用
+	Enable bool `json:"enable"`
+	// Service 目标服务，* 表示命名空间下的所有服务
+	Service string `json:"service"`
+	// Rule 规则的具体内容，json 格式
+	Rule string `json:"rule"`
+	// Revision 规则的版本信息
+	Revision string `json:"revision"`
+	// Description 规则简单描述
+	Description string `json:"description"`
+	// Valid 规则是否有效，没有被逻辑删除
+	Valid bool `json:"flag"`
+	// CreateTime 规则创建时间
+	CreateTime time.Time `json:"ctime"`
+	// ModifyTime 规则修改时间
+	ModifyTime time.Time `json:"mtime"`
+	// EnableTime 规则最近一次启用时间
+	EnableTime time.Time `json:"etime"`
+}
+
+// ToExtendFaultMirrorRule 转为扩展对象，提前反序列化出相应的 pb struct
+func (r *FaultMirrorRule) ToExtendFaultMirrorRule() (*ExtendFaultMirrorRule, error) {
+	rule := &apiv2.FaultMirrorRule{}
+	if err := jsonpb.UnmarshalString(r.Rule, rule); err != nil {
+		return nil, err
+	}
+	return &ExtendFaultMirrorRule{
+		FaultMirrorRule: r,
+		Proto:           rule,
+	}, nil
+}
+
+// ParseFromAPI 从 API 对象中转换出内部对象
+func (r *FaultMirrorRule) ParseFromAPI(rule *apiv2.FaultMirrorRule) error {
+	// 只需要保存规则的具体内容，基础属性单独存储
+	content := &apiv2.FaultMirrorRule{
+		Sources: rule.GetSources(),
+		Fault:   rule.GetFault(),
+		Mirrors: rule.GetMirrors(),
+	}
+	marshaler := &jsonpb.Marshaler{}
+	data, err := marshaler.MarshalToString(content)
+	if err != nil {
+		return err
+	}
+
+	r.ID = rule.GetId()
+	r.Revision = rule.GetRevision()
+	r.Name = rule.GetName()
+	r.Namespace = rule.GetNamespace()
+	r.Enable = rule.GetEnable()
+	r.Description = rule.GetDescription()
+	r.Service = rule.GetService()
+	r.Rule = data
+	return nil
+}
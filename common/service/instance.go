@@ -144,7 +144,7 @@ func CreateInstanceModel(serviceID string, req *api.Instance) *model.Instance {
 		(req.GetEnableHealthCheck() == nil || req.GetEnableHealthCheck().GetValue()) {
 		protoIns.EnableHealthCheck = utils.NewBoolValue(true)
 		protoIns.HealthCheck = req.HealthCheck
-		protoIns.HealthCheck.Type = api.HealthCheck_HEARTBEAT
+		protoIns.HealthCheck.Type = model.NormalizeHealthCheckType(req.GetHealthCheck().GetType())
 		// ttl range: (0, 60]
 		ttl := protoIns.GetHealthCheck().GetHeartbeat().GetTtl().GetValue()
 		if ttl == 0 || ttl > 60 {
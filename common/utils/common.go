@@ -355,6 +355,31 @@ func ParseClientAddress(ctx context.Context) string {
 	return rid
 }
 
+// ParseClientLabels 从ctx中获取客户端标签，标签格式为 k1=v1,k2=v2
+func ParseClientLabels(ctx context.Context) map[string]string {
+	if ctx == nil {
+		return nil
+	}
+	raw, _ := ctx.Value(ContextClientLabels).(string)
+	if raw == "" {
+		return nil
+	}
+	labels := make(map[string]string)
+	for _, item := range strings.Split(raw, ",") {
+		kv := strings.SplitN(item, "=", 2)
+		key := strings.TrimSpace(kv[0])
+		if key == "" {
+			continue
+		}
+		var value string
+		if len(kv) == 2 {
+			value = strings.TrimSpace(kv[1])
+		}
+		labels[key] = value
+	}
+	return labels
+}
+
 // ParseAuthToken 从ctx中获取token
 func ParseAuthToken(ctx context.Context) string {
 	if ctx == nil {
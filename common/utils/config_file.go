@@ -24,6 +24,14 @@ const (
 	ReleaseTypeNormal = "normal"
 	// ReleaseTypeDelete 发布类型，删除配置文件
 	ReleaseTypeDelete = "delete"
+	// ReleaseTypeGray 发布类型，灰度发布
+	ReleaseTypeGray = "gray"
+	// ReleaseTypeGrayPromote 发布类型，灰度转全量
+	ReleaseTypeGrayPromote = "gray-promote"
+	// ReleaseTypeGrayAbort 发布类型，终止灰度
+	ReleaseTypeGrayAbort = "gray-abort"
+	// ReleaseTypeRollback 发布类型，回滚到历史发布版本
+	ReleaseTypeRollback = "rollback"
 
 	// ReleaseStatusSuccess 发布成功状态
 	ReleaseStatusSuccess = "success"
@@ -39,14 +47,19 @@ const (
 	FileFormatJson       = "json"
 	FileFormatHtml       = "html"
 	FileFormatProperties = "properties"
+	FileFormatToml       = "toml"
 
 	FileIdSeparator = "+"
+
+	// ConfigFileTagKeyUseEncrypted 配置文件标签，值为 true 时配置内容加密存储
+	ConfigFileTagKeyUseEncrypted = "internal-encrypted"
 )
 
 // IsValidFileFormat 判断文件格式是否合法
 func IsValidFileFormat(format string) bool {
 	return format == FileFormatText || format == FileFormatYaml || format == FileFormatXml ||
-		format == FileFormatJson || format == FileFormatHtml || format == FileFormatProperties
+		format == FileFormatJson || format == FileFormatHtml || format == FileFormatProperties ||
+		format == FileFormatToml
 }
 
 // GenFileId 生成文件 Id
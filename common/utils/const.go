@@ -42,6 +42,10 @@ const (
 	HeaderOwnerIDKey string = "X-Owner-ID"
 	// HeaderUserRoleKey user role key
 	HeaderUserRoleKey string = "X-Polaris-User-Role"
+	// HeaderClientLabelsKey client labels key, format is k1=v1,k2=v2
+	HeaderClientLabelsKey string = "X-Polaris-Client-Labels"
+	// HeaderConfigPublicKey client rsa public key used to exchange config data key, base64 encoded DER
+	HeaderConfigPublicKey string = "X-Polaris-Config-Public-Key"
 
 	// ContextAuthTokenKey auth token key
 	ContextAuthTokenKey = StringContext(HeaderAuthTokenKey)
@@ -63,6 +67,10 @@ const (
 	ContextOpenAsyncRegis = StringContext("client-asyncRegis")
 	// ContextGrpcHeader grpc header key
 	ContextGrpcHeader = StringContext("grpc-header")
+	// ContextClientLabels client labels key
+	ContextClientLabels = StringContext(HeaderClientLabelsKey)
+	// ContextConfigPublicKey client config public key
+	ContextConfigPublicKey = StringContext(HeaderConfigPublicKey)
 )
 
 const (
@@ -21,6 +21,7 @@ import (
 	"context"
 
 	api "github.com/polarismesh/polaris/common/api/v1"
+	"github.com/polarismesh/polaris/common/model"
 )
 
 type (
@@ -46,6 +47,9 @@ type ConfigFileGroupOperate interface {
 
 	// UpdateConfigFileGroup 更新配置文件组
 	UpdateConfigFileGroup(ctx context.Context, configFileGroup *api.ConfigFileGroup) *api.ConfigResponse
+
+	// UpdateConfigFileGroupSchema 更新配置文件组的 JSON Schema，发布前按照 Schema 校验组内的配置内容
+	UpdateConfigFileGroupSchema(ctx context.Context, namespace, group, schema string) *api.ConfigResponse
 }
 
 // ConfigFileOperate 配置文件接口
@@ -85,6 +89,19 @@ type ConfigFileReleaseOperate interface {
 
 	// DeleteConfigFileRelease 删除配置文件发布内容
 	DeleteConfigFileRelease(ctx context.Context, namespace, group, fileName, deleteBy string) *api.ConfigResponse
+
+	// PublishConfigFileGray 灰度发布配置文件，只有命中灰度规则的客户端能获取到灰度内容
+	PublishConfigFileGray(ctx context.Context, configFileRelease *api.ConfigFileRelease,
+		rule *model.ConfigFileGrayRule) *api.ConfigResponse
+
+	// PromoteConfigFileGrayRelease 灰度转全量
+	PromoteConfigFileGrayRelease(ctx context.Context, namespace, group, fileName string) *api.ConfigResponse
+
+	// AbortConfigFileGrayRelease 终止灰度，灰度客户端回退到正式发布的内容
+	AbortConfigFileGrayRelease(ctx context.Context, namespace, group, fileName string) *api.ConfigResponse
+
+	// RollbackConfigFile 回滚配置文件到指定的历史发布版本
+	RollbackConfigFile(ctx context.Context, namespace, group, fileName string, historyId uint64) *api.ConfigResponse
 }
 
 // ConfigFileReleaseHistoryOperate 配置文件发布历史接口
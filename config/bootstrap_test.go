@@ -148,9 +148,9 @@ func (c *ConfigCenterTest) loadBootstrapConfig() error {
 	c.defaultCtx = context.WithValue(c.defaultCtx, utils.StringContext("request-id"), "config-test-request-id")
 	c.defaultCtx = context.WithValue(c.defaultCtx, utils.ContextUserNameKey, "polaris")
 
-	if os.Getenv("STORE_MODE") == "sqldb" {
-		fmt.Printf("run store mode : sqldb\n")
-		confFileName = testdata.Path("config_test_sqldb.yaml")
+	if storeMode := os.Getenv("STORE_MODE"); storeMode == "sqldb" || storeMode == "pgsql" {
+		fmt.Printf("run store mode : %s\n", storeMode)
+		confFileName = testdata.Path("config_test_" + storeMode + ".yaml")
 		c.defaultCtx = context.WithValue(c.defaultCtx, utils.ContextAuthTokenKey, "nu/0WRA4EqSR1FagrjRj0fZwPXuGlMpX+zCuWu4uMqy8xr1vRjisSbA25aAC3mtU8MeeRsKhQiDAynUR09I=")
 	} else {
 		c.defaultCtx = context.WithValue(c.defaultCtx, utils.ContextAuthTokenKey, "nu/0WRA4EqSR1FagrjRj0fZwPXuGlMpX+zCuWu4uMqy8xr1vRjisSbA25aAC3mtU8MeeRsKhQiDAynUR09I=")
@@ -180,7 +180,7 @@ func (c *ConfigCenterTest) clearTestData() error {
 		time.Sleep(5 * time.Second)
 	}()
 
-	if c.storage.Name() == sqldb.STORENAME {
+	if sqldb.IsSqlStore(c.storage.Name()) {
 		if err := c.clearTestDataWhenUseRDS(); err != nil {
 			return err
 		}
@@ -213,6 +213,8 @@ func (c *ConfigCenterTest) clearTestDataWhenUseBoltdb() error {
 		"ConfigFileReleaseHistoryID",
 		"ConfigFileRelease",
 		"ConfigFileReleaseID",
+		"ConfigFileGrayRelease",
+		"ConfigFileGrayReleaseID",
 		"ConfigFileTag",
 		"ConfigFileTagID",
 		"namespace",
@@ -254,6 +256,10 @@ func (c *ConfigCenterTest) clearTestDataWhenUseRDS() error {
 	if err != nil {
 		return err
 	}
+	_, err = tx.Exec("delete from config_file_gray_release where namespace = ? ", testNamespace)
+	if err != nil {
+		return err
+	}
 	_, err = tx.Exec("delete from config_file_release_history where namespace = ? ", testNamespace)
 	if err != nil {
 		return err
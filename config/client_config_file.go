@@ -65,6 +65,17 @@ func (s *Server) GetConfigFileForClient(ctx context.Context,
 		return api.NewConfigClientResponse(api.NotFoundResource, nil)
 	}
 
+	// 命中灰度规则的客户端获取灰度发布的内容
+	if gray := s.grayCache.match(namespace, group, fileName, entry.Version, parseConfigClient(ctx)); gray != nil {
+		log.Info("[Config][Client] client get gray config file success.",
+			zap.String("requestId", requestID),
+			zap.String("client", utils.ParseClientAddress(ctx)),
+			zap.String("file", fileName),
+			zap.Uint64("version", gray.Version))
+		content, md5 := s.clientConfigFileContent(ctx, gray.Content, gray.Md5)
+		return utils2.GenConfigFileResponse(namespace, group, fileName, content, md5, gray.Version)
+	}
+
 	// 客户端版本号大于服务端版本号，服务端需要重新加载缓存
 	if clientVersion > entry.Version {
 		entry, err = s.fileCache.ReLoad(namespace, group, fileName)
@@ -83,14 +94,17 @@ func (s *Server) GetConfigFileForClient(ctx context.Context,
 		zap.String("file", fileName),
 		zap.Uint64("version", entry.Version))
 
-	resp := utils2.GenConfigFileResponse(namespace, group, fileName, entry.Content, entry.Md5, entry.Version)
-	return resp
+	content, md5 := s.clientConfigFileContent(ctx, entry.Content, entry.Md5)
+	return utils2.GenConfigFileResponse(namespace, group, fileName, content, md5, entry.Version)
 }
 
 func (s *Server) WatchConfigFiles(ctx context.Context,
 	request *api.ClientWatchConfigFileRequest) (WatchCallback, error) {
 	clientAddr := utils.ParseClientAddress(ctx)
 	watchFiles := request.GetWatchFiles()
+	if clientIP := request.GetClientIp().GetValue(); clientIP != "" {
+		ctx = context.WithValue(ctx, utils.StringContext("client-ip"), clientIP)
+	}
 	// 2. 检查客户端是否有版本落后
 	if resp := s.doCheckClientConfigFile(ctx, watchFiles, compareByVersion); resp.Code.GetValue() != api.DataNoChange {
 		return func() *api.ConfigClientResponse {
@@ -101,7 +115,7 @@ func (s *Server) WatchConfigFiles(ctx context.Context,
 	// 3. 监听配置变更，hold 请求 30s，30s 内如果有配置发布，则响应请求
 	clientId := clientAddr + "@" + utils.NewUUID()[0:8]
 
-	finishChan := s.ConnManager().AddConn(clientId, watchFiles)
+	finishChan := s.ConnManager().AddConn(clientId, parseConfigClient(ctx), watchFiles)
 
 	return func() *api.ConfigClientResponse {
 		return <-finishChan
@@ -123,6 +137,7 @@ func (s *Server) doCheckClientConfigFile(ctx context.Context, configFiles []*api
 	}
 
 	requestID := utils.ParseRequestID(ctx)
+	client := parseConfigClient(ctx)
 	for _, configFile := range configFiles {
 		namespace := configFile.Namespace.GetValue()
 		group := configFile.Group.GetValue()
@@ -145,6 +160,10 @@ func (s *Server) doCheckClientConfigFile(ctx context.Context, configFiles []*api
 			return api.NewConfigClientResponse(api.ExecuteException, nil)
 		}
 
+		if gray := s.grayCache.match(namespace, group, fileName, entry.Version, client); gray != nil && !entry.Empty {
+			entry = &cache.Entry{Content: gray.Content, Md5: gray.Md5, Version: gray.Version}
+		}
+
 		if compartor(configFile, entry) {
 			return utils2.GenConfigFileResponse(namespace, group, fileName, "", entry.Md5, entry.Version)
 		}
@@ -21,11 +21,25 @@ import (
 	"context"
 
 	api "github.com/polarismesh/polaris/common/api/v1"
+	"github.com/polarismesh/polaris/common/model"
+	"github.com/polarismesh/polaris/common/utils"
 )
 
 // GetConfigFileForClient 从缓存中获取配置文件，如果客户端的版本号大于服务端，则服务端重新加载缓存
+// 客户端上报公钥时会下发加密配置的数据密钥，需要先校验客户端对配置文件的读权限
 func (s *serverAuthability) GetConfigFileForClient(ctx context.Context,
 	fileInfo *api.ClientConfigFileInfo) *api.ConfigClientResponse {
+	if publicKey, _ := ctx.Value(utils.ContextConfigPublicKey).(string); publicKey != "" {
+		authCtx := s.collectConfigFileAuthContext(ctx, []*api.ConfigFile{{
+			Namespace: fileInfo.GetNamespace(),
+			Group:     fileInfo.GetGroup(),
+			Name:      fileInfo.GetFileName(),
+		}}, model.Read, "GetConfigFileForClient")
+		if _, err := s.checker.CheckClientPermission(authCtx); err != nil {
+			return api.NewConfigClientResponseWithMessage(convertToErrCode(err), err.Error())
+		}
+		ctx = authCtx.GetRequestContext()
+	}
 	return s.targetServer.GetConfigFileForClient(ctx, fileInfo)
 }
 
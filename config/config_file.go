@@ -93,8 +93,16 @@ func (s *Server) CreateConfigFile(ctx context.Context, configFile *api.ConfigFil
 		return api.NewConfigFileResponse(api.ExistedResource, configFile)
 	}
 
+	if rsp := s.checkConfigFileContent(ctx, namespace, group, configFile.Format.GetValue(),
+		configFile.Content.GetValue()); rsp != nil {
+		return rsp
+	}
+
 	fileStoreModel := transferConfigFileAPIModel2StoreModel(configFile)
 	fileStoreModel.ModifyBy = fileStoreModel.CreateBy
+	if rsp := s.encryptConfigFileIfNeed(ctx, configFile, fileStoreModel); rsp != nil {
+		return rsp
+	}
 
 	// 创建配置文件
 	createdFile, err := s.storage.CreateConfigFile(s.getTx(ctx), fileStoreModel)
@@ -122,7 +130,8 @@ func (s *Server) CreateConfigFile(ctx context.Context, configFile *api.ConfigFil
 		zap.String("name", name),
 		zap.Error(err))
 
-	return api.NewConfigFileResponse(api.ExecuteSuccess, transferConfigFileStoreModel2APIModel(createdFile))
+	return api.NewConfigFileResponse(api.ExecuteSuccess,
+		s.decryptConfigFile(ctx, transferConfigFileStoreModel2APIModel(createdFile)))
 }
 
 // GetConfigFileBaseInfo 获取配置文件，只返回基础元信息
@@ -155,7 +164,8 @@ func (s *Server) GetConfigFileBaseInfo(ctx context.Context, namespace, group, na
 		return api.NewConfigFileResponse(api.NotFoundResource, nil)
 	}
 
-	return api.NewConfigFileResponse(api.ExecuteSuccess, transferConfigFileStoreModel2APIModel(file))
+	return api.NewConfigFileResponse(api.ExecuteSuccess,
+		s.decryptConfigFile(ctx, transferConfigFileStoreModel2APIModel(file)))
 }
 
 // GetConfigFileRichInfo 获取单个配置文件基础信息，包含发布状态等信息
@@ -216,7 +226,7 @@ func (s *Server) QueryConfigFilesByGroup(ctx context.Context, namespace, group s
 
 	var fileAPIModels []*api.ConfigFile
 	for _, file := range files {
-		baseFile := transferConfigFileStoreModel2APIModel(file)
+		baseFile := s.decryptConfigFile(ctx, transferConfigFileStoreModel2APIModel(file))
 		baseFile, err = s.fillReleaseAndTags(ctx, baseFile)
 		if err != nil {
 			return api.NewConfigFileBatchQueryResponse(api.StoreLayerException, 0, nil)
@@ -297,7 +307,7 @@ func (s *Server) queryConfigFileWithoutTags(ctx context.Context, namespace, grou
 	fileAPIModels := make([]*api.ConfigFile, 0, len(files))
 
 	for _, file := range files {
-		baseFile := transferConfigFileStoreModel2APIModel(file)
+		baseFile := s.decryptConfigFile(ctx, transferConfigFileStoreModel2APIModel(file))
 		baseFile, err = s.fillReleaseAndTags(ctx, baseFile)
 		if err != nil {
 			return api.NewConfigFileBatchQueryResponse(api.StoreLayerException, 0, nil)
@@ -345,6 +355,12 @@ func (s *Server) UpdateConfigFile(ctx context.Context, configFile *api.ConfigFil
 	if configFile.Format.GetValue() == "" {
 		toUpdateFile.Format = managedFile.Format
 	}
+	if rsp := s.checkConfigFileContent(ctx, namespace, group, toUpdateFile.Format, toUpdateFile.Content); rsp != nil {
+		return rsp
+	}
+	if rsp := s.encryptConfigFileIfNeed(ctx, configFile, toUpdateFile); rsp != nil {
+		return rsp
+	}
 
 	updatedFile, err := s.storage.UpdateConfigFile(s.getTx(ctx), toUpdateFile)
 	if err != nil {
@@ -363,7 +379,7 @@ func (s *Server) UpdateConfigFile(ctx context.Context, configFile *api.ConfigFil
 		return response
 	}
 
-	baseFile := transferConfigFileStoreModel2APIModel(updatedFile)
+	baseFile := s.decryptConfigFile(ctx, transferConfigFileStoreModel2APIModel(updatedFile))
 	baseFile, err = s.fillReleaseAndTags(ctx, baseFile)
 
 	return api.NewConfigFileResponse(api.ExecuteSuccess, baseFile)
@@ -473,6 +489,46 @@ func (s *Server) BatchDeleteConfigFile(ctx context.Context, configFiles []*api.C
 	return api.NewConfigFileResponse(api.ExecuteSuccess, nil)
 }
 
+// checkConfigFileContent 按照配置文件格式以及所属配置分组的 JSON Schema 校验配置内容
+func (s *Server) checkConfigFileContent(ctx context.Context, namespace, group, format,
+	content string) *api.ConfigResponse {
+	if err := utils2.CheckContentFormat(format, content); err != nil {
+		return api.NewConfigFileResponseWithMessage(api.InvalidConfigFileContent, err.Error())
+	}
+
+	fileGroup, err := s.storage.GetConfigFileGroup(namespace, group)
+	if err != nil {
+		log.Error("[Config][Service] get config file group error.",
+			utils.ZapRequestIDByCtx(ctx),
+			zap.String("namespace", namespace),
+			zap.String("group", group),
+			zap.Error(err))
+		return api.NewConfigFileResponse(api.StoreLayerException, nil)
+	}
+	if fileGroup == nil || fileGroup.Schema == "" {
+		return nil
+	}
+	if err := utils2.CheckContentSchema(format, content, fileGroup.Schema); err != nil {
+		return api.NewConfigFileResponseWithMessage(api.InvalidConfigFileSchema, err.Error())
+	}
+	return nil
+}
+
+// checkPublishConfigFileContent 发布前校验待发布的配置内容，加密存储的内容先解密再校验
+func (s *Server) checkPublishConfigFileContent(ctx context.Context, file *model.ConfigFile) *api.ConfigResponse {
+	content, err := s.decryptConfigFileContent(file.Content)
+	if err != nil {
+		log.Error("[Config][Service] decrypt config file content error.",
+			utils.ZapRequestIDByCtx(ctx),
+			zap.String("namespace", file.Namespace),
+			zap.String("group", file.Group),
+			zap.String("name", file.Name),
+			zap.Error(err))
+		return api.NewConfigFileResponse(api.ExecuteException, nil)
+	}
+	return s.checkConfigFileContent(ctx, file.Namespace, file.Group, file.Format, content)
+}
+
 func checkConfigFileParams(configFile *api.ConfigFile, checkFormat bool) *api.ConfigResponse {
 	if configFile == nil {
 		return api.NewConfigFileResponse(api.InvalidParameter, configFile)
@@ -595,7 +651,9 @@ func (s *Server) fillReleaseAndTags(ctx context.Context, file *api.ConfigFile) (
 	}
 
 	latestRelease := latestReleaseRsp.ConfigFileReleaseHistory
-	if latestRelease != nil && latestRelease.Type.GetValue() == utils.ReleaseTypeNormal {
+	if latestRelease != nil && (latestRelease.Type.GetValue() == utils.ReleaseTypeNormal ||
+		latestRelease.Type.GetValue() == utils.ReleaseTypeGrayPromote ||
+		latestRelease.Type.GetValue() == utils.ReleaseTypeRollback) {
 		file.ReleaseBy = latestRelease.CreateBy
 		file.ReleaseTime = latestRelease.CreateTime
 
@@ -0,0 +1,250 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package config
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	api "github.com/polarismesh/polaris/common/api/v1"
+	"github.com/polarismesh/polaris/common/model"
+	"github.com/polarismesh/polaris/common/utils"
+	utils2 "github.com/polarismesh/polaris/config/utils"
+	"github.com/polarismesh/polaris/plugin"
+)
+
+const (
+	// encryptedContentPrefix 加密配置内容前缀，格式为 polaris-enc:v1:{算法}:{base64 数据密钥}:{密文}
+	encryptedContentPrefix = "polaris-enc:v1"
+)
+
+var (
+	// ErrCryptoNotConfigured 未配置加解密插件
+	ErrCryptoNotConfigured = errors.New("crypto plugin not configured")
+	// ErrInvalidEncryptedContent 加密内容格式错误
+	ErrInvalidEncryptedContent = errors.New("invalid encrypted config content")
+)
+
+// isEncryptConfigFile 配置文件是否开启了加密存储
+func isEncryptConfigFile(tags []*api.ConfigFileTag) bool {
+	for _, tag := range tags {
+		if tag.GetKey().GetValue() == utils.ConfigFileTagKeyUseEncrypted {
+			return tag.GetValue().GetValue() == "true"
+		}
+	}
+	return false
+}
+
+// isEncryptedContent 判断内容是否为加密后的格式
+func isEncryptedContent(content string) bool {
+	return strings.HasPrefix(content, encryptedContentPrefix+":")
+}
+
+func formatEncryptedContent(algorithm string, key []byte, ciphertext string) string {
+	return fmt.Sprintf("%s:%s:%s:%s", encryptedContentPrefix, algorithm,
+		base64.StdEncoding.EncodeToString(key), ciphertext)
+}
+
+// parseEncryptedContent 解析加密内容，返回算法、数据密钥以及密文
+func parseEncryptedContent(content string) (string, []byte, string, error) {
+	items := strings.SplitN(content, ":", 5)
+	if len(items) != 5 || items[0]+":"+items[1] != encryptedContentPrefix {
+		return "", nil, "", ErrInvalidEncryptedContent
+	}
+	key, err := base64.StdEncoding.DecodeString(items[3])
+	if err != nil {
+		return "", nil, "", ErrInvalidEncryptedContent
+	}
+	return items[2], key, items[4], nil
+}
+
+// encryptConfigFileContent 每次写入生成新的数据密钥加密配置内容，数据密钥由主密钥加密后随密文一起保存
+func (s *Server) encryptConfigFileContent(content string) (string, error) {
+	if s.crypto == nil {
+		return "", ErrCryptoNotConfigured
+	}
+	dataKey, err := s.crypto.GenerateKey()
+	if err != nil {
+		return "", err
+	}
+	ciphertext, err := s.crypto.Encrypt(content, dataKey)
+	if err != nil {
+		return "", err
+	}
+	wrappedKey, err := s.crypto.WrapKey(dataKey)
+	if err != nil {
+		return "", err
+	}
+	return formatEncryptedContent(s.crypto.Algorithm(), wrappedKey, ciphertext), nil
+}
+
+// encryptConfigFileIfNeed 配置文件开启加密时，加密待存储的配置内容
+func (s *Server) encryptConfigFileIfNeed(ctx context.Context, configFile *api.ConfigFile,
+	file *model.ConfigFile) *api.ConfigResponse {
+	if !isEncryptConfigFile(configFile.GetTags()) {
+		return nil
+	}
+	content, err := s.encryptConfigFileContent(file.Content)
+	if err != nil {
+		log.Error("[Config][Service] encrypt config file content error.",
+			utils.ZapRequestIDByCtx(ctx),
+			zap.String("namespace", file.Namespace),
+			zap.String("group", file.Group),
+			zap.String("name", file.Name),
+			zap.Error(err))
+		if errors.Is(err, ErrCryptoNotConfigured) {
+			return api.NewConfigFileResponseWithMessage(api.BadRequest, err.Error())
+		}
+		return api.NewConfigFileResponse(api.ExecuteException, configFile)
+	}
+	file.Content = content
+	return nil
+}
+
+// decryptConfigFileContent 解密配置内容，非加密内容原样返回
+func (s *Server) decryptConfigFileContent(content string) (string, error) {
+	if !isEncryptedContent(content) {
+		return content, nil
+	}
+	if s.crypto == nil {
+		return "", ErrCryptoNotConfigured
+	}
+	_, wrappedKey, ciphertext, err := parseEncryptedContent(content)
+	if err != nil {
+		return "", err
+	}
+	dataKey, err := s.crypto.UnwrapKey(wrappedKey)
+	if err != nil {
+		return "", err
+	}
+	return s.crypto.Decrypt(ciphertext, dataKey)
+}
+
+// decryptContentValue 控制台展示时解密配置内容，解密失败时保留密文
+func (s *Server) decryptContentValue(ctx context.Context, content *wrapperspb.StringValue) *wrapperspb.StringValue {
+	if content == nil || !isEncryptedContent(content.GetValue()) {
+		return content
+	}
+	plaintext, err := s.decryptConfigFileContent(content.GetValue())
+	if err != nil {
+		log.Error("[Config][Service] decrypt config file content error.",
+			utils.ZapRequestIDByCtx(ctx), zap.Error(err))
+		return content
+	}
+	return utils.NewStringValue(plaintext)
+}
+
+// decryptConfigFile 解密配置文件内容
+func (s *Server) decryptConfigFile(ctx context.Context, file *api.ConfigFile) *api.ConfigFile {
+	if file != nil {
+		file.Content = s.decryptContentValue(ctx, file.Content)
+	}
+	return file
+}
+
+// decryptConfigFileRelease 解密配置发布内容
+func (s *Server) decryptConfigFileRelease(ctx context.Context,
+	release *api.ConfigFileRelease) *api.ConfigFileRelease {
+	if release != nil {
+		release.Content = s.decryptContentValue(ctx, release.Content)
+	}
+	return release
+}
+
+// decryptReleaseHistory 解密配置发布历史内容
+func (s *Server) decryptReleaseHistory(ctx context.Context,
+	history *api.ConfigFileReleaseHistory) *api.ConfigFileReleaseHistory {
+	if history != nil {
+		history.Content = s.decryptContentValue(ctx, history.Content)
+	}
+	return history
+}
+
+// clientConfigFileContent 下发给客户端的加密配置，数据密钥使用客户端上报的 RSA 公钥重新加密，
+// 客户端未上报公钥时不下发数据密钥。下发内容与存储内容不同时，按照下发内容重新计算 md5
+func (s *Server) clientConfigFileContent(ctx context.Context, content, md5 string) (string, string) {
+	if !isEncryptedContent(content) {
+		return content, md5
+	}
+	algorithm, wrappedKey, ciphertext, err := parseEncryptedContent(content)
+	if err != nil || s.crypto == nil {
+		return content, md5
+	}
+
+	var clientKey []byte
+	publicKey, _ := ctx.Value(utils.ContextConfigPublicKey).(string)
+	if publicKey != "" {
+		clientKey, err = s.exchangeDataKey(wrappedKey, publicKey)
+		if err != nil {
+			log.Error("[Config][Service] exchange config data key error.",
+				utils.ZapRequestIDByCtx(ctx), zap.Error(err))
+			clientKey = nil
+		}
+	}
+	clientContent := formatEncryptedContent(algorithm, clientKey, ciphertext)
+	return clientContent, utils2.CalMd5(clientContent)
+}
+
+func (s *Server) exchangeDataKey(wrappedKey []byte, publicKey string) ([]byte, error) {
+	der, err := base64.StdEncoding.DecodeString(publicKey)
+	if err != nil {
+		return nil, err
+	}
+	pub, err := x509.ParsePKIXPublicKey(der)
+	if err != nil {
+		return nil, err
+	}
+	rsaPub, ok := pub.(*rsa.PublicKey)
+	if !ok {
+		return nil, errors.New("config public key is not rsa public key")
+	}
+	dataKey, err := s.crypto.UnwrapKey(wrappedKey)
+	if err != nil {
+		return nil, err
+	}
+	return rsa.EncryptOAEP(sha256.New(), rand.Reader, rsaPub, dataKey, nil)
+}
+
+// DecryptClientConfigContent 客户端使用 RSA 私钥解密服务端下发的加密配置
+func DecryptClientConfigContent(content string, privateKey *rsa.PrivateKey, crypto plugin.Crypto) (string, error) {
+	if !isEncryptedContent(content) {
+		return content, nil
+	}
+	_, encryptedKey, ciphertext, err := parseEncryptedContent(content)
+	if err != nil {
+		return "", err
+	}
+	if len(encryptedKey) == 0 {
+		return "", errors.New("config data key not found, client public key required")
+	}
+	dataKey, err := rsa.DecryptOAEP(sha256.New(), rand.Reader, privateKey, encryptedKey, nil)
+	if err != nil {
+		return "", err
+	}
+	return crypto.Decrypt(ciphertext, dataKey)
+}
@@ -0,0 +1,116 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package config
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	api "github.com/polarismesh/polaris/common/api/v1"
+	"github.com/polarismesh/polaris/common/utils"
+	utils2 "github.com/polarismesh/polaris/config/utils"
+	"github.com/polarismesh/polaris/plugin/crypto/aes"
+)
+
+// TestEncryptConfigFile 测试配置文件加密存储、控制台解密展示以及客户端密钥交换
+func TestEncryptConfigFile(t *testing.T) {
+	testSuit, err := newConfigCenterTest(t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		testSuit.testServer.crypto = nil
+		if err := testSuit.clearTestData(); err != nil {
+			t.Fatal(err)
+		}
+	}()
+
+	configFile := assembleConfigFile()
+	configFile.Tags = append(configFile.Tags, &api.ConfigFileTag{
+		Key:   utils.NewStringValue(utils.ConfigFileTagKeyUseEncrypted),
+		Value: utils.NewStringValue("true"),
+	})
+	plaintext := configFile.Content.GetValue()
+
+	t.Run("未配置加密插件", func(t *testing.T) {
+		rsp := testSuit.testService.CreateConfigFile(testSuit.defaultCtx, configFile)
+		assert.Equal(t, api.BadRequest, rsp.Code.GetValue())
+	})
+
+	crypto := &aes.AESCrypto{}
+	assert.NoError(t, crypto.SetMasterKey(base64.StdEncoding.EncodeToString(make([]byte, 32))))
+	testSuit.testServer.crypto = crypto
+
+	t.Run("加密存储", func(t *testing.T) {
+		rsp := testSuit.testService.CreateConfigFile(testSuit.defaultCtx, configFile)
+		assert.Equal(t, api.ExecuteSuccess, rsp.Code.GetValue())
+		assert.Equal(t, plaintext, rsp.ConfigFile.Content.GetValue())
+
+		stored, err := testSuit.storage.GetConfigFile(nil, testNamespace, testGroup, testFile)
+		assert.NoError(t, err)
+		assert.True(t, isEncryptedContent(stored.Content))
+		assert.NotContains(t, stored.Content, plaintext)
+
+		rsp = testSuit.testService.GetConfigFileRichInfo(testSuit.defaultCtx, testNamespace, testGroup, testFile)
+		assert.Equal(t, api.ExecuteSuccess, rsp.Code.GetValue())
+		assert.Equal(t, plaintext, rsp.ConfigFile.Content.GetValue())
+	})
+
+	t.Run("发布后状态与内容", func(t *testing.T) {
+		rsp := testSuit.testService.PublishConfigFile(testSuit.defaultCtx, assembleConfigFileRelease(configFile))
+		assert.Equal(t, api.ExecuteSuccess, rsp.Code.GetValue())
+		assert.Equal(t, plaintext, rsp.ConfigFileRelease.Content.GetValue())
+
+		rsp = testSuit.testService.GetConfigFileRichInfo(testSuit.defaultCtx, testNamespace, testGroup, testFile)
+		assert.Equal(t, utils.ReleaseStatusSuccess, rsp.ConfigFile.Status.GetValue())
+
+		stored, err := testSuit.storage.GetConfigFileRelease(nil, testNamespace, testGroup, testFile)
+		assert.NoError(t, err)
+		assert.True(t, isEncryptedContent(stored.Content))
+	})
+
+	t.Run("客户端获取加密配置", func(t *testing.T) {
+		privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+		assert.NoError(t, err)
+		der, err := x509.MarshalPKIXPublicKey(&privateKey.PublicKey)
+		assert.NoError(t, err)
+
+		fileInfo := assembleDefaultClientConfigFile(0)[0]
+		ctx := context.WithValue(testSuit.defaultCtx, utils.ContextConfigPublicKey,
+			base64.StdEncoding.EncodeToString(der))
+		rsp := testSuit.testService.GetConfigFileForClient(ctx, fileInfo)
+		assert.Equal(t, api.ExecuteSuccess, rsp.Code.GetValue())
+		// md5 按照下发的内容计算
+		assert.Equal(t, utils2.CalMd5(rsp.ConfigFile.Content.GetValue()), rsp.ConfigFile.Md5.GetValue())
+		content, err := DecryptClientConfigContent(rsp.ConfigFile.Content.GetValue(), privateKey, crypto)
+		assert.NoError(t, err)
+		assert.Equal(t, plaintext, content)
+
+		// 未上报公钥的客户端无法解密
+		rsp = testSuit.testService.GetConfigFileForClient(testSuit.defaultCtx, fileInfo)
+		assert.Equal(t, api.ExecuteSuccess, rsp.Code.GetValue())
+		_, err = DecryptClientConfigContent(rsp.ConfigFile.Content.GetValue(), privateKey, crypto)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,345 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"sync"
+
+	"go.uber.org/zap"
+
+	api "github.com/polarismesh/polaris/common/api/v1"
+	"github.com/polarismesh/polaris/common/model"
+	"github.com/polarismesh/polaris/common/utils"
+	utils2 "github.com/polarismesh/polaris/config/utils"
+)
+
+// grayRelease 缓存中的灰度发布，包含解析后的灰度规则
+type grayRelease struct {
+	*model.ConfigFileGrayRelease
+	rule *model.ConfigFileGrayRule
+}
+
+// grayReleaseCache 生效中的灰度发布缓存，由发布事件扫描器维护
+type grayReleaseCache struct {
+	releases *sync.Map // fileId -> *grayRelease
+}
+
+func newGrayReleaseCache() *grayReleaseCache {
+	return &grayReleaseCache{releases: new(sync.Map)}
+}
+
+func (c *grayReleaseCache) get(namespace, group, fileName string) *grayRelease {
+	val, ok := c.releases.Load(utils.GenFileId(namespace, group, fileName))
+	if !ok {
+		return nil
+	}
+	return val.(*grayRelease)
+}
+
+func (c *grayReleaseCache) set(release *model.ConfigFileGrayRelease) (*grayRelease, error) {
+	rule := &model.ConfigFileGrayRule{}
+	if err := json.Unmarshal([]byte(release.Rule), rule); err != nil {
+		return nil, err
+	}
+	item := &grayRelease{ConfigFileGrayRelease: release, rule: rule}
+	c.releases.Store(utils.GenFileId(release.Namespace, release.Group, release.FileName), item)
+	return item, nil
+}
+
+func (c *grayReleaseCache) remove(namespace, group, fileName string) {
+	c.releases.Delete(utils.GenFileId(namespace, group, fileName))
+}
+
+// match 获取客户端命中的灰度发布，灰度版本必须比正式发布的版本新
+func (c *grayReleaseCache) match(namespace, group, fileName string, version uint64,
+	client *model.ConfigClient) *grayRelease {
+	item := c.get(namespace, group, fileName)
+	if item == nil || item.Version <= version || !item.rule.Match(client) {
+		return nil
+	}
+	return item
+}
+
+// parseConfigClient 从请求上下文中解析客户端的 IP 以及标签
+func parseConfigClient(ctx context.Context) *model.ConfigClient {
+	ip, _ := ctx.Value(utils.StringContext("client-ip")).(string)
+	if ip == "" {
+		address := utils.ParseClientAddress(ctx)
+		if host, _, err := net.SplitHostPort(address); err == nil {
+			ip = host
+		} else {
+			ip = address
+		}
+	}
+	return &model.ConfigClient{
+		IP:     ip,
+		Labels: utils.ParseClientLabels(ctx),
+	}
+}
+
+// PublishConfigFileGray 灰度发布配置文件，只有命中灰度规则的客户端能获取到灰度发布的内容
+func (s *Server) PublishConfigFileGray(ctx context.Context, configFileRelease *api.ConfigFileRelease,
+	rule *model.ConfigFileGrayRule) *api.ConfigResponse {
+	namespace := configFileRelease.Namespace.GetValue()
+	group := configFileRelease.Group.GetValue()
+	fileName := configFileRelease.FileName.GetValue()
+
+	if errRsp := checkReleaseFileParams(namespace, group, fileName); errRsp != nil {
+		return errRsp
+	}
+	if rule == nil || rule.IsEmpty() {
+		return api.NewConfigFileResponseWithMessage(api.InvalidParameter, "gray rule can not be empty")
+	}
+	if rule.Percentage > 100 {
+		return api.NewConfigFileResponseWithMessage(api.InvalidParameter, "gray percentage must be in [0, 100]")
+	}
+	if !s.checkNamespaceExisted(namespace) {
+		return api.NewConfigFileReleaseResponse(api.NotFoundNamespace, configFileRelease)
+	}
+
+	ruleStr, err := json.Marshal(rule)
+	if err != nil {
+		return api.NewConfigFileResponseWithMessage(api.InvalidParameter, err.Error())
+	}
+
+	requestID := utils.ParseRequestID(ctx)
+	userName := utils.ParseUserName(ctx)
+
+	tx, err := s.storage.StartTx()
+	if err != nil {
+		log.Error("[Config][Service] start tx error when gray release.", utils.ZapRequestID(requestID), zap.Error(err))
+		return api.NewConfigFileResponse(api.StoreLayerException, nil)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	toPublishFile, err := s.storage.GetConfigFile(tx, namespace, group, fileName)
+	if err != nil {
+		logReleaseError(requestID, "get config file error.", namespace, group, fileName, err)
+		return api.NewConfigFileResponse(api.StoreLayerException, nil)
+	}
+	if toPublishFile == nil {
+		return api.NewConfigFileResponse(api.NotFoundResource, nil)
+	}
+	if rsp := s.checkPublishConfigFileContent(ctx, toPublishFile); rsp != nil {
+		return rsp
+	}
+
+	mainRelease, err := s.storage.GetConfigFileRelease(tx, namespace, group, fileName)
+	if err != nil {
+		logReleaseError(requestID, "get config file release error.", namespace, group, fileName, err)
+		return api.NewConfigFileResponse(api.StoreLayerException, nil)
+	}
+	if mainRelease == nil {
+		return api.NewConfigFileResponseWithMessage(api.BadRequest,
+			"config file must be released before gray release")
+	}
+
+	managedGray, err := s.storage.GetConfigFileGrayReleaseWithAllFlag(tx, namespace, group, fileName)
+	if err != nil {
+		logReleaseError(requestID, "get config file gray release error.", namespace, group, fileName, err)
+		return api.NewConfigFileResponse(api.StoreLayerException, nil)
+	}
+
+	// 灰度版本与正式发布共用一个递增的版本序列
+	version := mainRelease.Version
+	if managedGray != nil && managedGray.Version > version {
+		version = managedGray.Version
+	}
+	if managedGray != nil && managedGray.Flag == 0 {
+		// 调整已有的灰度规则时，先用正式发布的内容推高版本，保证不再命中灰度的客户端能够回退到正式发布
+		version++
+		mainRelease.Version = version
+		mainRelease.ModifyBy = userName
+		if _, err = s.storage.UpdateConfigFileRelease(tx, mainRelease); err != nil {
+			logReleaseError(requestID, "update config file release error.", namespace, group, fileName, err)
+			return api.NewConfigFileResponse(api.StoreLayerException, nil)
+		}
+	}
+
+	releaseName := configFileRelease.Name.GetValue()
+	if releaseName == "" {
+		releaseName = fileName + "-gray"
+	}
+	grayModel := &model.ConfigFileGrayRelease{
+		Name:      releaseName,
+		Namespace: namespace,
+		Group:     group,
+		FileName:  fileName,
+		Content:   toPublishFile.Content,
+		Comment:   configFileRelease.Comment.GetValue(),
+		Md5:       utils2.CalMd5(toPublishFile.Content),
+		Version:   version + 1,
+		Rule:      string(ruleStr),
+		CreateBy:  userName,
+		ModifyBy:  userName,
+	}
+
+	var saved *model.ConfigFileGrayRelease
+	if managedGray == nil {
+		saved, err = s.storage.CreateConfigFileGrayRelease(tx, grayModel)
+	} else {
+		saved, err = s.storage.UpdateConfigFileGrayRelease(tx, grayModel)
+	}
+	if err != nil {
+		logReleaseError(requestID, "save config file gray release error.", namespace, group, fileName, err)
+		s.recordReleaseHistory(ctx, grayRelease2Release(grayModel), utils.ReleaseTypeGray, utils.ReleaseStatusFail)
+		return api.NewConfigFileResponse(api.StoreLayerException, nil)
+	}
+
+	if err = tx.Commit(); err != nil {
+		logReleaseError(requestID, "commit gray release tx error.", namespace, group, fileName, err)
+		s.recordReleaseHistory(ctx, grayRelease2Release(grayModel), utils.ReleaseTypeGray, utils.ReleaseStatusFail)
+		return api.NewConfigFileResponse(api.StoreLayerException, nil)
+	}
+
+	s.recordReleaseHistory(ctx, grayRelease2Release(saved), utils.ReleaseTypeGray, utils.ReleaseStatusSuccess)
+
+	return api.NewConfigFileReleaseResponse(api.ExecuteSuccess,
+		s.decryptConfigFileRelease(ctx, configFileRelease2Api(grayRelease2Release(saved))))
+}
+
+// PromoteConfigFileGrayRelease 灰度转全量，使用灰度发布的内容覆盖正式发布
+func (s *Server) PromoteConfigFileGrayRelease(ctx context.Context, namespace, group,
+	fileName string) *api.ConfigResponse {
+	return s.finishConfigFileGrayRelease(ctx, namespace, group, fileName, true)
+}
+
+// AbortConfigFileGrayRelease 终止灰度，灰度客户端回退到正式发布的内容
+func (s *Server) AbortConfigFileGrayRelease(ctx context.Context, namespace, group,
+	fileName string) *api.ConfigResponse {
+	return s.finishConfigFileGrayRelease(ctx, namespace, group, fileName, false)
+}
+
+func (s *Server) finishConfigFileGrayRelease(ctx context.Context, namespace, group, fileName string,
+	promote bool) *api.ConfigResponse {
+	if errRsp := checkReleaseFileParams(namespace, group, fileName); errRsp != nil {
+		return errRsp
+	}
+
+	releaseType := utils.ReleaseTypeGrayAbort
+	if promote {
+		releaseType = utils.ReleaseTypeGrayPromote
+	}
+	requestID := utils.ParseRequestID(ctx)
+	userName := utils.ParseUserName(ctx)
+
+	tx, err := s.storage.StartTx()
+	if err != nil {
+		log.Error("[Config][Service] start tx error when finish gray release.",
+			utils.ZapRequestID(requestID), zap.Error(err))
+		return api.NewConfigFileResponse(api.StoreLayerException, nil)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	gray, err := s.storage.GetConfigFileGrayReleaseWithAllFlag(tx, namespace, group, fileName)
+	if err != nil {
+		logReleaseError(requestID, "get config file gray release error.", namespace, group, fileName, err)
+		return api.NewConfigFileResponse(api.StoreLayerException, nil)
+	}
+	if gray == nil || gray.Flag != 0 {
+		return api.NewConfigFileResponse(api.NotFoundResource, nil)
+	}
+
+	mainRelease, err := s.storage.GetConfigFileRelease(tx, namespace, group, fileName)
+	if err != nil {
+		logReleaseError(requestID, "get config file release error.", namespace, group, fileName, err)
+		return api.NewConfigFileResponse(api.StoreLayerException, nil)
+	}
+	if mainRelease == nil {
+		return api.NewConfigFileResponse(api.NotFoundResource, nil)
+	}
+
+	if promote {
+		// 正式发布直接使用灰度版本号，已经拿到灰度内容的客户端无需再次推送
+		mainRelease.Name = utils2.GenReleaseName(mainRelease.Name, fileName)
+		mainRelease.Content = gray.Content
+		mainRelease.Comment = gray.Comment
+		mainRelease.Md5 = gray.Md5
+		mainRelease.Version = gray.Version
+	} else {
+		// 推高正式发布的版本号，通知灰度客户端回退
+		mainRelease.Version = gray.Version + 1
+	}
+	mainRelease.ModifyBy = userName
+
+	updated, err := s.storage.UpdateConfigFileRelease(tx, mainRelease)
+	if err == nil {
+		err = s.storage.DeleteConfigFileGrayRelease(tx, namespace, group, fileName, userName)
+	}
+	if err == nil {
+		err = tx.Commit()
+	}
+	if err != nil {
+		logReleaseError(requestID, "finish config file gray release error.", namespace, group, fileName, err)
+		s.recordReleaseHistory(ctx, mainRelease, releaseType, utils.ReleaseStatusFail)
+		return api.NewConfigFileResponse(api.StoreLayerException, nil)
+	}
+
+	s.recordReleaseHistory(ctx, updated, releaseType, utils.ReleaseStatusSuccess)
+
+	return api.NewConfigFileReleaseResponse(api.ExecuteSuccess,
+		s.decryptConfigFileRelease(ctx, configFileRelease2Api(updated)))
+}
+
+func checkReleaseFileParams(namespace, group, fileName string) *api.ConfigResponse {
+	if err := utils2.CheckFileName(utils.NewStringValue(fileName)); err != nil {
+		return api.NewConfigFileResponse(api.InvalidConfigFileName, nil)
+	}
+	if err := utils2.CheckResourceName(utils.NewStringValue(namespace)); err != nil {
+		return api.NewConfigFileResponse(api.InvalidNamespaceName, nil)
+	}
+	if err := utils2.CheckResourceName(utils.NewStringValue(group)); err != nil {
+		return api.NewConfigFileResponse(api.InvalidConfigFileGroupName, nil)
+	}
+	return nil
+}
+
+func logReleaseError(requestID, msg, namespace, group, fileName string, err error) {
+	log.Error("[Config][Service] "+msg,
+		utils.ZapRequestID(requestID),
+		zap.String("namespace", namespace),
+		zap.String("group", group),
+		zap.String("fileName", fileName),
+		zap.Error(err))
+}
+
+func grayRelease2Release(gray *model.ConfigFileGrayRelease) *model.ConfigFileRelease {
+	if gray == nil {
+		return nil
+	}
+	return &model.ConfigFileRelease{
+		Id:         gray.Id,
+		Name:       gray.Name,
+		Namespace:  gray.Namespace,
+		Group:      gray.Group,
+		FileName:   gray.FileName,
+		Content:    gray.Content,
+		Comment:    gray.Comment,
+		Md5:        gray.Md5,
+		Version:    gray.Version,
+		Flag:       gray.Flag,
+		CreateTime: gray.CreateTime,
+		CreateBy:   gray.CreateBy,
+		ModifyTime: gray.ModifyTime,
+		ModifyBy:   gray.ModifyBy,
+		Valid:      gray.Valid,
+	}
+}
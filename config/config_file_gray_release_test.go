@@ -0,0 +1,136 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package config
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	api "github.com/polarismesh/polaris/common/api/v1"
+	"github.com/polarismesh/polaris/common/model"
+	"github.com/polarismesh/polaris/common/utils"
+)
+
+// TestConfigFileGrayRelease 测试配置文件灰度发布、终止灰度以及灰度转全量
+func TestConfigFileGrayRelease(t *testing.T) {
+	testSuit, err := newConfigCenterTest(t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := testSuit.clearTestData(); err != nil {
+			t.Fatal(err)
+		}
+	}()
+
+	grayCtx := context.WithValue(testSuit.defaultCtx, utils.StringContext("client-ip"), "10.0.0.1")
+	normalCtx := context.WithValue(testSuit.defaultCtx, utils.StringContext("client-ip"), "10.0.0.2")
+	rule := &model.ConfigFileGrayRule{ClientIPs: []string{"10.0.0.1"}}
+	fileInfo := assembleDefaultClientConfigFile(0)[0]
+
+	// 等待扫描器加载灰度发布
+	waitGray := func(expect bool) {
+		for i := 0; i < 50; i++ {
+			gray := testSuit.testServer.grayCache.get(testNamespace, testGroup, testFile)
+			if (gray != nil) == expect {
+				return
+			}
+			time.Sleep(100 * time.Millisecond)
+		}
+		t.Fatalf("wait gray release timeout, expect exist %v", expect)
+	}
+
+	configFile := assembleConfigFile()
+	rsp := testSuit.testService.CreateConfigFile(testSuit.defaultCtx, configFile)
+	assert.Equal(t, api.ExecuteSuccess, rsp.Code.GetValue())
+
+	// 未全量发布过的配置不能灰度发布
+	rsp = testSuit.testService.PublishConfigFileGray(testSuit.defaultCtx, assembleConfigFileRelease(configFile), rule)
+	assert.Equal(t, api.BadRequest, rsp.Code.GetValue())
+
+	rsp = testSuit.testService.PublishConfigFile(testSuit.defaultCtx, assembleConfigFileRelease(configFile))
+	assert.Equal(t, api.ExecuteSuccess, rsp.Code.GetValue())
+	originContent := configFile.Content.GetValue()
+
+	grayContent := "k1=gray"
+	configFile.Content = utils.NewStringValue(grayContent)
+	rsp = testSuit.testService.UpdateConfigFile(testSuit.defaultCtx, configFile)
+	assert.Equal(t, api.ExecuteSuccess, rsp.Code.GetValue())
+
+	rsp = testSuit.testService.PublishConfigFileGray(testSuit.defaultCtx, assembleConfigFileRelease(configFile),
+		&model.ConfigFileGrayRule{})
+	assert.Equal(t, api.InvalidParameter, rsp.Code.GetValue())
+
+	rsp = testSuit.testService.PublishConfigFileGray(testSuit.defaultCtx, assembleConfigFileRelease(configFile), rule)
+	assert.Equal(t, api.ExecuteSuccess, rsp.Code.GetValue())
+	assert.Equal(t, uint64(2), rsp.ConfigFileRelease.Version.GetValue())
+	waitGray(true)
+
+	// 命中灰度的客户端获取灰度内容，其他客户端不受影响
+	clientRsp := testSuit.testService.GetConfigFileForClient(grayCtx, fileInfo)
+	assert.Equal(t, grayContent, clientRsp.ConfigFile.Content.GetValue())
+	assert.Equal(t, uint64(2), clientRsp.ConfigFile.Version.GetValue())
+	clientRsp = testSuit.testService.GetConfigFileForClient(normalCtx, fileInfo)
+	assert.Equal(t, originContent, clientRsp.ConfigFile.Content.GetValue())
+	assert.Equal(t, uint64(1), clientRsp.ConfigFile.Version.GetValue())
+
+	checkRsp := testSuit.testServer.doCheckClientConfigFile(grayCtx, assembleDefaultClientConfigFile(1),
+		compareByVersion)
+	assert.Equal(t, api.ExecuteSuccess, checkRsp.Code.GetValue())
+	assert.Equal(t, uint64(2), checkRsp.ConfigFile.Version.GetValue())
+	checkRsp = testSuit.testServer.doCheckClientConfigFile(normalCtx, assembleDefaultClientConfigFile(1),
+		compareByVersion)
+	assert.Equal(t, api.DataNoChange, checkRsp.Code.GetValue())
+
+	// 终止灰度，灰度客户端回退到正式发布的内容
+	rsp = testSuit.testService.AbortConfigFileGrayRelease(testSuit.defaultCtx, testNamespace, testGroup, testFile)
+	assert.Equal(t, api.ExecuteSuccess, rsp.Code.GetValue())
+	assert.Equal(t, uint64(3), rsp.ConfigFileRelease.Version.GetValue())
+	waitGray(false)
+
+	clientRsp = testSuit.testService.GetConfigFileForClient(grayCtx, assembleDefaultClientConfigFile(2)[0])
+	assert.Equal(t, originContent, clientRsp.ConfigFile.Content.GetValue())
+	assert.Equal(t, uint64(3), clientRsp.ConfigFile.Version.GetValue())
+
+	rsp = testSuit.testService.AbortConfigFileGrayRelease(testSuit.defaultCtx, testNamespace, testGroup, testFile)
+	assert.Equal(t, api.NotFoundResource, rsp.Code.GetValue())
+
+	// 再次灰度后转全量
+	rsp = testSuit.testService.PublishConfigFileGray(testSuit.defaultCtx, assembleConfigFileRelease(configFile), rule)
+	assert.Equal(t, api.ExecuteSuccess, rsp.Code.GetValue())
+	assert.Equal(t, uint64(4), rsp.ConfigFileRelease.Version.GetValue())
+	waitGray(true)
+
+	rsp = testSuit.testService.PromoteConfigFileGrayRelease(testSuit.defaultCtx, testNamespace, testGroup, testFile)
+	assert.Equal(t, api.ExecuteSuccess, rsp.Code.GetValue())
+	assert.Equal(t, uint64(4), rsp.ConfigFileRelease.Version.GetValue())
+	assert.Equal(t, grayContent, rsp.ConfigFileRelease.Content.GetValue())
+	waitGray(false)
+
+	clientRsp = testSuit.testService.GetConfigFileForClient(normalCtx, assembleDefaultClientConfigFile(3)[0])
+	assert.Equal(t, grayContent, clientRsp.ConfigFile.Content.GetValue())
+	assert.Equal(t, uint64(4), clientRsp.ConfigFile.Version.GetValue())
+
+	history := testSuit.testService.GetConfigFileLatestReleaseHistory(testSuit.defaultCtx, testNamespace,
+		testGroup, testFile)
+	assert.Equal(t, api.ExecuteSuccess, history.Code.GetValue())
+	assert.Equal(t, utils.ReleaseTypeGrayPromote, history.ConfigFileReleaseHistory.Type.GetValue())
+}
@@ -383,6 +383,44 @@ func (s *Server) UpdateConfigFileGroup(ctx context.Context,
 	return api.NewConfigFileGroupResponse(api.ExecuteSuccess, configFileGroup2Api(updatedGroup))
 }
 
+// UpdateConfigFileGroupSchema 更新配置文件组的 JSON Schema，schema 为空时表示不再校验
+func (s *Server) UpdateConfigFileGroupSchema(ctx context.Context, namespace, group, schema string) *api.ConfigResponse {
+	if err := utils2.CheckResourceName(utils.NewStringValue(namespace)); err != nil {
+		return api.NewConfigFileGroupResponse(api.InvalidNamespaceName, nil)
+	}
+	if err := utils2.CheckResourceName(utils.NewStringValue(group)); err != nil {
+		return api.NewConfigFileGroupResponse(api.InvalidConfigFileGroupName, nil)
+	}
+	if schema != "" {
+		if err := utils2.CheckJsonSchema(schema); err != nil {
+			return api.NewConfigFileResponseWithMessage(api.InvalidParameter, err.Error())
+		}
+	}
+
+	fileGroup, err := s.storage.GetConfigFileGroup(namespace, group)
+	if err != nil {
+		log.Error("[Config][Service] get config file group failed. ",
+			utils.ZapRequestIDByCtx(ctx),
+			zap.String("namespace", namespace),
+			zap.String("name", group),
+			zap.Error(err))
+		return api.NewConfigFileGroupResponse(api.StoreLayerException, nil)
+	}
+	if fileGroup == nil {
+		return api.NewConfigFileGroupResponse(api.NotFoundResource, nil)
+	}
+
+	if err := s.storage.UpdateConfigFileGroupSchema(namespace, group, schema, utils.ParseUserName(ctx)); err != nil {
+		log.Error("[Config][Service] update config file group schema failed. ",
+			utils.ZapRequestIDByCtx(ctx),
+			zap.String("namespace", namespace),
+			zap.String("name", group),
+			zap.Error(err))
+		return api.NewConfigFileGroupResponse(api.StoreLayerException, nil)
+	}
+	return api.NewConfigFileGroupResponse(api.ExecuteSuccess, configFileGroup2Api(fileGroup))
+}
+
 func checkConfigFileGroupParams(configFileGroup *api.ConfigFileGroup) *api.ConfigResponse {
 	if configFileGroup == nil {
 		return api.NewConfigFileGroupResponse(api.InvalidParameter, configFileGroup)
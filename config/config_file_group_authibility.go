@@ -107,3 +107,23 @@ func (s *serverAuthability) UpdateConfigFileGroup(ctx context.Context,
 
 	return s.targetServer.UpdateConfigFileGroup(ctx, configFileGroup)
 }
+
+// UpdateConfigFileGroupSchema 更新配置文件组的 JSON Schema
+func (s *serverAuthability) UpdateConfigFileGroupSchema(ctx context.Context,
+	namespace, group, schema string) *api.ConfigResponse {
+	configFileGroup := &api.ConfigFileGroup{
+		Namespace: utils.NewStringValue(namespace),
+		Name:      utils.NewStringValue(group),
+	}
+	authCtx := s.collectConfigGroupAuthContext(ctx, []*api.ConfigFileGroup{configFileGroup},
+		model.Modify, "UpdateConfigFileGroupSchema")
+
+	if _, err := s.checker.CheckConsolePermission(authCtx); err != nil {
+		return api.NewConfigFileResponseWithMessage(convertToErrCode(err), err.Error())
+	}
+
+	ctx = authCtx.GetRequestContext()
+	ctx = context.WithValue(ctx, utils.ContextAuthContextKey, authCtx)
+
+	return s.targetServer.UpdateConfigFileGroupSchema(ctx, namespace, group, schema)
+}
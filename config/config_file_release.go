@@ -19,6 +19,7 @@ package config
 
 import (
 	"context"
+	"fmt"
 
 	"go.uber.org/zap"
 
@@ -77,6 +78,10 @@ func (s *Server) PublishConfigFile(ctx context.Context, configFileRelease *api.C
 		return api.NewConfigFileResponse(api.NotFoundResource, nil)
 	}
 
+	if rsp := s.checkPublishConfigFileContent(ctx, toPublishFile); rsp != nil {
+		return rsp
+	}
+
 	md5 := utils2.CalMd5(toPublishFile.Content)
 
 	// 获取 configFileRelease 信息
@@ -94,6 +99,36 @@ func (s *Server) PublishConfigFile(ctx context.Context, configFileRelease *api.C
 		return api.NewConfigFileResponse(api.StoreLayerException, nil)
 	}
 
+	// 正式发布会覆盖生效中的灰度发布，版本号需要比灰度版本更新
+	grayRelease, err := s.storage.GetConfigFileGrayReleaseWithAllFlag(tx, namespace, group, fileName)
+	if err != nil {
+		log.Error("[Config][Service] get config file gray release error.",
+			utils.ZapRequestID(requestID),
+			zap.String("namespace", namespace),
+			zap.String("group", group),
+			zap.String("fileName", fileName),
+			zap.Error(err))
+
+		s.recordReleaseFail(ctx, transferConfigFileReleaseAPIModel2StoreModel(configFileRelease))
+
+		return api.NewConfigFileResponse(api.StoreLayerException, nil)
+	}
+	if grayRelease != nil && grayRelease.Flag == 0 {
+		if err := s.storage.DeleteConfigFileGrayRelease(tx, namespace, group, fileName,
+			configFileRelease.CreateBy.GetValue()); err != nil {
+			log.Error("[Config][Service] delete config file gray release error.",
+				utils.ZapRequestID(requestID),
+				zap.String("namespace", namespace),
+				zap.String("group", group),
+				zap.String("fileName", fileName),
+				zap.Error(err))
+
+			s.recordReleaseFail(ctx, transferConfigFileReleaseAPIModel2StoreModel(configFileRelease))
+
+			return api.NewConfigFileResponse(api.StoreLayerException, nil)
+		}
+	}
+
 	releaseName := configFileRelease.Name.GetValue()
 	if releaseName == "" {
 		if managedFileRelease == nil {
@@ -135,10 +170,15 @@ func (s *Server) PublishConfigFile(ctx context.Context, configFileRelease *api.C
 
 		s.recordReleaseHistory(ctx, createdFileRelease, utils.ReleaseTypeNormal, utils.ReleaseStatusSuccess)
 
-		return api.NewConfigFileReleaseResponse(api.ExecuteSuccess, configFileRelease2Api(createdFileRelease))
+		return api.NewConfigFileReleaseResponse(api.ExecuteSuccess,
+			s.decryptConfigFileRelease(ctx, configFileRelease2Api(createdFileRelease)))
 	}
 
 	// 更新发布
+	version := managedFileRelease.Version + 1
+	if grayRelease != nil && grayRelease.Version >= version {
+		version = grayRelease.Version + 1
+	}
 	fileRelease := &model.ConfigFileRelease{
 		Name:      releaseName,
 		Namespace: namespace,
@@ -147,7 +187,7 @@ func (s *Server) PublishConfigFile(ctx context.Context, configFileRelease *api.C
 		Content:   toPublishFile.Content,
 		Comment:   configFileRelease.Comment.GetValue(),
 		Md5:       md5,
-		Version:   managedFileRelease.Version + 1,
+		Version:   version,
 		ModifyBy:  configFileRelease.CreateBy.GetValue(),
 	}
 
@@ -167,7 +207,8 @@ func (s *Server) PublishConfigFile(ctx context.Context, configFileRelease *api.C
 
 	s.recordReleaseHistory(ctx, updatedFileRelease, utils.ReleaseTypeNormal, utils.ReleaseStatusSuccess)
 
-	return api.NewConfigFileReleaseResponse(api.ExecuteSuccess, configFileRelease2Api(updatedFileRelease))
+	return api.NewConfigFileReleaseResponse(api.ExecuteSuccess,
+		s.decryptConfigFileRelease(ctx, configFileRelease2Api(updatedFileRelease)))
 }
 
 // GetConfigFileRelease 获取配置文件发布内容
@@ -198,7 +239,8 @@ func (s *Server) GetConfigFileRelease(ctx context.Context, namespace, group, fil
 		return api.NewConfigFileResponse(api.StoreLayerException, nil)
 	}
 
-	return api.NewConfigFileReleaseResponse(api.ExecuteSuccess, configFileRelease2Api(fileRelease))
+	return api.NewConfigFileReleaseResponse(api.ExecuteSuccess,
+		s.decryptConfigFileRelease(ctx, configFileRelease2Api(fileRelease)))
 }
 
 // DeleteConfigFileRelease 删除配置文件发布，删除配置文件的时候，同步删除配置文件发布数据
@@ -217,15 +259,22 @@ func (s *Server) DeleteConfigFileRelease(ctx context.Context, namespace,
 		return api.NewConfigFileResponse(api.InvalidConfigFileGroupName, nil)
 	}
 
-	latestReleaseRsp := s.GetConfigFileRelease(ctx, namespace, group, fileName)
-	if latestReleaseRsp.Code.GetValue() != api.ExecuteSuccess {
-		return api.NewConfigFileResponse(latestReleaseRsp.Code.GetValue(), nil)
-	}
-
 	requestID, _ := ctx.Value(utils.StringContext("request-id")).(string)
 
+	// 直接读取存储层数据，避免加密配置以明文形式回写
+	fileRelease, err := s.storage.GetConfigFileRelease(s.getTx(ctx), namespace, group, fileName)
+	if err != nil {
+		log.Error("[Config][Service]get config file release error.",
+			utils.ZapRequestID(requestID),
+			zap.String("namespace", namespace),
+			zap.String("group", group),
+			zap.String("fileName", fileName),
+			zap.Error(err))
+		return api.NewConfigFileResponse(api.StoreLayerException, nil)
+	}
+
 	var releaseName string
-	latestRelease := latestReleaseRsp.ConfigFileRelease
+	latestRelease := configFileRelease2Api(fileRelease)
 	if latestRelease == nil {
 		// 从来没有发布过，无需删除
 		return api.NewConfigFileResponse(api.ExecuteSuccess, nil)
@@ -248,7 +297,20 @@ func (s *Server) DeleteConfigFileRelease(ctx context.Context, namespace,
 		return api.NewConfigFileResponse(api.StoreLayerException, nil)
 	}
 
-	err := s.storage.DeleteConfigFileRelease(s.getTx(ctx), namespace, group, fileName, deleteBy)
+	// 同时结束生效中的灰度发布，并保证删除后的版本号比灰度版本更新
+	grayRelease, err := s.storage.GetConfigFileGrayReleaseWithAllFlag(s.getTx(ctx), namespace, group, fileName)
+	if err == nil && grayRelease != nil && grayRelease.Flag == 0 {
+		err = s.storage.DeleteConfigFileGrayRelease(s.getTx(ctx), namespace, group, fileName, deleteBy)
+		if err == nil && grayRelease.Version >= latestRelease.Version.GetValue() {
+			releaseModel := transferConfigFileReleaseAPIModel2StoreModel(latestRelease)
+			releaseModel.Name = latestRelease.Name.GetValue()
+			releaseModel.Version = grayRelease.Version
+			_, err = s.storage.UpdateConfigFileRelease(s.getTx(ctx), releaseModel)
+		}
+	}
+	if err == nil {
+		err = s.storage.DeleteConfigFileRelease(s.getTx(ctx), namespace, group, fileName, deleteBy)
+	}
 
 	if err != nil {
 		log.Error("[Config][Service] delete config file release error.",
@@ -352,3 +414,103 @@ func configFileRelease2Api(release *model.ConfigFileRelease) *api.ConfigFileRele
 		ModifyTime: utils.NewStringValue(time.Time2String(release.ModifyTime)),
 	}
 }
+
+// RollbackConfigFile 回滚配置文件到指定的历史发布版本，同时将配置文件内容恢复为历史发布的内容
+func (s *Server) RollbackConfigFile(ctx context.Context, namespace, group, fileName string,
+	historyId uint64) *api.ConfigResponse {
+	if errRsp := checkReleaseFileParams(namespace, group, fileName); errRsp != nil {
+		return errRsp
+	}
+
+	requestID := utils.ParseRequestID(ctx)
+	userName := utils.ParseUserName(ctx)
+
+	history, err := s.storage.GetConfigFileReleaseHistory(historyId)
+	if err != nil {
+		logReleaseError(requestID, "get config file release history error.", namespace, group, fileName, err)
+		return api.NewConfigFileResponse(api.StoreLayerException, nil)
+	}
+	if history == nil || history.Namespace != namespace || history.Group != group || history.FileName != fileName {
+		return api.NewConfigFileResponse(api.NotFoundResource, nil)
+	}
+	// 只有成功的正式发布记录可以作为回滚的目标
+	if history.Status != utils.ReleaseStatusSuccess || (history.Type != utils.ReleaseTypeNormal &&
+		history.Type != utils.ReleaseTypeGrayPromote && history.Type != utils.ReleaseTypeRollback) {
+		return api.NewConfigFileResponseWithMessage(api.BadRequest,
+			"only success normal release history can be rollback")
+	}
+
+	tx, err := s.storage.StartTx()
+	if err != nil {
+		log.Error("[Config][Service] start tx error when rollback config file.",
+			utils.ZapRequestID(requestID), zap.Error(err))
+		return api.NewConfigFileResponse(api.StoreLayerException, nil)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	file, err := s.storage.GetConfigFile(tx, namespace, group, fileName)
+	if err != nil {
+		logReleaseError(requestID, "get config file error.", namespace, group, fileName, err)
+		return api.NewConfigFileResponse(api.StoreLayerException, nil)
+	}
+	if file == nil {
+		return api.NewConfigFileResponse(api.NotFoundResource, nil)
+	}
+
+	managedRelease, err := s.storage.GetConfigFileReleaseWithAllFlag(tx, namespace, group, fileName)
+	if err == nil && managedRelease == nil {
+		return api.NewConfigFileResponse(api.NotFoundResource, nil)
+	}
+	var gray *model.ConfigFileGrayRelease
+	if err == nil {
+		gray, err = s.storage.GetConfigFileGrayReleaseWithAllFlag(tx, namespace, group, fileName)
+	}
+	if err != nil {
+		logReleaseError(requestID, "get config file release error.", namespace, group, fileName, err)
+		return api.NewConfigFileResponse(api.StoreLayerException, nil)
+	}
+
+	// 回滚与正式发布一样覆盖生效中的灰度发布，版本号需要比灰度版本更新
+	version := managedRelease.Version + 1
+	if gray != nil && gray.Version >= version {
+		version = gray.Version + 1
+	}
+	rollbackRelease := &model.ConfigFileRelease{
+		Name:      utils2.GenReleaseName(managedRelease.Name, fileName),
+		Namespace: namespace,
+		Group:     group,
+		FileName:  fileName,
+		Content:   history.Content,
+		Comment:   fmt.Sprintf("rollback to release history %d", historyId),
+		Md5:       utils2.CalMd5(history.Content),
+		Version:   version,
+		ModifyBy:  userName,
+	}
+
+	file.Content = history.Content
+	if history.Format != "" {
+		file.Format = history.Format
+	}
+	file.ModifyBy = userName
+
+	var updated *model.ConfigFileRelease
+	if _, err = s.storage.UpdateConfigFile(tx, file); err == nil {
+		updated, err = s.storage.UpdateConfigFileRelease(tx, rollbackRelease)
+	}
+	if err == nil && gray != nil && gray.Flag == 0 {
+		err = s.storage.DeleteConfigFileGrayRelease(tx, namespace, group, fileName, userName)
+	}
+	if err == nil {
+		err = tx.Commit()
+	}
+	if err != nil {
+		logReleaseError(requestID, "rollback config file error.", namespace, group, fileName, err)
+		s.recordReleaseHistory(ctx, rollbackRelease, utils.ReleaseTypeRollback, utils.ReleaseStatusFail)
+		return api.NewConfigFileResponse(api.StoreLayerException, nil)
+	}
+
+	s.recordReleaseHistory(ctx, updated, utils.ReleaseTypeRollback, utils.ReleaseStatusSuccess)
+
+	return api.NewConfigFileReleaseResponse(api.ExecuteSuccess,
+		s.decryptConfigFileRelease(ctx, configFileRelease2Api(updated)))
+}
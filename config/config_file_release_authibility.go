@@ -70,3 +70,77 @@ func (s *serverAuthability) DeleteConfigFileRelease(ctx context.Context, namespa
 
 	return s.targetServer.DeleteConfigFileRelease(ctx, namespace, group, fileName, deleteBy)
 }
+
+// PublishConfigFileGray 灰度发布配置文件
+func (s *serverAuthability) PublishConfigFileGray(ctx context.Context,
+	configFileRelease *api.ConfigFileRelease, rule *model.ConfigFileGrayRule) *api.ConfigResponse {
+
+	authCtx := s.collectConfigFileReleaseAuthContext(ctx,
+		[]*api.ConfigFileRelease{configFileRelease}, model.Create, "PublishConfigFileGray")
+
+	if _, err := s.checker.CheckConsolePermission(authCtx); err != nil {
+		return api.NewConfigFileResponseWithMessage(convertToErrCode(err), err.Error())
+	}
+
+	ctx = authCtx.GetRequestContext()
+	ctx = context.WithValue(ctx, utils.ContextAuthContextKey, authCtx)
+
+	return s.targetServer.PublishConfigFileGray(ctx, configFileRelease, rule)
+}
+
+// PromoteConfigFileGrayRelease 灰度转全量
+func (s *serverAuthability) PromoteConfigFileGrayRelease(ctx context.Context, namespace,
+	group, fileName string) *api.ConfigResponse {
+
+	authCtx := s.collectFileReleaseModifyAuthContext(ctx, namespace, group, fileName, "PromoteConfigFileGrayRelease")
+	if _, err := s.checker.CheckConsolePermission(authCtx); err != nil {
+		return api.NewConfigFileResponseWithMessage(convertToErrCode(err), err.Error())
+	}
+
+	ctx = authCtx.GetRequestContext()
+	ctx = context.WithValue(ctx, utils.ContextAuthContextKey, authCtx)
+
+	return s.targetServer.PromoteConfigFileGrayRelease(ctx, namespace, group, fileName)
+}
+
+// AbortConfigFileGrayRelease 终止灰度
+func (s *serverAuthability) AbortConfigFileGrayRelease(ctx context.Context, namespace,
+	group, fileName string) *api.ConfigResponse {
+
+	authCtx := s.collectFileReleaseModifyAuthContext(ctx, namespace, group, fileName, "AbortConfigFileGrayRelease")
+	if _, err := s.checker.CheckConsolePermission(authCtx); err != nil {
+		return api.NewConfigFileResponseWithMessage(convertToErrCode(err), err.Error())
+	}
+
+	ctx = authCtx.GetRequestContext()
+	ctx = context.WithValue(ctx, utils.ContextAuthContextKey, authCtx)
+
+	return s.targetServer.AbortConfigFileGrayRelease(ctx, namespace, group, fileName)
+}
+
+// RollbackConfigFile 回滚配置文件到指定的历史发布版本
+func (s *serverAuthability) RollbackConfigFile(ctx context.Context, namespace, group, fileName string,
+	historyId uint64) *api.ConfigResponse {
+
+	authCtx := s.collectFileReleaseModifyAuthContext(ctx, namespace, group, fileName, "RollbackConfigFile")
+	if _, err := s.checker.CheckConsolePermission(authCtx); err != nil {
+		return api.NewConfigFileResponseWithMessage(convertToErrCode(err), err.Error())
+	}
+
+	ctx = authCtx.GetRequestContext()
+	ctx = context.WithValue(ctx, utils.ContextAuthContextKey, authCtx)
+
+	return s.targetServer.RollbackConfigFile(ctx, namespace, group, fileName, historyId)
+}
+
+func (s *serverAuthability) collectFileReleaseModifyAuthContext(ctx context.Context, namespace, group,
+	fileName, methodName string) *model.AcquireContext {
+	req := []*api.ConfigFileRelease{
+		{
+			Namespace: utils.NewStringValue(namespace),
+			Group:     utils.NewStringValue(group),
+			FileName:  utils.NewStringValue(fileName),
+		},
+	}
+	return s.collectConfigFileReleaseAuthContext(ctx, req, model.Modify, methodName)
+}
@@ -100,7 +100,7 @@ func (s *Server) GetConfigFileReleaseHistory(ctx context.Context, namespace, gro
 
 	var apiReleaseHistory []*api.ConfigFileReleaseHistory
 	for _, history := range releaseHistories {
-		historyAPIModel := transferReleaseHistoryStoreModel2APIModel(history)
+		historyAPIModel := s.decryptReleaseHistory(ctx, transferReleaseHistoryStoreModel2APIModel(history))
 		apiReleaseHistory = append(apiReleaseHistory, historyAPIModel)
 	}
 
@@ -137,7 +137,7 @@ func (s *Server) GetConfigFileLatestReleaseHistory(ctx context.Context, namespac
 	}
 
 	return api.NewConfigFileReleaseHistoryResponse(api.ExecuteSuccess,
-		transferReleaseHistoryStoreModel2APIModel(history))
+		s.decryptReleaseHistory(ctx, transferReleaseHistoryStoreModel2APIModel(history)))
 }
 
 func transferReleaseHistoryStoreModel2APIModel(
@@ -20,6 +20,7 @@ package config
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
@@ -317,3 +318,139 @@ func TestPublishConfigFile(t *testing.T) {
 	assert.Equal(t, 2, len(rsp9.ConfigFileReleaseHistories))
 
 }
+
+// TestConfigFileContentValidation 测试按照文件格式以及配置分组 JSON Schema 校验配置内容
+func TestConfigFileContentValidation(t *testing.T) {
+	testSuit, err := newConfigCenterTest(t)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() {
+		if err := testSuit.clearTestData(); err != nil {
+			t.Fatal(err)
+		}
+	}()
+
+	configFile := assembleConfigFile()
+	configFile.Format = utils.NewStringValue(utils.FileFormatJson)
+	configFile.Content = utils.NewStringValue("{\n  \"port\": 80,\n  \"host\" \"a\"\n}")
+
+	t.Run("格式错误", func(t *testing.T) {
+		rsp := testSuit.testService.CreateConfigFile(testSuit.defaultCtx, configFile)
+		assert.Equal(t, api.InvalidConfigFileContent, rsp.Code.GetValue())
+		assert.Contains(t, rsp.Info.GetValue(), "line 3, column 10")
+	})
+
+	configFile.Content = utils.NewStringValue(`{"port": 80}`)
+	rsp := testSuit.testService.CreateConfigFile(testSuit.defaultCtx, configFile)
+	assert.Equal(t, api.ExecuteSuccess, rsp.Code.GetValue())
+
+	t.Run("配置分组Schema", func(t *testing.T) {
+		rsp := testSuit.testService.UpdateConfigFileGroupSchema(testSuit.defaultCtx, testNamespace, testGroup, "{")
+		assert.Equal(t, api.InvalidParameter, rsp.Code.GetValue())
+
+		schema := `{"type": "object", "required": ["port"], "properties": {"port": {"type": "integer"}}}`
+		rsp = testSuit.testService.UpdateConfigFileGroupSchema(testSuit.defaultCtx, testNamespace, testGroup, schema)
+		assert.Equal(t, api.ExecuteSuccess, rsp.Code.GetValue())
+
+		configFile.Content = utils.NewStringValue(`{"port": "80"}`)
+		rsp = testSuit.testService.UpdateConfigFile(testSuit.defaultCtx, configFile)
+		assert.Equal(t, api.InvalidConfigFileSchema, rsp.Code.GetValue())
+		assert.Contains(t, rsp.Info.GetValue(), "$.port")
+
+		configFile.Content = utils.NewStringValue(`{"port": 8080}`)
+		rsp = testSuit.testService.UpdateConfigFile(testSuit.defaultCtx, configFile)
+		assert.Equal(t, api.ExecuteSuccess, rsp.Code.GetValue())
+
+		rsp = testSuit.testService.PublishConfigFile(testSuit.defaultCtx, assembleConfigFileRelease(configFile))
+		assert.Equal(t, api.ExecuteSuccess, rsp.Code.GetValue())
+	})
+
+	t.Run("发布前校验", func(t *testing.T) {
+		// 绕过接口直接写入不合法的内容，发布时需要拦截
+		file, err := testSuit.storage.GetConfigFile(nil, testNamespace, testGroup, testFile)
+		assert.NoError(t, err)
+		file.Content = `{"port": 8080`
+		_, err = testSuit.storage.UpdateConfigFile(nil, file)
+		assert.NoError(t, err)
+
+		rsp := testSuit.testService.PublishConfigFile(testSuit.defaultCtx, assembleConfigFileRelease(configFile))
+		assert.Equal(t, api.InvalidConfigFileContent, rsp.Code.GetValue())
+	})
+}
+
+// TestRollbackConfigFile 测试回滚配置文件到历史发布版本
+func TestRollbackConfigFile(t *testing.T) {
+	testSuit, err := newConfigCenterTest(t)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() {
+		if err := testSuit.clearTestData(); err != nil {
+			t.Fatal(err)
+		}
+	}()
+
+	configFile := assembleConfigFile()
+	rsp := testSuit.testService.CreateConfigFile(testSuit.defaultCtx, configFile)
+	assert.Equal(t, api.ExecuteSuccess, rsp.Code.GetValue())
+	rsp = testSuit.testService.PublishConfigFile(testSuit.defaultCtx, assembleConfigFileRelease(configFile))
+	assert.Equal(t, api.ExecuteSuccess, rsp.Code.GetValue())
+	firstContent := configFile.Content.GetValue()
+
+	historyRsp := testSuit.testService.GetConfigFileLatestReleaseHistory(testSuit.defaultCtx,
+		testNamespace, testGroup, testFile)
+	assert.Equal(t, api.ExecuteSuccess, historyRsp.Code.GetValue())
+	historyId := historyRsp.ConfigFileReleaseHistory.Id.GetValue()
+
+	configFile.Content = utils.NewStringValue("k1=v3")
+	rsp = testSuit.testService.UpdateConfigFile(testSuit.defaultCtx, configFile)
+	assert.Equal(t, api.ExecuteSuccess, rsp.Code.GetValue())
+	rsp = testSuit.testService.PublishConfigFile(testSuit.defaultCtx, assembleConfigFileRelease(configFile))
+	assert.Equal(t, api.ExecuteSuccess, rsp.Code.GetValue())
+
+	t.Run("回滚不存在的历史记录", func(t *testing.T) {
+		rsp := testSuit.testService.RollbackConfigFile(testSuit.defaultCtx, testNamespace, testGroup, testFile,
+			historyId+100)
+		assert.Equal(t, api.NotFoundResource, rsp.Code.GetValue())
+
+		rsp = testSuit.testService.RollbackConfigFile(testSuit.defaultCtx, testNamespace, testGroup, "otherFile",
+			historyId)
+		assert.Equal(t, api.NotFoundResource, rsp.Code.GetValue())
+	})
+
+	t.Run("回滚到历史版本", func(t *testing.T) {
+		rsp := testSuit.testService.RollbackConfigFile(testSuit.defaultCtx, testNamespace, testGroup, testFile,
+			historyId)
+		assert.Equal(t, api.ExecuteSuccess, rsp.Code.GetValue())
+		assert.Equal(t, firstContent, rsp.ConfigFileRelease.Content.GetValue())
+		assert.Equal(t, uint64(3), rsp.ConfigFileRelease.Version.GetValue())
+
+		rsp = testSuit.testService.GetConfigFileRichInfo(testSuit.defaultCtx, testNamespace, testGroup, testFile)
+		assert.Equal(t, api.ExecuteSuccess, rsp.Code.GetValue())
+		assert.Equal(t, firstContent, rsp.ConfigFile.Content.GetValue())
+		assert.Equal(t, utils.ReleaseStatusSuccess, rsp.ConfigFile.Status.GetValue())
+
+		historyRsp := testSuit.testService.GetConfigFileLatestReleaseHistory(testSuit.defaultCtx,
+			testNamespace, testGroup, testFile)
+		assert.Equal(t, utils.ReleaseTypeRollback, historyRsp.ConfigFileReleaseHistory.Type.GetValue())
+	})
+
+	t.Run("客户端获取回滚后的配置", func(t *testing.T) {
+		// 等待发布事件扫描器刷新缓存
+		fileInfo := assembleDefaultClientConfigFile(2)[0]
+		var rsp *api.ConfigClientResponse
+		for i := 0; i < 50; i++ {
+			rsp = testSuit.testService.GetConfigFileForClient(testSuit.defaultCtx, fileInfo)
+			if rsp.ConfigFile.Version.GetValue() == 3 {
+				break
+			}
+			time.Sleep(100 * time.Millisecond)
+		}
+		assert.Equal(t, api.ExecuteSuccess, rsp.Code.GetValue())
+		assert.Equal(t, uint64(3), rsp.ConfigFile.Version.GetValue())
+		assert.Equal(t, firstContent, rsp.ConfigFile.Content.GetValue())
+	})
+}
@@ -23,6 +23,7 @@ import (
 	"time"
 
 	api "github.com/polarismesh/polaris/common/api/v1"
+	"github.com/polarismesh/polaris/common/model"
 	"github.com/polarismesh/polaris/common/utils"
 )
 
@@ -63,7 +64,8 @@ func NewConfigConnManager(ctx context.Context, watchCenter *watchCenter) *connMa
 	return cm
 }
 
-func (c *connManager) AddConn(clientId string, files []*api.ClientConfigFileInfo) chan *api.ConfigClientResponse {
+func (c *connManager) AddConn(clientId string, client *model.ConfigClient,
+	files []*api.ClientConfigFileInfo) chan *api.ConfigClientResponse {
 
 	finishChan := make(chan *api.ConfigClientResponse)
 
@@ -73,7 +75,7 @@ func (c *connManager) AddConn(clientId string, files []*api.ClientConfigFileInfo
 		watchConfigFiles: files,
 	})
 
-	c.watchCenter.AddWatcher(clientId, files, func(clientId string, rsp *api.ConfigClientResponse) bool {
+	c.watchCenter.AddClientWatcher(clientId, client, files, func(clientId string, rsp *api.ConfigClientResponse) bool {
 		connObj, ok := cm.conns.Load(clientId)
 		if ok {
 			conn := connObj.(*connection)
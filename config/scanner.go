@@ -40,18 +40,23 @@ type releaseMessageScanner struct {
 
 	lastScannerTime time.Time
 
+	lastGrayScannerTime time.Time
+
 	scanInterval time.Duration
 
 	fileCache cache.FileCache
 
+	grayCache *grayReleaseCache
+
 	eventCenter *Center
 }
 
 func initReleaseMessageScanner(ctx context.Context, storage store.Store, fileCache cache.FileCache,
-	eventCenter *Center, scanInterval time.Duration) error {
+	grayCache *grayReleaseCache, eventCenter *Center, scanInterval time.Duration) error {
 	scanner := &releaseMessageScanner{
 		storage:      storage,
 		fileCache:    fileCache,
+		grayCache:    grayCache,
 		eventCenter:  eventCenter,
 		scanInterval: scanInterval,
 	}
@@ -67,6 +72,15 @@ func initReleaseMessageScanner(ctx context.Context, storage store.Store, fileCac
 }
 
 func (s *releaseMessageScanner) scanAtFirstTime() error {
+	// 灰度发布只缓存在内存中，启动时需要全量加载
+	s.lastGrayScannerTime = time.Unix(0, 0)
+	grayReleases, err := s.storage.FindConfigFileGrayReleaseByModifyTimeAfter(s.lastGrayScannerTime)
+	if err != nil {
+		log.Error("[Config][Scanner] scan config file gray release error.", zap.Error(err))
+		return err
+	}
+	s.handlerGrayReleases(true, grayReleases)
+
 	t := time.Now().Add(FirstScanTimeOffset)
 	s.lastScannerTime = t
 
@@ -100,6 +114,15 @@ func (s *releaseMessageScanner) startScanTask(ctx context.Context) {
 		case <-ctx.Done():
 			return
 		case <-t.C:
+			// 先处理灰度发布，保证正式发布的通知能够感知到最新的灰度状态
+			grayReleases, err := s.storage.FindConfigFileGrayReleaseByModifyTimeAfter(
+				s.lastGrayScannerTime.Add(DefaultScanTimeOffset))
+			if err != nil {
+				log.Error("[Config][Scanner] scan config file gray release error.", zap.Error(err))
+				continue
+			}
+			s.handlerGrayReleases(false, grayReleases)
+
 			// 为了避免丢失消息，扫描发布消息的时间点往前拨10s。因为处理消息是幂等的，所以即使捞出重复消息也能够正常处理
 			scanIdx := s.lastScannerTime.Add(DefaultScanTimeOffset)
 			releases, err := s.storage.FindConfigFileReleaseByModifyTimeAfter(scanIdx)
@@ -167,6 +190,41 @@ func (s *releaseMessageScanner) handlerReleases(firstTime bool, releases []*mode
 	return nil
 }
 
+func (s *releaseMessageScanner) handlerGrayReleases(firstTime bool, grayReleases []*model.ConfigFileGrayRelease) {
+	for _, release := range grayReleases {
+		if release.ModifyTime.After(s.lastGrayScannerTime) {
+			s.lastGrayScannerTime = release.ModifyTime
+		}
+
+		cached := s.grayCache.get(release.Namespace, release.Group, release.FileName)
+		if release.Flag == 1 {
+			// 灰度结束时正式发布同时会推高版本号，由正式发布的事件通知客户端
+			if cached != nil && cached.Version <= release.Version {
+				s.grayCache.remove(release.Namespace, release.Group, release.FileName)
+			}
+			continue
+		}
+		if cached != nil && cached.Version >= release.Version {
+			continue
+		}
+
+		item, err := s.grayCache.set(release)
+		if err != nil {
+			log.Error("[Config][Scanner] parse config file gray rule error.",
+				zap.String("namespace", release.Namespace), zap.String("group", release.Group),
+				zap.String("fileName", release.FileName), zap.Error(err))
+			continue
+		}
+
+		if !firstTime && !release.ModifyTime.Before(time.Now().Add(MessageExpireTime)) {
+			s.eventCenter.handleEvent(Event{
+				EventType: eventTypePublishGrayConfig,
+				Message:   item,
+			})
+		}
+	}
+}
+
 func isExpireMessage(release *model.ConfigFileRelease) bool {
 	return release.ModifyTime.Before(time.Now().Add(MessageExpireTime))
 }
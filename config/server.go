@@ -29,6 +29,7 @@ import (
 	api "github.com/polarismesh/polaris/common/api/v1"
 	"github.com/polarismesh/polaris/common/model"
 	"github.com/polarismesh/polaris/namespace"
+	"github.com/polarismesh/polaris/plugin"
 	"github.com/polarismesh/polaris/store"
 )
 
@@ -36,6 +37,7 @@ var _ ConfigCenterServer = (*Server)(nil)
 
 const (
 	eventTypePublishConfigFile  = "PublishConfigFile"
+	eventTypePublishGrayConfig  = "PublishGrayConfigFile"
 	defaultExpireTimeAfterWrite = 60 * 60 // expire after 1 hour
 )
 
@@ -54,10 +56,12 @@ type Config struct {
 type Server struct {
 	storage           store.Store
 	fileCache         cache.FileCache
+	grayCache         *grayReleaseCache
 	caches            *cache.CacheManager
 	watchCenter       *watchCenter
 	connManager       *connManager
 	namespaceOperator namespace.NamespaceOperateServer
+	crypto            plugin.Crypto
 	initialized       bool
 
 	hooks []ResourceHook
@@ -93,17 +97,20 @@ func (s *Server) initialize(ctx context.Context, config Config, ss store.Store,
 	s.storage = ss
 	s.namespaceOperator = namespaceOperator
 	s.fileCache = cacheMgn.ConfigFile()
+	s.grayCache = newGrayReleaseCache()
+	s.crypto = plugin.GetCrypto()
 
 	// 初始化事件中心
 	eventCenter := NewEventCenter()
-	s.watchCenter = NewWatchCenter(eventCenter)
+	s.watchCenter = NewWatchCenter(eventCenter, s.grayCache)
 
 	// 初始化连接管理器
 	connMng := NewConfigConnManager(ctx, s.watchCenter)
 	s.connManager = connMng
 
 	// 初始化发布事件扫描器
-	if err := initReleaseMessageScanner(ctx, ss, s.fileCache, eventCenter, time.Second); err != nil {
+	if err := initReleaseMessageScanner(ctx, ss, s.fileCache, s.grayCache, eventCenter,
+		time.Second); err != nil {
 		log.Error("[Config][Server] init release message scanner error. ", zap.Error(err))
 		return errors.New("init config module error")
 	}
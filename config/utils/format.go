@@ -0,0 +1,262 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v2"
+
+	"github.com/polarismesh/polaris/common/utils"
+)
+
+var (
+	regYamlErrorLine = regexp.MustCompile(`line (\d+)`)
+)
+
+// ContentFormatError 配置内容与声明的格式不匹配，Line、Column 从 1 开始，为 0 时表示无法定位
+type ContentFormatError struct {
+	Format string
+	Line   int
+	Column int
+	Msg    string
+}
+
+// Error 返回包含行列信息的错误描述
+func (e *ContentFormatError) Error() string {
+	if e.Column > 0 {
+		return fmt.Sprintf("invalid %s content at line %d, column %d: %s", e.Format, e.Line, e.Column, e.Msg)
+	}
+	if e.Line > 0 {
+		return fmt.Sprintf("invalid %s content at line %d: %s", e.Format, e.Line, e.Msg)
+	}
+	return fmt.Sprintf("invalid %s content: %s", e.Format, e.Msg)
+}
+
+// CheckContentFormat 按照配置文件声明的格式校验配置内容，text、html 不做校验
+func CheckContentFormat(format, content string) error {
+	if strings.TrimSpace(content) == "" {
+		return nil
+	}
+	switch format {
+	case utils.FileFormatJson:
+		return checkJsonContent(content)
+	case utils.FileFormatYaml:
+		return checkYamlContent(content)
+	case utils.FileFormatXml:
+		return checkXmlContent(content)
+	case utils.FileFormatProperties:
+		_, err := parseProperties(content)
+		return err
+	case utils.FileFormatToml:
+		return checkTomlContent(content)
+	default:
+		return nil
+	}
+}
+
+func checkJsonContent(content string) error {
+	var v interface{}
+	err := json.Unmarshal([]byte(content), &v)
+	if err == nil {
+		return nil
+	}
+	formatErr := &ContentFormatError{Format: utils.FileFormatJson, Msg: err.Error()}
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		// Offset 为读取到出错字符之后的偏移
+		formatErr.Line, formatErr.Column = offsetToPosition(content, int(syntaxErr.Offset)-1)
+	}
+	return formatErr
+}
+
+func checkYamlContent(content string) error {
+	var v interface{}
+	err := yaml.Unmarshal([]byte(content), &v)
+	if err == nil {
+		return nil
+	}
+	msg := strings.TrimPrefix(err.Error(), "yaml: ")
+	formatErr := &ContentFormatError{Format: utils.FileFormatYaml, Msg: msg}
+	if items := regYamlErrorLine.FindStringSubmatch(msg); len(items) == 2 {
+		formatErr.Line, _ = strconv.Atoi(items[1])
+	}
+	return formatErr
+}
+
+func checkXmlContent(content string) error {
+	decoder := xml.NewDecoder(strings.NewReader(content))
+	hasRoot := false
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			formatErr := &ContentFormatError{Format: utils.FileFormatXml, Msg: err.Error()}
+			var syntaxErr *xml.SyntaxError
+			if errors.As(err, &syntaxErr) {
+				formatErr.Msg = syntaxErr.Msg
+			}
+			formatErr.Line, formatErr.Column = offsetToPosition(content, int(decoder.InputOffset()))
+			return formatErr
+		}
+		if _, ok := token.(xml.StartElement); ok {
+			hasRoot = true
+		}
+	}
+	if !hasRoot {
+		return &ContentFormatError{Format: utils.FileFormatXml, Msg: "root element not found"}
+	}
+	return nil
+}
+
+func checkTomlContent(content string) error {
+	v := map[string]interface{}{}
+	_, err := toml.Decode(content, &v)
+	if err == nil {
+		return nil
+	}
+	formatErr := &ContentFormatError{Format: utils.FileFormatToml, Msg: err.Error()}
+	var parseErr toml.ParseError
+	if errors.As(err, &parseErr) {
+		formatErr.Msg = parseErr.Message
+		formatErr.Line, formatErr.Column = offsetToPosition(content, parseErr.Position.Start)
+	}
+	return formatErr
+}
+
+// parseProperties 解析 properties 格式内容，支持续行以及 unicode 转义
+func parseProperties(content string) (map[string]interface{}, error) {
+	result := map[string]interface{}{}
+	lines := strings.Split(content, "\n")
+	for i := 0; i < len(lines); i++ {
+		lineNo := i + 1
+		line := strings.TrimLeft(strings.TrimRight(lines[i], "\r"), " \t\f")
+		if line == "" || line[0] == '#' || line[0] == '!' {
+			continue
+		}
+		// 以奇数个反斜杠结尾表示续行
+		for endsWithContinuation(line) && i+1 < len(lines) {
+			i++
+			line = line[:len(line)-1] + strings.TrimLeft(strings.TrimRight(lines[i], "\r"), " \t\f")
+		}
+		key, value, column := splitPropertiesLine(line)
+		if key == "" {
+			return nil, &ContentFormatError{Format: utils.FileFormatProperties, Line: lineNo, Column: column,
+				Msg: "property key is empty"}
+		}
+		unquotedKey, err := unescapeProperties(key)
+		if err != nil {
+			return nil, &ContentFormatError{Format: utils.FileFormatProperties, Line: lineNo, Msg: err.Error()}
+		}
+		unquotedValue, err := unescapeProperties(value)
+		if err != nil {
+			return nil, &ContentFormatError{Format: utils.FileFormatProperties, Line: lineNo, Msg: err.Error()}
+		}
+		result[unquotedKey] = unquotedValue
+	}
+	return result, nil
+}
+
+func endsWithContinuation(line string) bool {
+	count := 0
+	for i := len(line) - 1; i >= 0 && line[i] == '\\'; i-- {
+		count++
+	}
+	return count%2 == 1
+}
+
+// splitPropertiesLine 拆分键值，返回键、值以及分隔符所在的列
+func splitPropertiesLine(line string) (string, string, int) {
+	for i := 0; i < len(line); i++ {
+		switch line[i] {
+		case '\\':
+			i++
+		case '=', ':', ' ', '\t', '\f':
+			value := strings.TrimLeft(line[i+1:], " \t\f")
+			if line[i] == ' ' || line[i] == '\t' || line[i] == '\f' {
+				if len(value) > 0 && (value[0] == '=' || value[0] == ':') {
+					value = strings.TrimLeft(value[1:], " \t\f")
+				}
+			}
+			return line[:i], value, i + 1
+		}
+	}
+	return line, "", 1
+}
+
+func unescapeProperties(s string) (string, error) {
+	if !strings.Contains(s, "\\") {
+		return s, nil
+	}
+	var buf bytes.Buffer
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i+1 >= len(s) {
+			buf.WriteByte(s[i])
+			continue
+		}
+		i++
+		switch s[i] {
+		case 't':
+			buf.WriteByte('\t')
+		case 'n':
+			buf.WriteByte('\n')
+		case 'r':
+			buf.WriteByte('\r')
+		case 'f':
+			buf.WriteByte('\f')
+		case 'u':
+			if i+5 > len(s) {
+				return "", fmt.Errorf("malformed \\uxxxx encoding: %s", s[i-1:])
+			}
+			r, err := strconv.ParseUint(s[i+1:i+5], 16, 32)
+			if err != nil {
+				return "", fmt.Errorf("malformed \\uxxxx encoding: %s", s[i-1:i+5])
+			}
+			buf.WriteRune(rune(r))
+			i += 4
+		default:
+			buf.WriteByte(s[i])
+		}
+	}
+	return buf.String(), nil
+}
+
+// offsetToPosition 将字节偏移转换为从 1 开始的行列号
+func offsetToPosition(content string, offset int) (int, int) {
+	if offset < 0 {
+		offset = 0
+	}
+	if offset > len(content) {
+		offset = len(content)
+	}
+	before := content[:offset]
+	line := strings.Count(before, "\n") + 1
+	column := len([]rune(before[strings.LastIndex(before, "\n")+1:])) + 1
+	return line, column
+}
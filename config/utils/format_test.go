@@ -0,0 +1,107 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/polarismesh/polaris/common/utils"
+)
+
+func TestCheckContentFormat(t *testing.T) {
+	assert.NoError(t, CheckContentFormat(utils.FileFormatJson, `{"a": 1}`))
+	assert.NoError(t, CheckContentFormat(utils.FileFormatYaml, "a: 1\nb:\n  - c\n"))
+	assert.NoError(t, CheckContentFormat(utils.FileFormatXml, "<a><b>1</b></a>"))
+	assert.NoError(t, CheckContentFormat(utils.FileFormatProperties, "a=1\nb : 2\n# comment\nc \\\n  3\nd\\u0041=4"))
+	assert.NoError(t, CheckContentFormat(utils.FileFormatToml, "a = 1\n[b]\nc = \"d\"\n"))
+	assert.NoError(t, CheckContentFormat(utils.FileFormatText, "{"))
+	assert.NoError(t, CheckContentFormat(utils.FileFormatJson, ""))
+
+	err := CheckContentFormat(utils.FileFormatJson, "{\n  \"a\": 1,\n  \"b\" 2\n}")
+	formatErr, ok := err.(*ContentFormatError)
+	assert.True(t, ok)
+	assert.Equal(t, 3, formatErr.Line)
+	assert.Equal(t, 7, formatErr.Column)
+
+	err = CheckContentFormat(utils.FileFormatYaml, "a: 1\nb: [1, 2\nc: 3\n")
+	formatErr, ok = err.(*ContentFormatError)
+	assert.True(t, ok)
+	assert.True(t, formatErr.Line > 0)
+
+	err = CheckContentFormat(utils.FileFormatXml, "<a>\n  <b>1</c>\n</a>")
+	formatErr, ok = err.(*ContentFormatError)
+	assert.True(t, ok)
+	assert.Equal(t, 2, formatErr.Line)
+
+	err = CheckContentFormat(utils.FileFormatProperties, "a=1\n=2")
+	formatErr, ok = err.(*ContentFormatError)
+	assert.True(t, ok)
+	assert.Equal(t, 2, formatErr.Line)
+	assert.Equal(t, 1, formatErr.Column)
+
+	err = CheckContentFormat(utils.FileFormatProperties, "a=\\u00g1")
+	assert.Error(t, err)
+
+	err = CheckContentFormat(utils.FileFormatToml, "a = 1\nb = \n")
+	formatErr, ok = err.(*ContentFormatError)
+	assert.True(t, ok)
+	assert.Equal(t, 2, formatErr.Line)
+}
+
+func TestCheckContentSchema(t *testing.T) {
+	schema := `{
+		"type": "object",
+		"required": ["port"],
+		"properties": {
+			"port": {"type": "integer", "minimum": 1, "maximum": 65535},
+			"mode": {"enum": ["dev", "prod"]},
+			"hosts": {"type": "array", "items": {"type": "string", "pattern": "^[a-z.]+$"}}
+		},
+		"additionalProperties": false
+	}`
+	assert.NoError(t, CheckJsonSchema(schema))
+	assert.Error(t, CheckJsonSchema(`{"pattern": "("}`))
+	assert.Error(t, CheckJsonSchema(`[]`))
+
+	assert.NoError(t, CheckContentSchema(utils.FileFormatJson, `{"port": 80, "hosts": ["a.b"]}`, schema))
+	assert.NoError(t, CheckContentSchema(utils.FileFormatYaml, "port: 80\nmode: dev\n", schema))
+	assert.NoError(t, CheckContentSchema(utils.FileFormatToml, "port = 80\n", schema))
+	assert.NoError(t, CheckContentSchema(utils.FileFormatXml, "<a/>", schema))
+
+	err := CheckContentSchema(utils.FileFormatJson, `{"mode": "dev"}`, schema)
+	schemaErr, ok := err.(*ContentSchemaError)
+	assert.True(t, ok)
+	assert.Equal(t, "$", schemaErr.Path)
+
+	err = CheckContentSchema(utils.FileFormatYaml, "port: 80\nhosts:\n  - A\n", schema)
+	schemaErr, ok = err.(*ContentSchemaError)
+	assert.True(t, ok)
+	assert.Equal(t, "$.hosts[0]", schemaErr.Path)
+
+	err = CheckContentSchema(utils.FileFormatJson, `{"port": 80, "other": 1}`, schema)
+	schemaErr, ok = err.(*ContentSchemaError)
+	assert.True(t, ok)
+	assert.Equal(t, "$.other", schemaErr.Path)
+
+	err = CheckContentSchema(utils.FileFormatProperties, "port=80", schema)
+	schemaErr, ok = err.(*ContentSchemaError)
+	assert.True(t, ok)
+	assert.Equal(t, "$.port", schemaErr.Path)
+}
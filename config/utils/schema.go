@@ -0,0 +1,315 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package utils
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"time"
+	"unicode/utf8"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v2"
+
+	"github.com/polarismesh/polaris/common/utils"
+)
+
+// ContentSchemaError 配置内容不符合配置分组的 JSON Schema，Path 为出错字段的路径
+type ContentSchemaError struct {
+	Path string
+	Msg  string
+}
+
+// Error 返回包含字段路径的错误描述
+func (e *ContentSchemaError) Error() string {
+	return fmt.Sprintf("content does not match schema at %s: %s", e.Path, e.Msg)
+}
+
+// CheckJsonSchema 校验 JSON Schema 本身是否合法，支持 type、enum、const、properties、required、
+// additionalProperties、items、minItems、maxItems、minLength、maxLength、pattern、minimum、maximum、
+// exclusiveMinimum、exclusiveMaximum 关键字
+func CheckJsonSchema(schema string) error {
+	_, err := parseJsonSchema(schema)
+	return err
+}
+
+// CheckContentSchema 按照 JSON Schema 校验配置内容，只对 json、yaml、toml、properties 格式生效
+func CheckContentSchema(format, content, schema string) error {
+	if schema == "" {
+		return nil
+	}
+	root, err := parseJsonSchema(schema)
+	if err != nil {
+		return err
+	}
+
+	var value interface{}
+	switch format {
+	case utils.FileFormatJson:
+		err = json.Unmarshal([]byte(content), &value)
+	case utils.FileFormatYaml:
+		err = yaml.Unmarshal([]byte(content), &value)
+	case utils.FileFormatToml:
+		m := map[string]interface{}{}
+		_, err = toml.Decode(content, &m)
+		value = m
+	case utils.FileFormatProperties:
+		value, err = parseProperties(content)
+	default:
+		return nil
+	}
+	if err != nil {
+		return CheckContentFormat(format, content)
+	}
+	return validateSchema(normalizeValue(value), root, "$")
+}
+
+func parseJsonSchema(schema string) (map[string]interface{}, error) {
+	root := map[string]interface{}{}
+	if err := json.Unmarshal([]byte(schema), &root); err != nil {
+		return nil, fmt.Errorf("invalid json schema: %w", err)
+	}
+	if err := checkSchemaNode(root); err != nil {
+		return nil, fmt.Errorf("invalid json schema: %w", err)
+	}
+	return root, nil
+}
+
+func checkSchemaNode(node map[string]interface{}) error {
+	if pattern, ok := node["pattern"].(string); ok {
+		if _, err := regexp.Compile(pattern); err != nil {
+			return err
+		}
+	}
+	if properties, ok := node["properties"]; ok {
+		props, ok := properties.(map[string]interface{})
+		if !ok {
+			return errors.New("properties must be an object")
+		}
+		for _, sub := range props {
+			subNode, ok := sub.(map[string]interface{})
+			if !ok {
+				return errors.New("property schema must be an object")
+			}
+			if err := checkSchemaNode(subNode); err != nil {
+				return err
+			}
+		}
+	}
+	for _, key := range []string{"items", "additionalProperties"} {
+		if sub, ok := node[key].(map[string]interface{}); ok {
+			if err := checkSchemaNode(sub); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// normalizeValue 将不同格式解析出的结果统一为 JSON 的数据类型
+func normalizeValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[interface{}]interface{}:
+		ret := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			ret[fmt.Sprint(key)] = normalizeValue(item)
+		}
+		return ret
+	case map[string]interface{}:
+		ret := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			ret[key] = normalizeValue(item)
+		}
+		return ret
+	case []map[string]interface{}:
+		ret := make([]interface{}, 0, len(v))
+		for _, item := range v {
+			ret = append(ret, normalizeValue(item))
+		}
+		return ret
+	case []interface{}:
+		ret := make([]interface{}, 0, len(v))
+		for _, item := range v {
+			ret = append(ret, normalizeValue(item))
+		}
+		return ret
+	case int:
+		return float64(v)
+	case int64:
+		return float64(v)
+	case uint64:
+		return float64(v)
+	case float32:
+		return float64(v)
+	case time.Time:
+		return v.Format(time.RFC3339)
+	default:
+		return v
+	}
+}
+
+func validateSchema(value interface{}, schema map[string]interface{}, path string) error {
+	if expect, ok := schema["type"]; ok && !matchSchemaType(value, expect) {
+		return &ContentSchemaError{Path: path, Msg: fmt.Sprintf("expect type %v", expect)}
+	}
+	if enum, ok := schema["enum"].([]interface{}); ok {
+		matched := false
+		for _, item := range enum {
+			if reflect.DeepEqual(item, value) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return &ContentSchemaError{Path: path, Msg: fmt.Sprintf("value must be one of %v", enum)}
+		}
+	}
+	if expect, ok := schema["const"]; ok && !reflect.DeepEqual(expect, value) {
+		return &ContentSchemaError{Path: path, Msg: fmt.Sprintf("value must be %v", expect)}
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return validateObject(v, schema, path)
+	case []interface{}:
+		if err := checkRange(float64(len(v)), schema, "minItems", "maxItems", path, "items count"); err != nil {
+			return err
+		}
+		if items, ok := schema["items"].(map[string]interface{}); ok {
+			for i, item := range v {
+				if err := validateSchema(item, items, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+					return err
+				}
+			}
+		}
+	case string:
+		length := float64(utf8.RuneCountInString(v))
+		if err := checkRange(length, schema, "minLength", "maxLength", path, "length"); err != nil {
+			return err
+		}
+		if pattern, ok := schema["pattern"].(string); ok && !regexp.MustCompile(pattern).MatchString(v) {
+			return &ContentSchemaError{Path: path, Msg: fmt.Sprintf("value does not match pattern %s", pattern)}
+		}
+	case float64:
+		if err := checkRange(v, schema, "minimum", "maximum", path, "value"); err != nil {
+			return err
+		}
+		if limit, ok := schema["exclusiveMinimum"].(float64); ok && v <= limit {
+			return &ContentSchemaError{Path: path, Msg: fmt.Sprintf("value must be greater than %v", limit)}
+		}
+		if limit, ok := schema["exclusiveMaximum"].(float64); ok && v >= limit {
+			return &ContentSchemaError{Path: path, Msg: fmt.Sprintf("value must be less than %v", limit)}
+		}
+	}
+	return nil
+}
+
+func validateObject(value map[string]interface{}, schema map[string]interface{}, path string) error {
+	if required, ok := schema["required"].([]interface{}); ok {
+		for _, item := range required {
+			key, _ := item.(string)
+			if _, exist := value[key]; !exist {
+				return &ContentSchemaError{Path: path, Msg: fmt.Sprintf("missing required property %s", key)}
+			}
+		}
+	}
+	properties, _ := schema["properties"].(map[string]interface{})
+	keys := make([]string, 0, len(value))
+	for key := range value {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		subPath := path + "." + key
+		if sub, ok := properties[key].(map[string]interface{}); ok {
+			if err := validateSchema(value[key], sub, subPath); err != nil {
+				return err
+			}
+			continue
+		}
+		switch additional := schema["additionalProperties"].(type) {
+		case bool:
+			if !additional {
+				return &ContentSchemaError{Path: subPath, Msg: "additional property is not allowed"}
+			}
+		case map[string]interface{}:
+			if err := validateSchema(value[key], additional, subPath); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func checkRange(value float64, schema map[string]interface{}, minKey, maxKey, path, name string) error {
+	if limit, ok := schema[minKey].(float64); ok && value < limit {
+		return &ContentSchemaError{Path: path, Msg: fmt.Sprintf("%s must be >= %v", name, limit)}
+	}
+	if limit, ok := schema[maxKey].(float64); ok && value > limit {
+		return &ContentSchemaError{Path: path, Msg: fmt.Sprintf("%s must be <= %v", name, limit)}
+	}
+	return nil
+}
+
+func matchSchemaType(value interface{}, expect interface{}) bool {
+	switch t := expect.(type) {
+	case string:
+		return matchSingleType(value, t)
+	case []interface{}:
+		for _, item := range t {
+			if name, ok := item.(string); ok && matchSingleType(value, name) {
+				return true
+			}
+		}
+		return false
+	default:
+		return true
+	}
+}
+
+func matchSingleType(value interface{}, expect string) bool {
+	switch expect {
+	case "object":
+		_, ok := value.(map[string]interface{})
+		return ok
+	case "array":
+		_, ok := value.([]interface{})
+		return ok
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "number":
+		_, ok := value.(float64)
+		return ok
+	case "integer":
+		v, ok := value.(float64)
+		return ok && v == math.Trunc(v)
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	case "null":
+		return value == nil
+	default:
+		return false
+	}
+}
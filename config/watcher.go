@@ -37,27 +37,46 @@ type FileReleaseCallback func(clientId string, rsp *api.ConfigClientResponse) bo
 type watchContext struct {
 	fileReleaseCb FileReleaseCallback
 	ClientVersion uint64
+	// client 为空时表示无法识别客户端，灰度发布会通知到该订阅者
+	client *model.ConfigClient
+}
+
+// releaseMessage 发布消息，gray 不为空时表示灰度发布
+type releaseMessage struct {
+	release *model.ConfigFileRelease
+	gray    *grayRelease
 }
 
 // watchCenter 处理客户端订阅配置请求，监听配置文件发布事件通知客户端
 type watchCenter struct {
 	eventCenter         *Center
+	grayCache           *grayReleaseCache
 	configFileWatchers  *sync.Map // fileId -> clientId -> watchContext
 	lock                *sync.Mutex
-	releaseMessageQueue chan *model.ConfigFileRelease
+	releaseMessageQueue chan *releaseMessage
 }
 
 // NewWatchCenter 创建一个客户端监听配置发布的处理中心
-func NewWatchCenter(eventCenter *Center) *watchCenter {
+func NewWatchCenter(eventCenter *Center, grayCache *grayReleaseCache) *watchCenter {
 	wc := &watchCenter{
 		eventCenter:         eventCenter,
+		grayCache:           grayCache,
 		configFileWatchers:  new(sync.Map),
 		lock:                new(sync.Mutex),
-		releaseMessageQueue: make(chan *model.ConfigFileRelease, QueueSize),
+		releaseMessageQueue: make(chan *releaseMessage, QueueSize),
 	}
 
 	eventCenter.WatchEvent(eventTypePublishConfigFile, func(event Event) bool {
-		wc.releaseMessageQueue <- event.Message.(*model.ConfigFileRelease)
+		wc.releaseMessageQueue <- &releaseMessage{release: event.Message.(*model.ConfigFileRelease)}
+		return true
+	})
+
+	eventCenter.WatchEvent(eventTypePublishGrayConfig, func(event Event) bool {
+		gray := event.Message.(*grayRelease)
+		wc.releaseMessageQueue <- &releaseMessage{
+			release: grayRelease2Release(gray.ConfigFileGrayRelease),
+			gray:    gray,
+		}
 		return true
 	})
 
@@ -69,6 +88,12 @@ func NewWatchCenter(eventCenter *Center) *watchCenter {
 // AddWatcher 新增订阅者
 func (wc *watchCenter) AddWatcher(clientId string, watchConfigFiles []*api.ClientConfigFileInfo,
 	fileReleaseCb FileReleaseCallback) {
+	wc.AddClientWatcher(clientId, nil, watchConfigFiles, fileReleaseCb)
+}
+
+// AddClientWatcher 新增订阅者，并记录客户端信息用于灰度发布的匹配
+func (wc *watchCenter) AddClientWatcher(clientId string, client *model.ConfigClient,
+	watchConfigFiles []*api.ClientConfigFileInfo, fileReleaseCb FileReleaseCallback) {
 	if len(watchConfigFiles) == 0 {
 		return
 	}
@@ -88,6 +113,7 @@ func (wc *watchCenter) AddWatcher(clientId string, watchConfigFiles []*api.Clien
 				newWatchers.Store(clientId, &watchContext{
 					fileReleaseCb: fileReleaseCb,
 					ClientVersion: file.Version.GetValue(),
+					client:        client,
 				})
 				wc.configFileWatchers.Store(watchFileId, newWatchers)
 			}
@@ -100,6 +126,7 @@ func (wc *watchCenter) AddWatcher(clientId string, watchConfigFiles []*api.Clien
 		watcherMap.Store(clientId, &watchContext{
 			fileReleaseCb: fileReleaseCb,
 			ClientVersion: file.Version.GetValue(),
+			client:        client,
 		})
 	}
 }
@@ -136,7 +163,8 @@ func (wc *watchCenter) handleMessage() {
 	}()
 }
 
-func (wc *watchCenter) notifyToWatchers(publishConfigFile *model.ConfigFileRelease) {
+func (wc *watchCenter) notifyToWatchers(message *releaseMessage) {
+	publishConfigFile := message.release
 	watchFileId := utils.GenFileId(publishConfigFile.Namespace, publishConfigFile.Group, publishConfigFile.FileName)
 
 	log.Info("[Config][Watcher] received config file publish message.", zap.String("file", watchFileId))
@@ -149,22 +177,48 @@ func (wc *watchCenter) notifyToWatchers(publishConfigFile *model.ConfigFileRelea
 	response := utils2.GenConfigFileResponse(publishConfigFile.Namespace, publishConfigFile.Group,
 		publishConfigFile.FileName, "", publishConfigFile.Md5, publishConfigFile.Version)
 
+	// 正式发布时，命中生效中灰度的客户端仍然以灰度版本为准
+	activeGray := message.gray
+	if activeGray == nil && wc.grayCache != nil {
+		activeGray = wc.grayCache.get(publishConfigFile.Namespace, publishConfigFile.Group,
+			publishConfigFile.FileName)
+		if activeGray != nil && activeGray.Version <= publishConfigFile.Version {
+			activeGray = nil
+		}
+	}
+	var grayResponse *api.ConfigClientResponse
+	if activeGray != nil {
+		grayResponse = utils2.GenConfigFileResponse(activeGray.Namespace, activeGray.Group,
+			activeGray.FileName, "", activeGray.Md5, activeGray.Version)
+	}
+
 	watcherMap := watchers.(*sync.Map)
 	watcherMap.Range(func(clientId, watchCtx interface{}) bool {
 
 		c := watchCtx.(*watchContext)
-		if c.ClientVersion < publishConfigFile.Version {
+		version, rsp := publishConfigFile.Version, response
+		if activeGray != nil {
+			hit := activeGray.rule.Match(c.client)
+			if message.gray != nil && c.client != nil && !hit {
+				// 灰度发布只通知命中规则的客户端
+				return true
+			}
+			if hit {
+				version, rsp = activeGray.Version, grayResponse
+			}
+		}
+		if c.ClientVersion < version {
 			log.Info("[Config][Watcher] notify to client.",
 				zap.String("file", watchFileId),
 				zap.String("clientId", clientId.(string)),
-				zap.Uint64("version", publishConfigFile.Version))
-			c.fileReleaseCb(clientId.(string), response)
+				zap.Uint64("version", version))
+			c.fileReleaseCb(clientId.(string), rsp)
 		} else {
 			log.Info("[Config][Watcher] notify to client ignore.",
 				zap.String("file", watchFileId),
 				zap.String("clientId", clientId.(string)),
 				zap.Uint64("client-version", c.ClientVersion),
-				zap.Uint64("version", publishConfigFile.Version))
+				zap.Uint64("version", version))
 		}
 		return true
 	})
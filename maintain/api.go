@@ -19,9 +19,13 @@ package maintain
 
 import (
 	"context"
+	"io"
 
 	api "github.com/polarismesh/polaris/common/api/v1"
 	"github.com/polarismesh/polaris/common/connlimit"
+	"github.com/polarismesh/polaris/common/model"
+	"github.com/polarismesh/polaris/service/healthcheck"
+	"github.com/polarismesh/polaris/store/transfer"
 )
 
 type ConnReq struct {
@@ -69,9 +73,19 @@ type MaintainOperateServer interface {
 	// GetLastHeartbeat Get last heartbeat
 	GetLastHeartbeat(ctx context.Context, req *api.Instance) *api.Response
 
+	// GetInstanceHealthHistory Get instance health status change history recorded by all nodes
+	GetInstanceHealthHistory(ctx context.Context,
+		req *healthcheck.HealthHistoryQuery) ([]*model.HealthChangeRecord, error)
+
 	// GetLogOutputLevel Get log output level
 	GetLogOutputLevel(ctx context.Context) (map[string]string, error)
 
 	// SetLogOutputLevel Set log output level by scope
 	SetLogOutputLevel(ctx context.Context, scope string, level string) error
+
+	// ExportBackup Export all resources from a consistent snapshot and stream the archive to w
+	ExportBackup(ctx context.Context, format BackupFormat, w io.Writer) error
+
+	// RestoreBackup Restore a backup archive into the store
+	RestoreBackup(ctx context.Context, req *RestoreBackupReq) (*transfer.Report, error)
 }
@@ -0,0 +1,352 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package maintain
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"reflect"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/polarismesh/polaris/store"
+	"github.com/polarismesh/polaris/store/transfer"
+)
+
+// BackupArchiveVersion 备份文件的格式版本
+const BackupArchiveVersion = "v1"
+
+const (
+	// MaxBackupArchiveSize 还原时允许上传的备份文件的最大字节数
+	MaxBackupArchiveSize = 512 << 20
+	// backupManifestName 备份文件中描述版本等信息的文件名，必须是归档中的第一个文件
+	backupManifestName = "manifest"
+	// backupEntryRecords 备份文件中单个数据文件包含的最大记录数
+	backupEntryRecords = 1000
+)
+
+// maxBackupDataSize 还原时备份文件解压后的最大字节数，防止解压出超大的数据
+var maxBackupDataSize int64 = 2 << 30
+
+// BackupFormat 备份文件中数据的编码格式
+type BackupFormat string
+
+const (
+	// BackupFormatJSON json 格式
+	BackupFormatJSON BackupFormat = "json"
+	// BackupFormatYAML yaml 格式
+	BackupFormatYAML BackupFormat = "yaml"
+)
+
+// ParseBackupFormat 解析备份文件的编码格式，为空时默认为 json
+func ParseBackupFormat(value string) (BackupFormat, error) {
+	switch format := BackupFormat(value); format {
+	case "":
+		return BackupFormatJSON, nil
+	case BackupFormatJSON, BackupFormatYAML:
+		return format, nil
+	default:
+		return "", fmt.Errorf("invalid backup format %s, must be one of json, yaml", value)
+	}
+}
+
+// BackupArchive 全量数据的备份文件
+// 备份文件为 tar.gz 归档，第一个文件为 manifest.<format>，之后每类资源按照 <section>/<seq>.<format> 分块存放
+type BackupArchive struct {
+	Version    string             `json:"version"`
+	CreateTime time.Time          `json:"create_time"`
+	Data       *transfer.Snapshot `json:"data"`
+}
+
+// backupManifest 备份文件的描述信息
+type backupManifest struct {
+	Version    string    `json:"version"`
+	CreateTime time.Time `json:"create_time"`
+}
+
+// RestoreBackupReq 还原备份数据的请求
+type RestoreBackupReq struct {
+	// Conflict 数据已经存在且不一致时的处理策略，为空时跳过
+	Conflict transfer.ConflictPolicy
+	// DryRun 只统计将要写入的记录，不实际写入
+	DryRun bool
+	// Archive tar.gz 格式的备份文件，还原时流式解析
+	Archive io.Reader
+}
+
+// EncodeBackupArchive 按照指定格式将备份文件流式写出
+func EncodeBackupArchive(w io.Writer, archive *BackupArchive, format BackupFormat) error {
+	gw := gzip.NewWriter(w)
+	tw := tar.NewWriter(gw)
+	manifest := &backupManifest{Version: archive.Version, CreateTime: archive.CreateTime}
+	if err := writeBackupEntry(tw, backupManifestName, manifest, format, archive.CreateTime); err != nil {
+		return err
+	}
+	data := reflect.ValueOf(archive.Data).Elem()
+	for i := 0; i < data.NumField(); i++ {
+		section := backupSectionName(data.Type().Field(i))
+		records := data.Field(i)
+		for start, seq := 0, 0; start < records.Len(); start, seq = start+backupEntryRecords, seq+1 {
+			end := start + backupEntryRecords
+			if end > records.Len() {
+				end = records.Len()
+			}
+			name := fmt.Sprintf("%s/%06d", section, seq)
+			chunk := records.Slice(start, end).Interface()
+			if err := writeBackupEntry(tw, name, chunk, format, archive.CreateTime); err != nil {
+				return err
+			}
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gw.Close()
+}
+
+// writeBackupEntry 编码单个数据块并写入归档，数据块大小有上限，编码后再写入以得到文件长度
+func writeBackupEntry(tw *tar.Writer, name string, value interface{}, format BackupFormat, mtime time.Time) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	if format == BackupFormatYAML {
+		var o interface{}
+		if err := json.Unmarshal(data, &o); err != nil {
+			return err
+		}
+		if data, err = yaml.Marshal(o); err != nil {
+			return err
+		}
+	}
+	if err := tw.WriteHeader(&tar.Header{
+		Typeflag: tar.TypeReg,
+		Name:     name + "." + string(format),
+		Mode:     0644,
+		Size:     int64(len(data)),
+		ModTime:  mtime,
+	}); err != nil {
+		return err
+	}
+	_, err = tw.Write(data)
+	return err
+}
+
+// DecodeBackupArchive 流式解析 tar.gz 格式的备份文件，数据文件的格式根据文件扩展名判断
+func DecodeBackupArchive(r io.Reader) (*BackupArchive, error) {
+	gr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("invalid backup archive: %w", err)
+	}
+	defer gr.Close()
+
+	archive := &BackupArchive{Data: &transfer.Snapshot{}}
+	sections := make(map[string]reflect.Value)
+	data := reflect.ValueOf(archive.Data).Elem()
+	for i := 0; i < data.NumField(); i++ {
+		sections[backupSectionName(data.Type().Field(i))] = data.Field(i)
+	}
+
+	var manifest *backupManifest
+	tr := tar.NewReader(&backupDataReader{r: gr, remain: maxBackupDataSize})
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid backup archive: %w", err)
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		ext := path.Ext(hdr.Name)
+		if ext == "" {
+			return nil, fmt.Errorf("backup entry %s has no format extension", hdr.Name)
+		}
+		format, err := ParseBackupFormat(strings.TrimPrefix(ext, "."))
+		if err != nil {
+			return nil, fmt.Errorf("backup entry %s: %w", hdr.Name, err)
+		}
+		name := strings.TrimSuffix(hdr.Name, ext)
+		if name == backupManifestName {
+			manifest = &backupManifest{}
+			if err := decodeBackupEntry(tr, format, manifest); err != nil {
+				return nil, fmt.Errorf("backup entry %s: %w", hdr.Name, err)
+			}
+			if manifest.Version != BackupArchiveVersion {
+				return nil, fmt.Errorf("unsupported backup archive version %s", manifest.Version)
+			}
+			continue
+		}
+		if manifest == nil {
+			return nil, errors.New("backup archive must start with manifest")
+		}
+		field, ok := sections[path.Dir(name)]
+		if !ok {
+			return nil, fmt.Errorf("unknown backup entry %s", hdr.Name)
+		}
+		records := reflect.New(field.Type())
+		if err := decodeBackupEntry(tr, format, records.Interface()); err != nil {
+			return nil, fmt.Errorf("backup entry %s: %w", hdr.Name, err)
+		}
+		field.Set(reflect.AppendSlice(field, records.Elem()))
+	}
+	if manifest == nil {
+		return nil, errors.New("backup archive has no manifest")
+	}
+	archive.Version = manifest.Version
+	archive.CreateTime = manifest.CreateTime
+	return archive, nil
+}
+
+// decodeBackupEntry 解析单个数据文件，yaml 先转换为 json 再解析，与导出时的编码保持一致
+func decodeBackupEntry(r io.Reader, format BackupFormat, value interface{}) error {
+	if format != BackupFormatYAML {
+		return json.NewDecoder(r).Decode(value)
+	}
+	var o interface{}
+	if err := yaml.NewDecoder(r).Decode(&o); err != nil {
+		return err
+	}
+	data, err := json.Marshal(convertYAMLValue(o))
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, value)
+}
+
+// backupSectionName 资源在备份文件中的目录名，与 transfer.Snapshot 的 json 字段名一致
+func backupSectionName(field reflect.StructField) string {
+	name := strings.Split(field.Tag.Get("json"), ",")[0]
+	if name == "" {
+		return field.Name
+	}
+	return name
+}
+
+// backupDataReader 限制备份文件解压后的总字节数
+type backupDataReader struct {
+	r      io.Reader
+	remain int64
+}
+
+// Read 超过限制时返回错误，恰好读完时仍然可以正常返回 io.EOF
+func (r *backupDataReader) Read(p []byte) (int, error) {
+	if r.remain <= 0 {
+		var probe [1]byte
+		if n, err := r.r.Read(probe[:]); n == 0 && err != nil {
+			return 0, err
+		}
+		return 0, fmt.Errorf("backup data exceeds %d bytes", maxBackupDataSize)
+	}
+	if int64(len(p)) > r.remain {
+		p = p[:r.remain]
+	}
+	n, err := r.r.Read(p)
+	r.remain -= int64(n)
+	return n, err
+}
+
+// convertYAMLValue yaml 解析出的 map 的 key 为 interface{}，需要转换为 string 才能编码为 json
+func convertYAMLValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[interface{}]interface{}:
+		o := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			o[fmt.Sprint(key)] = convertYAMLValue(item)
+		}
+		return o
+	case []interface{}:
+		for i := range v {
+			v[i] = convertYAMLValue(v[i])
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+// ExportBackup 在存储的一致视图上导出全量数据，导出完成后立即释放视图，再将备份文件流式写出
+func (s *Server) ExportBackup(_ context.Context, format BackupFormat, w io.Writer) error {
+	snapshotStore, ok := s.storage.(store.SnapshotStore)
+	if !ok {
+		return fmt.Errorf("store %s does not support consistent snapshot", s.storage.Name())
+	}
+	s.backupMu.Lock()
+	defer s.backupMu.Unlock()
+
+	start := time.Now()
+	view, release, err := snapshotStore.Snapshot()
+	if err != nil {
+		log.Errorf("[Maintain][Backup] create store snapshot fail: %v", err)
+		return err
+	}
+	snapshot, err := transfer.Export(view)
+	release()
+	if err != nil {
+		log.Errorf("[Maintain][Backup] export fail: %v", err)
+		return err
+	}
+	log.Infof("[Maintain][Backup] export finish, cost %v", time.Since(start))
+
+	if err := EncodeBackupArchive(w, &BackupArchive{
+		Version:    BackupArchiveVersion,
+		CreateTime: start,
+		Data:       snapshot,
+	}, format); err != nil {
+		log.Errorf("[Maintain][Backup] write backup archive fail: %v", err)
+		return err
+	}
+	return nil
+}
+
+// RestoreBackup 将备份数据还原到当前集群的存储中，写入的数据由各节点的缓存增量加载
+// 冲突策略为 fail 且存在冲突时返回 transfer.ErrConflict 以及冲突的统计结果
+func (s *Server) RestoreBackup(_ context.Context, req *RestoreBackupReq) (*transfer.Report, error) {
+	if req.Archive == nil {
+		return nil, errors.New("missing backup archive")
+	}
+	archive, err := DecodeBackupArchive(req.Archive)
+	if err != nil {
+		return nil, err
+	}
+	s.backupMu.Lock()
+	defer s.backupMu.Unlock()
+
+	start := time.Now()
+	report, err := transfer.Import(s.storage, archive.Data, &transfer.ImportOptions{
+		DryRun:   req.DryRun,
+		Conflict: req.Conflict,
+	})
+	if err != nil {
+		log.Errorf("[Maintain][Backup] restore fail: %v", err)
+		return report, err
+	}
+	log.Infof("[Maintain][Backup] restore finish, dry run %v, conflict policy %s, errors %d, cost %v",
+		req.DryRun, req.Conflict, len(report.Errors), time.Since(start))
+	return report, nil
+}
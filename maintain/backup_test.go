@@ -0,0 +1,182 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package maintain
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/golang/protobuf/ptypes/wrappers"
+	"github.com/stretchr/testify/assert"
+
+	api "github.com/polarismesh/polaris/common/api/v1"
+	"github.com/polarismesh/polaris/common/model"
+	"github.com/polarismesh/polaris/store"
+	"github.com/polarismesh/polaris/store/boltdb"
+	"github.com/polarismesh/polaris/store/transfer"
+)
+
+func newTestServer(t *testing.T, name string) *Server {
+	s := boltdb.NewStore()
+	err := s.Initialize(&store.Config{
+		Name: boltdb.STORENAME,
+		// 初始化数据使用固定的 token，备份与恢复的存储初始化数据保持一致
+		Option: map[string]interface{}{"path": filepath.Join(t.TempDir(), name), "fixedInitToken": true},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		_ = s.Destroy()
+	})
+	return &Server{storage: s}
+}
+
+func Test_BackupRestore(t *testing.T) {
+	src := newTestServer(t, "src.bolt")
+	assert.NoError(t, src.storage.AddNamespace(&model.Namespace{Name: "backup", Token: "ns-token"}))
+	assert.NoError(t, src.storage.AddService(&model.Service{ID: "svc-1", Name: "svc", Namespace: "backup",
+		Token: "svc-token", Revision: "svc-revision", Meta: map[string]string{"env": "prod"}}))
+	assert.NoError(t, src.storage.AddInstance(&model.Instance{
+		ServiceID: "svc-1",
+		Proto: &api.Instance{
+			Id:        &wrappers.StringValue{Value: "ins-1"},
+			Service:   &wrappers.StringValue{Value: "svc"},
+			Namespace: &wrappers.StringValue{Value: "backup"},
+			Host:      &wrappers.StringValue{Value: "127.0.0.1"},
+			Port:      &wrappers.UInt32Value{Value: 8080},
+			Weight:    &wrappers.UInt32Value{Value: 100},
+			Revision:  &wrappers.StringValue{Value: "ins-revision"},
+		},
+	}))
+	_, err := src.storage.CreateConfigFileGroup(&model.ConfigFileGroup{Name: "group", Namespace: "backup"})
+	assert.NoError(t, err)
+	_, err = src.storage.CreateConfigFile(nil, &model.ConfigFile{Name: "app.yaml", Namespace: "backup",
+		Group: "group", Content: "a: 1\nb: [x, y]\n", Format: "yaml"})
+	assert.NoError(t, err)
+
+	for _, format := range []BackupFormat{BackupFormatJSON, BackupFormatYAML} {
+		t.Run(string(format), func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			assert.NoError(t, src.ExportBackup(context.Background(), format, buf))
+			data := buf.Bytes()
+			archive, err := DecodeBackupArchive(bytes.NewReader(data))
+			assert.NoError(t, err)
+			assert.Equal(t, BackupArchiveVersion, archive.Version)
+
+			dst := newTestServer(t, "dst.bolt")
+			report, err := dst.RestoreBackup(context.Background(), &RestoreBackupReq{
+				Conflict: transfer.ConflictFail,
+				Archive:  bytes.NewReader(data),
+			})
+			assert.NoError(t, err)
+			assert.False(t, report.HasError(), report.Errors)
+
+			verifyReport, err := transfer.Verify(dst.storage, archive.Data)
+			assert.NoError(t, err)
+			assert.False(t, verifyReport.HasError(), verifyReport.Errors)
+
+			ins, err := dst.storage.GetInstance("ins-1")
+			assert.NoError(t, err)
+			assert.Equal(t, uint32(8080), ins.Port())
+			assert.Equal(t, uint32(100), ins.Weight())
+		})
+	}
+}
+
+func Test_EncodeBackupArchiveChunks(t *testing.T) {
+	snapshot := &transfer.Snapshot{}
+	for i := 0; i < backupEntryRecords+1; i++ {
+		snapshot.Namespaces = append(snapshot.Namespaces, &model.Namespace{Name: fmt.Sprintf("ns-%d", i)})
+	}
+	buf := &bytes.Buffer{}
+	assert.NoError(t, EncodeBackupArchive(buf, &BackupArchive{Version: BackupArchiveVersion,
+		Data: snapshot}, BackupFormatYAML))
+
+	gr, err := gzip.NewReader(bytes.NewReader(buf.Bytes()))
+	assert.NoError(t, err)
+	tr := tar.NewReader(gr)
+	var names []string
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		assert.NoError(t, err)
+		names = append(names, hdr.Name)
+	}
+	assert.Equal(t, []string{"manifest.yaml", "namespaces/000000.yaml", "namespaces/000001.yaml"}, names)
+
+	archive, err := DecodeBackupArchive(bytes.NewReader(buf.Bytes()))
+	assert.NoError(t, err)
+	assert.Equal(t, snapshot.Namespaces, archive.Data.Namespaces)
+}
+
+func Test_DecodeBackupArchive(t *testing.T) {
+	newArchive := func(entries map[string]string, names ...string) io.Reader {
+		buf := &bytes.Buffer{}
+		gw := gzip.NewWriter(buf)
+		tw := tar.NewWriter(gw)
+		for _, name := range names {
+			assert.NoError(t, tw.WriteHeader(&tar.Header{Typeflag: tar.TypeReg, Name: name, Mode: 0644,
+				Size: int64(len(entries[name]))}))
+			_, err := tw.Write([]byte(entries[name]))
+			assert.NoError(t, err)
+		}
+		assert.NoError(t, tw.Close())
+		assert.NoError(t, gw.Close())
+		return buf
+	}
+
+	_, err := DecodeBackupArchive(strings.NewReader(`{"version":"v1"}`))
+	assert.Error(t, err)
+	_, err = DecodeBackupArchive(newArchive(map[string]string{"manifest.json": `{"version":"v0"}`},
+		"manifest.json"))
+	assert.Error(t, err)
+	_, err = DecodeBackupArchive(newArchive(map[string]string{"namespaces/000000.json": `[]`},
+		"namespaces/000000.json"))
+	assert.Error(t, err)
+	_, err = DecodeBackupArchive(newArchive(map[string]string{"manifest.yaml": "version: v1\n",
+		"unknown/000000.yaml": "[]"}, "manifest.yaml", "unknown/000000.yaml"))
+	assert.Error(t, err)
+	_, err = ParseBackupFormat("xml")
+	assert.Error(t, err)
+
+	archive, err := DecodeBackupArchive(newArchive(map[string]string{"manifest.yaml": "version: v1\n",
+		"namespaces/000000.yaml": "- name: a\n", "namespaces/000001.json": `[{"name":"b"}]`},
+		"manifest.yaml", "namespaces/000000.yaml", "namespaces/000001.json"))
+	assert.NoError(t, err)
+	assert.Len(t, archive.Data.Namespaces, 2)
+
+	limit := maxBackupDataSize
+	maxBackupDataSize = 1024
+	defer func() {
+		maxBackupDataSize = limit
+	}()
+	_, err = DecodeBackupArchive(newArchive(map[string]string{"manifest.yaml": "version: v1\n",
+		"namespaces/000000.json": "[" + strings.Repeat(" ", 2048) + "]"},
+		"manifest.yaml", "namespaces/000000.json"))
+	assert.Error(t, err)
+}
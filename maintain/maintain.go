@@ -26,6 +26,8 @@ import (
 	api "github.com/polarismesh/polaris/common/api/v1"
 	"github.com/polarismesh/polaris/common/connlimit"
 	commonlog "github.com/polarismesh/polaris/common/log"
+	"github.com/polarismesh/polaris/common/model"
+	"github.com/polarismesh/polaris/service/healthcheck"
 )
 
 func (s *Server) GetServerConnections(_ context.Context, req *ConnReq) (*ConnCountResp, error) {
@@ -143,6 +145,17 @@ func (s *Server) GetLastHeartbeat(_ context.Context, req *api.Instance) *api.Res
 	return s.healthCheckServer.GetLastHeartbeat(req)
 }
 
+func (s *Server) GetInstanceHealthHistory(_ context.Context,
+	req *healthcheck.HealthHistoryQuery) ([]*model.HealthChangeRecord, error) {
+	if s.healthCheckServer == nil {
+		return nil, errors.New("health check not open")
+	}
+	if req.InstanceId == "" && req.Service == "" {
+		return nil, errors.New("missing param id or service")
+	}
+	return s.healthCheckServer.GetHealthHistory(req)
+}
+
 func (s *Server) GetLogOutputLevel(_ context.Context) (map[string]string, error) {
 	scopes := commonlog.Scopes()
 	out := make(map[string]string, len(scopes))
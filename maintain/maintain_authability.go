@@ -19,9 +19,12 @@ package maintain
 
 import (
 	"context"
+	"io"
 
 	api "github.com/polarismesh/polaris/common/api/v1"
 	"github.com/polarismesh/polaris/common/model"
+	"github.com/polarismesh/polaris/service/healthcheck"
+	"github.com/polarismesh/polaris/store/transfer"
 )
 
 var _ MaintainOperateServer = (*serverAuthAbility)(nil)
@@ -96,6 +99,17 @@ func (svr *serverAuthAbility) GetLastHeartbeat(ctx context.Context, req *api.Ins
 	return svr.targetServer.GetLastHeartbeat(ctx, req)
 }
 
+func (svr *serverAuthAbility) GetInstanceHealthHistory(ctx context.Context,
+	req *healthcheck.HealthHistoryQuery) ([]*model.HealthChangeRecord, error) {
+	authCtx := svr.collectMaintainAuthContext(ctx, model.Read, "GetInstanceHealthHistory")
+	_, err := svr.authMgn.CheckConsolePermission(authCtx)
+	if err != nil {
+		return nil, err
+	}
+
+	return svr.targetServer.GetInstanceHealthHistory(ctx, req)
+}
+
 func (svr *serverAuthAbility) GetLogOutputLevel(ctx context.Context) (map[string]string, error) {
 	authCtx := svr.collectMaintainAuthContext(ctx, model.Read, "GetLogOutputLevel")
 	_, err := svr.authMgn.CheckConsolePermission(authCtx)
@@ -115,3 +129,25 @@ func (svr *serverAuthAbility) SetLogOutputLevel(ctx context.Context, scope strin
 
 	return svr.targetServer.SetLogOutputLevel(ctx, scope, level)
 }
+
+// ExportBackup 备份数据包含用户密码以及各类资源的 token，只允许管理员导出
+func (svr *serverAuthAbility) ExportBackup(ctx context.Context, format BackupFormat, w io.Writer) error {
+	authCtx := svr.collectMaintainAuthContext(ctx, model.Modify, "ExportBackup")
+	_, err := svr.authMgn.CheckConsolePermission(authCtx)
+	if err != nil {
+		return err
+	}
+
+	return svr.targetServer.ExportBackup(ctx, format, w)
+}
+
+func (svr *serverAuthAbility) RestoreBackup(ctx context.Context,
+	req *RestoreBackupReq) (*transfer.Report, error) {
+	authCtx := svr.collectMaintainAuthContext(ctx, model.Create, "RestoreBackup")
+	_, err := svr.authMgn.CheckConsolePermission(authCtx)
+	if err != nil {
+		return nil, err
+	}
+
+	return svr.targetServer.RestoreBackup(ctx, req)
+}
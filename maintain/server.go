@@ -28,7 +28,9 @@ import (
 var _ MaintainOperateServer = (*Server)(nil)
 
 type Server struct {
-	mu                sync.Mutex
+	mu sync.Mutex
+	// backupMu 同一时刻只允许一个备份或者还原任务
+	backupMu          sync.Mutex
 	namingServer      service.DiscoverServer
 	healthCheckServer *healthcheck.Server
 	storage           store.Store
@@ -18,26 +18,33 @@
 package main
 
 import (
+	_ "github.com/polarismesh/polaris/apiserver/consulserver"
 	_ "github.com/polarismesh/polaris/apiserver/eurekaserver"
 	_ "github.com/polarismesh/polaris/apiserver/grpcserver/config"
 	_ "github.com/polarismesh/polaris/apiserver/grpcserver/discover"
 	_ "github.com/polarismesh/polaris/apiserver/httpserver"
 	_ "github.com/polarismesh/polaris/apiserver/l5pbserver"
+	_ "github.com/polarismesh/polaris/apiserver/nacosserver"
 	_ "github.com/polarismesh/polaris/apiserver/prometheussd"
 	_ "github.com/polarismesh/polaris/apiserver/xdsserverv3"
 	_ "github.com/polarismesh/polaris/auth/defaultauth"
 	_ "github.com/polarismesh/polaris/cache"
 	_ "github.com/polarismesh/polaris/plugin/cmdb/memory"
+	_ "github.com/polarismesh/polaris/plugin/crypto/aes"
 	_ "github.com/polarismesh/polaris/plugin/discoverevent/local"
 	_ "github.com/polarismesh/polaris/plugin/discoverstat/discoverlocal"
+	_ "github.com/polarismesh/polaris/plugin/healthchecker/activeprobe"
 	_ "github.com/polarismesh/polaris/plugin/healthchecker/heartbeatmemory"
+	_ "github.com/polarismesh/polaris/plugin/healthchecker/heartbeatpeer"
 	_ "github.com/polarismesh/polaris/plugin/healthchecker/heartbeatredis"
 	_ "github.com/polarismesh/polaris/plugin/history/logger"
+	_ "github.com/polarismesh/polaris/plugin/keyprovider/file"
 	_ "github.com/polarismesh/polaris/plugin/password"
 	_ "github.com/polarismesh/polaris/plugin/ratelimit/lrurate"
 	_ "github.com/polarismesh/polaris/plugin/ratelimit/token"
 	_ "github.com/polarismesh/polaris/plugin/statis/local"
 	_ "github.com/polarismesh/polaris/plugin/whitelist"
 	_ "github.com/polarismesh/polaris/store/boltdb"
+	_ "github.com/polarismesh/polaris/store/raftdb"
 	_ "github.com/polarismesh/polaris/store/sqldb"
 )
@@ -0,0 +1,64 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package plugin
+
+import (
+	"os"
+	"sync"
+
+	commonLog "github.com/polarismesh/polaris/common/log"
+)
+
+var (
+	cryptoOnce sync.Once
+)
+
+// Crypto 配置加解密插件，采用信封加密：数据密钥加密配置内容，主密钥加密数据密钥
+type Crypto interface {
+	Plugin
+	// Algorithm 加密算法名称
+	Algorithm() string
+	// GenerateKey 生成数据密钥
+	GenerateKey() ([]byte, error)
+	// Encrypt 使用数据密钥加密，返回 base64 编码的密文
+	Encrypt(plaintext string, key []byte) (string, error)
+	// Decrypt 使用数据密钥解密 base64 编码的密文
+	Decrypt(ciphertext string, key []byte) (string, error)
+	// WrapKey 使用主密钥加密数据密钥
+	WrapKey(key []byte) ([]byte, error)
+	// UnwrapKey 使用主密钥解密数据密钥
+	UnwrapKey(wrappedKey []byte) ([]byte, error)
+}
+
+// GetCrypto 获取配置加解密插件，未配置时返回 nil
+func GetCrypto() Crypto {
+	c := &config.Crypto
+	plugin, exist := pluginSet[c.Name]
+	if !exist {
+		return nil
+	}
+
+	cryptoOnce.Do(func() {
+		if err := plugin.Initialize(c); err != nil {
+			commonLog.GetScopeOrDefaultByName(c.Name).Errorf("plugin init err: %s", err.Error())
+			os.Exit(-1)
+		}
+	})
+
+	return plugin.(Crypto)
+}
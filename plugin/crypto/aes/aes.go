@@ -0,0 +1,172 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package aes
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+
+	"github.com/polarismesh/polaris/plugin"
+)
+
+const (
+	// PluginName 插件名称
+	PluginName = "AES"
+	// dataKeySize 数据密钥长度，使用 AES-256
+	dataKeySize = 32
+)
+
+// init 初始化注册函数
+func init() {
+	plugin.RegisterPlugin(PluginName, &AESCrypto{})
+}
+
+// AESCrypto 基于本地密钥文件的 AES-GCM 加解密插件
+type AESCrypto struct {
+	masterKey []byte
+}
+
+// Name 返回插件名字
+func (c *AESCrypto) Name() string {
+	return PluginName
+}
+
+// Initialize 插件初始化，从 keyFile 中读取 base64 编码的主密钥
+func (c *AESCrypto) Initialize(conf *plugin.ConfigEntry) error {
+	keyFile, _ := conf.Option["keyFile"].(string)
+	if keyFile == "" {
+		return errors.New("crypto plugin AES keyFile is empty")
+	}
+	data, err := ioutil.ReadFile(keyFile)
+	if err != nil {
+		return err
+	}
+	return c.SetMasterKey(strings.TrimSpace(string(data)))
+}
+
+// SetMasterKey 设置 base64 编码的主密钥，长度必须为 16、24 或 32 字节
+func (c *AESCrypto) SetMasterKey(encodedKey string) error {
+	key, err := base64.StdEncoding.DecodeString(encodedKey)
+	if err != nil {
+		return fmt.Errorf("decode master key: %w", err)
+	}
+	switch len(key) {
+	case 16, 24, 32:
+	default:
+		return fmt.Errorf("invalid master key size %d", len(key))
+	}
+	c.masterKey = key
+	return nil
+}
+
+// Destroy 销毁插件
+func (c *AESCrypto) Destroy() error {
+	return nil
+}
+
+// Algorithm 加密算法名称
+func (c *AESCrypto) Algorithm() string {
+	return PluginName
+}
+
+// GenerateKey 生成数据密钥
+func (c *AESCrypto) GenerateKey() ([]byte, error) {
+	key := make([]byte, dataKeySize)
+	if _, err := io.ReadFull(rand.Reader, key); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// Encrypt 使用数据密钥加密
+func (c *AESCrypto) Encrypt(plaintext string, key []byte) (string, error) {
+	sealed, err := seal([]byte(plaintext), key)
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt 使用数据密钥解密
+func (c *AESCrypto) Decrypt(ciphertext string, key []byte) (string, error) {
+	data, err := base64.StdEncoding.DecodeString(ciphertext)
+	if err != nil {
+		return "", err
+	}
+	plaintext, err := open(data, key)
+	if err != nil {
+		return "", err
+	}
+	return string(plaintext), nil
+}
+
+// WrapKey 使用主密钥加密数据密钥
+func (c *AESCrypto) WrapKey(key []byte) ([]byte, error) {
+	if len(c.masterKey) == 0 {
+		return nil, errors.New("crypto plugin AES master key not initialized")
+	}
+	return seal(key, c.masterKey)
+}
+
+// UnwrapKey 使用主密钥解密数据密钥
+func (c *AESCrypto) UnwrapKey(wrappedKey []byte) ([]byte, error) {
+	if len(c.masterKey) == 0 {
+		return nil, errors.New("crypto plugin AES master key not initialized")
+	}
+	return open(wrappedKey, c.masterKey)
+}
+
+// seal AES-GCM 加密，随机 nonce 放在密文前面
+func seal(plaintext, key []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	return gcm.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+func open(data, key []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < gcm.NonceSize() {
+		return nil, errors.New("ciphertext too short")
+	}
+	nonce, ciphertext := data[:gcm.NonceSize()], data[gcm.NonceSize():]
+	return gcm.Open(nil, nonce, ciphertext, nil)
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,71 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package aes
+
+import (
+	"encoding/base64"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/polarismesh/polaris/plugin"
+)
+
+func TestAESCrypto_Initialize(t *testing.T) {
+	dir, err := ioutil.TempDir("", "polaris-crypto")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	keyFile := filepath.Join(dir, "master.key")
+	assert.NoError(t, ioutil.WriteFile(keyFile,
+		[]byte(base64.StdEncoding.EncodeToString(make([]byte, 32))+"\n"), 0600))
+
+	c := &AESCrypto{}
+	assert.NoError(t, c.Initialize(&plugin.ConfigEntry{Option: map[string]interface{}{"keyFile": keyFile}}))
+	assert.Error(t, c.Initialize(&plugin.ConfigEntry{Option: map[string]interface{}{}}))
+	assert.Error(t, c.SetMasterKey(base64.StdEncoding.EncodeToString(make([]byte, 10))))
+}
+
+func TestAESCrypto_EncryptDecrypt(t *testing.T) {
+	c := &AESCrypto{}
+	assert.NoError(t, c.SetMasterKey(base64.StdEncoding.EncodeToString([]byte("0123456789abcdef"))))
+
+	key, err := c.GenerateKey()
+	assert.NoError(t, err)
+	assert.Equal(t, dataKeySize, len(key))
+
+	ciphertext, err := c.Encrypt("password=polaris", key)
+	assert.NoError(t, err)
+	assert.NotContains(t, ciphertext, "polaris")
+	plaintext, err := c.Decrypt(ciphertext, key)
+	assert.NoError(t, err)
+	assert.Equal(t, "password=polaris", plaintext)
+
+	otherKey, _ := c.GenerateKey()
+	_, err = c.Decrypt(ciphertext, otherKey)
+	assert.Error(t, err)
+
+	wrapped, err := c.WrapKey(key)
+	assert.NoError(t, err)
+	unwrapped, err := c.UnwrapKey(wrapped)
+	assert.NoError(t, err)
+	assert.Equal(t, key, unwrapped)
+}
@@ -38,6 +38,8 @@ type CheckRequest struct {
 	QueryRequest
 	ExpireDurationSec uint32
 	CurTimeSec        func() int64
+	// Metadata instance metadata merged over its service metadata, only filled for active checkers
+	Metadata map[string]string
 }
 
 // CheckResponse check heartbeat response
@@ -74,10 +76,13 @@ type HealthCheckType int32
 
 const (
 	HealthCheckerHeartbeat HealthCheckType = iota + 1
+	// HealthCheckerProbe the server actively dials the instance instead of waiting for heartbeats
+	HealthCheckerProbe
 )
 
 var (
-	healthCheckOnce = &sync.Once{}
+	healthCheckLock  = &sync.Mutex{}
+	healthCheckOnces = make(map[string]*sync.Once)
 )
 
 // HealthChecker health checker plugin interface
@@ -99,6 +104,12 @@ type HealthChecker interface {
 	Delete(id string) error
 }
 
+// PeerHealthChecker 需要在健康检查节点之间同步数据的 checker，节点列表变化时会收到通知
+type PeerHealthChecker interface {
+	// SetPeers 设置健康检查的节点列表，localHost 为当前节点
+	SetPeers(localHost string, peers []string)
+}
+
 // GetHealthChecker get the health checker by name
 func GetHealthChecker(name string, cfg *ConfigEntry) HealthChecker {
 	plugin, exist := pluginSet[name]
@@ -106,7 +117,15 @@ func GetHealthChecker(name string, cfg *ConfigEntry) HealthChecker {
 		return nil
 	}
 
-	healthCheckOnce.Do(func() {
+	healthCheckLock.Lock()
+	initOnce, ok := healthCheckOnces[name]
+	if !ok {
+		initOnce = &sync.Once{}
+		healthCheckOnces[name] = initOnce
+	}
+	healthCheckLock.Unlock()
+
+	initOnce.Do(func() {
 		if err := plugin.Initialize(cfg); err != nil {
 			healthcheckLog.Errorf("plugin init err: %s", err.Error())
 			os.Exit(-1)
@@ -0,0 +1,240 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package activeprobe
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/mitchellh/mapstructure"
+
+	commonLog "github.com/polarismesh/polaris/common/log"
+	"github.com/polarismesh/polaris/common/utils"
+	"github.com/polarismesh/polaris/plugin"
+)
+
+const (
+	// PluginName plugin name
+	PluginName = "activeProbe"
+
+	defaultTimeout            = 2 * time.Second
+	defaultHealthyThreshold   = 1
+	defaultUnhealthyThreshold = 3
+)
+
+var log = commonLog.GetScopeOrDefaultByName(commonLog.HealthcheckLoggerName)
+
+// Config 主动探测插件配置，探测参数可以通过实例的 internal-probe-* 元数据按实例覆盖
+type Config struct {
+	// Protocol 探测协议，tcp、http 或者 grpc，默认为 tcp
+	Protocol string `mapstructure:"protocol"`
+	// Port 探测端口，为 0 时探测实例端口
+	Port uint32 `mapstructure:"port"`
+	// Timeout 单次探测的超时时间
+	Timeout time.Duration `mapstructure:"timeout"`
+	// HTTPPath http 探测的请求路径，默认为 /
+	HTTPPath string `mapstructure:"httpPath"`
+	// HTTPExpectStatus http 探测期望的状态码，如 200,204 或者 2xx，默认为 2xx
+	HTTPExpectStatus string `mapstructure:"httpExpectStatus"`
+	// HTTPExpectBody http 探测的响应体需要包含的内容
+	HTTPExpectBody string `mapstructure:"httpExpectBody"`
+	// GRPCService grpc 健康检查请求中的服务名，为空时检查整个 server
+	GRPCService string `mapstructure:"grpcService"`
+	// HealthyThreshold 连续成功多少次后，实例由不健康变为健康
+	HealthyThreshold uint32 `mapstructure:"healthyThreshold"`
+	// UnhealthyThreshold 连续失败多少次后，实例由健康变为不健康
+	UnhealthyThreshold uint32 `mapstructure:"unhealthyThreshold"`
+}
+
+func (c *Config) setDefault() {
+	if c.Protocol == "" {
+		c.Protocol = protocolTCP
+	}
+	if c.Timeout <= 0 {
+		c.Timeout = defaultTimeout
+	}
+	if c.HTTPPath == "" {
+		c.HTTPPath = "/"
+	}
+	if c.HTTPExpectStatus == "" {
+		c.HTTPExpectStatus = defaultExpectStatus
+	}
+	if c.HealthyThreshold == 0 {
+		c.HealthyThreshold = defaultHealthyThreshold
+	}
+	if c.UnhealthyThreshold == 0 {
+		c.UnhealthyThreshold = defaultUnhealthyThreshold
+	}
+}
+
+// ProbeRecord probe result of an instance
+type ProbeRecord struct {
+	LastSuccessSec int64
+	SuccessCount   uint32
+	FailureCount   uint32
+}
+
+// ProbeHealthChecker dials the instances by tcp/http/grpc to check their health
+type ProbeHealthChecker struct {
+	cfg        *Config
+	records    *sync.Map
+	httpClient *http.Client
+}
+
+// Name return plugin name
+func (p *ProbeHealthChecker) Name() string {
+	return PluginName
+}
+
+// Initialize initialize plugin
+func (p *ProbeHealthChecker) Initialize(c *plugin.ConfigEntry) error {
+	cfg := &Config{}
+	decoder, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+		DecodeHook: mapstructure.StringToTimeDurationHookFunc(),
+		Result:     cfg,
+	})
+	if err != nil {
+		return err
+	}
+	if err = decoder.Decode(c.Option); err != nil {
+		return fmt.Errorf("fail to decode %s config entry, err is %v", PluginName, err)
+	}
+	cfg.setDefault()
+	if !isValidProtocol(cfg.Protocol) {
+		return fmt.Errorf("unsupported probe protocol %s", cfg.Protocol)
+	}
+	p.init(cfg)
+	return nil
+}
+
+func (p *ProbeHealthChecker) init(cfg *Config) {
+	p.cfg = cfg
+	p.records = &sync.Map{}
+	p.httpClient = &http.Client{
+		Transport: &http.Transport{
+			DisableKeepAlives: true,
+		},
+		// 探测不跟随重定向，3xx 由期望状态码决定是否健康
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// Destroy plugin destruction
+func (p *ProbeHealthChecker) Destroy() error {
+	return nil
+}
+
+// Type for health check plugin, only one same type plugin is allowed
+func (p *ProbeHealthChecker) Type() plugin.HealthCheckType {
+	return plugin.HealthCheckerProbe
+}
+
+// Report 主动探测的实例不依赖心跳上报，直接忽略
+func (p *ProbeHealthChecker) Report(request *plugin.ReportRequest) error {
+	log.Debugf("[HealthCheck][ProbeCheck]ignore heartbeat of probing instance %s", request.InstanceId)
+	return nil
+}
+
+// Query queries the last successful probe time
+func (p *ProbeHealthChecker) Query(request *plugin.QueryRequest) (*plugin.QueryResponse, error) {
+	record, ok := p.loadRecord(request.InstanceId)
+	if !ok {
+		return &plugin.QueryResponse{}, nil
+	}
+	return &plugin.QueryResponse{
+		Server:           utils.LocalHost,
+		Exists:           true,
+		LastHeartbeatSec: record.LastSuccessSec,
+	}, nil
+}
+
+// Check probes the instance and decides whether the health status should change
+func (p *ProbeHealthChecker) Check(request *plugin.CheckRequest) (*plugin.CheckResponse, error) {
+	settings, err := parseProbeSettings(request, p.cfg)
+	if err != nil {
+		return nil, err
+	}
+	record, _ := p.loadRecord(request.InstanceId)
+	probeErr := p.probe(settings)
+	if probeErr == nil {
+		record.SuccessCount++
+		record.FailureCount = 0
+		record.LastSuccessSec = request.CurTimeSec()
+	} else {
+		record.FailureCount++
+		record.SuccessCount = 0
+		log.Debugf("[HealthCheck][ProbeCheck]probe %s %s failed, id is %s, err is %v",
+			settings.protocol, settings.address, request.InstanceId, probeErr)
+	}
+	p.records.Store(request.InstanceId, record)
+
+	checkResp := &plugin.CheckResponse{
+		Healthy:              request.Healthy,
+		LastHeartbeatTimeSec: record.LastSuccessSec,
+		Regular:              true,
+	}
+	switch {
+	case request.Healthy && record.FailureCount >= p.cfg.UnhealthyThreshold:
+		checkResp.Healthy = false
+		log.Infof("[Health Check][ProbeCheck]probe failed %d times, %s %s, id is %s, last err is %v",
+			record.FailureCount, settings.protocol, settings.address, request.InstanceId, probeErr)
+	case !request.Healthy && record.SuccessCount >= p.cfg.HealthyThreshold:
+		checkResp.Healthy = true
+		log.Infof("[Health Check][ProbeCheck]probe resumed, %s %s, id is %s",
+			settings.protocol, settings.address, request.InstanceId)
+	default:
+		checkResp.StayUnchanged = true
+	}
+	return checkResp, nil
+}
+
+func (p *ProbeHealthChecker) loadRecord(id string) (ProbeRecord, bool) {
+	value, ok := p.records.Load(id)
+	if !ok {
+		return ProbeRecord{}, false
+	}
+	return value.(ProbeRecord), true
+}
+
+// AddToCheck add the instances to check procedure
+func (p *ProbeHealthChecker) AddToCheck(request *plugin.AddCheckRequest) error {
+	return nil
+}
+
+// RemoveFromCheck the instances are probed by other server now, forget the local results
+func (p *ProbeHealthChecker) RemoveFromCheck(request *plugin.AddCheckRequest) error {
+	for _, id := range request.Instances {
+		p.records.Delete(id)
+	}
+	return nil
+}
+
+// Delete delete the id
+func (p *ProbeHealthChecker) Delete(id string) error {
+	p.records.Delete(id)
+	return nil
+}
+
+func init() {
+	d := &ProbeHealthChecker{}
+	plugin.RegisterPlugin(d.Name(), d)
+}
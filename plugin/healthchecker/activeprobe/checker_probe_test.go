@@ -0,0 +1,196 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package activeprobe
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+
+	"github.com/polarismesh/polaris/common/model"
+	"github.com/polarismesh/polaris/plugin"
+)
+
+func newTestChecker() *ProbeHealthChecker {
+	cfg := &Config{Timeout: time.Second, UnhealthyThreshold: 2}
+	cfg.setDefault()
+	checker := &ProbeHealthChecker{}
+	checker.init(cfg)
+	return checker
+}
+
+func newCheckRequest(addr string, healthy bool, metadata map[string]string) *plugin.CheckRequest {
+	host, portStr, _ := net.SplitHostPort(addr)
+	port, _ := strconv.Atoi(portStr)
+	return &plugin.CheckRequest{
+		QueryRequest: plugin.QueryRequest{
+			InstanceId: "instance",
+			Host:       host,
+			Port:       uint32(port),
+			Healthy:    healthy,
+		},
+		CurTimeSec: func() int64 {
+			return time.Now().Unix()
+		},
+		ExpireDurationSec: 5,
+		Metadata:          metadata,
+	}
+}
+
+func TestProbeHealthChecker_CheckTCP(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	addr := ln.Addr().String()
+
+	checker := newTestChecker()
+	resp, err := checker.Check(newCheckRequest(addr, false, nil))
+	assert.NoError(t, err)
+	assert.True(t, resp.Healthy)
+	assert.False(t, resp.StayUnchanged)
+
+	_ = ln.Close()
+	// 第一次失败未达到阈值，状态保持不变
+	resp, err = checker.Check(newCheckRequest(addr, true, nil))
+	assert.NoError(t, err)
+	assert.True(t, resp.Healthy)
+	assert.True(t, resp.StayUnchanged)
+
+	resp, err = checker.Check(newCheckRequest(addr, true, nil))
+	assert.NoError(t, err)
+	assert.False(t, resp.Healthy)
+	assert.False(t, resp.StayUnchanged)
+
+	queryResp, err := checker.Query(&plugin.QueryRequest{InstanceId: "instance"})
+	assert.NoError(t, err)
+	assert.True(t, queryResp.Exists)
+	assert.True(t, queryResp.LastHeartbeatSec > 0)
+}
+
+func TestProbeHealthChecker_CheckHTTP(t *testing.T) {
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/health" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(`{"status":"UP"}`))
+	}))
+	defer svr.Close()
+	addr := svr.Listener.Addr().String()
+
+	checker := newTestChecker()
+	resp, err := checker.Check(newCheckRequest(addr, false, map[string]string{
+		model.MetaKeyProbeProtocol:       "http",
+		model.MetaKeyProbeHTTPPath:       "/health",
+		model.MetaKeyProbeHTTPExpectBody: "UP",
+	}))
+	assert.NoError(t, err)
+	assert.True(t, resp.Healthy)
+
+	err = checker.probe(&probeSettings{
+		protocol:     protocolHTTP,
+		address:      addr,
+		timeout:      time.Second,
+		httpPath:     "/",
+		expectStatus: defaultExpectStatus,
+	})
+	assert.Error(t, err)
+
+	err = checker.probe(&probeSettings{
+		protocol:     protocolHTTP,
+		address:      addr,
+		timeout:      time.Second,
+		httpPath:     "/health",
+		expectStatus: defaultExpectStatus,
+		expectBody:   "DOWN",
+	})
+	assert.Error(t, err)
+}
+
+func TestProbeHealthChecker_CheckGRPC(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	healthSvr := health.NewServer()
+	healthSvr.SetServingStatus("demo", healthpb.HealthCheckResponse_NOT_SERVING)
+	grpcSvr := grpc.NewServer()
+	healthpb.RegisterHealthServer(grpcSvr, healthSvr)
+	go func() {
+		_ = grpcSvr.Serve(ln)
+	}()
+	defer grpcSvr.Stop()
+
+	checker := newTestChecker()
+	settings := &probeSettings{
+		protocol: protocolGRPC,
+		address:  ln.Addr().String(),
+		timeout:  time.Second,
+	}
+	assert.NoError(t, checker.probe(settings))
+
+	settings.grpcService = "demo"
+	assert.Error(t, checker.probe(settings))
+}
+
+func TestParseProbeSettings(t *testing.T) {
+	request := newCheckRequest("127.0.0.1:8080", true, map[string]string{
+		model.MetaKeyProbeProtocol: "HTTP",
+		model.MetaKeyProbePort:     "8081",
+		model.MetaKeyProbeTimeout:  "500ms",
+		model.MetaKeyProbeHTTPPath: "ready",
+	})
+	cfg := &Config{Timeout: time.Second}
+	cfg.setDefault()
+	settings, err := parseProbeSettings(request, cfg)
+	assert.NoError(t, err)
+	assert.Equal(t, protocolHTTP, settings.protocol)
+	assert.Equal(t, "127.0.0.1:8081", settings.address)
+	assert.Equal(t, 500*time.Millisecond, settings.timeout)
+	assert.Equal(t, "/ready", settings.httpPath)
+	assert.Equal(t, defaultExpectStatus, settings.expectStatus)
+
+	// 未设置元数据时使用插件配置
+	cfg = &Config{Protocol: protocolGRPC, Port: 9090, GRPCService: "demo"}
+	cfg.setDefault()
+	request.Metadata = nil
+	settings, err = parseProbeSettings(request, cfg)
+	assert.NoError(t, err)
+	assert.Equal(t, protocolGRPC, settings.protocol)
+	assert.Equal(t, "127.0.0.1:9090", settings.address)
+	assert.Equal(t, defaultTimeout, settings.timeout)
+	assert.Equal(t, "demo", settings.grpcService)
+
+	request.Metadata = map[string]string{model.MetaKeyProbeProtocol: "udp"}
+	_, err = parseProbeSettings(request, cfg)
+	assert.Error(t, err)
+}
+
+func TestMatchStatus(t *testing.T) {
+	assert.True(t, matchStatus("2xx", 200))
+	assert.True(t, matchStatus("2xx", 204))
+	assert.False(t, matchStatus("2xx", 301))
+	assert.True(t, matchStatus("200, 301", 301))
+	assert.False(t, matchStatus("200,301", 404))
+	assert.True(t, matchStatus("4XX", 404))
+}
@@ -0,0 +1,218 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package activeprobe
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+
+	"github.com/polarismesh/polaris/common/model"
+	"github.com/polarismesh/polaris/plugin"
+)
+
+const (
+	protocolTCP  = "tcp"
+	protocolHTTP = "http"
+	protocolGRPC = "grpc"
+
+	defaultExpectStatus = "2xx"
+	// maxBodySize 只读取响应体的前 64KB 用于匹配
+	maxBodySize = 64 * 1024
+)
+
+type probeSettings struct {
+	protocol     string
+	address      string
+	timeout      time.Duration
+	httpPath     string
+	expectStatus string
+	expectBody   string
+	grpcService  string
+}
+
+// parseProbeSettings 探测参数以插件配置为准，实例元数据中的 internal-probe-* 可以按实例覆盖
+func parseProbeSettings(request *plugin.CheckRequest, cfg *Config) (*probeSettings, error) {
+	metadata := request.Metadata
+	settings := &probeSettings{
+		protocol:     cfg.Protocol,
+		timeout:      cfg.Timeout,
+		httpPath:     cfg.HTTPPath,
+		expectStatus: cfg.HTTPExpectStatus,
+		expectBody:   cfg.HTTPExpectBody,
+		grpcService:  cfg.GRPCService,
+	}
+	if val, ok := metadata[model.MetaKeyProbeProtocol]; ok {
+		settings.protocol = strings.ToLower(val)
+	}
+	if !isValidProtocol(settings.protocol) {
+		return nil, fmt.Errorf("unsupported probe protocol %s", settings.protocol)
+	}
+	if val, ok := metadata[model.MetaKeyProbeHTTPPath]; ok && val != "" {
+		settings.httpPath = val
+	}
+	if val, ok := metadata[model.MetaKeyProbeHTTPExpectStatus]; ok && val != "" {
+		settings.expectStatus = val
+	}
+	if val, ok := metadata[model.MetaKeyProbeHTTPExpectBody]; ok {
+		settings.expectBody = val
+	}
+	if val, ok := metadata[model.MetaKeyProbeGRPCService]; ok {
+		settings.grpcService = val
+	}
+
+	port := request.Port
+	if cfg.Port != 0 {
+		port = cfg.Port
+	}
+	if val, ok := metadata[model.MetaKeyProbePort]; ok {
+		probePort, err := strconv.ParseUint(val, 10, 16)
+		if err != nil {
+			return nil, fmt.Errorf("invalid probe port %s", val)
+		}
+		port = uint32(probePort)
+	}
+	if port == 0 {
+		return nil, fmt.Errorf("probe port is empty")
+	}
+	settings.address = net.JoinHostPort(request.Host, strconv.Itoa(int(port)))
+
+	if val, ok := metadata[model.MetaKeyProbeTimeout]; ok {
+		timeout, err := time.ParseDuration(val)
+		if err != nil || timeout <= 0 {
+			return nil, fmt.Errorf("invalid probe timeout %s", val)
+		}
+		settings.timeout = timeout
+	}
+	if !strings.HasPrefix(settings.httpPath, "/") {
+		settings.httpPath = "/" + settings.httpPath
+	}
+	return settings, nil
+}
+
+func isValidProtocol(protocol string) bool {
+	switch protocol {
+	case protocolTCP, protocolHTTP, protocolGRPC:
+		return true
+	default:
+		return false
+	}
+}
+
+func (p *ProbeHealthChecker) probe(settings *probeSettings) error {
+	ctx, cancel := context.WithTimeout(context.Background(), settings.timeout)
+	defer cancel()
+
+	switch settings.protocol {
+	case protocolHTTP:
+		return p.probeHTTP(ctx, settings)
+	case protocolGRPC:
+		return probeGRPC(ctx, settings)
+	default:
+		return probeTCP(ctx, settings)
+	}
+}
+
+func probeTCP(ctx context.Context, settings *probeSettings) error {
+	dialer := &net.Dialer{}
+	conn, err := dialer.DialContext(ctx, "tcp", settings.address)
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
+
+func (p *ProbeHealthChecker) probeHTTP(ctx context.Context, settings *probeSettings) error {
+	url := "http://" + settings.address + settings.httpPath
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if !matchStatus(settings.expectStatus, resp.StatusCode) {
+		return fmt.Errorf("unexpected status code %d, expect %s", resp.StatusCode, settings.expectStatus)
+	}
+	if settings.expectBody == "" {
+		return nil
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxBodySize))
+	if err != nil {
+		return err
+	}
+	if !bytes.Contains(body, []byte(settings.expectBody)) {
+		return fmt.Errorf("response body not contains %s", settings.expectBody)
+	}
+	return nil
+}
+
+func probeGRPC(ctx context.Context, settings *probeSettings) error {
+	conn, err := grpc.DialContext(ctx, settings.address,
+		grpc.WithTransportCredentials(insecure.NewCredentials()), grpc.WithBlock())
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	resp, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{
+		Service: settings.grpcService,
+	})
+	if err != nil {
+		return err
+	}
+	if resp.GetStatus() != healthpb.HealthCheckResponse_SERVING {
+		return fmt.Errorf("grpc health status is %s", resp.GetStatus())
+	}
+	return nil
+}
+
+// matchStatus 期望状态码支持逗号分隔，以及 2xx 这样的通配写法
+func matchStatus(expect string, code int) bool {
+	codeStr := strconv.Itoa(code)
+	for _, item := range strings.Split(expect, ",") {
+		item = strings.ToLower(strings.TrimSpace(item))
+		if len(item) != len(codeStr) {
+			continue
+		}
+		matched := true
+		for i := range item {
+			if item[i] != 'x' && item[i] != codeStr[i] {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,470 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package heartbeatpeer
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/mitchellh/mapstructure"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	commonLog "github.com/polarismesh/polaris/common/log"
+	"github.com/polarismesh/polaris/plugin"
+)
+
+const (
+	// PluginName plugin name
+	PluginName = "heartbeatPeer"
+
+	defaultListenPort   = 8092
+	defaultSyncInterval = time.Second
+	defaultSyncTimeout  = 2 * time.Second
+	defaultBatchSize    = 1024
+	defaultRecordTTL    = time.Hour
+	sweepInterval       = time.Minute
+	// tokenHeader 节点之间同步心跳记录时携带共享密钥的请求头
+	tokenHeader = "x-polaris-heartbeat-peer-token"
+)
+
+var log = commonLog.GetScopeOrDefaultByName(commonLog.HealthcheckLoggerName)
+
+// Config 节点间同步心跳的插件配置
+type Config struct {
+	// ListenPort 接收其他节点同步心跳记录的端口，所有节点需要保持一致
+	ListenPort uint32 `mapstructure:"listenPort"`
+	// SyncInterval 合并同步心跳记录的间隔
+	SyncInterval time.Duration `mapstructure:"syncInterval"`
+	// SyncTimeout 单次同步请求的超时时间
+	SyncTimeout time.Duration `mapstructure:"syncTimeout"`
+	// BatchSize 单次同步请求最多携带的心跳记录数
+	BatchSize int `mapstructure:"batchSize"`
+	// RecordTTL 超过该时长没有更新的心跳记录会被清理
+	RecordTTL time.Duration `mapstructure:"recordTTL"`
+	// Token 节点之间同步心跳记录时校验的共享密钥，所有节点需要保持一致
+	Token string `mapstructure:"token"`
+}
+
+func (c *Config) setDefault() {
+	if c.ListenPort == 0 {
+		c.ListenPort = defaultListenPort
+	}
+	if c.SyncInterval <= 0 {
+		c.SyncInterval = defaultSyncInterval
+	}
+	if c.SyncTimeout <= 0 {
+		c.SyncTimeout = defaultSyncTimeout
+	}
+	if c.BatchSize <= 0 {
+		c.BatchSize = defaultBatchSize
+	}
+	if c.RecordTTL <= 0 {
+		c.RecordTTL = defaultRecordTTL
+	}
+}
+
+type peer struct {
+	host   string
+	conn   *grpc.ClientConn
+	client HeartbeatPeerClient
+}
+
+// PeerHealthChecker 心跳记录保存在内存中，并同步给其他的健康检查节点，
+// 任意节点收到的心跳都可以被负责检查该实例的节点感知，不再依赖 redis
+type PeerHealthChecker struct {
+	cfg *Config
+
+	lock    sync.RWMutex
+	records map[string]*HeartbeatRecord
+	// tombstones 实例 id => 被删除的心跳记录的时间，不早于该时间的同步记录都是过期的
+	tombstones map[string]int64
+
+	pendingLock sync.Mutex
+	pending     map[string]*HeartbeatRecord
+
+	peersLock sync.Mutex
+	localHost string
+	peers     map[string]*peer
+
+	server *grpc.Server
+	cancel context.CancelFunc
+}
+
+// Name return plugin name
+func (p *PeerHealthChecker) Name() string {
+	return PluginName
+}
+
+// Initialize initialize plugin
+func (p *PeerHealthChecker) Initialize(c *plugin.ConfigEntry) error {
+	cfg := &Config{}
+	decoder, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+		DecodeHook: mapstructure.StringToTimeDurationHookFunc(),
+		Result:     cfg,
+	})
+	if err != nil {
+		return err
+	}
+	if err = decoder.Decode(c.Option); err != nil {
+		return fmt.Errorf("fail to decode %s config entry, err is %v", PluginName, err)
+	}
+	cfg.setDefault()
+	if cfg.Token == "" {
+		return fmt.Errorf("%s token is required to authenticate peers", PluginName)
+	}
+
+	listener, err := net.Listen("tcp", fmt.Sprintf(":%d", cfg.ListenPort))
+	if err != nil {
+		return fmt.Errorf("fail to listen %s port %d, err is %v", PluginName, cfg.ListenPort, err)
+	}
+	p.init(cfg)
+	p.server = grpc.NewServer()
+	RegisterHeartbeatPeerServer(p.server, p)
+	go func() {
+		if err := p.server.Serve(listener); err != nil {
+			log.Error("[HealthCheck][PeerCheck]heartbeat peer server stopped", zap.Error(err))
+		}
+	}()
+
+	var ctx context.Context
+	ctx, p.cancel = context.WithCancel(context.Background())
+	go p.run(ctx)
+	return nil
+}
+
+func (p *PeerHealthChecker) init(cfg *Config) {
+	p.cfg = cfg
+	p.records = make(map[string]*HeartbeatRecord)
+	p.tombstones = make(map[string]int64)
+	p.pending = make(map[string]*HeartbeatRecord)
+	p.peers = make(map[string]*peer)
+}
+
+// Destroy plugin destruction
+func (p *PeerHealthChecker) Destroy() error {
+	if p.cancel != nil {
+		p.cancel()
+	}
+	if p.server != nil {
+		p.server.Stop()
+	}
+	p.peersLock.Lock()
+	defer p.peersLock.Unlock()
+	for host, item := range p.peers {
+		_ = item.conn.Close()
+		delete(p.peers, host)
+	}
+	return nil
+}
+
+// Type for health check plugin, only one same type plugin is allowed
+func (p *PeerHealthChecker) Type() plugin.HealthCheckType {
+	return plugin.HealthCheckerHeartbeat
+}
+
+// Report process heartbeat info report
+func (p *PeerHealthChecker) Report(request *plugin.ReportRequest) error {
+	record := &HeartbeatRecord{
+		InstanceId: request.InstanceId,
+		Server:     request.LocalHost,
+		CurTimeSec: request.CurTimeSec,
+	}
+	if !p.store(record) {
+		return nil
+	}
+	p.pendingLock.Lock()
+	p.pending[record.InstanceId] = record
+	p.pendingLock.Unlock()
+	log.Debugf("[HealthCheck][PeerCheck]add hb record, instanceId %s, record %+v", request.InstanceId, record)
+	return nil
+}
+
+// store 只保存更新的心跳记录，避免同步延迟导致心跳时间回退，
+// 也避免其他节点把本地已经删除的记录重新同步回来
+func (p *PeerHealthChecker) store(record *HeartbeatRecord) bool {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if old, ok := p.records[record.InstanceId]; ok && old.CurTimeSec >= record.CurTimeSec {
+		return false
+	}
+	if deleted, ok := p.tombstones[record.InstanceId]; ok {
+		if deleted >= record.CurTimeSec {
+			return false
+		}
+		delete(p.tombstones, record.InstanceId)
+	}
+	p.records[record.InstanceId] = record
+	return true
+}
+
+// Query queries the heartbeat time
+func (p *PeerHealthChecker) Query(request *plugin.QueryRequest) (*plugin.QueryResponse, error) {
+	p.lock.RLock()
+	record, ok := p.records[request.InstanceId]
+	p.lock.RUnlock()
+	if !ok {
+		return &plugin.QueryResponse{
+			LastHeartbeatSec: 0,
+		}, nil
+	}
+	return &plugin.QueryResponse{
+		Server:           record.Server,
+		Exists:           true,
+		LastHeartbeatSec: record.CurTimeSec,
+	}, nil
+}
+
+// Check Report process the instance check
+func (p *PeerHealthChecker) Check(request *plugin.CheckRequest) (*plugin.CheckResponse, error) {
+	queryResp, err := p.Query(&request.QueryRequest)
+	if err != nil {
+		return nil, err
+	}
+	lastHeartbeatTime := queryResp.LastHeartbeatSec
+	checkResp := &plugin.CheckResponse{
+		LastHeartbeatTimeSec: lastHeartbeatTime,
+	}
+	curTimeSec := request.CurTimeSec()
+	if curTimeSec > lastHeartbeatTime && curTimeSec-lastHeartbeatTime >= int64(request.ExpireDurationSec) {
+		// 心跳超时
+		checkResp.Healthy = false
+		_ = p.Delete(request.InstanceId)
+		if request.Healthy {
+			log.Infof("[Health Check][PeerCheck]health check expired, "+
+				"last hb timestamp is %d, curTimeSec is %d, expireDurationSec is %d instanceId %s",
+				lastHeartbeatTime, curTimeSec, request.ExpireDurationSec, request.InstanceId)
+		} else {
+			checkResp.StayUnchanged = true
+		}
+		return checkResp, nil
+	}
+	checkResp.Healthy = true
+	if !request.Healthy {
+		log.Infof("[Health Check][PeerCheck]health check resumed, "+
+			"last hb timestamp is %d, curTimeSec is %d, expireDurationSec is %d instanceId %s",
+			lastHeartbeatTime, curTimeSec, request.ExpireDurationSec, request.InstanceId)
+	} else {
+		checkResp.StayUnchanged = true
+	}
+	return checkResp, nil
+}
+
+// AddToCheck add the instances to check procedure
+func (p *PeerHealthChecker) AddToCheck(request *plugin.AddCheckRequest) error {
+	return nil
+}
+
+// RemoveFromCheck removes the instances from check procedure
+func (p *PeerHealthChecker) RemoveFromCheck(request *plugin.AddCheckRequest) error {
+	return nil
+}
+
+// Delete delete the id
+func (p *PeerHealthChecker) Delete(id string) error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if record, ok := p.records[id]; ok {
+		p.tombstones[id] = record.CurTimeSec
+		delete(p.records, id)
+	}
+	return nil
+}
+
+// SetPeers 更新需要同步心跳记录的节点，新加入的节点会收到全量的心跳记录
+func (p *PeerHealthChecker) SetPeers(localHost string, peers []string) {
+	p.peersLock.Lock()
+	defer p.peersLock.Unlock()
+
+	p.localHost = localHost
+	next := make(map[string]struct{}, len(peers))
+	for _, host := range peers {
+		if host == localHost {
+			continue
+		}
+		next[host] = struct{}{}
+		if _, ok := p.peers[host]; ok {
+			continue
+		}
+		address := fmt.Sprintf("%s:%d", host, p.cfg.ListenPort)
+		conn, err := grpc.Dial(address, grpc.WithTransportCredentials(insecure.NewCredentials()))
+		if err != nil {
+			log.Error("[HealthCheck][PeerCheck]fail to dial peer", zap.String("address", address), zap.Error(err))
+			continue
+		}
+		item := &peer{host: host, conn: conn, client: NewHeartbeatPeerClient(conn)}
+		p.peers[host] = item
+		log.Infof("[HealthCheck][PeerCheck]add peer %s", address)
+		go p.send(item, p.snapshot())
+	}
+	for host, item := range p.peers {
+		if _, ok := next[host]; ok {
+			continue
+		}
+		_ = item.conn.Close()
+		delete(p.peers, host)
+		log.Infof("[HealthCheck][PeerCheck]remove peer %s", host)
+	}
+}
+
+// Sync 接收其他节点同步过来的心跳记录，只接受携带正确密钥的已知节点同步的记录
+func (p *PeerHealthChecker) Sync(ctx context.Context, req *SyncRequest) (*SyncResponse, error) {
+	if err := p.verify(ctx, req.GetFrom()); err != nil {
+		log.Warn("[HealthCheck][PeerCheck]reject hb records", zap.String("from", req.GetFrom()), zap.Error(err))
+		return nil, err
+	}
+	nowSec := time.Now().Unix()
+	ttlSec := int64(p.cfg.RecordTTL / time.Second)
+	for _, record := range req.GetRecords() {
+		// 已经过期或者时间明显超前的记录不会被清理，直接丢弃
+		if record.GetInstanceId() == "" || record.GetCurTimeSec() < nowSec-ttlSec ||
+			record.GetCurTimeSec() > nowSec+ttlSec {
+			continue
+		}
+		p.store(record)
+	}
+	log.Debugf("[HealthCheck][PeerCheck]receive %d hb records from %s", len(req.GetRecords()), req.GetFrom())
+	return &SyncResponse{}, nil
+}
+
+// verify 校验同步请求的密钥以及发送方是否为已知的节点
+func (p *PeerHealthChecker) verify(ctx context.Context, from string) error {
+	md, _ := metadata.FromIncomingContext(ctx)
+	tokens := md.Get(tokenHeader)
+	if len(tokens) == 0 || subtle.ConstantTimeCompare([]byte(tokens[0]), []byte(p.cfg.Token)) != 1 {
+		return status.Error(codes.Unauthenticated, "invalid heartbeat peer token")
+	}
+	p.peersLock.Lock()
+	_, ok := p.peers[from]
+	p.peersLock.Unlock()
+	if !ok {
+		return status.Errorf(codes.PermissionDenied, "unknown heartbeat peer %s", from)
+	}
+	return nil
+}
+
+func (p *PeerHealthChecker) run(ctx context.Context) {
+	syncTicker := time.NewTicker(p.cfg.SyncInterval)
+	defer syncTicker.Stop()
+	sweepTicker := time.NewTicker(sweepInterval)
+	defer sweepTicker.Stop()
+
+	for {
+		select {
+		case <-syncTicker.C:
+			p.flush()
+		case <-sweepTicker.C:
+			p.sweep(time.Now().Add(-p.cfg.RecordTTL).Unix())
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// flush 把上一个周期内收到的心跳记录同步给其他的节点
+func (p *PeerHealthChecker) flush() {
+	p.pendingLock.Lock()
+	if len(p.pending) == 0 {
+		p.pendingLock.Unlock()
+		return
+	}
+	pending := p.pending
+	p.pending = make(map[string]*HeartbeatRecord, len(pending))
+	p.pendingLock.Unlock()
+
+	records := make([]*HeartbeatRecord, 0, len(pending))
+	for _, record := range pending {
+		records = append(records, record)
+	}
+
+	p.peersLock.Lock()
+	peers := make([]*peer, 0, len(p.peers))
+	for _, item := range p.peers {
+		peers = append(peers, item)
+	}
+	p.peersLock.Unlock()
+
+	wg := &sync.WaitGroup{}
+	for _, item := range peers {
+		wg.Add(1)
+		go func(item *peer) {
+			defer wg.Done()
+			p.send(item, records)
+		}(item)
+	}
+	wg.Wait()
+}
+
+// send 分批发送心跳记录，发送失败的记录会在实例下一次心跳时重新同步
+func (p *PeerHealthChecker) send(item *peer, records []*HeartbeatRecord) {
+	for start := 0; start < len(records); start += p.cfg.BatchSize {
+		end := start + p.cfg.BatchSize
+		if end > len(records) {
+			end = len(records)
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), p.cfg.SyncTimeout)
+		ctx = metadata.AppendToOutgoingContext(ctx, tokenHeader, p.cfg.Token)
+		_, err := item.client.Sync(ctx, &SyncRequest{From: p.localHost, Records: records[start:end]})
+		cancel()
+		if err != nil {
+			log.Warn("[HealthCheck][PeerCheck]fail to sync hb records", zap.String("peer", item.host),
+				zap.Int("count", len(records)-start), zap.Error(err))
+			return
+		}
+	}
+}
+
+// snapshot 当前全量的心跳记录
+func (p *PeerHealthChecker) snapshot() []*HeartbeatRecord {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+	records := make([]*HeartbeatRecord, 0, len(p.records))
+	for _, record := range p.records {
+		records = append(records, record)
+	}
+	return records
+}
+
+// sweep 清理长时间没有更新的心跳记录，比如已经反注册的实例，以及过期的删除标记
+func (p *PeerHealthChecker) sweep(beforeSec int64) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	for id, record := range p.records {
+		if record.CurTimeSec < beforeSec {
+			delete(p.records, id)
+		}
+	}
+	for id, deleted := range p.tombstones {
+		if deleted < beforeSec {
+			delete(p.tombstones, id)
+		}
+	}
+}
+
+func init() {
+	d := &PeerHealthChecker{}
+	plugin.RegisterPlugin(d.Name(), d)
+}
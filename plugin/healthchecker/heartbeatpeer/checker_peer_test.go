@@ -0,0 +1,164 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package heartbeatpeer
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/polarismesh/polaris/plugin"
+)
+
+func freePort(t *testing.T) int {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	defer listener.Close()
+	return listener.Addr().(*net.TCPAddr).Port
+}
+
+func TestPeerHealthCheckerSync(t *testing.T) {
+	port := freePort(t)
+	receiver := &PeerHealthChecker{}
+	// 没有配置密钥时拒绝启动
+	err := receiver.Initialize(&plugin.ConfigEntry{
+		Name:   PluginName,
+		Option: map[string]interface{}{"listenPort": port},
+	})
+	assert.Error(t, err)
+	err = receiver.Initialize(&plugin.ConfigEntry{
+		Name:   PluginName,
+		Option: map[string]interface{}{"listenPort": port, "syncInterval": "100ms", "token": "secret"},
+	})
+	assert.NoError(t, err)
+	defer receiver.Destroy()
+	receiver.SetPeers("127.0.0.1", []string{"127.0.0.1", "127.0.0.2"})
+
+	sender := &PeerHealthChecker{}
+	cfg := &Config{ListenPort: uint32(port), Token: "secret"}
+	cfg.setDefault()
+	sender.init(cfg)
+
+	curTimeSec := time.Now().Unix()
+	assert.NoError(t, sender.Report(&plugin.ReportRequest{
+		QueryRequest: plugin.QueryRequest{InstanceId: "ins-1"},
+		LocalHost:    "127.0.0.2",
+		CurTimeSec:   curTimeSec,
+	}))
+	// 新加入的节点会收到全量的心跳记录
+	sender.SetPeers("127.0.0.2", []string{"127.0.0.2", "127.0.0.1"})
+	defer sender.Destroy()
+	assert.Eventually(t, func() bool {
+		resp, err := receiver.Query(&plugin.QueryRequest{InstanceId: "ins-1"})
+		return err == nil && resp.LastHeartbeatSec == curTimeSec
+	}, 5*time.Second, 50*time.Millisecond)
+
+	// 增量的心跳记录由定时任务合并同步
+	assert.NoError(t, sender.Report(&plugin.ReportRequest{
+		QueryRequest: plugin.QueryRequest{InstanceId: "ins-2"},
+		LocalHost:    "127.0.0.2",
+		CurTimeSec:   curTimeSec,
+	}))
+	sender.flush()
+	resp, err := receiver.Query(&plugin.QueryRequest{InstanceId: "ins-2"})
+	assert.NoError(t, err)
+	assert.True(t, resp.Exists)
+	assert.Equal(t, "127.0.0.2", resp.Server)
+
+	// 同步过来的旧记录不会覆盖更新的心跳时间
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(tokenHeader, "secret"))
+	_, err = receiver.Sync(ctx, &SyncRequest{From: "127.0.0.2", Records: []*HeartbeatRecord{
+		{InstanceId: "ins-2", Server: "127.0.0.3", CurTimeSec: curTimeSec - 10},
+	}})
+	assert.NoError(t, err)
+	resp, _ = receiver.Query(&plugin.QueryRequest{InstanceId: "ins-2"})
+	assert.Equal(t, curTimeSec, resp.LastHeartbeatSec)
+
+	// 密钥错误或者不是已知节点时拒绝同步
+	records := []*HeartbeatRecord{{InstanceId: "ins-3", Server: "127.0.0.3", CurTimeSec: curTimeSec}}
+	badCtx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(tokenHeader, "bad"))
+	_, err = receiver.Sync(badCtx, &SyncRequest{From: "127.0.0.2", Records: records})
+	assert.Error(t, err)
+	_, err = receiver.Sync(context.Background(), &SyncRequest{From: "127.0.0.2", Records: records})
+	assert.Error(t, err)
+	_, err = receiver.Sync(ctx, &SyncRequest{From: "127.0.0.3", Records: records})
+	assert.Error(t, err)
+	resp, _ = receiver.Query(&plugin.QueryRequest{InstanceId: "ins-3"})
+	assert.False(t, resp.Exists)
+
+	// 时间明显超前的记录直接丢弃
+	_, err = receiver.Sync(ctx, &SyncRequest{From: "127.0.0.2", Records: []*HeartbeatRecord{
+		{InstanceId: "ins-3", Server: "127.0.0.2", CurTimeSec: curTimeSec + int64(defaultRecordTTL/time.Second) + 60},
+	}})
+	assert.NoError(t, err)
+	resp, _ = receiver.Query(&plugin.QueryRequest{InstanceId: "ins-3"})
+	assert.False(t, resp.Exists)
+
+	sender.SetPeers("127.0.0.2", []string{"127.0.0.2"})
+	assert.Equal(t, 0, len(sender.peers))
+}
+
+func TestPeerHealthCheckerCheck(t *testing.T) {
+	checker := &PeerHealthChecker{}
+	cfg := &Config{}
+	cfg.setDefault()
+	checker.init(cfg)
+
+	curTimeSec := time.Now().Unix()
+	assert.NoError(t, checker.Report(&plugin.ReportRequest{
+		QueryRequest: plugin.QueryRequest{InstanceId: "ins-1"},
+		CurTimeSec:   curTimeSec - 20,
+	}))
+	check := func() *plugin.CheckResponse {
+		resp, err := checker.Check(&plugin.CheckRequest{
+			QueryRequest:      plugin.QueryRequest{InstanceId: "ins-1", Healthy: true},
+			ExpireDurationSec: 15,
+			CurTimeSec:        func() int64 { return curTimeSec },
+		})
+		assert.NoError(t, err)
+		return resp
+	}
+	resp := check()
+	assert.False(t, resp.Healthy)
+	assert.False(t, resp.StayUnchanged)
+
+	// 其他节点同步回来的旧记录不会恢复已经删除的记录
+	assert.False(t, checker.store(&HeartbeatRecord{InstanceId: "ins-1", CurTimeSec: curTimeSec - 20}))
+	queryResp, err := checker.Query(&plugin.QueryRequest{InstanceId: "ins-1"})
+	assert.NoError(t, err)
+	assert.False(t, queryResp.Exists)
+
+	assert.NoError(t, checker.Report(&plugin.ReportRequest{
+		QueryRequest: plugin.QueryRequest{InstanceId: "ins-1"},
+		CurTimeSec:   curTimeSec,
+	}))
+	resp = check()
+	assert.True(t, resp.Healthy)
+	assert.True(t, resp.StayUnchanged)
+
+	assert.Equal(t, 0, len(checker.tombstones))
+
+	checker.sweep(curTimeSec + 1)
+	queryResp, err = checker.Query(&plugin.QueryRequest{InstanceId: "ins-1"})
+	assert.NoError(t, err)
+	assert.False(t, queryResp.Exists)
+}
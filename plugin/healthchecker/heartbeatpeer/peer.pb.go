@@ -0,0 +1,256 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: peer.proto
+
+package heartbeatpeer
+
+import proto "github.com/golang/protobuf/proto"
+import fmt "fmt"
+import math "math"
+
+import (
+	context "golang.org/x/net/context"
+	grpc "google.golang.org/grpc"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
+
+// HeartbeatRecord 实例最后一次心跳的记录
+type HeartbeatRecord struct {
+	InstanceId string `protobuf:"bytes,1,opt,name=instance_id,proto3" json:"instance_id,omitempty"`
+	// server the polaris node which received the heartbeat
+	Server string `protobuf:"bytes,2,opt,name=server,proto3" json:"server,omitempty"`
+	// cur_time_sec the time of the heartbeat, unit is second
+	CurTimeSec           int64    `protobuf:"varint,3,opt,name=cur_time_sec,proto3" json:"cur_time_sec,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *HeartbeatRecord) Reset()         { *m = HeartbeatRecord{} }
+func (m *HeartbeatRecord) String() string { return proto.CompactTextString(m) }
+func (*HeartbeatRecord) ProtoMessage()    {}
+func (*HeartbeatRecord) Descriptor() ([]byte, []int) {
+	return fileDescriptor_peer_034a109da6841ac7, []int{0}
+}
+func (m *HeartbeatRecord) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_HeartbeatRecord.Unmarshal(m, b)
+}
+func (m *HeartbeatRecord) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_HeartbeatRecord.Marshal(b, m, deterministic)
+}
+func (dst *HeartbeatRecord) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_HeartbeatRecord.Merge(dst, src)
+}
+func (m *HeartbeatRecord) XXX_Size() int {
+	return xxx_messageInfo_HeartbeatRecord.Size(m)
+}
+func (m *HeartbeatRecord) XXX_DiscardUnknown() {
+	xxx_messageInfo_HeartbeatRecord.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_HeartbeatRecord proto.InternalMessageInfo
+
+func (m *HeartbeatRecord) GetInstanceId() string {
+	if m != nil {
+		return m.InstanceId
+	}
+	return ""
+}
+
+func (m *HeartbeatRecord) GetServer() string {
+	if m != nil {
+		return m.Server
+	}
+	return ""
+}
+
+func (m *HeartbeatRecord) GetCurTimeSec() int64 {
+	if m != nil {
+		return m.CurTimeSec
+	}
+	return 0
+}
+
+type SyncRequest struct {
+	// from the polaris node which sends the records
+	From                 string             `protobuf:"bytes,1,opt,name=from,proto3" json:"from,omitempty"`
+	Records              []*HeartbeatRecord `protobuf:"bytes,2,rep,name=records,proto3" json:"records,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}           `json:"-"`
+	XXX_unrecognized     []byte             `json:"-"`
+	XXX_sizecache        int32              `json:"-"`
+}
+
+func (m *SyncRequest) Reset()         { *m = SyncRequest{} }
+func (m *SyncRequest) String() string { return proto.CompactTextString(m) }
+func (*SyncRequest) ProtoMessage()    {}
+func (*SyncRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_peer_034a109da6841ac7, []int{1}
+}
+func (m *SyncRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SyncRequest.Unmarshal(m, b)
+}
+func (m *SyncRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SyncRequest.Marshal(b, m, deterministic)
+}
+func (dst *SyncRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SyncRequest.Merge(dst, src)
+}
+func (m *SyncRequest) XXX_Size() int {
+	return xxx_messageInfo_SyncRequest.Size(m)
+}
+func (m *SyncRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SyncRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SyncRequest proto.InternalMessageInfo
+
+func (m *SyncRequest) GetFrom() string {
+	if m != nil {
+		return m.From
+	}
+	return ""
+}
+
+func (m *SyncRequest) GetRecords() []*HeartbeatRecord {
+	if m != nil {
+		return m.Records
+	}
+	return nil
+}
+
+type SyncResponse struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SyncResponse) Reset()         { *m = SyncResponse{} }
+func (m *SyncResponse) String() string { return proto.CompactTextString(m) }
+func (*SyncResponse) ProtoMessage()    {}
+func (*SyncResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_peer_034a109da6841ac7, []int{2}
+}
+func (m *SyncResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SyncResponse.Unmarshal(m, b)
+}
+func (m *SyncResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SyncResponse.Marshal(b, m, deterministic)
+}
+func (dst *SyncResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SyncResponse.Merge(dst, src)
+}
+func (m *SyncResponse) XXX_Size() int {
+	return xxx_messageInfo_SyncResponse.Size(m)
+}
+func (m *SyncResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_SyncResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SyncResponse proto.InternalMessageInfo
+
+func init() {
+	proto.RegisterType((*HeartbeatRecord)(nil), "heartbeatpeer.HeartbeatRecord")
+	proto.RegisterType((*SyncRequest)(nil), "heartbeatpeer.SyncRequest")
+	proto.RegisterType((*SyncResponse)(nil), "heartbeatpeer.SyncResponse")
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// HeartbeatPeerClient is the client API for HeartbeatPeer service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type HeartbeatPeerClient interface {
+	Sync(ctx context.Context, in *SyncRequest, opts ...grpc.CallOption) (*SyncResponse, error)
+}
+
+type heartbeatPeerClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewHeartbeatPeerClient(cc *grpc.ClientConn) HeartbeatPeerClient {
+	return &heartbeatPeerClient{cc}
+}
+
+func (c *heartbeatPeerClient) Sync(ctx context.Context, in *SyncRequest, opts ...grpc.CallOption) (*SyncResponse, error) {
+	out := new(SyncResponse)
+	err := c.cc.Invoke(ctx, "/heartbeatpeer.HeartbeatPeer/Sync", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// HeartbeatPeerServer is the server API for HeartbeatPeer service.
+type HeartbeatPeerServer interface {
+	Sync(context.Context, *SyncRequest) (*SyncResponse, error)
+}
+
+func RegisterHeartbeatPeerServer(s *grpc.Server, srv HeartbeatPeerServer) {
+	s.RegisterService(&_HeartbeatPeer_serviceDesc, srv)
+}
+
+func _HeartbeatPeer_Sync_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SyncRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HeartbeatPeerServer).Sync(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/heartbeatpeer.HeartbeatPeer/Sync",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HeartbeatPeerServer).Sync(ctx, req.(*SyncRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _HeartbeatPeer_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "heartbeatpeer.HeartbeatPeer",
+	HandlerType: (*HeartbeatPeerServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Sync",
+			Handler:    _HeartbeatPeer_Sync_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "peer.proto",
+}
+
+func init() { proto.RegisterFile("peer.proto", fileDescriptor_peer_034a109da6841ac7) }
+
+var fileDescriptor_peer_034a109da6841ac7 = []byte{
+	// 219 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x6c, 0x90, 0x31, 0x4f, 0xc3, 0x30,
+	0x10, 0x85, 0x49, 0x53, 0x15, 0x71, 0x69, 0x41, 0xba, 0x01, 0x59, 0x45, 0x42, 0x96, 0xa7, 0x4c,
+	0x19, 0xca, 0xc2, 0xca, 0xc6, 0x88, 0xcc, 0xc8, 0x10, 0xa5, 0xee, 0x21, 0x32, 0xd4, 0x0e, 0x67,
+	0x07, 0x89, 0x7f, 0x8f, 0xe2, 0x24, 0x28, 0x89, 0xba, 0xd9, 0xdf, 0x7b, 0x7a, 0xfe, 0x64, 0x80,
+	0x86, 0x88, 0x8b, 0x86, 0x5d, 0x70, 0xb8, 0xfb, 0xa2, 0x8a, 0xc3, 0x91, 0xaa, 0xd0, 0x41, 0xe5,
+	0xe0, 0xee, 0x75, 0x04, 0x9a, 0x8c, 0xe3, 0x13, 0x4a, 0xc8, 0x6a, 0xeb, 0x43, 0x65, 0x0d, 0x95,
+	0xf5, 0x49, 0x24, 0x32, 0xc9, 0x6f, 0xf4, 0x14, 0xe1, 0x3d, 0x6c, 0x3c, 0xf1, 0x0f, 0xb1, 0x58,
+	0xc5, 0x70, 0xb8, 0xa1, 0x82, 0xad, 0x69, 0xb9, 0x0c, 0xf5, 0x99, 0x4a, 0x4f, 0x46, 0xa4, 0x32,
+	0xc9, 0x53, 0x3d, 0x63, 0xea, 0x03, 0xb2, 0xf7, 0x5f, 0x6b, 0x34, 0x7d, 0xb7, 0xe4, 0x03, 0x22,
+	0xac, 0x3f, 0xd9, 0x9d, 0x87, 0x57, 0xe2, 0x19, 0x9f, 0xe1, 0x9a, 0xa3, 0x8a, 0x17, 0x2b, 0x99,
+	0xe6, 0xd9, 0xe1, 0xb1, 0x98, 0x49, 0x17, 0x0b, 0x63, 0x3d, 0xd6, 0xd5, 0x2d, 0x6c, 0xfb, 0x71,
+	0xdf, 0x38, 0xeb, 0xe9, 0xa0, 0x61, 0xf7, 0xdf, 0x7d, 0x23, 0x62, 0x7c, 0x81, 0x75, 0x57, 0xc0,
+	0xfd, 0x62, 0x71, 0xa2, 0xb4, 0x7f, 0xb8, 0x98, 0xf5, 0x8b, 0xea, 0xea, 0xb8, 0x89, 0xff, 0xf8,
+	0xf4, 0x37, 0x00, 0x3a, 0x17, 0x71, 0x0c, 0x55, 0x01, 0x00, 0x00,
+}
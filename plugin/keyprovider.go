@@ -0,0 +1,56 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package plugin
+
+import (
+	"os"
+	"sync"
+
+	commonLog "github.com/polarismesh/polaris/common/log"
+)
+
+var (
+	keyProviderOnce sync.Once
+)
+
+// KeyProvider 网格证书签发使用的根证书及私钥的存储插件
+type KeyProvider interface {
+	Plugin
+	// LoadRootCA 读取 PEM 编码的根证书及私钥，尚未保存过时返回 nil
+	LoadRootCA() (certPEM []byte, keyPEM []byte, err error)
+	// SaveRootCA 保存 PEM 编码的根证书及私钥
+	SaveRootCA(certPEM []byte, keyPEM []byte) error
+}
+
+// GetKeyProvider 获取根证书存储插件，未配置时返回 nil
+func GetKeyProvider() KeyProvider {
+	c := &config.KeyProvider
+	plugin, exist := pluginSet[c.Name]
+	if !exist {
+		return nil
+	}
+
+	keyProviderOnce.Do(func() {
+		if err := plugin.Initialize(c); err != nil {
+			commonLog.GetScopeOrDefaultByName(c.Name).Errorf("plugin init err: %s", err.Error())
+			os.Exit(-1)
+		}
+	})
+
+	return plugin.(KeyProvider)
+}
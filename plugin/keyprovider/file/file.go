@@ -0,0 +1,92 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package file
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/polarismesh/polaris/plugin"
+)
+
+const (
+	// PluginName 插件名称
+	PluginName = "keyProviderFile"
+	// defaultDir 默认的根证书存放目录
+	defaultDir = "./conf/ca"
+
+	rootCertFile = "root-cert.pem"
+	rootKeyFile  = "root-key.pem"
+)
+
+// init 初始化注册函数
+func init() {
+	plugin.RegisterPlugin(PluginName, &FileKeyProvider{})
+}
+
+// FileKeyProvider 将根证书及私钥保存在本地目录中
+type FileKeyProvider struct {
+	dir string
+}
+
+// Name 返回插件名字
+func (p *FileKeyProvider) Name() string {
+	return PluginName
+}
+
+// Initialize 插件初始化，dir 为根证书存放的目录
+func (p *FileKeyProvider) Initialize(conf *plugin.ConfigEntry) error {
+	p.dir, _ = conf.Option["dir"].(string)
+	if p.dir == "" {
+		p.dir = defaultDir
+	}
+	return nil
+}
+
+// Destroy 销毁插件
+func (p *FileKeyProvider) Destroy() error {
+	return nil
+}
+
+// LoadRootCA 读取根证书及私钥，文件不存在时返回 nil
+func (p *FileKeyProvider) LoadRootCA() ([]byte, []byte, error) {
+	certPEM, err := ioutil.ReadFile(filepath.Join(p.dir, rootCertFile))
+	if os.IsNotExist(err) {
+		return nil, nil, nil
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	keyPEM, err := ioutil.ReadFile(filepath.Join(p.dir, rootKeyFile))
+	if err != nil {
+		return nil, nil, err
+	}
+	return certPEM, keyPEM, nil
+}
+
+// SaveRootCA 保存根证书及私钥，私钥文件只允许当前用户读写
+func (p *FileKeyProvider) SaveRootCA(certPEM []byte, keyPEM []byte) error {
+	if err := os.MkdirAll(p.dir, 0700); err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(filepath.Join(p.dir, rootKeyFile), keyPEM, 0600); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filepath.Join(p.dir, rootCertFile), certPEM, 0644)
+}
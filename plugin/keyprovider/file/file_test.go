@@ -0,0 +1,49 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package file
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/polarismesh/polaris/plugin"
+)
+
+func TestFileKeyProvider(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "ca")
+	p := &FileKeyProvider{}
+	assert.NoError(t, p.Initialize(&plugin.ConfigEntry{Option: map[string]interface{}{"dir": dir}}))
+
+	certPEM, keyPEM, err := p.LoadRootCA()
+	assert.NoError(t, err)
+	assert.Nil(t, certPEM)
+	assert.Nil(t, keyPEM)
+
+	assert.NoError(t, p.SaveRootCA([]byte("cert"), []byte("key")))
+	certPEM, keyPEM, err = p.LoadRootCA()
+	assert.NoError(t, err)
+	assert.Equal(t, "cert", string(certPEM))
+	assert.Equal(t, "key", string(keyPEM))
+
+	info, err := os.Stat(filepath.Join(dir, rootKeyFile))
+	assert.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+}
@@ -66,4 +66,6 @@ type Config struct {
 	Whitelist            ConfigEntry `yaml:"whitelist"`
 	MeshResourceValidate ConfigEntry `yaml:"meshResourceValidate"`
 	DiscoverEvent        ConfigEntry `yaml:"discoverEvent"`
+	Crypto               ConfigEntry `yaml:"crypto"`
+	KeyProvider          ConfigEntry `yaml:"keyProvider"`
 }
@@ -30,6 +30,9 @@ type ClientV2Server interface {
 
 	// GetCircuitBreakerWithCache Fuse configuration information for obtaining services for clients
 	GetCircuitBreakerV2WithCache(ctx context.Context, req *apiv2.Service) *apiv2.DiscoverResponse
+
+	// GetFaultMirrorWithCache Fault injection and traffic mirroring rules for obtaining services for clients
+	GetFaultMirrorWithCache(ctx context.Context, req *apiv2.Service) *apiv2.DiscoverResponse
 }
 
 // RouteRuleV2OperateServer Routing rules related operations
@@ -46,9 +49,41 @@ type RouteRuleV2OperateServer interface {
 	EnableRoutings(ctx context.Context, req []*apiv2.Routing) *apiv2.BatchWriteResponse
 }
 
+// CircuitBreakerV2OperateServer Circuit breaker rules related operations
+type CircuitBreakerV2OperateServer interface {
+	// CreateCircuitBreakersV2 Batch creation circuit breaker rules
+	CreateCircuitBreakersV2(ctx context.Context, req []*apiv2.CircuitBreaker) *apiv2.BatchWriteResponse
+	// DeleteCircuitBreakersV2 Batch delete circuit breaker rules
+	DeleteCircuitBreakersV2(ctx context.Context, req []*apiv2.CircuitBreaker) *apiv2.BatchWriteResponse
+	// UpdateCircuitBreakersV2 Batch update circuit breaker rules
+	UpdateCircuitBreakersV2(ctx context.Context, req []*apiv2.CircuitBreaker) *apiv2.BatchWriteResponse
+	// EnableCircuitBreakersV2 Batch enable or disable circuit breaker rules
+	EnableCircuitBreakersV2(ctx context.Context, req []*apiv2.CircuitBreaker) *apiv2.BatchWriteResponse
+	// GetCircuitBreakersV2 Query circuit breaker rules to OSS
+	GetCircuitBreakersV2(ctx context.Context, query map[string]string) *apiv2.BatchQueryResponse
+}
+
+// FaultMirrorOperateServer Fault injection and traffic mirroring rules related operations
+type FaultMirrorOperateServer interface {
+	// CreateFaultMirrorRules Batch creation fault mirror rules
+	CreateFaultMirrorRules(ctx context.Context, req []*apiv2.FaultMirrorRule) *apiv2.BatchWriteResponse
+	// DeleteFaultMirrorRules Batch delete fault mirror rules
+	DeleteFaultMirrorRules(ctx context.Context, req []*apiv2.FaultMirrorRule) *apiv2.BatchWriteResponse
+	// UpdateFaultMirrorRules Batch update fault mirror rules
+	UpdateFaultMirrorRules(ctx context.Context, req []*apiv2.FaultMirrorRule) *apiv2.BatchWriteResponse
+	// EnableFaultMirrorRules Batch enable or disable fault mirror rules
+	EnableFaultMirrorRules(ctx context.Context, req []*apiv2.FaultMirrorRule) *apiv2.BatchWriteResponse
+	// GetFaultMirrorRules Query fault mirror rules to OSS
+	GetFaultMirrorRules(ctx context.Context, query map[string]string) *apiv2.BatchQueryResponse
+}
+
 type DiscoverServerV2 interface {
 	// ClientV2Server
 	ClientV2Server
 	// RouteRuleV2OperateServer Routing rules operation interface definition
 	RouteRuleV2OperateServer
+	// CircuitBreakerV2OperateServer Circuit breaker rules operation interface definition
+	CircuitBreakerV2OperateServer
+	// FaultMirrorOperateServer Fault injection and traffic mirroring rules operation interface definition
+	FaultMirrorOperateServer
 }
@@ -0,0 +1,408 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package service
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/any"
+	"go.uber.org/zap"
+
+	"github.com/polarismesh/polaris/cache"
+	apiv1 "github.com/polarismesh/polaris/common/api/v1"
+	apiv2 "github.com/polarismesh/polaris/common/api/v2"
+	"github.com/polarismesh/polaris/common/model"
+	v2 "github.com/polarismesh/polaris/common/model/v2"
+	"github.com/polarismesh/polaris/common/utils"
+)
+
+var (
+	// CircuitBreakerV2FilterAttrs circuit breaker rule v2 filter attrs
+	CircuitBreakerV2FilterAttrs = map[string]bool{
+		"id":            true,
+		"name":          true,
+		"namespace":     true,
+		"src_service":   true,
+		"src_namespace": true,
+		"dst_service":   true,
+		"dst_namespace": true,
+		"enable":        true,
+		"offset":        true,
+		"limit":         true,
+	}
+)
+
+// CreateCircuitBreakersV2 批量创建熔断规则
+func (s *Server) CreateCircuitBreakersV2(ctx context.Context,
+	req []*apiv2.CircuitBreaker) *apiv2.BatchWriteResponse {
+	if err := checkBatchCircuitBreakerV2(req); err != nil {
+		return err
+	}
+
+	resp := apiv2.NewBatchWriteResponse(apiv1.ExecuteSuccess)
+	for _, entry := range req {
+		resp.Collect(s.createCircuitBreakerV2(ctx, entry))
+	}
+
+	return apiv2.FormatBatchWriteResponse(resp)
+}
+
+// createCircuitBreakerV2 创建一个熔断规则
+func (s *Server) createCircuitBreakerV2(ctx context.Context, req *apiv2.CircuitBreaker) *apiv2.Response {
+	if resp := checkCircuitBreakerV2(req); resp != nil {
+		return resp
+	}
+
+	req.Id = utils.NewRoutingV2UUID()
+	req.Revision = utils.NewV2Revision()
+	rule, err := api2CircuitBreakerV2(req)
+	if err != nil {
+		log.Error("[CircuitBreaker][V2] parse circuitbreaker rule v2 from request for create",
+			utils.ZapRequestIDByCtx(ctx), zap.Error(err))
+		return apiv2.NewResponse(apiv1.ExecuteException)
+	}
+
+	if err := s.storage.CreateCircuitBreakerRuleV2(rule); err != nil {
+		log.Error("[CircuitBreaker][V2] create circuitbreaker rule v2 store layer",
+			utils.ZapRequestIDByCtx(ctx), zap.Error(err))
+		return apiv2.NewResponse(apiv1.StoreLayerException)
+	}
+
+	s.RecordHistory(circuitBreakerV2RecordEntry(ctx, req, rule, model.OCreate))
+	return apiv2.NewCircuitBreakerResponse(apiv1.ExecuteSuccess, req)
+}
+
+// DeleteCircuitBreakersV2 批量删除熔断规则
+func (s *Server) DeleteCircuitBreakersV2(ctx context.Context,
+	req []*apiv2.CircuitBreaker) *apiv2.BatchWriteResponse {
+	if err := checkBatchCircuitBreakerV2(req); err != nil {
+		return err
+	}
+
+	out := apiv2.NewBatchWriteResponse(apiv1.ExecuteSuccess)
+	for _, entry := range req {
+		out.Collect(s.deleteCircuitBreakerV2(ctx, entry))
+	}
+
+	return apiv2.FormatBatchWriteResponse(out)
+}
+
+// deleteCircuitBreakerV2 删除一个熔断规则
+func (s *Server) deleteCircuitBreakerV2(ctx context.Context, req *apiv2.CircuitBreaker) *apiv2.Response {
+	if resp := checkCircuitBreakerIDV2(req); resp != nil {
+		return resp
+	}
+
+	if err := s.storage.DeleteCircuitBreakerRuleV2(req.GetId()); err != nil {
+		log.Error("[CircuitBreaker][V2] delete circuitbreaker rule v2 store layer",
+			utils.ZapRequestIDByCtx(ctx), zap.Error(err))
+		return apiv2.NewResponse(apiv1.StoreLayerException)
+	}
+
+	s.RecordHistory(circuitBreakerV2RecordEntry(ctx, req, nil, model.ODelete))
+	return apiv2.NewCircuitBreakerResponse(apiv1.ExecuteSuccess, req)
+}
+
+// UpdateCircuitBreakersV2 批量更新熔断规则
+func (s *Server) UpdateCircuitBreakersV2(ctx context.Context,
+	req []*apiv2.CircuitBreaker) *apiv2.BatchWriteResponse {
+	if err := checkBatchCircuitBreakerV2(req); err != nil {
+		return err
+	}
+
+	out := apiv2.NewBatchWriteResponse(apiv1.ExecuteSuccess)
+	for _, entry := range req {
+		out.Collect(s.updateCircuitBreakerV2(ctx, entry))
+	}
+
+	return apiv2.FormatBatchWriteResponse(out)
+}
+
+// updateCircuitBreakerV2 更新单个熔断规则
+func (s *Server) updateCircuitBreakerV2(ctx context.Context, req *apiv2.CircuitBreaker) *apiv2.Response {
+	if resp := checkCircuitBreakerIDV2(req); resp != nil {
+		return resp
+	}
+	if resp := checkCircuitBreakerV2(req); resp != nil {
+		return resp
+	}
+
+	rule, err := s.storage.GetCircuitBreakerRuleV2WithID(req.GetId())
+	if err != nil {
+		log.Error("[CircuitBreaker][V2] get circuitbreaker rule v2 store layer",
+			utils.ZapRequestIDByCtx(ctx), zap.Error(err))
+		return apiv2.NewResponse(apiv1.StoreLayerException)
+	}
+	if rule == nil {
+		return apiv2.NewCircuitBreakerResponse(apiv1.NotFoundCircuitBreaker, req)
+	}
+	// 熔断规则所属的命名空间不允许修改
+	if rule.Namespace != req.GetNamespace() {
+		return apiv2.NewCircuitBreakerResponse(apiv1.InvalidCircuitBreakerNamespace, req)
+	}
+
+	// 作为一个整体进行Update，所有参数都要传递
+	req.Revision = utils.NewV2Revision()
+	reqModel, err := api2CircuitBreakerV2(req)
+	if err != nil {
+		log.Error("[CircuitBreaker][V2] parse circuitbreaker rule v2 from request for update",
+			utils.ZapRequestIDByCtx(ctx), zap.Error(err))
+		return apiv2.NewResponse(apiv1.ExecuteException)
+	}
+
+	if err := s.storage.UpdateCircuitBreakerRuleV2(reqModel); err != nil {
+		log.Error("[CircuitBreaker][V2] update circuitbreaker rule v2 store layer",
+			utils.ZapRequestIDByCtx(ctx), zap.Error(err))
+		return apiv2.NewResponse(apiv1.StoreLayerException)
+	}
+
+	s.RecordHistory(circuitBreakerV2RecordEntry(ctx, req, reqModel, model.OUpdate))
+	return apiv2.NewResponse(apiv1.ExecuteSuccess)
+}
+
+// EnableCircuitBreakersV2 批量启用或者禁用熔断规则
+func (s *Server) EnableCircuitBreakersV2(ctx context.Context,
+	req []*apiv2.CircuitBreaker) *apiv2.BatchWriteResponse {
+	if err := checkBatchCircuitBreakerV2(req); err != nil {
+		return err
+	}
+
+	out := apiv2.NewBatchWriteResponse(apiv1.ExecuteSuccess)
+	for _, entry := range req {
+		out.Collect(s.enableCircuitBreakerV2(ctx, entry))
+	}
+
+	return apiv2.FormatBatchWriteResponse(out)
+}
+
+// enableCircuitBreakerV2 启用或者禁用单个熔断规则
+func (s *Server) enableCircuitBreakerV2(ctx context.Context, req *apiv2.CircuitBreaker) *apiv2.Response {
+	if resp := checkCircuitBreakerIDV2(req); resp != nil {
+		return resp
+	}
+
+	rule, err := s.storage.GetCircuitBreakerRuleV2WithID(req.GetId())
+	if err != nil {
+		log.Error("[CircuitBreaker][V2] get circuitbreaker rule v2 store layer",
+			utils.ZapRequestIDByCtx(ctx), zap.Error(err))
+		return apiv2.NewResponse(apiv1.StoreLayerException)
+	}
+	if rule == nil {
+		return apiv2.NewCircuitBreakerResponse(apiv1.NotFoundCircuitBreaker, req)
+	}
+
+	rule.Enable = req.GetEnable()
+	rule.Revision = utils.NewV2Revision()
+	if err := s.storage.EnableCircuitBreakerRuleV2(rule); err != nil {
+		log.Error("[CircuitBreaker][V2] enable circuitbreaker rule v2 store layer",
+			utils.ZapRequestIDByCtx(ctx), zap.Error(err))
+		return apiv2.NewResponse(apiv1.StoreLayerException)
+	}
+
+	s.RecordHistory(circuitBreakerV2RecordEntry(ctx, req, rule, model.OUpdate))
+	return apiv2.NewResponse(apiv1.ExecuteSuccess)
+}
+
+// GetCircuitBreakersV2 提供给控制台的查询熔断规则的接口
+func (s *Server) GetCircuitBreakersV2(ctx context.Context, query map[string]string) *apiv2.BatchQueryResponse {
+	args, resp := parseCircuitBreakerV2Args(query)
+	if resp != nil {
+		return apiv2.NewBatchQueryResponse(resp.GetCode())
+	}
+
+	total, rules := s.Cache().CircuitBreaker().QueryCircuitBreakerRulesV2(args)
+	data, err := marshalCircuitBreakerV2toAnySlice(rules)
+	if err != nil {
+		log.Error("[CircuitBreaker][V2] marshal circuitbreaker rule list to anypb.Any list",
+			utils.ZapRequestIDByCtx(ctx), zap.Error(err))
+		return apiv2.NewBatchQueryResponse(apiv1.ExecuteException)
+	}
+
+	out := apiv2.NewBatchQueryResponse(apiv1.ExecuteSuccess)
+	out.Amount = total
+	out.Size = uint32(len(rules))
+	out.Data = data
+	return out
+}
+
+// parseCircuitBreakerV2Args 解析熔断规则的查询条件
+func parseCircuitBreakerV2Args(query map[string]string) (*cache.CircuitBreakerV2Args, *apiv2.Response) {
+	offset, limit, err := utils.ParseOffsetAndLimit(query)
+	if err != nil {
+		return nil, apiv2.NewResponse(apiv1.InvalidParameter)
+	}
+
+	for key := range query {
+		if _, ok := CircuitBreakerV2FilterAttrs[key]; !ok {
+			log.Errorf("[CircuitBreaker][V2][Query] attribute(%s) is not allowed", key)
+			return nil, apiv2.NewResponse(apiv1.InvalidParameter)
+		}
+	}
+
+	args := &cache.CircuitBreakerV2Args{
+		ID:           query["id"],
+		Name:         query["name"],
+		Namespace:    query["namespace"],
+		SrcService:   query["src_service"],
+		SrcNamespace: query["src_namespace"],
+		DstService:   query["dst_service"],
+		DstNamespace: query["dst_namespace"],
+		Offset:       offset,
+		Limit:        limit,
+	}
+	if enableStr, ok := query["enable"]; ok {
+		enable, err := strconv.ParseBool(enableStr)
+		if err != nil {
+			return nil, apiv2.NewResponse(apiv1.InvalidParameter)
+		}
+		args.Enable = &enable
+	}
+	return args, nil
+}
+
+// checkBatchCircuitBreakerV2 检查批量请求
+func checkBatchCircuitBreakerV2(req []*apiv2.CircuitBreaker) *apiv2.BatchWriteResponse {
+	if len(req) == 0 {
+		return apiv2.NewBatchWriteResponse(apiv1.EmptyRequest)
+	}
+
+	if len(req) > MaxBatchSize {
+		return apiv2.NewBatchWriteResponse(apiv1.BatchSizeOverLimit)
+	}
+
+	return nil
+}
+
+// checkCircuitBreakerIDV2 检查熔断规则的ID
+func checkCircuitBreakerIDV2(req *apiv2.CircuitBreaker) *apiv2.Response {
+	if req == nil {
+		return apiv2.NewCircuitBreakerResponse(apiv1.EmptyRequest, req)
+	}
+
+	if req.GetId() == "" {
+		return apiv2.NewCircuitBreakerResponse(apiv1.InvalidCircuitBreakerID, req)
+	}
+
+	return nil
+}
+
+// checkCircuitBreakerV2 检查熔断规则基础参数以及规则内容的有效性
+func checkCircuitBreakerV2(req *apiv2.CircuitBreaker) *apiv2.Response {
+	if req == nil {
+		return apiv2.NewCircuitBreakerResponse(apiv1.EmptyRequest, req)
+	}
+
+	if req.GetName() == "" {
+		return apiv2.NewCircuitBreakerResponse(apiv1.InvalidCircuitBreakerName, req)
+	}
+	if err := utils.CheckDbStrFieldLen(utils.NewStringValue(req.GetName()), MaxDbCircuitbreakerName); err != nil {
+		return apiv2.NewCircuitBreakerResponse(apiv1.InvalidCircuitBreakerName, req)
+	}
+	if req.GetNamespace() == "" {
+		return apiv2.NewCircuitBreakerResponse(apiv1.InvalidCircuitBreakerNamespace, req)
+	}
+	if err := utils.CheckDbStrFieldLen(utils.NewStringValue(req.GetNamespace()),
+		MaxDbCircuitbreakerNamespace); err != nil {
+		return apiv2.NewCircuitBreakerResponse(apiv1.InvalidCircuitBreakerNamespace, req)
+	}
+	if err := utils.CheckDbStrFieldLen(utils.NewStringValue(req.GetDescription()),
+		MaxDbCircuitbreakerComment); err != nil {
+		return apiv2.NewCircuitBreakerResponse(apiv1.InvalidCircuitBreakerComment, req)
+	}
+
+	if req.GetLevel() == apiv2.Level_UNKNOWN {
+		return apiv2.NewCircuitBreakerResponse(apiv1.InvalidCircuitBreakerRule, req)
+	}
+
+	// 被调服务必须指定，主调服务未指定时默认匹配全部
+	dst := req.GetRuleMatcher().GetDestination()
+	if dst.GetService() == "" || dst.GetNamespace() == "" {
+		return apiv2.NewCircuitBreakerResponse(apiv1.InvalidCircuitBreakerRule, req)
+	}
+	if req.RuleMatcher.Source == nil {
+		req.RuleMatcher.Source = &apiv2.RuleMatcher_SourceService{}
+	}
+	if req.RuleMatcher.Source.Service == "" {
+		req.RuleMatcher.Source.Service = v2.MatchAll
+	}
+	if req.RuleMatcher.Source.Namespace == "" {
+		req.RuleMatcher.Source.Namespace = v2.MatchAll
+	}
+
+	for _, trigger := range req.GetTriggerCondition() {
+		if trigger.GetTriggerType() == apiv2.TriggerCondition_UNKNOWN {
+			return apiv2.NewCircuitBreakerResponse(apiv1.InvalidCircuitBreakerRule, req)
+		}
+		if trigger.GetTriggerType() == apiv2.TriggerCondition_ERROR_RATE && trigger.GetErrorPercent() > 100 {
+			return apiv2.NewCircuitBreakerResponse(apiv1.InvalidCircuitBreakerRule, req)
+		}
+	}
+	if req.GetMaxEjectionPercent() > 100 {
+		return apiv2.NewCircuitBreakerResponse(apiv1.InvalidCircuitBreakerRule, req)
+	}
+
+	return nil
+}
+
+// api2CircuitBreakerV2 把API参数转换为内部的数据结构
+func api2CircuitBreakerV2(req *apiv2.CircuitBreaker) (*v2.CircuitBreakerRule, error) {
+	out := &v2.CircuitBreakerRule{
+		Valid: true,
+	}
+	if err := out.ParseFromAPI(req); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// marshalCircuitBreakerV2toAnySlice 转换为 []*anypb.Any 数组
+func marshalCircuitBreakerV2toAnySlice(rules []*v2.ExtendCircuitBreakerRule) ([]*any.Any, error) {
+	ret := make([]*any.Any, 0, len(rules))
+	for i := range rules {
+		item, err := ptypes.MarshalAny(rules[i].ToApi())
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, item)
+	}
+	return ret, nil
+}
+
+// circuitBreakerV2RecordEntry 构建 v2 熔断规则的记录 entry
+func circuitBreakerV2RecordEntry(ctx context.Context, req *apiv2.CircuitBreaker, md *v2.CircuitBreakerRule,
+	opt model.OperationType) *model.RecordEntry {
+	entry := &model.RecordEntry{
+		ResourceType:  model.RCircuitBreakerV2,
+		OperationType: opt,
+		Namespace:     req.GetNamespace(),
+		Operator:      utils.ParseOperator(ctx),
+		CreateTime:    time.Now(),
+	}
+
+	if md != nil {
+		entry.Context = fmt.Sprintf("id:%s,name:%s,enable:%v,rule:%s,revision:%s",
+			md.ID, md.Name, md.Enable, md.Rule, md.Revision)
+	} else {
+		entry.Context = fmt.Sprintf("id:%s", req.GetId())
+	}
+	return entry
+}
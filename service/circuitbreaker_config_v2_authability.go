@@ -0,0 +1,54 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package service
+
+import (
+	"context"
+
+	apiv2 "github.com/polarismesh/polaris/common/api/v2"
+)
+
+// CreateCircuitBreakersV2 批量创建熔断规则
+func (svr *serverAuthAbility) CreateCircuitBreakersV2(ctx context.Context,
+	req []*apiv2.CircuitBreaker) *apiv2.BatchWriteResponse {
+	return svr.targetServer.CreateCircuitBreakersV2(ctx, req)
+}
+
+// DeleteCircuitBreakersV2 批量删除熔断规则
+func (svr *serverAuthAbility) DeleteCircuitBreakersV2(ctx context.Context,
+	req []*apiv2.CircuitBreaker) *apiv2.BatchWriteResponse {
+	return svr.targetServer.DeleteCircuitBreakersV2(ctx, req)
+}
+
+// UpdateCircuitBreakersV2 批量更新熔断规则
+func (svr *serverAuthAbility) UpdateCircuitBreakersV2(ctx context.Context,
+	req []*apiv2.CircuitBreaker) *apiv2.BatchWriteResponse {
+	return svr.targetServer.UpdateCircuitBreakersV2(ctx, req)
+}
+
+// EnableCircuitBreakersV2 批量启用或者禁用熔断规则
+func (svr *serverAuthAbility) EnableCircuitBreakersV2(ctx context.Context,
+	req []*apiv2.CircuitBreaker) *apiv2.BatchWriteResponse {
+	return svr.targetServer.EnableCircuitBreakersV2(ctx, req)
+}
+
+// GetCircuitBreakersV2 提供给控制台的查询熔断规则的接口
+func (svr *serverAuthAbility) GetCircuitBreakersV2(ctx context.Context,
+	query map[string]string) *apiv2.BatchQueryResponse {
+	return svr.targetServer.GetCircuitBreakersV2(ctx, query)
+}
@@ -0,0 +1,196 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	api "github.com/polarismesh/polaris/common/api/v1"
+	apiv2 "github.com/polarismesh/polaris/common/api/v2"
+	v2 "github.com/polarismesh/polaris/common/model/v2"
+	"github.com/polarismesh/polaris/common/utils"
+)
+
+func buildCircuitBreakerV2(name, dstService, dstNamespace string) *apiv2.CircuitBreaker {
+	return &apiv2.CircuitBreaker{
+		Name:      name,
+		Namespace: dstNamespace,
+		Enable:    true,
+		Level:     apiv2.Level_INSTANCE,
+		RuleMatcher: &apiv2.RuleMatcher{
+			Destination: &apiv2.RuleMatcher_DestinationService{
+				Service:   dstService,
+				Namespace: dstNamespace,
+			},
+		},
+		ErrorConditions: []*apiv2.ErrorCondition{
+			{
+				InputType: apiv2.ErrorCondition_RET_CODE,
+				Condition: &apiv2.MatchString{
+					Type:  apiv2.MatchString_IN,
+					Value: utils.NewStringValue("500,502"),
+				},
+			},
+		},
+		TriggerCondition: []*apiv2.TriggerCondition{
+			{
+				TriggerType:    apiv2.TriggerCondition_ERROR_RATE,
+				ErrorPercent:   50,
+				Interval:       30,
+				MinimumRequest: 10,
+			},
+		},
+		RecoverCondition: &apiv2.RecoverCondition{
+			SleepWindow:        60,
+			ConsecutiveSuccess: 3,
+		},
+	}
+}
+
+// TestCircuitBreakerV2 测试 v2 熔断规则的增删改查以及客户端下发
+func TestCircuitBreakerV2(t *testing.T) {
+	discoverSuit := &DiscoverTestSuit{}
+	if err := discoverSuit.initialize(); err != nil {
+		t.Fatal(err)
+	}
+	defer discoverSuit.Destroy()
+
+	_, svc := discoverSuit.createCommonService(t, 301)
+	defer discoverSuit.cleanServiceName(svc.GetName().GetValue(), svc.GetNamespace().GetValue())
+	defer discoverSuit.truncateCircuitBreakerV2()
+
+	svcName := svc.GetName().GetValue()
+	svcNamespace := svc.GetNamespace().GetValue()
+	discoverReq := &apiv2.Service{Name: svcName, Namespace: svcNamespace}
+
+	t.Run("参数非法的规则创建失败", func(t *testing.T) {
+		rule := buildCircuitBreakerV2("invalid-rule", svcName, svcNamespace)
+		rule.Level = apiv2.Level_UNKNOWN
+		resp := discoverSuit.server.CreateCircuitBreakersV2(discoverSuit.defaultCtx, []*apiv2.CircuitBreaker{rule})
+		assert.Equal(t, api.InvalidCircuitBreakerRule, resp.GetCode())
+
+		rule = buildCircuitBreakerV2("invalid-rule", "", svcNamespace)
+		resp = discoverSuit.server.CreateCircuitBreakersV2(discoverSuit.defaultCtx, []*apiv2.CircuitBreaker{rule})
+		assert.Equal(t, api.InvalidCircuitBreakerRule, resp.GetCode())
+	})
+
+	rules := []*apiv2.CircuitBreaker{
+		buildCircuitBreakerV2("cb-rule-1", svcName, svcNamespace),
+		buildCircuitBreakerV2("cb-rule-2", v2.MatchAll, svcNamespace),
+		buildCircuitBreakerV2("cb-rule-3", "other-service", svcNamespace),
+	}
+	resp := discoverSuit.server.CreateCircuitBreakersV2(discoverSuit.defaultCtx, rules)
+	if !respSuccessV2(resp) {
+		t.Fatalf("error: %+v", resp)
+	}
+	for i := range rules {
+		assert.NotEmpty(t, rules[i].GetId())
+	}
+
+	var revision string
+	t.Run("客户端获取匹配被调服务的熔断规则", func(t *testing.T) {
+		time.Sleep(discoverSuit.updateCacheInterval)
+
+		out := discoverSuit.server.GetCircuitBreakerV2WithCache(discoverSuit.defaultCtx, discoverReq)
+		if !respSuccessV2(out) {
+			t.Fatalf("error: %+v", out)
+		}
+		assert.Equal(t, apiv2.DiscoverResponse_CIRCUIT_BREAKER, out.GetType())
+		assert.Equal(t, 2, len(out.GetCircuitBreakers()))
+		assert.NotEmpty(t, out.GetService().GetRevision())
+		for _, item := range out.GetCircuitBreakers() {
+			assert.Equal(t, v2.MatchAll, item.GetRuleMatcher().GetSource().GetService())
+			assert.Equal(t, 1, len(item.GetTriggerCondition()))
+		}
+		revision = out.GetService().GetRevision()
+
+		// revision 没有变化时，不下发规则内容
+		discoverReq.Revision = revision
+		out = discoverSuit.server.GetCircuitBreakerV2WithCache(discoverSuit.defaultCtx, discoverReq)
+		assert.Equal(t, api.DataNoChange, out.GetCode())
+		assert.Equal(t, 0, len(out.GetCircuitBreakers()))
+	})
+
+	t.Run("控制台查询熔断规则", func(t *testing.T) {
+		out := discoverSuit.server.GetCircuitBreakersV2(discoverSuit.defaultCtx, map[string]string{
+			"namespace": svcNamespace,
+			"name":      "cb-rule",
+			"offset":    "0",
+			"limit":     "2",
+		})
+		if !respSuccessV2(out) {
+			t.Fatalf("error: %+v", out)
+		}
+		assert.Equal(t, uint32(3), out.GetAmount())
+		assert.Equal(t, uint32(2), out.GetSize())
+
+		out = discoverSuit.server.GetCircuitBreakersV2(discoverSuit.defaultCtx, map[string]string{
+			"service": svcName,
+		})
+		assert.Equal(t, api.InvalidParameter, out.GetCode())
+	})
+
+	t.Run("禁用、更新以及删除熔断规则后客户端感知变化", func(t *testing.T) {
+		rules[1].Enable = false
+		resp := discoverSuit.server.EnableCircuitBreakersV2(discoverSuit.defaultCtx, rules[1:2])
+		if !respSuccessV2(resp) {
+			t.Fatalf("error: %+v", resp)
+		}
+		time.Sleep(discoverSuit.updateCacheInterval)
+
+		out := discoverSuit.server.GetCircuitBreakerV2WithCache(discoverSuit.defaultCtx, discoverReq)
+		if !respSuccessV2(out) {
+			t.Fatalf("error: %+v", out)
+		}
+		assert.Equal(t, 1, len(out.GetCircuitBreakers()))
+		assert.NotEqual(t, revision, out.GetService().GetRevision())
+		discoverReq.Revision = out.GetService().GetRevision()
+
+		rules[0].MaxEjectionPercent = 30
+		resp = discoverSuit.server.UpdateCircuitBreakersV2(discoverSuit.defaultCtx, rules[0:1])
+		if !respSuccessV2(resp) {
+			t.Fatalf("error: %+v", resp)
+		}
+		time.Sleep(discoverSuit.updateCacheInterval)
+
+		out = discoverSuit.server.GetCircuitBreakerV2WithCache(discoverSuit.defaultCtx, discoverReq)
+		if !respSuccessV2(out) {
+			t.Fatalf("error: %+v", out)
+		}
+		assert.Equal(t, 1, len(out.GetCircuitBreakers()))
+		assert.Equal(t, uint32(30), out.GetCircuitBreakers()[0].GetMaxEjectionPercent())
+
+		resp = discoverSuit.server.DeleteCircuitBreakersV2(discoverSuit.defaultCtx, rules[0:1])
+		if !respSuccessV2(resp) {
+			t.Fatalf("error: %+v", resp)
+		}
+		time.Sleep(discoverSuit.updateCacheInterval)
+
+		out = discoverSuit.server.GetCircuitBreakerV2WithCache(discoverSuit.defaultCtx, discoverReq)
+		if !respSuccessV2(out) {
+			t.Fatalf("error: %+v", out)
+		}
+		assert.Equal(t, 0, len(out.GetCircuitBreakers()))
+
+		resp = discoverSuit.server.UpdateCircuitBreakersV2(discoverSuit.defaultCtx, rules[0:1])
+		assert.Equal(t, api.NotFoundCircuitBreaker, resp.GetCode())
+	})
+}
@@ -78,5 +78,88 @@ func (s *Server) GetRoutingConfigV2WithCache(ctx context.Context, req *apiv2.Ser
 
 // GetCircuitBreakerWithCache Fuse configuration information for obtaining services for clients
 func (s *Server) GetCircuitBreakerV2WithCache(ctx context.Context, req *apiv2.Service) *apiv2.DiscoverResponse {
-	return apiv2.NewDiscoverRoutingResponse(api.NotFoundService, req)
+	if s.caches == nil {
+		return apiv2.NewDiscoverCircuitBreakerResponse(api.ClientAPINotOpen, req)
+	}
+	if req == nil {
+		return apiv2.NewDiscoverCircuitBreakerResponse(api.EmptyRequest, req)
+	}
+	if req.GetName() == "" {
+		return apiv2.NewDiscoverCircuitBreakerResponse(api.InvalidServiceName, req)
+	}
+	if req.GetNamespace() == "" {
+		return apiv2.NewDiscoverCircuitBreakerResponse(api.InvalidNamespaceName, req)
+	}
+
+	// 熔断规则以被调服务进行匹配，这里需要确认服务存在
+	svc := s.getServiceCache(req.GetName(), req.GetNamespace())
+	if svc == nil {
+		return apiv2.NewDiscoverCircuitBreakerResponse(api.NotFoundService, req)
+	}
+
+	out, revision, err := s.caches.CircuitBreaker().GetCircuitBreakerRulesV2(svc.Name, svc.Namespace)
+	if err != nil {
+		log.Error("[Server][Service][CircuitBreaker] discover circuitbreaker v2", utils.ZapRequestIDByCtx(ctx),
+			zap.Error(err))
+		return apiv2.NewDiscoverCircuitBreakerResponse(api.ExecuteException, req)
+	}
+
+	resp := apiv2.NewDiscoverCircuitBreakerResponse(api.ExecuteSuccess, &apiv2.Service{
+		Name:      req.GetName(),
+		Namespace: req.GetNamespace(),
+		Revision:  revision,
+	})
+	// 规则的 revision 没有发生变化，不需要下发规则内容
+	if req.GetRevision() == revision {
+		resp.Code = api.DataNoChange
+		resp.Info = api.Code2Info(api.DataNoChange)
+		return resp
+	}
+
+	resp.CircuitBreakers = out
+	return resp
+}
+
+// GetFaultMirrorWithCache 获取服务的故障注入与流量镜像规则
+func (s *Server) GetFaultMirrorWithCache(ctx context.Context, req *apiv2.Service) *apiv2.DiscoverResponse {
+	if s.caches == nil {
+		return apiv2.NewDiscoverFaultMirrorResponse(api.ClientAPINotOpen, req)
+	}
+	if req == nil {
+		return apiv2.NewDiscoverFaultMirrorResponse(api.EmptyRequest, req)
+	}
+	if req.GetName() == "" {
+		return apiv2.NewDiscoverFaultMirrorResponse(api.InvalidServiceName, req)
+	}
+	if req.GetNamespace() == "" {
+		return apiv2.NewDiscoverFaultMirrorResponse(api.InvalidNamespaceName, req)
+	}
+
+	// 规则以被调服务进行匹配，这里需要确认服务存在
+	svc := s.getServiceCache(req.GetName(), req.GetNamespace())
+	if svc == nil {
+		return apiv2.NewDiscoverFaultMirrorResponse(api.NotFoundService, req)
+	}
+
+	out, revision, err := s.caches.FaultMirror().GetFaultMirrorRules(svc.Name, svc.Namespace)
+	if err != nil {
+		log.Error("[Server][Service][FaultMirror] discover fault mirror rules", utils.ZapRequestIDByCtx(ctx),
+			zap.Error(err))
+		return apiv2.NewDiscoverFaultMirrorResponse(api.ExecuteException, req)
+	}
+
+	resp := apiv2.NewDiscoverFaultMirrorResponse(api.ExecuteSuccess, &apiv2.Service{
+		Name:      req.GetName(),
+		Namespace: req.GetNamespace(),
+		Revision:  revision,
+	})
+	// 规则的 revision 没有发生变化，不需要下发规则内容
+	if req.GetRevision() == revision {
+		resp.Code = api.DataNoChange
+		resp.Info = api.Code2Info(api.DataNoChange)
+		return resp
+	}
+
+	resp.FaultMirrorRules = out
+	return resp
 }
@@ -32,3 +32,8 @@ func (svr *serverAuthAbility) GetRoutingConfigV2WithCache(ctx context.Context, r
 func (svr *serverAuthAbility) GetCircuitBreakerV2WithCache(ctx context.Context, req *apiv2.Service) *apiv2.DiscoverResponse {
 	return svr.targetServer.GetCircuitBreakerV2WithCache(ctx, req)
 }
+
+// GetFaultMirrorWithCache Fault injection and traffic mirroring rules for obtaining services for clients
+func (svr *serverAuthAbility) GetFaultMirrorWithCache(ctx context.Context, req *apiv2.Service) *apiv2.DiscoverResponse {
+	return svr.targetServer.GetFaultMirrorWithCache(ctx, req)
+}
@@ -0,0 +1,403 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package service
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/any"
+	"go.uber.org/zap"
+
+	"github.com/polarismesh/polaris/cache"
+	apiv1 "github.com/polarismesh/polaris/common/api/v1"
+	apiv2 "github.com/polarismesh/polaris/common/api/v2"
+	"github.com/polarismesh/polaris/common/model"
+	v2 "github.com/polarismesh/polaris/common/model/v2"
+	"github.com/polarismesh/polaris/common/utils"
+)
+
+const (
+	// maxFaultMirrorPercentage 故障注入以及流量镜像的比例上限
+	maxFaultMirrorPercentage = 100
+	minAbortHTTPStatus       = 200
+	maxAbortHTTPStatus       = 599
+)
+
+var (
+	// FaultMirrorFilterAttrs fault mirror rule filter attrs
+	FaultMirrorFilterAttrs = map[string]bool{
+		"id":        true,
+		"name":      true,
+		"namespace": true,
+		"service":   true,
+		"enable":    true,
+		"offset":    true,
+		"limit":     true,
+	}
+)
+
+// CreateFaultMirrorRules 批量创建故障注入与流量镜像规则
+func (s *Server) CreateFaultMirrorRules(ctx context.Context,
+	req []*apiv2.FaultMirrorRule) *apiv2.BatchWriteResponse {
+	if err := checkBatchFaultMirrorRules(req); err != nil {
+		return err
+	}
+
+	resp := apiv2.NewBatchWriteResponse(apiv1.ExecuteSuccess)
+	for _, entry := range req {
+		resp.Collect(s.createFaultMirrorRule(ctx, entry))
+	}
+
+	return apiv2.FormatBatchWriteResponse(resp)
+}
+
+// createFaultMirrorRule 创建一个故障注入与流量镜像规则
+func (s *Server) createFaultMirrorRule(ctx context.Context, req *apiv2.FaultMirrorRule) *apiv2.Response {
+	if resp := checkFaultMirrorRule(req); resp != nil {
+		return resp
+	}
+
+	req.Id = utils.NewRoutingV2UUID()
+	req.Revision = utils.NewV2Revision()
+	rule, err := api2FaultMirrorRule(req)
+	if err != nil {
+		log.Error("[FaultMirror] parse fault mirror rule from request for create",
+			utils.ZapRequestIDByCtx(ctx), zap.Error(err))
+		return apiv2.NewResponse(apiv1.ExecuteException)
+	}
+
+	if err := s.storage.CreateFaultMirrorRule(rule); err != nil {
+		log.Error("[FaultMirror] create fault mirror rule store layer",
+			utils.ZapRequestIDByCtx(ctx), zap.Error(err))
+		return apiv2.NewResponse(apiv1.StoreLayerException)
+	}
+
+	s.RecordHistory(faultMirrorRecordEntry(ctx, req, rule, model.OCreate))
+	return apiv2.NewFaultMirrorResponse(apiv1.ExecuteSuccess, req)
+}
+
+// DeleteFaultMirrorRules 批量删除故障注入与流量镜像规则
+func (s *Server) DeleteFaultMirrorRules(ctx context.Context,
+	req []*apiv2.FaultMirrorRule) *apiv2.BatchWriteResponse {
+	if err := checkBatchFaultMirrorRules(req); err != nil {
+		return err
+	}
+
+	out := apiv2.NewBatchWriteResponse(apiv1.ExecuteSuccess)
+	for _, entry := range req {
+		out.Collect(s.deleteFaultMirrorRule(ctx, entry))
+	}
+
+	return apiv2.FormatBatchWriteResponse(out)
+}
+
+// deleteFaultMirrorRule 删除一个故障注入与流量镜像规则
+func (s *Server) deleteFaultMirrorRule(ctx context.Context, req *apiv2.FaultMirrorRule) *apiv2.Response {
+	if resp := checkFaultMirrorRuleID(req); resp != nil {
+		return resp
+	}
+
+	if err := s.storage.DeleteFaultMirrorRule(req.GetId()); err != nil {
+		log.Error("[FaultMirror] delete fault mirror rule store layer",
+			utils.ZapRequestIDByCtx(ctx), zap.Error(err))
+		return apiv2.NewResponse(apiv1.StoreLayerException)
+	}
+
+	s.RecordHistory(faultMirrorRecordEntry(ctx, req, nil, model.ODelete))
+	return apiv2.NewFaultMirrorResponse(apiv1.ExecuteSuccess, req)
+}
+
+// UpdateFaultMirrorRules 批量更新故障注入与流量镜像规则
+func (s *Server) UpdateFaultMirrorRules(ctx context.Context,
+	req []*apiv2.FaultMirrorRule) *apiv2.BatchWriteResponse {
+	if err := checkBatchFaultMirrorRules(req); err != nil {
+		return err
+	}
+
+	out := apiv2.NewBatchWriteResponse(apiv1.ExecuteSuccess)
+	for _, entry := range req {
+		out.Collect(s.updateFaultMirrorRule(ctx, entry))
+	}
+
+	return apiv2.FormatBatchWriteResponse(out)
+}
+
+// updateFaultMirrorRule 更新单个故障注入与流量镜像规则
+func (s *Server) updateFaultMirrorRule(ctx context.Context, req *apiv2.FaultMirrorRule) *apiv2.Response {
+	if resp := checkFaultMirrorRuleID(req); resp != nil {
+		return resp
+	}
+	if resp := checkFaultMirrorRule(req); resp != nil {
+		return resp
+	}
+
+	rule, err := s.storage.GetFaultMirrorRuleWithID(req.GetId())
+	if err != nil {
+		log.Error("[FaultMirror] get fault mirror rule store layer",
+			utils.ZapRequestIDByCtx(ctx), zap.Error(err))
+		return apiv2.NewResponse(apiv1.StoreLayerException)
+	}
+	if rule == nil {
+		return apiv2.NewFaultMirrorResponse(apiv1.NotFoundResource, req)
+	}
+	// 规则所属的命名空间不允许修改
+	if rule.Namespace != req.GetNamespace() {
+		return apiv2.NewFaultMirrorResponse(apiv1.InvalidNamespaceName, req)
+	}
+
+	// 作为一个整体进行Update，所有参数都要传递
+	req.Revision = utils.NewV2Revision()
+	reqModel, err := api2FaultMirrorRule(req)
+	if err != nil {
+		log.Error("[FaultMirror] parse fault mirror rule from request for update",
+			utils.ZapRequestIDByCtx(ctx), zap.Error(err))
+		return apiv2.NewResponse(apiv1.ExecuteException)
+	}
+
+	if err := s.storage.UpdateFaultMirrorRule(reqModel); err != nil {
+		log.Error("[FaultMirror] update fault mirror rule store layer",
+			utils.ZapRequestIDByCtx(ctx), zap.Error(err))
+		return apiv2.NewResponse(apiv1.StoreLayerException)
+	}
+
+	s.RecordHistory(faultMirrorRecordEntry(ctx, req, reqModel, model.OUpdate))
+	return apiv2.NewResponse(apiv1.ExecuteSuccess)
+}
+
+// EnableFaultMirrorRules 批量启用或者禁用故障注入与流量镜像规则
+func (s *Server) EnableFaultMirrorRules(ctx context.Context,
+	req []*apiv2.FaultMirrorRule) *apiv2.BatchWriteResponse {
+	if err := checkBatchFaultMirrorRules(req); err != nil {
+		return err
+	}
+
+	out := apiv2.NewBatchWriteResponse(apiv1.ExecuteSuccess)
+	for _, entry := range req {
+		out.Collect(s.enableFaultMirrorRule(ctx, entry))
+	}
+
+	return apiv2.FormatBatchWriteResponse(out)
+}
+
+// enableFaultMirrorRule 启用或者禁用单个故障注入与流量镜像规则
+func (s *Server) enableFaultMirrorRule(ctx context.Context, req *apiv2.FaultMirrorRule) *apiv2.Response {
+	if resp := checkFaultMirrorRuleID(req); resp != nil {
+		return resp
+	}
+
+	rule, err := s.storage.GetFaultMirrorRuleWithID(req.GetId())
+	if err != nil {
+		log.Error("[FaultMirror] get fault mirror rule store layer",
+			utils.ZapRequestIDByCtx(ctx), zap.Error(err))
+		return apiv2.NewResponse(apiv1.StoreLayerException)
+	}
+	if rule == nil {
+		return apiv2.NewFaultMirrorResponse(apiv1.NotFoundResource, req)
+	}
+
+	rule.Enable = req.GetEnable()
+	rule.Revision = utils.NewV2Revision()
+	if err := s.storage.EnableFaultMirrorRule(rule); err != nil {
+		log.Error("[FaultMirror] enable fault mirror rule store layer",
+			utils.ZapRequestIDByCtx(ctx), zap.Error(err))
+		return apiv2.NewResponse(apiv1.StoreLayerException)
+	}
+
+	s.RecordHistory(faultMirrorRecordEntry(ctx, req, rule, model.OUpdate))
+	return apiv2.NewResponse(apiv1.ExecuteSuccess)
+}
+
+// GetFaultMirrorRules 提供给控制台的查询故障注入与流量镜像规则的接口
+func (s *Server) GetFaultMirrorRules(ctx context.Context, query map[string]string) *apiv2.BatchQueryResponse {
+	args, resp := parseFaultMirrorArgs(query)
+	if resp != nil {
+		return apiv2.NewBatchQueryResponse(resp.GetCode())
+	}
+
+	total, rules := s.Cache().FaultMirror().QueryFaultMirrorRules(args)
+	data, err := marshalFaultMirrorRulesToAnySlice(rules)
+	if err != nil {
+		log.Error("[FaultMirror] marshal fault mirror rule list to anypb.Any list",
+			utils.ZapRequestIDByCtx(ctx), zap.Error(err))
+		return apiv2.NewBatchQueryResponse(apiv1.ExecuteException)
+	}
+
+	out := apiv2.NewBatchQueryResponse(apiv1.ExecuteSuccess)
+	out.Amount = total
+	out.Size = uint32(len(rules))
+	out.Data = data
+	return out
+}
+
+// parseFaultMirrorArgs 解析故障注入与流量镜像规则的查询条件
+func parseFaultMirrorArgs(query map[string]string) (*cache.FaultMirrorArgs, *apiv2.Response) {
+	offset, limit, err := utils.ParseOffsetAndLimit(query)
+	if err != nil {
+		return nil, apiv2.NewResponse(apiv1.InvalidParameter)
+	}
+
+	for key := range query {
+		if _, ok := FaultMirrorFilterAttrs[key]; !ok {
+			log.Errorf("[FaultMirror][Query] attribute(%s) is not allowed", key)
+			return nil, apiv2.NewResponse(apiv1.InvalidParameter)
+		}
+	}
+
+	args := &cache.FaultMirrorArgs{
+		ID:        query["id"],
+		Name:      query["name"],
+		Namespace: query["namespace"],
+		Service:   query["service"],
+		Offset:    offset,
+		Limit:     limit,
+	}
+	if enableStr, ok := query["enable"]; ok {
+		enable, err := strconv.ParseBool(enableStr)
+		if err != nil {
+			return nil, apiv2.NewResponse(apiv1.InvalidParameter)
+		}
+		args.Enable = &enable
+	}
+	return args, nil
+}
+
+// checkBatchFaultMirrorRules 检查批量请求
+func checkBatchFaultMirrorRules(req []*apiv2.FaultMirrorRule) *apiv2.BatchWriteResponse {
+	if len(req) == 0 {
+		return apiv2.NewBatchWriteResponse(apiv1.EmptyRequest)
+	}
+
+	if len(req) > MaxBatchSize {
+		return apiv2.NewBatchWriteResponse(apiv1.BatchSizeOverLimit)
+	}
+
+	return nil
+}
+
+// checkFaultMirrorRuleID 检查故障注入与流量镜像规则的ID
+func checkFaultMirrorRuleID(req *apiv2.FaultMirrorRule) *apiv2.Response {
+	if req == nil {
+		return apiv2.NewFaultMirrorResponse(apiv1.EmptyRequest, req)
+	}
+
+	if req.GetId() == "" {
+		return apiv2.NewFaultMirrorResponse(apiv1.InvalidFaultMirrorID, req)
+	}
+
+	return nil
+}
+
+// checkFaultMirrorRule 检查故障注入与流量镜像规则基础参数以及规则内容的有效性
+func checkFaultMirrorRule(req *apiv2.FaultMirrorRule) *apiv2.Response {
+	if req == nil {
+		return apiv2.NewFaultMirrorResponse(apiv1.EmptyRequest, req)
+	}
+
+	if req.GetName() == "" {
+		return apiv2.NewFaultMirrorResponse(apiv1.InvalidFaultMirrorName, req)
+	}
+	if err := utils.CheckDbStrFieldLen(utils.NewStringValue(req.GetName()), MaxDbRoutingName); err != nil {
+		return apiv2.NewFaultMirrorResponse(apiv1.InvalidFaultMirrorName, req)
+	}
+	if req.GetNamespace() == "" {
+		return apiv2.NewFaultMirrorResponse(apiv1.InvalidNamespaceName, req)
+	}
+	if err := utils.CheckDbStrFieldLen(utils.NewStringValue(req.GetNamespace()),
+		MaxDbServiceNamespaceLength); err != nil {
+		return apiv2.NewFaultMirrorResponse(apiv1.InvalidNamespaceName, req)
+	}
+	if req.GetService() == "" {
+		return apiv2.NewFaultMirrorResponse(apiv1.InvalidServiceName, req)
+	}
+	if err := utils.CheckDbStrFieldLen(utils.NewStringValue(req.GetService()), MaxDbServiceNameLength); err != nil {
+		return apiv2.NewFaultMirrorResponse(apiv1.InvalidServiceName, req)
+	}
+
+	// 故障注入以及流量镜像至少需要配置一项
+	delay, abort := req.GetFault().GetDelay(), req.GetFault().GetAbort()
+	if delay.GetPercentage() == 0 && abort.GetPercentage() == 0 && len(req.GetMirrors()) == 0 {
+		return apiv2.NewFaultMirrorResponse(apiv1.InvalidFaultMirrorRule, req)
+	}
+	if delay.GetPercentage() > maxFaultMirrorPercentage ||
+		(delay.GetPercentage() > 0 && delay.GetFixedDelay() == 0) {
+		return apiv2.NewFaultMirrorResponse(apiv1.InvalidFaultMirrorRule, req)
+	}
+	if abort.GetPercentage() > maxFaultMirrorPercentage || (abort.GetPercentage() > 0 &&
+		(abort.GetHttpStatus() < minAbortHTTPStatus || abort.GetHttpStatus() > maxAbortHTTPStatus)) {
+		return apiv2.NewFaultMirrorResponse(apiv1.InvalidFaultMirrorRule, req)
+	}
+	for _, mirror := range req.GetMirrors() {
+		if mirror.GetService() == "" || mirror.GetPercentage() > maxFaultMirrorPercentage {
+			return apiv2.NewFaultMirrorResponse(apiv1.InvalidFaultMirrorRule, req)
+		}
+		// 镜像目标未指定命名空间时，默认与规则的命名空间一致
+		if mirror.GetNamespace() == "" {
+			mirror.Namespace = req.GetNamespace()
+		}
+	}
+
+	return nil
+}
+
+// api2FaultMirrorRule 把API参数转换为内部的数据结构
+func api2FaultMirrorRule(req *apiv2.FaultMirrorRule) (*v2.FaultMirrorRule, error) {
+	out := &v2.FaultMirrorRule{
+		Valid: true,
+	}
+	if err := out.ParseFromAPI(req); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// marshalFaultMirrorRulesToAnySlice 转换为 []*anypb.Any 数组
+func marshalFaultMirrorRulesToAnySlice(rules []*v2.ExtendFaultMirrorRule) ([]*any.Any, error) {
+	ret := make([]*any.Any, 0, len(rules))
+	for i := range rules {
+		item, err := ptypes.MarshalAny(rules[i].ToApi())
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, item)
+	}
+	return ret, nil
+}
+
+// faultMirrorRecordEntry 构建故障注入与流量镜像规则的记录 entry
+func faultMirrorRecordEntry(ctx context.Context, req *apiv2.FaultMirrorRule, md *v2.FaultMirrorRule,
+	opt model.OperationType) *model.RecordEntry {
+	entry := &model.RecordEntry{
+		ResourceType:  model.RFaultMirror,
+		OperationType: opt,
+		Namespace:     req.GetNamespace(),
+		Operator:      utils.ParseOperator(ctx),
+		CreateTime:    time.Now(),
+	}
+
+	if md != nil {
+		entry.Context = fmt.Sprintf("id:%s,name:%s,service:%s,enable:%v,rule:%s,revision:%s",
+			md.ID, md.Name, md.Service, md.Enable, md.Rule, md.Revision)
+	} else {
+		entry.Context = fmt.Sprintf("id:%s", req.GetId())
+	}
+	return entry
+}
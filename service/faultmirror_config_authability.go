@@ -0,0 +1,54 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package service
+
+import (
+	"context"
+
+	apiv2 "github.com/polarismesh/polaris/common/api/v2"
+)
+
+// CreateFaultMirrorRules 批量创建故障注入与流量镜像规则
+func (svr *serverAuthAbility) CreateFaultMirrorRules(ctx context.Context,
+	req []*apiv2.FaultMirrorRule) *apiv2.BatchWriteResponse {
+	return svr.targetServer.CreateFaultMirrorRules(ctx, req)
+}
+
+// DeleteFaultMirrorRules 批量删除故障注入与流量镜像规则
+func (svr *serverAuthAbility) DeleteFaultMirrorRules(ctx context.Context,
+	req []*apiv2.FaultMirrorRule) *apiv2.BatchWriteResponse {
+	return svr.targetServer.DeleteFaultMirrorRules(ctx, req)
+}
+
+// UpdateFaultMirrorRules 批量更新故障注入与流量镜像规则
+func (svr *serverAuthAbility) UpdateFaultMirrorRules(ctx context.Context,
+	req []*apiv2.FaultMirrorRule) *apiv2.BatchWriteResponse {
+	return svr.targetServer.UpdateFaultMirrorRules(ctx, req)
+}
+
+// EnableFaultMirrorRules 批量启用或者禁用故障注入与流量镜像规则
+func (svr *serverAuthAbility) EnableFaultMirrorRules(ctx context.Context,
+	req []*apiv2.FaultMirrorRule) *apiv2.BatchWriteResponse {
+	return svr.targetServer.EnableFaultMirrorRules(ctx, req)
+}
+
+// GetFaultMirrorRules 提供给控制台的查询故障注入与流量镜像规则的接口
+func (svr *serverAuthAbility) GetFaultMirrorRules(ctx context.Context,
+	query map[string]string) *apiv2.BatchQueryResponse {
+	return svr.targetServer.GetFaultMirrorRules(ctx, query)
+}
@@ -0,0 +1,199 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	api "github.com/polarismesh/polaris/common/api/v1"
+	apiv2 "github.com/polarismesh/polaris/common/api/v2"
+	v2 "github.com/polarismesh/polaris/common/model/v2"
+)
+
+func buildFaultMirrorRule(name, dstService, dstNamespace string) *apiv2.FaultMirrorRule {
+	return &apiv2.FaultMirrorRule{
+		Name:      name,
+		Namespace: dstNamespace,
+		Enable:    true,
+		Service:   dstService,
+		Fault: &apiv2.FaultInjection{
+			Delay: &apiv2.FaultInjection_Delay{
+				Percentage: 10,
+				FixedDelay: 200,
+			},
+			Abort: &apiv2.FaultInjection_Abort{
+				Percentage: 5,
+				HttpStatus: 503,
+			},
+		},
+		Mirrors: []*apiv2.MirrorDestination{
+			{
+				Service:    "mirror-service",
+				Percentage: 50,
+			},
+		},
+	}
+}
+
+// TestFaultMirrorRule 测试故障注入与流量镜像规则的增删改查以及客户端下发
+func TestFaultMirrorRule(t *testing.T) {
+	discoverSuit := &DiscoverTestSuit{}
+	if err := discoverSuit.initialize(); err != nil {
+		t.Fatal(err)
+	}
+	defer discoverSuit.Destroy()
+
+	_, svc := discoverSuit.createCommonService(t, 302)
+	defer discoverSuit.cleanServiceName(svc.GetName().GetValue(), svc.GetNamespace().GetValue())
+	defer discoverSuit.truncateFaultMirror()
+
+	svcName := svc.GetName().GetValue()
+	svcNamespace := svc.GetNamespace().GetValue()
+	discoverReq := &apiv2.Service{Name: svcName, Namespace: svcNamespace}
+
+	t.Run("参数非法的规则创建失败", func(t *testing.T) {
+		rule := buildFaultMirrorRule("invalid-rule", svcName, svcNamespace)
+		rule.Fault.Abort.HttpStatus = 100
+		resp := discoverSuit.server.CreateFaultMirrorRules(discoverSuit.defaultCtx, []*apiv2.FaultMirrorRule{rule})
+		assert.Equal(t, api.InvalidFaultMirrorRule, resp.GetCode())
+
+		rule = buildFaultMirrorRule("invalid-rule", svcName, svcNamespace)
+		rule.Fault, rule.Mirrors = nil, nil
+		resp = discoverSuit.server.CreateFaultMirrorRules(discoverSuit.defaultCtx, []*apiv2.FaultMirrorRule{rule})
+		assert.Equal(t, api.InvalidFaultMirrorRule, resp.GetCode())
+
+		rule = buildFaultMirrorRule("", svcName, svcNamespace)
+		resp = discoverSuit.server.CreateFaultMirrorRules(discoverSuit.defaultCtx, []*apiv2.FaultMirrorRule{rule})
+		assert.Equal(t, api.InvalidFaultMirrorName, resp.GetCode())
+	})
+
+	rules := []*apiv2.FaultMirrorRule{
+		buildFaultMirrorRule("fm-rule-1", svcName, svcNamespace),
+		buildFaultMirrorRule("fm-rule-2", v2.MatchAll, svcNamespace),
+		buildFaultMirrorRule("fm-rule-3", "other-service", svcNamespace),
+	}
+	resp := discoverSuit.server.CreateFaultMirrorRules(discoverSuit.defaultCtx, rules)
+	if !respSuccessV2(resp) {
+		t.Fatalf("error: %+v", resp)
+	}
+	for i := range rules {
+		assert.NotEmpty(t, rules[i].GetId())
+		// 镜像目标的命名空间默认与规则一致
+		assert.Equal(t, svcNamespace, rules[i].GetMirrors()[0].GetNamespace())
+	}
+
+	var revision string
+	t.Run("客户端获取匹配服务的故障注入与流量镜像规则", func(t *testing.T) {
+		time.Sleep(discoverSuit.updateCacheInterval)
+
+		out := discoverSuit.server.GetFaultMirrorWithCache(discoverSuit.defaultCtx, discoverReq)
+		if !respSuccessV2(out) {
+			t.Fatalf("error: %+v", out)
+		}
+		assert.Equal(t, apiv2.DiscoverResponse_FAULT_MIRROR, out.GetType())
+		assert.Equal(t, 2, len(out.GetFaultMirrorRules()))
+		assert.NotEmpty(t, out.GetService().GetRevision())
+		for _, item := range out.GetFaultMirrorRules() {
+			assert.Equal(t, uint32(200), item.GetFault().GetDelay().GetFixedDelay())
+			assert.Equal(t, 1, len(item.GetMirrors()))
+		}
+		revision = out.GetService().GetRevision()
+
+		// revision 没有变化时，不下发规则内容
+		discoverReq.Revision = revision
+		out = discoverSuit.server.GetFaultMirrorWithCache(discoverSuit.defaultCtx, discoverReq)
+		assert.Equal(t, api.DataNoChange, out.GetCode())
+		assert.Equal(t, 0, len(out.GetFaultMirrorRules()))
+	})
+
+	t.Run("控制台查询故障注入与流量镜像规则", func(t *testing.T) {
+		out := discoverSuit.server.GetFaultMirrorRules(discoverSuit.defaultCtx, map[string]string{
+			"namespace": svcNamespace,
+			"name":      "fm-rule",
+			"offset":    "0",
+			"limit":     "2",
+		})
+		if !respSuccessV2(out) {
+			t.Fatalf("error: %+v", out)
+		}
+		assert.Equal(t, uint32(3), out.GetAmount())
+		assert.Equal(t, uint32(2), out.GetSize())
+
+		out = discoverSuit.server.GetFaultMirrorRules(discoverSuit.defaultCtx, map[string]string{
+			"service": svcName,
+		})
+		if !respSuccessV2(out) {
+			t.Fatalf("error: %+v", out)
+		}
+		assert.Equal(t, uint32(1), out.GetAmount())
+
+		out = discoverSuit.server.GetFaultMirrorRules(discoverSuit.defaultCtx, map[string]string{
+			"source": svcName,
+		})
+		assert.Equal(t, api.InvalidParameter, out.GetCode())
+	})
+
+	t.Run("禁用、更新以及删除规则后客户端感知变化", func(t *testing.T) {
+		rules[1].Enable = false
+		resp := discoverSuit.server.EnableFaultMirrorRules(discoverSuit.defaultCtx, rules[1:2])
+		if !respSuccessV2(resp) {
+			t.Fatalf("error: %+v", resp)
+		}
+		time.Sleep(discoverSuit.updateCacheInterval)
+
+		out := discoverSuit.server.GetFaultMirrorWithCache(discoverSuit.defaultCtx, discoverReq)
+		if !respSuccessV2(out) {
+			t.Fatalf("error: %+v", out)
+		}
+		assert.Equal(t, 1, len(out.GetFaultMirrorRules()))
+		assert.NotEqual(t, revision, out.GetService().GetRevision())
+		discoverReq.Revision = out.GetService().GetRevision()
+
+		rules[0].Fault.Delay.FixedDelay = 500
+		resp = discoverSuit.server.UpdateFaultMirrorRules(discoverSuit.defaultCtx, rules[0:1])
+		if !respSuccessV2(resp) {
+			t.Fatalf("error: %+v", resp)
+		}
+		time.Sleep(discoverSuit.updateCacheInterval)
+
+		out = discoverSuit.server.GetFaultMirrorWithCache(discoverSuit.defaultCtx, discoverReq)
+		if !respSuccessV2(out) {
+			t.Fatalf("error: %+v", out)
+		}
+		assert.Equal(t, 1, len(out.GetFaultMirrorRules()))
+		assert.Equal(t, uint32(500), out.GetFaultMirrorRules()[0].GetFault().GetDelay().GetFixedDelay())
+
+		resp = discoverSuit.server.DeleteFaultMirrorRules(discoverSuit.defaultCtx, rules[0:1])
+		if !respSuccessV2(resp) {
+			t.Fatalf("error: %+v", resp)
+		}
+		time.Sleep(discoverSuit.updateCacheInterval)
+
+		out = discoverSuit.server.GetFaultMirrorWithCache(discoverSuit.defaultCtx, discoverReq)
+		if !respSuccessV2(out) {
+			t.Fatalf("error: %+v", out)
+		}
+		assert.Equal(t, 0, len(out.GetFaultMirrorRules()))
+
+		resp = discoverSuit.server.UpdateFaultMirrorRules(discoverSuit.defaultCtx, rules[0:1])
+		assert.Equal(t, api.NotFoundResource, resp.GetCode())
+	})
+}
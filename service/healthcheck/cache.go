@@ -300,6 +300,9 @@ func (c *CacheProvider) OnUpdated(value interface{}) {
 			// instance is healthy, exists, consistent healthCheckInstance.Revision(), no need to change。
 			healthCheckInstance := value.GetInstance()
 			noChanged = healthCheckInstance.Revision() == actual.Revision()
+			if !noChanged {
+				c.recordIsolateChange(healthCheckInstance, actual)
+			}
 		}
 		if !noChanged {
 			log.Infof("[Health Check][Cache]update service instance is %s:%d, id is %s",
@@ -320,6 +323,21 @@ func (c *CacheProvider) OnUpdated(value interface{}) {
 	}
 }
 
+// recordIsolateChange 实例被手动隔离或者取消隔离时，由负责检查该实例的节点记录健康状态的变化
+func (c *CacheProvider) recordIsolateChange(lastInstance *model.Instance, instance *model.Instance) {
+	if lastInstance.Isolate() == instance.Isolate() || c.svr.checkScheduler == nil {
+		return
+	}
+	if _, ok := c.svr.checkScheduler.getInstanceValue(instance.ID()); !ok {
+		return
+	}
+	reason := ReasonUnIsolate
+	if instance.Isolate() {
+		reason = ReasonIsolate
+	}
+	c.svr.recordHealthChange(instance, instance.Healthy(), instance.Isolate(), reason)
+}
+
 // OnDeleted callback when cache value deleted
 func (c *CacheProvider) OnDeleted(value interface{}) {
 	switch actual := value.(type) {
@@ -308,6 +308,10 @@ func (c *CheckScheduler) AddClient(clientWithChecker *ClientWithChecker) {
 
 func getExpireDurationSec(instance *api.Instance) uint32 {
 	ttlValue := instance.GetHealthCheck().GetHeartbeat().GetTtl().GetValue()
+	if instance.GetHealthCheck().GetType() == model.HealthCheckProbe {
+		// 主动探测每个ttl执行一次，连续失败次数由checker自己判断
+		return ttlValue
+	}
 	return expireTtlCount * ttlValue
 }
 
@@ -452,6 +456,9 @@ func (c *CheckScheduler) checkCallbackInstance(value interface{}) {
 		CurTimeSec:        currentTimeSec,
 		ExpireDurationSec: instanceValue.expireDurationSec,
 	}
+	if instanceValue.checker.Type() == plugin.HealthCheckerProbe {
+		request.Metadata = probeMetadata(cachedInstance)
+	}
 	checkResp, err = instanceValue.checker.Check(request)
 	if err != nil {
 		log.Errorf("[Health Check][Check]fail to check instance %s:%d, id is %s, err is %v",
@@ -459,7 +466,8 @@ func (c *CheckScheduler) checkCallbackInstance(value interface{}) {
 		return
 	}
 	if !checkResp.StayUnchanged {
-		code := setInsDbStatus(cachedInstance, checkResp.Healthy)
+		code := setInsDbStatus(cachedInstance, checkResp.Healthy,
+			healthChangeReason(instanceValue.checker, checkResp.Healthy))
 		if checkResp.Healthy {
 			// from unhealthy to healthy
 			log.Infof(
@@ -479,6 +487,22 @@ func (c *CheckScheduler) checkCallbackInstance(value interface{}) {
 	}
 }
 
+// probeMetadata 服务级别的探测配置作为默认值，实例上的配置优先
+func probeMetadata(instance *model.Instance) map[string]string {
+	metadata := make(map[string]string)
+	if server.serviceCache != nil {
+		if svc := server.serviceCache.GetServiceByID(instance.ServiceID); svc != nil {
+			for k, v := range svc.Meta {
+				metadata[k] = v
+			}
+		}
+	}
+	for k, v := range instance.Metadata() {
+		metadata[k] = v
+	}
+	return metadata
+}
+
 // DelInstance del instance from check
 func (c *CheckScheduler) DelClient(clientWithChecker *ClientWithChecker) {
 	client := clientWithChecker.client
@@ -503,6 +527,19 @@ func (c *CheckScheduler) DelInstance(instanceWithChecker *InstanceWithChecker) {
 	}
 }
 
+// instanceIds 当前节点负责检查的实例 ID 列表
+func (c *CheckScheduler) instanceIds() []string {
+	c.rwMutex.RLock()
+	defer c.rwMutex.RUnlock()
+	ids := make([]string, 0, len(c.scheduledInstances))
+	for id, value := range c.scheduledInstances {
+		if value.ItemType == itemTypeInstance {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
 func (c *CheckScheduler) delIfPresent(instanceId string) bool {
 	c.rwMutex.Lock()
 	defer c.rwMutex.Unlock()
@@ -512,7 +549,7 @@ func (c *CheckScheduler) delIfPresent(instanceId string) bool {
 }
 
 // setInsDbStatus 修改实例状态, 需要打印操作记录
-func setInsDbStatus(instance *model.Instance, healthStatus bool) uint32 {
+func setInsDbStatus(instance *model.Instance, healthStatus bool, reason HealthChangeReason) uint32 {
 	id := instance.ID()
 	host := instance.Host()
 	port := instance.Port()
@@ -556,6 +593,8 @@ func setInsDbStatus(instance *model.Instance, healthStatus bool) uint32 {
 		}
 
 		server.PublishDiscoverEvent(instance.ServiceID, event)
+		server.recordHealthChange(instance, healthStatus, instance.Isolate(), reason)
+		server.markHealthChanged(id, healthStatus, time.Now())
 	}
 
 	server.RecordHistory(instanceRecordEntry(recordInstance, model.OUpdate))
@@ -34,12 +34,44 @@ type Config struct {
 	ClientReportInterval time.Duration          `yaml:"clientReportInterval"`
 	Checkers             []plugin.ConfigEntry   `yaml:"checkers"`
 	Batch                map[string]interface{} `yaml:"batch"`
+	InstanceExpire       InstanceExpireConfig   `yaml:"instanceExpire"`
+	HealthHistory        HealthHistoryConfig    `yaml:"healthHistory"`
+}
+
+// HealthHistoryConfig 实例健康状态变化记录的配置，记录通过存储层持久化，所有节点共享
+type HealthHistoryConfig struct {
+	// Retention 记录的保留时长，过期的记录会被定期清理
+	Retention time.Duration `yaml:"retention"`
+	// MaxSize 单次查询最多返回的记录条数
+	MaxSize int `yaml:"maxSize"`
+}
+
+// InstanceExpireConfig 长时间不健康的实例自动反注册的配置
+type InstanceExpireConfig struct {
+	Open bool `yaml:"open"`
+	// Interval 扫描不健康实例的间隔
+	Interval time.Duration `yaml:"interval"`
+	// Duration 实例持续不健康超过该时长后会被反注册
+	Duration time.Duration `yaml:"duration"`
+	// Rules 按命名空间、服务覆盖过期时长，按顺序取第一条匹配的规则
+	Rules []InstanceExpireRule `yaml:"rules"`
+}
+
+// InstanceExpireRule 命名空间、服务级别的过期时长，为空或者 * 表示匹配全部，时长为 0 表示不过期
+type InstanceExpireRule struct {
+	Namespace string        `yaml:"namespace"`
+	Service   string        `yaml:"service"`
+	Duration  time.Duration `yaml:"duration"`
 }
 
 const (
 	minCheckInterval            = 1 * time.Second
 	maxCheckInterval            = 30 * time.Second
 	defaultClientReportInterval = 120 * time.Second
+	defaultExpireInterval       = 60 * time.Second
+	defaultExpireDuration       = 24 * time.Hour
+	defaultHistoryRetention     = 24 * time.Hour
+	defaultHistoryMaxSize       = 100000
 )
 
 // SetDefault 设置默认值
@@ -63,4 +95,30 @@ func (c *Config) SetDefault() {
 	if c.ClientReportInterval == 0 {
 		c.ClientReportInterval = defaultClientReportInterval
 	}
+	if c.InstanceExpire.Interval == 0 {
+		c.InstanceExpire.Interval = defaultExpireInterval
+	}
+	if c.InstanceExpire.Duration == 0 {
+		c.InstanceExpire.Duration = defaultExpireDuration
+	}
+	if c.HealthHistory.Retention == 0 {
+		c.HealthHistory.Retention = defaultHistoryRetention
+	}
+	if c.HealthHistory.MaxSize == 0 {
+		c.HealthHistory.MaxSize = defaultHistoryMaxSize
+	}
+}
+
+// expireDuration 获取服务下实例的过期时长，返回 0 表示不过期
+func (c *InstanceExpireConfig) expireDuration(namespace, service string) time.Duration {
+	for _, rule := range c.Rules {
+		if matchExpireRule(rule.Namespace, namespace) && matchExpireRule(rule.Service, service) {
+			return rule.Duration
+		}
+	}
+	return c.Duration
+}
+
+func matchExpireRule(expect, actual string) bool {
+	return expect == "" || expect == "*" || expect == actual
 }
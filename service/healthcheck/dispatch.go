@@ -25,6 +25,7 @@ import (
 
 	api "github.com/polarismesh/polaris/common/api/v1"
 	"github.com/polarismesh/polaris/common/model"
+	"github.com/polarismesh/polaris/plugin"
 )
 
 const (
@@ -129,9 +130,23 @@ func (d *Dispatcher) reloadSelfContinuum() bool {
 	}
 	d.selfServiceBuckets = nextBuckets
 	d.continuum = New(d.selfServiceBuckets)
+	d.notifyPeers()
 	return true
 }
 
+// notifyPeers 把健康检查的节点列表通知给需要在节点之间同步数据的 checker
+func (d *Dispatcher) notifyPeers() {
+	peers := make([]string, 0, len(d.selfServiceBuckets))
+	for bucket := range d.selfServiceBuckets {
+		peers = append(peers, bucket.Host)
+	}
+	for _, checker := range d.svr.checkers {
+		if peerChecker, ok := checker.(plugin.PeerHealthChecker); ok {
+			peerChecker.SetPeers(d.svr.localHost, peers)
+		}
+	}
+}
+
 func (d *Dispatcher) reloadManagedClients() {
 	nextClients := make(map[string]*ClientWithChecker)
 
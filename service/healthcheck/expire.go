@@ -0,0 +1,172 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package healthcheck
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	api "github.com/polarismesh/polaris/common/api/v1"
+	"github.com/polarismesh/polaris/common/model"
+	"github.com/polarismesh/polaris/plugin"
+)
+
+// instanceExpirer 定期反注册长时间不健康的心跳实例，每个节点只处理自己负责检查的实例
+type instanceExpirer struct {
+	svr  *Server
+	conf *InstanceExpireConfig
+}
+
+// startInstanceExpirer 启动实例过期的定时任务
+func startInstanceExpirer(ctx context.Context, conf *InstanceExpireConfig, svr *Server) {
+	expirer := &instanceExpirer{
+		svr:  svr,
+		conf: conf,
+	}
+	go expirer.run(ctx)
+}
+
+func (e *instanceExpirer) run(ctx context.Context) {
+	ticker := time.NewTicker(e.conf.Interval)
+	defer ticker.Stop()
+	log.Infof("[Health Check][Expire]instance expire task has been started, interval %v", e.conf.Interval)
+
+	for {
+		select {
+		case <-ticker.C:
+			e.expire(time.Now())
+		case <-ctx.Done():
+			log.Infof("[Health Check][Expire]instance expire task has been stopped")
+			return
+		}
+	}
+}
+
+// expire 遍历当前节点负责检查的实例，反注册已经过期的实例
+func (e *instanceExpirer) expire(now time.Time) {
+	ids := e.svr.checkScheduler.instanceIds()
+	scheduled := make(map[string]struct{}, len(ids))
+	for _, id := range ids {
+		scheduled[id] = struct{}{}
+		value, ok := e.svr.cacheProvider.healthCheckInstances.Load(id)
+		if !ok {
+			continue
+		}
+		instance := value.GetInstance()
+		if instance == nil || instance.HealthCheck().GetType() != api.HealthCheck_HEARTBEAT {
+			continue
+		}
+		if instance.Healthy() {
+			e.svr.unhealthyTimes.Delete(id)
+			continue
+		}
+		duration := e.conf.expireDuration(instance.Namespace(), instance.Service())
+		unhealthyTime := e.svr.unhealthyTime(id, now)
+		if !shouldExpire(instance, duration, unhealthyTime, e.lastHeartbeatSec(instance, value.GetChecker()), now) {
+			continue
+		}
+		e.deregister(instance, duration)
+	}
+	// 清理不再由当前节点负责检查的实例
+	e.svr.unhealthyTimes.Range(func(key, _ interface{}) bool {
+		if _, ok := scheduled[key.(string)]; !ok {
+			e.svr.unhealthyTimes.Delete(key)
+		}
+		return true
+	})
+}
+
+// markHealthChanged 记录实例健康状态发生变化的时间
+func (s *Server) markHealthChanged(id string, healthy bool, now time.Time) {
+	if healthy {
+		s.unhealthyTimes.Delete(id)
+		return
+	}
+	s.unhealthyTimes.Store(id, now)
+}
+
+// unhealthyTime 实例变为不健康的时间，没有记录时（例如节点重启或者实例刚转移到当前节点检查）
+// 从当前时间开始计算，宁可晚一些过期也不能提前反注册
+func (s *Server) unhealthyTime(id string, now time.Time) time.Time {
+	value, _ := s.unhealthyTimes.LoadOrStore(id, now)
+	return value.(time.Time)
+}
+
+// lastHeartbeatSec 查询实例最后一次心跳的时间，避免刚恢复心跳的实例被误删
+func (e *instanceExpirer) lastHeartbeatSec(instance *model.Instance, checker plugin.HealthChecker) int64 {
+	if checker == nil {
+		return 0
+	}
+	resp, err := checker.Query(&plugin.QueryRequest{
+		InstanceId: instance.ID(),
+		Host:       instance.Host(),
+		Port:       instance.Port(),
+	})
+	if err != nil {
+		log.Error("[Health Check][Expire]fail to query last heartbeat", zap.String("id", instance.ID()),
+			zap.Error(err))
+		// 查询失败时按照刚刚有心跳处理，等待下一轮再判断
+		return time.Now().Unix()
+	}
+	return resp.LastHeartbeatSec
+}
+
+// shouldExpire 实例不健康的时长以及距离最后一次心跳的时长都超过过期时长时，实例才会过期
+func shouldExpire(instance *model.Instance, duration time.Duration, unhealthyTime time.Time,
+	lastHeartbeatSec int64, now time.Time) bool {
+	if duration <= 0 || instance.Healthy() || unhealthyTime.IsZero() {
+		return false
+	}
+	if now.Sub(unhealthyTime) < duration {
+		return false
+	}
+	return now.Sub(time.Unix(lastHeartbeatSec, 0)) >= duration
+}
+
+// deregister 反注册过期的实例，并输出事件以及操作记录
+func (e *instanceExpirer) deregister(instance *model.Instance, duration time.Duration) {
+	log.Infof("[Health Check][Expire]instance unhealthy over %v, id is %s, address is %s:%d",
+		duration, instance.ID(), instance.Host(), instance.Port())
+
+	if e.svr.bc != nil && e.svr.bc.DeleteInstanceOpen() {
+		future := e.svr.bc.AsyncDeleteInstance(instance.Proto)
+		if err := future.Wait(); err != nil {
+			// 实例已经被删除时不需要重复输出事件以及操作记录
+			if future.Code() != api.NotFoundResource {
+				log.Error("[Health Check][Expire]fail to deregister instance", zap.String("id", instance.ID()),
+					zap.Error(err))
+			}
+			return
+		}
+	} else if err := e.svr.storage.DeleteInstance(instance.ID()); err != nil {
+		log.Error("[Health Check][Expire]fail to deregister instance", zap.String("id", instance.ID()),
+			zap.Error(err))
+		return
+	}
+
+	e.svr.PublishDiscoverEvent(instance.ServiceID, model.DiscoverEvent{
+		Namespace: instance.Namespace(),
+		Service:   instance.Service(),
+		Host:      instance.Host(),
+		Port:      int(instance.Port()),
+		EType:     model.EventInstanceExpired,
+	})
+	e.svr.RecordHistory(instanceRecordEntry(instance, model.ODelete))
+}
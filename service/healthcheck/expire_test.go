@@ -0,0 +1,87 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package healthcheck
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	api "github.com/polarismesh/polaris/common/api/v1"
+	"github.com/polarismesh/polaris/common/model"
+	"github.com/polarismesh/polaris/common/utils"
+)
+
+func TestInstanceExpireDuration(t *testing.T) {
+	conf := &InstanceExpireConfig{
+		Duration: time.Hour,
+		Rules: []InstanceExpireRule{
+			{Namespace: "Test", Service: "keep", Duration: 0},
+			{Namespace: "Test", Service: "*", Duration: time.Minute},
+			{Service: "short", Duration: time.Second},
+		},
+	}
+	assert.Equal(t, time.Duration(0), conf.expireDuration("Test", "keep"))
+	assert.Equal(t, time.Minute, conf.expireDuration("Test", "short"))
+	assert.Equal(t, time.Second, conf.expireDuration("Production", "short"))
+	assert.Equal(t, time.Hour, conf.expireDuration("Production", "other"))
+
+	hcConf := &Config{}
+	hcConf.SetDefault()
+	assert.Equal(t, defaultExpireInterval, hcConf.InstanceExpire.Interval)
+	assert.Equal(t, defaultExpireDuration, hcConf.InstanceExpire.expireDuration("Test", "svc"))
+}
+
+func TestShouldExpire(t *testing.T) {
+	now := time.Now()
+	instance := &model.Instance{
+		Proto: &api.Instance{Healthy: utils.NewBoolValue(false)},
+		// 实例的其他属性刚刚被修改过，不影响不健康时长的计算
+		ModifyTime: now,
+	}
+	unhealthyTime := now.Add(-2 * time.Hour)
+	lastHeartbeat := now.Add(-3 * time.Hour).Unix()
+
+	assert.True(t, shouldExpire(instance, time.Hour, unhealthyTime, lastHeartbeat, now))
+	assert.True(t, shouldExpire(instance, time.Hour, unhealthyTime, 0, now))
+	// 过期时长为 0 表示不过期
+	assert.False(t, shouldExpire(instance, 0, unhealthyTime, lastHeartbeat, now))
+	// 不健康的时长还没有达到过期时长
+	assert.False(t, shouldExpire(instance, 3*time.Hour, unhealthyTime, 0, now))
+	// 最近刚恢复心跳
+	assert.False(t, shouldExpire(instance, time.Hour, unhealthyTime, now.Unix(), now))
+
+	instance.Proto.Healthy = utils.NewBoolValue(true)
+	assert.False(t, shouldExpire(instance, time.Hour, unhealthyTime, lastHeartbeat, now))
+}
+
+func TestUnhealthyTime(t *testing.T) {
+	svr := &Server{}
+	now := time.Now()
+	changed := now.Add(-time.Hour)
+
+	// 没有记录状态变化时从第一次发现不健康开始计算
+	assert.Equal(t, now, svr.unhealthyTime("a", now))
+	assert.Equal(t, now, svr.unhealthyTime("a", now.Add(time.Minute)))
+
+	svr.markHealthChanged("b", false, changed)
+	assert.Equal(t, changed, svr.unhealthyTime("b", now))
+	svr.markHealthChanged("b", true, now)
+	assert.Equal(t, now, svr.unhealthyTime("b", now))
+}
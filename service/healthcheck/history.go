@@ -0,0 +1,209 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package healthcheck
+
+import (
+	"context"
+	"time"
+
+	"github.com/polarismesh/polaris/common/model"
+	"github.com/polarismesh/polaris/plugin"
+	"github.com/polarismesh/polaris/store"
+)
+
+// HealthChangeReason 实例健康状态变化的原因
+type HealthChangeReason string
+
+const (
+	// ReasonHeartbeatTimeout 心跳超时，实例变为不健康
+	ReasonHeartbeatTimeout HealthChangeReason = "HeartbeatTimeout"
+	// ReasonHeartbeatRecover 心跳恢复，实例变为健康
+	ReasonHeartbeatRecover HealthChangeReason = "HeartbeatRecover"
+	// ReasonProbeFailed 主动探测失败，实例变为不健康
+	ReasonProbeFailed HealthChangeReason = "ProbeFailed"
+	// ReasonProbeRecover 主动探测恢复，实例变为健康
+	ReasonProbeRecover HealthChangeReason = "ProbeRecover"
+	// ReasonIsolate 实例被手动隔离
+	ReasonIsolate HealthChangeReason = "Isolate"
+	// ReasonUnIsolate 实例被手动取消隔离
+	ReasonUnIsolate HealthChangeReason = "UnIsolate"
+)
+
+// HealthHistoryQuery 健康状态变化记录的查询条件，实例ID不为空时忽略命名空间以及服务
+type HealthHistoryQuery struct {
+	InstanceId string
+	Namespace  string
+	Service    string
+	// Limit 最多返回的记录数，为 0 或者超过 maxSize 时返回 maxSize 条，优先返回最新的记录
+	Limit int
+}
+
+const (
+	historyQueueSize     = 10240
+	historyBatchSize     = 256
+	historyFlushInterval = time.Second
+	historyCleanInterval = 10 * time.Minute
+)
+
+// healthHistory 将实例健康状态变化记录异步写入存储层，所有节点共享同一份记录
+type healthHistory struct {
+	storage   store.MaintainStore
+	retention time.Duration
+	maxSize   int
+	records   chan *model.HealthChangeRecord
+}
+
+func newHealthHistory(conf *HealthHistoryConfig, storage store.MaintainStore) *healthHistory {
+	return &healthHistory{
+		storage:   storage,
+		retention: conf.Retention,
+		maxSize:   conf.MaxSize,
+		records:   make(chan *model.HealthChangeRecord, historyQueueSize),
+	}
+}
+
+// start 启动记录的批量写入以及过期清理任务
+func (h *healthHistory) start(ctx context.Context) {
+	go func() {
+		flushTicker := time.NewTicker(historyFlushInterval)
+		defer flushTicker.Stop()
+		cleanTicker := time.NewTicker(historyCleanInterval)
+		defer cleanTicker.Stop()
+		for {
+			select {
+			case <-flushTicker.C:
+				h.flush()
+			case <-cleanTicker.C:
+				h.clean(time.Now())
+			case <-ctx.Done():
+				h.flush()
+				return
+			}
+		}
+	}()
+}
+
+// add 追加一条记录，队列满时丢弃，避免阻塞健康检查
+func (h *healthHistory) add(record *model.HealthChangeRecord) {
+	select {
+	case h.records <- record:
+	default:
+		log.Warnf("[Health Check][History]queue is full, drop record of instance %s", record.InstanceId)
+	}
+}
+
+// flush 将队列中的记录分批写入存储层
+func (h *healthHistory) flush() {
+	for {
+		batch := make([]*model.HealthChangeRecord, 0, historyBatchSize)
+	loop:
+		for len(batch) < historyBatchSize {
+			select {
+			case record := <-h.records:
+				batch = append(batch, record)
+			default:
+				break loop
+			}
+		}
+		if len(batch) == 0 {
+			return
+		}
+		if err := h.storage.AddHealthChangeRecords(batch); err != nil {
+			log.Errorf("[Health Check][History]save %d records, err: %s", len(batch), err.Error())
+		}
+		if len(batch) < historyBatchSize {
+			return
+		}
+	}
+}
+
+// clean 清理超出保留时长的记录
+func (h *healthHistory) clean(now time.Time) {
+	if h.retention <= 0 {
+		return
+	}
+	count, err := h.storage.CleanHealthChangeRecords(now.Add(-h.retention))
+	if err != nil {
+		log.Errorf("[Health Check][History]clean expired records, err: %s", err.Error())
+		return
+	}
+	if count > 0 {
+		log.Infof("[Health Check][History]clean %d expired records", count)
+	}
+}
+
+// query 查询保留期内满足条件的记录，按照时间倒序返回
+func (h *healthHistory) query(query *HealthHistoryQuery, now time.Time) ([]*model.HealthChangeRecord, error) {
+	filter := &model.HealthHistoryFilter{
+		InstanceId: query.InstanceId,
+		Limit:      query.Limit,
+	}
+	if filter.InstanceId == "" {
+		filter.Namespace = query.Namespace
+		filter.Service = query.Service
+	}
+	if h.retention > 0 {
+		filter.Since = now.Add(-h.retention)
+	}
+	if h.maxSize > 0 && (filter.Limit <= 0 || filter.Limit > h.maxSize) {
+		filter.Limit = h.maxSize
+	}
+	return h.storage.GetHealthChangeRecords(filter)
+}
+
+// healthChangeReason 根据检查插件的类型得到健康状态变化的原因
+func healthChangeReason(checker plugin.HealthChecker, healthy bool) HealthChangeReason {
+	if checker != nil && checker.Type() == plugin.HealthCheckerProbe {
+		if healthy {
+			return ReasonProbeRecover
+		}
+		return ReasonProbeFailed
+	}
+	if healthy {
+		return ReasonHeartbeatRecover
+	}
+	return ReasonHeartbeatTimeout
+}
+
+// recordHealthChange 记录实例健康状态的变化，healthy 以及 isolate 为变化后的状态
+func (s *Server) recordHealthChange(instance *model.Instance, healthy bool, isolate bool,
+	reason HealthChangeReason) {
+	if s.healthHistory == nil {
+		return
+	}
+	s.healthHistory.add(&model.HealthChangeRecord{
+		InstanceId: instance.ID(),
+		Namespace:  instance.Namespace(),
+		Service:    instance.Service(),
+		Host:       instance.Host(),
+		Port:       instance.Port(),
+		Healthy:    healthy,
+		Isolate:    isolate,
+		Reason:     string(reason),
+		Checker:    s.localHost,
+		CreateTime: time.Now(),
+	})
+}
+
+// GetHealthHistory 查询所有节点记录的实例健康状态变化
+func (s *Server) GetHealthHistory(query *HealthHistoryQuery) ([]*model.HealthChangeRecord, error) {
+	if s.healthHistory == nil {
+		return make([]*model.HealthChangeRecord, 0), nil
+	}
+	return s.healthHistory.query(query, time.Now())
+}
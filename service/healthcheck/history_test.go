@@ -0,0 +1,145 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package healthcheck
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	api "github.com/polarismesh/polaris/common/api/v1"
+	"github.com/polarismesh/polaris/common/model"
+	"github.com/polarismesh/polaris/common/utils"
+	"github.com/polarismesh/polaris/plugin"
+	"github.com/polarismesh/polaris/store"
+)
+
+type mockProbeChecker struct {
+	plugin.HealthChecker
+}
+
+func (m *mockProbeChecker) Type() plugin.HealthCheckType {
+	return plugin.HealthCheckerProbe
+}
+
+func TestHealthChangeReason(t *testing.T) {
+	assert.Equal(t, ReasonHeartbeatTimeout, healthChangeReason(nil, false))
+	assert.Equal(t, ReasonHeartbeatRecover, healthChangeReason(nil, true))
+	assert.Equal(t, ReasonProbeFailed, healthChangeReason(&mockProbeChecker{}, false))
+	assert.Equal(t, ReasonProbeRecover, healthChangeReason(&mockProbeChecker{}, true))
+}
+
+// fakeHistoryStore 在内存中保存健康状态变化记录的存储层
+type fakeHistoryStore struct {
+	store.MaintainStore
+	records []*model.HealthChangeRecord
+	filter  *model.HealthHistoryFilter
+	before  time.Time
+}
+
+func (f *fakeHistoryStore) AddHealthChangeRecords(records []*model.HealthChangeRecord) error {
+	f.records = append(f.records, records...)
+	return nil
+}
+
+func (f *fakeHistoryStore) GetHealthChangeRecords(
+	filter *model.HealthHistoryFilter) ([]*model.HealthChangeRecord, error) {
+	f.filter = filter
+	ret := make([]*model.HealthChangeRecord, 0, len(f.records))
+	for i := len(f.records) - 1; i >= 0; i-- {
+		if filter.InstanceId == "" || filter.InstanceId == f.records[i].InstanceId {
+			ret = append(ret, f.records[i])
+		}
+	}
+	return ret, nil
+}
+
+func (f *fakeHistoryStore) CleanHealthChangeRecords(before time.Time) (uint32, error) {
+	f.before = before
+	return 0, nil
+}
+
+func TestHealthHistory(t *testing.T) {
+	storage := &fakeHistoryStore{}
+	history := newHealthHistory(&HealthHistoryConfig{Retention: time.Hour, MaxSize: 3}, storage)
+	now := time.Now()
+
+	for i := 0; i < historyBatchSize+1; i++ {
+		history.add(&model.HealthChangeRecord{InstanceId: "ins-1", CreateTime: now})
+	}
+	// 写入前记录只在队列中
+	assert.Equal(t, 0, len(storage.records))
+	history.flush()
+	assert.Equal(t, historyBatchSize+1, len(storage.records))
+
+	// 实例ID不为空时忽略命名空间以及服务，按照保留时长以及容量限制查询
+	_, err := history.query(&HealthHistoryQuery{InstanceId: "ins-1", Namespace: "Test", Service: "svc"}, now)
+	assert.NoError(t, err)
+	assert.Equal(t, &model.HealthHistoryFilter{InstanceId: "ins-1", Since: now.Add(-time.Hour), Limit: 3},
+		storage.filter)
+
+	_, err = history.query(&HealthHistoryQuery{Namespace: "Test", Service: "svc", Limit: 1}, now)
+	assert.NoError(t, err)
+	assert.Equal(t, &model.HealthHistoryFilter{Namespace: "Test", Service: "svc",
+		Since: now.Add(-time.Hour), Limit: 1}, storage.filter)
+
+	history.clean(now)
+	assert.Equal(t, now.Add(-time.Hour), storage.before)
+}
+
+func TestHealthHistoryQueueFull(t *testing.T) {
+	storage := &fakeHistoryStore{}
+	history := newHealthHistory(&HealthHistoryConfig{Retention: time.Hour}, storage)
+	for i := 0; i < historyQueueSize+1; i++ {
+		history.add(&model.HealthChangeRecord{InstanceId: "ins-1"})
+	}
+	// 队列满时丢弃记录而不是阻塞
+	history.flush()
+	assert.Equal(t, historyQueueSize, len(storage.records))
+}
+
+func TestRecordHealthChange(t *testing.T) {
+	storage := &fakeHistoryStore{}
+	svr := &Server{
+		localHost:     "127.0.0.1",
+		healthHistory: newHealthHistory(&HealthHistoryConfig{Retention: time.Hour}, storage),
+	}
+	instance := &model.Instance{
+		Proto: &api.Instance{
+			Id:        utils.NewStringValue("ins-1"),
+			Namespace: utils.NewStringValue("Test"),
+			Service:   utils.NewStringValue("svc"),
+			Host:      utils.NewStringValue("10.0.0.1"),
+			Port:      utils.NewUInt32Value(8080),
+		},
+	}
+	svr.recordHealthChange(instance, false, false, ReasonHeartbeatTimeout)
+	svr.recordHealthChange(instance, false, true, ReasonIsolate)
+	svr.healthHistory.flush()
+
+	records, err := svr.GetHealthHistory(&HealthHistoryQuery{InstanceId: "ins-1"})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(records))
+	assert.Equal(t, string(ReasonIsolate), records[0].Reason)
+	assert.True(t, records[0].Isolate)
+	assert.Equal(t, string(ReasonHeartbeatTimeout), records[1].Reason)
+	assert.Equal(t, "127.0.0.1", records[1].Checker)
+	assert.Equal(t, "10.0.0.1", records[1].Host)
+	assert.Equal(t, uint32(8080), records[1].Port)
+}
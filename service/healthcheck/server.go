@@ -50,12 +50,15 @@ type Server struct {
 	dispatcher     *Dispatcher
 	checkScheduler *CheckScheduler
 	history        plugin.History
+	healthHistory  *healthHistory
 	discoverEvent  plugin.DiscoverChannel
 	localHost      string
 	discoverCh     chan eventWrapper
 	bc             *batch.Controller
 	serviceCache   cache.ServiceCache
 	instanceCache  cache.InstanceCache
+	// unhealthyTimes 实例 id => 实例变为不健康的时间
+	unhealthyTimes sync.Map
 }
 
 // Initialize 初始化
@@ -108,10 +111,15 @@ func initialize(ctx context.Context, hcOpt *Config, cacheOpen bool, bc *batch.Co
 	server.history = plugin.GetHistory()
 	server.discoverEvent = plugin.GetDiscoverEvent()
 
+	server.healthHistory = newHealthHistory(&hcOpt.HealthHistory, server.storage)
+	server.healthHistory.start(ctx)
 	server.cacheProvider = newCacheProvider(hcOpt.Service, server)
 	server.timeAdjuster = newTimeAdjuster(ctx, server.storage)
 	server.checkScheduler = newCheckScheduler(ctx, hcOpt.SlotNum, hcOpt.MinCheckInterval, hcOpt.MaxCheckInterval)
 	server.dispatcher = newDispatcher(ctx, server)
+	if hcOpt.InstanceExpire.Open {
+		startInstanceExpirer(ctx, &hcOpt.InstanceExpire, server)
+	}
 
 	server.discoverCh = make(chan eventWrapper, 32)
 	go server.receiveEventAndPush()
@@ -61,10 +61,15 @@ func TestInitialize(ctx context.Context, hcOpt *Config, cacheOpen bool, bc *batc
 	testServer.history = plugin.GetHistory()
 	testServer.discoverEvent = plugin.GetDiscoverEvent()
 
+	testServer.healthHistory = newHealthHistory(&hcOpt.HealthHistory, storage)
+	testServer.healthHistory.start(ctx)
 	testServer.cacheProvider = newCacheProvider(hcOpt.Service, testServer)
 	testServer.timeAdjuster = newTimeAdjuster(ctx, storage)
 	testServer.checkScheduler = newCheckScheduler(ctx, hcOpt.SlotNum, hcOpt.MinCheckInterval, hcOpt.MaxCheckInterval)
 	testServer.dispatcher = newDispatcher(ctx, testServer)
+	if hcOpt.InstanceExpire.Open {
+		startInstanceExpirer(ctx, &hcOpt.InstanceExpire, testServer)
+	}
 
 	testServer.discoverCh = make(chan eventWrapper, 32)
 	go testServer.receiveEventAndPush()
@@ -644,12 +644,13 @@ func updateHealthCheck(req *api.Instance, instance *model.Instance) bool {
 			// ttl有变更
 			needUpdate = true
 		}
-		if api.HealthCheck_HEARTBEAT != instance.HealthCheck().GetType() {
+		checkType := model.NormalizeHealthCheckType(req.GetHealthCheck().GetType())
+		if checkType != instance.HealthCheck().GetType() {
 			// health check type有变更
 			needUpdate = true
 		}
 		insProto.HealthCheck = req.GetHealthCheck()
-		insProto.HealthCheck.Type = api.HealthCheck_HEARTBEAT
+		insProto.HealthCheck.Type = checkType
 		if insProto.HealthCheck.Heartbeat.Ttl == nil {
 			insProto.HealthCheck.Heartbeat.Ttl = utils.NewUInt32Value(0)
 		}
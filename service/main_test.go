@@ -78,6 +78,8 @@ const (
 	tblCircuitBreakerRelation = "circuitbreaker_rule_relation"
 	tblNameL5                 = "l5"
 	tblNameRoutingV2          = "routing_config_v2"
+	tblCircuitBreakerV2       = "circuitbreaker_rule_v2"
+	tblFaultMirror            = "fault_mirror_rule"
 	tblClient                 = "client"
 )
 
@@ -114,9 +116,9 @@ func (d *DiscoverTestSuit) loadConfig() error {
 	d.cfg = new(TestConfig)
 
 	confFileName := testdata.Path("service_test.yaml")
-	if os.Getenv("STORE_MODE") == "sqldb" {
-		fmt.Printf("run store mode : sqldb\n")
-		confFileName = testdata.Path("service_test_sqldb.yaml")
+	if storeMode := os.Getenv("STORE_MODE"); storeMode == "sqldb" || storeMode == "pgsql" {
+		fmt.Printf("run store mode : %s\n", storeMode)
+		confFileName = testdata.Path("service_test_" + storeMode + ".yaml")
 		d.defaultCtx = context.WithValue(d.defaultCtx, utils.ContextAuthTokenKey,
 			"nu/0WRA4EqSR1FagrjRj0fZwPXuGlMpX+zCuWu4uMqy8xr1vRjisSbA25aAC3mtU8MeeRsKhQiDAynUR09I=")
 	}
@@ -279,7 +281,7 @@ func (d *DiscoverTestSuit) Destroy() {
 }
 
 func (d *DiscoverTestSuit) cleanReportClient() {
-	if d.storage.Name() == sqldb.STORENAME {
+	if sqldb.IsSqlStore(d.storage.Name()) {
 		func() {
 			tx, err := d.storage.StartTx()
 			if err != nil {
@@ -328,7 +330,7 @@ func (d *DiscoverTestSuit) cleanNamespace(name string) {
 
 	log.Infof("clean namespace: %s", name)
 
-	if d.storage.Name() == sqldb.STORENAME {
+	if sqldb.IsSqlStore(d.storage.Name()) {
 		str := "delete from namespace where name = ?"
 		func() {
 			tx, err := d.storage.StartTx()
@@ -368,7 +370,7 @@ func (d *DiscoverTestSuit) cleanNamespace(name string) {
 // 从数据库彻底删除服务
 func (d *DiscoverTestSuit) cleanService(name, namespace string) {
 
-	if d.storage.Name() == sqldb.STORENAME {
+	if sqldb.IsSqlStore(d.storage.Name()) {
 		func() {
 			tx, err := d.storage.StartTx()
 			if err != nil {
@@ -442,7 +444,7 @@ func (d *DiscoverTestSuit) cleanServiceName(name string, namespace string) {
 // clean services
 func (d *DiscoverTestSuit) cleanServices(services []*api.Service) {
 
-	if d.storage.Name() == sqldb.STORENAME {
+	if sqldb.IsSqlStore(d.storage.Name()) {
 		func() {
 			tx, err := d.storage.StartTx()
 			if err != nil {
@@ -505,7 +507,7 @@ func (d *DiscoverTestSuit) cleanInstance(instanceID string) {
 	}
 	log.Infof("clean instance: %s", instanceID)
 
-	if d.storage.Name() == sqldb.STORENAME {
+	if sqldb.IsSqlStore(d.storage.Name()) {
 		func() {
 			tx, err := d.storage.StartTx()
 			if err != nil {
@@ -990,7 +992,7 @@ func (d *DiscoverTestSuit) updateCommonRoutingConfig(t *testing.T, req *api.Rout
 // 彻底删除一个路由配置
 func (d *DiscoverTestSuit) cleanCommonRoutingConfig(service string, namespace string) {
 
-	if d.storage.Name() == sqldb.STORENAME {
+	if sqldb.IsSqlStore(d.storage.Name()) {
 		func() {
 			tx, err := d.storage.StartTx()
 			if err != nil {
@@ -1055,7 +1057,7 @@ func (d *DiscoverTestSuit) cleanCommonRoutingConfig(service string, namespace st
 }
 
 func (d *DiscoverTestSuit) truncateCommonRoutingConfigV2() {
-	if d.storage.Name() == sqldb.STORENAME {
+	if sqldb.IsSqlStore(d.storage.Name()) {
 		func() {
 			tx, err := d.storage.StartTx()
 			if err != nil {
@@ -1095,10 +1097,86 @@ func (d *DiscoverTestSuit) truncateCommonRoutingConfigV2() {
 	}
 }
 
+func (d *DiscoverTestSuit) truncateCircuitBreakerV2() {
+	if sqldb.IsSqlStore(d.storage.Name()) {
+		func() {
+			tx, err := d.storage.StartTx()
+			if err != nil {
+				panic(err)
+			}
+
+			dbTx := tx.GetDelegateTx().(*sqldb.BaseTx)
+			defer dbTx.Rollback()
+
+			if _, err := dbTx.Exec("delete from circuitbreaker_rule_v2"); err != nil {
+				panic(err)
+			}
+
+			dbTx.Commit()
+		}()
+	} else if d.storage.Name() == boltdb.STORENAME {
+		func() {
+			tx, err := d.storage.StartTx()
+			if err != nil {
+				panic(err)
+			}
+
+			dbTx := tx.GetDelegateTx().(*bolt.Tx)
+			defer dbTx.Rollback()
+
+			if err := dbTx.DeleteBucket([]byte(tblCircuitBreakerV2)); err != nil {
+				if !errors.Is(err, bolt.ErrBucketNotFound) {
+					panic(err)
+				}
+			}
+
+			dbTx.Commit()
+		}()
+	}
+}
+
+func (d *DiscoverTestSuit) truncateFaultMirror() {
+	if sqldb.IsSqlStore(d.storage.Name()) {
+		func() {
+			tx, err := d.storage.StartTx()
+			if err != nil {
+				panic(err)
+			}
+
+			dbTx := tx.GetDelegateTx().(*sqldb.BaseTx)
+			defer dbTx.Rollback()
+
+			if _, err := dbTx.Exec("delete from fault_mirror_rule"); err != nil {
+				panic(err)
+			}
+
+			dbTx.Commit()
+		}()
+	} else if d.storage.Name() == boltdb.STORENAME {
+		func() {
+			tx, err := d.storage.StartTx()
+			if err != nil {
+				panic(err)
+			}
+
+			dbTx := tx.GetDelegateTx().(*bolt.Tx)
+			defer dbTx.Rollback()
+
+			if err := dbTx.DeleteBucket([]byte(tblFaultMirror)); err != nil {
+				if !errors.Is(err, bolt.ErrBucketNotFound) {
+					panic(err)
+				}
+			}
+
+			dbTx.Commit()
+		}()
+	}
+}
+
 // 彻底删除一个路由配置
 func (d *DiscoverTestSuit) cleanCommonRoutingConfigV2(rules []*apiv2.Routing) {
 
-	if d.storage.Name() == sqldb.STORENAME {
+	if sqldb.IsSqlStore(d.storage.Name()) {
 		func() {
 			tx, err := d.storage.StartTx()
 			if err != nil {
@@ -1376,7 +1454,7 @@ func (d *DiscoverTestSuit) updateRateLimit(t *testing.T, rateLimit *api.Rule) {
 // 彻底删除限流规则
 func (d *DiscoverTestSuit) cleanRateLimit(id string) {
 
-	if d.storage.Name() == sqldb.STORENAME {
+	if sqldb.IsSqlStore(d.storage.Name()) {
 		func() {
 			tx, err := d.storage.StartTx()
 			if err != nil {
@@ -1417,7 +1495,7 @@ func (d *DiscoverTestSuit) cleanRateLimit(id string) {
 // 彻底删除限流规则版本号
 func (d *DiscoverTestSuit) cleanRateLimitRevision(service, namespace string) {
 
-	if d.storage.Name() == sqldb.STORENAME {
+	if sqldb.IsSqlStore(d.storage.Name()) {
 		func() {
 			tx, err := d.storage.StartTx()
 			if err != nil {
@@ -1767,7 +1845,7 @@ func buildCircuitBreakerKey(id, version string) string {
 func (d *DiscoverTestSuit) cleanCircuitBreaker(id, version string) {
 	log.Infof("clean circuit breaker, id: %s, version: %s", id, version)
 
-	if d.storage.Name() == sqldb.STORENAME {
+	if sqldb.IsSqlStore(d.storage.Name()) {
 		func() {
 			tx, err := d.storage.StartTx()
 			if err != nil {
@@ -1808,7 +1886,7 @@ func (d *DiscoverTestSuit) cleanCircuitBreaker(id, version string) {
 // 彻底删除熔断规则发布记录
 func (d *DiscoverTestSuit) cleanCircuitBreakerRelation(name, namespace, ruleID, ruleVersion string) {
 
-	if d.storage.Name() == sqldb.STORENAME {
+	if sqldb.IsSqlStore(d.storage.Name()) {
 		func() {
 			tx, err := d.storage.StartTx()
 			if err != nil {
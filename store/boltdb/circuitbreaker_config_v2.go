@@ -0,0 +1,204 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package boltdb
+
+import (
+	"errors"
+	"time"
+
+	"github.com/boltdb/bolt"
+
+	v2 "github.com/polarismesh/polaris/common/model/v2"
+	"github.com/polarismesh/polaris/store"
+)
+
+const (
+	tblNameCircuitBreakerV2 = "circuitbreaker_rule_v2"
+
+	cbV2FieldName         = "Name"
+	cbV2FieldEnable       = "Enable"
+	cbV2FieldLevel        = "Level"
+	cbV2FieldSrcService   = "SrcService"
+	cbV2FieldSrcNamespace = "SrcNamespace"
+	cbV2FieldDstService   = "DstService"
+	cbV2FieldDstNamespace = "DstNamespace"
+	cbV2FieldRule         = "Rule"
+	cbV2FieldRevision     = "Revision"
+	cbV2FieldDescription  = "Description"
+	cbV2FieldValid        = "Valid"
+	cbV2FieldModifyTime   = "ModifyTime"
+	cbV2FieldEnableTime   = "EnableTime"
+)
+
+type circuitBreakerStoreV2 struct {
+	handler BoltHandler
+}
+
+// CreateCircuitBreakerRuleV2 新增一个熔断规则
+func (c *circuitBreakerStoreV2) CreateCircuitBreakerRuleV2(rule *v2.CircuitBreakerRule) error {
+	if rule.ID == "" || rule.Revision == "" {
+		log.Errorf("[Store][boltdb] create circuitbreaker rule v2 missing id or revision")
+		return store.NewStatusError(store.EmptyParamsErr, "missing id or revision")
+	}
+
+	return c.handler.Execute(true, func(tx *bolt.Tx) error {
+		// 清理掉之前已经被逻辑删除的同 ID 数据
+		if err := deleteValues(tx, tblNameCircuitBreakerV2, []string{rule.ID}); err != nil {
+			log.Errorf("[Store][boltdb] delete invalid circuitbreaker rule v2 error, %v", err)
+			return err
+		}
+
+		currTime := time.Now()
+		rule.CreateTime = currTime
+		rule.ModifyTime = currTime
+		rule.Valid = true
+		if rule.Enable {
+			rule.EnableTime = currTime
+		} else {
+			rule.EnableTime = time.Time{}
+		}
+
+		if err := saveValue(tx, tblNameCircuitBreakerV2, rule.ID, rule); err != nil {
+			log.Errorf("[Store][boltdb] add circuitbreaker rule v2 to kv error, %v", err)
+			return err
+		}
+		return nil
+	})
+}
+
+// UpdateCircuitBreakerRuleV2 更新一个熔断规则
+func (c *circuitBreakerStoreV2) UpdateCircuitBreakerRuleV2(rule *v2.CircuitBreakerRule) error {
+	if rule.ID == "" || rule.Revision == "" {
+		log.Errorf("[Store][boltdb] update circuitbreaker rule v2 missing id or revision")
+		return store.NewStatusError(store.EmptyParamsErr, "missing id or revision")
+	}
+
+	properties := make(map[string]interface{})
+	properties[cbV2FieldName] = rule.Name
+	properties[cbV2FieldLevel] = rule.Level
+	properties[cbV2FieldSrcService] = rule.SrcService
+	properties[cbV2FieldSrcNamespace] = rule.SrcNamespace
+	properties[cbV2FieldDstService] = rule.DstService
+	properties[cbV2FieldDstNamespace] = rule.DstNamespace
+	properties[cbV2FieldRule] = rule.Rule
+	properties[cbV2FieldRevision] = rule.Revision
+	properties[cbV2FieldDescription] = rule.Description
+	properties[cbV2FieldModifyTime] = time.Now()
+
+	if err := c.handler.UpdateValue(tblNameCircuitBreakerV2, rule.ID, properties); err != nil {
+		log.Errorf("[Store][boltdb] update circuitbreaker rule v2 to kv error, %v", err)
+		return err
+	}
+	return nil
+}
+
+// DeleteCircuitBreakerRuleV2 删除一个熔断规则
+func (c *circuitBreakerStoreV2) DeleteCircuitBreakerRuleV2(id string) error {
+	if id == "" {
+		log.Errorf("[Store][boltdb] delete circuitbreaker rule v2 missing id")
+		return store.NewStatusError(store.EmptyParamsErr, "missing id")
+	}
+
+	properties := make(map[string]interface{})
+	properties[cbV2FieldValid] = false
+	properties[cbV2FieldModifyTime] = time.Now()
+
+	if err := c.handler.UpdateValue(tblNameCircuitBreakerV2, id, properties); err != nil {
+		log.Errorf("[Store][boltdb] delete circuitbreaker rule v2 error, %v", err)
+		return err
+	}
+	return nil
+}
+
+// EnableCircuitBreakerRuleV2 设置熔断规则是否启用
+func (c *circuitBreakerStoreV2) EnableCircuitBreakerRuleV2(rule *v2.CircuitBreakerRule) error {
+	if rule.ID == "" || rule.Revision == "" {
+		return errors.New("[Store][boltdb] enable circuitbreaker rule v2 missing some params")
+	}
+
+	if rule.Enable {
+		rule.EnableTime = time.Now()
+	} else {
+		rule.EnableTime = time.Time{}
+	}
+
+	properties := make(map[string]interface{})
+	properties[cbV2FieldEnable] = rule.Enable
+	properties[cbV2FieldEnableTime] = rule.EnableTime
+	properties[cbV2FieldRevision] = rule.Revision
+	properties[cbV2FieldModifyTime] = time.Now()
+
+	if err := c.handler.UpdateValue(tblNameCircuitBreakerV2, rule.ID, properties); err != nil {
+		log.Errorf("[Store][boltdb] enable circuitbreaker rule v2 error, %v", err)
+		return err
+	}
+	return nil
+}
+
+// GetCircuitBreakerRuleV2WithID 根据规则ID拉取熔断规则
+func (c *circuitBreakerStoreV2) GetCircuitBreakerRuleV2WithID(id string) (*v2.CircuitBreakerRule, error) {
+	ret, err := c.handler.LoadValues(tblNameCircuitBreakerV2, []string{id}, &v2.CircuitBreakerRule{})
+	if err != nil {
+		log.Errorf("[Store][boltdb] load circuitbreaker rule v2 from kv error, %v", err)
+		return nil, err
+	}
+	val, ok := ret[id]
+	if !ok {
+		return nil, nil
+	}
+	rule := val.(*v2.CircuitBreakerRule)
+	if !rule.Valid {
+		return nil, nil
+	}
+	return rule, nil
+}
+
+// GetCircuitBreakerRulesV2ForCache 通过mtime拉取增量的熔断规则信息
+// 此方法用于 cache 增量更新，需要注意 mtime 应为数据库时间戳
+func (c *circuitBreakerStoreV2) GetCircuitBreakerRulesV2ForCache(
+	mtime time.Time, firstUpdate bool) ([]*v2.CircuitBreakerRule, error) {
+	if firstUpdate {
+		mtime = time.Time{}
+	}
+
+	fields := []string{cbV2FieldModifyTime, cbV2FieldValid}
+	values, err := c.handler.LoadValuesByFilter(tblNameCircuitBreakerV2, fields, &v2.CircuitBreakerRule{},
+		func(m map[string]interface{}) bool {
+			if firstUpdate {
+				valid, _ := m[cbV2FieldValid].(bool)
+				if !valid {
+					return false
+				}
+			}
+			rMtime, ok := m[cbV2FieldModifyTime]
+			if !ok {
+				return false
+			}
+			return !rMtime.(time.Time).Before(mtime)
+		})
+	if err != nil {
+		log.Errorf("[Store][boltdb] load circuitbreaker rules v2 from kv error, %v", err)
+		return nil, err
+	}
+
+	out := make([]*v2.CircuitBreakerRule, 0, len(values))
+	for _, v := range values {
+		out = append(out, v.(*v2.CircuitBreakerRule))
+	}
+	return out, nil
+}
@@ -18,6 +18,9 @@
 package boltdb
 
 import (
+	"io"
+	"os"
+	"path/filepath"
 	"time"
 
 	"github.com/boltdb/bolt"
@@ -57,18 +60,24 @@ type boltStore struct {
 	*configFileGroupStore
 	*configFileStore
 	*configFileReleaseStore
+	*configFileGrayReleaseStore
 	*configFileReleaseHistoryStore
 	*configFileTagStore
 	*configFileTemplateStore
 
 	// v2 存储
 	*routingStoreV2
+	*circuitBreakerStoreV2
+	*faultMirrorStore
 
 	// maintain store
 	*maintainStore
 
 	handler BoltHandler
-	start   bool
+	path    string
+	// fixedInitToken 初始化数据使用固定的 token
+	fixedInitToken bool
+	start          bool
 }
 
 // Name store name
@@ -88,6 +97,8 @@ func (m *boltStore) Initialize(c *store.Config) error {
 		return err
 	}
 	m.handler = handler
+	m.path = boltConfig.FileName
+	m.fixedInitToken = boltConfig.FixedInitToken
 	if err = m.newStore(); err != nil {
 		_ = handler.Close()
 		return err
@@ -117,6 +128,14 @@ var (
 		"polaris.checker": "fbca9bfa04ae4ead86e1ecf5811e32a9",
 	}
 
+	// fixedInitTokens 开启 fixedInitToken 时使用的 token，与 sqldb 初始化脚本保持一致，保证每个节点初始化得到相同的数据
+	fixedInitTokens = map[string]string{
+		"default":                  "e2e473081d3d4306b52264e49f7ce227",
+		namespacePolaris:           "2d1bfe5d12e04d54b8ee69e62494c7fd",
+		"polaris.checker":          "7d19c46de327408d8709ee7392b7700b",
+		"polaris.checker.revision": "301b1e9f0bbd47a6b697e26e99dfe012",
+	}
+
 	mainUser = &model.User{
 		ID:          "65e4789a6d5b49669adf1e9e8387549c",
 		Name:        "polaris",
@@ -172,12 +191,20 @@ var (
 	}
 )
 
+// initToken 获取初始化数据的 token，未开启 fixedInitToken 时随机生成
+func (m *boltStore) initToken(key string) string {
+	if m.fixedInitToken {
+		return fixedInitTokens[key]
+	}
+	return utils.NewUUID()
+}
+
 func (m *boltStore) initNamingStoreData() error {
 	for _, namespace := range namespacesToInit {
 		curTime := time.Now()
 		err := m.AddNamespace(&model.Namespace{
 			Name:       namespace,
-			Token:      utils.NewUUID(),
+			Token:      m.initToken(namespace),
 			Owner:      ownerToInit,
 			Valid:      true,
 			CreateTime: curTime,
@@ -193,9 +220,9 @@ func (m *boltStore) initNamingStoreData() error {
 			ID:         id,
 			Name:       svc,
 			Namespace:  namespacePolaris,
-			Token:      utils.NewUUID(),
+			Token:      m.initToken(svc),
 			Owner:      ownerToInit,
-			Revision:   utils.NewUUID(),
+			Revision:   m.initToken(svc + ".revision"),
 			Valid:      true,
 			CreateTime: curTime,
 			ModifyTime: curTime,
@@ -284,6 +311,10 @@ func (m *boltStore) newDiscoverModuleStore() error {
 
 	m.routingStoreV2 = &routingStoreV2{handler: m.handler}
 
+	m.circuitBreakerStoreV2 = &circuitBreakerStoreV2{handler: m.handler}
+
+	m.faultMirrorStore = &faultMirrorStore{handler: m.handler}
+
 	return nil
 }
 
@@ -325,6 +356,11 @@ func (m *boltStore) newConfigModuleStore() error {
 		return err
 	}
 
+	m.configFileGrayReleaseStore, err = newConfigFileGrayReleaseStore(m.handler)
+	if err != nil {
+		return err
+	}
+
 	m.configFileTemplateStore, err = newConfigFileTemplateStore(m.handler)
 	if err != nil {
 		return err
@@ -334,7 +370,11 @@ func (m *boltStore) newConfigModuleStore() error {
 }
 
 func (m *boltStore) newMaintainModuleStore() error {
-	m.maintainStore = &maintainStore{handler: m.handler}
+	var err error
+	m.maintainStore, err = newMaintainStore(m.handler)
+	if err != nil {
+		return err
+	}
 
 	return nil
 }
@@ -358,7 +398,104 @@ func (m *boltStore) StartTx() (store.Tx, error) {
 	return m.handler.StartTx()
 }
 
+// NewStore 创建一个新的 boltdb 存储对象，供需要在本地文件之上自行组织数据的存储插件使用
+func NewStore() store.Store {
+	return &boltStore{}
+}
+
+// WriteTo 将已经提交的数据完整写出为一个 boltdb 文件
+func (m *boltStore) WriteTo(w io.Writer) (int64, error) {
+	var n int64
+	err := m.handler.Execute(false, func(tx *bolt.Tx) error {
+		var err error
+		n, err = tx.WriteTo(w)
+		return err
+	})
+	return n, err
+}
+
+// ReadFrom 使用 WriteTo 写出的 boltdb 文件替换本地数据，不会重新写入初始化数据
+func (m *boltStore) ReadFrom(r io.Reader) (int64, error) {
+	tmpPath := m.path + ".restore"
+	file, err := os.OpenFile(tmpPath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return 0, err
+	}
+	n, err := io.Copy(file, r)
+	if err != nil {
+		_ = file.Close()
+		_ = os.Remove(tmpPath)
+		return n, err
+	}
+	if err = file.Close(); err != nil {
+		return n, err
+	}
+	if err = m.handler.Replace(tmpPath); err != nil {
+		return n, err
+	}
+	return n, m.reloadIDs()
+}
+
+// Snapshot 将已经提交的数据写出到临时文件，并在其之上打开只读的数据视图
+func (m *boltStore) Snapshot() (store.Store, func(), error) {
+	file, err := os.CreateTemp(filepath.Dir(m.path), filepath.Base(m.path)+".snapshot-*")
+	if err != nil {
+		return nil, nil, err
+	}
+	tmpPath := file.Name()
+	if _, err = m.WriteTo(file); err != nil {
+		_ = file.Close()
+		_ = os.Remove(tmpPath)
+		return nil, nil, err
+	}
+	if err = file.Close(); err != nil {
+		_ = os.Remove(tmpPath)
+		return nil, nil, err
+	}
+
+	handler, err := NewBoltHandler(&BoltConfig{FileName: tmpPath})
+	if err != nil {
+		_ = os.Remove(tmpPath)
+		return nil, nil, err
+	}
+	view := &boltStore{handler: handler, path: tmpPath}
+	release := func() {
+		_ = handler.Close()
+		_ = os.Remove(tmpPath)
+	}
+	if err = view.newStore(); err != nil {
+		release()
+		return nil, nil, err
+	}
+	return view, release, nil
+}
+
+// reloadIDs 数据替换后重新加载各个表的自增 id
+func (m *boltStore) reloadIDs() error {
+	ids := map[string]*uint64{
+		tblConfigFileID:               &m.configFileStore.id,
+		tbleConfigFileTagID:           &m.configFileTagStore.id,
+		tblConfigFileGroupID:          &m.configFileGroupStore.id,
+		tblConfigFileReleaseHistoryID: &m.configFileReleaseHistoryStore.id,
+		tblConfigFileReleaseID:        &m.configFileReleaseStore.id,
+		tblConfigFileGrayReleaseID:    &m.configFileGrayReleaseStore.id,
+		tblConfigFileTemplateID:       &m.configFileTemplateStore.id,
+		tblHealthChangeRecordID:       &m.maintainStore.id,
+	}
+	for tbl, id := range ids {
+		ret, err := m.handler.LoadValues(tbl, []string{tbl}, &IDHolder{})
+		if err != nil {
+			return err
+		}
+		*id = 0
+		if val, ok := ret[tbl]; ok {
+			*id = val.(*IDHolder).ID
+		}
+	}
+	return nil
+}
+
 func init() {
-	s := &boltStore{}
-	_ = store.RegisterStore(s)
+	_ = store.RegisterStore(NewStore())
+	store.RegisterStoreFactory(STORENAME, NewStore)
 }
@@ -0,0 +1,52 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package boltdb
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/polarismesh/polaris/common/model"
+)
+
+func TestBoltStore_Snapshot(t *testing.T) {
+	s := newRestoreTestStore(t)
+	assert.NoError(t, s.AddNamespace(&model.Namespace{Name: "before", Token: "token", Owner: "polaris"}))
+
+	view, release, err := s.Snapshot()
+	assert.NoError(t, err)
+	viewPath := view.(*boltStore).path
+
+	// 创建视图之后的写入对视图不可见
+	assert.NoError(t, s.AddNamespace(&model.Namespace{Name: "after", Token: "token", Owner: "polaris"}))
+	ns, err := view.GetNamespace("before")
+	assert.NoError(t, err)
+	assert.NotNil(t, ns)
+	ns, err = view.GetNamespace("after")
+	assert.NoError(t, err)
+	assert.Nil(t, ns)
+
+	release()
+	_, err = os.Stat(viewPath)
+	assert.True(t, os.IsNotExist(err))
+	ns, err = s.GetNamespace("after")
+	assert.NoError(t, err)
+	assert.NotNil(t, ns)
+}
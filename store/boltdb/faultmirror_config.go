@@ -0,0 +1,196 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package boltdb
+
+import (
+	"errors"
+	"time"
+
+	"github.com/boltdb/bolt"
+
+	v2 "github.com/polarismesh/polaris/common/model/v2"
+	"github.com/polarismesh/polaris/store"
+)
+
+const (
+	tblNameFaultMirror = "fault_mirror_rule"
+
+	fmFieldName        = "Name"
+	fmFieldEnable      = "Enable"
+	fmFieldService     = "Service"
+	fmFieldRule        = "Rule"
+	fmFieldRevision    = "Revision"
+	fmFieldDescription = "Description"
+	fmFieldValid       = "Valid"
+	fmFieldModifyTime  = "ModifyTime"
+	fmFieldEnableTime  = "EnableTime"
+)
+
+type faultMirrorStore struct {
+	handler BoltHandler
+}
+
+// CreateFaultMirrorRule 新增一个故障注入与流量镜像规则
+func (f *faultMirrorStore) CreateFaultMirrorRule(rule *v2.FaultMirrorRule) error {
+	if rule.ID == "" || rule.Revision == "" {
+		log.Errorf("[Store][boltdb] create fault mirror rule missing id or revision")
+		return store.NewStatusError(store.EmptyParamsErr, "missing id or revision")
+	}
+
+	return f.handler.Execute(true, func(tx *bolt.Tx) error {
+		// 清理掉之前已经被逻辑删除的同 ID 数据
+		if err := deleteValues(tx, tblNameFaultMirror, []string{rule.ID}); err != nil {
+			log.Errorf("[Store][boltdb] delete invalid fault mirror rule error, %v", err)
+			return err
+		}
+
+		currTime := time.Now()
+		rule.CreateTime = currTime
+		rule.ModifyTime = currTime
+		rule.Valid = true
+		if rule.Enable {
+			rule.EnableTime = currTime
+		} else {
+			rule.EnableTime = time.Time{}
+		}
+
+		if err := saveValue(tx, tblNameFaultMirror, rule.ID, rule); err != nil {
+			log.Errorf("[Store][boltdb] add fault mirror rule to kv error, %v", err)
+			return err
+		}
+		return nil
+	})
+}
+
+// UpdateFaultMirrorRule 更新一个故障注入与流量镜像规则
+func (f *faultMirrorStore) UpdateFaultMirrorRule(rule *v2.FaultMirrorRule) error {
+	if rule.ID == "" || rule.Revision == "" {
+		log.Errorf("[Store][boltdb] update fault mirror rule missing id or revision")
+		return store.NewStatusError(store.EmptyParamsErr, "missing id or revision")
+	}
+
+	properties := make(map[string]interface{})
+	properties[fmFieldName] = rule.Name
+	properties[fmFieldService] = rule.Service
+	properties[fmFieldRule] = rule.Rule
+	properties[fmFieldRevision] = rule.Revision
+	properties[fmFieldDescription] = rule.Description
+	properties[fmFieldModifyTime] = time.Now()
+
+	if err := f.handler.UpdateValue(tblNameFaultMirror, rule.ID, properties); err != nil {
+		log.Errorf("[Store][boltdb] update fault mirror rule to kv error, %v", err)
+		return err
+	}
+	return nil
+}
+
+// DeleteFaultMirrorRule 删除一个故障注入与流量镜像规则
+func (f *faultMirrorStore) DeleteFaultMirrorRule(id string) error {
+	if id == "" {
+		log.Errorf("[Store][boltdb] delete fault mirror rule missing id")
+		return store.NewStatusError(store.EmptyParamsErr, "missing id")
+	}
+
+	properties := make(map[string]interface{})
+	properties[fmFieldValid] = false
+	properties[fmFieldModifyTime] = time.Now()
+
+	if err := f.handler.UpdateValue(tblNameFaultMirror, id, properties); err != nil {
+		log.Errorf("[Store][boltdb] delete fault mirror rule error, %v", err)
+		return err
+	}
+	return nil
+}
+
+// EnableFaultMirrorRule 设置故障注入与流量镜像规则是否启用
+func (f *faultMirrorStore) EnableFaultMirrorRule(rule *v2.FaultMirrorRule) error {
+	if rule.ID == "" || rule.Revision == "" {
+		return errors.New("[Store][boltdb] enable fault mirror rule missing some params")
+	}
+
+	if rule.Enable {
+		rule.EnableTime = time.Now()
+	} else {
+		rule.EnableTime = time.Time{}
+	}
+
+	properties := make(map[string]interface{})
+	properties[fmFieldEnable] = rule.Enable
+	properties[fmFieldEnableTime] = rule.EnableTime
+	properties[fmFieldRevision] = rule.Revision
+	properties[fmFieldModifyTime] = time.Now()
+
+	if err := f.handler.UpdateValue(tblNameFaultMirror, rule.ID, properties); err != nil {
+		log.Errorf("[Store][boltdb] enable fault mirror rule error, %v", err)
+		return err
+	}
+	return nil
+}
+
+// GetFaultMirrorRuleWithID 根据规则ID拉取故障注入与流量镜像规则
+func (f *faultMirrorStore) GetFaultMirrorRuleWithID(id string) (*v2.FaultMirrorRule, error) {
+	ret, err := f.handler.LoadValues(tblNameFaultMirror, []string{id}, &v2.FaultMirrorRule{})
+	if err != nil {
+		log.Errorf("[Store][boltdb] load fault mirror rule from kv error, %v", err)
+		return nil, err
+	}
+	val, ok := ret[id]
+	if !ok {
+		return nil, nil
+	}
+	rule := val.(*v2.FaultMirrorRule)
+	if !rule.Valid {
+		return nil, nil
+	}
+	return rule, nil
+}
+
+// GetFaultMirrorRulesForCache 通过mtime拉取增量的故障注入与流量镜像规则信息
+// 此方法用于 cache 增量更新，需要注意 mtime 应为数据库时间戳
+func (f *faultMirrorStore) GetFaultMirrorRulesForCache(
+	mtime time.Time, firstUpdate bool) ([]*v2.FaultMirrorRule, error) {
+	if firstUpdate {
+		mtime = time.Time{}
+	}
+
+	fields := []string{fmFieldModifyTime, fmFieldValid}
+	values, err := f.handler.LoadValuesByFilter(tblNameFaultMirror, fields, &v2.FaultMirrorRule{},
+		func(m map[string]interface{}) bool {
+			if firstUpdate {
+				valid, _ := m[fmFieldValid].(bool)
+				if !valid {
+					return false
+				}
+			}
+			rMtime, ok := m[fmFieldModifyTime]
+			if !ok {
+				return false
+			}
+			return !rMtime.(time.Time).Before(mtime)
+		})
+	if err != nil {
+		log.Errorf("[Store][boltdb] load fault mirror rules from kv error, %v", err)
+		return nil, err
+	}
+
+	out := make([]*v2.FaultMirrorRule, 0, len(values))
+	for _, v := range values {
+		out = append(out, v.(*v2.FaultMirrorRule))
+	}
+	return out, nil
+}
@@ -20,7 +20,9 @@ package boltdb
 import (
 	"errors"
 	"fmt"
+	"os"
 	"reflect"
+	"sync"
 	"time"
 
 	"github.com/boltdb/bolt"
@@ -76,6 +78,9 @@ type BoltHandler interface {
 	// StartTx start new tx
 	StartTx() (store.Tx, error)
 
+	// Replace 使用 path 对应的 boltdb 文件替换当前的数据文件，正在执行的读写结束后关闭原来的文件
+	Replace(path string) error
+
 	// Close boltdb
 	Close() error
 }
@@ -84,11 +89,14 @@ type BoltHandler interface {
 type BoltConfig struct {
 	// FileName boltdb store file
 	FileName string
+	// FixedInitToken 初始化数据使用固定的 token，多个节点需要得到相同的初始化数据时开启
+	FixedInitToken bool
 }
 
 const (
-	confPath    = "path"
-	defaultPath = "./polaris.bolt"
+	confPath           = "path"
+	confFixedInitToken = "fixedInitToken"
+	defaultPath        = "./polaris.bolt"
 )
 
 // Parse parse yaml config
@@ -98,6 +106,9 @@ func (c *BoltConfig) Parse(opt map[string]interface{}) {
 	} else {
 		c.FileName = defaultPath
 	}
+	if value, ok := opt[confFixedInitToken]; ok {
+		c.FixedInitToken, _ = value.(bool)
+	}
 }
 
 const (
@@ -110,11 +121,44 @@ func NewBoltHandler(config *BoltConfig) (BoltHandler, error) {
 	if err != nil {
 		return nil, err
 	}
-	return &boltHandler{db: db}, nil
+	return &boltHandler{path: config.FileName, db: db}, nil
 }
 
 type boltHandler struct {
-	db *bolt.DB
+	path  string
+	mutex sync.RWMutex
+	db    *bolt.DB
+}
+
+// getDB 获取当前使用的 boltdb，Replace 之后返回新的 boltdb
+func (b *boltHandler) getDB() *bolt.DB {
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+	return b.db
+}
+
+// update 执行写事务，事务开始前 boltdb 已经被替换并关闭时，在新的 boltdb 上重试
+func (b *boltHandler) update(process func(tx *bolt.Tx) error) error {
+	for {
+		db := b.getDB()
+		err := db.Update(process)
+		if err == bolt.ErrDatabaseNotOpen && db != b.getDB() {
+			continue
+		}
+		return err
+	}
+}
+
+// view 执行读事务，事务开始前 boltdb 已经被替换并关闭时，在新的 boltdb 上重试
+func (b *boltHandler) view(process func(tx *bolt.Tx) error) error {
+	for {
+		db := b.getDB()
+		err := db.View(process)
+		if err == bolt.ErrDatabaseNotOpen && db != b.getDB() {
+			continue
+		}
+		return err
+	}
 }
 
 func openBoltDB(path string) (*bolt.DB, error) {
@@ -125,7 +169,7 @@ func openBoltDB(path string) (*bolt.DB, error) {
 
 // SaveValue insert data object, each data object should be identified by unique key
 func (b *boltHandler) SaveValue(typ string, key string, value interface{}) error {
-	return b.db.Update(func(tx *bolt.Tx) error {
+	return b.update(func(tx *bolt.Tx) error {
 		return saveValue(tx, typ, key, value)
 	})
 }
@@ -181,7 +225,7 @@ func (b *boltHandler) LoadValues(typ string, keys []string, typObject interface{
 	if len(keys) == 0 {
 		return values, nil
 	}
-	err := b.db.View(func(tx *bolt.Tx) error {
+	err := b.view(func(tx *bolt.Tx) error {
 		return loadValues(tx, typ, keys, typObject, values)
 	})
 	return values, err
@@ -206,7 +250,7 @@ func loadValues(tx *bolt.Tx, typ string, keys []string, typObject interface{}, v
 func (b *boltHandler) LoadValuesByFilter(typ string, fields []string,
 	typObject interface{}, filter func(map[string]interface{}) bool) (map[string]interface{}, error) {
 	values := make(map[string]interface{})
-	err := b.db.View(func(tx *bolt.Tx) error {
+	err := b.view(func(tx *bolt.Tx) error {
 		return loadValuesByFilter(tx, typ, fields, typObject, filter, values)
 	})
 	return values, err
@@ -350,7 +394,7 @@ func (b *boltHandler) IterateFields(typ string, field string, typObject interfac
 	if filter == nil {
 		return nil
 	}
-	return b.db.View(func(tx *bolt.Tx) error {
+	return b.view(func(tx *bolt.Tx) error {
 		typeBucket := tx.Bucket([]byte(typ))
 		if typeBucket == nil {
 			return nil
@@ -381,18 +425,35 @@ func (b *boltHandler) IterateFields(typ string, field string, typObject interfac
 
 // Close boltdb
 func (b *boltHandler) Close() error {
-	if b.db != nil {
-		return b.db.Close()
+	if db := b.getDB(); db != nil {
+		return db.Close()
 	}
 	return nil
 }
 
+// Replace 将 path 对应的文件重命名为当前的数据文件并打开，新的读写切换到新的 boltdb 上
+func (b *boltHandler) Replace(path string) error {
+	if err := os.Rename(path, b.path); err != nil {
+		return err
+	}
+	db, err := openBoltDB(b.path)
+	if err != nil {
+		return err
+	}
+	b.mutex.Lock()
+	old := b.db
+	b.db = db
+	b.mutex.Unlock()
+	// 关闭时会等待原来的 boltdb 上正在执行的事务结束
+	return old.Close()
+}
+
 // DeleteValues delete data object by unique key
 func (b *boltHandler) DeleteValues(typ string, keys []string) error {
 	if len(keys) == 0 {
 		return nil
 	}
-	return b.db.Update(func(tx *bolt.Tx) error {
+	return b.update(func(tx *bolt.Tx) error {
 		return deleteValues(tx, typ, keys)
 	})
 }
@@ -465,7 +526,7 @@ func getKeys(bucket *bolt.Bucket) ([]string, error) {
 // CountValues count all data objects
 func (b *boltHandler) CountValues(typ string) (int, error) {
 	var count int
-	err := b.db.View(func(tx *bolt.Tx) error {
+	err := b.view(func(tx *bolt.Tx) error {
 		typeBucket := tx.Bucket([]byte(typ))
 		if typeBucket == nil {
 			return nil
@@ -498,7 +559,7 @@ func (b *boltHandler) CountValues(typ string) (int, error) {
 
 // UpdateValue update properties of data object
 func (b *boltHandler) UpdateValue(typ string, key string, properties map[string]interface{}) error {
-	return b.db.Update(func(tx *bolt.Tx) error {
+	return b.update(func(tx *bolt.Tx) error {
 		return updateValue(tx, typ, key, properties)
 	})
 }
@@ -559,7 +620,7 @@ func updateValue(tx *bolt.Tx, typ string, key string, properties map[string]inte
 // LoadValuesAll load all saved data objects, return value is 'key->object' map
 func (b *boltHandler) LoadValuesAll(typ string, typObject interface{}) (map[string]interface{}, error) {
 	values := make(map[string]interface{})
-	err := b.db.View(func(tx *bolt.Tx) error {
+	err := b.view(func(tx *bolt.Tx) error {
 		typeBucket := tx.Bucket([]byte(typ))
 		if typeBucket == nil {
 			return nil
@@ -592,14 +653,14 @@ func (b *boltHandler) LoadValuesAll(typ string, typObject interface{}) (map[stri
 // Execute execute scripts directly
 func (b *boltHandler) Execute(writable bool, process func(tx *bolt.Tx) error) error {
 	if writable {
-		return b.db.Update(process)
+		return b.update(process)
 	}
-	return b.db.View(process)
+	return b.view(process)
 }
 
 // StartTx start a new tx
 func (b *boltHandler) StartTx() (store.Tx, error) {
-	tx, err := b.db.Begin(true)
+	tx, err := b.getDB().Begin(true)
 	if err != nil {
 		return nil, err
 	}
@@ -429,3 +429,38 @@ func TestBoltHandler_UpdateValue(t *testing.T) {
 	}
 
 }
+
+func TestBoltHandler_Replace(t *testing.T) {
+	dir := t.TempDir()
+	handler, err := NewBoltHandler(&BoltConfig{FileName: filepath.Join(dir, "table.bolt")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer handler.Close()
+	if err := handler.SaveValue(tblNameNamespace, "old", &model.Namespace{Name: "old"}); err != nil {
+		t.Fatal(err)
+	}
+
+	other, err := NewBoltHandler(&BoltConfig{FileName: filepath.Join(dir, "table.bolt.restore")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := other.SaveValue(tblNameNamespace, "new", &model.Namespace{Name: "new"}); err != nil {
+		t.Fatal(err)
+	}
+	_ = other.Close()
+
+	if err := handler.Replace(filepath.Join(dir, "table.bolt.restore")); err != nil {
+		t.Fatal(err)
+	}
+	values, err := handler.LoadValues(tblNameNamespace, []string{"old", "new"}, &model.Namespace{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := values["old"]; ok {
+		t.Fatal("old value should be replaced")
+	}
+	if _, ok := values["new"]; !ok {
+		t.Fatal("new value not found")
+	}
+}
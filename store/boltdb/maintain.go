@@ -17,12 +17,46 @@
 
 package boltdb
 
-import "github.com/polarismesh/polaris/common/model"
+import (
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/boltdb/bolt"
+	"go.uber.org/zap"
+
+	"github.com/polarismesh/polaris/common/model"
+)
+
+const (
+	tblHealthChangeRecord   string = "HealthChangeRecord"
+	tblHealthChangeRecordID string = "HealthChangeRecordID"
+
+	healthRecordFieldInstanceId string = "InstanceId"
+	healthRecordFieldNamespace  string = "Namespace"
+	healthRecordFieldService    string = "Service"
+	healthRecordFieldCreateTime string = "CreateTime"
+)
 
 type maintainStore struct {
+	id      uint64
 	handler BoltHandler
 }
 
+func newMaintainStore(handler BoltHandler) (*maintainStore, error) {
+	m := &maintainStore{handler: handler, id: 0}
+	ret, err := handler.LoadValues(tblHealthChangeRecordID, []string{tblHealthChangeRecordID}, &IDHolder{})
+	if err != nil {
+		return nil, err
+	}
+	if len(ret) == 0 {
+		return m, nil
+	}
+	val := ret[tblHealthChangeRecordID].(*IDHolder)
+	m.id = val.ID
+	return m, nil
+}
+
 // BatchCleanDeletedInstances
 func (m *maintainStore) BatchCleanDeletedInstances(batchSize uint32) (uint32, error) {
 	fields := []string{insFieldValid}
@@ -56,3 +90,90 @@ func (m *maintainStore) BatchCleanDeletedInstances(batchSize uint32) (uint32, er
 	}
 	return count, nil
 }
+
+// AddHealthChangeRecords 保存实例健康状态的变化记录
+func (m *maintainStore) AddHealthChangeRecords(records []*model.HealthChangeRecord) error {
+	if len(records) == 0 {
+		return nil
+	}
+	return m.handler.Execute(true, func(tx *bolt.Tx) error {
+		id := m.id
+		for _, record := range records {
+			id++
+			record.Id = id
+			if record.CreateTime.IsZero() {
+				record.CreateTime = time.Now()
+			}
+			if err := saveValue(tx, tblHealthChangeRecord, strconv.FormatUint(id, 10), record); err != nil {
+				log.Error("[HealthChangeRecord] save info", zap.Error(err))
+				return err
+			}
+		}
+		if err := saveValue(tx, tblHealthChangeRecordID, tblHealthChangeRecordID, &IDHolder{ID: id}); err != nil {
+			log.Error("[HealthChangeRecord] save auto_increment id", zap.Error(err))
+			return err
+		}
+		m.id = id
+		return nil
+	})
+}
+
+// GetHealthChangeRecords 查询实例健康状态的变化记录，按照时间倒序返回
+func (m *maintainStore) GetHealthChangeRecords(
+	filter *model.HealthHistoryFilter) ([]*model.HealthChangeRecord, error) {
+	fields := []string{healthRecordFieldInstanceId, healthRecordFieldNamespace,
+		healthRecordFieldService, healthRecordFieldCreateTime}
+	values, err := m.handler.LoadValuesByFilter(tblHealthChangeRecord, fields, &model.HealthChangeRecord{},
+		func(props map[string]interface{}) bool {
+			if filter.InstanceId != "" && props[healthRecordFieldInstanceId] != filter.InstanceId {
+				return false
+			}
+			if filter.Namespace != "" && props[healthRecordFieldNamespace] != filter.Namespace {
+				return false
+			}
+			if filter.Service != "" && props[healthRecordFieldService] != filter.Service {
+				return false
+			}
+			createTime, _ := props[healthRecordFieldCreateTime].(time.Time)
+			return createTime.After(filter.Since)
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	records := make([]*model.HealthChangeRecord, 0, len(values))
+	for _, v := range values {
+		records = append(records, v.(*model.HealthChangeRecord))
+	}
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].Id > records[j].Id
+	})
+	if filter.Limit > 0 && len(records) > filter.Limit {
+		records = records[:filter.Limit]
+	}
+	return records, nil
+}
+
+// CleanHealthChangeRecords 清理创建时间早于 before 的健康状态变化记录
+func (m *maintainStore) CleanHealthChangeRecords(before time.Time) (uint32, error) {
+	fields := []string{healthRecordFieldCreateTime}
+	values, err := m.handler.LoadValuesByFilter(tblHealthChangeRecord, fields, &model.HealthChangeRecord{},
+		func(props map[string]interface{}) bool {
+			createTime, _ := props[healthRecordFieldCreateTime].(time.Time)
+			return createTime.Before(before)
+		})
+	if err != nil {
+		return 0, err
+	}
+	if len(values) == 0 {
+		return 0, nil
+	}
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	if err := m.handler.DeleteValues(tblHealthChangeRecord, keys); err != nil {
+		return 0, err
+	}
+	return uint32(len(keys)), nil
+}
@@ -106,3 +106,70 @@ func TestMaintainStore_BatchCleanDeletedInstances(t *testing.T) {
 	}
 
 }
+
+func TestMaintainStore_HealthChangeRecords(t *testing.T) {
+	handler, err := NewBoltHandler(&BoltConfig{FileName: "./table.bolt"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		handler.Close()
+		_ = os.RemoveAll("./table.bolt")
+	}()
+
+	store, err := newMaintainStore(handler)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now()
+	records := []*model.HealthChangeRecord{
+		{InstanceId: "ins1", Namespace: "ns", Service: "svc", Healthy: false, CreateTime: now.Add(-time.Hour)},
+		{InstanceId: "ins1", Namespace: "ns", Service: "svc", Healthy: true, CreateTime: now.Add(-time.Minute)},
+		{InstanceId: "ins2", Namespace: "ns", Service: "svc", Healthy: false, CreateTime: now},
+	}
+	if err := store.AddHealthChangeRecords(records); err != nil {
+		t.Fatal(err)
+	}
+
+	ret, err := store.GetHealthChangeRecords(&model.HealthHistoryFilter{Since: now.Add(-2 * time.Hour)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ret) != 3 || ret[0].InstanceId != "ins2" {
+		t.Fatalf("records not match, actual=%+v", ret)
+	}
+
+	ret, err = store.GetHealthChangeRecords(&model.HealthHistoryFilter{
+		InstanceId: "ins1", Since: now.Add(-2 * time.Hour), Limit: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ret) != 1 || !ret[0].Healthy {
+		t.Fatalf("records not match, actual=%+v", ret)
+	}
+
+	// 重新加载后自增 id 需要延续
+	store, err = newMaintainStore(handler)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if store.id != 3 {
+		t.Fatalf("id not match, expect=%d, actual=%d", 3, store.id)
+	}
+
+	count, err := store.CleanHealthChangeRecords(now.Add(-30 * time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 1 {
+		t.Fatalf("count not match, expect cnt=%d, actual cnt=%d", 1, count)
+	}
+	ret, err = store.GetHealthChangeRecords(&model.HealthHistoryFilter{Since: now.Add(-2 * time.Hour)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ret) != 2 {
+		t.Fatalf("count not match, expect cnt=%d, actual cnt=%d", 2, len(ret))
+	}
+}
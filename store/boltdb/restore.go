@@ -0,0 +1,290 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package boltdb
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/boltdb/bolt"
+	"github.com/golang/protobuf/ptypes/wrappers"
+
+	"github.com/polarismesh/polaris/common/model"
+	commontime "github.com/polarismesh/polaris/common/time"
+	"github.com/polarismesh/polaris/store"
+)
+
+const (
+	restoreFieldId         string = "Id"
+	restoreFieldCreateTime string = "CreateTime"
+	restoreFieldModifyTime string = "ModifyTime"
+)
+
+// restoreTable 记录类型对应的数据表
+type restoreTable struct {
+	table string
+	// idTable 配置中心记录的自增ID表，配置中心的记录以自然键保存，需要按照自增ID查找
+	idTable string
+	// typObject 配置中心记录的存储类型
+	typObject interface{}
+}
+
+var restoreTables = map[store.RecordKind]restoreTable{
+	store.RecordNamespace:        {table: tblNameNamespace},
+	store.RecordService:          {table: tblNameService},
+	store.RecordInstance:         {table: tblNameInstance},
+	store.RecordRoutingConfig:    {table: tblNameRouting},
+	store.RecordRoutingConfigV2:  {table: tblNameRoutingV2},
+	store.RecordRateLimit:        {table: tblRateLimitConfig},
+	store.RecordCircuitBreaker:   {table: tblCircuitBreaker},
+	store.RecordCircuitBreakerV2: {table: tblNameCircuitBreakerV2},
+	store.RecordFaultMirrorRule:  {table: tblNameFaultMirror},
+	store.RecordConfigFileGroup: {table: tblConfigFileGroup, idTable: tblConfigFileGroupID,
+		typObject: &model.ConfigFileGroup{}},
+	store.RecordConfigFile: {table: tblConfigFile, idTable: tblConfigFileID, typObject: &model.ConfigFile{}},
+	store.RecordConfigFileRelease: {table: tblConfigFileRelease, idTable: tblConfigFileReleaseID,
+		typObject: &model.ConfigFileRelease{}},
+	store.RecordConfigFileReleaseHistory: {table: tblConfigFileReleaseHistory, idTable: tblConfigFileReleaseHistoryID,
+		typObject: &model.ConfigFileReleaseHistory{}},
+	store.RecordConfigFileTemplate: {table: tblConfigFileTemplate, idTable: tblConfigFileTemplateID,
+		typObject: &model.ConfigFileTemplate{}},
+	store.RecordUser:      {table: tblUser},
+	store.RecordUserGroup: {table: tblGroup},
+	store.RecordStrategy:  {table: tblStrategy},
+}
+
+// RestoreRecordMetas 还原记录原有的时间戳以及配置中心记录的自增ID
+// 数据表中存在导入之外的记录时只还原时间戳，避免源存储的自增ID与已有的记录冲突
+func (m *boltStore) RestoreRecordMetas(kind store.RecordKind, metas []*store.RecordMeta) error {
+	t, ok := restoreTables[kind]
+	if !ok {
+		return fmt.Errorf("record kind %s not support restore", kind)
+	}
+	if len(metas) == 0 {
+		return nil
+	}
+
+	restoreID := true
+	err := m.handler.Execute(true, func(tx *bolt.Tx) error {
+		if t.idTable == "" {
+			return restoreRecordTimes(tx, t.table, metas)
+		}
+		var err error
+		restoreID, err = restoreConfigRecordMetas(tx, t, metas)
+		return err
+	})
+	if err != nil {
+		log.Errorf("[Store][boltdb] restore %s metas err: %s", kind, err.Error())
+		return store.Error(err)
+	}
+	if t.idTable == "" {
+		return nil
+	}
+	if err := m.reloadIDs(); err != nil {
+		return store.Error(err)
+	}
+	if !restoreID {
+		return fmt.Errorf("table %s holds records not imported, ids of %s are not restored", t.table, kind)
+	}
+	return nil
+}
+
+// restoreRecordTimes 还原以 meta.Key 为主键的记录的时间戳
+func restoreRecordTimes(tx *bolt.Tx, table string, metas []*store.RecordMeta) error {
+	for _, meta := range metas {
+		key := meta.Key
+		if meta.Version != "" {
+			// 熔断规则 v1 以 ID 以及版本号作为主键
+			key = fmt.Sprintf("%s_%s", meta.Key, meta.Version)
+		}
+		properties := timeProperties(meta)
+		if len(properties) == 0 {
+			continue
+		}
+		var err error
+		if table == tblNameInstance {
+			err = restoreInstanceTimes(tx, key, meta)
+		} else {
+			err = updateValue(tx, table, key, properties)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// restoreInstanceTimes 实例的创建时间只保存在 Proto 中
+func restoreInstanceTimes(tx *bolt.Tx, key string, meta *store.RecordMeta) error {
+	values := make(map[string]interface{})
+	if err := loadValues(tx, tblNameInstance, []string{key}, &model.Instance{}, values); err != nil {
+		return err
+	}
+	value, ok := values[key]
+	if !ok {
+		return nil
+	}
+	ins := value.(*model.Instance)
+	if !meta.CreateTime.IsZero() {
+		ins.Proto.Ctime = &wrappers.StringValue{Value: commontime.Time2String(meta.CreateTime)}
+	}
+	if !meta.ModifyTime.IsZero() {
+		ins.Proto.Mtime = &wrappers.StringValue{Value: commontime.Time2String(meta.ModifyTime)}
+	}
+	properties := map[string]interface{}{insFieldProto: ins.Proto}
+	if !meta.ModifyTime.IsZero() {
+		properties[insFieldModifyTime] = meta.ModifyTime
+	}
+	return updateValue(tx, tblNameInstance, key, properties)
+}
+
+// restoreConfigRecordMetas 还原配置中心记录的时间戳以及自增ID，返回是否还原了自增ID
+func restoreConfigRecordMetas(tx *bolt.Tx, t restoreTable, metas []*store.RecordMeta) (bool, error) {
+	byID := make(map[uint64]*store.RecordMeta, len(metas))
+	originCount := 0
+	var maxID uint64
+	for _, meta := range metas {
+		id, err := strconv.ParseUint(meta.Key, 10, 64)
+		if err != nil {
+			return false, err
+		}
+		byID[id] = meta
+		if meta.OriginID != 0 {
+			originCount++
+		}
+		if meta.OriginID > maxID {
+			maxID = meta.OriginID
+		}
+	}
+
+	all := make(map[string]interface{})
+	err := loadValuesByFilter(tx, t.table, []string{restoreFieldId}, t.typObject,
+		func(map[string]interface{}) bool { return true }, all)
+	if err != nil {
+		return false, err
+	}
+	// 只有数据表中的记录全部为本次新写入的记录时，源存储的自增ID才不会与已有的记录冲突
+	restoreID := originCount > 0 && len(all) == originCount
+
+	records := make(map[string]*store.RecordMeta, len(metas))
+	for key, value := range all {
+		id, err := configRecordID(value)
+		if err != nil {
+			return false, err
+		}
+		if meta, ok := byID[id]; ok {
+			records[key] = meta
+		}
+	}
+
+	if t.table == tblConfigFileReleaseHistory {
+		return restoreID, restoreReleaseHistoryMetas(tx, all, records, restoreID)
+	}
+	for key, meta := range records {
+		properties := timeProperties(meta)
+		if restoreID && meta.OriginID != 0 {
+			properties[restoreFieldId] = meta.OriginID
+		}
+		if err := updateValue(tx, t.table, key, properties); err != nil {
+			return false, err
+		}
+	}
+	if !restoreID {
+		return false, nil
+	}
+	return true, saveMaxID(tx, t.idTable, maxID)
+}
+
+// restoreReleaseHistoryMetas 发布历史以自增ID作为主键，还原ID时需要先删除全部记录再按照新的主键写入
+func restoreReleaseHistoryMetas(tx *bolt.Tx, all map[string]interface{}, records map[string]*store.RecordMeta,
+	restoreID bool) error {
+	if !restoreID {
+		for key, meta := range records {
+			if err := updateValue(tx, tblConfigFileReleaseHistory, key, timeProperties(meta)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	keys := make([]string, 0, len(records))
+	for key := range records {
+		keys = append(keys, key)
+	}
+	if err := deleteValues(tx, tblConfigFileReleaseHistory, keys); err != nil {
+		return err
+	}
+	var maxID uint64
+	for key, meta := range records {
+		history := all[key].(*model.ConfigFileReleaseHistory)
+		history.Id = meta.OriginID
+		if !meta.CreateTime.IsZero() {
+			history.CreateTime = meta.CreateTime
+		}
+		if !meta.ModifyTime.IsZero() {
+			history.ModifyTime = meta.ModifyTime
+		}
+		if err := saveValue(tx, tblConfigFileReleaseHistory, strconv.FormatUint(history.Id, 10), history); err != nil {
+			return err
+		}
+		if history.Id > maxID {
+			maxID = history.Id
+		}
+	}
+	return saveMaxID(tx, tblConfigFileReleaseHistoryID, maxID)
+}
+
+// saveMaxID 自增ID表中的ID小于还原的最大ID时更新，避免后续写入的记录与还原的记录冲突
+func saveMaxID(tx *bolt.Tx, idTable string, maxID uint64) error {
+	values := make(map[string]interface{})
+	if err := loadValues(tx, idTable, []string{idTable}, &IDHolder{}, values); err != nil {
+		return err
+	}
+	if val, ok := values[idTable]; ok && val.(*IDHolder).ID >= maxID {
+		return nil
+	}
+	return saveValue(tx, idTable, idTable, &IDHolder{ID: maxID})
+}
+
+func configRecordID(value interface{}) (uint64, error) {
+	switch record := value.(type) {
+	case *model.ConfigFileGroup:
+		return record.Id, nil
+	case *model.ConfigFile:
+		return record.Id, nil
+	case *model.ConfigFileRelease:
+		return record.Id, nil
+	case *model.ConfigFileReleaseHistory:
+		return record.Id, nil
+	case *model.ConfigFileTemplate:
+		return record.Id, nil
+	default:
+		return 0, fmt.Errorf("unknown config record type %T", value)
+	}
+}
+
+func timeProperties(meta *store.RecordMeta) map[string]interface{} {
+	properties := make(map[string]interface{}, 3)
+	if !meta.CreateTime.IsZero() {
+		properties[restoreFieldCreateTime] = meta.CreateTime
+	}
+	if !meta.ModifyTime.IsZero() {
+		properties[restoreFieldModifyTime] = meta.ModifyTime
+	}
+	return properties
+}
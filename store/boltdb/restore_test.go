@@ -0,0 +1,127 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package boltdb
+
+import (
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/polarismesh/polaris/common/model"
+	"github.com/polarismesh/polaris/store"
+)
+
+func newRestoreTestStore(t *testing.T) *boltStore {
+	s := &boltStore{}
+	err := s.Initialize(&store.Config{
+		Name:   STORENAME,
+		Option: map[string]interface{}{"path": filepath.Join(t.TempDir(), "restore.bolt")},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		_ = s.Destroy()
+	})
+	return s
+}
+
+func TestBoltStore_RestoreRecordMetas(t *testing.T) {
+	s := newRestoreTestStore(t)
+	ctime := time.Unix(1600000000, 0)
+	mtime := time.Unix(1600000100, 0)
+
+	t.Run("restore times", func(t *testing.T) {
+		assert.NoError(t, s.AddService(&model.Service{ID: "svc-1", Name: "svc-1", Namespace: "default",
+			Token: "token", Owner: "polaris", Revision: "revision"}))
+		err := s.RestoreRecordMetas(store.RecordService, []*store.RecordMeta{
+			{Key: "svc-1", CreateTime: ctime, ModifyTime: mtime},
+		})
+		assert.NoError(t, err)
+
+		svc, err := s.GetServiceByID("svc-1")
+		assert.NoError(t, err)
+		assert.Equal(t, ctime.Unix(), svc.CreateTime.Unix())
+		assert.Equal(t, mtime.Unix(), svc.ModifyTime.Unix())
+	})
+
+	t.Run("restore config ids", func(t *testing.T) {
+		metas := make([]*store.RecordMeta, 0, 2)
+		for i, originID := range []uint64{10, 5} {
+			group, err := s.CreateConfigFileGroup(&model.ConfigFileGroup{Namespace: "default",
+				Name: "group-" + strconv.Itoa(i), CreateBy: "polaris"})
+			assert.NoError(t, err)
+			metas = append(metas, &store.RecordMeta{Key: strconv.FormatUint(group.Id, 10), OriginID: originID,
+				CreateTime: ctime, ModifyTime: mtime})
+		}
+		assert.NoError(t, s.RestoreRecordMetas(store.RecordConfigFileGroup, metas))
+
+		group, err := s.GetConfigFileGroup("default", "group-0")
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(10), group.Id)
+		assert.Equal(t, ctime.Unix(), group.CreateTime.Unix())
+		group, err = s.GetConfigFileGroup("default", "group-1")
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(5), group.Id)
+
+		// 后续写入的记录从还原的最大ID继续自增
+		group, err = s.CreateConfigFileGroup(&model.ConfigFileGroup{Namespace: "default", Name: "group-2",
+			CreateBy: "polaris"})
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(11), group.Id)
+
+		// 数据表中存在导入之外的记录时只还原时间戳
+		err = s.RestoreRecordMetas(store.RecordConfigFileGroup, []*store.RecordMeta{
+			{Key: strconv.FormatUint(group.Id, 10), OriginID: 1, CreateTime: ctime, ModifyTime: mtime},
+		})
+		assert.Error(t, err)
+		group, err = s.GetConfigFileGroup("default", "group-2")
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(11), group.Id)
+		assert.Equal(t, ctime.Unix(), group.CreateTime.Unix())
+	})
+
+	t.Run("restore release history ids", func(t *testing.T) {
+		metas := make([]*store.RecordMeta, 0, 2)
+		for i, originID := range []uint64{8, 7} {
+			history := &model.ConfigFileReleaseHistory{Name: "release", Namespace: "default", Group: "group",
+				FileName: "file-" + strconv.Itoa(i), Content: "content", Type: "normal", Status: "success"}
+			assert.NoError(t, s.CreateConfigFileReleaseHistory(nil, history))
+			metas = append(metas, &store.RecordMeta{Key: strconv.FormatUint(history.Id, 10), OriginID: originID,
+				CreateTime: ctime, ModifyTime: mtime})
+		}
+		assert.NoError(t, s.RestoreRecordMetas(store.RecordConfigFileReleaseHistory, metas))
+
+		for i, originID := range []uint64{8, 7} {
+			history, err := s.GetConfigFileReleaseHistory(originID)
+			assert.NoError(t, err)
+			assert.Equal(t, "file-"+strconv.Itoa(i), history.FileName)
+			assert.Equal(t, originID, history.Id)
+			assert.Equal(t, ctime.Unix(), history.CreateTime.Unix())
+		}
+		for _, meta := range metas {
+			id, _ := strconv.ParseUint(meta.Key, 10, 64)
+			history, err := s.GetConfigFileReleaseHistory(id)
+			assert.NoError(t, err)
+			assert.Nil(t, history)
+		}
+	})
+}
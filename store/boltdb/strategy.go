@@ -18,6 +18,7 @@
 package boltdb
 
 import (
+	"crypto/md5"
 	"errors"
 	"fmt"
 	"sort"
@@ -736,9 +737,14 @@ func (ss *strategyStore) cleanInvalidStrategy(tx *bolt.Tx, name, owner string) e
 	return deleteValues(tx, tblStrategy, keys)
 }
 
+// defaultStrategyID 默认策略的ID由用户或者用户组的ID生成，多个副本重放同一个写操作时可以得到相同的ID
+func defaultStrategyID(role model.PrincipalType, principalId string) string {
+	return fmt.Sprintf("%x", md5.Sum([]byte(fmt.Sprintf("%d/%s", role, principalId))))
+}
+
 func createDefaultStrategy(tx *bolt.Tx, role model.PrincipalType, principalId, name, owner string) error {
 	strategy := &model.StrategyDetail{
-		ID:        utils.NewUUID(),
+		ID:        defaultStrategyID(role, principalId),
 		Name:      model.BuildDefaultStrategyName(role, name),
 		Action:    api.AuthAction_READ_WRITE.String(),
 		Default:   true,
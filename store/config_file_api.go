@@ -28,6 +28,7 @@ type ConfigFileModuleStore interface {
 	ConfigFileGroupStore
 	ConfigFileStore
 	ConfigFileReleaseStore
+	ConfigFileGrayReleaseStore
 	ConfigFileReleaseHistoryStore
 	ConfigFileTagStore
 	ConfigFileTemplateStore
@@ -56,6 +57,9 @@ type ConfigFileGroupStore interface {
 
 	// GetConfigFileGroupById 根据Id获取文件组信息
 	GetConfigFileGroupById(id uint64) (*model.ConfigFileGroup, error)
+
+	// UpdateConfigFileGroupSchema 更新配置文件组的 JSON Schema
+	UpdateConfigFileGroupSchema(namespace, name, schema, modifyBy string) error
 }
 
 // ConfigFileStore 配置文件存储接口
@@ -106,6 +110,26 @@ type ConfigFileReleaseStore interface {
 	FindConfigFileReleaseByModifyTimeAfter(modifyTime time.Time) ([]*model.ConfigFileRelease, error)
 }
 
+// ConfigFileGrayReleaseStore 配置文件灰度发布存储接口
+type ConfigFileGrayReleaseStore interface {
+
+	// CreateConfigFileGrayRelease 创建配置文件灰度发布
+	CreateConfigFileGrayRelease(tx Tx, grayRelease *model.ConfigFileGrayRelease) (*model.ConfigFileGrayRelease, error)
+
+	// UpdateConfigFileGrayRelease 更新配置文件灰度发布，同时恢复 flag=0
+	UpdateConfigFileGrayRelease(tx Tx, grayRelease *model.ConfigFileGrayRelease) (*model.ConfigFileGrayRelease, error)
+
+	// GetConfigFileGrayReleaseWithAllFlag 获取配置文件灰度发布，返回所有 flag 的记录
+	GetConfigFileGrayReleaseWithAllFlag(tx Tx, namespace, group,
+		fileName string) (*model.ConfigFileGrayRelease, error)
+
+	// DeleteConfigFileGrayRelease 删除配置文件灰度发布，只标记 flag=1
+	DeleteConfigFileGrayRelease(tx Tx, namespace, group, fileName, deleteBy string) error
+
+	// FindConfigFileGrayReleaseByModifyTimeAfter 获取最近更新的配置文件灰度发布，包含已删除的记录
+	FindConfigFileGrayReleaseByModifyTimeAfter(modifyTime time.Time) ([]*model.ConfigFileGrayRelease, error)
+}
+
 // ConfigFileReleaseHistoryStore 配置文件发布历史存储接口
 type ConfigFileReleaseHistoryStore interface {
 
@@ -118,6 +142,9 @@ type ConfigFileReleaseHistoryStore interface {
 
 	// GetLatestConfigFileReleaseHistory 获取配置文件最后一次发布
 	GetLatestConfigFileReleaseHistory(namespace, group, fileName string) (*model.ConfigFileReleaseHistory, error)
+
+	// GetConfigFileReleaseHistory 根据 ID 获取单条发布历史记录
+	GetConfigFileReleaseHistory(id uint64) (*model.ConfigFileReleaseHistory, error)
 }
 
 type ConfigFileTagStore interface {
@@ -48,6 +48,10 @@ type NamingModuleStore interface {
 	StrategyStore
 	// RoutingConfigStoreV2 路由策略 v2 接口
 	RoutingConfigStoreV2
+	// CircuitBreakerStoreV2 熔断规则 v2 接口
+	CircuitBreakerStoreV2
+	// FaultMirrorStore 故障注入与流量镜像规则接口
+	FaultMirrorStore
 }
 
 // ServiceStore 服务存储接口
@@ -310,3 +314,37 @@ type RoutingConfigStoreV2 interface {
 	// GetRoutingConfigV2WithIDTx 根据服务ID拉取路由配置
 	GetRoutingConfigV2WithIDTx(tx Tx, id string) (*v2.RoutingConfig, error)
 }
+
+// CircuitBreakerStoreV2 熔断规则 v2 的存储接口
+type CircuitBreakerStoreV2 interface {
+	// CreateCircuitBreakerRuleV2 新增一个熔断规则
+	CreateCircuitBreakerRuleV2(rule *v2.CircuitBreakerRule) error
+	// UpdateCircuitBreakerRuleV2 更新一个熔断规则
+	UpdateCircuitBreakerRuleV2(rule *v2.CircuitBreakerRule) error
+	// DeleteCircuitBreakerRuleV2 删除一个熔断规则
+	DeleteCircuitBreakerRuleV2(id string) error
+	// EnableCircuitBreakerRuleV2 设置熔断规则是否启用
+	EnableCircuitBreakerRuleV2(rule *v2.CircuitBreakerRule) error
+	// GetCircuitBreakerRuleV2WithID 根据规则ID拉取熔断规则
+	GetCircuitBreakerRuleV2WithID(id string) (*v2.CircuitBreakerRule, error)
+	// GetCircuitBreakerRulesV2ForCache 通过mtime拉取增量的熔断规则信息
+	// 此方法用于 cache 增量更新，需要注意 mtime 应为数据库时间戳
+	GetCircuitBreakerRulesV2ForCache(mtime time.Time, firstUpdate bool) ([]*v2.CircuitBreakerRule, error)
+}
+
+// FaultMirrorStore 故障注入与流量镜像规则的存储接口
+type FaultMirrorStore interface {
+	// CreateFaultMirrorRule 新增一个故障注入与流量镜像规则
+	CreateFaultMirrorRule(rule *v2.FaultMirrorRule) error
+	// UpdateFaultMirrorRule 更新一个故障注入与流量镜像规则
+	UpdateFaultMirrorRule(rule *v2.FaultMirrorRule) error
+	// DeleteFaultMirrorRule 删除一个故障注入与流量镜像规则
+	DeleteFaultMirrorRule(id string) error
+	// EnableFaultMirrorRule 设置故障注入与流量镜像规则是否启用
+	EnableFaultMirrorRule(rule *v2.FaultMirrorRule) error
+	// GetFaultMirrorRuleWithID 根据规则ID拉取故障注入与流量镜像规则
+	GetFaultMirrorRuleWithID(id string) (*v2.FaultMirrorRule, error)
+	// GetFaultMirrorRulesForCache 通过mtime拉取增量的故障注入与流量镜像规则信息
+	// 此方法用于 cache 增量更新，需要注意 mtime 应为数据库时间戳
+	GetFaultMirrorRulesForCache(mtime time.Time, firstUpdate bool) ([]*v2.FaultMirrorRule, error)
+}
@@ -17,8 +17,23 @@
 
 package store
 
+import (
+	"time"
+
+	"github.com/polarismesh/polaris/common/model"
+)
+
 type MaintainStore interface {
 
 	// BatchCleanDeletedInstances batch clean soft deleted instances
 	BatchCleanDeletedInstances(batchSize uint32) (uint32, error)
+
+	// AddHealthChangeRecords 保存实例健康状态的变化记录
+	AddHealthChangeRecords(records []*model.HealthChangeRecord) error
+
+	// GetHealthChangeRecords 查询实例健康状态的变化记录，按照时间倒序返回
+	GetHealthChangeRecords(filter *model.HealthHistoryFilter) ([]*model.HealthChangeRecord, error)
+
+	// CleanHealthChangeRecords 清理创建时间早于 before 的健康状态变化记录
+	CleanHealthChangeRecords(before time.Time) (uint32, error)
 }
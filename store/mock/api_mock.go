@@ -15,6 +15,7 @@
  * specific language governing permissions and limitations under the License.
  */
 
+
 // Code generated by MockGen. DO NOT EDIT.
 // Source: api.go
 
@@ -68,6 +69,20 @@ func (mr *MockStoreMockRecorder) AddGroup(group interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddGroup", reflect.TypeOf((*MockStore)(nil).AddGroup), group)
 }
 
+// AddHealthChangeRecords mocks base method.
+func (m *MockStore) AddHealthChangeRecords(records []*model.HealthChangeRecord) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddHealthChangeRecords", records)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddHealthChangeRecords indicates an expected call of AddHealthChangeRecords.
+func (mr *MockStoreMockRecorder) AddHealthChangeRecords(records interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddHealthChangeRecords", reflect.TypeOf((*MockStore)(nil).AddHealthChangeRecords), records)
+}
+
 // AddInstance mocks base method.
 func (m *MockStore) AddInstance(instance *model.Instance) error {
 	m.ctrl.T.Helper()
@@ -252,6 +267,21 @@ func (mr *MockStoreMockRecorder) BatchSetInstanceIsolate(ids, isolate, revision
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BatchSetInstanceIsolate", reflect.TypeOf((*MockStore)(nil).BatchSetInstanceIsolate), ids, isolate, revision)
 }
 
+// CleanHealthChangeRecords mocks base method.
+func (m *MockStore) CleanHealthChangeRecords(before time.Time) (uint32, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CleanHealthChangeRecords", before)
+	ret0, _ := ret[0].(uint32)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CleanHealthChangeRecords indicates an expected call of CleanHealthChangeRecords.
+func (mr *MockStoreMockRecorder) CleanHealthChangeRecords(before interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CleanHealthChangeRecords", reflect.TypeOf((*MockStore)(nil).CleanHealthChangeRecords), before)
+}
+
 // CleanInstance mocks base method.
 func (m *MockStore) CleanInstance(instanceID string) error {
 	m.ctrl.T.Helper()
@@ -295,6 +325,20 @@ func (mr *MockStoreMockRecorder) CreateCircuitBreaker(circuitBreaker interface{}
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCircuitBreaker", reflect.TypeOf((*MockStore)(nil).CreateCircuitBreaker), circuitBreaker)
 }
 
+// CreateCircuitBreakerRuleV2 mocks base method.
+func (m *MockStore) CreateCircuitBreakerRuleV2(rule *v2.CircuitBreakerRule) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateCircuitBreakerRuleV2", rule)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateCircuitBreakerRuleV2 indicates an expected call of CreateCircuitBreakerRuleV2.
+func (mr *MockStoreMockRecorder) CreateCircuitBreakerRuleV2(rule interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCircuitBreakerRuleV2", reflect.TypeOf((*MockStore)(nil).CreateCircuitBreakerRuleV2), rule)
+}
+
 // CreateConfigFile mocks base method.
 func (m *MockStore) CreateConfigFile(tx store.Tx, file *model.ConfigFile) (*model.ConfigFile, error) {
 	m.ctrl.T.Helper()
@@ -310,6 +354,21 @@ func (mr *MockStoreMockRecorder) CreateConfigFile(tx, file interface{}) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateConfigFile", reflect.TypeOf((*MockStore)(nil).CreateConfigFile), tx, file)
 }
 
+// CreateConfigFileGrayRelease mocks base method.
+func (m *MockStore) CreateConfigFileGrayRelease(tx store.Tx, grayRelease *model.ConfigFileGrayRelease) (*model.ConfigFileGrayRelease, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateConfigFileGrayRelease", tx, grayRelease)
+	ret0, _ := ret[0].(*model.ConfigFileGrayRelease)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateConfigFileGrayRelease indicates an expected call of CreateConfigFileGrayRelease.
+func (mr *MockStoreMockRecorder) CreateConfigFileGrayRelease(tx, grayRelease interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateConfigFileGrayRelease", reflect.TypeOf((*MockStore)(nil).CreateConfigFileGrayRelease), tx, grayRelease)
+}
+
 // CreateConfigFileGroup mocks base method.
 func (m *MockStore) CreateConfigFileGroup(fileGroup *model.ConfigFileGroup) (*model.ConfigFileGroup, error) {
 	m.ctrl.T.Helper()
@@ -383,6 +442,20 @@ func (mr *MockStoreMockRecorder) CreateConfigFileTemplate(template interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateConfigFileTemplate", reflect.TypeOf((*MockStore)(nil).CreateConfigFileTemplate), template)
 }
 
+// CreateFaultMirrorRule mocks base method.
+func (m *MockStore) CreateFaultMirrorRule(rule *v2.FaultMirrorRule) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateFaultMirrorRule", rule)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateFaultMirrorRule indicates an expected call of CreateFaultMirrorRule.
+func (mr *MockStoreMockRecorder) CreateFaultMirrorRule(rule interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateFaultMirrorRule", reflect.TypeOf((*MockStore)(nil).CreateFaultMirrorRule), rule)
+}
+
 // CreateRateLimit mocks base method.
 func (m *MockStore) CreateRateLimit(limiting *model.RateLimit) error {
 	m.ctrl.T.Helper()
@@ -454,6 +527,20 @@ func (mr *MockStoreMockRecorder) CreateTransaction() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTransaction", reflect.TypeOf((*MockStore)(nil).CreateTransaction))
 }
 
+// DeleteCircuitBreakerRuleV2 mocks base method.
+func (m *MockStore) DeleteCircuitBreakerRuleV2(id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteCircuitBreakerRuleV2", id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteCircuitBreakerRuleV2 indicates an expected call of DeleteCircuitBreakerRuleV2.
+func (mr *MockStoreMockRecorder) DeleteCircuitBreakerRuleV2(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCircuitBreakerRuleV2", reflect.TypeOf((*MockStore)(nil).DeleteCircuitBreakerRuleV2), id)
+}
+
 // DeleteConfigFile mocks base method.
 func (m *MockStore) DeleteConfigFile(tx store.Tx, namespace, group, name string) error {
 	m.ctrl.T.Helper()
@@ -468,6 +555,20 @@ func (mr *MockStoreMockRecorder) DeleteConfigFile(tx, namespace, group, name int
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteConfigFile", reflect.TypeOf((*MockStore)(nil).DeleteConfigFile), tx, namespace, group, name)
 }
 
+// DeleteConfigFileGrayRelease mocks base method.
+func (m *MockStore) DeleteConfigFileGrayRelease(tx store.Tx, namespace, group, fileName, deleteBy string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteConfigFileGrayRelease", tx, namespace, group, fileName, deleteBy)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteConfigFileGrayRelease indicates an expected call of DeleteConfigFileGrayRelease.
+func (mr *MockStoreMockRecorder) DeleteConfigFileGrayRelease(tx, namespace, group, fileName, deleteBy interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteConfigFileGrayRelease", reflect.TypeOf((*MockStore)(nil).DeleteConfigFileGrayRelease), tx, namespace, group, fileName, deleteBy)
+}
+
 // DeleteConfigFileGroup mocks base method.
 func (m *MockStore) DeleteConfigFileGroup(namespace, name string) error {
 	m.ctrl.T.Helper()
@@ -510,6 +611,20 @@ func (mr *MockStoreMockRecorder) DeleteConfigFileTag(tx, namespace, group, fileN
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteConfigFileTag", reflect.TypeOf((*MockStore)(nil).DeleteConfigFileTag), tx, namespace, group, fileName, key, value)
 }
 
+// DeleteFaultMirrorRule mocks base method.
+func (m *MockStore) DeleteFaultMirrorRule(id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteFaultMirrorRule", id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteFaultMirrorRule indicates an expected call of DeleteFaultMirrorRule.
+func (mr *MockStoreMockRecorder) DeleteFaultMirrorRule(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteFaultMirrorRule", reflect.TypeOf((*MockStore)(nil).DeleteFaultMirrorRule), id)
+}
+
 // DeleteGroup mocks base method.
 func (m *MockStore) DeleteGroup(group *model.UserGroupDetail) error {
 	m.ctrl.T.Helper()
@@ -706,6 +821,34 @@ func (mr *MockStoreMockRecorder) Destroy() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Destroy", reflect.TypeOf((*MockStore)(nil).Destroy))
 }
 
+// EnableCircuitBreakerRuleV2 mocks base method.
+func (m *MockStore) EnableCircuitBreakerRuleV2(rule *v2.CircuitBreakerRule) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnableCircuitBreakerRuleV2", rule)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// EnableCircuitBreakerRuleV2 indicates an expected call of EnableCircuitBreakerRuleV2.
+func (mr *MockStoreMockRecorder) EnableCircuitBreakerRuleV2(rule interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnableCircuitBreakerRuleV2", reflect.TypeOf((*MockStore)(nil).EnableCircuitBreakerRuleV2), rule)
+}
+
+// EnableFaultMirrorRule mocks base method.
+func (m *MockStore) EnableFaultMirrorRule(rule *v2.FaultMirrorRule) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnableFaultMirrorRule", rule)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// EnableFaultMirrorRule indicates an expected call of EnableFaultMirrorRule.
+func (mr *MockStoreMockRecorder) EnableFaultMirrorRule(rule interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnableFaultMirrorRule", reflect.TypeOf((*MockStore)(nil).EnableFaultMirrorRule), rule)
+}
+
 // EnableRateLimit mocks base method.
 func (m *MockStore) EnableRateLimit(limit *model.RateLimit) error {
 	m.ctrl.T.Helper()
@@ -734,6 +877,21 @@ func (mr *MockStoreMockRecorder) EnableRouting(conf interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnableRouting", reflect.TypeOf((*MockStore)(nil).EnableRouting), conf)
 }
 
+// FindConfigFileGrayReleaseByModifyTimeAfter mocks base method.
+func (m *MockStore) FindConfigFileGrayReleaseByModifyTimeAfter(modifyTime time.Time) ([]*model.ConfigFileGrayRelease, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindConfigFileGrayReleaseByModifyTimeAfter", modifyTime)
+	ret0, _ := ret[0].([]*model.ConfigFileGrayRelease)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindConfigFileGrayReleaseByModifyTimeAfter indicates an expected call of FindConfigFileGrayReleaseByModifyTimeAfter.
+func (mr *MockStoreMockRecorder) FindConfigFileGrayReleaseByModifyTimeAfter(modifyTime interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindConfigFileGrayReleaseByModifyTimeAfter", reflect.TypeOf((*MockStore)(nil).FindConfigFileGrayReleaseByModifyTimeAfter), modifyTime)
+}
+
 // FindConfigFileGroups mocks base method.
 func (m *MockStore) FindConfigFileGroups(namespace string, names []string) ([]*model.ConfigFileGroup, error) {
 	m.ctrl.T.Helper()
@@ -839,6 +997,36 @@ func (mr *MockStoreMockRecorder) GetCircuitBreakerRelation(ruleID, ruleVersion i
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCircuitBreakerRelation", reflect.TypeOf((*MockStore)(nil).GetCircuitBreakerRelation), ruleID, ruleVersion)
 }
 
+// GetCircuitBreakerRuleV2WithID mocks base method.
+func (m *MockStore) GetCircuitBreakerRuleV2WithID(id string) (*v2.CircuitBreakerRule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCircuitBreakerRuleV2WithID", id)
+	ret0, _ := ret[0].(*v2.CircuitBreakerRule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCircuitBreakerRuleV2WithID indicates an expected call of GetCircuitBreakerRuleV2WithID.
+func (mr *MockStoreMockRecorder) GetCircuitBreakerRuleV2WithID(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCircuitBreakerRuleV2WithID", reflect.TypeOf((*MockStore)(nil).GetCircuitBreakerRuleV2WithID), id)
+}
+
+// GetCircuitBreakerRulesV2ForCache mocks base method.
+func (m *MockStore) GetCircuitBreakerRulesV2ForCache(mtime time.Time, firstUpdate bool) ([]*v2.CircuitBreakerRule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCircuitBreakerRulesV2ForCache", mtime, firstUpdate)
+	ret0, _ := ret[0].([]*v2.CircuitBreakerRule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCircuitBreakerRulesV2ForCache indicates an expected call of GetCircuitBreakerRulesV2ForCache.
+func (mr *MockStoreMockRecorder) GetCircuitBreakerRulesV2ForCache(mtime, firstUpdate interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCircuitBreakerRulesV2ForCache", reflect.TypeOf((*MockStore)(nil).GetCircuitBreakerRulesV2ForCache), mtime, firstUpdate)
+}
+
 // GetCircuitBreakerVersions mocks base method.
 func (m *MockStore) GetCircuitBreakerVersions(id string) ([]string, error) {
 	m.ctrl.T.Helper()
@@ -884,6 +1072,21 @@ func (mr *MockStoreMockRecorder) GetConfigFile(tx, namespace, group, name interf
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetConfigFile", reflect.TypeOf((*MockStore)(nil).GetConfigFile), tx, namespace, group, name)
 }
 
+// GetConfigFileGrayReleaseWithAllFlag mocks base method.
+func (m *MockStore) GetConfigFileGrayReleaseWithAllFlag(tx store.Tx, namespace, group, fileName string) (*model.ConfigFileGrayRelease, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetConfigFileGrayReleaseWithAllFlag", tx, namespace, group, fileName)
+	ret0, _ := ret[0].(*model.ConfigFileGrayRelease)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetConfigFileGrayReleaseWithAllFlag indicates an expected call of GetConfigFileGrayReleaseWithAllFlag.
+func (mr *MockStoreMockRecorder) GetConfigFileGrayReleaseWithAllFlag(tx, namespace, group, fileName interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetConfigFileGrayReleaseWithAllFlag", reflect.TypeOf((*MockStore)(nil).GetConfigFileGrayReleaseWithAllFlag), tx, namespace, group, fileName)
+}
+
 // GetConfigFileGroup mocks base method.
 func (m *MockStore) GetConfigFileGroup(namespace, name string) (*model.ConfigFileGroup, error) {
 	m.ctrl.T.Helper()
@@ -929,6 +1132,21 @@ func (mr *MockStoreMockRecorder) GetConfigFileRelease(tx, namespace, group, file
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetConfigFileRelease", reflect.TypeOf((*MockStore)(nil).GetConfigFileRelease), tx, namespace, group, fileName)
 }
 
+// GetConfigFileReleaseHistory mocks base method.
+func (m *MockStore) GetConfigFileReleaseHistory(id uint64) (*model.ConfigFileReleaseHistory, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetConfigFileReleaseHistory", id)
+	ret0, _ := ret[0].(*model.ConfigFileReleaseHistory)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetConfigFileReleaseHistory indicates an expected call of GetConfigFileReleaseHistory.
+func (mr *MockStoreMockRecorder) GetConfigFileReleaseHistory(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetConfigFileReleaseHistory", reflect.TypeOf((*MockStore)(nil).GetConfigFileReleaseHistory), id)
+}
+
 // GetConfigFileReleaseWithAllFlag mocks base method.
 func (m *MockStore) GetConfigFileReleaseWithAllFlag(tx store.Tx, namespace, group, fileName string) (*model.ConfigFileRelease, error) {
 	m.ctrl.T.Helper()
@@ -1006,6 +1224,36 @@ func (mr *MockStoreMockRecorder) GetExtendRateLimits(query, offset, limit interf
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExtendRateLimits", reflect.TypeOf((*MockStore)(nil).GetExtendRateLimits), query, offset, limit)
 }
 
+// GetFaultMirrorRuleWithID mocks base method.
+func (m *MockStore) GetFaultMirrorRuleWithID(id string) (*v2.FaultMirrorRule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFaultMirrorRuleWithID", id)
+	ret0, _ := ret[0].(*v2.FaultMirrorRule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFaultMirrorRuleWithID indicates an expected call of GetFaultMirrorRuleWithID.
+func (mr *MockStoreMockRecorder) GetFaultMirrorRuleWithID(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFaultMirrorRuleWithID", reflect.TypeOf((*MockStore)(nil).GetFaultMirrorRuleWithID), id)
+}
+
+// GetFaultMirrorRulesForCache mocks base method.
+func (m *MockStore) GetFaultMirrorRulesForCache(mtime time.Time, firstUpdate bool) ([]*v2.FaultMirrorRule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFaultMirrorRulesForCache", mtime, firstUpdate)
+	ret0, _ := ret[0].([]*v2.FaultMirrorRule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFaultMirrorRulesForCache indicates an expected call of GetFaultMirrorRulesForCache.
+func (mr *MockStoreMockRecorder) GetFaultMirrorRulesForCache(mtime, firstUpdate interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFaultMirrorRulesForCache", reflect.TypeOf((*MockStore)(nil).GetFaultMirrorRulesForCache), mtime, firstUpdate)
+}
+
 // GetGroup mocks base method.
 func (m *MockStore) GetGroup(id string) (*model.UserGroupDetail, error) {
 	m.ctrl.T.Helper()
@@ -1067,6 +1315,21 @@ func (mr *MockStoreMockRecorder) GetGroupsForCache(mtime, firstUpdate interface{
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGroupsForCache", reflect.TypeOf((*MockStore)(nil).GetGroupsForCache), mtime, firstUpdate)
 }
 
+// GetHealthChangeRecords mocks base method.
+func (m *MockStore) GetHealthChangeRecords(filter *model.HealthHistoryFilter) ([]*model.HealthChangeRecord, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHealthChangeRecords", filter)
+	ret0, _ := ret[0].([]*model.HealthChangeRecord)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetHealthChangeRecords indicates an expected call of GetHealthChangeRecords.
+func (mr *MockStoreMockRecorder) GetHealthChangeRecords(filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHealthChangeRecords", reflect.TypeOf((*MockStore)(nil).GetHealthChangeRecords), filter)
+}
+
 // GetInstance mocks base method.
 func (m *MockStore) GetInstance(instanceID string) (*model.Instance, error) {
 	m.ctrl.T.Helper()
@@ -2064,6 +2327,20 @@ func (mr *MockStoreMockRecorder) UpdateCircuitBreaker(circuitBraker interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCircuitBreaker", reflect.TypeOf((*MockStore)(nil).UpdateCircuitBreaker), circuitBraker)
 }
 
+// UpdateCircuitBreakerRuleV2 mocks base method.
+func (m *MockStore) UpdateCircuitBreakerRuleV2(rule *v2.CircuitBreakerRule) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateCircuitBreakerRuleV2", rule)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateCircuitBreakerRuleV2 indicates an expected call of UpdateCircuitBreakerRuleV2.
+func (mr *MockStoreMockRecorder) UpdateCircuitBreakerRuleV2(rule interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCircuitBreakerRuleV2", reflect.TypeOf((*MockStore)(nil).UpdateCircuitBreakerRuleV2), rule)
+}
+
 // UpdateConfigFile mocks base method.
 func (m *MockStore) UpdateConfigFile(tx store.Tx, file *model.ConfigFile) (*model.ConfigFile, error) {
 	m.ctrl.T.Helper()
@@ -2079,6 +2356,21 @@ func (mr *MockStoreMockRecorder) UpdateConfigFile(tx, file interface{}) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateConfigFile", reflect.TypeOf((*MockStore)(nil).UpdateConfigFile), tx, file)
 }
 
+// UpdateConfigFileGrayRelease mocks base method.
+func (m *MockStore) UpdateConfigFileGrayRelease(tx store.Tx, grayRelease *model.ConfigFileGrayRelease) (*model.ConfigFileGrayRelease, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateConfigFileGrayRelease", tx, grayRelease)
+	ret0, _ := ret[0].(*model.ConfigFileGrayRelease)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateConfigFileGrayRelease indicates an expected call of UpdateConfigFileGrayRelease.
+func (mr *MockStoreMockRecorder) UpdateConfigFileGrayRelease(tx, grayRelease interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateConfigFileGrayRelease", reflect.TypeOf((*MockStore)(nil).UpdateConfigFileGrayRelease), tx, grayRelease)
+}
+
 // UpdateConfigFileGroup mocks base method.
 func (m *MockStore) UpdateConfigFileGroup(fileGroup *model.ConfigFileGroup) (*model.ConfigFileGroup, error) {
 	m.ctrl.T.Helper()
@@ -2094,6 +2386,20 @@ func (mr *MockStoreMockRecorder) UpdateConfigFileGroup(fileGroup interface{}) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateConfigFileGroup", reflect.TypeOf((*MockStore)(nil).UpdateConfigFileGroup), fileGroup)
 }
 
+// UpdateConfigFileGroupSchema mocks base method.
+func (m *MockStore) UpdateConfigFileGroupSchema(namespace, name, schema, modifyBy string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateConfigFileGroupSchema", namespace, name, schema, modifyBy)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateConfigFileGroupSchema indicates an expected call of UpdateConfigFileGroupSchema.
+func (mr *MockStoreMockRecorder) UpdateConfigFileGroupSchema(namespace, name, schema, modifyBy interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateConfigFileGroupSchema", reflect.TypeOf((*MockStore)(nil).UpdateConfigFileGroupSchema), namespace, name, schema, modifyBy)
+}
+
 // UpdateConfigFileRelease mocks base method.
 func (m *MockStore) UpdateConfigFileRelease(tx store.Tx, fileRelease *model.ConfigFileRelease) (*model.ConfigFileRelease, error) {
 	m.ctrl.T.Helper()
@@ -2109,6 +2415,20 @@ func (mr *MockStoreMockRecorder) UpdateConfigFileRelease(tx, fileRelease interfa
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateConfigFileRelease", reflect.TypeOf((*MockStore)(nil).UpdateConfigFileRelease), tx, fileRelease)
 }
 
+// UpdateFaultMirrorRule mocks base method.
+func (m *MockStore) UpdateFaultMirrorRule(rule *v2.FaultMirrorRule) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateFaultMirrorRule", rule)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateFaultMirrorRule indicates an expected call of UpdateFaultMirrorRule.
+func (mr *MockStoreMockRecorder) UpdateFaultMirrorRule(rule interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateFaultMirrorRule", reflect.TypeOf((*MockStore)(nil).UpdateFaultMirrorRule), rule)
+}
+
 // UpdateGroup mocks base method.
 func (m *MockStore) UpdateGroup(group *model.ModifyUserGroup) error {
 	m.ctrl.T.Helper()
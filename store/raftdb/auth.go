@@ -0,0 +1,77 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package raftdb
+
+import (
+	"github.com/polarismesh/polaris/common/model"
+)
+
+// AddUser Create a user
+func (s *raftStore) AddUser(user *model.User) error {
+	return s.exec("AddUser", user)
+}
+
+// UpdateUser Update user
+func (s *raftStore) UpdateUser(user *model.User) error {
+	return s.exec("UpdateUser", user)
+}
+
+// DeleteUser delete users
+func (s *raftStore) DeleteUser(user *model.User) error {
+	return s.exec("DeleteUser", user)
+}
+
+// AddGroup Add a user group
+func (s *raftStore) AddGroup(group *model.UserGroupDetail) error {
+	return s.exec("AddGroup", group)
+}
+
+// UpdateGroup Update user group
+func (s *raftStore) UpdateGroup(group *model.ModifyUserGroup) error {
+	return s.exec("UpdateGroup", group)
+}
+
+// DeleteGroup Delete user group
+func (s *raftStore) DeleteGroup(group *model.UserGroupDetail) error {
+	return s.exec("DeleteGroup", group)
+}
+
+// AddStrategy Create authentication strategy
+func (s *raftStore) AddStrategy(strategy *model.StrategyDetail) error {
+	return s.exec("AddStrategy", strategy)
+}
+
+// UpdateStrategy Update authentication strategy
+func (s *raftStore) UpdateStrategy(strategy *model.ModifyStrategyDetail) error {
+	return s.exec("UpdateStrategy", strategy)
+}
+
+// DeleteStrategy Delete authentication strategy
+func (s *raftStore) DeleteStrategy(id string) error {
+	return s.exec("DeleteStrategy", id)
+}
+
+// LooseAddStrategyResources Song requires the resources of the authentication strategy,
+func (s *raftStore) LooseAddStrategyResources(resources []model.StrategyResource) error {
+	return s.exec("LooseAddStrategyResources", resources)
+}
+
+// RemoveStrategyResources Clean all the strategies associated with corresponding resources
+func (s *raftStore) RemoveStrategyResources(resources []model.StrategyResource) error {
+	return s.exec("RemoveStrategyResources", resources)
+}
@@ -0,0 +1,188 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package raftdb
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/polarismesh/polaris/store"
+)
+
+// commandType 写入 raft 日志或者转发给 leader 的命令类型
+type commandType int
+
+const (
+	// cmdCall 调用底层存储的方法
+	cmdCall commandType = iota + 1
+	// cmdBeginTx 开启事务
+	cmdBeginTx
+	// cmdCommitTx 提交事务
+	cmdCommitTx
+	// cmdRollbackTx 回滚事务
+	cmdRollbackTx
+	// cmdLock 在 leader 上加锁，不写入 raft 日志，带有 Method 时加锁后在 leader 上执行该读操作
+	cmdLock
+	// cmdUnlock 释放 leader 上会话持有的全部锁，不写入 raft 日志
+	cmdUnlock
+)
+
+const (
+	// transactionMethodPrefix 调用 store.Transaction 的方法时，方法名需要带上该前缀
+	transactionMethodPrefix = "Transaction."
+	// writeLockKey 写锁，事务持有排它锁，普通写操作持有共享锁
+	writeLockKey = "write"
+)
+
+// lockReadMethods 加锁时允许在 leader 上执行的读操作
+var lockReadMethods = map[string]struct{}{
+	transactionMethodPrefix + "LockNamespace": {},
+	transactionMethodPrefix + "LockService":   {},
+	transactionMethodPrefix + "RLockService":  {},
+}
+
+var (
+	storeType        = reflect.TypeOf((*store.Store)(nil)).Elem()
+	restoreStoreType = reflect.TypeOf((*store.RestoreStore)(nil)).Elem()
+	transactionType  = reflect.TypeOf((*store.Transaction)(nil)).Elem()
+	txType           = reflect.TypeOf((*store.Tx)(nil)).Elem()
+	errorType        = reflect.TypeOf((*error)(nil)).Elem()
+
+	errTxNotFound = errors.New("raft store transaction not found or has been closed")
+)
+
+// command 写入 raft 日志或者转发给 leader 的命令
+type command struct {
+	Type commandType `json:"type"`
+	// TxID 事务的ID，加锁时表示会话的ID
+	TxID   string            `json:"tx_id,omitempty"`
+	Method string            `json:"method,omitempty"`
+	Args   []json.RawMessage `json:"args,omitempty"`
+	// Key 加锁的资源
+	Key    string `json:"key,omitempty"`
+	Shared bool   `json:"shared,omitempty"`
+}
+
+func newCallCommand(txID string, method string, args ...interface{}) (*command, error) {
+	cmd := &command{
+		Type:   cmdCall,
+		TxID:   txID,
+		Method: method,
+		Args:   make([]json.RawMessage, 0, len(args)),
+	}
+	for _, arg := range args {
+		data, err := json.Marshal(arg)
+		if err != nil {
+			return nil, fmt.Errorf("fail to encode args of %s, err is %v", method, err)
+		}
+		cmd.Args = append(cmd.Args, data)
+	}
+	return cmd, nil
+}
+
+// methodType 查找命令对应的接口方法定义，只允许调用 store.Store、store.RestoreStore 以及 store.Transaction 的方法
+func methodType(method string) (reflect.Method, bool) {
+	if name, ok := transactionMethod(method); ok {
+		return transactionType.MethodByName(name)
+	}
+	if m, ok := restoreStoreType.MethodByName(method); ok {
+		return m, true
+	}
+	return storeType.MethodByName(method)
+}
+
+// transactionMethod 判断是否为 store.Transaction 的方法，并返回去掉前缀的方法名
+func transactionMethod(method string) (string, bool) {
+	if !strings.HasPrefix(method, transactionMethodPrefix) {
+		return "", false
+	}
+	return strings.TrimPrefix(method, transactionMethodPrefix), true
+}
+
+// applyResult 命令的执行结果
+type applyResult struct {
+	values []interface{}
+	err    error
+}
+
+// encodedResult 转发给 leader 执行后返回的结果
+type encodedResult struct {
+	Values  []json.RawMessage `json:"values,omitempty"`
+	Failed  bool              `json:"failed,omitempty"`
+	Status  bool              `json:"status,omitempty"`
+	Code    store.StatusCode  `json:"code,omitempty"`
+	Message string            `json:"message,omitempty"`
+}
+
+func encodeResult(values []interface{}, err error) ([]byte, error) {
+	ret := &encodedResult{}
+	if err != nil {
+		ret.Failed = true
+		ret.Message = err.Error()
+		if _, ok := err.(*store.StatusError); ok {
+			ret.Status = true
+			ret.Code = store.Code(err)
+		}
+		return json.Marshal(ret)
+	}
+	for _, value := range values {
+		data, err := json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+		ret.Values = append(ret.Values, data)
+	}
+	return json.Marshal(ret)
+}
+
+// decodeResult 按照方法的返回值类型解析 leader 返回的结果
+func decodeResult(method string, data []byte) ([]interface{}, error) {
+	ret := &encodedResult{}
+	if err := json.Unmarshal(data, ret); err != nil {
+		return nil, err
+	}
+	if ret.Failed {
+		if ret.Status {
+			return nil, store.NewStatusError(ret.Code, ret.Message)
+		}
+		return nil, errors.New(ret.Message)
+	}
+	if method == "" {
+		return nil, nil
+	}
+	m, ok := methodType(method)
+	if !ok {
+		return nil, fmt.Errorf("raft store unknown method %s", method)
+	}
+	numOut := m.Type.NumOut() - 1
+	if len(ret.Values) != numOut {
+		return nil, fmt.Errorf("raft store %s expect %d values, got %d", method, numOut, len(ret.Values))
+	}
+	values := make([]interface{}, 0, numOut)
+	for i := 0; i < numOut; i++ {
+		value := reflect.New(m.Type.Out(i))
+		if err := json.Unmarshal(ret.Values[i], value.Interface()); err != nil {
+			return nil, err
+		}
+		values = append(values, value.Elem().Interface())
+	}
+	return values, nil
+}
@@ -0,0 +1,146 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package raftdb
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/mitchellh/mapstructure"
+)
+
+const (
+	defaultPath              = "./polaris.bolt"
+	defaultDataDir           = "./raft"
+	defaultApplyTimeout      = 10 * time.Second
+	defaultLockTimeout       = 10 * time.Second
+	defaultLockTTL           = time.Minute
+	defaultSnapshotInterval  = 2 * time.Minute
+	defaultSnapshotThreshold = 8192
+	defaultSnapshotRetain    = 2
+)
+
+// Config raft 存储插件的配置
+type Config struct {
+	// Path 本地 boltdb 状态机文件
+	Path string `mapstructure:"path"`
+	// DataDir raft 日志以及快照的保存目录
+	DataDir string `mapstructure:"dataDir"`
+	// NodeID 当前节点在集群中的ID，需要与 peers 中的某一项对应
+	NodeID string `mapstructure:"nodeId"`
+	// Bootstrap 集群首次启动时由该节点按照 peers 初始化集群成员，已有 raft 数据时忽略
+	Bootstrap bool `mapstructure:"bootstrap"`
+	// Peers 集群的全部节点，包括当前节点
+	Peers []Peer `mapstructure:"peers"`
+	// ApplyTimeout 等待 leader 以及写操作提交的超时时间
+	ApplyTimeout time.Duration `mapstructure:"applyTimeout"`
+	// LockTimeout 等待 leader 上的锁的超时时间
+	LockTimeout time.Duration `mapstructure:"lockTimeout"`
+	// LockTTL 锁以及事务的租约，持有者超过该时长没有操作时 leader 会自动释放
+	LockTTL time.Duration `mapstructure:"lockTTL"`
+	// SnapshotInterval 检查是否需要生成快照的间隔
+	SnapshotInterval time.Duration `mapstructure:"snapshotInterval"`
+	// SnapshotThreshold 距离上一次快照超过该条数的日志时生成快照
+	SnapshotThreshold uint64 `mapstructure:"snapshotThreshold"`
+}
+
+// Peer 集群中的节点
+type Peer struct {
+	NodeID string `mapstructure:"nodeId"`
+	// RaftAddr raft 协议通信的地址
+	RaftAddr string `mapstructure:"raftAddr"`
+	// ForwardAddr 接收其他节点转发写操作的 gRPC 地址
+	ForwardAddr string `mapstructure:"forwardAddr"`
+}
+
+func parseConfig(option map[string]interface{}) (*Config, error) {
+	conf := &Config{}
+	decoder, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+		DecodeHook: mapstructure.StringToTimeDurationHookFunc(),
+		Result:     conf,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err = decoder.Decode(option); err != nil {
+		return nil, fmt.Errorf("fail to decode %s config, err is %v", STORENAME, err)
+	}
+	conf.setDefault()
+	if err = conf.validate(); err != nil {
+		return nil, err
+	}
+	return conf, nil
+}
+
+func (c *Config) setDefault() {
+	if c.Path == "" {
+		c.Path = defaultPath
+	}
+	if c.DataDir == "" {
+		c.DataDir = defaultDataDir
+	}
+	if c.ApplyTimeout <= 0 {
+		c.ApplyTimeout = defaultApplyTimeout
+	}
+	if c.LockTimeout <= 0 {
+		c.LockTimeout = defaultLockTimeout
+	}
+	if c.LockTTL <= 0 {
+		c.LockTTL = defaultLockTTL
+	}
+	if c.SnapshotInterval <= 0 {
+		c.SnapshotInterval = defaultSnapshotInterval
+	}
+	if c.SnapshotThreshold == 0 {
+		c.SnapshotThreshold = defaultSnapshotThreshold
+	}
+}
+
+func (c *Config) validate() error {
+	if c.NodeID == "" {
+		return errors.New("raft store nodeId is empty")
+	}
+	if c.localPeer() == nil {
+		return fmt.Errorf("raft store node %s not found in peers", c.NodeID)
+	}
+	ids := make(map[string]struct{}, len(c.Peers))
+	for _, peer := range c.Peers {
+		if peer.NodeID == "" || peer.RaftAddr == "" || peer.ForwardAddr == "" {
+			return errors.New("raft store peer nodeId, raftAddr and forwardAddr are required")
+		}
+		if _, ok := ids[peer.NodeID]; ok {
+			return fmt.Errorf("raft store duplicate peer %s", peer.NodeID)
+		}
+		ids[peer.NodeID] = struct{}{}
+	}
+	return nil
+}
+
+func (c *Config) localPeer() *Peer {
+	return c.peer(c.NodeID)
+}
+
+func (c *Config) peer(nodeID string) *Peer {
+	for i := range c.Peers {
+		if c.Peers[i].NodeID == nodeID {
+			return &c.Peers[i]
+		}
+	}
+	return nil
+}
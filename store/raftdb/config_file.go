@@ -0,0 +1,197 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package raftdb
+
+import (
+	"github.com/polarismesh/polaris/common/model"
+	"github.com/polarismesh/polaris/store"
+)
+
+// CreateConfigFileGroup 创建配置文件组
+func (s *raftStore) CreateConfigFileGroup(fileGroup *model.ConfigFileGroup) (*model.ConfigFileGroup, error) {
+	ret, err := s.call(nil, "CreateConfigFileGroup", fileGroup)
+	if err != nil {
+		return nil, err
+	}
+	return ret[0].(*model.ConfigFileGroup), nil
+}
+
+// DeleteConfigFileGroup 删除配置文件组
+func (s *raftStore) DeleteConfigFileGroup(namespace, name string) error {
+	return s.exec("DeleteConfigFileGroup", namespace, name)
+}
+
+// UpdateConfigFileGroup 更新配置文件组
+func (s *raftStore) UpdateConfigFileGroup(fileGroup *model.ConfigFileGroup) (*model.ConfigFileGroup, error) {
+	ret, err := s.call(nil, "UpdateConfigFileGroup", fileGroup)
+	if err != nil {
+		return nil, err
+	}
+	return ret[0].(*model.ConfigFileGroup), nil
+}
+
+// UpdateConfigFileGroupSchema 更新配置文件组的 JSON Schema
+func (s *raftStore) UpdateConfigFileGroupSchema(namespace, name, schema, modifyBy string) error {
+	return s.exec("UpdateConfigFileGroupSchema", namespace, name, schema, modifyBy)
+}
+
+// CreateConfigFile 创建配置文件
+func (s *raftStore) CreateConfigFile(tx store.Tx, file *model.ConfigFile) (*model.ConfigFile, error) {
+	ret, err := s.call(tx, "CreateConfigFile", file)
+	if err != nil {
+		return nil, err
+	}
+	return ret[0].(*model.ConfigFile), nil
+}
+
+// GetConfigFile 获取配置文件
+func (s *raftStore) GetConfigFile(tx store.Tx, namespace, group, name string) (*model.ConfigFile, error) {
+	if tx == nil {
+		return s.Store.GetConfigFile(nil, namespace, group, name)
+	}
+	ret, err := s.call(tx, "GetConfigFile", namespace, group, name)
+	if err != nil {
+		return nil, err
+	}
+	return ret[0].(*model.ConfigFile), nil
+}
+
+// UpdateConfigFile 更新配置文件
+func (s *raftStore) UpdateConfigFile(tx store.Tx, file *model.ConfigFile) (*model.ConfigFile, error) {
+	ret, err := s.call(tx, "UpdateConfigFile", file)
+	if err != nil {
+		return nil, err
+	}
+	return ret[0].(*model.ConfigFile), nil
+}
+
+// DeleteConfigFile 删除配置文件
+func (s *raftStore) DeleteConfigFile(tx store.Tx, namespace, group, name string) error {
+	return s.execTx(tx, "DeleteConfigFile", namespace, group, name)
+}
+
+// CreateConfigFileRelease 创建配置文件发布
+func (s *raftStore) CreateConfigFileRelease(tx store.Tx, fileRelease *model.ConfigFileRelease) (*model.ConfigFileRelease, error) {
+	ret, err := s.call(tx, "CreateConfigFileRelease", fileRelease)
+	if err != nil {
+		return nil, err
+	}
+	return ret[0].(*model.ConfigFileRelease), nil
+}
+
+// UpdateConfigFileRelease 更新配置文件发布
+func (s *raftStore) UpdateConfigFileRelease(tx store.Tx, fileRelease *model.ConfigFileRelease) (*model.ConfigFileRelease, error) {
+	ret, err := s.call(tx, "UpdateConfigFileRelease", fileRelease)
+	if err != nil {
+		return nil, err
+	}
+	return ret[0].(*model.ConfigFileRelease), nil
+}
+
+// GetConfigFileRelease 获取配置文件发布内容，只获取 flag=0 的记录
+func (s *raftStore) GetConfigFileRelease(tx store.Tx, namespace, group, fileName string) (*model.ConfigFileRelease, error) {
+	if tx == nil {
+		return s.Store.GetConfigFileRelease(nil, namespace, group, fileName)
+	}
+	ret, err := s.call(tx, "GetConfigFileRelease", namespace, group, fileName)
+	if err != nil {
+		return nil, err
+	}
+	return ret[0].(*model.ConfigFileRelease), nil
+}
+
+// GetConfigFileReleaseWithAllFlag 获取配置文件发布内容，返回所有 flag 的记录
+func (s *raftStore) GetConfigFileReleaseWithAllFlag(tx store.Tx, namespace, group, fileName string) (*model.ConfigFileRelease, error) {
+	if tx == nil {
+		return s.Store.GetConfigFileReleaseWithAllFlag(nil, namespace, group, fileName)
+	}
+	ret, err := s.call(tx, "GetConfigFileReleaseWithAllFlag", namespace, group, fileName)
+	if err != nil {
+		return nil, err
+	}
+	return ret[0].(*model.ConfigFileRelease), nil
+}
+
+// DeleteConfigFileRelease 删除配置文件发布内容
+func (s *raftStore) DeleteConfigFileRelease(tx store.Tx, namespace, group, fileName, deleteBy string) error {
+	return s.execTx(tx, "DeleteConfigFileRelease", namespace, group, fileName, deleteBy)
+}
+
+// CreateConfigFileGrayRelease 创建配置文件灰度发布
+func (s *raftStore) CreateConfigFileGrayRelease(tx store.Tx, grayRelease *model.ConfigFileGrayRelease) (*model.ConfigFileGrayRelease, error) {
+	ret, err := s.call(tx, "CreateConfigFileGrayRelease", grayRelease)
+	if err != nil {
+		return nil, err
+	}
+	return ret[0].(*model.ConfigFileGrayRelease), nil
+}
+
+// UpdateConfigFileGrayRelease 更新配置文件灰度发布，同时恢复 flag=0
+func (s *raftStore) UpdateConfigFileGrayRelease(tx store.Tx, grayRelease *model.ConfigFileGrayRelease) (*model.ConfigFileGrayRelease, error) {
+	ret, err := s.call(tx, "UpdateConfigFileGrayRelease", grayRelease)
+	if err != nil {
+		return nil, err
+	}
+	return ret[0].(*model.ConfigFileGrayRelease), nil
+}
+
+// GetConfigFileGrayReleaseWithAllFlag 获取配置文件灰度发布，返回所有 flag 的记录
+func (s *raftStore) GetConfigFileGrayReleaseWithAllFlag(tx store.Tx, namespace, group, fileName string) (*model.ConfigFileGrayRelease, error) {
+	if tx == nil {
+		return s.Store.GetConfigFileGrayReleaseWithAllFlag(nil, namespace, group, fileName)
+	}
+	ret, err := s.call(tx, "GetConfigFileGrayReleaseWithAllFlag", namespace, group, fileName)
+	if err != nil {
+		return nil, err
+	}
+	return ret[0].(*model.ConfigFileGrayRelease), nil
+}
+
+// DeleteConfigFileGrayRelease 删除配置文件灰度发布，只标记 flag=1
+func (s *raftStore) DeleteConfigFileGrayRelease(tx store.Tx, namespace, group, fileName, deleteBy string) error {
+	return s.execTx(tx, "DeleteConfigFileGrayRelease", namespace, group, fileName, deleteBy)
+}
+
+// CreateConfigFileReleaseHistory 创建配置文件发布历史记录
+func (s *raftStore) CreateConfigFileReleaseHistory(tx store.Tx, fileReleaseHistory *model.ConfigFileReleaseHistory) error {
+	return s.execTx(tx, "CreateConfigFileReleaseHistory", fileReleaseHistory)
+}
+
+// CreateConfigFileTag 创建配置文件标签
+func (s *raftStore) CreateConfigFileTag(tx store.Tx, fileTag *model.ConfigFileTag) error {
+	return s.execTx(tx, "CreateConfigFileTag", fileTag)
+}
+
+// DeleteConfigFileTag 删除配置文件标签
+func (s *raftStore) DeleteConfigFileTag(tx store.Tx, namespace, group, fileName, key, value string) error {
+	return s.execTx(tx, "DeleteConfigFileTag", namespace, group, fileName, key, value)
+}
+
+// DeleteTagByConfigFile 删除配置文件标签
+func (s *raftStore) DeleteTagByConfigFile(tx store.Tx, namespace, group, fileName string) error {
+	return s.execTx(tx, "DeleteTagByConfigFile", namespace, group, fileName)
+}
+
+// CreateConfigFileTemplate create config file template
+func (s *raftStore) CreateConfigFileTemplate(template *model.ConfigFileTemplate) (*model.ConfigFileTemplate, error) {
+	ret, err := s.call(nil, "CreateConfigFileTemplate", template)
+	if err != nil {
+		return nil, err
+	}
+	return ret[0].(*model.ConfigFileTemplate), nil
+}
@@ -0,0 +1,394 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package raftdb
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/raft"
+	raftboltdb "github.com/hashicorp/raft-boltdb"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+
+	"github.com/polarismesh/polaris/common/utils"
+	"github.com/polarismesh/polaris/store"
+	"github.com/polarismesh/polaris/store/boltdb"
+)
+
+const (
+	STORENAME = "raftdbStore"
+
+	raftLogFile         = "raft.db"
+	transportMaxPool    = 3
+	transportTimeout    = 10 * time.Second
+	leaderCheckInterval = 50 * time.Millisecond
+	lockExpireInterval  = time.Second
+)
+
+// raftStore 通过 raft 在多个节点之间复制 boltdb 状态机的存储插件
+// 写操作以及事务由 leader 写入 raft 日志后在每个节点上按顺序执行，读操作直接访问本地的 boltdb
+type raftStore struct {
+	// Store 本地的 boltdb 状态机
+	store.Store
+
+	conf      *Config
+	fsm       *fsm
+	raft      *raft.Raft
+	transport *raft.NetworkTransport
+	logStore  *raftboltdb.BoltStore
+	locks     *lockManager
+	server    *grpc.Server
+	stopCh    chan struct{}
+
+	connMutex sync.Mutex
+	conns     map[string]*grpc.ClientConn
+
+	start bool
+}
+
+// Name store name
+func (s *raftStore) Name() string {
+	return STORENAME
+}
+
+// Initialize init store
+func (s *raftStore) Initialize(c *store.Config) error {
+	if s.start {
+		return nil
+	}
+	conf, err := parseConfig(c.Option)
+	if err != nil {
+		return err
+	}
+	s.conf = conf
+	s.locks = newLockManager(conf.LockTTL)
+	s.conns = make(map[string]*grpc.ClientConn)
+	s.stopCh = make(chan struct{})
+
+	local := boltdb.NewStore()
+	if err = local.Initialize(localStoreConfig(conf.Path)); err != nil {
+		return err
+	}
+	s.Store = local
+	s.fsm = newFSM(local)
+
+	if err = s.startRaft(); err != nil {
+		_ = s.Destroy()
+		return err
+	}
+	if err = s.startForwardServer(); err != nil {
+		_ = s.Destroy()
+		return err
+	}
+	go s.watchLeader(s.raft, s.stopCh)
+	s.start = true
+	return nil
+}
+
+func (s *raftStore) startRaft() error {
+	conf := s.conf
+	if err := os.MkdirAll(conf.DataDir, 0755); err != nil {
+		return err
+	}
+	logger := hclog.New(&hclog.LoggerOptions{
+		Name:   "raft",
+		Level:  hclog.Info,
+		Output: &logWriter{},
+	})
+
+	logStore, err := raftboltdb.NewBoltStore(filepath.Join(conf.DataDir, raftLogFile))
+	if err != nil {
+		return err
+	}
+	s.logStore = logStore
+	snapshots, err := raft.NewFileSnapshotStoreWithLogger(conf.DataDir, defaultSnapshotRetain, logger)
+	if err != nil {
+		return err
+	}
+	local := conf.localPeer()
+	addr, err := net.ResolveTCPAddr("tcp", local.RaftAddr)
+	if err != nil {
+		return err
+	}
+	transport, err := raft.NewTCPTransportWithLogger(local.RaftAddr, addr, transportMaxPool, transportTimeout, logger)
+	if err != nil {
+		return err
+	}
+	s.transport = transport
+
+	raftConf := raft.DefaultConfig()
+	raftConf.LocalID = raft.ServerID(conf.NodeID)
+	raftConf.Logger = logger
+	raftConf.SnapshotInterval = conf.SnapshotInterval
+	raftConf.SnapshotThreshold = conf.SnapshotThreshold
+
+	hasState, err := raft.HasExistingState(logStore, logStore, snapshots)
+	if err != nil {
+		return err
+	}
+	s.raft, err = raft.NewRaft(raftConf, s.fsm, logStore, logStore, snapshots, transport)
+	if err != nil {
+		return err
+	}
+	if conf.Bootstrap && !hasState {
+		servers := make([]raft.Server, 0, len(conf.Peers))
+		for _, peer := range conf.Peers {
+			servers = append(servers, raft.Server{
+				ID:      raft.ServerID(peer.NodeID),
+				Address: raft.ServerAddress(peer.RaftAddr),
+			})
+		}
+		if err = s.raft.BootstrapCluster(raft.Configuration{Servers: servers}).Error(); err != nil {
+			return err
+		}
+		log.Info("[Store][raft] bootstrap cluster", zap.Int("peers", len(servers)))
+	}
+	return nil
+}
+
+// watchLeader leader 切换时清理锁，并定期回滚租约过期的事务
+func (s *raftStore) watchLeader(r *raft.Raft, stopCh chan struct{}) {
+	ticker := time.NewTicker(lockExpireInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case isLeader := <-r.LeaderCh():
+			log.Info("[Store][raft] leadership changed", zap.String("node", s.conf.NodeID),
+				zap.Bool("leader", isLeader))
+			s.locks.reset()
+		case <-ticker.C:
+			for _, txID := range s.locks.expire(time.Now()) {
+				log.Warn("[Store][raft] rollback expired transaction", zap.String("id", txID))
+				if _, err := s.propose(&command{Type: cmdRollbackTx, TxID: txID}); err != nil {
+					log.Error("[Store][raft] fail to rollback expired transaction", zap.String("id", txID),
+						zap.Error(err))
+				}
+			}
+		case <-stopCh:
+			return
+		}
+	}
+}
+
+// Destroy 关闭 raft 以及本地存储
+func (s *raftStore) Destroy() error {
+	s.start = false
+	if s.stopCh != nil {
+		close(s.stopCh)
+		s.stopCh = nil
+	}
+	if s.server != nil {
+		s.server.Stop()
+		s.server = nil
+	}
+	s.connMutex.Lock()
+	for addr, conn := range s.conns {
+		_ = conn.Close()
+		delete(s.conns, addr)
+	}
+	s.connMutex.Unlock()
+	if s.raft != nil {
+		if err := s.raft.Shutdown().Error(); err != nil {
+			log.Error("[Store][raft] fail to shutdown raft", zap.Error(err))
+		}
+		s.raft = nil
+	}
+	if s.transport != nil {
+		_ = s.transport.Close()
+		s.transport = nil
+	}
+	if s.logStore != nil {
+		_ = s.logStore.Close()
+		s.logStore = nil
+	}
+	if s.Store != nil {
+		return s.Store.Destroy()
+	}
+	return nil
+}
+
+// CreateTransaction 创建事务对象，锁由 leader 统一管理
+func (s *raftStore) CreateTransaction() (store.Transaction, error) {
+	return &transaction{s: s, session: utils.NewUUID()}, nil
+}
+
+// StartTx 在 leader 上开启一个事务，事务内的读写都会写入 raft 日志
+func (s *raftStore) StartTx() (store.Tx, error) {
+	id := utils.NewUUID()
+	if _, err := s.submit(&command{Type: cmdBeginTx, TxID: id}); err != nil {
+		return nil, err
+	}
+	return &raftTx{s: s, id: id}, nil
+}
+
+// Snapshot 本地状态机的只读数据视图，当前节点为 leader 时先等待已提交的日志全部执行完成
+func (s *raftStore) Snapshot() (store.Store, func(), error) {
+	snapshotter, ok := s.Store.(store.SnapshotStore)
+	if !ok {
+		return nil, nil, fmt.Errorf("raft local store %s not support snapshot", s.Store.Name())
+	}
+	if s.raft.State() == raft.Leader {
+		if err := s.raft.Barrier(s.conf.ApplyTimeout).Error(); err != nil {
+			return nil, nil, err
+		}
+	}
+	return snapshotter.Snapshot()
+}
+
+// call 将存储方法的调用提交到集群执行，tx 为空时表示不在事务内
+func (s *raftStore) call(tx store.Tx, method string, args ...interface{}) ([]interface{}, error) {
+	var txID string
+	if tx != nil {
+		raftTx, ok := tx.(*raftTx)
+		if !ok {
+			return nil, fmt.Errorf("raft store unsupported tx type %T", tx)
+		}
+		txID = raftTx.id
+	}
+	cmd, err := newCallCommand(txID, method, args...)
+	if err != nil {
+		return nil, err
+	}
+	return s.submit(cmd)
+}
+
+// exec 执行只返回 error 的写操作
+func (s *raftStore) exec(method string, args ...interface{}) error {
+	_, err := s.call(nil, method, args...)
+	return err
+}
+
+// execTx 在事务内执行只返回 error 的写操作
+func (s *raftStore) execTx(tx store.Tx, method string, args ...interface{}) error {
+	_, err := s.call(tx, method, args...)
+	return err
+}
+
+// submit 当前节点为 leader 时直接执行，否则转发给 leader
+func (s *raftStore) submit(cmd *command) ([]interface{}, error) {
+	deadline := time.Now().Add(s.conf.ApplyTimeout)
+	for {
+		if s.raft.State() == raft.Leader {
+			return s.leaderApply(cmd)
+		}
+		_, leaderID := s.raft.LeaderWithID()
+		if leaderID != "" {
+			return s.forward(string(leaderID), cmd)
+		}
+		if time.Now().After(deadline) {
+			return nil, errNoLeader
+		}
+		time.Sleep(leaderCheckInterval)
+	}
+}
+
+var errNoLeader = errors.New("raft store no leader")
+
+// leaderApply 在 leader 上处理命令，加锁后写入 raft 日志
+func (s *raftStore) leaderApply(cmd *command) ([]interface{}, error) {
+	switch cmd.Type {
+	case cmdLock:
+		if err := s.locks.lock(cmd.TxID, cmd.Key, cmd.Shared, s.conf.LockTimeout); err != nil {
+			return nil, err
+		}
+		if cmd.Method == "" {
+			return nil, nil
+		}
+		return s.leaderRead(cmd)
+	case cmdUnlock:
+		s.locks.unlock(cmd.TxID)
+		return nil, nil
+	case cmdBeginTx:
+		if err := s.locks.lock(cmd.TxID, writeLockKey, false, s.conf.LockTimeout); err != nil {
+			return nil, err
+		}
+		values, err := s.propose(cmd)
+		if err != nil {
+			s.locks.unlock(cmd.TxID)
+		}
+		return values, err
+	case cmdCommitTx, cmdRollbackTx:
+		defer s.locks.unlock(cmd.TxID)
+		return s.propose(cmd)
+	case cmdCall:
+		if cmd.TxID != "" {
+			if !s.locks.holdExclusive(cmd.TxID, writeLockKey) {
+				return nil, errTxNotFound
+			}
+			return s.propose(cmd)
+		}
+		session := utils.NewUUID()
+		if err := s.locks.lock(session, writeLockKey, true, s.conf.LockTimeout); err != nil {
+			return nil, err
+		}
+		defer s.locks.unlock(session)
+		return s.propose(cmd)
+	default:
+		return nil, fmt.Errorf("raft store unknown command type %d", cmd.Type)
+	}
+}
+
+// leaderRead 在 leader 上执行读操作，先等待之前提交的日志全部执行完成，保证读到最新的数据
+func (s *raftStore) leaderRead(cmd *command) ([]interface{}, error) {
+	if _, ok := lockReadMethods[cmd.Method]; !ok {
+		return nil, fmt.Errorf("raft store method %s not allowed when lock", cmd.Method)
+	}
+	if err := s.raft.Barrier(s.conf.ApplyTimeout).Error(); err != nil {
+		return nil, err
+	}
+	ret := s.fsm.call(nil, cmd)
+	return ret.values, ret.err
+}
+
+// propose 写入 raft 日志，等待本节点的状态机执行完成
+func (s *raftStore) propose(cmd *command) ([]interface{}, error) {
+	data, err := json.Marshal(cmd)
+	if err != nil {
+		return nil, err
+	}
+	future := s.raft.Apply(data, s.conf.ApplyTimeout)
+	if err = future.Error(); err != nil {
+		return nil, err
+	}
+	ret, ok := future.Response().(*applyResult)
+	if !ok {
+		return nil, errors.New("raft store unknown apply result")
+	}
+	return ret.values, ret.err
+}
+
+// logWriter 将 raft 的日志输出到存储层的日志中
+type logWriter struct{}
+
+func (w *logWriter) Write(p []byte) (int, error) {
+	log.Info(strings.TrimSpace(string(p)))
+	return len(p), nil
+}
+
+func init() {
+	_ = store.RegisterStore(&raftStore{})
+}
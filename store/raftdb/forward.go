@@ -0,0 +1,110 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package raftdb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+
+	"github.com/hashicorp/raft"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// startForwardServer 启动接收转发请求的 gRPC 服务
+func (s *raftStore) startForwardServer() error {
+	listener, err := net.Listen("tcp", s.conf.localPeer().ForwardAddr)
+	if err != nil {
+		return err
+	}
+	s.server = grpc.NewServer()
+	RegisterRaftStoreServer(s.server, &forwardServer{s: s})
+	go func(server *grpc.Server) {
+		if err := server.Serve(listener); err != nil {
+			log.Error("[Store][raft] forward server stopped", zap.Error(err))
+		}
+	}(s.server)
+	return nil
+}
+
+// forward 将命令转发给 leader 执行
+func (s *raftStore) forward(leaderID string, cmd *command) ([]interface{}, error) {
+	peer := s.conf.peer(leaderID)
+	if peer == nil {
+		return nil, fmt.Errorf("raft store leader %s not found in peers", leaderID)
+	}
+	conn, err := s.getConn(peer.ForwardAddr)
+	if err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(cmd)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), s.conf.ApplyTimeout+s.conf.LockTimeout)
+	defer cancel()
+	rsp, err := NewRaftStoreClient(conn).Forward(ctx, &ForwardRequest{Command: data})
+	if err != nil {
+		return nil, err
+	}
+	return decodeResult(cmd.Method, rsp.GetResult())
+}
+
+func (s *raftStore) getConn(addr string) (*grpc.ClientConn, error) {
+	s.connMutex.Lock()
+	defer s.connMutex.Unlock()
+	if conn, ok := s.conns[addr]; ok {
+		return conn, nil
+	}
+	conn, err := grpc.Dial(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, err
+	}
+	s.conns[addr] = conn
+	return conn, nil
+}
+
+// forwardServer 处理其他节点转发过来的命令
+type forwardServer struct {
+	s *raftStore
+}
+
+// Forward 在 leader 上执行转发过来的命令，当前节点已经不是 leader 时直接返回错误，避免在节点之间来回转发
+func (f *forwardServer) Forward(_ context.Context, req *ForwardRequest) (*ForwardResponse, error) {
+	cmd := &command{}
+	if err := json.Unmarshal(req.GetCommand(), cmd); err != nil {
+		return nil, err
+	}
+	var (
+		values []interface{}
+		err    error
+	)
+	if f.s.raft.State() == raft.Leader {
+		values, err = f.s.leaderApply(cmd)
+	} else {
+		err = raft.ErrNotLeader
+	}
+	result, err := encodeResult(values, err)
+	if err != nil {
+		return nil, err
+	}
+	return &ForwardResponse{Result: result}, nil
+}
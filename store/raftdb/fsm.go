@@ -0,0 +1,217 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package raftdb
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+
+	"github.com/hashicorp/raft"
+	"go.uber.org/zap"
+
+	"github.com/polarismesh/polaris/store"
+	"github.com/polarismesh/polaris/store/boltdb"
+)
+
+// fsm 以本地的 boltdb 存储作为 raft 的状态机，按照日志的顺序在每个节点上执行相同的写操作
+type fsm struct {
+	local store.Store
+	// txs 尚未提交的事务，leader 保证同一时间最多只有一个事务
+	txs map[string]store.Tx
+}
+
+func newFSM(local store.Store) *fsm {
+	return &fsm{
+		local: local,
+		txs:   make(map[string]store.Tx),
+	}
+}
+
+// Apply 执行 raft 日志中的命令
+func (f *fsm) Apply(l *raft.Log) interface{} {
+	cmd := &command{}
+	if err := json.Unmarshal(l.Data, cmd); err != nil {
+		log.Error("[Store][raft] fail to decode command", zap.Uint64("index", l.Index), zap.Error(err))
+		return &applyResult{err: err}
+	}
+	return f.apply(cmd)
+}
+
+func (f *fsm) apply(cmd *command) *applyResult {
+	switch cmd.Type {
+	case cmdBeginTx:
+		f.abortTxs()
+		tx, err := f.local.StartTx()
+		if err != nil {
+			return &applyResult{err: err}
+		}
+		f.txs[cmd.TxID] = tx
+		return &applyResult{}
+	case cmdCommitTx, cmdRollbackTx:
+		tx, ok := f.txs[cmd.TxID]
+		if !ok {
+			return &applyResult{err: errTxNotFound}
+		}
+		delete(f.txs, cmd.TxID)
+		if cmd.Type == cmdRollbackTx {
+			return &applyResult{err: tx.Rollback()}
+		}
+		return &applyResult{err: tx.Commit()}
+	case cmdCall:
+		var tx store.Tx
+		if cmd.TxID != "" {
+			var ok bool
+			if tx, ok = f.txs[cmd.TxID]; !ok {
+				return &applyResult{err: errTxNotFound}
+			}
+		} else {
+			f.abortTxs()
+		}
+		return f.call(tx, cmd)
+	default:
+		return &applyResult{err: fmt.Errorf("raft store unknown command type %d", cmd.Type)}
+	}
+}
+
+// abortTxs 回滚遗留的事务，只会在 leader 切换导致事务没有结束时出现，避免占用 boltdb 的写锁
+func (f *fsm) abortTxs() {
+	for id, tx := range f.txs {
+		log.Warn("[Store][raft] rollback unfinished transaction", zap.String("id", id))
+		_ = tx.Rollback()
+		delete(f.txs, id)
+	}
+}
+
+// call 通过反射调用本地存储的方法，参数按照方法定义的类型反序列化
+func (f *fsm) call(tx store.Tx, cmd *command) (ret *applyResult) {
+	m, ok := methodType(cmd.Method)
+	if !ok {
+		return &applyResult{err: fmt.Errorf("raft store unknown method %s", cmd.Method)}
+	}
+	target := reflect.ValueOf(f.local)
+	if _, ok := transactionMethod(cmd.Method); ok {
+		transaction, err := f.local.CreateTransaction()
+		if err != nil {
+			return &applyResult{err: err}
+		}
+		target = reflect.ValueOf(transaction)
+	}
+	method := target.MethodByName(m.Name)
+
+	args := make([]reflect.Value, 0, m.Type.NumIn())
+	next := 0
+	for i := 0; i < m.Type.NumIn(); i++ {
+		in := m.Type.In(i)
+		if in == txType {
+			if tx == nil {
+				args = append(args, reflect.Zero(txType))
+			} else {
+				args = append(args, reflect.ValueOf(tx))
+			}
+			continue
+		}
+		if next >= len(cmd.Args) {
+			return &applyResult{err: fmt.Errorf("raft store %s missing args", cmd.Method)}
+		}
+		value := reflect.New(in)
+		if err := json.Unmarshal(cmd.Args[next], value.Interface()); err != nil {
+			return &applyResult{err: fmt.Errorf("fail to decode args of %s, err is %v", cmd.Method, err)}
+		}
+		args = append(args, value.Elem())
+		next++
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			log.Error("[Store][raft] apply command panic", zap.String("method", cmd.Method), zap.Any("err", r))
+			ret = &applyResult{err: fmt.Errorf("raft store %s panic: %v", cmd.Method, r)}
+		}
+	}()
+	out := method.Call(args)
+	ret = &applyResult{}
+	for i, value := range out {
+		if m.Type.Out(i) == errorType {
+			if !value.IsNil() {
+				ret.err = value.Interface().(error)
+			}
+			continue
+		}
+		ret.values = append(ret.values, value.Interface())
+	}
+	return ret
+}
+
+// Snapshot 在状态机的协程中复制一份已提交的数据，避免持久化快照时数据继续变化
+func (f *fsm) Snapshot() (raft.FSMSnapshot, error) {
+	writer, ok := f.local.(io.WriterTo)
+	if !ok {
+		return nil, errors.New("raft store local store not support snapshot")
+	}
+	buf := &bytes.Buffer{}
+	if _, err := writer.WriteTo(buf); err != nil {
+		return nil, err
+	}
+	return &fsmSnapshot{data: buf.Bytes()}, nil
+}
+
+// Restore 使用快照替换本地的 boltdb 文件，替换在本地存储内部原子完成，不会重新写入初始化数据
+func (f *fsm) Restore(rc io.ReadCloser) error {
+	defer rc.Close()
+	f.abortTxs()
+
+	reader, ok := f.local.(io.ReaderFrom)
+	if !ok {
+		return errors.New("raft store local store not support restore")
+	}
+	if _, err := reader.ReadFrom(rc); err != nil {
+		return err
+	}
+	log.Info("[Store][raft] local store restored from snapshot")
+	return nil
+}
+
+// localStoreConfig 本地 boltdb 的配置，初始化数据使用固定的 token，保证每个节点得到相同的数据
+func localStoreConfig(path string) *store.Config {
+	return &store.Config{
+		Name: boltdb.STORENAME,
+		Option: map[string]interface{}{
+			"path":           path,
+			"fixedInitToken": true,
+		},
+	}
+}
+
+type fsmSnapshot struct {
+	data []byte
+}
+
+// Persist 写出快照
+func (s *fsmSnapshot) Persist(sink raft.SnapshotSink) error {
+	if _, err := sink.Write(s.data); err != nil {
+		_ = sink.Cancel()
+		return err
+	}
+	return sink.Close()
+}
+
+// Release 释放快照
+func (s *fsmSnapshot) Release() {}
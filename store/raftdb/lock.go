@@ -0,0 +1,175 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package raftdb
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+const lockRetryInterval = 10 * time.Millisecond
+
+var errLockTimeout = errors.New("raft store wait lock timeout")
+
+// lockEntry 资源上的锁，同一个会话可以重入
+type lockEntry struct {
+	exclusive string
+	shared    map[string]struct{}
+}
+
+// lockSession 持有锁的会话，事务以及 store.Transaction 各自对应一个会话
+type lockSession struct {
+	keys   map[string]struct{}
+	expire time.Time
+}
+
+// lockManager leader 上的锁，leader 切换后由新的 leader 重新开始计算
+type lockManager struct {
+	mutex    sync.Mutex
+	ttl      time.Duration
+	locks    map[string]*lockEntry
+	sessions map[string]*lockSession
+}
+
+func newLockManager(ttl time.Duration) *lockManager {
+	return &lockManager{
+		ttl:      ttl,
+		locks:    make(map[string]*lockEntry),
+		sessions: make(map[string]*lockSession),
+	}
+}
+
+// lock 加锁，等待超过 timeout 后返回 errLockTimeout
+func (m *lockManager) lock(session, key string, shared bool, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		if m.tryLock(session, key, shared) {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return errLockTimeout
+		}
+		time.Sleep(lockRetryInterval)
+	}
+}
+
+func (m *lockManager) tryLock(session, key string, shared bool) bool {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	entry, ok := m.locks[key]
+	if !ok {
+		entry = &lockEntry{shared: make(map[string]struct{})}
+		m.locks[key] = entry
+	}
+	if entry.exclusive != "" && entry.exclusive != session {
+		return false
+	}
+	if shared {
+		if entry.exclusive != session {
+			entry.shared[session] = struct{}{}
+		}
+	} else {
+		for holder := range entry.shared {
+			if holder != session {
+				return false
+			}
+		}
+		delete(entry.shared, session)
+		entry.exclusive = session
+	}
+
+	value, ok := m.sessions[session]
+	if !ok {
+		value = &lockSession{keys: make(map[string]struct{})}
+		m.sessions[session] = value
+	}
+	value.keys[key] = struct{}{}
+	value.expire = time.Now().Add(m.ttl)
+	return true
+}
+
+// holdExclusive 判断会话是否持有资源的排它锁，持有时顺便续期
+func (m *lockManager) holdExclusive(session, key string) bool {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	entry, ok := m.locks[key]
+	if !ok || entry.exclusive != session {
+		return false
+	}
+	if value, ok := m.sessions[session]; ok {
+		value.expire = time.Now().Add(m.ttl)
+	}
+	return true
+}
+
+// unlock 释放会话持有的全部锁
+func (m *lockManager) unlock(session string) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.release(session)
+}
+
+func (m *lockManager) release(session string) {
+	value, ok := m.sessions[session]
+	if !ok {
+		return
+	}
+	delete(m.sessions, session)
+	for key := range value.keys {
+		entry, ok := m.locks[key]
+		if !ok {
+			continue
+		}
+		if entry.exclusive == session {
+			entry.exclusive = ""
+		}
+		delete(entry.shared, session)
+		if entry.exclusive == "" && len(entry.shared) == 0 {
+			delete(m.locks, key)
+		}
+	}
+}
+
+// expire 释放过期的会话，返回其中持有写锁排它锁的会话，即需要回滚的事务
+func (m *lockManager) expire(now time.Time) []string {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	var txs []string
+	for session, value := range m.sessions {
+		if now.Before(value.expire) {
+			continue
+		}
+		if entry, ok := m.locks[writeLockKey]; ok && entry.exclusive == session {
+			txs = append(txs, session)
+		}
+		m.release(session)
+	}
+	return txs
+}
+
+// reset leader 切换时清理全部的锁
+func (m *lockManager) reset() {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.locks = make(map[string]*lockEntry)
+	m.sessions = make(map[string]*lockSession)
+}
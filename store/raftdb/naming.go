@@ -0,0 +1,329 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package raftdb
+
+import (
+	"time"
+
+	"github.com/polarismesh/polaris/common/model"
+	v2 "github.com/polarismesh/polaris/common/model/v2"
+	"github.com/polarismesh/polaris/store"
+)
+
+// AddNamespace Save a namespace
+func (s *raftStore) AddNamespace(namespace *model.Namespace) error {
+	return s.exec("AddNamespace", namespace)
+}
+
+// UpdateNamespace Update namespace
+func (s *raftStore) UpdateNamespace(namespace *model.Namespace) error {
+	return s.exec("UpdateNamespace", namespace)
+}
+
+// UpdateNamespaceToken Update namespace token
+func (s *raftStore) UpdateNamespaceToken(name, token string) error {
+	return s.exec("UpdateNamespaceToken", name, token)
+}
+
+// AddService 保存一个服务
+func (s *raftStore) AddService(service *model.Service) error {
+	return s.exec("AddService", service)
+}
+
+// DeleteService 删除服务
+func (s *raftStore) DeleteService(id, serviceName, namespaceName string) error {
+	return s.exec("DeleteService", id, serviceName, namespaceName)
+}
+
+// DeleteServiceAlias 删除服务别名
+func (s *raftStore) DeleteServiceAlias(name, namespace string) error {
+	return s.exec("DeleteServiceAlias", name, namespace)
+}
+
+// UpdateServiceAlias 修改服务别名
+func (s *raftStore) UpdateServiceAlias(alias *model.Service, needUpdateOwner bool) error {
+	return s.exec("UpdateServiceAlias", alias, needUpdateOwner)
+}
+
+// UpdateService 更新服务
+func (s *raftStore) UpdateService(service *model.Service, needUpdateOwner bool) error {
+	return s.exec("UpdateService", service, needUpdateOwner)
+}
+
+// UpdateServiceToken 更新服务token
+func (s *raftStore) UpdateServiceToken(serviceID, token, revision string) error {
+	return s.exec("UpdateServiceToken", serviceID, token, revision)
+}
+
+// AddInstance 增加一个实例
+func (s *raftStore) AddInstance(instance *model.Instance) error {
+	return s.exec("AddInstance", instance)
+}
+
+// BatchAddInstances 增加多个实例
+func (s *raftStore) BatchAddInstances(instances []*model.Instance) error {
+	return s.exec("BatchAddInstances", instances)
+}
+
+// UpdateInstance 更新实例
+func (s *raftStore) UpdateInstance(instance *model.Instance) error {
+	return s.exec("UpdateInstance", instance)
+}
+
+// DeleteInstance 删除一个实例，实际是把valid置为false
+func (s *raftStore) DeleteInstance(instanceID string) error {
+	return s.exec("DeleteInstance", instanceID)
+}
+
+// BatchDeleteInstances 批量删除实例，flag=1
+func (s *raftStore) BatchDeleteInstances(ids []interface{}) error {
+	return s.exec("BatchDeleteInstances", ids)
+}
+
+// CleanInstance 清空一个实例，真正删除
+func (s *raftStore) CleanInstance(instanceID string) error {
+	return s.exec("CleanInstance", instanceID)
+}
+
+// SetInstanceHealthStatus 设置实例的健康状态
+func (s *raftStore) SetInstanceHealthStatus(instanceID string, flag int, revision string) error {
+	return s.exec("SetInstanceHealthStatus", instanceID, flag, revision)
+}
+
+// BatchSetInstanceHealthStatus 批量设置实例的健康状态
+func (s *raftStore) BatchSetInstanceHealthStatus(ids []interface{}, healthy int, revision string) error {
+	return s.exec("BatchSetInstanceHealthStatus", ids, healthy, revision)
+}
+
+// BatchSetInstanceIsolate 批量修改实例的隔离状态
+func (s *raftStore) BatchSetInstanceIsolate(ids []interface{}, isolate int, revision string) error {
+	return s.exec("BatchSetInstanceIsolate", ids, isolate, revision)
+}
+
+// SetL5Extend 设置meta里保存的扩展数据，并返回剩余的meta
+func (s *raftStore) SetL5Extend(serviceID string, meta map[string]interface{}) (map[string]interface{}, error) {
+	ret, err := s.call(nil, "SetL5Extend", serviceID, meta)
+	if err != nil {
+		return nil, err
+	}
+	return ret[0].(map[string]interface{}), nil
+}
+
+// GenNextL5Sid 获取module
+func (s *raftStore) GenNextL5Sid(layoutID uint32) (string, error) {
+	ret, err := s.call(nil, "GenNextL5Sid", layoutID)
+	if err != nil {
+		return "", err
+	}
+	return ret[0].(string), nil
+}
+
+// CreateRoutingConfig 新增一个路由配置
+func (s *raftStore) CreateRoutingConfig(conf *model.RoutingConfig) error {
+	return s.exec("CreateRoutingConfig", conf)
+}
+
+// UpdateRoutingConfig 更新一个路由配置
+func (s *raftStore) UpdateRoutingConfig(conf *model.RoutingConfig) error {
+	return s.exec("UpdateRoutingConfig", conf)
+}
+
+// DeleteRoutingConfig 删除一个路由配置
+func (s *raftStore) DeleteRoutingConfig(serviceID string) error {
+	return s.exec("DeleteRoutingConfig", serviceID)
+}
+
+// DeleteRoutingConfigTx 删除一个路由配置
+func (s *raftStore) DeleteRoutingConfigTx(tx store.Tx, serviceID string) error {
+	return s.execTx(tx, "DeleteRoutingConfigTx", serviceID)
+}
+
+// CreateRateLimit 新增限流规则
+func (s *raftStore) CreateRateLimit(limiting *model.RateLimit) error {
+	return s.exec("CreateRateLimit", limiting)
+}
+
+// UpdateRateLimit 更新限流规则
+func (s *raftStore) UpdateRateLimit(limiting *model.RateLimit) error {
+	return s.exec("UpdateRateLimit", limiting)
+}
+
+// EnableRateLimit 启用限流规则
+func (s *raftStore) EnableRateLimit(limit *model.RateLimit) error {
+	return s.exec("EnableRateLimit", limit)
+}
+
+// DeleteRateLimit 删除限流规则
+func (s *raftStore) DeleteRateLimit(limiting *model.RateLimit) error {
+	return s.exec("DeleteRateLimit", limiting)
+}
+
+// CreateCircuitBreaker 新增熔断规则
+func (s *raftStore) CreateCircuitBreaker(circuitBreaker *model.CircuitBreaker) error {
+	return s.exec("CreateCircuitBreaker", circuitBreaker)
+}
+
+// TagCircuitBreaker 标记熔断规则
+func (s *raftStore) TagCircuitBreaker(circuitBreaker *model.CircuitBreaker) error {
+	return s.exec("TagCircuitBreaker", circuitBreaker)
+}
+
+// ReleaseCircuitBreaker 发布熔断规则
+func (s *raftStore) ReleaseCircuitBreaker(circuitBreakerRelation *model.CircuitBreakerRelation) error {
+	return s.exec("ReleaseCircuitBreaker", circuitBreakerRelation)
+}
+
+// UnbindCircuitBreaker 解绑熔断规则
+func (s *raftStore) UnbindCircuitBreaker(serviceID, ruleID, ruleVersion string) error {
+	return s.exec("UnbindCircuitBreaker", serviceID, ruleID, ruleVersion)
+}
+
+// DeleteTagCircuitBreaker 删除已标记熔断规则
+func (s *raftStore) DeleteTagCircuitBreaker(id, version string) error {
+	return s.exec("DeleteTagCircuitBreaker", id, version)
+}
+
+// DeleteMasterCircuitBreaker 删除master熔断规则
+func (s *raftStore) DeleteMasterCircuitBreaker(id string) error {
+	return s.exec("DeleteMasterCircuitBreaker", id)
+}
+
+// UpdateCircuitBreaker 修改熔断规则
+func (s *raftStore) UpdateCircuitBreaker(circuitBraker *model.CircuitBreaker) error {
+	return s.exec("UpdateCircuitBreaker", circuitBraker)
+}
+
+// BatchAddClients insert the client info
+func (s *raftStore) BatchAddClients(clients []*model.Client) error {
+	return s.exec("BatchAddClients", clients)
+}
+
+// BatchDeleteClients delete the client info
+func (s *raftStore) BatchDeleteClients(ids []string) error {
+	return s.exec("BatchDeleteClients", ids)
+}
+
+// EnableRouting 设置路由规则是否启用
+func (s *raftStore) EnableRouting(conf *v2.RoutingConfig) error {
+	return s.exec("EnableRouting", conf)
+}
+
+// CreateRoutingConfigV2 新增一个路由配置
+func (s *raftStore) CreateRoutingConfigV2(conf *v2.RoutingConfig) error {
+	return s.exec("CreateRoutingConfigV2", conf)
+}
+
+// CreateRoutingConfigV2Tx 新增一个路由配置
+func (s *raftStore) CreateRoutingConfigV2Tx(tx store.Tx, conf *v2.RoutingConfig) error {
+	return s.execTx(tx, "CreateRoutingConfigV2Tx", conf)
+}
+
+// UpdateRoutingConfigV2 更新一个路由配置
+func (s *raftStore) UpdateRoutingConfigV2(conf *v2.RoutingConfig) error {
+	return s.exec("UpdateRoutingConfigV2", conf)
+}
+
+// UpdateRoutingConfigV2Tx 更新一个路由配置
+func (s *raftStore) UpdateRoutingConfigV2Tx(tx store.Tx, conf *v2.RoutingConfig) error {
+	return s.execTx(tx, "UpdateRoutingConfigV2Tx", conf)
+}
+
+// DeleteRoutingConfigV2 删除一个路由配置
+func (s *raftStore) DeleteRoutingConfigV2(serviceID string) error {
+	return s.exec("DeleteRoutingConfigV2", serviceID)
+}
+
+// GetRoutingConfigV2WithIDTx 根据服务ID拉取路由配置
+func (s *raftStore) GetRoutingConfigV2WithIDTx(tx store.Tx, id string) (*v2.RoutingConfig, error) {
+	if tx == nil {
+		return s.Store.GetRoutingConfigV2WithIDTx(nil, id)
+	}
+	ret, err := s.call(tx, "GetRoutingConfigV2WithIDTx", id)
+	if err != nil {
+		return nil, err
+	}
+	return ret[0].(*v2.RoutingConfig), nil
+}
+
+// CreateCircuitBreakerRuleV2 新增一个熔断规则
+func (s *raftStore) CreateCircuitBreakerRuleV2(rule *v2.CircuitBreakerRule) error {
+	return s.exec("CreateCircuitBreakerRuleV2", rule)
+}
+
+// UpdateCircuitBreakerRuleV2 更新一个熔断规则
+func (s *raftStore) UpdateCircuitBreakerRuleV2(rule *v2.CircuitBreakerRule) error {
+	return s.exec("UpdateCircuitBreakerRuleV2", rule)
+}
+
+// DeleteCircuitBreakerRuleV2 删除一个熔断规则
+func (s *raftStore) DeleteCircuitBreakerRuleV2(id string) error {
+	return s.exec("DeleteCircuitBreakerRuleV2", id)
+}
+
+// EnableCircuitBreakerRuleV2 设置熔断规则是否启用
+func (s *raftStore) EnableCircuitBreakerRuleV2(rule *v2.CircuitBreakerRule) error {
+	return s.exec("EnableCircuitBreakerRuleV2", rule)
+}
+
+// CreateFaultMirrorRule 新增一个故障注入与流量镜像规则
+func (s *raftStore) CreateFaultMirrorRule(rule *v2.FaultMirrorRule) error {
+	return s.exec("CreateFaultMirrorRule", rule)
+}
+
+// UpdateFaultMirrorRule 更新一个故障注入与流量镜像规则
+func (s *raftStore) UpdateFaultMirrorRule(rule *v2.FaultMirrorRule) error {
+	return s.exec("UpdateFaultMirrorRule", rule)
+}
+
+// DeleteFaultMirrorRule 删除一个故障注入与流量镜像规则
+func (s *raftStore) DeleteFaultMirrorRule(id string) error {
+	return s.exec("DeleteFaultMirrorRule", id)
+}
+
+// EnableFaultMirrorRule 设置故障注入与流量镜像规则是否启用
+func (s *raftStore) EnableFaultMirrorRule(rule *v2.FaultMirrorRule) error {
+	return s.exec("EnableFaultMirrorRule", rule)
+}
+
+// BatchCleanDeletedInstances batch clean soft deleted instances
+func (s *raftStore) BatchCleanDeletedInstances(batchSize uint32) (uint32, error) {
+	ret, err := s.call(nil, "BatchCleanDeletedInstances", batchSize)
+	if err != nil {
+		return 0, err
+	}
+	return ret[0].(uint32), nil
+}
+
+// AddHealthChangeRecords 保存实例健康状态的变化记录
+func (s *raftStore) AddHealthChangeRecords(records []*model.HealthChangeRecord) error {
+	return s.exec("AddHealthChangeRecords", records)
+}
+
+// RestoreRecordMetas 还原记录原有的时间戳以及配置中心记录的自增ID
+func (s *raftStore) RestoreRecordMetas(kind store.RecordKind, metas []*store.RecordMeta) error {
+	return s.exec("RestoreRecordMetas", kind, metas)
+}
+
+// CleanHealthChangeRecords 清理创建时间早于 before 的健康状态变化记录
+func (s *raftStore) CleanHealthChangeRecords(before time.Time) (uint32, error) {
+	ret, err := s.call(nil, "CleanHealthChangeRecords", before)
+	if err != nil {
+		return 0, err
+	}
+	return ret[0].(uint32), nil
+}
@@ -0,0 +1,194 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: raftdb.proto
+
+package raftdb
+
+import proto "github.com/golang/protobuf/proto"
+import fmt "fmt"
+import math "math"
+
+import (
+	context "golang.org/x/net/context"
+	grpc "google.golang.org/grpc"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
+
+type ForwardRequest struct {
+	// command the encoded store command
+	Command              []byte   `protobuf:"bytes,1,opt,name=command,proto3" json:"command,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ForwardRequest) Reset()         { *m = ForwardRequest{} }
+func (m *ForwardRequest) String() string { return proto.CompactTextString(m) }
+func (*ForwardRequest) ProtoMessage()    {}
+func (*ForwardRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_raftdb_eae07ced758f9cb8, []int{0}
+}
+func (m *ForwardRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ForwardRequest.Unmarshal(m, b)
+}
+func (m *ForwardRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ForwardRequest.Marshal(b, m, deterministic)
+}
+func (dst *ForwardRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ForwardRequest.Merge(dst, src)
+}
+func (m *ForwardRequest) XXX_Size() int {
+	return xxx_messageInfo_ForwardRequest.Size(m)
+}
+func (m *ForwardRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ForwardRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ForwardRequest proto.InternalMessageInfo
+
+func (m *ForwardRequest) GetCommand() []byte {
+	if m != nil {
+		return m.Command
+	}
+	return nil
+}
+
+type ForwardResponse struct {
+	// result the encoded result of the command
+	Result               []byte   `protobuf:"bytes,1,opt,name=result,proto3" json:"result,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ForwardResponse) Reset()         { *m = ForwardResponse{} }
+func (m *ForwardResponse) String() string { return proto.CompactTextString(m) }
+func (*ForwardResponse) ProtoMessage()    {}
+func (*ForwardResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_raftdb_eae07ced758f9cb8, []int{1}
+}
+func (m *ForwardResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ForwardResponse.Unmarshal(m, b)
+}
+func (m *ForwardResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ForwardResponse.Marshal(b, m, deterministic)
+}
+func (dst *ForwardResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ForwardResponse.Merge(dst, src)
+}
+func (m *ForwardResponse) XXX_Size() int {
+	return xxx_messageInfo_ForwardResponse.Size(m)
+}
+func (m *ForwardResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ForwardResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ForwardResponse proto.InternalMessageInfo
+
+func (m *ForwardResponse) GetResult() []byte {
+	if m != nil {
+		return m.Result
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterType((*ForwardRequest)(nil), "raftdb.ForwardRequest")
+	proto.RegisterType((*ForwardResponse)(nil), "raftdb.ForwardResponse")
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// RaftStoreClient is the client API for RaftStore service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type RaftStoreClient interface {
+	Forward(ctx context.Context, in *ForwardRequest, opts ...grpc.CallOption) (*ForwardResponse, error)
+}
+
+type raftStoreClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewRaftStoreClient(cc *grpc.ClientConn) RaftStoreClient {
+	return &raftStoreClient{cc}
+}
+
+func (c *raftStoreClient) Forward(ctx context.Context, in *ForwardRequest, opts ...grpc.CallOption) (*ForwardResponse, error) {
+	out := new(ForwardResponse)
+	err := c.cc.Invoke(ctx, "/raftdb.RaftStore/Forward", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// RaftStoreServer is the server API for RaftStore service.
+type RaftStoreServer interface {
+	Forward(context.Context, *ForwardRequest) (*ForwardResponse, error)
+}
+
+func RegisterRaftStoreServer(s *grpc.Server, srv RaftStoreServer) {
+	s.RegisterService(&_RaftStore_serviceDesc, srv)
+}
+
+func _RaftStore_Forward_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ForwardRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RaftStoreServer).Forward(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/raftdb.RaftStore/Forward",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RaftStoreServer).Forward(ctx, req.(*ForwardRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _RaftStore_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "raftdb.RaftStore",
+	HandlerType: (*RaftStoreServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Forward",
+			Handler:    _RaftStore_Forward_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "raftdb.proto",
+}
+
+func init() { proto.RegisterFile("raftdb.proto", fileDescriptor_raftdb_eae07ced758f9cb8) }
+
+var fileDescriptor_raftdb_eae07ced758f9cb8 = []byte{
+	// 142 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0xe2, 0x29, 0x4a, 0x4c, 0x2b,
+	0x49, 0x49, 0xd2, 0x2b, 0x28, 0xca, 0x2f, 0xc9, 0x17, 0x62, 0x83, 0xf0, 0x94, 0xb4, 0xb8, 0xf8,
+	0xdc, 0xf2, 0x8b, 0xca, 0x13, 0x8b, 0x52, 0x82, 0x52, 0x0b, 0x4b, 0x53, 0x8b, 0x4b, 0x84, 0x24,
+	0xb8, 0xd8, 0x93, 0xf3, 0x73, 0x73, 0x13, 0xf3, 0x52, 0x24, 0x18, 0x15, 0x18, 0x35, 0x78, 0x82,
+	0x60, 0x5c, 0x25, 0x4d, 0x2e, 0x7e, 0xb8, 0xda, 0xe2, 0x82, 0xfc, 0xbc, 0xe2, 0x54, 0x21, 0x31,
+	0x2e, 0xb6, 0xa2, 0xd4, 0xe2, 0xd2, 0x9c, 0x12, 0xa8, 0x5a, 0x28, 0xcf, 0xc8, 0x93, 0x8b, 0x33,
+	0x28, 0x31, 0xad, 0x24, 0xb8, 0x24, 0xbf, 0x28, 0x55, 0xc8, 0x86, 0x8b, 0x1d, 0xaa, 0x4f, 0x48,
+	0x4c, 0x0f, 0xea, 0x0a, 0x54, 0x4b, 0xa5, 0xc4, 0x31, 0xc4, 0x21, 0x16, 0x28, 0x31, 0x24, 0xb1,
+	0x81, 0x1d, 0x6c, 0x0c, 0x18, 0x00, 0xb0, 0xde, 0xc7, 0x12, 0xc0, 0x00, 0x00, 0x00,
+}
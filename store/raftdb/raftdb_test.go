@@ -0,0 +1,363 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package raftdb
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/raft"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/polarismesh/polaris/common/model"
+	"github.com/polarismesh/polaris/store"
+	"github.com/polarismesh/polaris/store/boltdb"
+)
+
+func TestLockManager(t *testing.T) {
+	locks := newLockManager(time.Minute)
+
+	assert.Nil(t, locks.lock("s1", "key", true, 0))
+	assert.Nil(t, locks.lock("s2", "key", true, 0))
+	// 共享锁存在其他持有者时不能加排它锁
+	assert.Equal(t, errLockTimeout, locks.lock("s1", "key", false, 0))
+	locks.unlock("s2")
+	// 只有自己持有共享锁时可以升级为排它锁
+	assert.Nil(t, locks.lock("s1", "key", false, 0))
+	assert.Nil(t, locks.lock("s1", "key", true, 0))
+	assert.Equal(t, errLockTimeout, locks.lock("s2", "key", true, 20*time.Millisecond))
+
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		locks.unlock("s1")
+	}()
+	assert.Nil(t, locks.lock("s2", "key", false, time.Second))
+
+	assert.Nil(t, locks.lock("tx", writeLockKey, false, 0))
+	assert.True(t, locks.holdExclusive("tx", writeLockKey))
+	assert.False(t, locks.holdExclusive("s2", writeLockKey))
+	// 过期的会话被释放，持有写锁的会话作为事务返回
+	assert.Equal(t, []string{"tx"}, locks.expire(time.Now().Add(2*time.Minute)))
+	assert.False(t, locks.holdExclusive("tx", writeLockKey))
+	assert.Equal(t, 0, len(locks.locks))
+	assert.Equal(t, 0, len(locks.sessions))
+}
+
+func TestEncodeResult(t *testing.T) {
+	data, err := encodeResult([]interface{}{&model.Namespace{Name: "ns"}}, nil)
+	assert.Nil(t, err)
+	_, err = decodeResult("GetNamespace", data)
+	assert.Nil(t, err)
+	values, err := decodeResult("GetNamespace", data)
+	assert.Nil(t, err)
+	assert.Equal(t, "ns", values[0].(*model.Namespace).Name)
+
+	data, err = encodeResult([]interface{}{(*model.Namespace)(nil)}, nil)
+	assert.Nil(t, err)
+	values, err = decodeResult("GetNamespace", data)
+	assert.Nil(t, err)
+	assert.Nil(t, values[0].(*model.Namespace))
+
+	data, err = encodeResult(nil, store.NewStatusError(store.NotFoundService, "not found"))
+	assert.Nil(t, err)
+	_, err = decodeResult("AddInstance", data)
+	assert.Equal(t, store.NotFoundService, store.Code(err))
+	assert.Equal(t, "not found", err.Error())
+}
+
+func newTestFSM(t *testing.T, path string) *fsm {
+	local := boltdb.NewStore()
+	if err := local.Initialize(localStoreConfig(path)); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		_ = local.Destroy()
+	})
+	return newFSM(local)
+}
+
+func mustCall(t *testing.T, txID string, method string, args ...interface{}) *command {
+	cmd, err := newCallCommand(txID, method, args...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cmd
+}
+
+func TestFSMApply(t *testing.T) {
+	f := newTestFSM(t, filepath.Join(t.TempDir(), "fsm.bolt"))
+
+	ret := f.apply(mustCall(t, "", "AddNamespace", &model.Namespace{Name: "raft-ns", Owner: "polaris"}))
+	assert.Nil(t, ret.err)
+	ns, err := f.local.GetNamespace("raft-ns")
+	assert.Nil(t, err)
+	assert.Equal(t, "polaris", ns.Owner)
+
+	ret = f.apply(mustCall(t, "", "UnknownMethod"))
+	assert.NotNil(t, ret.err)
+
+	// 数据迁移还原元信息的可选接口同样经过 raft 日志写入
+	ctime := time.Unix(1600000000, 0)
+	ret = f.apply(mustCall(t, "", "RestoreRecordMetas", store.RecordNamespace,
+		[]*store.RecordMeta{{Key: "raft-ns", CreateTime: ctime, ModifyTime: ctime}}))
+	assert.Nil(t, ret.err)
+	ns, err = f.local.GetNamespace("raft-ns")
+	assert.Nil(t, err)
+	assert.Equal(t, ctime.Unix(), ns.CreateTime.Unix())
+
+	// 事务内的读写在同一个 boltdb 事务中执行
+	assert.Nil(t, f.apply(&command{Type: cmdBeginTx, TxID: "tx-1"}).err)
+	file := &model.ConfigFile{Namespace: "raft-ns", Group: "group", Name: "file", Content: "content"}
+	ret = f.apply(mustCall(t, "tx-1", "CreateConfigFile", file))
+	assert.Nil(t, ret.err)
+	assert.Equal(t, "content", ret.values[0].(*model.ConfigFile).Content)
+	ret = f.apply(mustCall(t, "tx-1", "GetConfigFile", "raft-ns", "group", "file"))
+	assert.Nil(t, ret.err)
+	assert.NotNil(t, ret.values[0].(*model.ConfigFile))
+	assert.Nil(t, f.apply(&command{Type: cmdCommitTx, TxID: "tx-1"}).err)
+	saved, err := f.local.GetConfigFile(nil, "raft-ns", "group", "file")
+	assert.Nil(t, err)
+	assert.Equal(t, "content", saved.Content)
+	assert.Equal(t, errTxNotFound, f.apply(&command{Type: cmdCommitTx, TxID: "tx-1"}).err)
+
+	// 遗留的事务在下一次普通写操作前被回滚
+	assert.Nil(t, f.apply(&command{Type: cmdBeginTx, TxID: "tx-2"}).err)
+	assert.Nil(t, f.apply(mustCall(t, "tx-2", "DeleteConfigFile", "raft-ns", "group", "file")).err)
+	assert.Nil(t, f.apply(mustCall(t, "", transactionMethodPrefix+"DeleteNamespace", "raft-ns")).err)
+	assert.Equal(t, 0, len(f.txs))
+	saved, err = f.local.GetConfigFile(nil, "raft-ns", "group", "file")
+	assert.Nil(t, err)
+	assert.NotNil(t, saved)
+	ns, err = f.local.GetNamespace("raft-ns")
+	assert.Nil(t, err)
+	assert.Nil(t, ns)
+}
+
+type testSink struct {
+	buf []byte
+}
+
+func (s *testSink) Write(p []byte) (int, error) {
+	s.buf = append(s.buf, p...)
+	return len(p), nil
+}
+
+func (s *testSink) Close() error {
+	return nil
+}
+
+func (s *testSink) ID() string {
+	return "test"
+}
+
+func (s *testSink) Cancel() error {
+	return nil
+}
+
+func TestFSMSnapshotRestore(t *testing.T) {
+	dir := t.TempDir()
+	source := newTestFSM(t, filepath.Join(dir, "source.bolt"))
+	assert.Nil(t, source.apply(mustCall(t, "", "AddNamespace", &model.Namespace{Name: "snapshot-ns"})).err)
+	assert.Nil(t, source.apply(mustCall(t, "", "AddHealthChangeRecords",
+		[]*model.HealthChangeRecord{{InstanceId: "ins-1", CreateTime: time.Now()}})).err)
+
+	snapshot, err := source.Snapshot()
+	assert.Nil(t, err)
+	sink := &testSink{}
+	assert.Nil(t, snapshot.Persist(sink))
+
+	target := newTestFSM(t, filepath.Join(dir, "target.bolt"))
+	ns, err := target.local.GetNamespace("snapshot-ns")
+	assert.Nil(t, err)
+	assert.Nil(t, ns)
+	assert.Nil(t, target.Restore(io.NopCloser(bytes.NewReader(sink.buf))))
+	ns, err = target.local.GetNamespace("snapshot-ns")
+	assert.Nil(t, err)
+	assert.NotNil(t, ns)
+
+	// 恢复后自增 id 从快照中的值继续分配
+	assert.Nil(t, target.apply(mustCall(t, "", "AddHealthChangeRecords",
+		[]*model.HealthChangeRecord{{InstanceId: "ins-2", CreateTime: time.Now()}})).err)
+	records, err := target.local.GetHealthChangeRecords(&model.HealthHistoryFilter{})
+	assert.Nil(t, err)
+	if assert.Equal(t, 2, len(records)) {
+		assert.Equal(t, "ins-2", records[0].InstanceId)
+		assert.Equal(t, uint64(2), records[0].Id)
+	}
+
+	// 初始化数据使用固定的 token，每个节点一致
+	sourceNs, err := source.local.GetNamespace("default")
+	assert.Nil(t, err)
+	targetNs, err := target.local.GetNamespace("default")
+	assert.Nil(t, err)
+	assert.Equal(t, sourceNs.Token, targetNs.Token)
+}
+
+func freeAddr(t *testing.T) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	return listener.Addr().String()
+}
+
+func startTestCluster(t *testing.T, count int) []*raftStore {
+	dir := t.TempDir()
+	peers := make([]interface{}, 0, count)
+	for i := 0; i < count; i++ {
+		peers = append(peers, map[string]interface{}{
+			"nodeId":      fmt.Sprintf("node-%d", i),
+			"raftAddr":    freeAddr(t),
+			"forwardAddr": freeAddr(t),
+		})
+	}
+	stores := make([]*raftStore, 0, count)
+	for i := 0; i < count; i++ {
+		s := &raftStore{}
+		err := s.Initialize(&store.Config{
+			Name: STORENAME,
+			Option: map[string]interface{}{
+				"path":        filepath.Join(dir, fmt.Sprintf("node-%d.bolt", i)),
+				"dataDir":     filepath.Join(dir, fmt.Sprintf("raft-%d", i)),
+				"nodeId":      fmt.Sprintf("node-%d", i),
+				"bootstrap":   i == 0,
+				"peers":       peers,
+				"lockTimeout": "200ms",
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		stores = append(stores, s)
+	}
+	t.Cleanup(func() {
+		for _, s := range stores {
+			_ = s.Destroy()
+		}
+	})
+
+	deadline := time.Now().Add(10 * time.Second)
+	for time.Now().Before(deadline) {
+		for _, s := range stores {
+			if s.raft.State() == raft.Leader {
+				return stores
+			}
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	t.Fatal("raft cluster has no leader")
+	return nil
+}
+
+func follower(stores []*raftStore) *raftStore {
+	for _, s := range stores {
+		if s.raft.State() != raft.Leader {
+			return s
+		}
+	}
+	return nil
+}
+
+func waitNamespace(t *testing.T, stores []*raftStore, name string, exist bool) {
+	deadline := time.Now().Add(5 * time.Second)
+	for _, s := range stores {
+		for {
+			ns, err := s.GetNamespace(name)
+			assert.Nil(t, err)
+			if (ns != nil) == exist {
+				break
+			}
+			if time.Now().After(deadline) {
+				t.Fatalf("namespace %s not replicated to %s", name, s.conf.NodeID)
+			}
+			time.Sleep(20 * time.Millisecond)
+		}
+	}
+}
+
+func TestRaftStoreCluster(t *testing.T) {
+	stores := startTestCluster(t, 3)
+	node := follower(stores)
+
+	// 非 leader 节点的写操作转发给 leader，并复制到全部节点
+	assert.Nil(t, node.AddNamespace(&model.Namespace{Name: "cluster-ns", Owner: "polaris"}))
+	waitNamespace(t, stores, "cluster-ns", true)
+
+	// 事务内的读写操作通过 raft 日志在各节点的同一事务中执行
+	tx, err := node.StartTx()
+	assert.Nil(t, err)
+	created, err := node.CreateConfigFile(tx, &model.ConfigFile{
+		Namespace: "cluster-ns", Group: "group", Name: "file", Content: "content"})
+	assert.Nil(t, err)
+	assert.Equal(t, "content", created.Content)
+	file, err := node.GetConfigFile(tx, "cluster-ns", "group", "file")
+	assert.Nil(t, err)
+	assert.NotNil(t, file)
+	assert.Nil(t, tx.Commit())
+	assert.Nil(t, tx.Rollback())
+
+	// 事务持有写锁期间，其他写操作需要等待
+	tx, err = node.StartTx()
+	assert.Nil(t, err)
+	assert.NotNil(t, node.AddNamespace(&model.Namespace{Name: "blocked-ns"}))
+	assert.Nil(t, tx.Rollback())
+	assert.Nil(t, node.AddNamespace(&model.Namespace{Name: "blocked-ns"}))
+
+	// 启动锁由 leader 统一管理
+	first, err := stores[0].CreateTransaction()
+	assert.Nil(t, err)
+	second, err := stores[1].CreateTransaction()
+	assert.Nil(t, err)
+	assert.Nil(t, first.LockBootstrap("key", "first"))
+	assert.NotNil(t, second.LockBootstrap("key", "second"))
+	assert.Nil(t, first.Commit())
+	assert.Nil(t, second.LockBootstrap("key", "second"))
+	ns, err := second.LockNamespace("cluster-ns")
+	assert.Nil(t, err)
+	assert.NotNil(t, ns)
+
+	// 加锁后由 leader 读取数据，不依赖本节点是否已经同步
+	assert.Nil(t, node.AddService(&model.Service{ID: "cluster-svc", Name: "svc", Namespace: "cluster-ns"}))
+	for _, s := range stores {
+		locker, err := s.CreateTransaction()
+		assert.Nil(t, err)
+		svc, err := locker.LockService("svc", "cluster-ns")
+		assert.Nil(t, err)
+		if assert.NotNil(t, svc) {
+			assert.Equal(t, "cluster-svc", svc.ID)
+		}
+		assert.Nil(t, locker.Commit())
+	}
+	assert.Nil(t, second.DeleteNamespace("blocked-ns"))
+	assert.Nil(t, second.Commit())
+	waitNamespace(t, stores, "blocked-ns", false)
+
+	for _, s := range stores {
+		file, err := s.GetConfigFile(nil, "cluster-ns", "group", "file")
+		assert.Nil(t, err)
+		if assert.NotNil(t, file) {
+			assert.Equal(t, created.Id, file.Id)
+		}
+	}
+}
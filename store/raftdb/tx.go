@@ -0,0 +1,133 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package raftdb
+
+import (
+	"fmt"
+
+	"github.com/polarismesh/polaris/common/model"
+	"github.com/polarismesh/polaris/store"
+)
+
+// raftTx 在 leader 上持有写锁的事务，事务内的读写都写入 raft 日志，在每个节点的同一个 boltdb 事务中执行
+type raftTx struct {
+	s      *raftStore
+	id     string
+	closed bool
+}
+
+// Commit 提交事务
+func (t *raftTx) Commit() error {
+	if t.closed {
+		return errTxNotFound
+	}
+	t.closed = true
+	_, err := t.s.submit(&command{Type: cmdCommitTx, TxID: t.id})
+	return err
+}
+
+// Rollback 回滚事务，事务已经提交时直接返回
+func (t *raftTx) Rollback() error {
+	if t.closed {
+		return nil
+	}
+	t.closed = true
+	_, err := t.s.submit(&command{Type: cmdRollbackTx, TxID: t.id})
+	return err
+}
+
+// GetDelegateTx 返回事务本身，事务只能在 raft 存储内使用
+func (t *raftTx) GetDelegateTx() interface{} {
+	return t
+}
+
+// transaction 通过 leader 上的锁实现 store.Transaction，锁在 Commit 时释放
+type transaction struct {
+	s       *raftStore
+	session string
+}
+
+// Commit 释放事务持有的全部锁
+func (t *transaction) Commit() error {
+	_, err := t.s.submit(&command{Type: cmdUnlock, TxID: t.session})
+	return err
+}
+
+// LockBootstrap 启动锁，限制Server启动的并发数
+func (t *transaction) LockBootstrap(key string, server string) error {
+	return t.lock("bootstrap/"+key, false)
+}
+
+// LockNamespace 排它锁namespace
+func (t *transaction) LockNamespace(name string) (*model.Namespace, error) {
+	values, err := t.lockAndRead("namespace/"+name, false, "LockNamespace", name)
+	if err != nil {
+		return nil, err
+	}
+	return values[0].(*model.Namespace), nil
+}
+
+// DeleteNamespace 删除namespace
+func (t *transaction) DeleteNamespace(name string) error {
+	return t.s.exec(transactionMethodPrefix+"DeleteNamespace", name)
+}
+
+// LockService 排它锁service
+func (t *transaction) LockService(name string, namespace string) (*model.Service, error) {
+	return t.lockService(name, namespace, false)
+}
+
+// RLockService 共享锁service
+func (t *transaction) RLockService(name string, namespace string) (*model.Service, error) {
+	return t.lockService(name, namespace, true)
+}
+
+func (t *transaction) lockService(name string, namespace string, shared bool) (*model.Service, error) {
+	method := "LockService"
+	if shared {
+		method = "RLockService"
+	}
+	values, err := t.lockAndRead(fmt.Sprintf("service/%s/%s", namespace, name), shared, method, name, namespace)
+	if err != nil {
+		return nil, err
+	}
+	return values[0].(*model.Service), nil
+}
+
+func (t *transaction) lock(key string, shared bool) error {
+	_, err := t.s.submit(&command{Type: cmdLock, TxID: t.session, Key: key, Shared: shared})
+	return err
+}
+
+// lockAndRead 加锁后由 leader 读取被锁定的数据，避免读到 follower 上尚未同步的数据
+func (t *transaction) lockAndRead(key string, shared bool, method string,
+	args ...interface{}) ([]interface{}, error) {
+	cmd, err := newCallCommand(t.session, transactionMethodPrefix+method, args...)
+	if err != nil {
+		return nil, err
+	}
+	cmd.Type = cmdLock
+	cmd.Key = key
+	cmd.Shared = shared
+	return t.s.submit(cmd)
+}
+
+var (
+	_ store.Tx          = (*raftTx)(nil)
+	_ store.Transaction = (*transaction)(nil)
+)
@@ -0,0 +1,67 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package store
+
+import (
+	"time"
+)
+
+// RecordKind 数据迁移时需要还原元信息的记录类型
+type RecordKind string
+
+const (
+	RecordNamespace                RecordKind = "namespace"
+	RecordService                  RecordKind = "service"
+	RecordInstance                 RecordKind = "instance"
+	RecordRoutingConfig            RecordKind = "routing_config"
+	RecordRoutingConfigV2          RecordKind = "routing_config_v2"
+	RecordRateLimit                RecordKind = "ratelimit"
+	RecordCircuitBreaker           RecordKind = "circuitbreaker"
+	RecordCircuitBreakerV2         RecordKind = "circuitbreaker_v2"
+	RecordFaultMirrorRule          RecordKind = "fault_mirror_rule"
+	RecordConfigFileGroup          RecordKind = "config_file_group"
+	RecordConfigFile               RecordKind = "config_file"
+	RecordConfigFileRelease        RecordKind = "config_file_release"
+	RecordConfigFileReleaseHistory RecordKind = "config_file_release_history"
+	RecordConfigFileTemplate       RecordKind = "config_file_template"
+	RecordUser                     RecordKind = "user"
+	RecordUserGroup                RecordKind = "user_group"
+	RecordStrategy                 RecordKind = "strategy"
+)
+
+// RecordMeta 数据迁移时需要还原的记录元信息
+type RecordMeta struct {
+	// Key 记录在目标存储中的主键，配置中心的记录为目标存储生成的自增ID
+	Key string
+	// Version 熔断规则 v1 的版本号，其余记录为空
+	Version string
+	// OriginID 配置中心记录在源存储中的自增ID，为 0 时不还原
+	// 目标存储的数据表中存在导入之外的记录时不还原，避免与已有记录的ID冲突
+	OriginID uint64
+	// CreateTime 源存储中的创建时间
+	CreateTime time.Time
+	// ModifyTime 源存储中的修改时间
+	ModifyTime time.Time
+}
+
+// RestoreStore 可选接口，数据迁移时还原记录原有的时间戳以及配置中心记录的自增ID
+// 未实现该接口的存储插件在迁移后，记录的时间戳为写入时的时间
+type RestoreStore interface {
+	// RestoreRecordMetas 批量还原同一类型记录的元信息
+	RestoreRecordMetas(kind RecordKind, metas []*RecordMeta) error
+}
@@ -0,0 +1,24 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package store
+
+// SnapshotStore 可选接口，提供某一时刻一致的只读数据视图，全量备份时从视图中导出数据，保证各类资源之间的一致性
+type SnapshotStore interface {
+	// Snapshot 创建一致的只读数据视图，导出完成后需要调用 release 释放视图占用的资源
+	Snapshot() (view Store, release func(), err error)
+}
@@ -20,7 +20,6 @@ package sqldb
 import (
 	"context"
 	"database/sql"
-	"fmt"
 	"strings"
 	"time"
 
@@ -28,7 +27,7 @@ import (
 )
 
 // db抛出的异常，需要重试的字符串组
-var errMsg = []string{"Deadlock", "bad connection", "invalid connection"}
+var errMsg = []string{"Deadlock", "deadlock detected", "bad connection", "invalid connection"}
 
 // BaseDB 对sql.DB的封装
 type BaseDB struct {
@@ -49,10 +48,14 @@ type dbConfig struct {
 	maxIdleConns     int
 	connMaxLifetime  int
 	txIsolationLevel int
+	dialect          dialect
 }
 
 // NewBaseDB 新建一个BaseDB
 func NewBaseDB(cfg *dbConfig, parsePwd plugin.ParsePassword) (*BaseDB, error) {
+	if cfg.dialect == nil {
+		cfg.dialect = mysqlDialect{}
+	}
 	baseDb := &BaseDB{cfg: cfg, parsePwd: parsePwd}
 	if cfg.txIsolationLevel > 0 {
 		baseDb.isolationLevel = sql.IsolationLevel(cfg.txIsolationLevel)
@@ -80,9 +83,7 @@ func (b *BaseDB) openDatabase() error {
 		c.dbPwd = pwd
 	}
 
-	dns := fmt.Sprintf("%s:%s@tcp(%s)/%s", c.dbUser, c.dbPwd, c.dbAddr, c.dbName)
-
-	db, err := sql.Open(c.dbType, dns)
+	db, err := sql.Open(c.dbType, c.dialect.dsn(c))
 	if err != nil {
 		log.Errorf("[Store][database] sql open err: %s", err.Error())
 		return err
@@ -113,7 +114,7 @@ func (b *BaseDB) Exec(query string, args ...interface{}) (sql.Result, error) {
 	var result sql.Result
 	var err error
 	Retry("exec "+query, func() error {
-		result, err = b.DB.Exec(query, args...)
+		result, err = b.cfg.dialect.exec(b.DB, query, args)
 		return err
 	})
 
@@ -125,13 +126,18 @@ func (b *BaseDB) Query(query string, args ...interface{}) (*sql.Rows, error) {
 	var rows *sql.Rows
 	var err error
 	Retry("query "+query, func() error {
-		rows, err = b.DB.Query(query, args...)
+		rows, err = b.cfg.dialect.query(b.DB, query, args)
 		return err
 	})
 
 	return rows, err
 }
 
+// QueryRow 重写db.QueryRow函数
+func (b *BaseDB) QueryRow(query string, args ...interface{}) *sql.Row {
+	return b.cfg.dialect.queryRow(b.DB, query, args)
+}
+
 // Begin 重写db.Begin
 func (b *BaseDB) Begin() (*BaseTx, error) {
 	var tx *sql.Tx
@@ -145,12 +151,28 @@ func (b *BaseDB) Begin() (*BaseTx, error) {
 		return err
 	})
 
-	return &BaseTx{Tx: tx}, err
+	return &BaseTx{Tx: tx, dialect: b.cfg.dialect}, err
 }
 
 // BaseTx 对sql.Tx的封装
 type BaseTx struct {
 	*sql.Tx
+	dialect dialect
+}
+
+// Exec 重写tx.Exec函数，按照数据库方言改写SQL
+func (t *BaseTx) Exec(query string, args ...interface{}) (sql.Result, error) {
+	return t.dialect.exec(t.Tx, query, args)
+}
+
+// Query 重写tx.Query函数
+func (t *BaseTx) Query(query string, args ...interface{}) (*sql.Rows, error) {
+	return t.dialect.query(t.Tx, query, args)
+}
+
+// QueryRow 重写tx.QueryRow函数
+func (t *BaseTx) QueryRow(query string, args ...interface{}) *sql.Row {
+	return t.dialect.queryRow(t.Tx, query, args)
 }
 
 // Retry 重试主函数
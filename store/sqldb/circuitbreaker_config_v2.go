@@ -0,0 +1,213 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package sqldb
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	v2 "github.com/polarismesh/polaris/common/model/v2"
+	"github.com/polarismesh/polaris/store"
+)
+
+// circuitBreakerStoreV2 CircuitBreakerStoreV2 的实现
+type circuitBreakerStoreV2 struct {
+	master *BaseDB
+	slave  *BaseDB
+}
+
+const (
+	queryCircuitBreakerRuleV2Fields = `select id, name, namespace, enable, level, src_service, src_namespace, 
+	dst_service, dst_namespace, rule, revision, description, flag, 
+	unix_timestamp(ctime), unix_timestamp(mtime), unix_timestamp(etime) from circuitbreaker_rule_v2 `
+)
+
+// CreateCircuitBreakerRuleV2 新增一个熔断规则
+func (c *circuitBreakerStoreV2) CreateCircuitBreakerRuleV2(rule *v2.CircuitBreakerRule) error {
+	if rule.ID == "" || rule.Revision == "" {
+		log.Errorf("[Store][database] create circuitbreaker rule v2 missing id or revision")
+		return store.NewStatusError(store.EmptyParamsErr, "missing id or revision")
+	}
+
+	insertSQL := "insert into circuitbreaker_rule_v2(id, name, namespace, enable, level, src_service, " +
+		" src_namespace, dst_service, dst_namespace, rule, revision, description, ctime, mtime, etime) " +
+		" values (?,?,?,?,?,?,?,?,?,?,?,?,sysdate(),sysdate(),%s)"
+
+	var enable int
+	if rule.Enable {
+		enable = 1
+		insertSQL = fmt.Sprintf(insertSQL, "sysdate()")
+	} else {
+		enable = 0
+		insertSQL = fmt.Sprintf(insertSQL, emptyEnableTime)
+	}
+
+	err := RetryTransaction("CreateCircuitBreakerRuleV2", func() error {
+		if _, err := c.master.Exec(insertSQL, rule.ID, rule.Name, rule.Namespace, enable, rule.Level,
+			rule.SrcService, rule.SrcNamespace, rule.DstService, rule.DstNamespace, rule.Rule,
+			rule.Revision, rule.Description); err != nil {
+			log.Errorf("[Store][database] create circuitbreaker rule v2(%+v) err: %s", rule, err.Error())
+			return err
+		}
+		return nil
+	})
+
+	return store.Error(err)
+}
+
+// UpdateCircuitBreakerRuleV2 更新一个熔断规则
+func (c *circuitBreakerStoreV2) UpdateCircuitBreakerRuleV2(rule *v2.CircuitBreakerRule) error {
+	if rule.ID == "" || rule.Revision == "" {
+		log.Errorf("[Store][database] update circuitbreaker rule v2 missing id or revision")
+		return store.NewStatusError(store.EmptyParamsErr, "missing id or revision")
+	}
+
+	str := "update circuitbreaker_rule_v2 set name = ?, level = ?, src_service = ?, src_namespace = ?, " +
+		" dst_service = ?, dst_namespace = ?, rule = ?, revision = ?, description = ?, mtime = sysdate() " +
+		" where id = ? and namespace = ?"
+	err := RetryTransaction("UpdateCircuitBreakerRuleV2", func() error {
+		if _, err := c.master.Exec(str, rule.Name, rule.Level, rule.SrcService, rule.SrcNamespace,
+			rule.DstService, rule.DstNamespace, rule.Rule, rule.Revision, rule.Description,
+			rule.ID, rule.Namespace); err != nil {
+			log.Errorf("[Store][database] update circuitbreaker rule v2(%+v) err: %s", rule, err.Error())
+			return err
+		}
+		return nil
+	})
+
+	return store.Error(err)
+}
+
+// DeleteCircuitBreakerRuleV2 删除一个熔断规则
+func (c *circuitBreakerStoreV2) DeleteCircuitBreakerRuleV2(id string) error {
+	if id == "" {
+		log.Errorf("[Store][database] delete circuitbreaker rule v2 missing id")
+		return store.NewStatusError(store.EmptyParamsErr, "missing id")
+	}
+
+	str := `update circuitbreaker_rule_v2 set flag = 1, mtime = sysdate() where id = ?`
+	if _, err := c.master.Exec(str, id); err != nil {
+		log.Errorf("[Store][database] delete circuitbreaker rule v2(%s) err: %s", id, err.Error())
+		return store.Error(err)
+	}
+
+	return nil
+}
+
+// EnableCircuitBreakerRuleV2 设置熔断规则是否启用
+func (c *circuitBreakerStoreV2) EnableCircuitBreakerRuleV2(rule *v2.CircuitBreakerRule) error {
+	if rule.ID == "" || rule.Revision == "" {
+		log.Errorf("[Store][database] enable circuitbreaker rule v2 missing id or revision")
+		return store.NewStatusError(store.EmptyParamsErr, "missing id or revision")
+	}
+
+	err := RetryTransaction("EnableCircuitBreakerRuleV2", func() error {
+		var (
+			enable   int
+			etimeStr string
+		)
+		if rule.Enable {
+			enable = 1
+			etimeStr = "sysdate()"
+		} else {
+			enable = 0
+			etimeStr = emptyEnableTime
+		}
+		str := fmt.Sprintf(`update circuitbreaker_rule_v2 set enable = ?, revision = ?, mtime = sysdate(), 
+		etime = %s where id = ?`, etimeStr)
+		if _, err := c.master.Exec(str, enable, rule.Revision, rule.ID); err != nil {
+			log.Errorf("[Store][database] enable circuitbreaker rule v2(%+v) err: %s", rule, err.Error())
+			return err
+		}
+		return nil
+	})
+
+	return store.Error(err)
+}
+
+// GetCircuitBreakerRuleV2WithID 根据规则ID拉取熔断规则
+func (c *circuitBreakerStoreV2) GetCircuitBreakerRuleV2WithID(id string) (*v2.CircuitBreakerRule, error) {
+	str := queryCircuitBreakerRuleV2Fields + " where id = ? and flag = 0"
+	rows, err := c.master.Query(str, id)
+	if err != nil {
+		log.Errorf("[Store][database] query circuitbreaker rule v2 with id(%s) err: %s", id, err.Error())
+		return nil, store.Error(err)
+	}
+
+	out, err := fetchCircuitBreakerRuleV2Rows(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(out) == 0 {
+		return nil, nil
+	}
+	return out[0], nil
+}
+
+// GetCircuitBreakerRulesV2ForCache 通过mtime拉取增量的熔断规则信息
+// 此方法用于 cache 增量更新，需要注意 mtime 应为数据库时间戳
+func (c *circuitBreakerStoreV2) GetCircuitBreakerRulesV2ForCache(
+	mtime time.Time, firstUpdate bool) ([]*v2.CircuitBreakerRule, error) {
+	str := queryCircuitBreakerRuleV2Fields + " where mtime > FROM_UNIXTIME(?)"
+	if firstUpdate {
+		str += " and flag != 1"
+	}
+	rows, err := c.slave.Query(str, timeToTimestamp(mtime))
+	if err != nil {
+		log.Errorf("[Store][database] query circuitbreaker rules v2 with mtime err: %s", err.Error())
+		return nil, err
+	}
+
+	return fetchCircuitBreakerRuleV2Rows(rows)
+}
+
+// fetchCircuitBreakerRuleV2Rows 读取数据库的数据，并且释放rows
+func fetchCircuitBreakerRuleV2Rows(rows *sql.Rows) ([]*v2.CircuitBreakerRule, error) {
+	defer rows.Close()
+	var out []*v2.CircuitBreakerRule
+	for rows.Next() {
+		var (
+			entry               v2.CircuitBreakerRule
+			flag, enable        int
+			ctime, mtime, etime int64
+		)
+
+		err := rows.Scan(&entry.ID, &entry.Name, &entry.Namespace, &enable, &entry.Level, &entry.SrcService,
+			&entry.SrcNamespace, &entry.DstService, &entry.DstNamespace, &entry.Rule, &entry.Revision,
+			&entry.Description, &flag, &ctime, &mtime, &etime)
+		if err != nil {
+			log.Errorf("[Store][database] fetch circuitbreaker rule v2 scan err: %s", err.Error())
+			return nil, err
+		}
+
+		entry.CreateTime = time.Unix(ctime, 0)
+		entry.ModifyTime = time.Unix(mtime, 0)
+		entry.EnableTime = time.Unix(etime, 0)
+		entry.Valid = flag == 0
+		entry.Enable = enable == 1
+
+		out = append(out, &entry)
+	}
+	if err := rows.Err(); err != nil {
+		log.Errorf("[Store][database] fetch circuitbreaker rule v2 next err: %s", err.Error())
+		return nil, err
+	}
+
+	return out, nil
+}
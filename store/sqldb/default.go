@@ -32,14 +32,33 @@ const (
 	SystemNamespace = "Polaris"
 	// STORENAME database storage name
 	STORENAME = "defaultStore"
+	// PGSTORENAME postgresql database storage name
+	PGSTORENAME = "postgresqlStore"
 	// DefaultConnMaxLifetime default maximum connection lifetime
 	DefaultConnMaxLifetime = 60 * 30 // 默认是30分钟
 )
 
+// dialects 存储插件名与数据库方言的对应关系
+var dialects = map[string]dialect{
+	STORENAME:   mysqlDialect{},
+	PGSTORENAME: newPostgresDialect(),
+}
+
 // init 自动引入包初始化函数
 func init() {
-	s := &stableStore{}
-	_ = store.RegisterStore(s)
+	for _, name := range []string{STORENAME, PGSTORENAME} {
+		name := name
+		_ = store.RegisterStore(&stableStore{name: name, dialect: dialects[name]})
+		store.RegisterStoreFactory(name, func() store.Store {
+			return &stableStore{name: name, dialect: dialects[name]}
+		})
+	}
+}
+
+// IsSqlStore 判断存储插件是否为关系型数据库存储
+func IsSqlStore(name string) bool {
+	_, ok := dialects[name]
+	return ok
 }
 
 // stableStore 实现了Store接口
@@ -60,6 +79,7 @@ type stableStore struct {
 	*configFileGroupStore
 	*configFileStore
 	*configFileReleaseStore
+	*configFileGrayReleaseStore
 	*configFileReleaseHistoryStore
 	*configFileTagStore
 	*configFileTemplateStore
@@ -69,6 +89,8 @@ type stableStore struct {
 
 	// v2 存储
 	*routingConfigStoreV2
+	*circuitBreakerStoreV2
+	*faultMirrorStore
 
 	// maintain store
 	*maintainStore
@@ -80,11 +102,15 @@ type stableStore struct {
 	// 备数据库，提供只读
 	slave *BaseDB
 	start bool
+
+	// 存储插件名，不同插件使用不同的数据库方言
+	name    string
+	dialect dialect
 }
 
 // Name 实现Name函数
 func (s *stableStore) Name() string {
-	return STORENAME
+	return s.name
 }
 
 // Initialize 初始化函数
@@ -97,6 +123,10 @@ func (s *stableStore) Initialize(conf *store.Config) error {
 	if err != nil {
 		return err
 	}
+	masterConfig.dialect = s.dialect
+	if slaveConfig != nil {
+		slaveConfig.dialect = s.dialect
+	}
 	master, err := NewBaseDB(masterConfig, plugin.GetParsePassword())
 	if err != nil {
 		return err
@@ -109,6 +139,16 @@ func (s *stableStore) Initialize(conf *store.Config) error {
 	}
 	s.masterTx = masterTx
 
+	// 执行尚未执行的 schema 迁移，数据库版本高于 server 支持的版本时拒绝启动
+	ret, err := migrateSchema(s.master, false)
+	if err != nil {
+		log.Errorf("[Store][database] migrate schema err: %s", err.Error())
+		return err
+	}
+	if len(ret.Pending) > 0 {
+		log.Infof("[Store][database] migrate schema from version %d to %d", ret.Current, ret.Latest)
+	}
+
 	if slaveConfig != nil {
 		log.Infof("[Store][database] use slave database config: %+v", slaveConfig)
 		slave, err := NewBaseDB(slaveConfig, plugin.GetParsePassword())
@@ -265,6 +305,8 @@ func (s *stableStore) newStore() {
 
 	s.configFileReleaseStore = &configFileReleaseStore{db: s.master}
 
+	s.configFileGrayReleaseStore = &configFileGrayReleaseStore{db: s.master}
+
 	s.configFileReleaseHistoryStore = &configFileReleaseHistoryStore{db: s.master}
 
 	s.configFileTagStore = &configFileTagStore{db: s.master}
@@ -275,5 +317,9 @@ func (s *stableStore) newStore() {
 
 	s.routingConfigStoreV2 = &routingConfigStoreV2{master: s.master, slave: s.slave}
 
+	s.circuitBreakerStoreV2 = &circuitBreakerStoreV2{master: s.master, slave: s.slave}
+
+	s.faultMirrorStore = &faultMirrorStore{master: s.master, slave: s.slave}
+
 	s.maintainStore = &maintainStore{master: s.master}
 }
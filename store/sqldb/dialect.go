@@ -0,0 +1,88 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package sqldb
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// executor 可以执行 SQL 的对象，*sql.DB 与 *sql.Tx 均实现了该接口
+type executor interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+// dialect 数据库方言
+// 存储层的 SQL 统一按照 MySQL 语法编写，由方言负责生成连接串以及改写为目标数据库的语法
+type dialect interface {
+	// dsn 根据配置生成数据库连接串
+	dsn(c *dbConfig) string
+	// exec 执行写操作
+	exec(e executor, query string, args []interface{}) (sql.Result, error)
+	// query 执行查询操作
+	query(e executor, query string, args []interface{}) (*sql.Rows, error)
+	// queryRow 执行单行查询操作
+	queryRow(e executor, query string, args []interface{}) *sql.Row
+	// migrationDir 内置 schema 迁移脚本所在的目录
+	migrationDir() string
+	// resetAutoIncrement 生成将自增序列重置为当前最大ID之后的语句
+	resetAutoIncrement(table string) string
+	// lockTablesForRead 生成在当前会话中锁定数据表、阻塞其他会话写入的语句，以及释放锁的语句
+	lockTablesForRead(tables []string) (lock []string, unlock string)
+}
+
+// mysqlDialect MySQL 方言，SQL 无需改写
+type mysqlDialect struct{}
+
+// dsn 生成 go-sql-driver/mysql 的连接串
+func (mysqlDialect) dsn(c *dbConfig) string {
+	return fmt.Sprintf("%s:%s@tcp(%s)/%s", c.dbUser, c.dbPwd, c.dbAddr, c.dbName)
+}
+
+func (mysqlDialect) migrationDir() string {
+	return "mysql"
+}
+
+// resetAutoIncrement InnoDB 会将小于当前最大ID的自增值调整为最大ID加一
+func (mysqlDialect) resetAutoIncrement(table string) string {
+	return fmt.Sprintf("ALTER TABLE %s AUTO_INCREMENT = 1", table)
+}
+
+// lockTablesForRead 持有 READ 锁时其他会话仍然可以读取，写入等待到 UNLOCK TABLES
+func (mysqlDialect) lockTablesForRead(tables []string) ([]string, string) {
+	items := make([]string, 0, len(tables))
+	for _, table := range tables {
+		items = append(items, "`"+table+"` READ")
+	}
+	return []string{"LOCK TABLES " + strings.Join(items, ", ")}, "UNLOCK TABLES"
+}
+
+func (mysqlDialect) exec(e executor, query string, args []interface{}) (sql.Result, error) {
+	return e.Exec(query, args...)
+}
+
+func (mysqlDialect) query(e executor, query string, args []interface{}) (*sql.Rows, error) {
+	return e.Query(query, args...)
+}
+
+func (mysqlDialect) queryRow(e executor, query string, args []interface{}) *sql.Row {
+	return e.QueryRow(query, args...)
+}
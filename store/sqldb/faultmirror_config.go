@@ -0,0 +1,207 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package sqldb
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	v2 "github.com/polarismesh/polaris/common/model/v2"
+	"github.com/polarismesh/polaris/store"
+)
+
+// faultMirrorStore FaultMirrorStore 的实现
+type faultMirrorStore struct {
+	master *BaseDB
+	slave  *BaseDB
+}
+
+const (
+	queryFaultMirrorRuleFields = `select id, name, namespace, enable, service, rule, revision, description, flag, 
+	unix_timestamp(ctime), unix_timestamp(mtime), unix_timestamp(etime) from fault_mirror_rule `
+)
+
+// CreateFaultMirrorRule 新增一个故障注入与流量镜像规则
+func (f *faultMirrorStore) CreateFaultMirrorRule(rule *v2.FaultMirrorRule) error {
+	if rule.ID == "" || rule.Revision == "" {
+		log.Errorf("[Store][database] create fault mirror rule missing id or revision")
+		return store.NewStatusError(store.EmptyParamsErr, "missing id or revision")
+	}
+
+	insertSQL := "insert into fault_mirror_rule(id, name, namespace, enable, service, rule, revision, " +
+		" description, ctime, mtime, etime) values (?,?,?,?,?,?,?,?,sysdate(),sysdate(),%s)"
+
+	var enable int
+	if rule.Enable {
+		enable = 1
+		insertSQL = fmt.Sprintf(insertSQL, "sysdate()")
+	} else {
+		enable = 0
+		insertSQL = fmt.Sprintf(insertSQL, emptyEnableTime)
+	}
+
+	err := RetryTransaction("CreateFaultMirrorRule", func() error {
+		if _, err := f.master.Exec(insertSQL, rule.ID, rule.Name, rule.Namespace, enable, rule.Service,
+			rule.Rule, rule.Revision, rule.Description); err != nil {
+			log.Errorf("[Store][database] create fault mirror rule(%+v) err: %s", rule, err.Error())
+			return err
+		}
+		return nil
+	})
+
+	return store.Error(err)
+}
+
+// UpdateFaultMirrorRule 更新一个故障注入与流量镜像规则
+func (f *faultMirrorStore) UpdateFaultMirrorRule(rule *v2.FaultMirrorRule) error {
+	if rule.ID == "" || rule.Revision == "" {
+		log.Errorf("[Store][database] update fault mirror rule missing id or revision")
+		return store.NewStatusError(store.EmptyParamsErr, "missing id or revision")
+	}
+
+	str := "update fault_mirror_rule set name = ?, service = ?, rule = ?, revision = ?, description = ?, " +
+		" mtime = sysdate() where id = ? and namespace = ?"
+	err := RetryTransaction("UpdateFaultMirrorRule", func() error {
+		if _, err := f.master.Exec(str, rule.Name, rule.Service, rule.Rule, rule.Revision, rule.Description,
+			rule.ID, rule.Namespace); err != nil {
+			log.Errorf("[Store][database] update fault mirror rule(%+v) err: %s", rule, err.Error())
+			return err
+		}
+		return nil
+	})
+
+	return store.Error(err)
+}
+
+// DeleteFaultMirrorRule 删除一个故障注入与流量镜像规则
+func (f *faultMirrorStore) DeleteFaultMirrorRule(id string) error {
+	if id == "" {
+		log.Errorf("[Store][database] delete fault mirror rule missing id")
+		return store.NewStatusError(store.EmptyParamsErr, "missing id")
+	}
+
+	str := `update fault_mirror_rule set flag = 1, mtime = sysdate() where id = ?`
+	if _, err := f.master.Exec(str, id); err != nil {
+		log.Errorf("[Store][database] delete fault mirror rule(%s) err: %s", id, err.Error())
+		return store.Error(err)
+	}
+
+	return nil
+}
+
+// EnableFaultMirrorRule 设置故障注入与流量镜像规则是否启用
+func (f *faultMirrorStore) EnableFaultMirrorRule(rule *v2.FaultMirrorRule) error {
+	if rule.ID == "" || rule.Revision == "" {
+		log.Errorf("[Store][database] enable fault mirror rule missing id or revision")
+		return store.NewStatusError(store.EmptyParamsErr, "missing id or revision")
+	}
+
+	err := RetryTransaction("EnableFaultMirrorRule", func() error {
+		var (
+			enable   int
+			etimeStr string
+		)
+		if rule.Enable {
+			enable = 1
+			etimeStr = "sysdate()"
+		} else {
+			enable = 0
+			etimeStr = emptyEnableTime
+		}
+		str := fmt.Sprintf(`update fault_mirror_rule set enable = ?, revision = ?, mtime = sysdate(), 
+		etime = %s where id = ?`, etimeStr)
+		if _, err := f.master.Exec(str, enable, rule.Revision, rule.ID); err != nil {
+			log.Errorf("[Store][database] enable fault mirror rule(%+v) err: %s", rule, err.Error())
+			return err
+		}
+		return nil
+	})
+
+	return store.Error(err)
+}
+
+// GetFaultMirrorRuleWithID 根据规则ID拉取故障注入与流量镜像规则
+func (f *faultMirrorStore) GetFaultMirrorRuleWithID(id string) (*v2.FaultMirrorRule, error) {
+	str := queryFaultMirrorRuleFields + " where id = ? and flag = 0"
+	rows, err := f.master.Query(str, id)
+	if err != nil {
+		log.Errorf("[Store][database] query fault mirror rule with id(%s) err: %s", id, err.Error())
+		return nil, store.Error(err)
+	}
+
+	out, err := fetchFaultMirrorRuleRows(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(out) == 0 {
+		return nil, nil
+	}
+	return out[0], nil
+}
+
+// GetFaultMirrorRulesForCache 通过mtime拉取增量的故障注入与流量镜像规则信息
+// 此方法用于 cache 增量更新，需要注意 mtime 应为数据库时间戳
+func (f *faultMirrorStore) GetFaultMirrorRulesForCache(
+	mtime time.Time, firstUpdate bool) ([]*v2.FaultMirrorRule, error) {
+	str := queryFaultMirrorRuleFields + " where mtime > FROM_UNIXTIME(?)"
+	if firstUpdate {
+		str += " and flag != 1"
+	}
+	rows, err := f.slave.Query(str, timeToTimestamp(mtime))
+	if err != nil {
+		log.Errorf("[Store][database] query fault mirror rules with mtime err: %s", err.Error())
+		return nil, err
+	}
+
+	return fetchFaultMirrorRuleRows(rows)
+}
+
+// fetchFaultMirrorRuleRows 读取数据库的数据，并且释放rows
+func fetchFaultMirrorRuleRows(rows *sql.Rows) ([]*v2.FaultMirrorRule, error) {
+	defer rows.Close()
+	var out []*v2.FaultMirrorRule
+	for rows.Next() {
+		var (
+			entry               v2.FaultMirrorRule
+			flag, enable        int
+			ctime, mtime, etime int64
+		)
+
+		err := rows.Scan(&entry.ID, &entry.Name, &entry.Namespace, &enable, &entry.Service, &entry.Rule,
+			&entry.Revision, &entry.Description, &flag, &ctime, &mtime, &etime)
+		if err != nil {
+			log.Errorf("[Store][database] fetch fault mirror rule scan err: %s", err.Error())
+			return nil, err
+		}
+
+		entry.CreateTime = time.Unix(ctime, 0)
+		entry.ModifyTime = time.Unix(mtime, 0)
+		entry.EnableTime = time.Unix(etime, 0)
+		entry.Valid = flag == 0
+		entry.Enable = enable == 1
+
+		out = append(out, &entry)
+	}
+	if err := rows.Err(); err != nil {
+		log.Errorf("[Store][database] fetch fault mirror rule next err: %s", err.Error())
+		return nil, err
+	}
+
+	return out, nil
+}
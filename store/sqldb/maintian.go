@@ -17,7 +17,14 @@
 
 package sqldb
 
-import "github.com/polarismesh/polaris/store"
+import (
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/polarismesh/polaris/common/model"
+	"github.com/polarismesh/polaris/store"
+)
 
 // maintainStore implement MaintainStore interface
 type maintainStore struct {
@@ -42,3 +49,91 @@ func (maintain *maintainStore) BatchCleanDeletedInstances(batchSize uint32) (uin
 
 	return uint32(rows), nil
 }
+
+// AddHealthChangeRecords 保存实例健康状态的变化记录
+func (maintain *maintainStore) AddHealthChangeRecords(records []*model.HealthChangeRecord) error {
+	if len(records) == 0 {
+		return nil
+	}
+	str := "insert into instance_health_history(instance_id, namespace, service, host, port, healthy, " +
+		"isolate, reason, checker, create_time) values "
+	values := make([]string, 0, len(records))
+	args := make([]interface{}, 0, len(records)*10)
+	for _, record := range records {
+		values = append(values, "(?,?,?,?,?,?,?,?,?,FROM_UNIXTIME(?))")
+		args = append(args, record.InstanceId, record.Namespace, record.Service, record.Host, record.Port,
+			record.Healthy, record.Isolate, record.Reason, record.Checker, record.CreateTime.Unix())
+	}
+	if _, err := maintain.master.Exec(str+strings.Join(values, ","), args...); err != nil {
+		log.Errorf("[Store][database] add health change records err: %s", err.Error())
+		return store.Error(err)
+	}
+	return nil
+}
+
+// GetHealthChangeRecords 查询实例健康状态的变化记录，按照时间倒序返回
+func (maintain *maintainStore) GetHealthChangeRecords(
+	filter *model.HealthHistoryFilter) ([]*model.HealthChangeRecord, error) {
+	str := "select id, instance_id, namespace, service, host, port, healthy, isolate, reason, checker, " +
+		"UNIX_TIMESTAMP(create_time) from instance_health_history where create_time > FROM_UNIXTIME(?)"
+	args := []interface{}{filter.Since.Unix()}
+	if filter.InstanceId != "" {
+		str += " and instance_id = ?"
+		args = append(args, filter.InstanceId)
+	} else {
+		if filter.Namespace != "" {
+			str += " and namespace = ?"
+			args = append(args, filter.Namespace)
+		}
+		if filter.Service != "" {
+			str += " and service = ?"
+			args = append(args, filter.Service)
+		}
+	}
+	str += " order by id desc"
+	if filter.Limit > 0 {
+		str += " limit ?"
+		args = append(args, filter.Limit)
+	}
+
+	rows, err := maintain.master.Query(str, args...)
+	if err != nil {
+		log.Errorf("[Store][database] get health change records err: %s", err.Error())
+		return nil, store.Error(err)
+	}
+	return fetchHealthChangeRecords(rows)
+}
+
+func fetchHealthChangeRecords(rows *sql.Rows) ([]*model.HealthChangeRecord, error) {
+	defer rows.Close()
+	records := make([]*model.HealthChangeRecord, 0)
+	for rows.Next() {
+		record := &model.HealthChangeRecord{}
+		var createTime int64
+		if err := rows.Scan(&record.Id, &record.InstanceId, &record.Namespace, &record.Service, &record.Host,
+			&record.Port, &record.Healthy, &record.Isolate, &record.Reason, &record.Checker, &createTime); err != nil {
+			return nil, store.Error(err)
+		}
+		record.CreateTime = time.Unix(createTime, 0)
+		records = append(records, record)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, store.Error(err)
+	}
+	return records, nil
+}
+
+// CleanHealthChangeRecords 清理创建时间早于 before 的健康状态变化记录
+func (maintain *maintainStore) CleanHealthChangeRecords(before time.Time) (uint32, error) {
+	result, err := maintain.master.Exec("delete from instance_health_history where create_time < FROM_UNIXTIME(?)",
+		before.Unix())
+	if err != nil {
+		log.Errorf("[Store][database] clean health change records before %s err: %s", before, err.Error())
+		return 0, store.Error(err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return 0, store.Error(err)
+	}
+	return uint32(rows), nil
+}
@@ -0,0 +1,312 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package sqldb
+
+import (
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/polarismesh/polaris/plugin"
+	"github.com/polarismesh/polaris/store"
+)
+
+// migrationFS 内置的 schema 迁移脚本，按方言分目录存放，文件名格式为 <版本号>_<描述>.sql
+//
+//go:embed migrations
+var migrationFS embed.FS
+
+const (
+	// createSchemaVersionSQL 记录已执行的 schema 版本，MySQL 与 PostgreSQL 通用
+	createSchemaVersionSQL = "CREATE TABLE IF NOT EXISTS schema_version (" +
+		"version INT NOT NULL, " +
+		"description VARCHAR(128) NOT NULL DEFAULT '', " +
+		"ctime TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP, " +
+		"PRIMARY KEY (version))"
+	// maxMigrateLockTimes 获取启动锁的最大重试次数
+	maxMigrateLockTimes = 10
+)
+
+// ErrSchemaTooNew 数据库的 schema 版本高于当前 server 支持的版本
+var ErrSchemaTooNew = errors.New("database schema is newer than this server supports")
+
+// MigrateResult schema 迁移结果
+type MigrateResult struct {
+	// Current 迁移前数据库的 schema 版本
+	Current int
+	// Latest 当前 server 内置的最新 schema 版本
+	Latest int
+	// Pending 已执行或者待执行（dryRun）的迁移
+	Pending []string
+}
+
+// migration 一次 schema 变更
+type migration struct {
+	version     int
+	description string
+	statements  []string
+}
+
+// String 返回迁移的文件名形式
+func (m *migration) String() string {
+	return fmt.Sprintf("%04d_%s", m.version, m.description)
+}
+
+// Migrate 根据存储配置执行 schema 迁移，供命令行使用；dryRun 为 true 时只返回待执行的迁移
+func Migrate(conf *store.Config, dryRun bool) (*MigrateResult, error) {
+	d, ok := dialects[conf.Name]
+	if !ok {
+		return nil, fmt.Errorf("store %s is not a sql store", conf.Name)
+	}
+	masterConfig, _, err := parseDatabaseConf(conf.Option)
+	if err != nil {
+		return nil, err
+	}
+	masterConfig.dialect = d
+	db, err := NewBaseDB(masterConfig, plugin.GetParsePassword())
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+
+	return migrateSchema(db, dryRun)
+}
+
+// migrateSchema 持有启动锁，执行数据库中尚未执行的迁移
+// 持锁事务与执行迁移使用连接池中不同的连接，因此 MySQL DDL 的隐式提交不会释放启动锁
+func migrateSchema(db *BaseDB, dryRun bool) (*MigrateResult, error) {
+	migrations, err := loadMigrations(db.cfg.dialect.migrationDir())
+	if err != nil {
+		return nil, err
+	}
+
+	lockTx, err := lockStartLock(db)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = lockTx.Rollback() }()
+
+	if _, err := db.DB.Exec(createSchemaVersionSQL); err != nil {
+		log.Errorf("[Store][database] create schema_version table err: %s", err.Error())
+		return nil, err
+	}
+	current, err := schemaVersion(db)
+	if err != nil {
+		return nil, err
+	}
+
+	ret := &MigrateResult{Current: current}
+	if len(migrations) > 0 {
+		ret.Latest = migrations[len(migrations)-1].version
+	}
+	if current > ret.Latest {
+		return ret, fmt.Errorf("%w: database version %d, server version %d", ErrSchemaTooNew, current, ret.Latest)
+	}
+
+	for _, m := range migrations {
+		if m.version <= current {
+			continue
+		}
+		ret.Pending = append(ret.Pending, m.String())
+		if dryRun {
+			continue
+		}
+		log.Infof("[Store][database] apply schema migration %s", m)
+		if err := applyMigration(db, m); err != nil {
+			log.Errorf("[Store][database] apply schema migration %s err: %s", m, err.Error())
+			return ret, fmt.Errorf("apply migration %s: %w", m, err)
+		}
+	}
+	return ret, nil
+}
+
+// lockStartLock 锁住 start_lock 表的所有记录，与 LockBootstrap 互斥，避免多个节点同时执行迁移
+func lockStartLock(db *BaseDB) (*BaseTx, error) {
+	var lastErr error
+	for i := 0; i < maxMigrateLockTimes; i++ {
+		tx, err := db.Begin()
+		if err != nil {
+			return nil, err
+		}
+		rows, err := tx.Query("select lock_id from start_lock for update")
+		if err != nil {
+			// 这里可能会出现锁超时，超时则重试
+			log.Errorf("[Store][database] lock start_lock for migration err: %s", err.Error())
+			_ = tx.Rollback()
+			lastErr = err
+			continue
+		}
+		_ = rows.Close()
+		return tx, nil
+	}
+	return nil, fmt.Errorf("lock start_lock for migration: %w", lastErr)
+}
+
+// schemaVersion 查询数据库当前的 schema 版本，未执行过任何迁移时返回 0
+func schemaVersion(db *BaseDB) (int, error) {
+	var version sql.NullInt64
+	if err := db.QueryRow("select max(version) from schema_version").Scan(&version); err != nil {
+		log.Errorf("[Store][database] query schema version err: %s", err.Error())
+		return 0, err
+	}
+	return int(version.Int64), nil
+}
+
+// applyMigration 在一个事务中执行迁移并记录版本
+// PostgreSQL 的 DDL 支持事务，MySQL 的 DDL 会隐式提交，失败时需要人工处理
+func applyMigration(db *BaseDB, m *migration) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	for _, stmt := range m.statements {
+		// 迁移脚本按照目标数据库的语法编写，不经过方言改写
+		if _, err := tx.Tx.Exec(stmt); err != nil {
+			return err
+		}
+	}
+	if _, err := tx.Exec("insert into schema_version (version, description) values (?, ?)",
+		m.version, m.description); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// loadMigrations 加载方言目录下的迁移脚本，按版本号升序返回，版本号必须从 1 开始连续
+func loadMigrations(dir string) ([]*migration, error) {
+	dir = path.Join("migrations", dir)
+	entries, err := fs.ReadDir(migrationFS, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	migrations := make([]*migration, 0, len(entries))
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".sql") {
+			continue
+		}
+		idx := strings.Index(name, "_")
+		if idx <= 0 {
+			return nil, fmt.Errorf("invalid migration file name %s", name)
+		}
+		version, err := strconv.Atoi(name[:idx])
+		if err != nil {
+			return nil, fmt.Errorf("invalid migration file name %s: %w", name, err)
+		}
+		content, err := fs.ReadFile(migrationFS, path.Join(dir, name))
+		if err != nil {
+			return nil, err
+		}
+		migrations = append(migrations, &migration{
+			version:     version,
+			description: strings.TrimSuffix(name[idx+1:], ".sql"),
+			statements:  splitStatements(string(content)),
+		})
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].version < migrations[j].version
+	})
+	for i, m := range migrations {
+		if m.version != i+1 {
+			return nil, fmt.Errorf("migration version %d in %s is not continuous", m.version, dir)
+		}
+	}
+	return migrations, nil
+}
+
+// splitStatements 按分号切分 SQL 脚本并去掉注释，字符串、引用标识符以及 PostgreSQL $$ 块中的分号不切分
+func splitStatements(script string) []string {
+	var (
+		statements []string
+		buf        strings.Builder
+	)
+	flush := func() {
+		if stmt := strings.TrimSpace(buf.String()); stmt != "" {
+			statements = append(statements, stmt)
+		}
+		buf.Reset()
+	}
+
+	for i := 0; i < len(script); {
+		c := script[i]
+		switch {
+		case c == '-' && strings.HasPrefix(script[i:], "--"):
+			end := strings.IndexByte(script[i:], '\n')
+			if end < 0 {
+				end = len(script) - i
+			}
+			i += end
+		case c == '/' && strings.HasPrefix(script[i:], "/*"):
+			end := strings.Index(script[i+2:], "*/")
+			if end < 0 {
+				i = len(script)
+				continue
+			}
+			i += end + 4
+		case c == '\'' || c == '"' || c == '`':
+			end := i + 1
+			for end < len(script) {
+				if script[end] == '\\' && c != '`' {
+					end += 2
+					continue
+				}
+				if script[end] == c {
+					break
+				}
+				end++
+			}
+			end = minInt(end+1, len(script))
+			buf.WriteString(script[i:end])
+			i = end
+		case c == '$' && strings.HasPrefix(script[i:], "$$"):
+			end := strings.Index(script[i+2:], "$$")
+			if end < 0 {
+				end = len(script)
+			} else {
+				end += i + 4
+			}
+			buf.WriteString(script[i:end])
+			i = end
+		case c == ';':
+			flush()
+			i++
+		default:
+			buf.WriteByte(c)
+			i++
+		}
+	}
+	flush()
+	return statements
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
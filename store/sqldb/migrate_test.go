@@ -0,0 +1,91 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package sqldb
+
+import (
+	"strings"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// TestSplitStatements 迁移脚本切分的测试
+func TestSplitStatements(t *testing.T) {
+	Convey("注释被去掉，字符串中的分号不切分", t, func() {
+		stmts := splitStatements("/* header; */\n-- comment;\nALTER TABLE `t` ADD COLUMN `c` VARCHAR(8) " +
+			"NOT NULL DEFAULT ';';\n\nINSERT INTO t (c) VALUES ('a''b;', \"--x\");\n-- tail")
+		So(stmts, ShouldResemble, []string{
+			"ALTER TABLE `t` ADD COLUMN `c` VARCHAR(8) NOT NULL DEFAULT ';'",
+			"INSERT INTO t (c) VALUES ('a''b;', \"--x\")",
+		})
+	})
+	Convey("PostgreSQL $$ 块中的分号不切分", t, func() {
+		stmts := splitStatements("CREATE FUNCTION f() RETURNS TRIGGER AS $$ BEGIN NEW.a = 1; RETURN NEW; END; " +
+			"$$ LANGUAGE plpgsql;\nCREATE TABLE t (a INT)")
+		So(stmts, ShouldResemble, []string{
+			"CREATE FUNCTION f() RETURNS TRIGGER AS $$ BEGIN NEW.a = 1; RETURN NEW; END; $$ LANGUAGE plpgsql",
+			"CREATE TABLE t (a INT)",
+		})
+	})
+	Convey("只有注释的脚本没有需要执行的语句", t, func() {
+		So(splitStatements("--\n-- baseline\n--\n"), ShouldBeEmpty)
+	})
+}
+
+// TestLoadMigrations 内置迁移脚本加载的测试
+func TestLoadMigrations(t *testing.T) {
+	Convey("各方言的迁移脚本版本号从 1 开始连续", t, func() {
+		for _, d := range dialects {
+			migrations, err := loadMigrations(d.migrationDir())
+			So(err, ShouldBeNil)
+			So(len(migrations), ShouldBeGreaterThan, 0)
+			So(migrations[0].String(), ShouldEqual, "0001_baseline")
+			So(migrations[0].statements, ShouldBeEmpty)
+		}
+	})
+	Convey("各方言的最新版本号一致", t, func() {
+		mysqlMigrations, err := loadMigrations(mysqlDialect{}.migrationDir())
+		So(err, ShouldBeNil)
+		pgMigrations, err := loadMigrations(newPostgresDialect().migrationDir())
+		So(err, ShouldBeNil)
+		So(len(pgMigrations), ShouldEqual, len(mysqlMigrations))
+	})
+	Convey("补齐新增表的迁移可以重复执行", t, func() {
+		for _, d := range dialects {
+			migrations, err := loadMigrations(d.migrationDir())
+			So(err, ShouldBeNil)
+			So(len(migrations), ShouldBeGreaterThanOrEqualTo, 3)
+			So(migrations[2].String(), ShouldEqual, "0003_gray_release_circuitbreaker_v2_fault_mirror")
+			tables := 0
+			for _, stmt := range migrations[2].statements {
+				upper := strings.ToUpper(stmt)
+				if strings.HasPrefix(upper, "CREATE TABLE") || strings.HasPrefix(upper, "CREATE INDEX") {
+					So(upper, ShouldContainSubstring, "IF NOT EXISTS")
+				}
+				if strings.HasPrefix(upper, "CREATE TABLE") {
+					tables++
+				}
+			}
+			So(tables, ShouldEqual, 3)
+		}
+	})
+	Convey("不存在的目录返回错误", t, func() {
+		_, err := loadMigrations("oracle")
+		So(err, ShouldNotBeNil)
+	})
+}
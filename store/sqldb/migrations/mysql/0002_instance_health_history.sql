@@ -0,0 +1,38 @@
+/*
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+--
+-- 实例健康状态变化记录，由健康检查节点写入，按照保留时长定期清理
+--
+CREATE TABLE IF NOT EXISTS `instance_health_history`
+(
+    `id`          bigint unsigned NOT NULL AUTO_INCREMENT COMMENT '主键',
+    `instance_id` varchar(128)    NOT NULL COMMENT '实例ID',
+    `namespace`   varchar(64)     NOT NULL COMMENT '实例所属的命名空间',
+    `service`     varchar(128)    NOT NULL COMMENT '实例所属的服务',
+    `host`        varchar(128)    NOT NULL COMMENT '实例地址',
+    `port`        int(11)         NOT NULL COMMENT '实例端口',
+    `healthy`     tinyint(4)      NOT NULL DEFAULT '0' COMMENT '变化后是否健康',
+    `isolate`     tinyint(4)      NOT NULL DEFAULT '0' COMMENT '变化后是否隔离',
+    `reason`      varchar(64)     NOT NULL DEFAULT '' COMMENT '变化原因',
+    `checker`     varchar(128)    NOT NULL DEFAULT '' COMMENT '做出变更的健康检查节点',
+    `create_time` timestamp       NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    PRIMARY KEY (`id`),
+    KEY `instance_id` (`instance_id`),
+    KEY `service` (`namespace`, `service`),
+    KEY `create_time` (`create_time`)
+) ENGINE = InnoDB
+  AUTO_INCREMENT = 1 COMMENT = '实例健康状态变化记录表';
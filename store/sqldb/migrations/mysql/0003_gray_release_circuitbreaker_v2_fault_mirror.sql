@@ -0,0 +1,93 @@
+/*
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+--
+-- 补齐 v1.11 之后新增的表以及字段，使用 IF NOT EXISTS 保证已经通过 delta 脚本升级过的数据库可以重复执行
+--
+CREATE TABLE IF NOT EXISTS `config_file_gray_release`
+(
+    `id`          bigint unsigned NOT NULL AUTO_INCREMENT COMMENT '主键',
+    `name`        varchar(128)             DEFAULT NULL COMMENT '发布标题',
+    `namespace`   varchar(64)     NOT NULL COMMENT '所属的namespace',
+    `group`       varchar(128)    NOT NULL COMMENT '所属的文件组',
+    `file_name`   varchar(128)    NOT NULL COMMENT '配置文件名',
+    `content`     longtext        NOT NULL COMMENT '文件内容',
+    `comment`     varchar(512)             DEFAULT NULL COMMENT '备注信息',
+    `md5`         varchar(128)    NOT NULL COMMENT 'content的md5值',
+    `version`     int(11)         NOT NULL COMMENT '版本号，与正式发布共用递增序列',
+    `rule`        text            NOT NULL COMMENT '灰度规则',
+    `flag`        tinyint(4)      NOT NULL DEFAULT '0' COMMENT '是否被删除',
+    `create_time` timestamp       NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    `create_by`   varchar(32)              DEFAULT NULL COMMENT '创建人',
+    `modify_time` timestamp       NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '最后更新时间',
+    `modify_by`   varchar(32)              DEFAULT NULL COMMENT '最后更新人',
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uk_file` (`namespace`, `group`, `file_name`),
+    KEY `idx_modify_time` (`modify_time`)
+) ENGINE = InnoDB
+  AUTO_INCREMENT = 1 COMMENT = '配置文件灰度发布表';
+
+-- MySQL 不支持 ADD COLUMN IF NOT EXISTS，字段不存在时才执行
+SET @add_json_schema = IF((SELECT COUNT(*) FROM information_schema.COLUMNS
+                           WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = 'config_file_group'
+                             AND COLUMN_NAME = 'json_schema') = 0,
+                          'ALTER TABLE `config_file_group` ADD COLUMN `json_schema` text DEFAULT NULL',
+                          'SELECT 1');
+PREPARE add_json_schema FROM @add_json_schema;
+EXECUTE add_json_schema;
+DEALLOCATE PREPARE add_json_schema;
+
+CREATE TABLE IF NOT EXISTS `circuitbreaker_rule_v2`
+(
+    `id`            VARCHAR(128) NOT NULL,
+    `name`          VARCHAR(128) NOT NULL,
+    `namespace`     VARCHAR(64)  NOT NULL default '',
+    `enable`        INT          NOT NULL DEFAULT 0,
+    `level`         VARCHAR(32)  NOT NULL,
+    `src_service`   VARCHAR(128) NOT NULL,
+    `src_namespace` VARCHAR(64)  NOT NULL,
+    `dst_service`   VARCHAR(128) NOT NULL,
+    `dst_namespace` VARCHAR(64)  NOT NULL,
+    `rule`          TEXT,
+    `revision`      VARCHAR(40)  NOT NULL,
+    `description`   VARCHAR(1024) NOT NULL DEFAULT '',
+    `flag`          TINYINT(4)   NOT NULL DEFAULT '0',
+    `ctime`         TIMESTAMP    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    `mtime`         TIMESTAMP    NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    `etime`         TIMESTAMP    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (`id`),
+    KEY `name` (`name`),
+    KEY `mtime` (`mtime`)
+) engine = innodb;
+
+CREATE TABLE IF NOT EXISTS `fault_mirror_rule`
+(
+    `id`          VARCHAR(128)  NOT NULL,
+    `name`        VARCHAR(128)  NOT NULL,
+    `namespace`   VARCHAR(64)   NOT NULL default '',
+    `enable`      INT           NOT NULL DEFAULT 0,
+    `service`     VARCHAR(128)  NOT NULL,
+    `rule`        TEXT,
+    `revision`    VARCHAR(40)   NOT NULL,
+    `description` VARCHAR(1024) NOT NULL DEFAULT '',
+    `flag`        TINYINT(4)    NOT NULL DEFAULT '0',
+    `ctime`       TIMESTAMP     NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    `mtime`       TIMESTAMP     NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    `etime`       TIMESTAMP     NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (`id`),
+    KEY `name` (`name`),
+    KEY `mtime` (`mtime`)
+) engine = innodb;
@@ -0,0 +1,21 @@
+/*
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+--
+-- 基线版本，对应 scripts/postgresql/polaris_server.sql 初始化出的 schema，无需执行任何语句
+-- 后续的 schema 变更以 <版本号>_<描述>.sql 的形式追加到本目录，版本号需要连续，
+-- 同时需要修改初始化脚本，并在其 schema_version 表中写入最新的版本号
+--
@@ -0,0 +1,38 @@
+/*
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+--
+-- 实例健康状态变化记录，由健康检查节点写入，按照保留时长定期清理
+--
+CREATE TABLE IF NOT EXISTS instance_health_history
+(
+    id          BIGSERIAL NOT NULL,
+    instance_id VARCHAR(128) NOT NULL,
+    namespace   VARCHAR(64) NOT NULL,
+    service     VARCHAR(128) NOT NULL,
+    host        VARCHAR(128) NOT NULL,
+    port        INTEGER NOT NULL,
+    healthy     SMALLINT NOT NULL DEFAULT 0,
+    isolate     SMALLINT NOT NULL DEFAULT 0,
+    reason      VARCHAR(64) NOT NULL DEFAULT '',
+    checker     VARCHAR(128) NOT NULL DEFAULT '',
+    create_time TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (id)
+);
+COMMENT ON TABLE instance_health_history IS '实例健康状态变化记录表';
+CREATE INDEX IF NOT EXISTS instance_health_history_instance_id ON instance_health_history (instance_id);
+CREATE INDEX IF NOT EXISTS instance_health_history_service ON instance_health_history (namespace, service);
+CREATE INDEX IF NOT EXISTS instance_health_history_create_time ON instance_health_history (create_time);
@@ -0,0 +1,91 @@
+/*
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+--
+-- 补齐 v1.11 之后新增的表以及字段，使用 IF NOT EXISTS 保证已经包含这些表的数据库可以重复执行
+--
+CREATE TABLE IF NOT EXISTS config_file_gray_release
+(
+    id          BIGSERIAL NOT NULL,
+    name        VARCHAR(128) DEFAULT NULL,
+    namespace   VARCHAR(64) NOT NULL,
+    "group"     VARCHAR(128) NOT NULL,
+    file_name   VARCHAR(128) NOT NULL,
+    content     TEXT NOT NULL,
+    comment     VARCHAR(512) DEFAULT NULL,
+    md5         VARCHAR(128) NOT NULL,
+    version     INTEGER NOT NULL,
+    rule        TEXT NOT NULL,
+    flag        SMALLINT NOT NULL DEFAULT 0,
+    create_time TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    create_by   VARCHAR(32) DEFAULT NULL,
+    modify_time TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    modify_by   VARCHAR(32) DEFAULT NULL,
+    PRIMARY KEY (id),
+    UNIQUE (namespace, "group", file_name)
+);
+COMMENT ON TABLE config_file_gray_release IS '配置文件灰度发布表';
+CREATE INDEX IF NOT EXISTS config_file_gray_release_idx_modify_time ON config_file_gray_release (modify_time);
+DROP TRIGGER IF EXISTS config_file_gray_release_modify_time ON config_file_gray_release;
+CREATE TRIGGER config_file_gray_release_modify_time BEFORE UPDATE ON config_file_gray_release FOR EACH ROW EXECUTE PROCEDURE polaris_update_modify_time();
+
+ALTER TABLE config_file_group ADD COLUMN IF NOT EXISTS json_schema TEXT DEFAULT NULL;
+
+CREATE TABLE IF NOT EXISTS circuitbreaker_rule_v2
+(
+    id            VARCHAR(128) NOT NULL,
+    name          VARCHAR(128) NOT NULL,
+    namespace     VARCHAR(64) NOT NULL DEFAULT '',
+    enable        INTEGER NOT NULL DEFAULT 0,
+    level         VARCHAR(32) NOT NULL,
+    src_service   VARCHAR(128) NOT NULL,
+    src_namespace VARCHAR(64) NOT NULL,
+    dst_service   VARCHAR(128) NOT NULL,
+    dst_namespace VARCHAR(64) NOT NULL,
+    rule          TEXT,
+    revision      VARCHAR(40) NOT NULL,
+    description   VARCHAR(1024) NOT NULL DEFAULT '',
+    flag          SMALLINT NOT NULL DEFAULT 0,
+    ctime         TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    mtime         TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    etime         TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (id)
+);
+CREATE INDEX IF NOT EXISTS circuitbreaker_rule_v2_name ON circuitbreaker_rule_v2 (name);
+CREATE INDEX IF NOT EXISTS circuitbreaker_rule_v2_mtime ON circuitbreaker_rule_v2 (mtime);
+DROP TRIGGER IF EXISTS circuitbreaker_rule_v2_mtime ON circuitbreaker_rule_v2;
+CREATE TRIGGER circuitbreaker_rule_v2_mtime BEFORE UPDATE ON circuitbreaker_rule_v2 FOR EACH ROW EXECUTE PROCEDURE polaris_update_mtime();
+
+CREATE TABLE IF NOT EXISTS fault_mirror_rule
+(
+    id          VARCHAR(128) NOT NULL,
+    name        VARCHAR(128) NOT NULL,
+    namespace   VARCHAR(64) NOT NULL DEFAULT '',
+    enable      INTEGER NOT NULL DEFAULT 0,
+    service     VARCHAR(128) NOT NULL,
+    rule        TEXT,
+    revision    VARCHAR(40) NOT NULL,
+    description VARCHAR(1024) NOT NULL DEFAULT '',
+    flag        SMALLINT NOT NULL DEFAULT 0,
+    ctime       TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    mtime       TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    etime       TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (id)
+);
+CREATE INDEX IF NOT EXISTS fault_mirror_rule_name ON fault_mirror_rule (name);
+CREATE INDEX IF NOT EXISTS fault_mirror_rule_mtime ON fault_mirror_rule (mtime);
+DROP TRIGGER IF EXISTS fault_mirror_rule_mtime ON fault_mirror_rule;
+CREATE TRIGGER fault_mirror_rule_mtime BEFORE UPDATE ON fault_mirror_rule FOR EACH ROW EXECUTE PROCEDURE polaris_update_mtime();
@@ -0,0 +1,532 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package sqldb
+
+import (
+	"database/sql"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	lru "github.com/hashicorp/golang-lru"
+	_ "github.com/lib/pq"
+)
+
+const (
+	// pgStatementCacheSize 改写结果的缓存数量
+	pgStatementCacheSize = 4096
+)
+
+// pgTable 模拟 MySQL 的 REPLACE INTO / ON DUPLICATE KEY UPDATE 语义时需要的表结构
+type pgTable struct {
+	// keys 主键列
+	keys []string
+	// columns 全部列
+	columns []string
+}
+
+// pgTables 存储层中使用了 upsert 语法的表
+var pgTables = map[string]pgTable{
+	"instance": {
+		keys: []string{"id"},
+		columns: []string{"id", "service_id", "vpc_id", "host", "port", "protocol", "version", "health_status",
+			"isolate", "weight", "enable_health_check", "logic_set", "cmdb_region", "cmdb_zone", "cmdb_idc",
+			"priority", "revision", "flag", "ctime", "mtime"},
+	},
+	"health_check": {
+		keys:    []string{"id"},
+		columns: []string{"id", "type", "ttl"},
+	},
+	"client": {
+		keys:    []string{"id"},
+		columns: []string{"id", "host", "type", "version", "region", "zone", "campus", "flag", "ctime", "mtime"},
+	},
+	"circuitbreaker_rule_relation": {
+		keys:    []string{"service_id"},
+		columns: []string{"service_id", "rule_id", "rule_version", "flag", "ctime", "mtime"},
+	},
+	"ratelimit_revision": {
+		keys:    []string{"service_id"},
+		columns: []string{"service_id", "last_revision", "mtime"},
+	},
+	"auth_principal": {
+		keys:    []string{"strategy_id", "principal_id", "principal_role"},
+		columns: []string{"strategy_id", "principal_id", "principal_role"},
+	},
+	"auth_strategy_resource": {
+		keys:    []string{"strategy_id", "res_type", "res_id"},
+		columns: []string{"strategy_id", "res_type", "res_id", "ctime", "mtime"},
+	},
+}
+
+// pgIgnoreConflictTables 插入时忽略主键冲突的表
+// MySQL 中主键冲突只影响当前语句，调用方忽略 DuplicateEntryErr 后可以继续使用事务；
+// PostgreSQL 中任意语句失败都会导致整个事务不可用，因此在改写时直接忽略冲突
+var pgIgnoreConflictTables = map[string]struct{}{
+	"user_group_relation": {},
+}
+
+var (
+	pgForceIndexRegex    = regexp.MustCompile(`(?i)\b(force|use|ignore)\s+index\s*\([^)]*\)`)
+	pgSysdateRegex       = regexp.MustCompile(`(?i)\bsysdate\s*\(\s*\)`)
+	pgIfNullRegex        = regexp.MustCompile(`(?i)\bifnull\s*\(`)
+	pgFromUnixTimeRegex  = regexp.MustCompile(`(?i)\bfrom_unixtime\s*\(`)
+	pgUnixTimestampRegex = regexp.MustCompile(`(?i)\bunix_timestamp\s*\(`)
+	pgShareModeRegex     = regexp.MustCompile(`(?i)\block\s+in\s+share\s+mode\b`)
+	pgLimitRegex         = regexp.MustCompile(`(?i)\blimit\s+(\$\d+|\d+)\s*,\s*(\$\d+|\d+)`)
+	pgValueRegex         = regexp.MustCompile(`(?i)\bvalue\s*\(`)
+	pgUserTableRegex     = regexp.MustCompile(`(?i)\b(from|into|update|join)\s+user\b`)
+	pgInsertIgnoreRegex  = regexp.MustCompile(`(?is)^\s*insert\s+ignore\s+into\b`)
+	pgInsertTableRegex   = regexp.MustCompile(`(?is)^\s*insert\s+into\s+"?([a-z_]+)"?`)
+	pgDuplicateRegex     = regexp.MustCompile(`(?is)\bon\s+duplicate\s+key\s+update\b`)
+	pgValuesColumnRegex  = regexp.MustCompile(`(?i)\bvalues\s*\(\s*"?([a-z_]+)"?\s*\)`)
+	pgReplaceRegex       = regexp.MustCompile(`(?is)^\s*replace\s+into\s+"?([a-z_]+)"?\s*\(([^)]*)\)\s*values\s*`)
+	pgDeleteLimitRegex   = regexp.MustCompile(
+		`(?is)^\s*delete\s+from\s+("?[a-z_]+"?)\s+where\s+(.+?)\s+limit\s+(\$\d+|\d+)\s*$`)
+	pgParamRegex   = regexp.MustCompile(`\$(\d+)`)
+	pgLiteralRegex = regexp.MustCompile("\x00(\\d+)\x00")
+)
+
+// pgStatement 改写为 PostgreSQL 语法后的语句
+type pgStatement struct {
+	query string
+	// deleteQuery 不为空时需要在 query 之前执行，用于模拟 REPLACE INTO 先删除冲突行的语义
+	deleteQuery string
+	// deleteArgs deleteQuery 使用的参数在原始参数中的下标
+	deleteArgs []int
+}
+
+// postgresDialect PostgreSQL 方言
+type postgresDialect struct {
+	statements *lru.Cache
+}
+
+// newPostgresDialect 新建 PostgreSQL 方言
+func newPostgresDialect() *postgresDialect {
+	statements, _ := lru.New(pgStatementCacheSize)
+	return &postgresDialect{statements: statements}
+}
+
+func (d *postgresDialect) migrationDir() string {
+	return "postgresql"
+}
+
+func (d *postgresDialect) resetAutoIncrement(table string) string {
+	return fmt.Sprintf("SELECT setval(pg_get_serial_sequence('%s', 'id'), "+
+		"(SELECT COALESCE(MAX(id), 0) + 1 FROM %s), false)", table, table)
+}
+
+// lockTablesForRead SHARE 锁与写入需要的 ROW EXCLUSIVE 锁冲突，不影响其他会话读取，事务结束时释放
+func (d *postgresDialect) lockTablesForRead(tables []string) ([]string, string) {
+	items := make([]string, 0, len(tables))
+	for _, table := range tables {
+		items = append(items, `"`+table+`"`)
+	}
+	return []string{"BEGIN", "LOCK TABLE " + strings.Join(items, ", ") + " IN SHARE MODE"}, "ROLLBACK"
+}
+
+// dsn 生成 lib/pq 的连接串
+func (d *postgresDialect) dsn(c *dbConfig) string {
+	u := &url.URL{
+		Scheme:   "postgres",
+		User:     url.UserPassword(c.dbUser, c.dbPwd),
+		Host:     c.dbAddr,
+		Path:     c.dbName,
+		RawQuery: "sslmode=disable",
+	}
+	return u.String()
+}
+
+func (d *postgresDialect) exec(e executor, query string, args []interface{}) (sql.Result, error) {
+	stmt := d.rewrite(query)
+	args = pgArgs(args)
+	if stmt.deleteQuery == "" {
+		return e.Exec(stmt.query, args...)
+	}
+
+	// REPLACE INTO 需要先删除再插入，不在事务中时开启一个事务保证原子性
+	if db, ok := e.(*sql.DB); ok {
+		tx, err := db.Begin()
+		if err != nil {
+			return nil, err
+		}
+		defer func() { _ = tx.Rollback() }()
+		result, err := execReplace(tx, stmt, args)
+		if err != nil {
+			return nil, err
+		}
+		return result, tx.Commit()
+	}
+	return execReplace(e, stmt, args)
+}
+
+func (d *postgresDialect) query(e executor, query string, args []interface{}) (*sql.Rows, error) {
+	return e.Query(d.rewrite(query).query, pgArgs(args)...)
+}
+
+func (d *postgresDialect) queryRow(e executor, query string, args []interface{}) *sql.Row {
+	return e.QueryRow(d.rewrite(query).query, pgArgs(args)...)
+}
+
+// rewrite 改写 SQL，结果按照原始 SQL 缓存
+func (d *postgresDialect) rewrite(query string) *pgStatement {
+	if stmt, ok := d.statements.Get(query); ok {
+		return stmt.(*pgStatement)
+	}
+	stmt := rewritePostgreSQL(query)
+	d.statements.Add(query, stmt)
+	return stmt
+}
+
+// execReplace 执行 REPLACE INTO 改写后的删除与插入语句
+func execReplace(e executor, stmt *pgStatement, args []interface{}) (sql.Result, error) {
+	deleteArgs := make([]interface{}, 0, len(stmt.deleteArgs))
+	for _, index := range stmt.deleteArgs {
+		deleteArgs = append(deleteArgs, args[index])
+	}
+	if _, err := e.Exec(stmt.deleteQuery, deleteArgs...); err != nil {
+		return nil, err
+	}
+	return e.Exec(stmt.query, args...)
+}
+
+// pgArgs 转换参数类型，MySQL 驱动会将 bool 转换为 0/1，[]byte 按字符串处理
+func pgArgs(args []interface{}) []interface{} {
+	var ret []interface{}
+	for i, arg := range args {
+		var converted interface{}
+		switch v := arg.(type) {
+		case bool:
+			if v {
+				converted = 1
+			} else {
+				converted = 0
+			}
+		case []byte:
+			converted = string(v)
+		default:
+			continue
+		}
+		if ret == nil {
+			ret = make([]interface{}, len(args))
+			copy(ret, args)
+		}
+		ret[i] = converted
+	}
+	if ret == nil {
+		return args
+	}
+	return ret
+}
+
+// rewritePostgreSQL 将 MySQL 语法的 SQL 改写为 PostgreSQL 语法
+func rewritePostgreSQL(query string) *pgStatement {
+	code, literals := pgTokenize(query)
+
+	code = pgForceIndexRegex.ReplaceAllString(code, "")
+	code = pgSysdateRegex.ReplaceAllString(code, "clock_timestamp()")
+	code = pgIfNullRegex.ReplaceAllString(code, "COALESCE(")
+	code = pgFromUnixTimeRegex.ReplaceAllString(code, "TO_TIMESTAMP(")
+	code = pgRewriteUnixTimestamp(code)
+	code = pgShareModeRegex.ReplaceAllString(code, "FOR SHARE")
+	code = pgLimitRegex.ReplaceAllString(code, "LIMIT $2 OFFSET $1")
+	code = pgValueRegex.ReplaceAllString(code, "VALUES (")
+	code = pgUserTableRegex.ReplaceAllString(code, `$1 "user"`)
+
+	stmt := &pgStatement{}
+	switch {
+	case pgInsertIgnoreRegex.MatchString(code):
+		code = pgInsertIgnoreRegex.ReplaceAllString(code, "INSERT INTO") + " ON CONFLICT DO NOTHING"
+	case pgDuplicateRegex.MatchString(code):
+		code = pgRewriteDuplicate(code)
+	case pgReplaceRegex.MatchString(code):
+		code = pgRewriteReplace(code, stmt)
+	case pgDeleteLimitRegex.MatchString(code):
+		code = pgDeleteLimitRegex.ReplaceAllString(code,
+			"DELETE FROM $1 WHERE ctid IN (SELECT ctid FROM $1 WHERE $2 LIMIT $3)")
+	case pgIgnoreConflict(code):
+		code += " ON CONFLICT DO NOTHING"
+	}
+
+	stmt.query = pgRestoreLiterals(code, literals)
+	stmt.deleteQuery = pgRestoreLiterals(stmt.deleteQuery, literals)
+	return stmt
+}
+
+// pgIgnoreConflict 判断是否为插入 pgIgnoreConflictTables 中的表的语句
+func pgIgnoreConflict(code string) bool {
+	match := pgInsertTableRegex.FindStringSubmatch(code)
+	if match == nil {
+		return false
+	}
+	_, ok := pgIgnoreConflictTables[match[1]]
+	return ok
+}
+
+// pgTokenize 处理字符串常量、标识符与占位符
+// 字符串常量替换为标记，避免后续的改写误伤；反引号标识符转换为双引号标识符；? 占位符转换为 $n
+func pgTokenize(query string) (string, []string) {
+	var (
+		code     strings.Builder
+		literals []string
+		param    int
+	)
+	for i := 0; i < len(query); i++ {
+		c := query[i]
+		switch c {
+		case '?':
+			param++
+			code.WriteString("$" + strconv.Itoa(param))
+		case '`':
+			end := strings.IndexByte(query[i+1:], '`')
+			if end < 0 {
+				code.WriteString(query[i:])
+				return code.String(), literals
+			}
+			code.WriteString(`"` + strings.ToLower(query[i+1:i+1+end]) + `"`)
+			i += end + 1
+		case '\'', '"':
+			value, next := pgReadString(query, i)
+			code.WriteString("\x00" + strconv.Itoa(len(literals)) + "\x00")
+			literals = append(literals, pgQuoteString(value))
+			i = next
+		default:
+			code.WriteByte(c)
+		}
+	}
+	return code.String(), literals
+}
+
+// pgReadString 读取从 start 开始的字符串常量，返回常量的值与结束引号的位置
+func pgReadString(query string, start int) (string, int) {
+	quote := query[start]
+	var value strings.Builder
+	i := start + 1
+	for ; i < len(query); i++ {
+		c := query[i]
+		if c == '\\' && i+1 < len(query) {
+			i++
+			value.WriteByte(query[i])
+			continue
+		}
+		if c == quote {
+			// 连续两个引号表示引号本身
+			if i+1 < len(query) && query[i+1] == quote {
+				value.WriteByte(quote)
+				i++
+				continue
+			}
+			break
+		}
+		value.WriteByte(c)
+	}
+	return value.String(), i
+}
+
+// pgQuoteString 生成 PostgreSQL 的字符串常量
+func pgQuoteString(value string) string {
+	value = strings.ReplaceAll(value, "'", "''")
+	if strings.Contains(value, `\`) {
+		return "E'" + strings.ReplaceAll(value, `\`, `\\`) + "'"
+	}
+	return "'" + value + "'"
+}
+
+// pgRestoreLiterals 将字符串常量标记还原
+func pgRestoreLiterals(code string, literals []string) string {
+	if len(literals) == 0 || code == "" {
+		return code
+	}
+	return pgLiteralRegex.ReplaceAllStringFunc(code, func(marker string) string {
+		index, _ := strconv.Atoi(marker[1 : len(marker)-1])
+		return literals[index]
+	})
+}
+
+// pgRewriteUnixTimestamp UNIX_TIMESTAMP(expr) 改写为 CAST(FLOOR(EXTRACT(EPOCH FROM expr)) AS BIGINT)
+func pgRewriteUnixTimestamp(code string) string {
+	for {
+		loc := pgUnixTimestampRegex.FindStringIndex(code)
+		if loc == nil {
+			return code
+		}
+		end := pgMatchParen(code, loc[1]-1)
+		if end < 0 {
+			return code
+		}
+		expr := strings.TrimSpace(code[loc[1]:end])
+		if expr == "" {
+			expr = "clock_timestamp()"
+		}
+		code = code[:loc[0]] + "CAST(FLOOR(EXTRACT(EPOCH FROM " + expr + ")) AS BIGINT)" + code[end+1:]
+	}
+}
+
+// pgMatchParen 返回与 start 位置左括号匹配的右括号位置
+func pgMatchParen(code string, start int) int {
+	depth := 0
+	for i := start; i < len(code); i++ {
+		switch code[i] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// pgRewriteDuplicate ON DUPLICATE KEY UPDATE 改写为 ON CONFLICT (keys) DO UPDATE SET
+func pgRewriteDuplicate(code string) string {
+	match := pgInsertTableRegex.FindStringSubmatch(code)
+	if match == nil {
+		return code
+	}
+	table, ok := pgTables[match[1]]
+	if !ok {
+		return code
+	}
+	loc := pgDuplicateRegex.FindStringIndex(code)
+	assignments := pgValuesColumnRegex.ReplaceAllString(code[loc[1]:], "EXCLUDED.$1")
+	return code[:loc[0]] + "ON CONFLICT (" + strings.Join(table.keys, ", ") + ") DO UPDATE SET" + assignments
+}
+
+// pgRewriteReplace REPLACE INTO 改写为先删除主键冲突的行，再 INSERT ... ON CONFLICT DO UPDATE
+// 先删除可以保持 MySQL 中未指定的列恢复默认值、级联删除关联数据的语义
+func pgRewriteReplace(code string, stmt *pgStatement) string {
+	match := pgReplaceRegex.FindStringSubmatch(code)
+	tableName := match[1]
+	table, ok := pgTables[tableName]
+	if !ok {
+		return code
+	}
+	columns := strings.Split(match[2], ",")
+	for i := range columns {
+		columns[i] = strings.Trim(strings.TrimSpace(columns[i]), `"`)
+	}
+
+	insert := "INSERT INTO " + tableName + "(" + strings.Join(columns, ", ") + ") VALUES " +
+		strings.TrimSpace(code[len(match[0]):]) + " ON CONFLICT (" + strings.Join(table.keys, ", ") + ") "
+	assignments := make([]string, 0, len(table.columns))
+	for _, column := range table.columns {
+		if pgContains(table.keys, column) {
+			continue
+		}
+		if pgContains(columns, column) {
+			assignments = append(assignments, column+" = EXCLUDED."+column)
+		} else {
+			assignments = append(assignments, column+" = DEFAULT")
+		}
+	}
+	if len(assignments) == 0 {
+		insert += "DO NOTHING"
+	} else {
+		insert += "DO UPDATE SET " + strings.Join(assignments, ", ")
+	}
+
+	rows, ok := pgSplitValues(code[len(match[0]):])
+	if !ok {
+		return insert
+	}
+	keyIndexes := make([]int, 0, len(table.keys))
+	for _, key := range table.keys {
+		index := pgIndexOf(columns, key)
+		if index < 0 {
+			return insert
+		}
+		keyIndexes = append(keyIndexes, index)
+	}
+	conditions := make([]string, 0, len(rows))
+	for _, row := range rows {
+		if len(row) != len(columns) {
+			return insert
+		}
+		values := make([]string, 0, len(keyIndexes))
+		for _, index := range keyIndexes {
+			values = append(values, row[index])
+		}
+		conditions = append(conditions, "("+strings.Join(values, ", ")+")")
+	}
+	deleteQuery := fmt.Sprintf("DELETE FROM %s WHERE (%s) IN (%s)", tableName, strings.Join(table.keys, ", "),
+		strings.Join(conditions, ", "))
+
+	// 删除语句只使用了部分参数，需要重新编号
+	stmt.deleteQuery = pgParamRegex.ReplaceAllStringFunc(deleteQuery, func(param string) string {
+		index, _ := strconv.Atoi(param[1:])
+		stmt.deleteArgs = append(stmt.deleteArgs, index-1)
+		return "$" + strconv.Itoa(len(stmt.deleteArgs))
+	})
+	return insert
+}
+
+// pgSplitValues 拆分 VALUES 之后的多行数据，返回每一行的各个值
+func pgSplitValues(values string) ([][]string, bool) {
+	var (
+		rows  [][]string
+		row   []string
+		depth int
+		start int
+	)
+	for i := 0; i < len(values); i++ {
+		switch c := values[i]; c {
+		case '(':
+			depth++
+			if depth == 1 {
+				row = nil
+				start = i + 1
+			}
+		case ')':
+			depth--
+			if depth < 0 {
+				return nil, false
+			}
+			if depth == 0 {
+				row = append(row, strings.TrimSpace(values[start:i]))
+				rows = append(rows, row)
+			}
+		case ',':
+			if depth == 1 {
+				row = append(row, strings.TrimSpace(values[start:i]))
+				start = i + 1
+			}
+		default:
+			if depth == 0 && c != ' ' && c != '\t' && c != '\n' && c != '\r' {
+				return nil, false
+			}
+		}
+	}
+	return rows, depth == 0 && len(rows) > 0
+}
+
+func pgContains(values []string, target string) bool {
+	return pgIndexOf(values, target) >= 0
+}
+
+func pgIndexOf(values []string, target string) int {
+	for i := range values {
+		if values[i] == target {
+			return i
+		}
+	}
+	return -1
+}
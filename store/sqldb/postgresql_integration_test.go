@@ -0,0 +1,173 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package sqldb
+
+import (
+	"net"
+	"os"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	api "github.com/polarismesh/polaris/common/api/v1"
+	"github.com/polarismesh/polaris/common/model"
+	"github.com/polarismesh/polaris/common/utils"
+	"github.com/polarismesh/polaris/store"
+)
+
+// newPostgresTestStore 连接真实的 PostgreSQL，只在 STORE_MODE=pgsql 时执行
+// 连接参数读取 PGHOST、PGPORT、PGUSER、PGPASSWORD，数据库需要提前执行 postgresql/polaris_server.sql 初始化
+func newPostgresTestStore(t *testing.T) *stableStore {
+	if os.Getenv("STORE_MODE") != "pgsql" {
+		t.Skip("STORE_MODE is not pgsql, skip postgresql integration test")
+	}
+	getenv := func(key, defaultValue string) string {
+		if value := os.Getenv(key); value != "" {
+			return value
+		}
+		return defaultValue
+	}
+	s := &stableStore{name: PGSTORENAME, dialect: dialects[PGSTORENAME]}
+	err := s.Initialize(&store.Config{
+		Name: PGSTORENAME,
+		Option: map[string]interface{}{
+			"master": map[interface{}]interface{}{
+				"dbType": "postgres",
+				"dbUser": getenv("PGUSER", "postgres"),
+				"dbPwd":  getenv("PGPASSWORD", "polaris"),
+				"dbAddr": net.JoinHostPort(getenv("PGHOST", "127.0.0.1"), getenv("PGPORT", "5432")),
+				"dbName": "polaris_server",
+			},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		_ = s.Destroy()
+	})
+	return s
+}
+
+// TestPostgreSQLIntegration 在真实的 PostgreSQL 上执行改写后的 upsert、忽略冲突以及带 LIMIT 的删除语句
+func TestPostgreSQLIntegration(t *testing.T) {
+	s := newPostgresTestStore(t)
+	suffix := utils.NewUUID()[:8]
+
+	Convey("REPLACE INTO 重复写入实例以及健康检查", t, func() {
+		svc := &model.Service{
+			ID:        "pg-svc-" + suffix,
+			Name:      "pg-svc-" + suffix,
+			Namespace: "default",
+			Token:     utils.NewUUID(),
+			Owner:     "polaris",
+			Revision:  utils.NewUUID(),
+		}
+		So(s.AddService(svc), ShouldBeNil)
+		newInstance := func(weight uint32) *model.Instance {
+			return &model.Instance{
+				ServiceID: svc.ID,
+				Proto: &api.Instance{
+					Id:                utils.NewStringValue("pg-ins-" + suffix),
+					Host:              utils.NewStringValue("127.0.0.1"),
+					Port:              utils.NewUInt32Value(8080),
+					Weight:            utils.NewUInt32Value(weight),
+					EnableHealthCheck: utils.NewBoolValue(true),
+					HealthCheck: &api.HealthCheck{
+						Type:      api.HealthCheck_HEARTBEAT,
+						Heartbeat: &api.HeartbeatHealthCheck{Ttl: utils.NewUInt32Value(5)},
+					},
+					Revision: utils.NewStringValue(utils.NewUUID()),
+				},
+			}
+		}
+		So(s.AddInstance(newInstance(100)), ShouldBeNil)
+		So(s.AddInstance(newInstance(50)), ShouldBeNil)
+		saved, err := s.GetInstance("pg-ins-" + suffix)
+		So(err, ShouldBeNil)
+		So(saved, ShouldNotBeNil)
+		So(saved.Weight(), ShouldEqual, uint32(50))
+		So(saved.HealthCheck().GetHeartbeat().GetTtl().GetValue(), ShouldEqual, uint32(5))
+
+		// 软删除后按照批次清理
+		So(s.DeleteInstance("pg-ins-"+suffix), ShouldBeNil)
+		count, err := s.BatchCleanDeletedInstances(1)
+		So(err, ShouldBeNil)
+		So(count, ShouldEqual, uint32(1))
+	})
+
+	Convey("ON DUPLICATE KEY UPDATE 更新限流版本号", t, func() {
+		serviceID := "pg-rl-" + suffix
+		for i := 0; i < 2; i++ {
+			_, err := s.master.Exec("insert into ratelimit_revision(service_id,last_revision,mtime) "+
+				"values(?,?,sysdate()) on duplicate key update last_revision = ?", serviceID, suffix, suffix)
+			So(err, ShouldBeNil)
+		}
+		var revision string
+		So(s.master.QueryRow("select last_revision from ratelimit_revision where service_id = ?",
+			serviceID).Scan(&revision), ShouldBeNil)
+		So(revision, ShouldEqual, suffix)
+	})
+
+	Convey("重复添加用户组的成员不会导致事务失败", t, func() {
+		group := &model.UserGroupDetail{
+			UserGroup: &model.UserGroup{
+				ID:    "pg-group-" + suffix,
+				Name:  "pg-group-" + suffix,
+				Owner: "polaris",
+				Token: utils.NewUUID(),
+			},
+			UserIds: map[string]struct{}{"pg-user-1": {}},
+		}
+		So(s.AddGroup(group), ShouldBeNil)
+		So(s.UpdateGroup(&model.ModifyUserGroup{
+			ID:         group.ID,
+			Owner:      "polaris",
+			Token:      group.Token,
+			Comment:    "updated",
+			AddUserIds: []string{"pg-user-1", "pg-user-2"},
+		}), ShouldBeNil)
+		var count int
+		So(s.master.QueryRow("select count(*) from user_group_relation where group_id = ?",
+			group.ID).Scan(&count), ShouldBeNil)
+		So(count, ShouldEqual, 2)
+		saved, err := s.GetGroup(group.ID)
+		So(err, ShouldBeNil)
+		So(saved.Comment, ShouldEqual, "updated")
+	})
+
+	Convey("健康状态变化记录的时间函数", t, func() {
+		now := time.Now()
+		So(s.AddHealthChangeRecords([]*model.HealthChangeRecord{
+			{InstanceId: "pg-ins-" + suffix, Namespace: "default", Service: "svc", CreateTime: now.Add(-time.Hour)},
+			{InstanceId: "pg-ins-" + suffix, Namespace: "default", Service: "svc", CreateTime: now},
+		}), ShouldBeNil)
+		records, err := s.GetHealthChangeRecords(&model.HealthHistoryFilter{
+			InstanceId: "pg-ins-" + suffix, Since: now.Add(-2 * time.Hour), Limit: 1})
+		So(err, ShouldBeNil)
+		So(len(records), ShouldEqual, 1)
+		So(records[0].CreateTime.Unix(), ShouldEqual, now.Unix())
+		_, err = s.CleanHealthChangeRecords(now.Add(-time.Minute))
+		So(err, ShouldBeNil)
+		records, err = s.GetHealthChangeRecords(&model.HealthHistoryFilter{
+			InstanceId: "pg-ins-" + suffix, Since: now.Add(-2 * time.Hour)})
+		So(err, ShouldBeNil)
+		So(len(records), ShouldEqual, 1)
+	})
+}
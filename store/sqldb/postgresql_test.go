@@ -0,0 +1,205 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package sqldb
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// TestRewritePostgreSQL MySQL 语法改写为 PostgreSQL 语法的测试
+func TestRewritePostgreSQL(t *testing.T) {
+	Convey("函数、占位符与标识符可以正常改写", t, func() {
+		stmt := rewritePostgreSQL("select id, IFNULL(business, \"\"), UNIX_TIMESTAMP(ctime) from `user` " +
+			"where name like ? and mtime >= FROM_UNIXTIME(?) limit ?, ?")
+		So(stmt.query, ShouldEqual, "select id, COALESCE(business, ''), "+
+			"CAST(FLOOR(EXTRACT(EPOCH FROM ctime)) AS BIGINT) from \"user\" "+
+			"where name like $1 and mtime >= TO_TIMESTAMP($2) LIMIT $4 OFFSET $3")
+		So(stmt.deleteQuery, ShouldBeEmpty)
+
+		stmt = rewritePostgreSQL(nowSql)
+		So(stmt.query, ShouldEqual, "select CAST(FLOOR(EXTRACT(EPOCH FROM clock_timestamp())) AS BIGINT)")
+	})
+	Convey("字符串常量不会被改写", t, func() {
+		stmt := rewritePostgreSQL(`select 'it''s', "say \"hi\"", 'sysdate() ?' from t where x = ?`)
+		So(stmt.query, ShouldEqual, `select 'it''s', 'say "hi"', 'sysdate() ?' from t where x = $1`)
+	})
+	Convey("加锁与索引语法可以正常改写", t, func() {
+		stmt := rewritePostgreSQL("select name from namespace where name = ? and flag != 1 lock in share mode")
+		So(stmt.query, ShouldEqual, "select name from namespace where name = $1 and flag != 1 FOR SHARE")
+
+		stmt = rewritePostgreSQL("select id from instance force index(service_id, host) where id = ? for update")
+		So(stmt.query, ShouldEqual, "select id from instance  where id = $1 for update")
+	})
+	Convey("upsert 语法可以正常改写", t, func() {
+		stmt := rewritePostgreSQL("INSERT IGNORE INTO auth_principal(strategy_id, principal_id, principal_role) " +
+			"VALUES (?,?,?)")
+		So(stmt.query, ShouldEqual, "INSERT INTO auth_principal(strategy_id, principal_id, principal_role) "+
+			"VALUES ($1,$2,$3) ON CONFLICT DO NOTHING")
+
+		stmt = rewritePostgreSQL("INSERT INTO user_group_relation (group_id, user_id) VALUE (?,?)")
+		So(stmt.query, ShouldEqual, "INSERT INTO user_group_relation (group_id, user_id) VALUES ($1,$2) "+
+			"ON CONFLICT DO NOTHING")
+
+		stmt = rewritePostgreSQL("insert into ratelimit_revision(service_id,last_revision,mtime) " +
+			"values(?,?,sysdate()) on duplicate key update last_revision = VALUES(last_revision)")
+		So(stmt.query, ShouldEqual, "insert into ratelimit_revision(service_id,last_revision,mtime) "+
+			"values($1,$2,clock_timestamp()) ON CONFLICT (service_id) DO UPDATE SET last_revision = "+
+			"EXCLUDED.last_revision")
+	})
+	Convey("REPLACE INTO 先删除再插入", t, func() {
+		stmt := rewritePostgreSQL("replace into health_check(`id`, `type`, `ttl`) values(?,?,?),(?,?,?)")
+		So(stmt.query, ShouldEqual, "INSERT INTO health_check(id, type, ttl) VALUES ($1,$2,$3),($4,$5,$6) "+
+			"ON CONFLICT (id) DO UPDATE SET type = EXCLUDED.type, ttl = EXCLUDED.ttl")
+		So(stmt.deleteQuery, ShouldEqual, "DELETE FROM health_check WHERE (id) IN (($1), ($2))")
+		So(stmt.deleteArgs, ShouldResemble, []int{0, 3})
+
+		stmt = rewritePostgreSQL("replace into client(id, host, type, version, region, zone, campus, flag, " +
+			"ctime, mtime) values(?, ?, ?, ?, ?, ?, ?, 0, sysdate(), sysdate())")
+		So(stmt.query, ShouldContainSubstring, "ON CONFLICT (id) DO UPDATE SET host = EXCLUDED.host")
+		So(stmt.deleteQuery, ShouldEqual, "DELETE FROM client WHERE (id) IN (($1))")
+
+		stmt = rewritePostgreSQL("REPLACE INTO auth_strategy_resource(strategy_id, res_type, res_id) VALUES (?,?,?)")
+		So(stmt.query, ShouldEndWith, "ON CONFLICT (strategy_id, res_type, res_id) DO UPDATE SET "+
+			"ctime = DEFAULT, mtime = DEFAULT")
+		So(stmt.deleteQuery, ShouldEqual,
+			"DELETE FROM auth_strategy_resource WHERE (strategy_id, res_type, res_id) IN (($1, $2, $3))")
+		So(stmt.deleteArgs, ShouldResemble, []int{0, 1, 2})
+	})
+	Convey("带 LIMIT 的删除语句可以正常改写", t, func() {
+		stmt := rewritePostgreSQL("delete from instance where flag = 1 limit ?")
+		So(stmt.query, ShouldEqual,
+			"DELETE FROM instance WHERE ctid IN (SELECT ctid FROM instance WHERE flag = 1 LIMIT $1)")
+	})
+}
+
+// TestPgArgs 参数类型转换的测试
+func TestPgArgs(t *testing.T) {
+	Convey("bool 与 []byte 参数可以正常转换", t, func() {
+		args := []interface{}{"id", true, false, []byte("content"), 1}
+		So(pgArgs(args), ShouldResemble, []interface{}{"id", 1, 0, "content", 1})
+		So(args[1], ShouldEqual, true)
+	})
+}
+
+var (
+	srcReplaceRegex   = regexp.MustCompile("(?is)\\breplace\\s+into\\s+`?([a-z_]+)")
+	srcInsertRegex    = regexp.MustCompile("(?is)\\binsert\\s+(?:ignore\\s+)?into\\s+`?([a-z_]+)")
+	srcDuplicateRegex = regexp.MustCompile(`(?is)\bon\s+duplicate\s+key\s+update\b`)
+	ddlTableRegex     = regexp.MustCompile(`(?is)CREATE TABLE (?:IF NOT EXISTS )?"?([a-z_]+)"?\s*\((.*?)\n\);`)
+	ddlPrimaryRegex   = regexp.MustCompile(`(?i)PRIMARY KEY\s*\(([^)]*)\)`)
+)
+
+// upsertTables 扫描存储层的源码，找出使用了 REPLACE INTO 以及 ON DUPLICATE KEY UPDATE 的表
+func upsertTables(t *testing.T) map[string]struct{} {
+	files, err := filepath.Glob("*.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tables := make(map[string]struct{})
+	for _, file := range files {
+		// 跳过测试以及方言自身的代码
+		if strings.HasSuffix(file, "_test.go") || file == "postgresql.go" {
+			continue
+		}
+		data, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		code := string(data)
+		for _, match := range srcReplaceRegex.FindAllStringSubmatch(code, -1) {
+			tables[strings.ToLower(match[1])] = struct{}{}
+		}
+		// ON DUPLICATE KEY UPDATE 属于在它之前最近的一条 INSERT 语句
+		for _, loc := range srcDuplicateRegex.FindAllStringIndex(code, -1) {
+			inserts := srcInsertRegex.FindAllStringSubmatch(code[:loc[0]], -1)
+			if len(inserts) == 0 {
+				t.Fatalf("%s: on duplicate key update without insert", file)
+			}
+			tables[strings.ToLower(inserts[len(inserts)-1][1])] = struct{}{}
+		}
+	}
+	return tables
+}
+
+// pgSchemaTables 解析 PostgreSQL 初始化脚本中的表结构
+func pgSchemaTables(t *testing.T) map[string]pgTable {
+	data, err := os.ReadFile(filepath.Join("scripts", "postgresql", "polaris_server.sql"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tables := make(map[string]pgTable)
+	for _, match := range ddlTableRegex.FindAllStringSubmatch(string(data), -1) {
+		table := pgTable{}
+		for _, line := range strings.Split(match[2], "\n") {
+			line = strings.TrimSpace(line)
+			if line == "" || line == "(" || strings.HasPrefix(line, "--") {
+				continue
+			}
+			if key := ddlPrimaryRegex.FindStringSubmatch(line); key != nil {
+				for _, column := range strings.Split(key[1], ",") {
+					table.keys = append(table.keys, strings.Trim(strings.TrimSpace(column), `"`))
+				}
+				continue
+			}
+			upper := strings.ToUpper(line)
+			if strings.HasPrefix(upper, "CONSTRAINT") || strings.HasPrefix(upper, "UNIQUE") {
+				continue
+			}
+			table.columns = append(table.columns, strings.Trim(strings.Fields(line)[0], `"`))
+		}
+		tables[match[1]] = table
+	}
+	return tables
+}
+
+func sortedCopy(values []string) []string {
+	ret := append([]string(nil), values...)
+	sort.Strings(ret)
+	return ret
+}
+
+// TestPgTablesSchema pgTables 需要覆盖存储层中全部 upsert 的表，并且与 PostgreSQL 的建表脚本保持一致
+func TestPgTablesSchema(t *testing.T) {
+	schema := pgSchemaTables(t)
+	Convey("存储层中 upsert 的表都在 pgTables 中", t, func() {
+		for table := range upsertTables(t) {
+			So(pgTables, ShouldContainKey, table)
+		}
+	})
+	Convey("pgTables 的主键与列和建表脚本一致", t, func() {
+		for name, table := range pgTables {
+			So(schema, ShouldContainKey, name)
+			ddl := schema[name]
+			So(table.keys, ShouldResemble, ddl.keys)
+			So(sortedCopy(table.columns), ShouldResemble, sortedCopy(ddl.columns))
+		}
+	})
+	Convey("忽略主键冲突的表存在主键", t, func() {
+		for name := range pgIgnoreConflictTables {
+			So(schema, ShouldContainKey, name)
+			So(schema[name].keys, ShouldNotBeEmpty)
+		}
+	})
+}
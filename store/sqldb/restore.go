@@ -0,0 +1,203 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package sqldb
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/polarismesh/polaris/store"
+)
+
+// restoreTable 记录类型对应的数据表以及需要还原的列
+type restoreTable struct {
+	table       string
+	keyColumn   string
+	ctimeColumn string
+	mtimeColumn string
+	// autoIncrement 主键为自增ID，还原ID后需要重置自增序列
+	autoIncrement bool
+}
+
+var restoreTables = map[store.RecordKind]restoreTable{
+	store.RecordNamespace:        {table: "namespace", keyColumn: "name", ctimeColumn: "ctime", mtimeColumn: "mtime"},
+	store.RecordService:          {table: "service", keyColumn: "id", ctimeColumn: "ctime", mtimeColumn: "mtime"},
+	store.RecordInstance:         {table: "instance", keyColumn: "id", ctimeColumn: "ctime", mtimeColumn: "mtime"},
+	store.RecordRoutingConfig:    {table: "routing_config", keyColumn: "id", ctimeColumn: "ctime", mtimeColumn: "mtime"},
+	store.RecordRoutingConfigV2:  {table: "routing_config_v2", keyColumn: "id", ctimeColumn: "ctime", mtimeColumn: "mtime"},
+	store.RecordRateLimit:        {table: "ratelimit_config", keyColumn: "id", ctimeColumn: "ctime", mtimeColumn: "mtime"},
+	store.RecordCircuitBreaker:   {table: "circuitbreaker_rule", keyColumn: "id", ctimeColumn: "ctime", mtimeColumn: "mtime"},
+	store.RecordCircuitBreakerV2: {table: "circuitbreaker_rule_v2", keyColumn: "id", ctimeColumn: "ctime", mtimeColumn: "mtime"},
+	store.RecordFaultMirrorRule:  {table: "fault_mirror_rule", keyColumn: "id", ctimeColumn: "ctime", mtimeColumn: "mtime"},
+	store.RecordConfigFileGroup: {table: "config_file_group", keyColumn: "id", ctimeColumn: "create_time",
+		mtimeColumn: "modify_time", autoIncrement: true},
+	store.RecordConfigFile: {table: "config_file", keyColumn: "id", ctimeColumn: "create_time",
+		mtimeColumn: "modify_time", autoIncrement: true},
+	store.RecordConfigFileRelease: {table: "config_file_release", keyColumn: "id", ctimeColumn: "create_time",
+		mtimeColumn: "modify_time", autoIncrement: true},
+	store.RecordConfigFileReleaseHistory: {table: "config_file_release_history", keyColumn: "id",
+		ctimeColumn: "create_time", mtimeColumn: "modify_time", autoIncrement: true},
+	store.RecordConfigFileTemplate: {table: "config_file_template", keyColumn: "id", ctimeColumn: "create_time",
+		mtimeColumn: "modify_time", autoIncrement: true},
+	store.RecordUser:      {table: "user", keyColumn: "id", ctimeColumn: "ctime", mtimeColumn: "mtime"},
+	store.RecordUserGroup: {table: "user_group", keyColumn: "id", ctimeColumn: "ctime", mtimeColumn: "mtime"},
+	store.RecordStrategy:  {table: "auth_strategy", keyColumn: "id", ctimeColumn: "ctime", mtimeColumn: "mtime"},
+}
+
+// RestoreRecordMetas 还原记录原有的时间戳以及配置中心记录的自增ID
+// 数据表中存在导入之外的记录时只还原时间戳，避免源存储的自增ID与已有的记录冲突
+func (s *stableStore) RestoreRecordMetas(kind store.RecordKind, metas []*store.RecordMeta) error {
+	t, ok := restoreTables[kind]
+	if !ok {
+		return fmt.Errorf("record kind %s not support restore", kind)
+	}
+	if len(metas) == 0 {
+		return nil
+	}
+
+	var offset uint64
+	err := RetryTransaction("restoreRecordMetas", func() error {
+		tx, err := s.master.Begin()
+		if err != nil {
+			return err
+		}
+		defer func() { _ = tx.Rollback() }()
+
+		offset = 0
+		if t.autoIncrement {
+			if offset, err = shiftRestoreIDs(tx, t, metas); err != nil {
+				log.Errorf("[Store][database] shift %s ids err: %s", kind, err.Error())
+				return err
+			}
+		}
+		for _, meta := range metas {
+			if err := restoreRecordMeta(tx, t, meta, offset); err != nil {
+				log.Errorf("[Store][database] restore %s(%s) meta err: %s", kind, meta.Key, err.Error())
+				return err
+			}
+		}
+		return tx.Commit()
+	})
+	if err != nil {
+		return store.Error(err)
+	}
+	if !t.autoIncrement {
+		return nil
+	}
+	if offset == 0 {
+		return fmt.Errorf("table %s holds records not imported, ids of %s are not restored", t.table, kind)
+	}
+	// 迁移脚本级别的语句，不经过方言改写
+	if _, err := s.master.DB.Exec(s.dialect.resetAutoIncrement(t.table)); err != nil {
+		log.Errorf("[Store][database] reset %s auto increment err: %s", t.table, err.Error())
+		return store.Error(err)
+	}
+	return nil
+}
+
+// shiftRestoreIDs 数据表中只有本次新写入的记录时，先将记录的ID整体移动到源存储的ID范围之外，
+// 避免还原过程中与尚未还原的记录互相冲突，返回移动的偏移量，为 0 时不还原自增ID
+func shiftRestoreIDs(tx *BaseTx, t restoreTable, metas []*store.RecordMeta) (uint64, error) {
+	var offset uint64
+	originCount := 0
+	for _, meta := range metas {
+		if meta.OriginID == 0 {
+			continue
+		}
+		originCount++
+		key, err := strconv.ParseUint(meta.Key, 10, 64)
+		if err != nil {
+			return 0, err
+		}
+		if key > offset {
+			offset = key
+		}
+		if meta.OriginID > offset {
+			offset = meta.OriginID
+		}
+	}
+	if originCount == 0 {
+		return 0, nil
+	}
+
+	var total int
+	if err := tx.QueryRow("select count(*) from " + t.table).Scan(&total); err != nil {
+		return 0, err
+	}
+	if total != originCount {
+		return 0, nil
+	}
+
+	// 按照ID倒序移动，避免移动过程中与尚未移动的记录冲突
+	sorted := make([]*store.RecordMeta, 0, originCount)
+	for _, meta := range metas {
+		if meta.OriginID != 0 {
+			sorted = append(sorted, meta)
+		}
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		left, _ := strconv.ParseUint(sorted[i].Key, 10, 64)
+		right, _ := strconv.ParseUint(sorted[j].Key, 10, 64)
+		return left > right
+	})
+	for _, meta := range sorted {
+		key, _ := strconv.ParseUint(meta.Key, 10, 64)
+		if _, err := tx.Exec("update "+t.table+" set id = ? where id = ?", key+offset, key); err != nil {
+			return 0, err
+		}
+	}
+	return offset, nil
+}
+
+// restoreRecordMeta 还原单条记录的元信息
+// offset 不为 0 时记录的ID已经移动了 offset，需要还原为源存储中的自增ID
+func restoreRecordMeta(tx *BaseTx, t restoreTable, meta *store.RecordMeta, offset uint64) error {
+	str := "update " + t.table + " set "
+	args := make([]interface{}, 0, 5)
+	if !meta.CreateTime.IsZero() {
+		str += t.ctimeColumn + " = FROM_UNIXTIME(?), "
+		args = append(args, timeToTimestamp(meta.CreateTime))
+	}
+	if !meta.ModifyTime.IsZero() {
+		str += t.mtimeColumn + " = FROM_UNIXTIME(?), "
+		args = append(args, timeToTimestamp(meta.ModifyTime))
+	}
+	key := meta.Key
+	if offset != 0 && meta.OriginID != 0 {
+		str += "id = ?, "
+		args = append(args, meta.OriginID)
+		id, err := strconv.ParseUint(meta.Key, 10, 64)
+		if err != nil {
+			return err
+		}
+		key = strconv.FormatUint(id+offset, 10)
+	}
+	if len(args) == 0 {
+		return nil
+	}
+
+	str = str[:len(str)-2] + " where " + t.keyColumn + " = ?"
+	args = append(args, key)
+	if meta.Version != "" {
+		str += " and version = ?"
+		args = append(args, meta.Version)
+	}
+	_, err := tx.Exec(str, args...)
+	return err
+}
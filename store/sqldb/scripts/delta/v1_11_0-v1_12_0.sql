@@ -39,3 +39,73 @@ CREATE TABLE `routing_config_v2`
     KEY `mtime` (`mtime`)
 ) engine = innodb;
 
+CREATE TABLE `circuitbreaker_rule_v2`
+(
+    `id`            VARCHAR(128) NOT NULL,
+    `name`          VARCHAR(128) NOT NULL,
+    `namespace`     VARCHAR(64)  NOT NULL default '',
+    `enable`        INT          NOT NULL DEFAULT 0,
+    `level`         VARCHAR(32)  NOT NULL,
+    `src_service`   VARCHAR(128) NOT NULL,
+    `src_namespace` VARCHAR(64)  NOT NULL,
+    `dst_service`   VARCHAR(128) NOT NULL,
+    `dst_namespace` VARCHAR(64)  NOT NULL,
+    `rule`          TEXT,
+    `revision`      VARCHAR(40)  NOT NULL,
+    `description`   VARCHAR(1024) NOT NULL DEFAULT '',
+    `flag`          TINYINT(4)   NOT NULL DEFAULT '0',
+    `ctime`         TIMESTAMP    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    `mtime`         TIMESTAMP    NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    `etime`         TIMESTAMP    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (`id`),
+    KEY `name` (`name`),
+    KEY `mtime` (`mtime`)
+) engine = innodb;
+
+CREATE TABLE `fault_mirror_rule`
+(
+    `id`          VARCHAR(128)  NOT NULL,
+    `name`        VARCHAR(128)  NOT NULL,
+    `namespace`   VARCHAR(64)   NOT NULL default '',
+    `enable`      INT           NOT NULL DEFAULT 0,
+    `service`     VARCHAR(128)  NOT NULL,
+    `rule`        TEXT,
+    `revision`    VARCHAR(40)   NOT NULL,
+    `description` VARCHAR(1024) NOT NULL DEFAULT '',
+    `flag`        TINYINT(4)    NOT NULL DEFAULT '0',
+    `ctime`       TIMESTAMP     NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    `mtime`       TIMESTAMP     NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    `etime`       TIMESTAMP     NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (`id`),
+    KEY `name` (`name`),
+    KEY `mtime` (`mtime`)
+) engine = innodb;
+
+-- --------------------------------------------------------
+--
+-- Table structure `config_file_gray_release`
+--
+CREATE TABLE `config_file_gray_release`
+(
+    `id`          bigint unsigned NOT NULL AUTO_INCREMENT COMMENT '主键',
+    `name`        varchar(128)             DEFAULT NULL COMMENT '发布标题',
+    `namespace`   varchar(64)     NOT NULL COMMENT '所属的namespace',
+    `group`       varchar(128)    NOT NULL COMMENT '所属的文件组',
+    `file_name`   varchar(128)    NOT NULL COMMENT '配置文件名',
+    `content`     longtext        NOT NULL COMMENT '文件内容',
+    `comment`     varchar(512)             DEFAULT NULL COMMENT '备注信息',
+    `md5`         varchar(128)    NOT NULL COMMENT 'content的md5值',
+    `version`     int(11)         NOT NULL COMMENT '版本号，与正式发布共用递增序列',
+    `rule`        text            NOT NULL COMMENT '灰度规则',
+    `flag`        tinyint(4)      NOT NULL DEFAULT '0' COMMENT '是否被删除',
+    `create_time` timestamp       NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    `create_by`   varchar(32)              DEFAULT NULL COMMENT '创建人',
+    `modify_time` timestamp       NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '最后更新时间',
+    `modify_by`   varchar(32)              DEFAULT NULL COMMENT '最后更新人',
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uk_file` (`namespace`, `group`, `file_name`),
+    KEY `idx_modify_time` (`modify_time`)
+) ENGINE = InnoDB
+  AUTO_INCREMENT = 1 COMMENT = '配置文件灰度发布表';
+
+ALTER TABLE `config_file_group` ADD COLUMN `json_schema` text DEFAULT NULL COMMENT '配置内容校验使用的 JSON Schema';
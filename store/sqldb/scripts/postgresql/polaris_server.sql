@@ -0,0 +1,833 @@
+/*
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+-- PostgreSQL 版本的初始化脚本，使用前需要先创建数据库，例如: CREATE DATABASE polaris_server;
+-- mtime / modify_time 列通过触发器模拟 MySQL 的 ON UPDATE CURRENT_TIMESTAMP
+
+CREATE OR REPLACE FUNCTION polaris_update_mtime() RETURNS TRIGGER AS
+$$
+BEGIN
+    IF NEW.mtime = OLD.mtime AND NEW IS DISTINCT FROM OLD THEN
+        NEW.mtime = clock_timestamp();
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION polaris_update_modify_time() RETURNS TRIGGER AS
+$$
+BEGIN
+    IF NEW.modify_time = OLD.modify_time AND NEW IS DISTINCT FROM OLD THEN
+        NEW.modify_time = clock_timestamp();
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TABLE business
+(
+    id    VARCHAR(32) NOT NULL,
+    name  VARCHAR(64) NOT NULL,
+    token VARCHAR(64) NOT NULL,
+    owner VARCHAR(1024) NOT NULL,
+    flag  SMALLINT NOT NULL DEFAULT 0,
+    ctime TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    mtime TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (id)
+);
+CREATE TRIGGER business_mtime BEFORE UPDATE ON business FOR EACH ROW EXECUTE PROCEDURE polaris_update_mtime();
+
+CREATE TABLE instance
+(
+    id                  VARCHAR(128) NOT NULL,
+    service_id          VARCHAR(32) NOT NULL,
+    vpc_id              VARCHAR(64) DEFAULT NULL,
+    host                VARCHAR(128) NOT NULL,
+    port                INTEGER NOT NULL,
+    protocol            VARCHAR(32) DEFAULT NULL,
+    version             VARCHAR(32) DEFAULT NULL,
+    health_status       SMALLINT NOT NULL DEFAULT 1,
+    isolate             SMALLINT NOT NULL DEFAULT 0,
+    weight              SMALLINT NOT NULL DEFAULT 100,
+    enable_health_check SMALLINT NOT NULL DEFAULT 0,
+    logic_set           VARCHAR(128) DEFAULT NULL,
+    cmdb_region         VARCHAR(128) DEFAULT NULL,
+    cmdb_zone           VARCHAR(128) DEFAULT NULL,
+    cmdb_idc            VARCHAR(128) DEFAULT NULL,
+    priority            SMALLINT NOT NULL DEFAULT 0,
+    revision            VARCHAR(32) NOT NULL,
+    flag                SMALLINT NOT NULL DEFAULT 0,
+    ctime               TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    mtime               TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (id)
+);
+CREATE INDEX instance_service_id ON instance (service_id);
+CREATE INDEX instance_mtime ON instance (mtime);
+CREATE INDEX instance_host ON instance (host);
+CREATE TRIGGER instance_mtime BEFORE UPDATE ON instance FOR EACH ROW EXECUTE PROCEDURE polaris_update_mtime();
+
+CREATE TABLE health_check
+(
+    id   VARCHAR(128) NOT NULL,
+    type SMALLINT NOT NULL DEFAULT 0,
+    ttl  INTEGER NOT NULL,
+    PRIMARY KEY (id),
+    CONSTRAINT health_check_ibfk_1 FOREIGN KEY (id) REFERENCES instance (id) ON DELETE CASCADE ON UPDATE CASCADE
+);
+
+CREATE TABLE instance_metadata
+(
+    id     VARCHAR(128) NOT NULL,
+    mkey   VARCHAR(128) NOT NULL,
+    mvalue VARCHAR(4096) NOT NULL,
+    ctime  TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    mtime  TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (id, mkey),
+    CONSTRAINT instance_metadata_ibfk_1 FOREIGN KEY (id) REFERENCES instance (id) ON DELETE CASCADE ON UPDATE CASCADE
+);
+CREATE INDEX instance_metadata_mkey ON instance_metadata (mkey);
+CREATE TRIGGER instance_metadata_mtime BEFORE UPDATE ON instance_metadata FOR EACH ROW EXECUTE PROCEDURE polaris_update_mtime();
+
+CREATE TABLE namespace
+(
+    name    VARCHAR(64) NOT NULL,
+    comment VARCHAR(1024) DEFAULT NULL,
+    token   VARCHAR(64) NOT NULL,
+    owner   VARCHAR(1024) NOT NULL,
+    flag    SMALLINT NOT NULL DEFAULT 0,
+    ctime   TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    mtime   TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (name)
+);
+CREATE TRIGGER namespace_mtime BEFORE UPDATE ON namespace FOR EACH ROW EXECUTE PROCEDURE polaris_update_mtime();
+
+CREATE TABLE routing_config
+(
+    id         VARCHAR(32) NOT NULL,
+    in_bounds  TEXT,
+    out_bounds TEXT,
+    revision   VARCHAR(40) NOT NULL,
+    flag       SMALLINT NOT NULL DEFAULT 0,
+    ctime      TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    mtime      TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (id)
+);
+CREATE INDEX routing_config_mtime ON routing_config (mtime);
+CREATE TRIGGER routing_config_mtime BEFORE UPDATE ON routing_config FOR EACH ROW EXECUTE PROCEDURE polaris_update_mtime();
+
+CREATE TABLE ratelimit_config
+(
+    id         VARCHAR(32) NOT NULL,
+    name       VARCHAR(64) NOT NULL,
+    disable    SMALLINT NOT NULL DEFAULT 0,
+    service_id VARCHAR(32) NOT NULL,
+    method     VARCHAR(512) NOT NULL,
+    labels     TEXT NOT NULL,
+    priority   SMALLINT NOT NULL DEFAULT 0,
+    rule       TEXT NOT NULL,
+    revision   VARCHAR(32) NOT NULL,
+    flag       SMALLINT NOT NULL DEFAULT 0,
+    ctime      TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    mtime      TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    etime      TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (id)
+);
+CREATE INDEX ratelimit_config_mtime ON ratelimit_config (mtime);
+CREATE INDEX ratelimit_config_service_id ON ratelimit_config (service_id);
+CREATE TRIGGER ratelimit_config_mtime BEFORE UPDATE ON ratelimit_config FOR EACH ROW EXECUTE PROCEDURE polaris_update_mtime();
+
+CREATE TABLE ratelimit_revision
+(
+    service_id    VARCHAR(32) NOT NULL,
+    last_revision VARCHAR(40) NOT NULL,
+    mtime         TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (service_id)
+);
+CREATE INDEX ratelimit_revision_service_id ON ratelimit_revision (service_id);
+CREATE INDEX ratelimit_revision_mtime ON ratelimit_revision (mtime);
+CREATE TRIGGER ratelimit_revision_mtime BEFORE UPDATE ON ratelimit_revision FOR EACH ROW EXECUTE PROCEDURE polaris_update_mtime();
+
+CREATE TABLE service
+(
+    id           VARCHAR(32) NOT NULL,
+    name         VARCHAR(128) NOT NULL,
+    namespace    VARCHAR(64) NOT NULL,
+    ports        TEXT DEFAULT NULL,
+    business     VARCHAR(64) DEFAULT NULL,
+    department   VARCHAR(1024) DEFAULT NULL,
+    cmdb_mod1    VARCHAR(1024) DEFAULT NULL,
+    cmdb_mod2    VARCHAR(1024) DEFAULT NULL,
+    cmdb_mod3    VARCHAR(1024) DEFAULT NULL,
+    comment      VARCHAR(1024) DEFAULT NULL,
+    token        VARCHAR(2048) NOT NULL,
+    revision     VARCHAR(32) NOT NULL,
+    owner        VARCHAR(1024) NOT NULL,
+    flag         SMALLINT NOT NULL DEFAULT 0,
+    reference    VARCHAR(32) DEFAULT NULL,
+    refer_filter VARCHAR(1024) DEFAULT NULL,
+    platform_id  VARCHAR(32) DEFAULT '',
+    ctime        TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    mtime        TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (id),
+    UNIQUE (name, namespace)
+);
+CREATE INDEX service_namespace ON service (namespace);
+CREATE INDEX service_mtime ON service (mtime);
+CREATE INDEX service_reference ON service (reference);
+CREATE INDEX service_platform_id ON service (platform_id);
+CREATE TRIGGER service_mtime BEFORE UPDATE ON service FOR EACH ROW EXECUTE PROCEDURE polaris_update_mtime();
+
+CREATE TABLE service_metadata
+(
+    id     VARCHAR(32) NOT NULL,
+    mkey   VARCHAR(128) NOT NULL,
+    mvalue VARCHAR(4096) NOT NULL,
+    ctime  TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    mtime  TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (id, mkey),
+    CONSTRAINT service_metadata_ibfk_1 FOREIGN KEY (id) REFERENCES service (id) ON DELETE CASCADE ON UPDATE CASCADE
+);
+CREATE INDEX service_metadata_mkey ON service_metadata (mkey);
+CREATE TRIGGER service_metadata_mtime BEFORE UPDATE ON service_metadata FOR EACH ROW EXECUTE PROCEDURE polaris_update_mtime();
+
+CREATE TABLE owner_service_map
+(
+    id        VARCHAR(32) NOT NULL,
+    owner     VARCHAR(32) NOT NULL,
+    service   VARCHAR(128) NOT NULL,
+    namespace VARCHAR(64) NOT NULL,
+    PRIMARY KEY (id)
+);
+CREATE INDEX owner_service_map_owner ON owner_service_map (owner);
+CREATE INDEX owner_service_map_name ON owner_service_map (service, namespace);
+
+CREATE TABLE circuitbreaker_rule
+(
+    id         VARCHAR(97) NOT NULL,
+    version    VARCHAR(32) NOT NULL DEFAULT 'master',
+    name       VARCHAR(128) NOT NULL,
+    namespace  VARCHAR(64) NOT NULL,
+    business   VARCHAR(64) DEFAULT NULL,
+    department VARCHAR(1024) DEFAULT NULL,
+    comment    VARCHAR(1024) DEFAULT NULL,
+    inbounds   TEXT NOT NULL,
+    outbounds  TEXT NOT NULL,
+    token      VARCHAR(32) NOT NULL,
+    owner      VARCHAR(1024) NOT NULL,
+    revision   VARCHAR(32) NOT NULL,
+    flag       SMALLINT NOT NULL DEFAULT 0,
+    ctime      TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    mtime      TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (id, version),
+    UNIQUE (name, namespace, version)
+);
+CREATE INDEX circuitbreaker_rule_mtime ON circuitbreaker_rule (mtime);
+CREATE TRIGGER circuitbreaker_rule_mtime BEFORE UPDATE ON circuitbreaker_rule FOR EACH ROW EXECUTE PROCEDURE polaris_update_mtime();
+
+CREATE TABLE circuitbreaker_rule_relation
+(
+    service_id   VARCHAR(32) NOT NULL,
+    rule_id      VARCHAR(97) NOT NULL,
+    rule_version VARCHAR(32) NOT NULL,
+    flag         SMALLINT NOT NULL DEFAULT 0,
+    ctime        TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    mtime        TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (service_id),
+    CONSTRAINT circuitbreaker_rule_relation_ibfk_1 FOREIGN KEY (service_id) REFERENCES service (id) ON DELETE CASCADE ON UPDATE CASCADE
+);
+CREATE INDEX circuitbreaker_rule_relation_mtime ON circuitbreaker_rule_relation (mtime);
+CREATE INDEX circuitbreaker_rule_relation_rule_id ON circuitbreaker_rule_relation (rule_id);
+CREATE TRIGGER circuitbreaker_rule_relation_mtime BEFORE UPDATE ON circuitbreaker_rule_relation FOR EACH ROW EXECUTE PROCEDURE polaris_update_mtime();
+
+CREATE TABLE t_ip_config
+(
+    fip     BIGINT NOT NULL,
+    fareaid BIGINT NOT NULL,
+    fcityid BIGINT NOT NULL,
+    fidcid  BIGINT NOT NULL,
+    fflag   SMALLINT DEFAULT 0,
+    fstamp  TIMESTAMP NOT NULL,
+    fflow   BIGINT NOT NULL,
+    PRIMARY KEY (fip)
+);
+CREATE INDEX t_ip_config_idx_fflow ON t_ip_config (fflow);
+
+CREATE TABLE t_policy
+(
+    fmodid BIGINT NOT NULL,
+    fdiv   BIGINT NOT NULL,
+    fmod   BIGINT NOT NULL,
+    fflag  SMALLINT DEFAULT 0,
+    fstamp TIMESTAMP NOT NULL,
+    fflow  BIGINT NOT NULL,
+    PRIMARY KEY (fmodid)
+);
+
+CREATE TABLE t_route
+(
+    fip    BIGINT NOT NULL,
+    fmodid BIGINT NOT NULL,
+    fcmdid BIGINT NOT NULL,
+    fsetid VARCHAR(32) NOT NULL,
+    fflag  SMALLINT DEFAULT 0,
+    fstamp TIMESTAMP NOT NULL,
+    fflow  BIGINT NOT NULL,
+    PRIMARY KEY (fip, fmodid, fcmdid)
+);
+CREATE INDEX t_route_fflow ON t_route (fflow);
+CREATE INDEX t_route_idx1 ON t_route (fmodid, fcmdid, fsetid);
+
+CREATE TABLE t_section
+(
+    fmodid BIGINT NOT NULL,
+    ffrom  BIGINT NOT NULL,
+    fto    BIGINT NOT NULL,
+    fxid   BIGINT NOT NULL,
+    fflag  SMALLINT DEFAULT 0,
+    fstamp TIMESTAMP NOT NULL,
+    fflow  BIGINT NOT NULL,
+    PRIMARY KEY (fmodid, ffrom, fto)
+);
+
+CREATE TABLE start_lock
+(
+    lock_id  INTEGER NOT NULL,
+    lock_key VARCHAR(32) NOT NULL,
+    server   VARCHAR(32) NOT NULL,
+    mtime    TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (lock_id, lock_key)
+);
+CREATE TRIGGER start_lock_mtime BEFORE UPDATE ON start_lock FOR EACH ROW EXECUTE PROCEDURE polaris_update_mtime();
+
+CREATE TABLE cl5_module
+(
+    module_id    INTEGER NOT NULL,
+    interface_id INTEGER NOT NULL,
+    range_num    INTEGER NOT NULL,
+    mtime        TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (module_id)
+);
+COMMENT ON TABLE cl5_module IS 'To generate SID';
+CREATE TRIGGER cl5_module_mtime BEFORE UPDATE ON cl5_module FOR EACH ROW EXECUTE PROCEDURE polaris_update_mtime();
+
+CREATE TABLE mesh
+(
+    id            VARCHAR(32) NOT NULL,
+    name          VARCHAR(128) NOT NULL,
+    department    VARCHAR(1024) DEFAULT NULL,
+    business      VARCHAR(128) NOT NULL,
+    managed       SMALLINT NOT NULL,
+    istio_version VARCHAR(64),
+    data_cluster  VARCHAR(1024),
+    revision      VARCHAR(32) NOT NULL,
+    comment       VARCHAR(1024) DEFAULT NULL,
+    token         VARCHAR(32) NOT NULL,
+    owner         VARCHAR(1024) NOT NULL,
+    flag          SMALLINT NOT NULL DEFAULT 0,
+    ctime         TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    mtime         TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (id)
+);
+CREATE INDEX mesh_name ON mesh (name);
+CREATE INDEX mesh_mtime ON mesh (mtime);
+CREATE TRIGGER mesh_mtime BEFORE UPDATE ON mesh FOR EACH ROW EXECUTE PROCEDURE polaris_update_mtime();
+
+CREATE TABLE mesh_service
+(
+    id             VARCHAR(32) NOT NULL,
+    mesh_id        VARCHAR(32) NOT NULL,
+    service_id     VARCHAR(32) NOT NULL,
+    namespace      VARCHAR(64) NOT NULL,
+    service        VARCHAR(128) NOT NULL,
+    mesh_namespace VARCHAR(64) NOT NULL,
+    mesh_service   VARCHAR(128) NOT NULL,
+    location       VARCHAR(16) NOT NULL,
+    export_to      VARCHAR(1024) NOT NULL,
+    revision       VARCHAR(32) NOT NULL,
+    flag           SMALLINT NOT NULL DEFAULT 0,
+    ctime          TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    mtime          TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (id),
+    UNIQUE (mesh_id, mesh_namespace, mesh_service)
+);
+CREATE INDEX mesh_service_namespace ON mesh_service (namespace);
+CREATE INDEX mesh_service_service ON mesh_service (service);
+CREATE INDEX mesh_service_location ON mesh_service (location);
+CREATE INDEX mesh_service_export_to ON mesh_service (export_to);
+CREATE INDEX mesh_service_mtime ON mesh_service (mtime);
+CREATE INDEX mesh_service_flag ON mesh_service (flag);
+CREATE TRIGGER mesh_service_mtime BEFORE UPDATE ON mesh_service FOR EACH ROW EXECUTE PROCEDURE polaris_update_mtime();
+
+CREATE TABLE mesh_service_revision
+(
+    mesh_id  VARCHAR(32) NOT NULL,
+    revision VARCHAR(32) NOT NULL,
+    ctime    TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    mtime    TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (mesh_id)
+);
+CREATE INDEX mesh_service_revision_mtime ON mesh_service_revision (mtime);
+CREATE TRIGGER mesh_service_revision_mtime BEFORE UPDATE ON mesh_service_revision FOR EACH ROW EXECUTE PROCEDURE polaris_update_mtime();
+
+CREATE TABLE mesh_resource
+(
+    id             VARCHAR(32) NOT NULL,
+    mesh_id        VARCHAR(32) NOT NULL,
+    name           VARCHAR(64) NOT NULL,
+    mesh_namespace VARCHAR(64) NOT NULL,
+    type_url       VARCHAR(96) NOT NULL,
+    revision       VARCHAR(32) NOT NULL,
+    body           TEXT,
+    flag           SMALLINT NOT NULL DEFAULT 0,
+    ctime          TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    mtime          TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (id),
+    UNIQUE (mesh_id, name, mesh_namespace, type_url)
+);
+CREATE INDEX mesh_resource_mtime ON mesh_resource (mtime);
+CREATE TRIGGER mesh_resource_mtime BEFORE UPDATE ON mesh_resource FOR EACH ROW EXECUTE PROCEDURE polaris_update_mtime();
+
+CREATE TABLE mesh_resource_revision
+(
+    mesh_id  VARCHAR(32) NOT NULL,
+    type_url VARCHAR(96) NOT NULL,
+    revision VARCHAR(32) NOT NULL,
+    ctime    TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    mtime    TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (mesh_id, type_url)
+);
+CREATE INDEX mesh_resource_revision_mtime ON mesh_resource_revision (mtime);
+CREATE TRIGGER mesh_resource_revision_mtime BEFORE UPDATE ON mesh_resource_revision FOR EACH ROW EXECUTE PROCEDURE polaris_update_mtime();
+
+CREATE TABLE config_file
+(
+    id          BIGSERIAL NOT NULL,
+    namespace   VARCHAR(64) NOT NULL,
+    "group"     VARCHAR(128) NOT NULL DEFAULT '',
+    name        VARCHAR(128) NOT NULL,
+    content     TEXT NOT NULL,
+    format      VARCHAR(16) DEFAULT 'text',
+    comment     VARCHAR(512) DEFAULT NULL,
+    flag        SMALLINT NOT NULL DEFAULT 0,
+    create_time TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    create_by   VARCHAR(32) DEFAULT NULL,
+    modify_time TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    modify_by   VARCHAR(32) DEFAULT NULL,
+    PRIMARY KEY (id),
+    UNIQUE (namespace, "group", name)
+);
+COMMENT ON TABLE config_file IS '配置文件表';
+CREATE TRIGGER config_file_modify_time BEFORE UPDATE ON config_file FOR EACH ROW EXECUTE PROCEDURE polaris_update_modify_time();
+
+CREATE TABLE config_file_group
+(
+    id          BIGSERIAL NOT NULL,
+    name        VARCHAR(128) NOT NULL,
+    namespace   VARCHAR(64) NOT NULL,
+    comment     VARCHAR(512) DEFAULT NULL,
+    owner       VARCHAR(1024) DEFAULT NULL,
+    json_schema TEXT DEFAULT NULL,
+    create_time TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    create_by   VARCHAR(32) DEFAULT NULL,
+    modify_time TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    modify_by   VARCHAR(32) DEFAULT NULL,
+    PRIMARY KEY (id),
+    UNIQUE (namespace, name)
+);
+COMMENT ON TABLE config_file_group IS '配置文件组表';
+CREATE TRIGGER config_file_group_modify_time BEFORE UPDATE ON config_file_group FOR EACH ROW EXECUTE PROCEDURE polaris_update_modify_time();
+
+CREATE TABLE config_file_release
+(
+    id          BIGSERIAL NOT NULL,
+    name        VARCHAR(128) DEFAULT NULL,
+    namespace   VARCHAR(64) NOT NULL,
+    "group"     VARCHAR(128) NOT NULL,
+    file_name   VARCHAR(128) NOT NULL,
+    content     TEXT NOT NULL,
+    comment     VARCHAR(512) DEFAULT NULL,
+    md5         VARCHAR(128) NOT NULL,
+    version     INTEGER NOT NULL,
+    flag        SMALLINT NOT NULL DEFAULT 0,
+    create_time TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    create_by   VARCHAR(32) DEFAULT NULL,
+    modify_time TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    modify_by   VARCHAR(32) DEFAULT NULL,
+    PRIMARY KEY (id),
+    UNIQUE (namespace, "group", file_name)
+);
+COMMENT ON TABLE config_file_release IS '配置文件发布表';
+CREATE INDEX config_file_release_idx_modify_time ON config_file_release (modify_time);
+CREATE TRIGGER config_file_release_modify_time BEFORE UPDATE ON config_file_release FOR EACH ROW EXECUTE PROCEDURE polaris_update_modify_time();
+
+CREATE TABLE config_file_gray_release
+(
+    id          BIGSERIAL NOT NULL,
+    name        VARCHAR(128) DEFAULT NULL,
+    namespace   VARCHAR(64) NOT NULL,
+    "group"     VARCHAR(128) NOT NULL,
+    file_name   VARCHAR(128) NOT NULL,
+    content     TEXT NOT NULL,
+    comment     VARCHAR(512) DEFAULT NULL,
+    md5         VARCHAR(128) NOT NULL,
+    version     INTEGER NOT NULL,
+    rule        TEXT NOT NULL,
+    flag        SMALLINT NOT NULL DEFAULT 0,
+    create_time TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    create_by   VARCHAR(32) DEFAULT NULL,
+    modify_time TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    modify_by   VARCHAR(32) DEFAULT NULL,
+    PRIMARY KEY (id),
+    UNIQUE (namespace, "group", file_name)
+);
+COMMENT ON TABLE config_file_gray_release IS '配置文件灰度发布表';
+CREATE INDEX config_file_gray_release_idx_modify_time ON config_file_gray_release (modify_time);
+CREATE TRIGGER config_file_gray_release_modify_time BEFORE UPDATE ON config_file_gray_release FOR EACH ROW EXECUTE PROCEDURE polaris_update_modify_time();
+
+CREATE TABLE config_file_release_history
+(
+    id          BIGSERIAL NOT NULL,
+    name        VARCHAR(64) DEFAULT '',
+    namespace   VARCHAR(64) NOT NULL,
+    "group"     VARCHAR(128) NOT NULL,
+    file_name   VARCHAR(128) NOT NULL,
+    content     TEXT NOT NULL,
+    format      VARCHAR(16) DEFAULT 'text',
+    tags        VARCHAR(2048) DEFAULT '',
+    comment     VARCHAR(512) DEFAULT NULL,
+    md5         VARCHAR(128) NOT NULL,
+    type        VARCHAR(32) NOT NULL,
+    status      VARCHAR(16) NOT NULL DEFAULT 'success',
+    create_time TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    create_by   VARCHAR(32) DEFAULT NULL,
+    modify_time TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    modify_by   VARCHAR(32) DEFAULT NULL,
+    PRIMARY KEY (id)
+);
+COMMENT ON TABLE config_file_release_history IS '配置文件发布历史表';
+CREATE INDEX config_file_release_history_idx_file ON config_file_release_history (namespace, "group", file_name);
+CREATE TRIGGER config_file_release_history_modify_time BEFORE UPDATE ON config_file_release_history FOR EACH ROW EXECUTE PROCEDURE polaris_update_modify_time();
+
+CREATE TABLE config_file_tag
+(
+    id          BIGSERIAL NOT NULL,
+    key         VARCHAR(128) NOT NULL,
+    value       VARCHAR(128) NOT NULL,
+    namespace   VARCHAR(64) NOT NULL,
+    "group"     VARCHAR(128) NOT NULL DEFAULT '',
+    file_name   VARCHAR(128) NOT NULL,
+    create_time TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    create_by   VARCHAR(32) DEFAULT NULL,
+    modify_time TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    modify_by   VARCHAR(32) DEFAULT NULL,
+    PRIMARY KEY (id),
+    UNIQUE (key, value, namespace, "group", file_name)
+);
+COMMENT ON TABLE config_file_tag IS '配置文件标签表';
+CREATE INDEX config_file_tag_idx_file ON config_file_tag (namespace, "group", file_name);
+CREATE TRIGGER config_file_tag_modify_time BEFORE UPDATE ON config_file_tag FOR EACH ROW EXECUTE PROCEDURE polaris_update_modify_time();
+
+CREATE TABLE "user"
+(
+    id           VARCHAR(128) NOT NULL,
+    name         VARCHAR(100) NOT NULL,
+    password     VARCHAR(100) NOT NULL,
+    owner        VARCHAR(128) NOT NULL,
+    source       VARCHAR(32) NOT NULL,
+    mobile       VARCHAR(12) NOT NULL DEFAULT '',
+    email        VARCHAR(64) NOT NULL DEFAULT '',
+    token        VARCHAR(255) NOT NULL,
+    token_enable SMALLINT NOT NULL DEFAULT 1,
+    user_type    INTEGER NOT NULL DEFAULT 20,
+    comment      VARCHAR(255) NOT NULL,
+    flag         SMALLINT NOT NULL DEFAULT 0,
+    ctime        TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    mtime        TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (id),
+    UNIQUE (name, owner)
+);
+CREATE INDEX user_owner ON "user" (owner);
+CREATE INDEX user_mtime ON "user" (mtime);
+CREATE TRIGGER user_mtime BEFORE UPDATE ON "user" FOR EACH ROW EXECUTE PROCEDURE polaris_update_mtime();
+
+CREATE TABLE user_group
+(
+    id           VARCHAR(128) NOT NULL,
+    name         VARCHAR(100) NOT NULL,
+    owner        VARCHAR(128) NOT NULL,
+    token        VARCHAR(255) NOT NULL,
+    comment      VARCHAR(255) NOT NULL,
+    token_enable SMALLINT NOT NULL DEFAULT 1,
+    flag         SMALLINT NOT NULL DEFAULT 0,
+    ctime        TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    mtime        TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (id),
+    UNIQUE (name, owner)
+);
+CREATE INDEX user_group_owner ON user_group (owner);
+CREATE INDEX user_group_mtime ON user_group (mtime);
+CREATE TRIGGER user_group_mtime BEFORE UPDATE ON user_group FOR EACH ROW EXECUTE PROCEDURE polaris_update_mtime();
+
+CREATE TABLE user_group_relation
+(
+    user_id  VARCHAR(128) NOT NULL,
+    group_id VARCHAR(128) NOT NULL,
+    ctime    TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    mtime    TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, group_id)
+);
+CREATE INDEX user_group_relation_mtime ON user_group_relation (mtime);
+CREATE TRIGGER user_group_relation_mtime BEFORE UPDATE ON user_group_relation FOR EACH ROW EXECUTE PROCEDURE polaris_update_mtime();
+
+CREATE TABLE auth_strategy
+(
+    id        VARCHAR(128) NOT NULL,
+    name      VARCHAR(100) NOT NULL,
+    action    VARCHAR(32) NOT NULL,
+    owner     VARCHAR(128) NOT NULL,
+    comment   VARCHAR(255) NOT NULL,
+    "default" SMALLINT NOT NULL DEFAULT 0,
+    revision  VARCHAR(128) NOT NULL,
+    flag      SMALLINT NOT NULL DEFAULT 0,
+    ctime     TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    mtime     TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (id),
+    UNIQUE (name, owner)
+);
+CREATE INDEX auth_strategy_owner ON auth_strategy (owner);
+CREATE INDEX auth_strategy_mtime ON auth_strategy (mtime);
+CREATE TRIGGER auth_strategy_mtime BEFORE UPDATE ON auth_strategy FOR EACH ROW EXECUTE PROCEDURE polaris_update_mtime();
+
+CREATE TABLE auth_principal
+(
+    strategy_id    VARCHAR(128) NOT NULL,
+    principal_id   VARCHAR(128) NOT NULL,
+    principal_role INTEGER NOT NULL,
+    PRIMARY KEY (strategy_id, principal_id, principal_role)
+);
+
+CREATE TABLE auth_strategy_resource
+(
+    strategy_id VARCHAR(128) NOT NULL,
+    res_type    INTEGER NOT NULL,
+    res_id      VARCHAR(128) NOT NULL,
+    ctime       TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    mtime       TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (strategy_id, res_type, res_id)
+);
+CREATE INDEX auth_strategy_resource_mtime ON auth_strategy_resource (mtime);
+CREATE TRIGGER auth_strategy_resource_mtime BEFORE UPDATE ON auth_strategy_resource FOR EACH ROW EXECUTE PROCEDURE polaris_update_mtime();
+
+CREATE TABLE client
+(
+    id      VARCHAR(128) NOT NULL,
+    host    VARCHAR(100) NOT NULL,
+    type    VARCHAR(100) NOT NULL,
+    version VARCHAR(32) NOT NULL,
+    region  VARCHAR(128) DEFAULT NULL,
+    zone    VARCHAR(128) DEFAULT NULL,
+    campus  VARCHAR(128) DEFAULT NULL,
+    flag    SMALLINT NOT NULL DEFAULT 0,
+    ctime   TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    mtime   TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (id)
+);
+CREATE INDEX client_mtime ON client (mtime);
+CREATE TRIGGER client_mtime BEFORE UPDATE ON client FOR EACH ROW EXECUTE PROCEDURE polaris_update_mtime();
+
+CREATE TABLE client_stat
+(
+    client_id VARCHAR(128) NOT NULL,
+    target    VARCHAR(100) NOT NULL,
+    port      INTEGER NOT NULL,
+    protocol  VARCHAR(100) NOT NULL,
+    path      VARCHAR(128) NOT NULL,
+    PRIMARY KEY (client_id, target, port)
+);
+
+CREATE TABLE config_file_template
+(
+    id          BIGSERIAL NOT NULL,
+    name        VARCHAR(128) NOT NULL,
+    content     TEXT NOT NULL,
+    format      VARCHAR(16) DEFAULT 'text',
+    comment     VARCHAR(512) DEFAULT NULL,
+    create_time TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    create_by   VARCHAR(32) DEFAULT NULL,
+    modify_time TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    modify_by   VARCHAR(32) DEFAULT NULL,
+    PRIMARY KEY (id),
+    UNIQUE (name)
+);
+COMMENT ON TABLE config_file_template IS '配置文件模板表';
+CREATE TRIGGER config_file_template_modify_time BEFORE UPDATE ON config_file_template FOR EACH ROW EXECUTE PROCEDURE polaris_update_modify_time();
+
+CREATE TABLE routing_config_v2
+(
+    id          VARCHAR(128) NOT NULL,
+    name        VARCHAR(64) NOT NULL DEFAULT '',
+    namespace   VARCHAR(64) NOT NULL DEFAULT '',
+    policy      VARCHAR(64) NOT NULL,
+    config      TEXT,
+    enable      INTEGER NOT NULL DEFAULT 0,
+    revision    VARCHAR(40) NOT NULL,
+    description VARCHAR(500) NOT NULL DEFAULT '',
+    priority    SMALLINT NOT NULL DEFAULT 0,
+    flag        SMALLINT NOT NULL DEFAULT 0,
+    ctime       TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    mtime       TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    etime       TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    extend_info VARCHAR(1024) DEFAULT '',
+    PRIMARY KEY (id)
+);
+CREATE INDEX routing_config_v2_mtime ON routing_config_v2 (mtime);
+CREATE TRIGGER routing_config_v2_mtime BEFORE UPDATE ON routing_config_v2 FOR EACH ROW EXECUTE PROCEDURE polaris_update_mtime();
+
+CREATE TABLE circuitbreaker_rule_v2
+(
+    id            VARCHAR(128) NOT NULL,
+    name          VARCHAR(128) NOT NULL,
+    namespace     VARCHAR(64) NOT NULL DEFAULT '',
+    enable        INTEGER NOT NULL DEFAULT 0,
+    level         VARCHAR(32) NOT NULL,
+    src_service   VARCHAR(128) NOT NULL,
+    src_namespace VARCHAR(64) NOT NULL,
+    dst_service   VARCHAR(128) NOT NULL,
+    dst_namespace VARCHAR(64) NOT NULL,
+    rule          TEXT,
+    revision      VARCHAR(40) NOT NULL,
+    description   VARCHAR(1024) NOT NULL DEFAULT '',
+    flag          SMALLINT NOT NULL DEFAULT 0,
+    ctime         TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    mtime         TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    etime         TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (id)
+);
+CREATE INDEX circuitbreaker_rule_v2_name ON circuitbreaker_rule_v2 (name);
+CREATE INDEX circuitbreaker_rule_v2_mtime ON circuitbreaker_rule_v2 (mtime);
+CREATE TRIGGER circuitbreaker_rule_v2_mtime BEFORE UPDATE ON circuitbreaker_rule_v2 FOR EACH ROW EXECUTE PROCEDURE polaris_update_mtime();
+
+CREATE TABLE fault_mirror_rule
+(
+    id          VARCHAR(128) NOT NULL,
+    name        VARCHAR(128) NOT NULL,
+    namespace   VARCHAR(64) NOT NULL DEFAULT '',
+    enable      INTEGER NOT NULL DEFAULT 0,
+    service     VARCHAR(128) NOT NULL,
+    rule        TEXT,
+    revision    VARCHAR(40) NOT NULL,
+    description VARCHAR(1024) NOT NULL DEFAULT '',
+    flag        SMALLINT NOT NULL DEFAULT 0,
+    ctime       TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    mtime       TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    etime       TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (id)
+);
+CREATE INDEX fault_mirror_rule_name ON fault_mirror_rule (name);
+CREATE INDEX fault_mirror_rule_mtime ON fault_mirror_rule (mtime);
+CREATE TRIGGER fault_mirror_rule_mtime BEFORE UPDATE ON fault_mirror_rule FOR EACH ROW EXECUTE PROCEDURE polaris_update_mtime();
+
+INSERT INTO namespace (name, comment, token, owner, flag, ctime, mtime)
+VALUES ('Polaris', 'Polaris-server', '2d1bfe5d12e04d54b8ee69e62494c7fd', 'polaris', 0,
+        '2019-09-06 07:55:07', '2019-09-06 07:55:07'),
+       ('default', 'Default Environment', 'e2e473081d3d4306b52264e49f7ce227', 'polaris', 0,
+        '2021-07-27 19:37:37', '2021-07-27 19:37:37');
+
+INSERT INTO service (id, name, namespace, comment, business, token, revision, owner, flag, ctime, mtime)
+VALUES ('fbca9bfa04ae4ead86e1ecf5811e32a9', 'polaris.checker', 'Polaris', 'polaris checker service', 'polaris',
+        '7d19c46de327408d8709ee7392b7700b', '301b1e9f0bbd47a6b697e26e99dfe012', 'polaris', 0,
+        '2021-09-06 07:55:07', '2021-09-06 07:55:09');
+
+INSERT INTO start_lock (lock_id, lock_key, server, mtime)
+VALUES (1, 'sz', 'aaa', '2019-12-05 08:35:49');
+
+INSERT INTO cl5_module (module_id, interface_id, range_num)
+VALUES (3000001, 1, 0);
+
+INSERT INTO "user" (id, name, password, source, token, token_enable, user_type, comment, mobile, email, owner)
+VALUES ('65e4789a6d5b49669adf1e9e8387549c', 'polaris',
+        '$2a$10$3izWuZtE5SBdAtSZci.gs.iZ2pAn9I8hEqYrC6gwJp1dyjqQnrrum', 'Polaris',
+        'nu/0WRA4EqSR1FagrjRj0fZwPXuGlMpX+zCuWu4uMqy8xr1vRjisSbA25aAC3mtU8MeeRsKhQiDAynUR09I=', 1, 20,
+        'default polaris admin account', '12345678910', '12345678910', '');
+
+INSERT INTO auth_strategy (id, name, action, owner, comment, "default", revision, flag, ctime, mtime)
+VALUES ('fbca9bfa04ae4ead86e1ecf5811e32a9', '(用户) polaris的默认策略', 'READ_WRITE',
+        '65e4789a6d5b49669adf1e9e8387549c', 'default admin', 1, 'fbca9bfa04ae4ead86e1ecf5811e32a9', 0,
+        CURRENT_TIMESTAMP, CURRENT_TIMESTAMP);
+
+INSERT INTO auth_strategy_resource (strategy_id, res_type, res_id, ctime, mtime)
+VALUES ('fbca9bfa04ae4ead86e1ecf5811e32a9', 0, '*', CURRENT_TIMESTAMP, CURRENT_TIMESTAMP),
+       ('fbca9bfa04ae4ead86e1ecf5811e32a9', 1, '*', CURRENT_TIMESTAMP, CURRENT_TIMESTAMP),
+       ('fbca9bfa04ae4ead86e1ecf5811e32a9', 2, '*', CURRENT_TIMESTAMP, CURRENT_TIMESTAMP);
+
+INSERT INTO auth_principal (strategy_id, principal_id, principal_role)
+VALUES ('fbca9bfa04ae4ead86e1ecf5811e32a9', '65e4789a6d5b49669adf1e9e8387549c', 1);
+
+INSERT INTO config_file_template (name, content, format, comment, create_time, create_by, modify_time, modify_by)
+VALUES ('spring-cloud-gateway-braining', '{
+    "rules":[
+        {
+            "conditions":[
+                {
+                    "key":"${http.query.uid}",
+                    "values":["10000"],
+                    "operation":"EQUALS"
+                }
+            ],
+            "labels":[
+                {
+                    "key":"env",
+                    "value":"green"
+                }
+            ]
+        }
+    ]
+}', 'json', 'Spring Cloud Gateway  染色规则', NOW(), 'polaris', NOW(), 'polaris');
+
+-- 实例健康状态变化记录
+CREATE TABLE instance_health_history
+(
+    id          BIGSERIAL NOT NULL,
+    instance_id VARCHAR(128) NOT NULL,
+    namespace   VARCHAR(64) NOT NULL,
+    service     VARCHAR(128) NOT NULL,
+    host        VARCHAR(128) NOT NULL,
+    port        INTEGER NOT NULL,
+    healthy     SMALLINT NOT NULL DEFAULT 0,
+    isolate     SMALLINT NOT NULL DEFAULT 0,
+    reason      VARCHAR(64) NOT NULL DEFAULT '',
+    checker     VARCHAR(128) NOT NULL DEFAULT '',
+    create_time TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (id)
+);
+COMMENT ON TABLE instance_health_history IS '实例健康状态变化记录表';
+CREATE INDEX instance_health_history_instance_id ON instance_health_history (instance_id);
+CREATE INDEX instance_health_history_service ON instance_health_history (namespace, service);
+CREATE INDEX instance_health_history_create_time ON instance_health_history (create_time);
+
+-- schema 版本，由 server 启动时自动维护，见 store/sqldb/migrations
+CREATE TABLE schema_version
+(
+    version     INT          NOT NULL,
+    description VARCHAR(128) NOT NULL DEFAULT '',
+    ctime       TIMESTAMP    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (version)
+);
+
+INSERT INTO schema_version (version, description)
+VALUES (1, 'baseline'),
+       (2, 'instance_health_history'),
+       (3, 'gray_release_circuitbreaker_v2_fault_mirror');
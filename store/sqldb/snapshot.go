@@ -0,0 +1,63 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package sqldb
+
+import (
+	"context"
+
+	"github.com/polarismesh/polaris/store"
+)
+
+// snapshotTables 全量备份读取的业务数据表
+var snapshotTables = []string{
+	"namespace", "service", "service_metadata", "owner_service_map", "instance", "instance_metadata",
+	"health_check", "routing_config", "routing_config_v2", "ratelimit_config", "ratelimit_revision",
+	"circuitbreaker_rule", "circuitbreaker_rule_relation", "circuitbreaker_rule_v2", "fault_mirror_rule",
+	"config_file_group", "config_file", "config_file_tag", "config_file_release", "config_file_release_history",
+	"config_file_template", "user", "user_group", "user_group_relation", "auth_strategy", "auth_principal",
+	"auth_strategy_resource",
+}
+
+// Snapshot 在独立的连接上锁定业务数据表，release 之前其他会话的写入都会等待，读取到的数据保持一致
+// 返回的视图只读取主库，避免从库同步延迟导致读取到不一致的数据
+func (s *stableStore) Snapshot() (store.Store, func(), error) {
+	ctx := context.Background()
+	conn, err := s.master.DB.Conn(ctx)
+	if err != nil {
+		return nil, nil, store.Error(err)
+	}
+	lock, unlock := s.dialect.lockTablesForRead(snapshotTables)
+	release := func() {
+		if _, err := conn.ExecContext(ctx, unlock); err != nil {
+			log.Errorf("[Store][database] unlock snapshot tables err: %s", err.Error())
+		}
+		_ = conn.Close()
+	}
+	for _, stmt := range lock {
+		if _, err := conn.ExecContext(ctx, stmt); err != nil {
+			log.Errorf("[Store][database] lock snapshot tables err: %s", err.Error())
+			release()
+			return nil, nil, store.Error(err)
+		}
+	}
+
+	view := &stableStore{master: s.master, masterTx: s.masterTx, slave: s.master, start: true,
+		name: s.name, dialect: s.dialect}
+	view.newStore()
+	return view, release, nil
+}
@@ -60,14 +60,15 @@ func Error(err error) error {
 		return err
 	}
 
+	// 同时兼容 MySQL 与 PostgreSQL 的错误信息
 	s := &StatusError{message: err.Error()}
-	if strings.Contains(s.message, "Data too long") {
+	if containsAny(s.message, "Data too long", "value too long") {
 		s.code = OutOfRangeErr
-	} else if strings.Contains(s.message, "Duplicate entry") {
+	} else if containsAny(s.message, "Duplicate entry", "duplicate key value violates unique constraint") {
 		s.code = DuplicateEntryErr
-	} else if strings.Contains(s.message, "a foreign key constraint fails") {
+	} else if containsAny(s.message, "a foreign key constraint fails", "violates foreign key constraint") {
 		s.code = ForeignKeyErr
-	} else if strings.Contains(s.message, "Deadlock") {
+	} else if containsAny(s.message, "Deadlock", "deadlock detected") {
 		s.code = DeadlockErr
 	} else {
 		s.code = Unknown
@@ -76,6 +77,15 @@ func Error(err error) error {
 	return s
 }
 
+func containsAny(message string, subs ...string) bool {
+	for _, sub := range subs {
+		if strings.Contains(message, sub) {
+			return true
+		}
+	}
+	return false
+}
+
 // NewStatusError 根据code和message创建StatusError
 func NewStatusError(code StatusCode, message string) error {
 	return &StatusError{
@@ -33,6 +33,8 @@ type Config struct {
 var (
 	// StoreSlots store slots
 	StoreSlots = make(map[string]Store)
+	// storeFactories 存储插件的构造函数，用于创建独立于 StoreSlots 的存储实例
+	storeFactories = make(map[string]func() Store)
 
 	once   = &sync.Once{}
 	config = &Config{}
@@ -49,6 +51,25 @@ func RegisterStore(s Store) error {
 	return nil
 }
 
+// RegisterStoreFactory 注册存储插件的构造函数
+func RegisterStoreFactory(name string, factory func() Store) {
+	storeFactories[name] = factory
+}
+
+// NewStore 根据配置创建并初始化一个独立的存储实例，用于离线工具同时打开多个存储，由调用方负责 Destroy
+func NewStore(conf *Config) (Store, error) {
+	factory, ok := storeFactories[conf.Name]
+	if !ok {
+		return nil, fmt.Errorf("store `%s` does not support creating standalone instance", conf.Name)
+	}
+
+	s := factory()
+	if err := s.Initialize(conf); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
 // GetStore 获取Store
 func GetStore() (Store, error) {
 	name := config.Name
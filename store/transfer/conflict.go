@@ -0,0 +1,510 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package transfer
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+
+	api "github.com/polarismesh/polaris/common/api/v1"
+	"github.com/polarismesh/polaris/common/model"
+	v2 "github.com/polarismesh/polaris/common/model/v2"
+	"github.com/polarismesh/polaris/store"
+)
+
+// ConflictPolicy 目标存储中已经存在与源记录不一致的记录时的处理策略
+type ConflictPolicy string
+
+const (
+	// ConflictSkip 保留目标存储中的记录
+	ConflictSkip ConflictPolicy = "skip"
+	// ConflictOverwrite 使用源记录覆盖目标存储中的记录
+	ConflictOverwrite ConflictPolicy = "overwrite"
+	// ConflictFail 存在冲突时不写入任何记录
+	ConflictFail ConflictPolicy = "fail"
+)
+
+// ErrConflict 冲突策略为 ConflictFail 时，目标存储中存在冲突的记录
+var ErrConflict = errors.New("records conflict with target store")
+
+// ParseConflictPolicy 解析冲突策略，为空时默认为 ConflictSkip
+func ParseConflictPolicy(value string) (ConflictPolicy, error) {
+	switch policy := ConflictPolicy(value); policy {
+	case "":
+		return ConflictSkip, nil
+	case ConflictSkip, ConflictOverwrite, ConflictFail:
+		return policy, nil
+	default:
+		return "", fmt.Errorf("invalid conflict policy %s, must be one of skip, overwrite, fail", value)
+	}
+}
+
+// conflictHandler 目标存储中已经存在的记录的冲突检查以及覆盖方法
+type conflictHandler struct {
+	// diff 读取目标存储中的记录，返回与源记录不一致的字段说明，一致时返回空
+	diff func() (string, error)
+	// overwrite 使用源记录覆盖目标存储中的记录，为 nil 时该类型的记录不支持覆盖
+	overwrite func() (*store.RecordMeta, error)
+}
+
+// resolveConflict 按照冲突策略处理目标存储中已经存在的记录，返回覆盖方法，返回 nil 时跳过该记录
+func (im *importer) resolveConflict(kr *KindReport, key string,
+	h *conflictHandler) func() (*store.RecordMeta, error) {
+	if h == nil {
+		kr.Skipped++
+		return nil
+	}
+	diff, err := h.diff()
+	if err != nil {
+		kr.Failed++
+		im.errorf("%s(%s) check conflict: %s", kr.Kind, key, err.Error())
+		return nil
+	}
+	if diff == "" {
+		kr.Skipped++
+		return nil
+	}
+	kr.Conflicted++
+	switch {
+	case im.opts.Conflict == ConflictFail:
+		kr.Skipped++
+		im.errorf("%s(%s) conflicts with target: %s", kr.Kind, key, diff)
+		return nil
+	case im.opts.Conflict != ConflictOverwrite:
+		kr.Skipped++
+		im.warnf("%s(%s) conflicts with target, skipped: %s", kr.Kind, key, diff)
+		return nil
+	case h.overwrite == nil:
+		kr.Skipped++
+		im.warnf("%s(%s) conflicts with target, skipped as not support overwrite: %s", kr.Kind, key, diff)
+		return nil
+	}
+	return h.overwrite
+}
+
+func (im *importer) namespaceConflict(item *model.Namespace) *conflictHandler {
+	return &conflictHandler{
+		diff: func() (string, error) {
+			ret, err := im.dst.GetNamespace(item.Name)
+			if err != nil || ret == nil {
+				return "", err
+			}
+			return diffNamespace(item, ret), nil
+		},
+		overwrite: func() (*store.RecordMeta, error) {
+			record := *item
+			if err := im.dst.UpdateNamespace(&record); err != nil {
+				return nil, err
+			}
+			if item.Token != "" {
+				if err := im.dst.UpdateNamespaceToken(item.Name, item.Token); err != nil {
+					return nil, err
+				}
+			}
+			return newMeta(item.Name, item.CreateTime, item.ModifyTime), nil
+		},
+	}
+}
+
+func (im *importer) serviceConflict(item *model.Service) *conflictHandler {
+	var existID string
+	return &conflictHandler{
+		diff: func() (string, error) {
+			ret, err := im.dst.GetService(item.Name, item.Namespace)
+			if err != nil || ret == nil {
+				return "", err
+			}
+			existID = ret.ID
+			return diffService(item, ret), nil
+		},
+		overwrite: func() (*store.RecordMeta, error) {
+			if existID != item.ID {
+				return nil, fmt.Errorf("service id in target is %s, can not overwrite", existID)
+			}
+			record := *item
+			var err error
+			if item.Reference != "" {
+				err = im.dst.UpdateServiceAlias(&record, item.Owner != "")
+			} else {
+				err = im.dst.UpdateService(&record, item.Owner != "")
+			}
+			if err != nil {
+				return nil, err
+			}
+			return newMeta(item.ID, item.CreateTime, item.ModifyTime), nil
+		},
+	}
+}
+
+func (im *importer) instanceConflict(item *model.Instance) *conflictHandler {
+	return &conflictHandler{
+		diff: func() (string, error) {
+			ret, err := im.dst.GetInstance(item.ID())
+			if err != nil || ret == nil {
+				return "", err
+			}
+			return diffInstance(item, ret), nil
+		},
+		overwrite: func() (*store.RecordMeta, error) {
+			record := *item
+			record.Proto = proto.Clone(item.Proto).(*api.Instance)
+			if err := im.dst.UpdateInstance(&record); err != nil {
+				return nil, err
+			}
+			return newMeta(item.ID(), parseProtoTime(item.Proto.GetCtime().GetValue()), item.ModifyTime), nil
+		},
+	}
+}
+
+func (im *importer) routingConfigConflict(item *model.RoutingConfig) *conflictHandler {
+	return &conflictHandler{
+		diff: func() (string, error) {
+			ret, err := im.dst.GetRoutingConfigWithID(item.ID)
+			if err != nil || ret == nil {
+				return "", err
+			}
+			return diffRoutingConfig(item, ret), nil
+		},
+		overwrite: func() (*store.RecordMeta, error) {
+			record := *item
+			if err := im.dst.UpdateRoutingConfig(&record); err != nil {
+				return nil, err
+			}
+			return newMeta(item.ID, item.CreateTime, item.ModifyTime), nil
+		},
+	}
+}
+
+func (im *importer) routingConfigV2Conflict(item *v2.RoutingConfig) *conflictHandler {
+	return &conflictHandler{
+		diff: func() (string, error) {
+			ret, err := im.dst.GetRoutingConfigV2WithID(item.ID)
+			if err != nil || ret == nil {
+				return "", err
+			}
+			return diffRoutingConfigV2(item, ret), nil
+		},
+		overwrite: func() (*store.RecordMeta, error) {
+			record := *item
+			if err := im.dst.UpdateRoutingConfigV2(&record); err != nil {
+				return nil, err
+			}
+			if err := im.dst.EnableRouting(&record); err != nil {
+				return nil, err
+			}
+			return newMeta(item.ID, item.CreateTime, item.ModifyTime), nil
+		},
+	}
+}
+
+func (im *importer) rateLimitConflict(item *model.RateLimit) *conflictHandler {
+	return &conflictHandler{
+		diff: func() (string, error) {
+			ret, err := im.dst.GetRateLimitWithID(item.ID)
+			if err != nil || ret == nil {
+				return "", err
+			}
+			return diffRateLimit(item, ret), nil
+		},
+		overwrite: func() (*store.RecordMeta, error) {
+			record := *item
+			if err := im.dst.UpdateRateLimit(&record); err != nil {
+				return nil, err
+			}
+			if err := im.dst.EnableRateLimit(&record); err != nil {
+				return nil, err
+			}
+			return newMeta(item.ID, item.CreateTime, item.ModifyTime), nil
+		},
+	}
+}
+
+func (im *importer) circuitBreakerConflict(item *model.CircuitBreaker) *conflictHandler {
+	return &conflictHandler{
+		diff: func() (string, error) {
+			ret, err := im.dst.GetCircuitBreaker(item.ID, item.Version)
+			if err != nil || ret == nil {
+				return "", err
+			}
+			return diffCircuitBreaker(item, ret), nil
+		},
+		overwrite: func() (*store.RecordMeta, error) {
+			record := *item
+			if err := im.dst.UpdateCircuitBreaker(&record); err != nil {
+				return nil, err
+			}
+			meta := newMeta(item.ID, item.CreateTime, item.ModifyTime)
+			meta.Version = item.Version
+			return meta, nil
+		},
+	}
+}
+
+func (im *importer) circuitBreakerV2Conflict(item *v2.CircuitBreakerRule) *conflictHandler {
+	return &conflictHandler{
+		diff: func() (string, error) {
+			ret, err := im.dst.GetCircuitBreakerRuleV2WithID(item.ID)
+			if err != nil || ret == nil {
+				return "", err
+			}
+			return diffCircuitBreakerV2(item, ret), nil
+		},
+		overwrite: func() (*store.RecordMeta, error) {
+			record := *item
+			if err := im.dst.UpdateCircuitBreakerRuleV2(&record); err != nil {
+				return nil, err
+			}
+			if err := im.dst.EnableCircuitBreakerRuleV2(&record); err != nil {
+				return nil, err
+			}
+			return newMeta(item.ID, item.CreateTime, item.ModifyTime), nil
+		},
+	}
+}
+
+func (im *importer) faultMirrorRuleConflict(item *v2.FaultMirrorRule) *conflictHandler {
+	return &conflictHandler{
+		diff: func() (string, error) {
+			ret, err := im.dst.GetFaultMirrorRuleWithID(item.ID)
+			if err != nil || ret == nil {
+				return "", err
+			}
+			return diffFaultMirrorRule(item, ret), nil
+		},
+		overwrite: func() (*store.RecordMeta, error) {
+			record := *item
+			if err := im.dst.UpdateFaultMirrorRule(&record); err != nil {
+				return nil, err
+			}
+			if err := im.dst.EnableFaultMirrorRule(&record); err != nil {
+				return nil, err
+			}
+			return newMeta(item.ID, item.CreateTime, item.ModifyTime), nil
+		},
+	}
+}
+
+// configFileGroupConflict 覆盖配置分组时保留目标存储中的自增ID
+func (im *importer) configFileGroupConflict(item *model.ConfigFileGroup) *conflictHandler {
+	var existID uint64
+	return &conflictHandler{
+		diff: func() (string, error) {
+			ret, err := im.dst.GetConfigFileGroup(item.Namespace, item.Name)
+			if err != nil || ret == nil {
+				return "", err
+			}
+			existID = ret.Id
+			return diffConfigFileGroup(item, ret), nil
+		},
+		overwrite: func() (*store.RecordMeta, error) {
+			record := *item
+			if _, err := im.dst.UpdateConfigFileGroup(&record); err != nil {
+				return nil, err
+			}
+			// 更新配置分组时不会写入 schema，需要单独更新
+			if err := im.dst.UpdateConfigFileGroupSchema(item.Namespace, item.Name, item.Schema,
+				item.ModifyBy); err != nil {
+				return nil, err
+			}
+			return newConfigMeta(existID, 0, item.CreateTime, item.ModifyTime), nil
+		},
+	}
+}
+
+func (im *importer) configFileConflict(item *model.ConfigFile) *conflictHandler {
+	return &conflictHandler{
+		diff: func() (string, error) {
+			ret, err := im.dst.GetConfigFile(nil, item.Namespace, item.Group, item.Name)
+			if err != nil || ret == nil {
+				return "", err
+			}
+			return diffConfigFile(item, ret), nil
+		},
+		overwrite: func() (*store.RecordMeta, error) {
+			record := *item
+			updated, err := im.dst.UpdateConfigFile(nil, &record)
+			if err != nil {
+				return nil, err
+			}
+			return newConfigMeta(updated.Id, 0, item.CreateTime, item.ModifyTime), nil
+		},
+	}
+}
+
+func (im *importer) configFileReleaseConflict(item *model.ConfigFileRelease) *conflictHandler {
+	return &conflictHandler{
+		diff: func() (string, error) {
+			ret, err := im.dst.GetConfigFileReleaseWithAllFlag(nil, item.Namespace, item.Group, item.FileName)
+			if err != nil || ret == nil {
+				return "", err
+			}
+			return diffConfigFileRelease(item, ret), nil
+		},
+		overwrite: func() (*store.RecordMeta, error) {
+			record := *item
+			updated, err := im.dst.UpdateConfigFileRelease(nil, &record)
+			if err != nil {
+				return nil, err
+			}
+			if updated == nil {
+				return nil, errors.New("release not found after written")
+			}
+			return newConfigMeta(updated.Id, 0, item.CreateTime, item.ModifyTime), nil
+		},
+	}
+}
+
+// configFileTemplateConflict 存储层没有更新配置模板的方法，冲突时只能跳过
+func (im *importer) configFileTemplateConflict(item *model.ConfigFileTemplate) *conflictHandler {
+	return &conflictHandler{
+		diff: func() (string, error) {
+			ret, err := im.dst.GetConfigFileTemplate(item.Name)
+			if err != nil || ret == nil {
+				return "", err
+			}
+			return diffConfigFileTemplate(item, ret), nil
+		},
+	}
+}
+
+func (im *importer) userConflict(item *model.User) *conflictHandler {
+	return &conflictHandler{
+		diff: func() (string, error) {
+			ret, err := im.dst.GetUser(item.ID)
+			if err != nil || ret == nil {
+				return "", err
+			}
+			return diffUser(item, ret), nil
+		},
+		overwrite: func() (*store.RecordMeta, error) {
+			record := *item
+			if err := im.dst.UpdateUser(&record); err != nil {
+				return nil, err
+			}
+			return newMeta(item.ID, item.CreateTime, item.ModifyTime), nil
+		},
+	}
+}
+
+// userGroupConflict 覆盖用户组时按照目标存储中的成员计算需要增删的用户
+func (im *importer) userGroupConflict(item *model.UserGroupDetail) *conflictHandler {
+	var exist *model.UserGroupDetail
+	return &conflictHandler{
+		diff: func() (string, error) {
+			ret, err := im.dst.GetGroup(item.ID)
+			if err != nil || ret == nil || ret.UserGroup == nil {
+				return "", err
+			}
+			exist = ret
+			return diffUserGroup(item, ret), nil
+		},
+		overwrite: func() (*store.RecordMeta, error) {
+			modify := &model.ModifyUserGroup{
+				ID:          item.ID,
+				Owner:       item.Owner,
+				Token:       item.Token,
+				TokenEnable: item.TokenEnable,
+				Comment:     item.Comment,
+			}
+			for id := range item.UserIds {
+				if _, ok := exist.UserIds[id]; !ok {
+					modify.AddUserIds = append(modify.AddUserIds, id)
+				}
+			}
+			for id := range exist.UserIds {
+				if _, ok := item.UserIds[id]; !ok {
+					modify.RemoveUserIds = append(modify.RemoveUserIds, id)
+				}
+			}
+			if err := im.dst.UpdateGroup(modify); err != nil {
+				return nil, err
+			}
+			return newMeta(item.ID, item.CreateTime, item.ModifyTime), nil
+		},
+	}
+}
+
+func (im *importer) strategyConflict(item *model.StrategyDetail) *conflictHandler {
+	var exist *model.StrategyDetail
+	return &conflictHandler{
+		diff: func() (string, error) {
+			ret, err := im.dst.GetStrategyDetail(item.ID)
+			if err != nil || ret == nil {
+				return "", err
+			}
+			exist = ret
+			return diffStrategy(item, ret), nil
+		},
+		overwrite: func() (*store.RecordMeta, error) {
+			if err := im.dst.UpdateStrategy(buildModifyStrategy(item, exist)); err != nil {
+				return nil, err
+			}
+			return newMeta(item.ID, item.CreateTime, item.ModifyTime), nil
+		},
+	}
+}
+
+// buildModifyStrategy 计算将目标存储中的鉴权策略更新为源策略需要增删的成员以及资源
+func buildModifyStrategy(src, dst *model.StrategyDetail) *model.ModifyStrategyDetail {
+	modify := &model.ModifyStrategyDetail{
+		ID:         src.ID,
+		Name:       src.Name,
+		Action:     src.Action,
+		Comment:    src.Comment,
+		ModifyTime: time.Now(),
+	}
+
+	srcPrincipals := principalKeys(src.Principals)
+	dstPrincipals := principalKeys(dst.Principals)
+	for i, key := range srcPrincipals {
+		if !containsKey(dstPrincipals, key) {
+			modify.AddPrincipals = append(modify.AddPrincipals, src.Principals[i])
+		}
+	}
+	for i, key := range dstPrincipals {
+		if !containsKey(srcPrincipals, key) {
+			modify.RemovePrincipals = append(modify.RemovePrincipals, dst.Principals[i])
+		}
+	}
+
+	srcResources := resourceKeys(src.Resources)
+	dstResources := resourceKeys(dst.Resources)
+	for i, key := range srcResources {
+		if !containsKey(dstResources, key) {
+			modify.AddResources = append(modify.AddResources, src.Resources[i])
+		}
+	}
+	for i, key := range dstResources {
+		if !containsKey(srcResources, key) {
+			modify.RemoveResources = append(modify.RemoveResources, dst.Resources[i])
+		}
+	}
+	return modify
+}
+
+func containsKey(keys []string, key string) bool {
+	for _, item := range keys {
+		if item == key {
+			return true
+		}
+	}
+	return false
+}
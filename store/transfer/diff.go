@@ -0,0 +1,152 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package transfer
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/polarismesh/polaris/common/model"
+	v2 "github.com/polarismesh/polaris/common/model/v2"
+)
+
+// diffFields 依次比较源记录以及目标记录的字段，返回第一个不一致的字段说明
+func diffFields(pairs ...interface{}) string {
+	for i := 0; i+2 < len(pairs); i += 3 {
+		if pairs[i+1] != pairs[i+2] {
+			return fmt.Sprintf("%v expect %v but %v", pairs[i], pairs[i+1], pairs[i+2])
+		}
+	}
+	return ""
+}
+
+// joinDiff 合并多个字段的不一致说明
+func joinDiff(diffs ...string) string {
+	var out []string
+	for _, diff := range diffs {
+		if diff != "" {
+			out = append(out, diff)
+		}
+	}
+	return strings.Join(out, "; ")
+}
+
+// diffSet 比较两个无序集合
+func diffSet(name string, src, dst []string) string {
+	sort.Strings(src)
+	sort.Strings(dst)
+	return diffFields(name, strings.Join(src, ","), strings.Join(dst, ","))
+}
+
+// 以下各个方法比较源记录与目标存储中的同一条记录，返回不一致的字段说明，一致时返回空字符串
+
+func diffNamespace(src, dst *model.Namespace) string {
+	return diffFields("token", src.Token, dst.Token, "owner", src.Owner, dst.Owner)
+}
+
+func diffService(src, dst *model.Service) string {
+	return diffFields("id", src.ID, dst.ID, "revision", src.Revision, dst.Revision, "token", src.Token, dst.Token,
+		"reference", src.Reference, dst.Reference)
+}
+
+func diffInstance(src, dst *model.Instance) string {
+	return diffFields("service", src.ServiceID, dst.ServiceID, "host", src.Host(), dst.Host(),
+		"port", src.Port(), dst.Port(), "revision", src.Revision(), dst.Revision())
+}
+
+func diffRoutingConfig(src, dst *model.RoutingConfig) string {
+	return diffFields("revision", src.Revision, dst.Revision)
+}
+
+func diffRoutingConfigV2(src, dst *v2.RoutingConfig) string {
+	return diffFields("revision", src.Revision, dst.Revision, "enable", src.Enable, dst.Enable)
+}
+
+func diffRateLimit(src, dst *model.RateLimit) string {
+	return diffFields("revision", src.Revision, dst.Revision, "disable", src.Disable, dst.Disable)
+}
+
+func diffCircuitBreaker(src, dst *model.CircuitBreaker) string {
+	return diffFields("revision", src.Revision, dst.Revision, "token", src.Token, dst.Token)
+}
+
+func diffCircuitBreakerV2(src, dst *v2.CircuitBreakerRule) string {
+	return diffFields("revision", src.Revision, dst.Revision, "enable", src.Enable, dst.Enable)
+}
+
+func diffFaultMirrorRule(src, dst *v2.FaultMirrorRule) string {
+	return diffFields("revision", src.Revision, dst.Revision, "enable", src.Enable, dst.Enable)
+}
+
+func diffConfigFileGroup(src, dst *model.ConfigFileGroup) string {
+	return diffFields("schema", src.Schema, dst.Schema, "comment", src.Comment, dst.Comment)
+}
+
+func diffConfigFile(src, dst *model.ConfigFile) string {
+	return diffFields("content", src.Content, dst.Content, "format", src.Format, dst.Format,
+		"comment", src.Comment, dst.Comment)
+}
+
+func diffConfigFileRelease(src, dst *model.ConfigFileRelease) string {
+	return diffFields("md5", src.Md5, dst.Md5, "version", src.Version, dst.Version)
+}
+
+func diffConfigFileTemplate(src, dst *model.ConfigFileTemplate) string {
+	return diffFields("content", src.Content, dst.Content, "format", src.Format, dst.Format)
+}
+
+func diffUser(src, dst *model.User) string {
+	return diffFields("name", src.Name, dst.Name, "password", src.Password, dst.Password,
+		"token", src.Token, dst.Token, "token enable", src.TokenEnable, dst.TokenEnable)
+}
+
+func diffUserGroup(src, dst *model.UserGroupDetail) string {
+	return joinDiff(diffFields("name", src.Name, dst.Name, "token", src.Token, dst.Token,
+		"token enable", src.TokenEnable, dst.TokenEnable), diffSet("users", groupUserIDs(src), groupUserIDs(dst)))
+}
+
+func groupUserIDs(group *model.UserGroupDetail) []string {
+	ids := make([]string, 0, len(group.UserIds))
+	for id := range group.UserIds {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+func diffStrategy(src, dst *model.StrategyDetail) string {
+	return joinDiff(diffFields("name", src.Name, dst.Name, "action", src.Action, dst.Action),
+		diffSet("principals", principalKeys(src.Principals), principalKeys(dst.Principals)),
+		diffSet("resources", resourceKeys(src.Resources), resourceKeys(dst.Resources)))
+}
+
+func principalKeys(principals []model.Principal) []string {
+	keys := make([]string, 0, len(principals))
+	for _, principal := range principals {
+		keys = append(keys, fmt.Sprintf("%d/%s", principal.PrincipalRole, principal.PrincipalID))
+	}
+	return keys
+}
+
+func resourceKeys(resources []model.StrategyResource) []string {
+	keys := make([]string, 0, len(resources))
+	for _, resource := range resources {
+		keys = append(keys, fmt.Sprintf("%d/%s", resource.ResType, resource.ResID))
+	}
+	return keys
+}
@@ -0,0 +1,330 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package transfer
+
+import (
+	"sort"
+	"time"
+
+	"github.com/polarismesh/polaris/common/model"
+	"github.com/polarismesh/polaris/store"
+)
+
+// exportPageSize 分页导出时每页的记录数
+const exportPageSize = 100
+
+// Export 导出存储中全部有效的业务数据
+func Export(s store.Store) (*Snapshot, error) {
+	snapshot := &Snapshot{}
+	steps := []func(store.Store, *Snapshot) error{
+		exportNaming,
+		exportRules,
+		exportCircuitBreakers,
+		exportConfigFiles,
+		exportConfigFileReleases,
+		exportAuth,
+	}
+	for _, step := range steps {
+		if err := step(s, snapshot); err != nil {
+			return nil, err
+		}
+	}
+	return snapshot, nil
+}
+
+// exportNaming 导出命名空间、服务以及实例
+func exportNaming(s store.Store, snapshot *Snapshot) error {
+	namespaces, err := s.GetMoreNamespaces(time.Time{})
+	if err != nil {
+		return err
+	}
+	for _, item := range namespaces {
+		if item.Valid {
+			snapshot.Namespaces = append(snapshot.Namespaces, item)
+		}
+	}
+	sort.Slice(snapshot.Namespaces, func(i, j int) bool {
+		return snapshot.Namespaces[i].Name < snapshot.Namespaces[j].Name
+	})
+
+	services, err := s.GetMoreServices(time.Time{}, true, false, true)
+	if err != nil {
+		return err
+	}
+	for _, item := range services {
+		if item.Valid {
+			snapshot.Services = append(snapshot.Services, item)
+		}
+	}
+	// 别名依赖源服务，需要排在源服务之后
+	sort.Slice(snapshot.Services, func(i, j int) bool {
+		left, right := snapshot.Services[i], snapshot.Services[j]
+		if (left.Reference == "") != (right.Reference == "") {
+			return left.Reference == ""
+		}
+		return left.ID < right.ID
+	})
+
+	instances, err := s.GetMoreInstances(time.Time{}, true, true, nil)
+	if err != nil {
+		return err
+	}
+	for _, item := range instances {
+		if item.Valid {
+			snapshot.Instances = append(snapshot.Instances, item)
+		}
+	}
+	sort.Slice(snapshot.Instances, func(i, j int) bool {
+		return snapshot.Instances[i].ID() < snapshot.Instances[j].ID()
+	})
+	return nil
+}
+
+// exportRules 导出路由、限流、熔断 v2 以及故障注入和流量镜像规则
+func exportRules(s store.Store, snapshot *Snapshot) error {
+	routings, err := s.GetRoutingConfigsForCache(time.Time{}, true)
+	if err != nil {
+		return err
+	}
+	for _, item := range routings {
+		if item.Valid {
+			snapshot.RoutingConfigs = append(snapshot.RoutingConfigs, item)
+		}
+	}
+
+	routingsV2, err := s.GetRoutingConfigsV2ForCache(time.Time{}, true)
+	if err != nil {
+		return err
+	}
+	for _, item := range routingsV2 {
+		if item.Valid {
+			snapshot.RoutingConfigsV2 = append(snapshot.RoutingConfigsV2, item)
+		}
+	}
+
+	rateLimits, _, err := s.GetRateLimitsForCache(time.Time{}, true)
+	if err != nil {
+		return err
+	}
+	for _, item := range rateLimits {
+		if item.Valid {
+			snapshot.RateLimits = append(snapshot.RateLimits, item)
+		}
+	}
+
+	circuitBreakers, err := s.GetCircuitBreakerRulesV2ForCache(time.Time{}, true)
+	if err != nil {
+		return err
+	}
+	for _, item := range circuitBreakers {
+		if item.Valid {
+			snapshot.CircuitBreakersV2 = append(snapshot.CircuitBreakersV2, item)
+		}
+	}
+
+	faultMirrorRules, err := s.GetFaultMirrorRulesForCache(time.Time{}, true)
+	if err != nil {
+		return err
+	}
+	for _, item := range faultMirrorRules {
+		if item.Valid {
+			snapshot.FaultMirrorRules = append(snapshot.FaultMirrorRules, item)
+		}
+	}
+	return nil
+}
+
+// exportCircuitBreakers 导出熔断规则 v1 的各个版本以及与服务的绑定关系
+func exportCircuitBreakers(s store.Store, snapshot *Snapshot) error {
+	var ids []string
+	for offset := uint32(0); ; offset += exportPageSize {
+		detail, err := s.ListMasterCircuitBreakers(map[string]string{}, offset, exportPageSize)
+		if err != nil {
+			return err
+		}
+		for _, info := range detail.CircuitBreakerInfos {
+			ids = append(ids, info.CircuitBreaker.ID)
+		}
+		if len(detail.CircuitBreakerInfos) < exportPageSize {
+			break
+		}
+	}
+	sort.Strings(ids)
+
+	// master 规则需要先于其他版本写入
+	var masters, versions []*model.CircuitBreaker
+	for _, id := range ids {
+		tags, err := s.GetCircuitBreakerVersions(id)
+		if err != nil {
+			return err
+		}
+		for _, tag := range tags {
+			rule, err := s.GetCircuitBreaker(id, tag)
+			if err != nil {
+				return err
+			}
+			if rule == nil {
+				continue
+			}
+			if tag == masterCircuitBreakerVersion {
+				masters = append(masters, rule)
+			} else {
+				versions = append(versions, rule)
+			}
+			relations, err := s.GetCircuitBreakerRelation(id, tag)
+			if err != nil {
+				return err
+			}
+			for _, relation := range relations {
+				if relation.Valid {
+					snapshot.CircuitBreakerRelations = append(snapshot.CircuitBreakerRelations, relation)
+				}
+			}
+		}
+	}
+	snapshot.CircuitBreakers = append(masters, versions...)
+	return nil
+}
+
+// exportConfigFiles 导出配置分组、配置文件以及配置文件的标签
+func exportConfigFiles(s store.Store, snapshot *Snapshot) error {
+	for offset := uint32(0); ; offset += exportPageSize {
+		_, groups, err := s.QueryConfigFileGroups("", "", offset, exportPageSize)
+		if err != nil {
+			return err
+		}
+		snapshot.ConfigFileGroups = append(snapshot.ConfigFileGroups, groups...)
+		if len(groups) < exportPageSize {
+			break
+		}
+	}
+	sort.Slice(snapshot.ConfigFileGroups, func(i, j int) bool {
+		return snapshot.ConfigFileGroups[i].Id < snapshot.ConfigFileGroups[j].Id
+	})
+
+	for offset := uint32(0); ; offset += exportPageSize {
+		_, files, err := s.QueryConfigFiles("", "", "", offset, exportPageSize)
+		if err != nil {
+			return err
+		}
+		snapshot.ConfigFiles = append(snapshot.ConfigFiles, files...)
+		if len(files) < exportPageSize {
+			break
+		}
+	}
+	sort.Slice(snapshot.ConfigFiles, func(i, j int) bool {
+		return snapshot.ConfigFiles[i].Id < snapshot.ConfigFiles[j].Id
+	})
+
+	for _, file := range snapshot.ConfigFiles {
+		tags, err := s.QueryTagByConfigFile(file.Namespace, file.Group, file.Name)
+		if err != nil {
+			return err
+		}
+		snapshot.ConfigFileTags = append(snapshot.ConfigFileTags, tags...)
+	}
+
+	templates, err := s.QueryAllConfigFileTemplates()
+	if err != nil {
+		return err
+	}
+	snapshot.ConfigFileTemplates = templates
+	return nil
+}
+
+// exportConfigFileReleases 导出配置发布以及发布历史
+func exportConfigFileReleases(s store.Store, snapshot *Snapshot) error {
+	releases, err := s.FindConfigFileReleaseByModifyTimeAfter(time.Time{})
+	if err != nil {
+		return err
+	}
+	for _, item := range releases {
+		if item.Flag == 0 {
+			snapshot.ConfigFileReleases = append(snapshot.ConfigFileReleases, item)
+		}
+	}
+	sort.Slice(snapshot.ConfigFileReleases, func(i, j int) bool {
+		return snapshot.ConfigFileReleases[i].Id < snapshot.ConfigFileReleases[j].Id
+	})
+
+	// 发布历史按照ID倒序分页查询
+	var endID uint64
+	for {
+		_, histories, err := s.QueryConfigFileReleaseHistories("", "", "", 0, exportPageSize, endID)
+		if err != nil {
+			return err
+		}
+		snapshot.ConfigFileReleaseHistories = append(snapshot.ConfigFileReleaseHistories, histories...)
+		if len(histories) < exportPageSize {
+			break
+		}
+		endID = histories[len(histories)-1].Id
+	}
+	sort.Slice(snapshot.ConfigFileReleaseHistories, func(i, j int) bool {
+		return snapshot.ConfigFileReleaseHistories[i].Id < snapshot.ConfigFileReleaseHistories[j].Id
+	})
+	return nil
+}
+
+// exportAuth 导出用户、用户组以及鉴权策略
+func exportAuth(s store.Store, snapshot *Snapshot) error {
+	users, err := s.GetUsersForCache(time.Time{}, true)
+	if err != nil {
+		return err
+	}
+	for _, item := range users {
+		if item.Valid {
+			snapshot.Users = append(snapshot.Users, item)
+		}
+	}
+	// 子账号依赖主账号
+	sort.Slice(snapshot.Users, func(i, j int) bool {
+		left, right := snapshot.Users[i], snapshot.Users[j]
+		if (left.Owner == "") != (right.Owner == "") {
+			return left.Owner == ""
+		}
+		return left.ID < right.ID
+	})
+
+	groups, err := s.GetGroupsForCache(time.Time{}, true)
+	if err != nil {
+		return err
+	}
+	for _, item := range groups {
+		if item.UserGroup != nil && item.Valid {
+			snapshot.UserGroups = append(snapshot.UserGroups, item)
+		}
+	}
+	sort.Slice(snapshot.UserGroups, func(i, j int) bool {
+		return snapshot.UserGroups[i].ID < snapshot.UserGroups[j].ID
+	})
+
+	strategies, err := s.GetStrategyDetailsForCache(time.Time{}, true)
+	if err != nil {
+		return err
+	}
+	for _, item := range strategies {
+		if item.Valid {
+			snapshot.Strategies = append(snapshot.Strategies, item)
+		}
+	}
+	sort.Slice(snapshot.Strategies, func(i, j int) bool {
+		return snapshot.Strategies[i].ID < snapshot.Strategies[j].ID
+	})
+	return nil
+}
@@ -0,0 +1,626 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package transfer
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+
+	api "github.com/polarismesh/polaris/common/api/v1"
+	"github.com/polarismesh/polaris/common/model"
+	v2 "github.com/polarismesh/polaris/common/model/v2"
+	"github.com/polarismesh/polaris/store"
+)
+
+// ImportOptions 导入数据的选项
+type ImportOptions struct {
+	// DryRun 只统计将要写入的记录，不实际写入目标存储
+	DryRun bool
+	// Conflict 冲突处理策略，为空时为 ConflictSkip
+	Conflict ConflictPolicy
+	// RestoreMetas 还原记录原有的时间戳以及配置中心记录的自增ID
+	// 缓存根据修改时间增量加载数据，只能在服务停止时还原
+	RestoreMetas bool
+}
+
+// importer 将快照中的数据逐条写入目标存储
+type importer struct {
+	dst    store.Store
+	opts   *ImportOptions
+	report *Report
+	// kinds 按照写入顺序记录需要还原元信息的记录类型
+	kinds []store.RecordKind
+	metas map[store.RecordKind][]*store.RecordMeta
+}
+
+// Import 将快照中的数据导入目标存储，目标存储中已经存在的记录按照冲突策略处理
+// 单条记录写入失败不会中断导入，失败信息记录在返回的 Report 中
+// 冲突策略为 ConflictFail 时先检查冲突，存在冲突时返回 ErrConflict，不写入任何记录
+func Import(dst store.Store, snapshot *Snapshot, opts *ImportOptions) (*Report, error) {
+	if opts == nil {
+		opts = &ImportOptions{}
+	}
+	if _, err := ParseConflictPolicy(string(opts.Conflict)); err != nil {
+		return nil, err
+	}
+
+	if opts.Conflict == ConflictFail {
+		report := newImporter(dst, &ImportOptions{DryRun: true, Conflict: ConflictFail}).run(snapshot)
+		report.DryRun = opts.DryRun
+		if report.Conflicts() > 0 {
+			return report, ErrConflict
+		}
+		if opts.DryRun {
+			return report, nil
+		}
+	}
+	return newImporter(dst, opts).run(snapshot), nil
+}
+
+func newImporter(dst store.Store, opts *ImportOptions) *importer {
+	return &importer{
+		dst:    dst,
+		opts:   opts,
+		report: &Report{DryRun: opts.DryRun},
+		metas:  make(map[store.RecordKind][]*store.RecordMeta),
+	}
+}
+
+func (im *importer) run(snapshot *Snapshot) *Report {
+	im.importNamespaces(snapshot.Namespaces)
+	im.importServices(snapshot.Services)
+	im.importInstances(snapshot.Instances)
+	im.importRoutingConfigs(snapshot.RoutingConfigs)
+	im.importRoutingConfigsV2(snapshot.RoutingConfigsV2)
+	im.importRateLimits(snapshot.RateLimits)
+	im.importCircuitBreakers(snapshot.CircuitBreakers)
+	im.importCircuitBreakerRelations(snapshot.CircuitBreakerRelations)
+	im.importCircuitBreakersV2(snapshot.CircuitBreakersV2)
+	im.importFaultMirrorRules(snapshot.FaultMirrorRules)
+	im.importConfigFileGroups(snapshot.ConfigFileGroups)
+	im.importConfigFiles(snapshot.ConfigFiles)
+	im.importConfigFileTags(snapshot.ConfigFileTags)
+	im.importConfigFileReleases(snapshot.ConfigFileReleases)
+	im.importConfigFileReleaseHistories(snapshot.ConfigFileReleaseHistories)
+	im.importConfigFileTemplates(snapshot.ConfigFileTemplates)
+	im.importUsers(snapshot.Users)
+	im.importUserGroups(snapshot.UserGroups)
+	im.importStrategies(snapshot.Strategies)
+
+	if !im.opts.DryRun && im.opts.RestoreMetas {
+		im.restoreMetas()
+	}
+	return im.report
+}
+
+// importRecord 导入单条记录，exist 判断目标存储中是否已经存在该记录，create 写入记录并返回需要还原的元信息
+// 记录已经存在时由 conflict 检查是否与源记录一致，并按照冲突策略决定是否覆盖，conflict 为 nil 时直接跳过
+func (im *importer) importRecord(kr *KindReport, kind store.RecordKind, key string,
+	exist func() (bool, error), create func() (*store.RecordMeta, error), conflict *conflictHandler) {
+	existed, err := exist()
+	if err != nil {
+		kr.Failed++
+		im.errorf("%s(%s) check exist: %s", kr.Kind, key, err.Error())
+		return
+	}
+	write, action := create, "create"
+	if existed {
+		if write = im.resolveConflict(kr, key, conflict); write == nil {
+			return
+		}
+		action = "overwrite"
+	}
+	if im.opts.DryRun {
+		im.written(kr, existed)
+		return
+	}
+
+	meta, err := write()
+	if err != nil {
+		kr.Failed++
+		im.errorf("%s(%s) %s: %s", kr.Kind, key, action, err.Error())
+		return
+	}
+	im.written(kr, existed)
+	if meta == nil || kind == "" {
+		return
+	}
+	if _, ok := im.metas[kind]; !ok {
+		im.kinds = append(im.kinds, kind)
+	}
+	im.metas[kind] = append(im.metas[kind], meta)
+}
+
+// restoreMetas 还原已写入记录原有的时间戳以及配置中心记录的自增ID
+func (im *importer) restoreMetas() {
+	if len(im.kinds) == 0 {
+		return
+	}
+	restorer, ok := im.dst.(store.RestoreStore)
+	if !ok {
+		im.report.Warnings = append(im.report.Warnings, fmt.Sprintf(
+			"store %s not support restoring record metas, create and modify time of imported records "+
+				"are the import time, and config center records get new ids", im.dst.Name()))
+		return
+	}
+	for _, kind := range im.kinds {
+		if err := restorer.RestoreRecordMetas(kind, im.metas[kind]); err != nil {
+			im.errorf("%s restore record metas: %s", kind, err.Error())
+		}
+	}
+}
+
+func (im *importer) written(kr *KindReport, overwritten bool) {
+	if overwritten {
+		kr.Overwritten++
+	} else {
+		kr.Created++
+	}
+}
+
+func (im *importer) errorf(format string, args ...interface{}) {
+	im.report.Errors = append(im.report.Errors, fmt.Sprintf(format, args...))
+}
+
+func (im *importer) warnf(format string, args ...interface{}) {
+	im.report.Warnings = append(im.report.Warnings, fmt.Sprintf(format, args...))
+}
+
+func newMeta(key string, ctime, mtime time.Time) *store.RecordMeta {
+	return &store.RecordMeta{Key: key, CreateTime: ctime, ModifyTime: mtime}
+}
+
+func (im *importer) importNamespaces(items []*model.Namespace) {
+	kr := im.report.newKind(string(store.RecordNamespace), len(items))
+	for _, item := range items {
+		item := item
+		im.importRecord(kr, store.RecordNamespace, item.Name, func() (bool, error) {
+			ret, err := im.dst.GetNamespace(item.Name)
+			return ret != nil, err
+		}, func() (*store.RecordMeta, error) {
+			record := *item
+			if err := im.dst.AddNamespace(&record); err != nil {
+				return nil, err
+			}
+			return newMeta(item.Name, item.CreateTime, item.ModifyTime), nil
+		}, im.namespaceConflict(item))
+	}
+}
+
+func (im *importer) importServices(items []*model.Service) {
+	kr := im.report.newKind(string(store.RecordService), len(items))
+	for _, item := range items {
+		item := item
+		im.importRecord(kr, store.RecordService, item.ID, func() (bool, error) {
+			ret, err := im.dst.GetService(item.Name, item.Namespace)
+			if err != nil || ret == nil {
+				return false, err
+			}
+			if ret.ID != item.ID {
+				im.warnf("%s(%s/%s) exists in target with different id %s, records bound to it are not imported",
+					kr.Kind, item.Namespace, item.Name, ret.ID)
+			}
+			return true, nil
+		}, func() (*store.RecordMeta, error) {
+			record := *item
+			if err := im.dst.AddService(&record); err != nil {
+				return nil, err
+			}
+			return newMeta(item.ID, item.CreateTime, item.ModifyTime), nil
+		}, im.serviceConflict(item))
+	}
+}
+
+func (im *importer) importInstances(items []*model.Instance) {
+	kr := im.report.newKind(string(store.RecordInstance), len(items))
+	for _, item := range items {
+		item := item
+		im.importRecord(kr, store.RecordInstance, item.ID(), func() (bool, error) {
+			ret, err := im.dst.GetInstance(item.ID())
+			return ret != nil, err
+		}, func() (*store.RecordMeta, error) {
+			record := *item
+			record.Proto = proto.Clone(item.Proto).(*api.Instance)
+			if err := im.dst.AddInstance(&record); err != nil {
+				return nil, err
+			}
+			return newMeta(item.ID(), parseProtoTime(item.Proto.GetCtime().GetValue()), item.ModifyTime), nil
+		}, im.instanceConflict(item))
+	}
+}
+
+// parseProtoTime 解析实例 proto 中的时间字符串，解析失败时返回零值
+func parseProtoTime(value string) time.Time {
+	t, err := time.ParseInLocation("2006-01-02 15:04:05", value, time.Local)
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
+
+func (im *importer) importRoutingConfigs(items []*model.RoutingConfig) {
+	kr := im.report.newKind(string(store.RecordRoutingConfig), len(items))
+	for _, item := range items {
+		item := item
+		im.importRecord(kr, store.RecordRoutingConfig, item.ID, func() (bool, error) {
+			ret, err := im.dst.GetRoutingConfigWithID(item.ID)
+			return ret != nil, err
+		}, func() (*store.RecordMeta, error) {
+			record := *item
+			if err := im.dst.CreateRoutingConfig(&record); err != nil {
+				return nil, err
+			}
+			return newMeta(item.ID, item.CreateTime, item.ModifyTime), nil
+		}, im.routingConfigConflict(item))
+	}
+}
+
+func (im *importer) importRoutingConfigsV2(items []*v2.RoutingConfig) {
+	kr := im.report.newKind(string(store.RecordRoutingConfigV2), len(items))
+	for _, item := range items {
+		item := item
+		im.importRecord(kr, store.RecordRoutingConfigV2, item.ID, func() (bool, error) {
+			ret, err := im.dst.GetRoutingConfigV2WithID(item.ID)
+			return ret != nil, err
+		}, func() (*store.RecordMeta, error) {
+			record := *item
+			if err := im.dst.CreateRoutingConfigV2(&record); err != nil {
+				return nil, err
+			}
+			return newMeta(item.ID, item.CreateTime, item.ModifyTime), nil
+		}, im.routingConfigV2Conflict(item))
+	}
+}
+
+func (im *importer) importRateLimits(items []*model.RateLimit) {
+	kr := im.report.newKind(string(store.RecordRateLimit), len(items))
+	for _, item := range items {
+		item := item
+		im.importRecord(kr, store.RecordRateLimit, item.ID, func() (bool, error) {
+			ret, err := im.dst.GetRateLimitWithID(item.ID)
+			return ret != nil, err
+		}, func() (*store.RecordMeta, error) {
+			record := *item
+			if err := im.dst.CreateRateLimit(&record); err != nil {
+				return nil, err
+			}
+			return newMeta(item.ID, item.CreateTime, item.ModifyTime), nil
+		}, im.rateLimitConflict(item))
+	}
+}
+
+func (im *importer) importCircuitBreakers(items []*model.CircuitBreaker) {
+	kr := im.report.newKind(string(store.RecordCircuitBreaker), len(items))
+	for _, item := range items {
+		item := item
+		key := item.ID + "/" + item.Version
+		im.importRecord(kr, store.RecordCircuitBreaker, key, func() (bool, error) {
+			ret, err := im.dst.GetCircuitBreaker(item.ID, item.Version)
+			return ret != nil, err
+		}, func() (*store.RecordMeta, error) {
+			record := *item
+			var err error
+			if item.Version == masterCircuitBreakerVersion {
+				err = im.dst.CreateCircuitBreaker(&record)
+			} else {
+				err = im.dst.TagCircuitBreaker(&record)
+			}
+			if err != nil {
+				return nil, err
+			}
+			meta := newMeta(item.ID, item.CreateTime, item.ModifyTime)
+			meta.Version = item.Version
+			return meta, nil
+		}, im.circuitBreakerConflict(item))
+	}
+}
+
+func (im *importer) importCircuitBreakerRelations(items []*model.CircuitBreakerRelation) {
+	kr := im.report.newKind(kindCircuitBreakerRelation, len(items))
+	for _, item := range items {
+		item := item
+		key := item.ServiceID + "/" + item.RuleID + "/" + item.RuleVersion
+		im.importRecord(kr, "", key, func() (bool, error) {
+			relations, err := im.dst.GetCircuitBreakerRelation(item.RuleID, item.RuleVersion)
+			if err != nil {
+				return false, err
+			}
+			for _, relation := range relations {
+				if relation.ServiceID == item.ServiceID {
+					return true, nil
+				}
+			}
+			return false, nil
+		}, func() (*store.RecordMeta, error) {
+			record := *item
+			return nil, im.dst.ReleaseCircuitBreaker(&record)
+		}, nil)
+	}
+}
+
+func (im *importer) importCircuitBreakersV2(items []*v2.CircuitBreakerRule) {
+	kr := im.report.newKind(string(store.RecordCircuitBreakerV2), len(items))
+	for _, item := range items {
+		item := item
+		im.importRecord(kr, store.RecordCircuitBreakerV2, item.ID, func() (bool, error) {
+			ret, err := im.dst.GetCircuitBreakerRuleV2WithID(item.ID)
+			return ret != nil, err
+		}, func() (*store.RecordMeta, error) {
+			record := *item
+			if err := im.dst.CreateCircuitBreakerRuleV2(&record); err != nil {
+				return nil, err
+			}
+			return newMeta(item.ID, item.CreateTime, item.ModifyTime), nil
+		}, im.circuitBreakerV2Conflict(item))
+	}
+}
+
+func (im *importer) importFaultMirrorRules(items []*v2.FaultMirrorRule) {
+	kr := im.report.newKind(string(store.RecordFaultMirrorRule), len(items))
+	for _, item := range items {
+		item := item
+		im.importRecord(kr, store.RecordFaultMirrorRule, item.ID, func() (bool, error) {
+			ret, err := im.dst.GetFaultMirrorRuleWithID(item.ID)
+			return ret != nil, err
+		}, func() (*store.RecordMeta, error) {
+			record := *item
+			if err := im.dst.CreateFaultMirrorRule(&record); err != nil {
+				return nil, err
+			}
+			return newMeta(item.ID, item.CreateTime, item.ModifyTime), nil
+		}, im.faultMirrorRuleConflict(item))
+	}
+}
+
+// newConfigMeta 配置中心的记录以目标存储生成的自增ID为主键，originID 不为 0 时还原为源存储中的自增ID
+func newConfigMeta(id, originID uint64, ctime, mtime time.Time) *store.RecordMeta {
+	meta := newMeta(strconv.FormatUint(id, 10), ctime, mtime)
+	meta.OriginID = originID
+	return meta
+}
+
+func (im *importer) importConfigFileGroups(items []*model.ConfigFileGroup) {
+	kr := im.report.newKind(string(store.RecordConfigFileGroup), len(items))
+	for _, item := range items {
+		item := item
+		key := item.Namespace + "/" + item.Name
+		im.importRecord(kr, store.RecordConfigFileGroup, key, func() (bool, error) {
+			ret, err := im.dst.GetConfigFileGroup(item.Namespace, item.Name)
+			return ret != nil, err
+		}, func() (*store.RecordMeta, error) {
+			record := *item
+			created, err := im.dst.CreateConfigFileGroup(&record)
+			if err != nil {
+				return nil, err
+			}
+			if item.Schema != "" {
+				if err := im.dst.UpdateConfigFileGroupSchema(item.Namespace, item.Name, item.Schema,
+					item.ModifyBy); err != nil {
+					return nil, err
+				}
+			}
+			return newConfigMeta(created.Id, item.Id, item.CreateTime, item.ModifyTime), nil
+		}, im.configFileGroupConflict(item))
+	}
+}
+
+func (im *importer) importConfigFiles(items []*model.ConfigFile) {
+	kr := im.report.newKind(string(store.RecordConfigFile), len(items))
+	for _, item := range items {
+		item := item
+		key := item.Namespace + "/" + item.Group + "/" + item.Name
+		im.importRecord(kr, store.RecordConfigFile, key, func() (bool, error) {
+			ret, err := im.dst.GetConfigFile(nil, item.Namespace, item.Group, item.Name)
+			return ret != nil, err
+		}, func() (*store.RecordMeta, error) {
+			record := *item
+			created, err := im.dst.CreateConfigFile(nil, &record)
+			if err != nil {
+				return nil, err
+			}
+			return newConfigMeta(created.Id, item.Id, item.CreateTime, item.ModifyTime), nil
+		}, im.configFileConflict(item))
+	}
+}
+
+func (im *importer) importConfigFileTags(items []*model.ConfigFileTag) {
+	kr := im.report.newKind(kindConfigFileTag, len(items))
+	for _, item := range items {
+		item := item
+		key := item.Namespace + "/" + item.Group + "/" + item.FileName + "/" + item.Key + "=" + item.Value
+		im.importRecord(kr, "", key, func() (bool, error) {
+			tags, err := im.dst.QueryTagByConfigFile(item.Namespace, item.Group, item.FileName)
+			if err != nil {
+				return false, err
+			}
+			for _, tag := range tags {
+				if tag.Key == item.Key && tag.Value == item.Value {
+					return true, nil
+				}
+			}
+			return false, nil
+		}, func() (*store.RecordMeta, error) {
+			record := *item
+			return nil, im.dst.CreateConfigFileTag(nil, &record)
+		}, nil)
+	}
+}
+
+func (im *importer) importConfigFileReleases(items []*model.ConfigFileRelease) {
+	kr := im.report.newKind(string(store.RecordConfigFileRelease), len(items))
+	for _, item := range items {
+		item := item
+		key := item.Namespace + "/" + item.Group + "/" + item.FileName
+		// 目标存储中已经删除的发布记录，需要更新而不是新建
+		var deleted bool
+		im.importRecord(kr, store.RecordConfigFileRelease, key, func() (bool, error) {
+			ret, err := im.dst.GetConfigFileReleaseWithAllFlag(nil, item.Namespace, item.Group, item.FileName)
+			if err != nil || ret == nil {
+				return false, err
+			}
+			deleted = ret.Flag != 0
+			return !deleted, nil
+		}, func() (*store.RecordMeta, error) {
+			record := *item
+			var (
+				created *model.ConfigFileRelease
+				err     error
+			)
+			if deleted {
+				created, err = im.dst.UpdateConfigFileRelease(nil, &record)
+			} else {
+				created, err = im.dst.CreateConfigFileRelease(nil, &record)
+			}
+			if err != nil {
+				return nil, err
+			}
+			if created == nil {
+				return nil, fmt.Errorf("release not found after written")
+			}
+			return newConfigMeta(created.Id, item.Id, item.CreateTime, item.ModifyTime), nil
+		}, im.configFileReleaseConflict(item))
+	}
+}
+
+func (im *importer) importConfigFileReleaseHistories(items []*model.ConfigFileReleaseHistory) {
+	kr := im.report.newKind(string(store.RecordConfigFileReleaseHistory), len(items))
+	// 目标存储中已经存在发布历史的配置文件，跳过该文件的全部发布历史，避免历史记录交错
+	skipFiles := make(map[string]bool)
+	for _, item := range items {
+		item := item
+		file := item.Namespace + "/" + item.Group + "/" + item.FileName
+		key := file + "/" + strconv.FormatUint(item.Id, 10)
+		im.importRecord(kr, store.RecordConfigFileReleaseHistory, key, func() (bool, error) {
+			if skip, ok := skipFiles[file]; ok {
+				return skip, nil
+			}
+			ret, err := im.dst.GetLatestConfigFileReleaseHistory(item.Namespace, item.Group, item.FileName)
+			if err != nil {
+				return false, err
+			}
+			skipFiles[file] = ret != nil
+			return ret != nil, nil
+		}, func() (*store.RecordMeta, error) {
+			record := *item
+			if err := im.dst.CreateConfigFileReleaseHistory(nil, &record); err != nil {
+				return nil, err
+			}
+			created, err := im.dst.GetLatestConfigFileReleaseHistory(item.Namespace, item.Group, item.FileName)
+			if err != nil {
+				return nil, err
+			}
+			if created == nil {
+				return nil, fmt.Errorf("release history not found after written")
+			}
+			return newConfigMeta(created.Id, item.Id, item.CreateTime, item.ModifyTime), nil
+		}, nil)
+	}
+}
+
+func (im *importer) importConfigFileTemplates(items []*model.ConfigFileTemplate) {
+	kr := im.report.newKind(string(store.RecordConfigFileTemplate), len(items))
+	for _, item := range items {
+		item := item
+		im.importRecord(kr, store.RecordConfigFileTemplate, item.Name, func() (bool, error) {
+			ret, err := im.dst.GetConfigFileTemplate(item.Name)
+			return ret != nil, err
+		}, func() (*store.RecordMeta, error) {
+			record := *item
+			created, err := im.dst.CreateConfigFileTemplate(&record)
+			if err != nil {
+				return nil, err
+			}
+			return newConfigMeta(created.Id, item.Id, item.CreateTime, item.ModifyTime), nil
+		}, im.configFileTemplateConflict(item))
+	}
+}
+
+func (im *importer) importUsers(items []*model.User) {
+	kr := im.report.newKind(string(store.RecordUser), len(items))
+	for _, item := range items {
+		item := item
+		im.importRecord(kr, store.RecordUser, item.ID, func() (bool, error) {
+			ret, err := im.dst.GetUser(item.ID)
+			return ret != nil && ret.Valid, err
+		}, func() (*store.RecordMeta, error) {
+			record := *item
+			if err := im.dst.AddUser(&record); err != nil {
+				return nil, err
+			}
+			return newMeta(item.ID, item.CreateTime, item.ModifyTime), nil
+		}, im.userConflict(item))
+	}
+}
+
+func (im *importer) importUserGroups(items []*model.UserGroupDetail) {
+	kr := im.report.newKind(string(store.RecordUserGroup), len(items))
+	for _, item := range items {
+		item := item
+		im.importRecord(kr, store.RecordUserGroup, item.ID, func() (bool, error) {
+			ret, err := im.dst.GetGroup(item.ID)
+			return ret != nil && ret.UserGroup != nil && ret.Valid, err
+		}, func() (*store.RecordMeta, error) {
+			group := *item.UserGroup
+			record := &model.UserGroupDetail{UserGroup: &group, UserIds: item.UserIds}
+			if err := im.dst.AddGroup(record); err != nil {
+				return nil, err
+			}
+			return newMeta(item.ID, item.CreateTime, item.ModifyTime), nil
+		}, im.userGroupConflict(item))
+	}
+}
+
+// importStrategies 导入鉴权策略
+// 写入用户和用户组时目标存储会为其生成新的默认策略，需要替换为源存储中的默认策略以保留策略ID
+func (im *importer) importStrategies(items []*model.StrategyDetail) {
+	kr := im.report.newKind(string(store.RecordStrategy), len(items))
+	for _, item := range items {
+		item := item
+		// replaceID 目标存储中需要被替换掉的默认策略
+		var replaceID string
+		im.importRecord(kr, store.RecordStrategy, item.ID, func() (bool, error) {
+			ret, err := im.dst.GetStrategyDetail(item.ID)
+			if err != nil || ret != nil || !item.Default || len(item.Principals) == 0 {
+				return ret != nil, err
+			}
+			principal := item.Principals[0]
+			ret, err = im.dst.GetDefaultStrategyDetailByPrincipal(principal.PrincipalID, principal.PrincipalRole)
+			if err != nil {
+				return false, err
+			}
+			if ret != nil {
+				replaceID = ret.ID
+			}
+			return false, nil
+		}, func() (*store.RecordMeta, error) {
+			if replaceID != "" {
+				if err := im.dst.DeleteStrategy(replaceID); err != nil {
+					return nil, err
+				}
+			}
+			record := *item
+			if err := im.dst.AddStrategy(&record); err != nil {
+				return nil, err
+			}
+			return newMeta(item.ID, item.CreateTime, item.ModifyTime), nil
+		}, im.strategyConflict(item))
+	}
+}
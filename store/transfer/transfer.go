@@ -0,0 +1,131 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+// Package transfer 在不同的存储插件之间迁移业务数据
+package transfer
+
+import (
+	"github.com/polarismesh/polaris/common/model"
+	v2 "github.com/polarismesh/polaris/common/model/v2"
+	"github.com/polarismesh/polaris/store"
+)
+
+const (
+	// kindCircuitBreakerRelation 熔断规则 v1 与服务的绑定关系
+	kindCircuitBreakerRelation = "circuitbreaker_relation"
+	// kindConfigFileTag 配置文件标签
+	kindConfigFileTag = "config_file_tag"
+
+	// masterCircuitBreakerVersion 熔断规则 v1 的 master 版本
+	masterCircuitBreakerVersion = "master"
+)
+
+// Snapshot 存储中全部有效的业务数据，各类数据按照写入的先后顺序排列
+type Snapshot struct {
+	Namespaces []*model.Namespace `json:"namespaces,omitempty"`
+	// Services 源服务在前，服务别名在后
+	Services         []*model.Service       `json:"services,omitempty"`
+	Instances        []*model.Instance      `json:"instances,omitempty"`
+	RoutingConfigs   []*model.RoutingConfig `json:"routing_configs,omitempty"`
+	RoutingConfigsV2 []*v2.RoutingConfig    `json:"routing_configs_v2,omitempty"`
+	RateLimits       []*model.RateLimit     `json:"rate_limits,omitempty"`
+	// CircuitBreakers master 规则在前，其余版本在后
+	CircuitBreakers         []*model.CircuitBreaker         `json:"circuit_breakers,omitempty"`
+	CircuitBreakerRelations []*model.CircuitBreakerRelation `json:"circuit_breaker_relations,omitempty"`
+	CircuitBreakersV2       []*v2.CircuitBreakerRule        `json:"circuit_breakers_v2,omitempty"`
+	FaultMirrorRules        []*v2.FaultMirrorRule           `json:"fault_mirror_rules,omitempty"`
+	ConfigFileGroups        []*model.ConfigFileGroup        `json:"config_file_groups,omitempty"`
+	ConfigFiles             []*model.ConfigFile             `json:"config_files,omitempty"`
+	ConfigFileTags          []*model.ConfigFileTag          `json:"config_file_tags,omitempty"`
+	ConfigFileReleases      []*model.ConfigFileRelease      `json:"config_file_releases,omitempty"`
+	// ConfigFileReleaseHistories 按照ID升序排列
+	ConfigFileReleaseHistories []*model.ConfigFileReleaseHistory `json:"config_file_release_histories,omitempty"`
+	ConfigFileTemplates        []*model.ConfigFileTemplate       `json:"config_file_templates,omitempty"`
+	// Users 主账号在前，子账号在后
+	Users      []*model.User            `json:"users,omitempty"`
+	UserGroups []*model.UserGroupDetail `json:"user_groups,omitempty"`
+	Strategies []*model.StrategyDetail  `json:"strategies,omitempty"`
+}
+
+// KindReport 单个记录类型的迁移或者校验结果
+type KindReport struct {
+	Kind string `json:"kind"`
+	// Total 源数据中的记录数
+	Total int `json:"total"`
+	// Created 写入目标存储的记录数，dryRun 时为将要写入的记录数
+	Created int `json:"created"`
+	// Skipped 目标存储中已经存在而跳过的记录数
+	Skipped int `json:"skipped"`
+	// Conflicted 目标存储中已经存在且与源数据不一致的记录数
+	Conflicted int `json:"conflicted"`
+	// Overwritten 覆盖目标存储中冲突记录的记录数，dryRun 时为将要覆盖的记录数
+	Overwritten int `json:"overwritten"`
+	// Failed 写入或者校验失败的记录数
+	Failed int `json:"failed"`
+	// Missing 校验时目标存储中不存在的记录数
+	Missing int `json:"missing"`
+	// Mismatched 校验时与源数据不一致的记录数
+	Mismatched int `json:"mismatched"`
+}
+
+// Report 迁移或者校验的结果
+type Report struct {
+	DryRun   bool          `json:"dry_run"`
+	Kinds    []*KindReport `json:"kinds"`
+	Errors   []string      `json:"errors,omitempty"`
+	Warnings []string      `json:"warnings,omitempty"`
+}
+
+// HasError 是否存在失败、缺失或者不一致的记录
+func (r *Report) HasError() bool {
+	for _, kr := range r.Kinds {
+		if kr.Failed > 0 || kr.Missing > 0 || kr.Mismatched > 0 {
+			return true
+		}
+	}
+	return len(r.Errors) > 0
+}
+
+// Conflicts 目标存储中与源数据不一致的记录总数
+func (r *Report) Conflicts() int {
+	total := 0
+	for _, kr := range r.Kinds {
+		total += kr.Conflicted
+	}
+	return total
+}
+
+func (r *Report) newKind(kind string, total int) *KindReport {
+	kr := &KindReport{Kind: kind, Total: total}
+	r.Kinds = append(r.Kinds, kr)
+	return kr
+}
+
+// Copy 将源存储的数据迁移到目标存储并还原记录原有的时间戳，目标存储中已经存在的记录会被跳过
+// verify 为 true 时在迁移完成后校验目标存储的数据
+func Copy(src, dst store.Store, dryRun, verify bool) (*Report, *Report, error) {
+	snapshot, err := Export(src)
+	if err != nil {
+		return nil, nil, err
+	}
+	report, err := Import(dst, snapshot, &ImportOptions{DryRun: dryRun, RestoreMetas: true})
+	if err != nil || dryRun || !verify {
+		return report, nil, err
+	}
+	verifyReport, err := Verify(dst, snapshot)
+	return report, verifyReport, err
+}
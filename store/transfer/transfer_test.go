@@ -0,0 +1,281 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package transfer
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/ptypes/wrappers"
+	"github.com/stretchr/testify/assert"
+
+	api "github.com/polarismesh/polaris/common/api/v1"
+	"github.com/polarismesh/polaris/common/model"
+	"github.com/polarismesh/polaris/store"
+	"github.com/polarismesh/polaris/store/boltdb"
+)
+
+const testOwner = "65e4789a6d5b49669adf1e9e8387549c"
+
+func newTestStore(t *testing.T, name string) store.Store {
+	s := boltdb.NewStore()
+	err := s.Initialize(&store.Config{
+		Name: boltdb.STORENAME,
+		// 初始化数据使用固定的 token，源存储与目标存储的初始化数据保持一致
+		Option: map[string]interface{}{"path": filepath.Join(t.TempDir(), name), "fixedInitToken": true},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		_ = s.Destroy()
+	})
+	return s
+}
+
+func mockSourceData(t *testing.T, s store.Store) {
+	now := time.Now()
+	must := func(err error) {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	must(s.AddNamespace(&model.Namespace{Name: "transfer", Token: "ns-token", Owner: testOwner}))
+	must(s.AddService(&model.Service{ID: "svc-1", Name: "svc", Namespace: "transfer", Token: "svc-token",
+		Revision: "svc-revision", Owner: testOwner}))
+	must(s.AddService(&model.Service{ID: "svc-alias", Name: "svc-alias", Namespace: "transfer",
+		Reference: "svc-1", Token: "svc-token", Revision: "alias-revision", Owner: testOwner}))
+	must(s.AddInstance(&model.Instance{
+		ServiceID: "svc-1",
+		Proto: &api.Instance{
+			Id:        &wrappers.StringValue{Value: "ins-1"},
+			Service:   &wrappers.StringValue{Value: "svc"},
+			Namespace: &wrappers.StringValue{Value: "transfer"},
+			Host:      &wrappers.StringValue{Value: "127.0.0.1"},
+			Port:      &wrappers.UInt32Value{Value: 8080},
+			Revision:  &wrappers.StringValue{Value: "ins-revision"},
+		},
+	}))
+
+	_, err := s.CreateConfigFileGroup(&model.ConfigFileGroup{Name: "group", Namespace: "transfer",
+		Schema: `{"type":"object"}`, CreateBy: "polaris"})
+	must(err)
+	must(s.UpdateConfigFileGroupSchema("transfer", "group", `{"type":"object"}`, "polaris"))
+	_, err = s.CreateConfigFile(nil, &model.ConfigFile{Name: "app.json", Namespace: "transfer", Group: "group",
+		Content: `{"a":1}`, Format: "json", CreateBy: "polaris"})
+	must(err)
+	must(s.CreateConfigFileTag(nil, &model.ConfigFileTag{Key: "env", Value: "prod", Namespace: "transfer",
+		Group: "group", FileName: "app.json"}))
+	_, err = s.CreateConfigFileRelease(nil, &model.ConfigFileRelease{Name: "release", Namespace: "transfer",
+		Group: "group", FileName: "app.json", Content: `{"a":1}`, Md5: "md5-1", Version: 3})
+	must(err)
+	for _, md5 := range []string{"md5-0", "md5-1"} {
+		must(s.CreateConfigFileReleaseHistory(nil, &model.ConfigFileReleaseHistory{Name: "release",
+			Namespace: "transfer", Group: "group", FileName: "app.json", Content: `{"a":1}`, Md5: md5,
+			Type: "normal", Status: "success", Valid: true}))
+	}
+	_, err = s.CreateConfigFileTemplate(&model.ConfigFileTemplate{Name: "tpl", Content: "tpl-content",
+		Format: "text", CreateTime: now, ModifyTime: now})
+	must(err)
+
+	must(s.AddUser(&model.User{ID: "user-1", Name: "user-1", Password: "pwd", Owner: testOwner,
+		Source: "Polaris", Type: model.SubAccountUserRole, Token: "user-token", TokenEnable: true, Valid: true}))
+	must(s.AddGroup(&model.UserGroupDetail{
+		UserGroup: &model.UserGroup{ID: "group-1", Name: "group-1", Owner: testOwner, Token: "group-token",
+			TokenEnable: true, Valid: true},
+		UserIds: map[string]struct{}{"user-1": {}},
+	}))
+	must(s.AddStrategy(&model.StrategyDetail{ID: "strategy-1", Name: "strategy-1", Action: "READ_WRITE",
+		Owner: testOwner, Valid: true,
+		Principals: []model.Principal{{StrategyID: "strategy-1", PrincipalID: "user-1",
+			PrincipalRole: model.PrincipalUser}},
+		Resources: []model.StrategyResource{{StrategyID: "strategy-1", ResType: 0, ResID: "transfer"}},
+	}))
+}
+
+func findKind(report *Report, kind string) *KindReport {
+	for _, kr := range report.Kinds {
+		if kr.Kind == kind {
+			return kr
+		}
+	}
+	return &KindReport{Kind: kind}
+}
+
+func Test_Copy(t *testing.T) {
+	src := newTestStore(t, "src.bolt")
+	mockSourceData(t, src)
+
+	t.Run("dry run", func(t *testing.T) {
+		dst := newTestStore(t, "dry.bolt")
+		report, verifyReport, err := Copy(src, dst, true, true)
+		assert.NoError(t, err)
+		assert.Nil(t, verifyReport)
+		assert.True(t, report.DryRun)
+		assert.Equal(t, 2, findKind(report, string(store.RecordService)).Created)
+
+		svc, err := dst.GetService("svc", "transfer")
+		assert.NoError(t, err)
+		assert.Nil(t, svc)
+	})
+
+	t.Run("copy and verify", func(t *testing.T) {
+		dst := newTestStore(t, "dst.bolt")
+		report, verifyReport, err := Copy(src, dst, false, true)
+		assert.NoError(t, err)
+		assert.False(t, report.HasError(), report.Errors)
+		// 校验时同时比较创建时间以及配置中心记录的自增ID
+		assert.False(t, verifyReport.HasError(), verifyReport.Errors)
+
+		for _, kind := range []store.RecordKind{store.RecordService, store.RecordInstance,
+			store.RecordConfigFileGroup, store.RecordConfigFile, store.RecordConfigFileRelease,
+			store.RecordConfigFileTemplate, store.RecordUserGroup} {
+			assert.NotZero(t, findKind(report, string(kind)).Created, kind)
+		}
+		assert.Equal(t, 1, findKind(report, kindConfigFileTag).Created)
+		assert.Equal(t, 2, findKind(report, string(store.RecordConfigFileReleaseHistory)).Created)
+		// 默认命名空间、管理员账号等初始化数据在目标存储中已经存在
+		assert.NotZero(t, findKind(report, string(store.RecordNamespace)).Skipped)
+		assert.NotZero(t, findKind(report, string(store.RecordUser)).Skipped)
+
+		group, err := dst.GetConfigFileGroup("transfer", "group")
+		assert.NoError(t, err)
+		assert.Equal(t, `{"type":"object"}`, group.Schema)
+
+		// 用户的默认策略保留源存储中的策略ID
+		srcStrategy, err := src.GetDefaultStrategyDetailByPrincipal("user-1", model.PrincipalUser)
+		assert.NoError(t, err)
+		dstStrategy, err := dst.GetDefaultStrategyDetailByPrincipal("user-1", model.PrincipalUser)
+		assert.NoError(t, err)
+		assert.Equal(t, srcStrategy.ID, dstStrategy.ID)
+
+		// 再次导入时全部跳过
+		report, err = Import(dst, mustExport(t, src), nil)
+		assert.NoError(t, err)
+		for _, kr := range report.Kinds {
+			assert.Zero(t, kr.Created, kr.Kind)
+			assert.Zero(t, kr.Failed, kr.Kind)
+		}
+	})
+}
+
+func Test_CopyNotEmptyTarget(t *testing.T) {
+	src := newTestStore(t, "src.bolt")
+	mockSourceData(t, src)
+	dst := newTestStore(t, "dst.bolt")
+	_, err := dst.CreateConfigFileGroup(&model.ConfigFileGroup{Name: "exist", Namespace: "default",
+		CreateBy: "polaris"})
+	assert.NoError(t, err)
+
+	// 目标存储中已有配置分组时，源存储的自增ID可能与其冲突，不还原配置分组的ID
+	report, _, err := Copy(src, dst, false, false)
+	assert.NoError(t, err)
+	assert.True(t, report.HasError())
+	assert.Contains(t, strings.Join(report.Errors, "\n"), "ids of config_file_group are not restored")
+
+	srcGroup, err := src.GetConfigFileGroup("transfer", "group")
+	assert.NoError(t, err)
+	dstGroup, err := dst.GetConfigFileGroup("transfer", "group")
+	assert.NoError(t, err)
+	assert.NotEqual(t, srcGroup.Id, dstGroup.Id)
+	assert.Equal(t, srcGroup.CreateTime.Unix(), dstGroup.CreateTime.Unix())
+	exist, err := dst.GetConfigFileGroup("default", "exist")
+	assert.NoError(t, err)
+	assert.NotEqual(t, exist.Id, dstGroup.Id)
+}
+
+func mustExport(t *testing.T, s store.Store) *Snapshot {
+	snapshot, err := Export(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return snapshot
+}
+
+func Test_ImportConflict(t *testing.T) {
+	src := newTestStore(t, "src.bolt")
+	mockSourceData(t, src)
+	dst := newTestStore(t, "dst.bolt")
+	_, _, err := Copy(src, dst, false, false)
+	assert.NoError(t, err)
+
+	// 修改源存储中的数据，使其与目标存储冲突
+	svc, err := src.GetService("svc", "transfer")
+	assert.NoError(t, err)
+	svc.Revision = "svc-revision-2"
+	assert.NoError(t, src.UpdateService(svc, false))
+	file, err := src.GetConfigFile(nil, "transfer", "group", "app.json")
+	assert.NoError(t, err)
+	file.Content = `{"a":2}`
+	_, err = src.UpdateConfigFile(nil, file)
+	assert.NoError(t, err)
+	snapshot := mustExport(t, src)
+
+	assertDst := func(revision, content string) {
+		svc, err := dst.GetService("svc", "transfer")
+		assert.NoError(t, err)
+		assert.Equal(t, revision, svc.Revision)
+		file, err := dst.GetConfigFile(nil, "transfer", "group", "app.json")
+		assert.NoError(t, err)
+		assert.Equal(t, content, file.Content)
+	}
+
+	t.Run("invalid policy", func(t *testing.T) {
+		_, err := Import(dst, snapshot, &ImportOptions{Conflict: "unknown"})
+		assert.Error(t, err)
+	})
+
+	t.Run("fail", func(t *testing.T) {
+		report, err := Import(dst, snapshot, &ImportOptions{Conflict: ConflictFail})
+		assert.ErrorIs(t, err, ErrConflict)
+		assert.Equal(t, 1, findKind(report, string(store.RecordService)).Conflicted)
+		assert.Equal(t, 1, findKind(report, string(store.RecordConfigFile)).Conflicted)
+		assertDst("svc-revision", `{"a":1}`)
+	})
+
+	t.Run("skip", func(t *testing.T) {
+		report, err := Import(dst, snapshot, &ImportOptions{Conflict: ConflictSkip})
+		assert.NoError(t, err)
+		assert.Equal(t, 2, report.Conflicts())
+		assert.NotEmpty(t, report.Warnings)
+		assertDst("svc-revision", `{"a":1}`)
+	})
+
+	t.Run("overwrite dry run", func(t *testing.T) {
+		report, err := Import(dst, snapshot, &ImportOptions{Conflict: ConflictOverwrite, DryRun: true})
+		assert.NoError(t, err)
+		assert.Equal(t, 1, findKind(report, string(store.RecordService)).Overwritten)
+		assertDst("svc-revision", `{"a":1}`)
+	})
+
+	t.Run("overwrite", func(t *testing.T) {
+		report, err := Import(dst, snapshot, &ImportOptions{Conflict: ConflictOverwrite})
+		assert.NoError(t, err)
+		assert.False(t, report.HasError(), report.Errors)
+		assert.Equal(t, 1, findKind(report, string(store.RecordService)).Overwritten)
+		assert.Equal(t, 1, findKind(report, string(store.RecordConfigFile)).Overwritten)
+		assertDst("svc-revision-2", `{"a":2}`)
+
+		verifyReport, err := Verify(dst, snapshot)
+		assert.NoError(t, err)
+		assert.False(t, verifyReport.HasError(), verifyReport.Errors)
+	})
+}
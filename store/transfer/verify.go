@@ -0,0 +1,350 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package transfer
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/polarismesh/polaris/common/model"
+	"github.com/polarismesh/polaris/store"
+)
+
+// verifier 逐条校验快照中的记录在目标存储中是否存在并且一致
+type verifier struct {
+	dst    store.Store
+	report *Report
+	// restored 目标存储支持还原记录元信息，需要同时校验创建时间以及配置中心记录的自增ID
+	restored bool
+}
+
+// Verify 校验快照中的记录是否已经完整的写入目标存储
+func Verify(dst store.Store, snapshot *Snapshot) (*Report, error) {
+	_, restored := dst.(store.RestoreStore)
+	v := &verifier{dst: dst, report: &Report{}, restored: restored}
+
+	v.verifyNaming(snapshot)
+	v.verifyRules(snapshot)
+	v.verifyConfigFiles(snapshot)
+	v.verifyConfigFileReleases(snapshot)
+	v.verifyAuth(snapshot)
+	return v.report, nil
+}
+
+// check 校验单条记录，fetch 返回目标存储中的记录是否存在以及不一致的字段说明
+func (v *verifier) check(kr *KindReport, key string, fetch func() (bool, string, error)) {
+	found, diff, err := fetch()
+	switch {
+	case err != nil:
+		kr.Failed++
+		v.report.Errors = append(v.report.Errors, fmt.Sprintf("%s(%s) verify: %s", kr.Kind, key, err.Error()))
+	case !found:
+		kr.Missing++
+		v.report.Errors = append(v.report.Errors, fmt.Sprintf("%s(%s) missing in target", kr.Kind, key))
+	case diff != "":
+		kr.Mismatched++
+		v.report.Errors = append(v.report.Errors, fmt.Sprintf("%s(%s) mismatched: %s", kr.Kind, key, diff))
+	}
+}
+
+// diffCreateTime 目标存储支持还原元信息时，比较记录的创建时间
+func (v *verifier) diffCreateTime(src, dst time.Time) string {
+	if !v.restored || src.IsZero() || src.Unix() == dst.Unix() {
+		return ""
+	}
+	return fmt.Sprintf("create time expect %s but %s", src.Format(time.RFC3339), dst.Format(time.RFC3339))
+}
+
+// diffID 目标存储支持还原元信息时，比较配置中心记录的自增ID
+func (v *verifier) diffID(src, dst uint64) string {
+	if !v.restored {
+		return ""
+	}
+	return diffFields("id", src, dst)
+}
+
+func (v *verifier) verifyNaming(snapshot *Snapshot) {
+	kr := v.report.newKind(string(store.RecordNamespace), len(snapshot.Namespaces))
+	for _, item := range snapshot.Namespaces {
+		v.check(kr, item.Name, func() (bool, string, error) {
+			ret, err := v.dst.GetNamespace(item.Name)
+			if err != nil || ret == nil {
+				return false, "", err
+			}
+			return true, joinDiff(diffNamespace(item, ret), v.diffCreateTime(item.CreateTime, ret.CreateTime)), nil
+		})
+	}
+
+	kr = v.report.newKind(string(store.RecordService), len(snapshot.Services))
+	for _, item := range snapshot.Services {
+		v.check(kr, item.ID, func() (bool, string, error) {
+			ret, err := v.dst.GetService(item.Name, item.Namespace)
+			if err != nil || ret == nil {
+				return false, "", err
+			}
+			return true, joinDiff(diffService(item, ret), v.diffCreateTime(item.CreateTime, ret.CreateTime)), nil
+		})
+	}
+
+	kr = v.report.newKind(string(store.RecordInstance), len(snapshot.Instances))
+	for _, item := range snapshot.Instances {
+		v.check(kr, item.ID(), func() (bool, string, error) {
+			ret, err := v.dst.GetInstance(item.ID())
+			if err != nil || ret == nil {
+				return false, "", err
+			}
+			return true, diffInstance(item, ret), nil
+		})
+	}
+}
+
+func (v *verifier) verifyRules(snapshot *Snapshot) {
+	kr := v.report.newKind(string(store.RecordRoutingConfig), len(snapshot.RoutingConfigs))
+	for _, item := range snapshot.RoutingConfigs {
+		v.check(kr, item.ID, func() (bool, string, error) {
+			ret, err := v.dst.GetRoutingConfigWithID(item.ID)
+			if err != nil || ret == nil {
+				return false, "", err
+			}
+			return true, joinDiff(diffRoutingConfig(item, ret),
+				v.diffCreateTime(item.CreateTime, ret.CreateTime)), nil
+		})
+	}
+
+	kr = v.report.newKind(string(store.RecordRoutingConfigV2), len(snapshot.RoutingConfigsV2))
+	for _, item := range snapshot.RoutingConfigsV2 {
+		v.check(kr, item.ID, func() (bool, string, error) {
+			ret, err := v.dst.GetRoutingConfigV2WithID(item.ID)
+			if err != nil || ret == nil {
+				return false, "", err
+			}
+			return true, joinDiff(diffRoutingConfigV2(item, ret),
+				v.diffCreateTime(item.CreateTime, ret.CreateTime)), nil
+		})
+	}
+
+	kr = v.report.newKind(string(store.RecordRateLimit), len(snapshot.RateLimits))
+	for _, item := range snapshot.RateLimits {
+		v.check(kr, item.ID, func() (bool, string, error) {
+			ret, err := v.dst.GetRateLimitWithID(item.ID)
+			if err != nil || ret == nil {
+				return false, "", err
+			}
+			return true, joinDiff(diffRateLimit(item, ret), v.diffCreateTime(item.CreateTime, ret.CreateTime)), nil
+		})
+	}
+
+	kr = v.report.newKind(string(store.RecordCircuitBreaker), len(snapshot.CircuitBreakers))
+	for _, item := range snapshot.CircuitBreakers {
+		v.check(kr, item.ID+"/"+item.Version, func() (bool, string, error) {
+			ret, err := v.dst.GetCircuitBreaker(item.ID, item.Version)
+			if err != nil || ret == nil {
+				return false, "", err
+			}
+			return true, diffCircuitBreaker(item, ret), nil
+		})
+	}
+
+	kr = v.report.newKind(kindCircuitBreakerRelation, len(snapshot.CircuitBreakerRelations))
+	for _, item := range snapshot.CircuitBreakerRelations {
+		v.check(kr, item.ServiceID+"/"+item.RuleID+"/"+item.RuleVersion, func() (bool, string, error) {
+			found, err := hasCircuitBreakerRelation(v.dst, item)
+			return found, "", err
+		})
+	}
+
+	kr = v.report.newKind(string(store.RecordCircuitBreakerV2), len(snapshot.CircuitBreakersV2))
+	for _, item := range snapshot.CircuitBreakersV2 {
+		v.check(kr, item.ID, func() (bool, string, error) {
+			ret, err := v.dst.GetCircuitBreakerRuleV2WithID(item.ID)
+			if err != nil || ret == nil {
+				return false, "", err
+			}
+			return true, joinDiff(diffCircuitBreakerV2(item, ret),
+				v.diffCreateTime(item.CreateTime, ret.CreateTime)), nil
+		})
+	}
+
+	kr = v.report.newKind(string(store.RecordFaultMirrorRule), len(snapshot.FaultMirrorRules))
+	for _, item := range snapshot.FaultMirrorRules {
+		v.check(kr, item.ID, func() (bool, string, error) {
+			ret, err := v.dst.GetFaultMirrorRuleWithID(item.ID)
+			if err != nil || ret == nil {
+				return false, "", err
+			}
+			return true, joinDiff(diffFaultMirrorRule(item, ret),
+				v.diffCreateTime(item.CreateTime, ret.CreateTime)), nil
+		})
+	}
+}
+
+func (v *verifier) verifyConfigFiles(snapshot *Snapshot) {
+	kr := v.report.newKind(string(store.RecordConfigFileGroup), len(snapshot.ConfigFileGroups))
+	for _, item := range snapshot.ConfigFileGroups {
+		v.check(kr, item.Namespace+"/"+item.Name, func() (bool, string, error) {
+			ret, err := v.dst.GetConfigFileGroup(item.Namespace, item.Name)
+			if err != nil || ret == nil {
+				return false, "", err
+			}
+			return true, joinDiff(diffConfigFileGroup(item, ret), v.diffID(item.Id, ret.Id),
+				v.diffCreateTime(item.CreateTime, ret.CreateTime)), nil
+		})
+	}
+
+	kr = v.report.newKind(string(store.RecordConfigFile), len(snapshot.ConfigFiles))
+	for _, item := range snapshot.ConfigFiles {
+		v.check(kr, item.Namespace+"/"+item.Group+"/"+item.Name, func() (bool, string, error) {
+			ret, err := v.dst.GetConfigFile(nil, item.Namespace, item.Group, item.Name)
+			if err != nil || ret == nil {
+				return false, "", err
+			}
+			return true, joinDiff(diffConfigFile(item, ret), v.diffID(item.Id, ret.Id),
+				v.diffCreateTime(item.CreateTime, ret.CreateTime)), nil
+		})
+	}
+
+	kr = v.report.newKind(kindConfigFileTag, len(snapshot.ConfigFileTags))
+	for _, item := range snapshot.ConfigFileTags {
+		v.check(kr, configFileTagKey(item), func() (bool, string, error) {
+			found, err := hasConfigFileTag(v.dst, item)
+			return found, "", err
+		})
+	}
+}
+
+func (v *verifier) verifyConfigFileReleases(snapshot *Snapshot) {
+	kr := v.report.newKind(string(store.RecordConfigFileRelease), len(snapshot.ConfigFileReleases))
+	for _, item := range snapshot.ConfigFileReleases {
+		v.check(kr, item.Namespace+"/"+item.Group+"/"+item.FileName, func() (bool, string, error) {
+			ret, err := v.dst.GetConfigFileRelease(nil, item.Namespace, item.Group, item.FileName)
+			if err != nil || ret == nil {
+				return false, "", err
+			}
+			return true, joinDiff(diffConfigFileRelease(item, ret), v.diffID(item.Id, ret.Id)), nil
+		})
+	}
+
+	// 目标存储不支持还原自增ID时，按照内容的md5匹配同一配置文件下的发布历史
+	histories := make(map[string][]*model.ConfigFileReleaseHistory)
+	kr = v.report.newKind(string(store.RecordConfigFileReleaseHistory), len(snapshot.ConfigFileReleaseHistories))
+	for _, item := range snapshot.ConfigFileReleaseHistories {
+		file := item.Namespace + "/" + item.Group + "/" + item.FileName
+		v.check(kr, fmt.Sprintf("%s/%d", file, item.Id), func() (bool, string, error) {
+			if v.restored {
+				ret, err := v.dst.GetConfigFileReleaseHistory(item.Id)
+				if err != nil || ret == nil {
+					return false, "", err
+				}
+				return true, diffFields("file", file, ret.Namespace+"/"+ret.Group+"/"+ret.FileName,
+					"md5", item.Md5, ret.Md5), nil
+			}
+			if _, ok := histories[file]; !ok {
+				_, rets, err := v.dst.QueryConfigFileReleaseHistories(item.Namespace, item.Group, item.FileName,
+					0, uint32(len(snapshot.ConfigFileReleaseHistories)), 0)
+				if err != nil {
+					return false, "", err
+				}
+				histories[file] = rets
+			}
+			for _, ret := range histories[file] {
+				if ret.Md5 == item.Md5 && ret.Type == item.Type {
+					return true, "", nil
+				}
+			}
+			return false, "", nil
+		})
+	}
+
+	kr = v.report.newKind(string(store.RecordConfigFileTemplate), len(snapshot.ConfigFileTemplates))
+	for _, item := range snapshot.ConfigFileTemplates {
+		v.check(kr, item.Name, func() (bool, string, error) {
+			ret, err := v.dst.GetConfigFileTemplate(item.Name)
+			if err != nil || ret == nil {
+				return false, "", err
+			}
+			return true, joinDiff(diffConfigFileTemplate(item, ret), v.diffID(item.Id, ret.Id)), nil
+		})
+	}
+}
+
+func (v *verifier) verifyAuth(snapshot *Snapshot) {
+	kr := v.report.newKind(string(store.RecordUser), len(snapshot.Users))
+	for _, item := range snapshot.Users {
+		v.check(kr, item.ID, func() (bool, string, error) {
+			ret, err := v.dst.GetUser(item.ID)
+			if err != nil || ret == nil || !ret.Valid {
+				return false, "", err
+			}
+			return true, joinDiff(diffUser(item, ret), v.diffCreateTime(item.CreateTime, ret.CreateTime)), nil
+		})
+	}
+
+	kr = v.report.newKind(string(store.RecordUserGroup), len(snapshot.UserGroups))
+	for _, item := range snapshot.UserGroups {
+		v.check(kr, item.ID, func() (bool, string, error) {
+			ret, err := v.dst.GetGroup(item.ID)
+			if err != nil || ret == nil || ret.UserGroup == nil || !ret.Valid {
+				return false, "", err
+			}
+			return true, joinDiff(diffUserGroup(item, ret), v.diffCreateTime(item.CreateTime, ret.CreateTime)), nil
+		})
+	}
+
+	kr = v.report.newKind(string(store.RecordStrategy), len(snapshot.Strategies))
+	for _, item := range snapshot.Strategies {
+		v.check(kr, item.ID, func() (bool, string, error) {
+			ret, err := v.dst.GetStrategyDetail(item.ID)
+			if err != nil || ret == nil || !ret.Valid {
+				return false, "", err
+			}
+			return true, joinDiff(diffStrategy(item, ret), v.diffCreateTime(item.CreateTime, ret.CreateTime)), nil
+		})
+	}
+}
+
+// hasCircuitBreakerRelation 目标存储中是否存在熔断规则 v1 与服务的绑定关系
+func hasCircuitBreakerRelation(s store.Store, item *model.CircuitBreakerRelation) (bool, error) {
+	relations, err := s.GetCircuitBreakerRelation(item.RuleID, item.RuleVersion)
+	if err != nil {
+		return false, err
+	}
+	for _, relation := range relations {
+		if relation.ServiceID == item.ServiceID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func configFileTagKey(item *model.ConfigFileTag) string {
+	return item.Namespace + "/" + item.Group + "/" + item.FileName + "/" + item.Key + "=" + item.Value
+}
+
+// hasConfigFileTag 目标存储中的配置文件是否存在该标签
+func hasConfigFileTag(s store.Store, item *model.ConfigFileTag) (bool, error) {
+	tags, err := s.QueryTagByConfigFile(item.Namespace, item.Group, item.FileName)
+	if err != nil {
+		return false, err
+	}
+	for _, tag := range tags {
+		if tag.Key == item.Key && tag.Value == item.Value {
+			return true, nil
+		}
+	}
+	return false, nil
+}